@@ -0,0 +1,253 @@
+package mageutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	gitutils "github.com/l50/goutils/v2/git"
+	"github.com/l50/goutils/v2/sys"
+)
+
+// WorkspaceRepo is a repository that BootstrapWorkspace clones next to
+// the current one.
+//
+// **Attributes:**
+//
+// URL: The URL to clone the repository from.
+// Path: Where to clone the repository. Relative paths are resolved
+// against the workspace root. If empty, the last element of the URL
+// without its ".git" suffix is used.
+type WorkspaceRepo struct {
+	URL  string
+	Path string
+}
+
+// WorkspaceConfig describes the workspace that BootstrapWorkspace sets
+// up.
+//
+// **Attributes:**
+//
+// Root: The directory sibling repositories are cloned into. If empty,
+// the parent directory of the current git repository is used.
+// Repos: The repositories to clone. Repositories that are already
+// cloned are left untouched.
+// Tools: The Go tools to install with `go install`, each pinned to a
+// version (e.g., "golang.org/x/tools/cmd/goimports@v0.22.0").
+// InstallHooks: Whether to install the pre-commit and commit-msg hooks
+// of the current repository's .pre-commit-config.yaml.
+// RequiredEnv: The environment variables that must be set.
+// Auth: The authentication method used to clone the repositories. If
+// nil, no authentication is used.
+// Output: Where the readiness report is printed. If nil, it's printed to
+// stdout.
+type WorkspaceConfig struct {
+	Root         string
+	Repos        []WorkspaceRepo
+	Tools        []string
+	InstallHooks bool
+	RequiredEnv  []string
+	Auth         transport.AuthMethod
+	Output       io.Writer
+}
+
+// WorkspaceCheck is the outcome of one step of BootstrapWorkspace.
+//
+// **Attributes:**
+//
+// Kind: The kind of step: "repo", "tool", "hooks", or "env".
+// Name: What the step set up or checked, such as a repository path or an
+// environment variable.
+// Err: Why the step failed, or nil if it succeeded.
+type WorkspaceCheck struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+// WorkspaceReport describes the outcome of BootstrapWorkspace.
+//
+// **Attributes:**
+//
+// Checks: The outcome of each step, in the order they ran.
+type WorkspaceReport struct {
+	Checks []WorkspaceCheck
+}
+
+// Ready reports whether every step of BootstrapWorkspace succeeded.
+//
+// **Returns:**
+//
+// bool: True if the workspace is ready to use.
+func (r *WorkspaceReport) Ready() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the checks of the steps that failed.
+//
+// **Returns:**
+//
+// []WorkspaceCheck: The failed checks, in the order they ran.
+func (r *WorkspaceReport) Failed() []WorkspaceCheck {
+	var failed []WorkspaceCheck
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			failed = append(failed, check)
+		}
+	}
+
+	return failed
+}
+
+// BootstrapWorkspace sets up a workspace for a new contributor: it clones
+// the sibling repositories, installs the pinned Go tools, installs the
+// pre-commit hooks, and checks that the required environment variables
+// are set. Every step runs even if an earlier one fails, so that the
+// readiness report printed at the end lists everything that needs
+// attention.
+//
+// **Parameters:**
+//
+// cfg: WorkspaceConfig describing the workspace.
+//
+// **Returns:**
+//
+// *WorkspaceReport: The outcome of each step.
+// error: An error if any step fails.
+func BootstrapWorkspace(cfg WorkspaceConfig) (*WorkspaceReport, error) {
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+
+	repoRoot, rootErr := gitutils.RepoRoot()
+	if cfg.Root == "" && len(cfg.Repos) > 0 {
+		if rootErr != nil {
+			return nil, fmt.Errorf("failed to find the workspace root: %v", rootErr)
+		}
+		cfg.Root = filepath.Dir(repoRoot)
+	}
+
+	report := &WorkspaceReport{}
+	for _, repo := range cfg.Repos {
+		path := workspaceRepoPath(cfg.Root, repo)
+		report.Checks = append(report.Checks, WorkspaceCheck{
+			Kind: "repo", Name: path, Err: cloneWorkspaceRepo(repo.URL, path, cfg.Auth),
+		})
+	}
+	for _, tool := range cfg.Tools {
+		report.Checks = append(report.Checks, WorkspaceCheck{
+			Kind: "tool", Name: tool, Err: installPinnedTool(tool),
+		})
+	}
+	if cfg.InstallHooks {
+		hooksErr := rootErr
+		if hooksErr == nil {
+			hooksErr = installWorkspaceHooks(repoRoot)
+		}
+		report.Checks = append(report.Checks, WorkspaceCheck{
+			Kind: "hooks", Name: "pre-commit", Err: hooksErr,
+		})
+	}
+	for _, key := range cfg.RequiredEnv {
+		report.Checks = append(report.Checks, WorkspaceCheck{
+			Kind: "env", Name: key, Err: sys.EnvVarSet(key),
+		})
+	}
+
+	printWorkspaceReport(cfg.Output, report)
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return report, fmt.Errorf("workspace is not ready: %d of %d checks failed",
+			len(failed), len(report.Checks))
+	}
+
+	return report, nil
+}
+
+// workspaceRepoPath returns where a repository is cloned.
+func workspaceRepoPath(root string, repo WorkspaceRepo) string {
+	path := repo.Path
+	if path == "" {
+		path = strings.TrimSuffix(filepath.Base(strings.TrimRight(repo.URL, "/")), ".git")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	return path
+}
+
+// cloneWorkspaceRepo clones a repository unless it's already cloned.
+func cloneWorkspaceRepo(url, path string, auth transport.AuthMethod) error {
+	if fileutils.Exists(path) {
+		if _, err := git.PlainOpen(path); err != nil {
+			return fmt.Errorf("%s exists but is not a git repository: %v", path, err)
+		}
+		return nil
+	}
+
+	_, err := gitutils.CloneRepo(url, path, auth)
+	return err
+}
+
+// installPinnedTool installs a Go tool, refusing unpinned versions so
+// that every contributor gets the same one.
+func installPinnedTool(tool string) error {
+	_, version, ok := strings.Cut(tool, "@")
+	if !ok || version == "" || version == "latest" {
+		return fmt.Errorf("%s is not pinned to a version", tool)
+	}
+
+	if _, err := sys.RunCommand("go", "install", tool); err != nil {
+		return fmt.Errorf("failed to install %s: %v", tool, err)
+	}
+
+	return nil
+}
+
+// installWorkspaceHooks installs the pre-commit hooks of a repository.
+func installWorkspaceHooks(repoRoot string) error {
+	if !fileutils.Exists(filepath.Join(repoRoot, ".pre-commit-config.yaml")) {
+		return errors.New("pre-commit is not configured for the current project")
+	}
+	if !sys.CmdExists("pre-commit") {
+		return errors.New("pre-commit is not installed")
+	}
+
+	cmd := sys.Cmd{
+		CmdString: "pre-commit",
+		Args:      []string{"install", "--hook-type", "pre-commit", "--hook-type", "commit-msg"},
+		Dir:       repoRoot,
+	}
+	if _, err := cmd.RunCmd(); err != nil {
+		return fmt.Errorf("failed to install pre-commit hooks: %v", err)
+	}
+
+	return nil
+}
+
+// printWorkspaceReport prints whether each step succeeded.
+func printWorkspaceReport(w io.Writer, report *WorkspaceReport) {
+	fmt.Fprintln(w, "Workspace readiness:")
+	for _, check := range report.Checks {
+		if check.Err != nil {
+			fmt.Fprintln(w, color.RedString("  [FAIL] %s %s: %v", check.Kind, check.Name, check.Err))
+		} else {
+			fmt.Fprintln(w, color.GreenString("  [ OK ] %s %s", check.Kind, check.Name))
+		}
+	}
+
+	if report.Ready() {
+		fmt.Fprintln(w, color.GreenString("Workspace is ready."))
+	} else {
+		fmt.Fprintln(w, color.RedString("Workspace is not ready: fix the failed checks and run the bootstrap again."))
+	}
+}
@@ -0,0 +1,114 @@
+package mageutils_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBootstrapWorkspace(t *testing.T) {
+	upstream := t.TempDir()
+	repo, err := git.PlainInit(upstream, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(upstream, "README.md"), []byte("# shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("BOOTSTRAP_TEST_TOKEN", "token")
+
+	testCases := []struct {
+		name       string
+		cfg        mageutils.WorkspaceConfig
+		wantFailed []string
+	}{
+		{
+			name: "Ready workspace",
+			cfg: mageutils.WorkspaceConfig{
+				Repos:       []mageutils.WorkspaceRepo{{URL: upstream, Path: "shared"}},
+				RequiredEnv: []string{"BOOTSTRAP_TEST_TOKEN"},
+			},
+		},
+		{
+			name: "Unpinned tool and missing env var",
+			cfg: mageutils.WorkspaceConfig{
+				Tools:       []string{"golang.org/x/tools/cmd/goimports@latest", "golang.org/x/lint/golint"},
+				RequiredEnv: []string{"BOOTSTRAP_TEST_TOKEN", "BOOTSTRAP_TEST_MISSING"},
+			},
+			wantFailed: []string{
+				"golang.org/x/tools/cmd/goimports@latest", "golang.org/x/lint/golint", "BOOTSTRAP_TEST_MISSING",
+			},
+		},
+		{
+			name: "Path is not a repository",
+			cfg: mageutils.WorkspaceConfig{
+				Repos: []mageutils.WorkspaceRepo{{URL: upstream, Path: "existing"}},
+			},
+			wantFailed: []string{"existing"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.Mkdir(filepath.Join(root, "existing"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			tc.cfg.Root = root
+			tc.cfg.Output = &out
+
+			report, err := mageutils.BootstrapWorkspace(tc.cfg)
+			if (err != nil) != (len(tc.wantFailed) > 0) {
+				t.Fatalf("BootstrapWorkspace() error = %v, want failures %v", err, tc.wantFailed)
+			}
+
+			failed := report.Failed()
+			if len(failed) != len(tc.wantFailed) {
+				t.Fatalf("Failed() = %v, want %v", failed, tc.wantFailed)
+			}
+			for i, check := range failed {
+				if !strings.HasSuffix(check.Name, tc.wantFailed[i]) {
+					t.Errorf("Failed()[%d] = %s, want %s", i, check.Name, tc.wantFailed[i])
+				}
+			}
+
+			if report.Ready() {
+				for _, r := range tc.cfg.Repos {
+					if _, err := git.PlainOpen(filepath.Join(root, r.Path)); err != nil {
+						t.Errorf("expected %s to be cloned: %v", r.Path, err)
+					}
+				}
+			}
+			if report.Ready() && !strings.Contains(out.String(), "Workspace is ready.") {
+				t.Errorf("report = %q, want the workspace to be ready", out.String())
+			}
+			for _, name := range tc.wantFailed {
+				if !strings.Contains(out.String(), "[FAIL]") || !strings.Contains(out.String(), name) {
+					t.Errorf("report = %q, want %s to fail", out.String(), name)
+				}
+			}
+		})
+	}
+}
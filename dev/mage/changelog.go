@@ -0,0 +1,187 @@
+package mageutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/magefile/mage/sh"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+// linkReferenceRegex matches the link reference definitions, such as
+// "[1.0.0]: https://...", at the end of a keep-a-changelog file.
+var linkReferenceRegex = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+
+// changelogSection is a version section of a keep-a-changelog file.
+type changelogSection struct {
+	version string
+	body    []string
+}
+
+// ExtractReleaseNotes returns the notes for a version from a changelog in
+// the keep-a-changelog format (https://keepachangelog.com). The notes are
+// the lines between the version's "## [version] - date" heading and the
+// next version heading, without the heading itself.
+//
+// **Parameters:**
+//
+// changelogPath: The path to the changelog, e.g., "CHANGELOG.md".
+// version: The version to extract, e.g., "v1.2.0" or "1.2.0". A leading
+// "v" is ignored on both sides. Use "Unreleased" for the unreleased
+// section.
+//
+// **Returns:**
+//
+// string: The release notes for the version, with surrounding blank lines
+// removed.
+// error: An error if the changelog can't be read or has no section for the
+// version.
+func ExtractReleaseNotes(changelogPath, version string) (string, error) {
+	sections, err := parseChangelog(changelogPath)
+	if err != nil {
+		return "", err
+	}
+
+	want := normalizeChangelogVersion(version)
+	for _, section := range sections {
+		if normalizeChangelogVersion(section.version) == want {
+			return strings.TrimSpace(strings.Join(section.body, "\n")), nil
+		}
+	}
+
+	return "", fmt.Errorf("no section for version %s found in %s", version, changelogPath)
+}
+
+// LatestChangelogVersion returns the newest released version in a
+// keep-a-changelog formatted file, which is the first version section
+// after the Unreleased section.
+//
+// **Parameters:**
+//
+// changelogPath: The path to the changelog, e.g., "CHANGELOG.md".
+//
+// **Returns:**
+//
+// string: The version as written in the changelog heading, e.g., "1.2.0".
+// error: An error if the changelog can't be read or has no released
+// versions.
+func LatestChangelogVersion(changelogPath string) (string, error) {
+	sections, err := parseChangelog(changelogPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, section := range sections {
+		if !strings.EqualFold(section.version, "unreleased") {
+			return section.version, nil
+		}
+	}
+
+	return "", fmt.Errorf("no released versions found in %s", changelogPath)
+}
+
+// GHReleaseWithNotes creates a new release on GitHub using the given
+// version and release notes, for example from ExtractReleaseNotes. Unlike
+// GHRelease, it doesn't regenerate the changelog. It requires the gh CLI
+// tool to be available on the PATH.
+//
+// **Parameters:**
+//
+// newVer: A string specifying the new version, e.g., "v1.0.1"
+// notes: The release notes.
+//
+// **Returns:**
+//
+// error: An error if the release can't be created.
+func GHReleaseWithNotes(newVer, notes string) error {
+	cmd := "gh"
+	if !sys.CmdExists(cmd) {
+		return fmt.Errorf("required cmd %s not found in $PATH", cmd)
+	}
+
+	notesDir, err := os.MkdirTemp("", "release-notes")
+	if err != nil {
+		return fmt.Errorf("failed to create release notes directory: %v", err)
+	}
+	defer os.RemoveAll(notesDir)
+
+	notesFile := filepath.Join(notesDir, "NOTES.md")
+	if err := os.WriteFile(notesFile, []byte(notes+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write release notes: %v", err)
+	}
+
+	if err := sh.RunV(cmd, "release", "create", newVer, "-F", notesFile); err != nil {
+		return fmt.Errorf("failed to create new release %s: %v", newVer, err)
+	}
+
+	return nil
+}
+
+// parseChangelog splits a keep-a-changelog file into its version
+// sections, in the order they appear.
+func parseChangelog(changelogPath string) ([]changelogSection, error) {
+	data, err := os.ReadFile(changelogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changelog %s: %v", changelogPath, err)
+	}
+
+	var sections []changelogSection
+	var current *changelogSection
+	inCodeBlock := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if !inCodeBlock {
+			if version, ok := changelogHeadingVersion(line); ok {
+				sections = append(sections, changelogSection{version: version})
+				current = &sections[len(sections)-1]
+				continue
+			}
+			if linkReferenceRegex.MatchString(trimmed) {
+				// Link references follow the last section.
+				current = nil
+				continue
+			}
+		}
+
+		if current != nil {
+			current.body = append(current.body, line)
+		}
+	}
+
+	return sections, nil
+}
+
+// changelogHeadingVersion returns the version of a "## [1.2.0] - date",
+// "## 1.2.0 - date", or "## [Unreleased]" heading.
+func changelogHeadingVersion(line string) (string, bool) {
+	if !strings.HasPrefix(line, "## ") {
+		return "", false
+	}
+	heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+
+	if strings.HasPrefix(heading, "[") {
+		end := strings.Index(heading, "]")
+		if end < 0 {
+			return "", false
+		}
+		return strings.TrimSpace(heading[1:end]), true
+	}
+
+	fields := strings.Fields(heading)
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[0], true
+}
+
+func normalizeChangelogVersion(version string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(version), "v"))
+}
@@ -0,0 +1,123 @@
+package mageutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+)
+
+const testChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+- Work in progress
+
+## [v1.2.0] - 2024-03-01
+
+### Added
+
+- Release notes extraction
+
+` + "```markdown\n## [0.0.1] - not a heading\n```" + `
+
+## 1.1.0 - 2024-02-01
+
+### Fixed
+
+- A bug
+
+## [1.0.0] - 2024-01-01
+
+[unreleased]: https://github.com/l50/goutils/compare/v1.2.0...HEAD
+[v1.2.0]: https://github.com/l50/goutils/compare/v1.1.0...v1.2.0
+`
+
+func writeChangelog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtractReleaseNotes(t *testing.T) {
+	path := writeChangelog(t, testChangelog)
+
+	testCases := []struct {
+		name    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "bracketed version with v prefix",
+			version: "1.2.0",
+			want:    "### Added\n\n- Release notes extraction\n\n```markdown\n## [0.0.1] - not a heading\n```",
+		},
+		{
+			name:    "plain heading",
+			version: "v1.1.0",
+			want:    "### Fixed\n\n- A bug",
+		},
+		{
+			name:    "unreleased section",
+			version: "unreleased",
+			want:    "- Work in progress",
+		},
+		{
+			name:    "empty last section before link references",
+			version: "1.0.0",
+			want:    "",
+		},
+		{
+			name:    "missing version",
+			version: "2.0.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mageutils.ExtractReleaseNotes(path, tc.version)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ExtractReleaseNotes() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ExtractReleaseNotes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := mageutils.ExtractReleaseNotes(filepath.Join(t.TempDir(), "missing.md"), "1.0.0"); err == nil {
+		t.Error("expected an error for a missing changelog")
+	}
+}
+
+func TestLatestChangelogVersion(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "skips unreleased", content: testChangelog, want: "v1.2.0"},
+		{name: "only unreleased", content: "## [Unreleased]\n\n- WIP\n", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mageutils.LatestChangelogVersion(writeChangelog(t, tc.content))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LatestChangelogVersion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("LatestChangelogVersion() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
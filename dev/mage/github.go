@@ -0,0 +1,165 @@
+package mageutils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/l50/goutils/v2/git/provider"
+)
+
+// GitHubClient talks to the GitHub REST API directly, so release and bot
+// workflows don't depend on the gh CLI being installed. Pull requests and
+// releases are managed with the embedded provider.GitHub; GitHubClient
+// adds the release asset uploads and pull request comments it doesn't
+// cover.
+//
+// **Attributes:**
+//
+// GitHub: The provider for the repository, holding its owner, name,
+// token, and API URL.
+type GitHubClient struct {
+	*provider.GitHub
+}
+
+// NewGitHubClient creates a GitHubClient authenticated with the
+// GITHUB_TOKEN environment variable.
+//
+// **Parameters:**
+//
+// repo: The repository in "owner/name" form. If empty, the
+// GITHUB_REPOSITORY environment variable set by GitHub Actions is used.
+//
+// **Returns:**
+//
+// *GitHubClient: The client for the repository.
+// error: An error if GITHUB_TOKEN isn't set or the repository is invalid.
+func NewGitHubClient(repo string) (*GitHubClient, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, errors.New("GITHUB_TOKEN is not set")
+	}
+
+	if repo == "" {
+		repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repository %q, expected owner/name", repo)
+	}
+
+	gh := provider.NewGitHub(owner, name, token)
+	gh.Client = &http.Client{Timeout: 5 * time.Minute}
+	return &GitHubClient{GitHub: gh}, nil
+}
+
+// UploadReleaseAsset uploads a file to the release of a tag. The asset
+// is named after the file.
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// tag: The tag of the release, e.g., "v1.0.1".
+// path: The path to the file to upload.
+//
+// **Returns:**
+//
+// string: The download URL of the uploaded asset.
+// error: An error if the release doesn't exist or the file can't be
+// uploaded.
+func (c *GitHubClient) UploadReleaseAsset(ctx context.Context, tag, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.repoURL("releases", "tags", tag), nil)
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		UploadURL string `json:"upload_url"`
+	}
+	if err := c.Do(req, &release); err != nil {
+		return "", fmt.Errorf("failed to get release %s: %w", tag, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read release asset %s: %v", path, err)
+	}
+
+	// The upload URL is a URI template such as ".../assets{?name,label}".
+	uploadURL, _, _ := strings.Cut(release.UploadURL, "{")
+	name := filepath.Base(path)
+	uploadURL += "?" + url.Values{"name": {name}}.Encode()
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	var asset struct {
+		BrowserDownloadURL string `json:"browser_download_url"`
+	}
+	if err := c.Do(req, &asset); err != nil {
+		return "", fmt.Errorf("failed to upload %s to release %s: %w", name, tag, err)
+	}
+
+	return asset.BrowserDownloadURL, nil
+}
+
+// CommentOnPR adds a comment to a pull request.
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// number: The number of the pull request.
+// body: The text of the comment.
+//
+// **Returns:**
+//
+// error: An error if the comment can't be created.
+func (c *GitHubClient) CommentOnPR(ctx context.Context, number int, body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.repoURL("issues", fmt.Sprint(number), "comments"), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := c.Do(req, nil); err != nil {
+		return fmt.Errorf("failed to comment on pull request #%d: %w", number, err)
+	}
+
+	return nil
+}
+
+// repoURL returns the API URL of a path below the repository, escaping
+// each segment.
+func (c *GitHubClient) repoURL(segments ...string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = provider.DefaultGitHubURL
+	}
+
+	parts := []string{strings.TrimRight(base, "/"), "repos", url.PathEscape(c.Owner), url.PathEscape(c.Repo)}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,185 @@
+package mageutils_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+	"github.com/l50/goutils/v2/git/provider"
+)
+
+// fakeGitHub serves the subset of the GitHub API used by GitHubClient
+// and records the requests it receives.
+type fakeGitHub struct {
+	t        *testing.T
+	server   *httptest.Server
+	requests map[string]map[string]any
+	uploaded map[string]string
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	f := &fakeGitHub{t: t, requests: map[string]map[string]any{}, uploaded: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/l50/goutils/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("tag") != "v1.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"Not Found"}`)
+			return
+		}
+		f.respond(w, map[string]any{"id": 7, "upload_url": f.server.URL + "/uploads/releases/7/assets{?name,label}"})
+	})
+	mux.HandleFunc("POST /uploads/releases/7/assets", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		name := r.URL.Query().Get("name")
+		f.uploaded[name] = string(data)
+		f.respond(w, map[string]any{"browser_download_url": "https://example.com/" + name})
+	})
+	for _, pattern := range []string{"POST /repos/l50/goutils/releases", "POST /repos/l50/goutils/pulls", "POST /repos/l50/goutils/issues/{number}/comments"} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = io.WriteString(w, `{"message":"Bad credentials"}`)
+				return
+			}
+			var payload map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			f.requests[r.URL.Path] = payload
+			f.respond(w, map[string]any{"number": 42, "html_url": "https://github.com/l50/goutils/pull/42"})
+		})
+	}
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGitHub) respond(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		f.t.Errorf("failed to encode response: %v", err)
+	}
+}
+
+func (f *fakeGitHub) client(token string) *mageutils.GitHubClient {
+	gh := provider.NewGitHub("l50", "goutils", token)
+	gh.BaseURL = f.server.URL
+	return &mageutils.GitHubClient{GitHub: gh}
+}
+
+func TestNewGitHubClient(t *testing.T) {
+	testCases := []struct {
+		name      string
+		token     string
+		envRepo   string
+		repo      string
+		wantOwner string
+		wantErr   bool
+	}{
+		{name: "Explicit repository", token: "secret", repo: "l50/goutils", wantOwner: "l50"},
+		{name: "Repository from environment", token: "secret", envRepo: "octo/cat", wantOwner: "octo"},
+		{name: "Missing token", repo: "l50/goutils", wantErr: true},
+		{name: "Invalid repository", token: "secret", repo: "goutils", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tc.token)
+			t.Setenv("GITHUB_REPOSITORY", tc.envRepo)
+
+			gh, err := mageutils.NewGitHubClient(tc.repo)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewGitHubClient() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && gh.Owner != tc.wantOwner {
+				t.Errorf("NewGitHubClient() owner = %s, want %s", gh.Owner, tc.wantOwner)
+			}
+		})
+	}
+}
+
+func TestGitHubClientReleases(t *testing.T) {
+	f := newFakeGitHub(t)
+	gh := f.client("secret")
+	ctx := context.Background()
+
+	if _, err := gh.CreateRelease(ctx, provider.ReleaseOptions{Tag: "v1.0.0", Body: "notes"}); err != nil {
+		t.Fatalf("CreateRelease() error = %v", err)
+	}
+	if got := f.requests["/repos/l50/goutils/releases"]["tag_name"]; got != "v1.0.0" {
+		t.Errorf("CreateRelease() sent tag_name %v, want v1.0.0", got)
+	}
+
+	asset := filepath.Join(t.TempDir(), "checksums.txt")
+	if err := os.WriteFile(asset, []byte("abc  app\n"), 0644); err != nil {
+		t.Fatalf("failed to write asset: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		tag     string
+		path    string
+		wantURL string
+		wantErr bool
+	}{
+		{name: "Upload asset", tag: "v1.0.0", path: asset, wantURL: "https://example.com/checksums.txt"},
+		{name: "Missing release", tag: "v9.9.9", path: asset, wantErr: true},
+		{name: "Missing file", tag: "v1.0.0", path: filepath.Join(t.TempDir(), "missing"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := gh.UploadReleaseAsset(ctx, tc.tag, tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UploadReleaseAsset() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if url != tc.wantURL {
+				t.Errorf("UploadReleaseAsset() = %s, want %s", url, tc.wantURL)
+			}
+			if f.uploaded["checksums.txt"] != "abc  app\n" {
+				t.Errorf("unexpected uploaded content %q", f.uploaded["checksums.txt"])
+			}
+		})
+	}
+}
+
+func TestGitHubClientPullRequests(t *testing.T) {
+	f := newFakeGitHub(t)
+	ctx := context.Background()
+
+	pr, err := f.client("secret").CreatePullRequest(ctx, provider.PullRequestOptions{
+		Title: "Update docs", Head: "docs-bot", Base: "main", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if pr.Number != 42 {
+		t.Errorf("CreatePullRequest() number = %d, want 42", pr.Number)
+	}
+	if got := f.requests["/repos/l50/goutils/pulls"]; got["head"] != "docs-bot" || got["draft"] != true {
+		t.Errorf("CreatePullRequest() sent %v", got)
+	}
+
+	if err := f.client("secret").CommentOnPR(ctx, 42, "Docs are up to date."); err != nil {
+		t.Fatalf("CommentOnPR() error = %v", err)
+	}
+	if got := f.requests["/repos/l50/goutils/issues/42/comments"]["body"]; got != "Docs are up to date." {
+		t.Errorf("CommentOnPR() sent body %v", got)
+	}
+
+	err = f.client("wrong").CommentOnPR(ctx, 42, "hi")
+	var apiErr *provider.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Bad credentials" {
+		t.Errorf("CommentOnPR() error = %v, want a 401 APIError", err)
+	}
+}
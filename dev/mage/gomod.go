@@ -0,0 +1,278 @@
+package mageutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ModuleRequirement is a dependency found by AuditGoMod.
+//
+// **Attributes:**
+//
+// GoMod: The path of the go.mod file the dependency was found in,
+// relative to the audited directory.
+// Path: The module path of the dependency.
+// Version: The version of the dependency.
+// Indirect: Whether the dependency is marked "// indirect".
+// Reason: Why the version was retracted, for retracted dependencies.
+type ModuleRequirement struct {
+	GoMod    string
+	Path     string
+	Version  string
+	Indirect bool
+	Reason   string
+}
+
+// ReplaceDirective is a replace directive found by AuditGoMod.
+//
+// **Attributes:**
+//
+// GoMod: The path of the go.mod file containing the directive, relative
+// to the audited directory.
+// OldPath: The module path being replaced.
+// OldVersion: The version being replaced. Empty if every version is.
+// NewPath: The replacement module path or directory.
+// NewVersion: The replacement version. Empty for directory replacements.
+// Local: Whether the replacement is a directory on disk, which only works
+// inside the repository and breaks `go install` of the module.
+type ReplaceDirective struct {
+	GoMod      string
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+	Local      bool
+}
+
+// MajorVersionSkew describes a dependency required at more than one major
+// version across the audited modules, e.g., both
+// "github.com/go-git/go-git/v5" and "github.com/go-git/go-git/v6".
+//
+// **Attributes:**
+//
+// Path: The module path without its major version suffix.
+// Majors: The distinct major versions required, sorted.
+// Requirements: The requirements of the dependency, sorted by go.mod.
+type MajorVersionSkew struct {
+	Path         string
+	Majors       []string
+	Requirements []ModuleRequirement
+}
+
+// GoModAudit is the dependency pinning report returned by AuditGoMod.
+//
+// **Attributes:**
+//
+// Modules: The go.mod files that were audited, relative to the audited
+// directory.
+// Replaces: The replace directives.
+// Retracted: The dependencies whose selected version was retracted by
+// its authors.
+// PseudoVersions: The requirements pinned to a pseudo-version (a commit)
+// rather than a tagged release.
+// MajorVersionSkew: The dependencies required at several major versions.
+type GoModAudit struct {
+	Modules          []string
+	Replaces         []ReplaceDirective
+	Retracted        []ModuleRequirement
+	PseudoVersions   []ModuleRequirement
+	MajorVersionSkew []MajorVersionSkew
+}
+
+// Clean reports whether the audit found no findings, which allows release
+// automation to gate on it.
+//
+// **Returns:**
+//
+// bool: True if there are no replace directives, retracted dependencies,
+// pseudo-versions, or major version skew.
+func (a *GoModAudit) Clean() bool {
+	return len(a.Replaces) == 0 && len(a.Retracted) == 0 &&
+		len(a.PseudoVersions) == 0 && len(a.MajorVersionSkew) == 0
+}
+
+// AuditGoMod audits the dependency pinning of every Go module in dir,
+// including nested modules. It reports replace directives, requirements
+// pinned to pseudo-versions, dependencies required at different major
+// versions, and dependencies whose selected version was retracted.
+// Retractions are looked up with `go list -m -retracted`, which uses the
+// module proxy, so they're only reported if it's reachable. vendor,
+// testdata, and hidden directories are skipped.
+//
+// **Parameters:**
+//
+// dir: A string representing the path to the repository to audit.
+//
+// **Returns:**
+//
+// *GoModAudit: The audit report.
+// error: An error if a go.mod file can't be read or parsed, or the
+// retracted versions can't be listed.
+func AuditGoMod(dir string) (*GoModAudit, error) {
+	goMods, err := findGoModFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(goMods) == 0 {
+		return nil, fmt.Errorf("no go.mod files found in %s", dir)
+	}
+
+	audit := &GoModAudit{}
+	majors := make(map[string]map[string]bool)
+	requirements := make(map[string][]ModuleRequirement)
+	for _, goMod := range goMods {
+		rel, err := filepath.Rel(dir, goMod)
+		if err != nil {
+			return nil, err
+		}
+		audit.Modules = append(audit.Modules, rel)
+
+		data, err := os.ReadFile(goMod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", goMod, err)
+		}
+		file, err := modfile.Parse(goMod, data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", goMod, err)
+		}
+
+		for _, rep := range file.Replace {
+			audit.Replaces = append(audit.Replaces, ReplaceDirective{
+				GoMod:      rel,
+				OldPath:    rep.Old.Path,
+				OldVersion: rep.Old.Version,
+				NewPath:    rep.New.Path,
+				NewVersion: rep.New.Version,
+				Local:      rep.New.Version == "",
+			})
+		}
+
+		for _, req := range file.Require {
+			requirement := ModuleRequirement{
+				GoMod:    rel,
+				Path:     req.Mod.Path,
+				Version:  req.Mod.Version,
+				Indirect: req.Indirect,
+			}
+			if module.IsPseudoVersion(req.Mod.Version) {
+				audit.PseudoVersions = append(audit.PseudoVersions, requirement)
+			}
+
+			base, _, ok := module.SplitPathVersion(req.Mod.Path)
+			if !ok {
+				base = req.Mod.Path
+			}
+			if majors[base] == nil {
+				majors[base] = make(map[string]bool)
+			}
+			majors[base][semver.Major(req.Mod.Version)] = true
+			requirements[base] = append(requirements[base], requirement)
+		}
+
+		retracted, err := listRetractedModules(filepath.Dir(goMod), rel)
+		if err != nil {
+			return nil, err
+		}
+		audit.Retracted = append(audit.Retracted, retracted...)
+	}
+
+	for base, found := range majors {
+		if len(found) < 2 {
+			continue
+		}
+		skew := MajorVersionSkew{Path: base, Requirements: requirements[base]}
+		for major := range found {
+			skew.Majors = append(skew.Majors, major)
+		}
+		sort.Slice(skew.Majors, func(i, j int) bool { return semver.Compare(skew.Majors[i], skew.Majors[j]) < 0 })
+		audit.MajorVersionSkew = append(audit.MajorVersionSkew, skew)
+	}
+	sort.Slice(audit.MajorVersionSkew, func(i, j int) bool {
+		return audit.MajorVersionSkew[i].Path < audit.MajorVersionSkew[j].Path
+	})
+
+	return audit, nil
+}
+
+// findGoModFiles returns the go.mod files under dir, skipping vendor,
+// testdata, and hidden directories.
+func findGoModFiles(dir string) ([]string, error) {
+	var goMods []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "go.mod" {
+			goMods = append(goMods, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find go.mod files in %s: %v", dir, err)
+	}
+
+	return goMods, nil
+}
+
+// listRetractedModules returns the modules in the build list of the Go
+// module in dir whose selected version is retracted.
+func listRetractedModules(dir, goMod string) ([]ModuleRequirement, error) {
+	cmd := exec.Command("go", "list", "-m", "-retracted", "-json", "all")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run `go list -m -retracted` in %s: %v\n%s", dir, err, stderr.String())
+	}
+
+	var retracted []ModuleRequirement
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var mod struct {
+			Path      string
+			Version   string
+			Main      bool
+			Indirect  bool
+			Retracted []string
+		}
+		if err := dec.Decode(&mod); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse `go list` output: %v", err)
+		}
+
+		if mod.Main || len(mod.Retracted) == 0 {
+			continue
+		}
+		retracted = append(retracted, ModuleRequirement{
+			GoMod:    goMod,
+			Path:     mod.Path,
+			Version:  mod.Version,
+			Indirect: mod.Indirect,
+			Reason:   strings.Join(mod.Retracted, "; "),
+		})
+	}
+
+	return retracted, nil
+}
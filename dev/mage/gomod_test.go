@@ -0,0 +1,162 @@
+package mageutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+)
+
+// writeFiles writes files, keyed by their path relative to root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for rel, contents := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// useFileProxy points the go command at a module proxy in a temporary
+// directory serving example.com/lib, whose v1.0.0 is retracted by
+// v1.1.0, and example.com/tool at v1 and v2.
+func useFileProxy(t *testing.T) {
+	t.Helper()
+
+	proxy := t.TempDir()
+	writeFiles(t, proxy, map[string]string{
+		"example.com/lib/@v/list":            "v1.0.0\nv1.1.0\n",
+		"example.com/lib/@v/v1.0.0.mod":      "module example.com/lib\n\ngo 1.22\n",
+		"example.com/lib/@v/v1.0.0.info":     `{"Version":"v1.0.0","Time":"2024-01-01T00:00:00Z"}`,
+		"example.com/lib/@v/v1.1.0.mod":      "module example.com/lib\n\ngo 1.22\n\nretract v1.0.0 // data loss bug\n",
+		"example.com/lib/@v/v1.1.0.info":     `{"Version":"v1.1.0","Time":"2024-02-01T00:00:00Z"}`,
+		"example.com/tool/@v/list":           "v1.2.0\n",
+		"example.com/tool/@v/v1.2.0.mod":     "module example.com/tool\n\ngo 1.22\n",
+		"example.com/tool/@v/v1.2.0.info":    `{"Version":"v1.2.0","Time":"2024-01-01T00:00:00Z"}`,
+		"example.com/tool/v2/@v/list":        "v2.0.0\n",
+		"example.com/tool/v2/@v/v2.0.0.mod":  "module example.com/tool/v2\n\ngo 1.22\n",
+		"example.com/tool/v2/@v/v2.0.0.info": `{"Version":"v2.0.0","Time":"2024-01-01T00:00:00Z"}`,
+	})
+
+	t.Setenv("GOPROXY", "file://"+filepath.ToSlash(proxy))
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOFLAGS", "-mod=mod -modcacherw")
+	t.Setenv("GOMODCACHE", t.TempDir())
+}
+
+func TestAuditGoMod(t *testing.T) {
+	useFileProxy(t)
+
+	testCases := []struct {
+		name           string
+		files          map[string]string
+		wantClean      bool
+		wantModules    int
+		wantReplaces   []mageutils.ReplaceDirective
+		wantRetracted  []string
+		wantPseudo     []string
+		wantSkewMajors map[string][]string
+	}{
+		{
+			name: "Pinning findings across modules",
+			files: map[string]string{
+				"go.mod": "module example.com/app\n\ngo 1.22\n\nrequire (\n" +
+					"\texample.com/lib v1.0.0\n" +
+					"\texample.com/fork v0.0.0-20240101000000-abcdefabcdef\n" +
+					"\texample.com/tool v1.2.0\n)\n\n" +
+					"replace example.com/fork => ./fork\n",
+				"fork/go.mod":     "module example.com/fork\n\ngo 1.22\n",
+				"tools/go.mod":    "module example.com/app/tools\n\ngo 1.22\n\nrequire example.com/tool/v2 v2.0.0\n",
+				"vendor/go.mod":   "module example.com/vendored\n\ngo 1.22\n\nrequire example.com/tool/v3 v3.0.0\n",
+				"testdata/go.mod": "module example.com/testdata\n\ngo 1.22\n\nreplace example.com/x => ../x\n",
+			},
+			wantModules: 3,
+			wantReplaces: []mageutils.ReplaceDirective{
+				{GoMod: "go.mod", OldPath: "example.com/fork", NewPath: "./fork", Local: true},
+			},
+			wantRetracted:  []string{"example.com/lib@v1.0.0: data loss bug"},
+			wantPseudo:     []string{"example.com/fork@v0.0.0-20240101000000-abcdefabcdef"},
+			wantSkewMajors: map[string][]string{"example.com/tool": {"v1", "v2"}},
+		},
+		{
+			name: "Clean module",
+			files: map[string]string{
+				"go.mod": "module example.com/app\n\ngo 1.22\n\nrequire example.com/tool v1.2.0\n",
+			},
+			wantClean:   true,
+			wantModules: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tc.files)
+
+			audit, err := mageutils.AuditGoMod(dir)
+			if err != nil {
+				t.Fatalf("AuditGoMod() error = %v", err)
+			}
+
+			if audit.Clean() != tc.wantClean {
+				t.Errorf("Clean() = %v, want %v: %+v", audit.Clean(), tc.wantClean, audit)
+			}
+			if len(audit.Modules) != tc.wantModules {
+				t.Errorf("audited %v, want %d modules", audit.Modules, tc.wantModules)
+			}
+
+			if len(audit.Replaces) != len(tc.wantReplaces) {
+				t.Fatalf("Replaces = %+v, want %+v", audit.Replaces, tc.wantReplaces)
+			}
+			for i, want := range tc.wantReplaces {
+				if audit.Replaces[i] != want {
+					t.Errorf("Replaces[%d] = %+v, want %+v", i, audit.Replaces[i], want)
+				}
+			}
+
+			var retracted []string
+			for _, req := range audit.Retracted {
+				retracted = append(retracted, req.Path+"@"+req.Version+": "+req.Reason)
+			}
+			assertStrings(t, "Retracted", retracted, tc.wantRetracted)
+
+			var pseudo []string
+			for _, req := range audit.PseudoVersions {
+				pseudo = append(pseudo, req.Path+"@"+req.Version)
+			}
+			assertStrings(t, "PseudoVersions", pseudo, tc.wantPseudo)
+
+			if len(audit.MajorVersionSkew) != len(tc.wantSkewMajors) {
+				t.Fatalf("MajorVersionSkew = %+v, want %v", audit.MajorVersionSkew, tc.wantSkewMajors)
+			}
+			for _, skew := range audit.MajorVersionSkew {
+				assertStrings(t, "MajorVersionSkew "+skew.Path, skew.Majors, tc.wantSkewMajors[skew.Path])
+			}
+		})
+	}
+
+	if _, err := mageutils.AuditGoMod(t.TempDir()); err == nil {
+		t.Error("AuditGoMod() expected an error for a directory without go.mod files")
+	}
+}
+
+func assertStrings(t *testing.T, name string, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package mageutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magefile/mage/sh"
+)
+
+// UnknownLicense is reported for dependencies whose license file is
+// missing or isn't recognized. Add it to the disallowed list passed to
+// LicenseReport to flag such dependencies for review.
+const UnknownLicense = "Unknown"
+
+// licenseFileNames are the names of the files checked for a license, in
+// order of preference, compared ignoring case.
+var licenseFileNames = []string{
+	"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md",
+	"LICENCE.txt", "COPYING", "COPYING.md", "COPYING.txt", "UNLICENSE",
+}
+
+// licenseMatcher identifies a license by phrases that all appear in its
+// text.
+type licenseMatcher struct {
+	id      string
+	phrases []string
+}
+
+// licenseMatchers are checked in order, so licenses whose text mentions
+// another license, such as the LGPL mentioning the GPL, come first.
+var licenseMatchers = []licenseMatcher{
+	{id: "AGPL-3.0", phrases: []string{"gnu affero general public license"}},
+	{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "GPL-3.0", phrases: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", phrases: []string{"gnu general public license", "version 2"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license", "2.0"}},
+	{id: "EPL-2.0", phrases: []string{"eclipse public license", "2.0"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+// DependencyLicense describes the license of a module dependency.
+//
+// **Attributes:**
+//
+// Module: The module path of the dependency.
+// Version: The version of the dependency.
+// License: The SPDX identifier of the detected license (e.g.,
+// "Apache-2.0"), or UnknownLicense.
+// LicenseFile: The path to the license file the license was detected
+// from. Empty if no license file was found.
+// Disallowed: Whether the license is on the disallowed list.
+type DependencyLicense struct {
+	Module      string
+	Version     string
+	License     string
+	LicenseFile string
+	Disallowed  bool
+}
+
+// VendorDeps executes 'go mod vendor' to copy the module dependencies
+// into the vendor directory.
+//
+// **Returns:**
+//
+// error: An error if the dependencies couldn't be vendored.
+func VendorDeps() error {
+	if err := sh.Run("go", "mod", "vendor"); err != nil {
+		return fmt.Errorf("failed to run `go mod vendor`: %v", err)
+	}
+
+	return nil
+}
+
+// LicenseReport enumerates the modules that the packages of the Go module
+// in dir depend on, detects the license of each from the license file at
+// the root of the module, and flags the ones whose license is disallowed.
+// Only modules providing packages that are built are reported, so
+// dependencies used only by other modules' tests are left out.
+//
+// **Parameters:**
+//
+// dir: A string representing the path to the Go module to report on.
+// disallowed: The SPDX identifiers of disallowed licenses (e.g.,
+// "GPL-3.0" or "AGPL-3.0"), compared ignoring case. Include UnknownLicense
+// to flag dependencies whose license can't be detected.
+//
+// **Returns:**
+//
+// []DependencyLicense: The dependencies and their licenses, sorted by
+// module path.
+// error: An error if the dependencies can't be listed, or if any
+// dependency has a disallowed license.
+func LicenseReport(dir string, disallowed []string) ([]DependencyLicense, error) {
+	modules, err := listDependencyModules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []DependencyLicense
+	var flagged []string
+	for _, mod := range modules {
+		dep := DependencyLicense{Module: mod.Path, Version: mod.Version, License: UnknownLicense}
+		if mod.Dir != "" {
+			dep.License, dep.LicenseFile, err = detectLicense(mod.Dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, id := range disallowed {
+			if strings.EqualFold(id, dep.License) {
+				dep.Disallowed = true
+				flagged = append(flagged, fmt.Sprintf("%s (%s)", dep.Module, dep.License))
+			}
+		}
+		report = append(report, dep)
+	}
+
+	if len(flagged) > 0 {
+		return report, fmt.Errorf("dependencies with disallowed licenses: %s", strings.Join(flagged, ", "))
+	}
+
+	return report, nil
+}
+
+// goListModule is the subset of the module information printed by
+// `go list -json` used by LicenseReport.
+type goListModule struct {
+	Path    string
+	Version string
+	Dir     string
+	Main    bool
+	Replace *goListModule
+}
+
+// listDependencyModules returns the modules, other than the main module,
+// that provide packages imported by the module in dir.
+func listDependencyModules(dir string) ([]goListModule, error) {
+	cmd := exec.Command("go", "list", "-deps", "-json=Module", "./...")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run `go list -deps` in %s: %v\n%s", dir, err, stderr.String())
+	}
+
+	seen := make(map[string]bool)
+	var modules []goListModule
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg struct{ Module *goListModule }
+		if err := dec.Decode(&pkg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse `go list` output: %v", err)
+		}
+
+		mod := pkg.Module
+		if mod == nil || mod.Main || seen[mod.Path] {
+			continue
+		}
+		seen[mod.Path] = true
+		if mod.Replace != nil && mod.Replace.Dir != "" {
+			mod.Dir = mod.Replace.Dir
+		}
+		modules = append(modules, *mod)
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+
+	return modules, nil
+}
+
+// detectLicense identifies the license of the module rooted at dir from
+// its license file, returning UnknownLicense if there's no license file or
+// its text isn't recognized.
+func detectLicense(dir string) (string, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read module directory %s: %v", dir, err)
+	}
+
+	for _, name := range licenseFileNames {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(entry.Name(), name) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read license file %s: %v", path, err)
+			}
+
+			return classifyLicense(string(data)), path, nil
+		}
+	}
+
+	return UnknownLicense, "", nil
+}
+
+// classifyLicense returns the SPDX identifier of the license text, or
+// UnknownLicense if it isn't recognized.
+func classifyLicense(text string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	for _, m := range licenseMatchers {
+		matched := true
+		for _, phrase := range m.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return m.id
+		}
+	}
+
+	return UnknownLicense
+}
@@ -0,0 +1,114 @@
+package mageutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+)
+
+// createLicenseModule creates a Go module that imports three local
+// dependencies licensed under MIT, GPL-3.0, and an unrecognized license,
+// and returns its path.
+func createLicenseModule(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	write := func(rel, contents string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deps := map[string]string{
+		"mitdep":     "MIT License\n\nPermission is hereby granted, free of charge, to any person\nobtaining a copy of this software...",
+		"gpldep":     "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n...",
+		"unknowndep": "All rights reserved.",
+	}
+	goMod := "module example.com/app\n\ngo 1.22\n\nrequire (\n"
+	replaces := ""
+	imports := ""
+	for name, license := range deps {
+		write(filepath.Join(name, "go.mod"), "module example.com/"+name+"\n\ngo 1.22\n")
+		write(filepath.Join(name, name+".go"), "package "+name+"\n\nconst Name = \""+name+"\"\n")
+		write(filepath.Join(name, "LICENSE"), license)
+		goMod += "\texample.com/" + name + " v0.0.0\n"
+		replaces += "replace example.com/" + name + " => ../" + name + "\n"
+		imports += "\t_ \"example.com/" + name + "\"\n"
+	}
+	write("app/go.mod", goMod+")\n\n"+replaces)
+	write("app/main.go", "package main\n\nimport (\n"+imports+")\n\nfunc main() {}\n")
+
+	return filepath.Join(root, "app")
+}
+
+func TestLicenseReport(t *testing.T) {
+	dir := createLicenseModule(t)
+
+	testCases := []struct {
+		name           string
+		disallowed     []string
+		wantErr        bool
+		wantDisallowed []string
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:           "disallowed license",
+			disallowed:     []string{"gpl-3.0", "AGPL-3.0"},
+			wantErr:        true,
+			wantDisallowed: []string{"example.com/gpldep"},
+		},
+		{
+			name:           "unknown licenses disallowed",
+			disallowed:     []string{mageutils.UnknownLicense},
+			wantErr:        true,
+			wantDisallowed: []string{"example.com/unknowndep"},
+		},
+	}
+
+	wantLicenses := map[string]string{
+		"example.com/gpldep":     "GPL-3.0",
+		"example.com/mitdep":     "MIT",
+		"example.com/unknowndep": mageutils.UnknownLicense,
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := mageutils.LicenseReport(dir, tc.disallowed)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LicenseReport() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(report) != len(wantLicenses) {
+				t.Fatalf("LicenseReport() returned %d dependencies, want %d", len(report), len(wantLicenses))
+			}
+
+			var disallowed []string
+			for _, dep := range report {
+				if dep.License != wantLicenses[dep.Module] {
+					t.Errorf("license of %s = %s, want %s", dep.Module, dep.License, wantLicenses[dep.Module])
+				}
+				if !strings.HasSuffix(dep.LicenseFile, "LICENSE") {
+					t.Errorf("license file of %s = %q", dep.Module, dep.LicenseFile)
+				}
+				if dep.Disallowed {
+					disallowed = append(disallowed, dep.Module)
+				}
+			}
+			if strings.Join(disallowed, ",") != strings.Join(tc.wantDisallowed, ",") {
+				t.Errorf("disallowed = %v, want %v", disallowed, tc.wantDisallowed)
+			}
+		})
+	}
+
+	if _, err := mageutils.LicenseReport(t.TempDir(), nil); err == nil {
+		t.Error("LicenseReport() should fail outside a Go module")
+	}
+}
@@ -0,0 +1,609 @@
+package mageutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	gitutils "github.com/l50/goutils/v2/git"
+	"github.com/l50/goutils/v2/git/provider"
+	"github.com/l50/goutils/v2/sys"
+)
+
+// ReleaseStep identifies a step of ReleasePipeline.
+type ReleaseStep string
+
+const (
+	// StepCleanTree ensures the git working tree has no uncommitted changes.
+	StepCleanTree ReleaseStep = "clean-tree"
+	// StepTest runs the tests and enforces the coverage threshold.
+	StepTest ReleaseStep = "test"
+	// StepDocs regenerates the docs and fails if they drifted.
+	StepDocs ReleaseStep = "docs"
+	// StepTag creates and pushes the next semver tag.
+	StepTag ReleaseStep = "tag"
+	// StepBuild builds the release artifacts.
+	StepBuild ReleaseStep = "build"
+	// StepChecksum writes a checksum file for the artifacts and signs it.
+	StepChecksum ReleaseStep = "checksum"
+	// StepRelease creates the GitHub release and uploads the artifacts.
+	StepRelease ReleaseStep = "release"
+)
+
+// ReleaseSteps lists every step of ReleasePipeline in the order they run.
+var ReleaseSteps = []ReleaseStep{
+	StepCleanTree, StepTest, StepDocs, StepTag, StepBuild, StepChecksum, StepRelease,
+}
+
+// BuildTarget is an operating system and architecture pair to compile
+// release artifacts for.
+//
+// **Attributes:**
+//
+// GOOS: The target operating system (e.g., "linux", "darwin", "windows").
+// GOARCH: The target architecture (e.g., "amd64", "arm64").
+type BuildTarget struct {
+	GOOS   string
+	GOARCH string
+}
+
+// ReleaseConfig configures ReleasePipeline.
+//
+// **Attributes:**
+//
+// Version: The version to release. If empty, the latest semver tag is
+// bumped according to Bump.
+// Bump: The part of the version to bump: "major", "minor", or "patch"
+// (default).
+// TestPackages: The packages to test (default "./...").
+// CoverageThreshold: The minimum total coverage percentage. 0 disables
+// the coverage gate.
+// GenerateDocs: Optional function that regenerates the docs, for example
+// by calling docs.CreatePackageDocs. The pipeline fails if it changes any
+// tracked file.
+// UseGoReleaser: Build with GoReleaser instead of compiling Targets.
+// Targets: The platforms to compile when UseGoReleaser is false.
+// BinaryName: The base name of the compiled binaries.
+// BuildDir: The directory artifacts are written to (default "dist").
+// Sign: Optional function that signs a file, for example with cosign or
+// gpg. It is called with the path to the checksum file and returns the
+// paths of the signature files it wrote, which are uploaded with the
+// release.
+// ChangelogPath: Optional path to a keep-a-changelog formatted file. If
+// set, the release notes for the version are extracted from it with
+// ExtractReleaseNotes instead of generating a new changelog.
+// Auth: The authentication method used to push the release tag.
+// Skip: Steps to skip.
+// DryRun: Run the read-only steps (clean tree, tests, docs) and report
+// what the remaining steps would do without tagging, building, or
+// publishing anything.
+// BeforeStep: Optional hook called before each step. Returning an error
+// aborts the pipeline.
+// AfterStep: Optional hook called after each step with the step's error.
+// The error it returns replaces the step's error.
+type ReleaseConfig struct {
+	Version           string
+	Bump              string
+	TestPackages      []string
+	CoverageThreshold float64
+	GenerateDocs      func() error
+	UseGoReleaser     bool
+	Targets           []BuildTarget
+	BinaryName        string
+	BuildDir          string
+	Sign              func(path string) ([]string, error)
+	ChangelogPath     string
+	Auth              transport.AuthMethod
+	Skip              []ReleaseStep
+	DryRun            bool
+	BeforeStep        func(step ReleaseStep) error
+	AfterStep         func(step ReleaseStep, err error) error
+}
+
+// ReleaseResult describes the outcome of ReleasePipeline.
+//
+// **Attributes:**
+//
+// Version: The version that was (or, in dry-run mode, would be) released.
+// Coverage: The total test coverage percentage, if tests were run.
+// Artifacts: The paths of the built artifacts.
+// ChecksumFile: The path of the checksum file, if one was written.
+// Signatures: The paths of the signature files returned by Sign.
+// CompletedSteps: The steps that ran successfully, in order.
+type ReleaseResult struct {
+	Version        string
+	Coverage       float64
+	Artifacts      []string
+	ChecksumFile   string
+	Signatures     []string
+	CompletedSteps []ReleaseStep
+}
+
+// ReleasePipeline runs a complete release of the repository in the
+// current directory: it ensures the git tree is clean, runs the tests
+// with a coverage gate, regenerates the docs and fails on drift, creates
+// and pushes the next semver tag, builds the artifacts with GoReleaser
+// or Compile, writes and signs a checksum file, and creates the GitHub
+// release with GHRelease. If the gh CLI isn't installed but GITHUB_TOKEN
+// is set, the release is created with a GitHubClient instead.
+//
+// **Parameters:**
+//
+// cfg: ReleaseConfig controlling the steps of the pipeline.
+//
+// **Returns:**
+//
+// *ReleaseResult: The outcome of the pipeline, populated up to the step
+// that failed.
+// error: An error if any step fails.
+func ReleasePipeline(cfg ReleaseConfig) (*ReleaseResult, error) {
+	if cfg.BuildDir == "" {
+		cfg.BuildDir = "dist"
+	}
+	if len(cfg.TestPackages) == 0 {
+		cfg.TestPackages = []string{"./..."}
+	}
+
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %v", err)
+	}
+
+	result := &ReleaseResult{Version: cfg.Version}
+	if result.Version == "" {
+		if result.Version, err = nextReleaseVersion(repo, cfg.Bump); err != nil {
+			return result, err
+		}
+	}
+
+	steps := map[ReleaseStep]func() error{
+		StepCleanTree: func() error { return ensureCleanTree(repo) },
+		StepTest: func() error {
+			result.Coverage, err = runTestsWithCoverage(cfg.TestPackages, cfg.CoverageThreshold)
+			return err
+		},
+		StepDocs: func() error { return checkDocsDrift(repo, cfg.GenerateDocs) },
+		StepTag:  func() error { return releaseTag(repo, result.Version, cfg) },
+		StepBuild: func() error {
+			result.Artifacts, err = buildArtifacts(result.Version, cfg)
+			return err
+		},
+		StepChecksum: func() error {
+			result.ChecksumFile, result.Signatures, err = writeChecksums(result.Artifacts, cfg)
+			return err
+		},
+		StepRelease: func() error { return publishRelease(result, cfg) },
+	}
+
+	for _, step := range ReleaseSteps {
+		if releaseStepSkipped(cfg.Skip, step) {
+			fmt.Println(color.YellowString("Skipping release step %s", step))
+			continue
+		}
+
+		if cfg.BeforeStep != nil {
+			if err := cfg.BeforeStep(step); err != nil {
+				return result, fmt.Errorf("before %s hook failed: %v", step, err)
+			}
+		}
+
+		fmt.Println(color.YellowString("Running release step %s", step))
+		stepErr := steps[step]()
+		if cfg.AfterStep != nil {
+			stepErr = cfg.AfterStep(step, stepErr)
+		}
+		if stepErr != nil {
+			return result, fmt.Errorf("release step %s failed: %v", step, stepErr)
+		}
+
+		result.CompletedSteps = append(result.CompletedSteps, step)
+	}
+
+	return result, nil
+}
+
+// BumpVersion increments part of a semantic version.
+//
+// **Parameters:**
+//
+// version: The version to bump, with or without a "v" prefix (e.g., "v1.2.3").
+// part: The part to bump: "major", "minor", or "patch". Empty means "patch".
+//
+// **Returns:**
+//
+// string: The bumped version with a "v" prefix.
+// error: An error if the version or part is invalid.
+func BumpVersion(version, part string) (string, error) {
+	major, minor, patch, err := parseSemver(version)
+	if err != nil {
+		return "", err
+	}
+
+	switch part {
+	case "major":
+		major, minor, patch = major+1, 0, 0
+	case "minor":
+		minor, patch = minor+1, 0
+	case "patch", "":
+		patch++
+	default:
+		return "", fmt.Errorf("invalid version part %q: must be major, minor, or patch", part)
+	}
+
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+}
+
+// parseSemver parses a "vMAJOR.MINOR.PATCH" version. Pre-release and
+// build metadata suffixes are ignored.
+func parseSemver(version string) (int, int, int, error) {
+	core := strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid semantic version %q", version)
+	}
+
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid semantic version %q", version)
+		}
+		nums[i] = n
+	}
+
+	return nums[0], nums[1], nums[2], nil
+}
+
+// nextReleaseVersion bumps the highest semver tag in the repository, or
+// v0.0.0 if there are no semver tags.
+func nextReleaseVersion(repo *git.Repository, bump string) (string, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %v", err)
+	}
+
+	latest := "v0.0.0"
+	var latestParts [3]int
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		major, minor, patch, err := parseSemver(name)
+		if err != nil {
+			return nil // ignore non-semver tags
+		}
+		parts := [3]int{major, minor, patch}
+		for i := range parts {
+			if parts[i] != latestParts[i] {
+				if parts[i] > latestParts[i] {
+					latest, latestParts = name, parts
+				}
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read tags: %v", err)
+	}
+
+	return BumpVersion(latest, bump)
+}
+
+func ensureCleanTree(repo *git.Repository) error {
+	changed, err := changedFiles(repo)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("working tree has uncommitted changes: %s", strings.Join(changed, ", "))
+	}
+
+	return nil
+}
+
+// changedFiles returns the sorted paths of files with uncommitted changes.
+func changedFiles(repo *git.Repository) ([]string, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree status: %v", err)
+	}
+
+	var changed []string
+	for path, s := range status {
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// runTestsWithCoverage runs the tests of the input packages and returns
+// the total coverage percentage.
+func runTestsWithCoverage(packages []string, threshold float64) (float64, error) {
+	profile, err := os.CreateTemp("", "coverage-*.out")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create coverage profile: %v", err)
+	}
+	profile.Close()
+	defer os.Remove(profile.Name())
+
+	testCmd := sys.Cmd{
+		CmdString: "go",
+		Args:      append([]string{"test", "-coverprofile=" + profile.Name()}, packages...),
+	}
+	if out, err := testCmd.RunCmd(); err != nil {
+		return 0, fmt.Errorf("tests failed: %v\n%s", err, out)
+	}
+
+	coverCmd := sys.Cmd{
+		CmdString:     "go",
+		Args:          []string{"tool", "cover", "-func=" + profile.Name()},
+		OutputHandler: func(string) {},
+	}
+	out, err := coverCmd.RunCmd()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute coverage: %v", err)
+	}
+
+	coverage, err := parseTotalCoverage(out)
+	if err != nil {
+		return 0, err
+	}
+	if coverage < threshold {
+		return coverage, fmt.Errorf("coverage %.1f%% is below the required %.1f%%", coverage, threshold)
+	}
+
+	return coverage, nil
+}
+
+// parseTotalCoverage extracts the total percentage from the output of
+// `go tool cover -func`.
+func parseTotalCoverage(output string) (float64, error) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "total:" {
+			return strconv.ParseFloat(strings.TrimSuffix(fields[len(fields)-1], "%"), 64)
+		}
+	}
+
+	return 0, errors.New("total coverage not found in go tool cover output")
+}
+
+func checkDocsDrift(repo *git.Repository, generate func() error) error {
+	if generate == nil {
+		return nil
+	}
+
+	if err := generate(); err != nil {
+		return fmt.Errorf("failed to generate docs: %v", err)
+	}
+
+	changed, err := changedFiles(repo)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("docs are out of date, regenerate and commit: %s", strings.Join(changed, ", "))
+	}
+
+	return nil
+}
+
+func releaseTag(repo *git.Repository, version string, cfg ReleaseConfig) error {
+	if cfg.DryRun {
+		fmt.Printf("[dry-run] would create and push tag %s\n", version)
+		return nil
+	}
+
+	if err := gitutils.CreateTag(repo, version); err != nil {
+		return err
+	}
+
+	return gitutils.PushTag(repo, version, cfg.Auth)
+}
+
+func buildArtifacts(version string, cfg ReleaseConfig) ([]string, error) {
+	if cfg.UseGoReleaser {
+		if cfg.DryRun {
+			fmt.Println("[dry-run] would run goreleaser")
+			return nil, nil
+		}
+		if err := GoReleaser(); err != nil {
+			return nil, err
+		}
+		return goReleaserArtifacts(cfg.BuildDir)
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, errors.New("no build targets configured")
+	}
+	if cfg.BinaryName == "" {
+		return nil, errors.New("BinaryName is required to compile build targets")
+	}
+
+	var artifacts []string
+	for _, target := range cfg.Targets {
+		name := fmt.Sprintf("%s_%s_%s_%s", cfg.BinaryName, version, target.GOOS, target.GOARCH)
+		if target.GOOS == "windows" {
+			name += ".exe"
+		}
+		artifacts = append(artifacts, filepath.Join(cfg.BuildDir, name))
+	}
+
+	if cfg.DryRun {
+		for _, artifact := range artifacts {
+			fmt.Printf("[dry-run] would compile %s\n", artifact)
+		}
+		return artifacts, nil
+	}
+
+	// Compile sets GOOS and GOARCH for the process, so restore them once
+	// the matrix is built.
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	defer func() {
+		os.Setenv("GOOS", goos)
+		os.Setenv("GOARCH", goarch)
+	}()
+
+	for i, target := range cfg.Targets {
+		if err := Compile(artifacts[i], target.GOOS, target.GOARCH); err != nil {
+			return nil, fmt.Errorf("failed to compile %s/%s: %v", target.GOOS, target.GOARCH, err)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// goReleaserArtifacts returns the archives and binaries GoReleaser wrote
+// to the top level of dir, leaving out its metadata and checksum files.
+func goReleaserArtifacts(dir string) ([]string, error) {
+	files, err := fileutils.ListRWithOptions(dir, fileutils.ListROptions{MaxDepth: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []string
+	for _, file := range files {
+		switch filepath.Ext(file) {
+		case ".json", ".yaml", ".yml", ".txt":
+			continue
+		}
+		artifacts = append(artifacts, file)
+	}
+
+	return artifacts, nil
+}
+
+// writeChecksums writes a sha256sum-compatible checksum file for the
+// artifacts to the build directory and signs it.
+func writeChecksums(artifacts []string, cfg ReleaseConfig) (string, []string, error) {
+	checksumFile := filepath.Join(cfg.BuildDir, "checksums.txt")
+	if cfg.DryRun {
+		fmt.Printf("[dry-run] would write and sign %s for %d artifacts\n", checksumFile, len(artifacts))
+		return checksumFile, nil, nil
+	}
+
+	var sb strings.Builder
+	for _, artifact := range artifacts {
+		sum, err := fileutils.Checksum(artifact, fileutils.SHA256)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(fmt.Sprintf("%s  %s\n", sum, filepath.Base(artifact)))
+	}
+
+	if err := os.WriteFile(checksumFile, []byte(sb.String()), 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write %s: %v", checksumFile, err)
+	}
+
+	if cfg.Sign == nil {
+		return checksumFile, nil, nil
+	}
+
+	signatures, err := cfg.Sign(checksumFile)
+	if err != nil {
+		return checksumFile, nil, fmt.Errorf("failed to sign %s: %v", checksumFile, err)
+	}
+
+	return checksumFile, signatures, nil
+}
+
+func publishRelease(result *ReleaseResult, cfg ReleaseConfig) error {
+	uploads := append([]string{}, result.Artifacts...)
+	if result.ChecksumFile != "" {
+		uploads = append(uploads, result.ChecksumFile)
+	}
+	uploads = append(uploads, result.Signatures...)
+
+	var notes string
+	if cfg.ChangelogPath != "" {
+		var err error
+		if notes, err = ExtractReleaseNotes(cfg.ChangelogPath, result.Version); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DryRun {
+		fmt.Printf("[dry-run] would create GitHub release %s with %d files\n", result.Version, len(uploads))
+		return nil
+	}
+
+	// Minimal CI images often lack the gh CLI but provide a token.
+	if !sys.CmdExists("gh") && os.Getenv("GITHUB_TOKEN") != "" {
+		return publishReleaseWithAPI(result.Version, notes, uploads)
+	}
+
+	if cfg.ChangelogPath != "" {
+		if err := GHReleaseWithNotes(result.Version, notes); err != nil {
+			return err
+		}
+	} else if err := GHRelease(result.Version); err != nil {
+		return err
+	}
+	if len(uploads) == 0 {
+		return nil
+	}
+
+	uploadCmd := sys.Cmd{
+		CmdString: "gh",
+		Args:      append([]string{"release", "upload", result.Version}, uploads...),
+		Timeout:   30 * time.Minute,
+	}
+	if out, err := uploadCmd.RunCmd(); err != nil {
+		return fmt.Errorf("failed to upload release artifacts: %v\n%s", err, out)
+	}
+
+	return nil
+}
+
+// publishReleaseWithAPI creates the release and uploads its files through
+// the GitHub API instead of the gh CLI.
+func publishReleaseWithAPI(version, notes string, uploads []string) error {
+	gh, err := NewGitHubClient("")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if _, err := gh.CreateRelease(ctx, provider.ReleaseOptions{Tag: version, Body: notes}); err != nil {
+		return err
+	}
+	for _, upload := range uploads {
+		if _, err := gh.UploadReleaseAsset(ctx, version, upload); err != nil {
+			return fmt.Errorf("failed to upload release artifacts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func releaseStepSkipped(skip []ReleaseStep, step ReleaseStep) bool {
+	for _, s := range skip {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
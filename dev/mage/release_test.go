@@ -0,0 +1,210 @@
+package mageutils_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	mageutils "github.com/l50/goutils/v2/dev/mage"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBumpVersion(t *testing.T) {
+	testCases := []struct {
+		name    string
+		version string
+		part    string
+		want    string
+		wantErr bool
+	}{
+		{name: "patch", version: "v1.2.3", part: "patch", want: "v1.2.4"},
+		{name: "default part is patch", version: "1.2.3", want: "v1.2.4"},
+		{name: "minor", version: "v1.2.3", part: "minor", want: "v1.3.0"},
+		{name: "major", version: "v1.2.3", part: "major", want: "v2.0.0"},
+		{name: "pre-release suffix is dropped", version: "v1.2.3-rc.1", part: "patch", want: "v1.2.4"},
+		{name: "invalid version", version: "v1.2", part: "patch", wantErr: true},
+		{name: "invalid part", version: "v1.2.3", part: "build", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mageutils.BumpVersion(tc.version, tc.part)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("BumpVersion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("BumpVersion() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// createReleaseRepo creates a tagged git repository containing a small
+// tested Go module and changes into it for the duration of the test.
+func createReleaseRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/release\n\ngo 1.22\n",
+		"calc.go":      "package release\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n",
+		"calc_test.go": "package release\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal(\"bad sum\")\n\t}\n}\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"v1.2.3", "v1.10.0", "not-semver"} {
+		if _, err := repo.CreateTag(tag, hash, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	return dir
+}
+
+func TestReleasePipeline(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       mageutils.ReleaseConfig
+		setup     func(t *testing.T, dir string)
+		wantSteps []mageutils.ReleaseStep
+		wantErr   bool
+	}{
+		{
+			name: "dry run completes every step",
+			cfg: mageutils.ReleaseConfig{
+				Bump:              "minor",
+				CoverageThreshold: 40,
+				BinaryName:        "calc",
+				Targets:           []mageutils.BuildTarget{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "windows", GOARCH: "amd64"}},
+				GenerateDocs:      func() error { return nil },
+				DryRun:            true,
+			},
+			wantSteps: mageutils.ReleaseSteps,
+		},
+		{
+			name: "dirty tree fails",
+			cfg:  mageutils.ReleaseConfig{DryRun: true},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte("package release\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name:      "coverage gate fails",
+			cfg:       mageutils.ReleaseConfig{CoverageThreshold: 90, DryRun: true},
+			wantSteps: []mageutils.ReleaseStep{mageutils.StepCleanTree},
+			wantErr:   true,
+		},
+		{
+			name: "docs drift fails",
+			cfg: mageutils.ReleaseConfig{
+				Skip: []mageutils.ReleaseStep{mageutils.StepTest},
+				GenerateDocs: func() error {
+					return os.WriteFile("README.md", []byte("# release\n"), 0644)
+				},
+				DryRun: true,
+			},
+			wantSteps: []mageutils.ReleaseStep{mageutils.StepCleanTree},
+			wantErr:   true,
+		},
+		{
+			name: "before hook aborts the pipeline",
+			cfg: mageutils.ReleaseConfig{
+				BeforeStep: func(step mageutils.ReleaseStep) error {
+					if step == mageutils.StepTag {
+						return errors.New("not today")
+					}
+					return nil
+				},
+				Skip:   []mageutils.ReleaseStep{mageutils.StepTest},
+				DryRun: true,
+			},
+			wantSteps: []mageutils.ReleaseStep{mageutils.StepCleanTree, mageutils.StepDocs},
+			wantErr:   true,
+		},
+		{
+			name: "missing changelog section fails",
+			cfg: mageutils.ReleaseConfig{
+				Skip:          []mageutils.ReleaseStep{mageutils.StepTest, mageutils.StepBuild, mageutils.StepChecksum},
+				ChangelogPath: writeChangelog(t, "# Changelog\n\n## [1.10.0] - 2024-01-01\n\n- Old release\n"),
+				DryRun:        true,
+			},
+			wantSteps: []mageutils.ReleaseStep{mageutils.StepCleanTree, mageutils.StepDocs, mageutils.StepTag},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createReleaseRepo(t)
+			if tc.setup != nil {
+				tc.setup(t, dir)
+			}
+
+			result, err := mageutils.ReleasePipeline(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ReleasePipeline() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(result.CompletedSteps, tc.wantSteps) {
+				t.Errorf("completed steps = %v, want %v", result.CompletedSteps, tc.wantSteps)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if result.Version != "v1.11.0" {
+				t.Errorf("version = %s, want v1.11.0", result.Version)
+			}
+			if result.Coverage != 50 {
+				t.Errorf("coverage = %.1f, want 50.0", result.Coverage)
+			}
+			wantArtifacts := []string{
+				filepath.Join("dist", "calc_v1.11.0_linux_amd64"),
+				filepath.Join("dist", "calc_v1.11.0_windows_amd64.exe"),
+			}
+			if !reflect.DeepEqual(result.Artifacts, wantArtifacts) {
+				t.Errorf("artifacts = %v, want %v", result.Artifacts, wantArtifacts)
+			}
+			if _, err := os.Stat("dist"); !os.IsNotExist(err) {
+				t.Error("dry run should not build anything")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package docs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gitutils "github.com/l50/goutils/v2/git"
+)
+
+// ChangeEntry describes a commit that changed a package, rendered in the
+// "Recent changes" section of its documentation.
+//
+// **Attributes:**
+//
+// Hash:    The abbreviated commit hash.
+// Subject: The first line of the commit message.
+// Author:  The name of the commit author.
+// Date:    When the commit was authored.
+type ChangeEntry struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+}
+
+// ChangeOptions enables the "Recent changes" section of the generated
+// package documentation.
+//
+// **Attributes:**
+//
+// Repository: The git repository holding the documented packages. The
+// walked filesystem paths must be relative to its root.
+// SinceTag:   Only include commits after this tag. Empty means the most
+// recent tag, or the whole history if the repository has no tags.
+// MaxCount:   The maximum number of changes per package. 0 means no
+// limit.
+type ChangeOptions struct {
+	Repository *git.Repository
+	SinceTag   string
+	MaxCount   int
+}
+
+// changeCollector reads the changes of each package from a repository.
+// Packages are documented concurrently, so access to the repository is
+// serialized.
+type changeCollector struct {
+	mu       sync.Mutex
+	repo     *git.Repository
+	since    time.Time
+	maxCount int
+}
+
+// newChangeCollector resolves the tag that opts.SinceTag refers to once,
+// so that every package is compared against the same starting point.
+func newChangeCollector(opts *ChangeOptions) (*changeCollector, error) {
+	if opts == nil || opts.Repository == nil {
+		return nil, nil
+	}
+
+	collector := &changeCollector{repo: opts.Repository, maxCount: opts.MaxCount}
+
+	var tagCommit *object.Commit
+	var err error
+	if opts.SinceTag != "" {
+		tagCommit, err = tagCommitByName(opts.Repository, opts.SinceTag)
+	} else {
+		tagCommit, err = latestTagCommit(opts.Repository)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tagCommit != nil {
+		// Git timestamps have second precision, so this excludes the
+		// tagged commit itself.
+		collector.since = tagCommit.Committer.When.Add(time.Second)
+	}
+
+	return collector, nil
+}
+
+// changes returns the commits that touched the package at path, newest
+// first.
+func (c *changeCollector) changes(path string) ([]ChangeEntry, error) {
+	pathFilter := filepath.ToSlash(filepath.Clean(path))
+	if pathFilter == "." {
+		pathFilter = ""
+	}
+
+	c.mu.Lock()
+	commits, err := gitutils.Log(c.repo, gitutils.LogOptions{
+		Since:      c.since,
+		PathFilter: pathFilter,
+		MaxCount:   c.maxCount,
+	})
+	c.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("error reading changes for %s: %w", path, err)
+	}
+
+	entries := make([]ChangeEntry, 0, len(commits))
+	for _, commit := range commits {
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		entries = append(entries, ChangeEntry{
+			Hash:    commit.Hash[:7],
+			Subject: strings.TrimSpace(subject),
+			Author:  commit.Author,
+			Date:    commit.Date,
+		})
+	}
+
+	return entries, nil
+}
+
+// tagCommitByName returns the commit a lightweight or annotated tag points
+// to.
+func tagCommitByName(repo *git.Repository, name string) (*object.Commit, error) {
+	ref, err := repo.Tag(name)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving tag %s: %w", name, err)
+	}
+	return refCommit(repo, ref)
+}
+
+// latestTagCommit returns the most recently committed commit that a tag
+// points to, or nil if the repository has no tags.
+func latestTagCommit(repo *git.Repository) (*object.Commit, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %w", err)
+	}
+
+	var latest *object.Commit
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		commit, err := refCommit(repo, ref)
+		if err != nil {
+			return err
+		}
+		if latest == nil || commit.Committer.When.After(latest.Committer.When) {
+			latest = commit
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// refCommit returns the commit a tag reference points to, peeling
+// annotated tags.
+func refCommit(repo *git.Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return nil, fmt.Errorf("error resolving tag %s: %w", ref.Name().Short(), err)
+		}
+		return commit, nil
+	}
+
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("error resolving tag %s: %w", ref.Name().Short(), err)
+	}
+	return commit, nil
+}
@@ -0,0 +1,124 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+func TestCreatePackageDocsChanges(t *testing.T) {
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(day int, msg string, file string) {
+		path := filepath.Join(dir, file)
+		src := "package " + filepath.Base(filepath.Dir(path)) + "\n\n// F does nothing.\nfunc F() {}\n// " + msg + "\n"
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		sig := &object.Signature{Name: "alice", Email: "alice@example.com", When: base.AddDate(0, 0, day)}
+		if _, err := w.Commit(msg, &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit(0, "add geo", "geo/geo.go")
+	head, err := repository.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repository.CreateTag("v1.0.0", head.Hash(), nil); err != nil {
+		t.Fatal(err)
+	}
+	commit(1, "fix geo\n\nLonger description.", "geo/geo.go")
+	commit(2, "add net", "net/net.go")
+	commit(3, "tweak geo again", "geo/geo.go")
+
+	testCases := []struct {
+		name      string
+		opts      *docs.ChangeOptions
+		wantGeo   []string
+		wantNet   []string
+		expectErr bool
+	}{
+		{
+			name:    "Changes since the latest tag",
+			opts:    &docs.ChangeOptions{Repository: repository},
+			wantGeo: []string{"tweak geo again", "fix geo"},
+			wantNet: []string{"add net"},
+		},
+		{
+			name:    "Max count",
+			opts:    &docs.ChangeOptions{Repository: repository, MaxCount: 1},
+			wantGeo: []string{"tweak geo again"},
+			wantNet: []string{"add net"},
+		},
+		{
+			name: "Disabled",
+		},
+		{
+			name:      "Unknown tag",
+			opts:      &docs.ChangeOptions{Repository: repository, SinceTag: "v9.9.9"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewBasePathFs(afero.NewOsFs(), dir)
+			r := docs.Repo{Owner: "l50", Name: "goutils", Changes: tc.opts}
+
+			err := docs.CreatePackageDocsWithFormat(fs, r, "", docs.FormatJSON)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("CreatePackageDocsWithFormat() error = %v, expectErr %v", err, tc.expectErr)
+			}
+			if tc.expectErr {
+				return
+			}
+
+			for pkg, want := range map[string][]string{"geo": tc.wantGeo, "net": tc.wantNet} {
+				data, err := afero.ReadFile(fs, filepath.Join(pkg, "docs.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var pkgDoc docs.PackageDoc
+				if err := json.Unmarshal(data, &pkgDoc); err != nil {
+					t.Fatal(err)
+				}
+
+				var got []string
+				for _, change := range pkgDoc.Changes {
+					if len(change.Hash) != 7 || change.Author != "alice" {
+						t.Errorf("%s change = %+v, want a short hash by alice", pkg, change)
+					}
+					got = append(got, change.Subject)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s changes = %q, want %q", pkg, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package docs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// UndocumentedIdentifier is an exported identifier without a doc comment.
+//
+// **Attributes:**
+//
+// Name: The identifier name. Methods are named "Type.Method".
+// Kind: "function", "method", "type", "const", or "var".
+// File: The path of the file that declares the identifier.
+// Line: The line of the declaration.
+type UndocumentedIdentifier struct {
+	Name string
+	Kind string
+	File string
+	Line int
+}
+
+// String returns the identifier in the file:line format used by compilers
+// and linters, so that editors can jump to it.
+//
+// **Returns:**
+//
+// string: The location and name of the undocumented identifier.
+func (u UndocumentedIdentifier) String() string {
+	return fmt.Sprintf("%s:%d: %s %s is undocumented", u.File, u.Line, u.Kind, u.Name)
+}
+
+// CoverageReport summarizes the documentation coverage of the exported
+// identifiers found by CheckDocCoverage.
+//
+// **Attributes:**
+//
+// Total:        The number of exported identifiers.
+// Documented:   The number of exported identifiers with a doc comment.
+// Undocumented: The exported identifiers without a doc comment, sorted by
+// file and line.
+type CoverageReport struct {
+	Total        int
+	Documented   int
+	Undocumented []UndocumentedIdentifier
+}
+
+// Percent returns the percentage of exported identifiers that have a doc
+// comment. A report without exported identifiers is fully covered.
+//
+// **Returns:**
+//
+// float64: The documentation coverage, from 0 to 100.
+func (r *CoverageReport) Percent() float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return float64(r.Documented) / float64(r.Total) * 100
+}
+
+// CoverageOptions configures CheckDocCoverage.
+//
+// **Attributes:**
+//
+// MinPercent: The minimum documentation coverage. CheckDocCoverage returns
+// an error when the coverage is below it. Zero disables enforcement.
+type CoverageOptions struct {
+	MinPercent float64
+}
+
+// CoverageOption is a function that modifies CoverageOptions.
+type CoverageOption func(*CoverageOptions)
+
+// WithMinCoverage makes CheckDocCoverage fail when the documentation
+// coverage is below the input percentage, e.g., in a mage target that
+// gates a release.
+//
+// **Parameters:**
+//
+// percent: The minimum coverage, from 0 to 100.
+//
+// **Returns:**
+//
+// CoverageOption: A CoverageOption that sets the minimum coverage.
+func WithMinCoverage(percent float64) CoverageOption {
+	return func(opts *CoverageOptions) {
+		opts.MinPercent = percent
+	}
+}
+
+// CheckDocCoverage reports the exported functions, methods, types,
+// constants, and variables under dir that have no doc comment. Test files,
+// hidden directories, testdata and vendor directories, and the paths
+// listed in dir's .docgenignore file are skipped. Constants and variables
+// are documented by a comment on their declaration group, and methods are
+// only counted for exported receiver types.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+//
+// dir: The root directory to check.
+//
+// options: Zero or more CoverageOption functions, such as
+// WithMinCoverage.
+//
+// **Returns:**
+//
+// *CoverageReport: The coverage of the exported identifiers. It is
+// returned even when the coverage is below the minimum.
+//
+// error: An error if the files can't be read or parsed, or if the coverage
+// is below the minimum set with WithMinCoverage.
+func CheckDocCoverage(fs afero.Fs, dir string, options ...CoverageOption) (*CoverageReport, error) {
+	opts := &CoverageOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	ignoreList, err := loadIgnoreList(fs, filepath.Join(dir, ".docgenignore"))
+	if err != nil {
+		return nil, fmt.Errorf("error loading ignore list: %w", err)
+	}
+
+	report := &CoverageReport{Undocumented: []UndocumentedIdentifier{}}
+	fset := token.NewFileSet()
+	err = afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			if _, ignored := ignoreList[filepath.Clean(rel)]; ignored {
+				return filepath.SkipDir
+			}
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+		checkFileCoverage(fset, file, report)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking directories: %w", err)
+	}
+
+	sort.SliceStable(report.Undocumented, func(i, j int) bool {
+		a, b := report.Undocumented[i], report.Undocumented[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	if opts.MinPercent > 0 && report.Percent() < opts.MinPercent {
+		return report, fmt.Errorf("documentation coverage %.1f%% is below the required %.1f%%: %d undocumented identifiers",
+			report.Percent(), opts.MinPercent, len(report.Undocumented))
+	}
+
+	return report, nil
+}
+
+// checkFileCoverage adds the exported identifiers declared in file to
+// report.
+func checkFileCoverage(fset *token.FileSet, file *ast.File, report *CoverageReport) {
+	record := func(name, kind string, pos token.Pos, doc *ast.CommentGroup) {
+		report.Total++
+		if strings.TrimSpace(doc.Text()) != "" {
+			report.Documented++
+			return
+		}
+		position := fset.Position(pos)
+		report.Undocumented = append(report.Undocumented, UndocumentedIdentifier{
+			Name: name,
+			Kind: kind,
+			File: position.Filename,
+			Line: position.Line,
+		})
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				record(d.Name.Name, "function", d.Pos(), d.Doc)
+				continue
+			}
+			recv := embeddedTypeName(d.Recv.List[0].Type)
+			if ast.IsExported(recv) {
+				record(recv+"."+d.Name.Name, "method", d.Pos(), d.Doc)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if !s.Name.IsExported() {
+						continue
+					}
+					// An unparenthesized declaration has its doc comment
+					// on the GenDecl.
+					doc := s.Doc
+					if doc == nil && !d.Lparen.IsValid() {
+						doc = d.Doc
+					}
+					record(s.Name.Name, "type", s.Pos(), doc)
+				case *ast.ValueSpec:
+					doc := s.Doc
+					if doc == nil {
+						doc = s.Comment
+					}
+					if doc == nil {
+						doc = d.Doc
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							record(name.Name, d.Tok.String(), name.Pos(), doc)
+						}
+					}
+				}
+			}
+		}
+	}
+}
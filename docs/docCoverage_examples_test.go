@@ -0,0 +1,24 @@
+package docs_test
+
+import (
+	"fmt"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+func ExampleCheckDocCoverage() {
+	fs := afero.NewOsFs()
+
+	// Fail when less than 80% of the exported identifiers are documented,
+	// e.g., in a mage target
+	report, err := docs.CheckDocCoverage(fs, ".", docs.WithMinCoverage(80))
+	if report != nil {
+		for _, undocumented := range report.Undocumented {
+			fmt.Println(undocumented)
+		}
+	}
+	if err != nil {
+		fmt.Printf("failed documentation coverage check: %v", err)
+	}
+}
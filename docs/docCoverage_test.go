@@ -0,0 +1,153 @@
+package docs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+const coverageSource = `package geo
+
+// Point is a location.
+type Point struct{ X, Y float64 }
+
+type Polygon []Point
+
+// Distance returns the distance between two points.
+func Distance(a, b Point) float64 { return 0 }
+
+func (p Point) String() string { return "" }
+
+func (p point) Hidden() {}
+
+type point struct{}
+
+// Units of length.
+const (
+	Meter = "m"
+	Foot  = "ft"
+)
+
+var Origin = Point{}
+
+var (
+	Scale = 1.0 // Scale is the map scale.
+	limit = 2
+)
+`
+
+func TestCheckDocCoverage(t *testing.T) {
+	newFs := func(t *testing.T) afero.Fs {
+		fs := afero.NewMemMapFs()
+		files := map[string]string{
+			"geo/geo.go":               coverageSource,
+			"geo/geo_test.go":          "package geo\n\nfunc Undocumented() {}\n",
+			"geo/testdata/skip.go":     "package skip\n\nfunc Undocumented() {}\n",
+			"ignored/ignored.go":       "package ignored\n\nfunc Undocumented() {}\n",
+			".docgenignore":            "ignored\n",
+			"documented/documented.go": "package documented\n\n// Do does it.\nfunc Do() {}\n",
+		}
+		for name, content := range files {
+			if err := afero.WriteFile(fs, name, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return fs
+	}
+
+	testCases := []struct {
+		name      string
+		dir       string
+		options   []docs.CoverageOption
+		wantTotal int
+		wantUndoc []string
+		wantErr   bool
+	}{
+		{
+			name:      "Reports undocumented identifiers",
+			dir:       ".",
+			wantTotal: 9,
+			wantUndoc: []string{
+				"geo/geo.go:6: type Polygon is undocumented",
+				"geo/geo.go:11: method Point.String is undocumented",
+				"geo/geo.go:23: var Origin is undocumented",
+			},
+		},
+		{
+			name:      "Coverage meets the minimum",
+			dir:       ".",
+			options:   []docs.CoverageOption{docs.WithMinCoverage(60)},
+			wantTotal: 9,
+			wantUndoc: []string{
+				"geo/geo.go:6: type Polygon is undocumented",
+				"geo/geo.go:11: method Point.String is undocumented",
+				"geo/geo.go:23: var Origin is undocumented",
+			},
+		},
+		{
+			name:      "Coverage below the minimum",
+			dir:       ".",
+			options:   []docs.CoverageOption{docs.WithMinCoverage(90)},
+			wantTotal: 9,
+			wantUndoc: []string{
+				"geo/geo.go:6: type Polygon is undocumented",
+				"geo/geo.go:11: method Point.String is undocumented",
+				"geo/geo.go:23: var Origin is undocumented",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Fully documented directory",
+			dir:       "documented",
+			options:   []docs.CoverageOption{docs.WithMinCoverage(100)},
+			wantTotal: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := docs.CheckDocCoverage(newFs(t), tc.dir, tc.options...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CheckDocCoverage() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if report == nil {
+				t.Fatal("CheckDocCoverage() returned a nil report")
+			}
+			if report.Total != tc.wantTotal {
+				t.Errorf("Total = %d, want %d", report.Total, tc.wantTotal)
+			}
+			if report.Documented != tc.wantTotal-len(tc.wantUndoc) {
+				t.Errorf("Documented = %d, want %d", report.Documented, tc.wantTotal-len(tc.wantUndoc))
+			}
+
+			var got []string
+			for _, undoc := range report.Undocumented {
+				got = append(got, undoc.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.wantUndoc, "\n") {
+				t.Errorf("Undocumented = %q, want %q", got, tc.wantUndoc)
+			}
+		})
+	}
+}
+
+func TestCoverageReportPercent(t *testing.T) {
+	testCases := []struct {
+		name   string
+		report docs.CoverageReport
+		want   float64
+	}{
+		{name: "Empty report", report: docs.CoverageReport{}, want: 100},
+		{name: "Partial coverage", report: docs.CoverageReport{Total: 4, Documented: 3}, want: 75},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.report.Percent(); got != tc.want {
+				t.Errorf("Percent() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -24,22 +24,37 @@ import (
 //
 // PackageName: The package name.
 // Functions:   A slice of FunctionDoc instances representing the functions.
+// Types:       The exported types, sorted by name.
+// Constants:   The exported constant declarations, in source order.
+// Variables:   The exported package-level variable declarations, in source
+// order.
+// Examples:    The package-level Example functions from the test files.
+// Changes:     The commits that touched the package since the last tag,
+// newest first. Only set when Repo.Changes is configured.
 // GoGetPath:   The 'go get' path for the package.
 type PackageDoc struct {
-	PackageName string
-	Functions   []FunctionDoc
-	GoGetPath   string
+	PackageName string        `json:"package_name"`
+	Functions   []FunctionDoc `json:"functions"`
+	Types       []TypeDoc     `json:"types"`
+	Constants   []ValueDoc    `json:"constants"`
+	Variables   []ValueDoc    `json:"variables"`
+	Examples    []ExampleDoc  `json:"examples,omitempty"`
+	Changes     []ChangeEntry `json:"changes,omitempty"`
+	GoGetPath   string        `json:"go_get_path"`
 }
 
 // Repo represents a GitHub repository.
 //
 // **Attributes:**
 //
-// Owner: The repository owner's name.
-// Name:  The repository's name.
+// Owner:   The repository owner's name.
+// Name:    The repository's name.
+// Changes: Optional settings that add the commits touching each package
+// to its documentation. Nil disables the "Recent changes" section.
 type Repo struct {
-	Owner string
-	Name  string
+	Owner   string
+	Name    string
+	Changes *ChangeOptions
 }
 
 // FunctionDoc contains the documentation for a function within a Go package.
@@ -50,12 +65,34 @@ type Repo struct {
 // Signature:   The function signature, including parameters and return types.
 // Description: The documentation or description of the function.
 // Params:      The function parameters.
+// StructName:  The struct name shown before the function name in templates.
+// Receiver:    The receiver type of a method, such as "*Client". Empty for
+// functions.
+// Parameters:  The function parameters, one entry per parameter.
+// Results:     The function results, one entry per result.
+// Examples:    The ExampleF or ExampleT_M functions from the package's test
+// files.
 type FunctionDoc struct {
-	Name        string
-	Signature   string
-	Description string
-	Params      string
-	StructName  string
+	Name        string       `json:"name"`
+	Signature   string       `json:"signature"`
+	Description string       `json:"description"`
+	Params      string       `json:"params,omitempty"`
+	StructName  string       `json:"struct_name,omitempty"`
+	Receiver    string       `json:"receiver,omitempty"`
+	Parameters  []ParamDoc   `json:"parameters"`
+	Results     []ParamDoc   `json:"results"`
+	Examples    []ExampleDoc `json:"examples,omitempty"`
+}
+
+// ParamDoc describes a single parameter or result of a function.
+//
+// **Attributes:**
+//
+// Name: The parameter name. Empty for unnamed parameters and results.
+// Type: The parameter type, such as "context.Context" or "...string".
+type ParamDoc struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
 }
 
 // FuncInfo holds information about an exported function within a Go package.
@@ -94,25 +131,122 @@ type FuncInfo struct {
 // file exists, walking the project directory, or generating the package
 // documentation.
 func CreatePackageDocs(fs afero.Fs, repo Repo, templatePath string, excludedPackages ...string) error {
+	return CreatePackageDocsWithFormat(fs, repo, templatePath, FormatMarkdown, excludedPackages...)
+}
+
+// CreatePackageDocsWithFormat generates package documentation for a Go
+// project in the specified output format. FormatMarkdown behaves like
+// CreatePackageDocs and writes a README.md for each package. FormatJSON
+// writes the extracted PackageDoc model to a docs.json file for each
+// package, so that it can feed a documentation website or API index.
+// FormatHTML writes a docs.html file for each package.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+//
+// repo: A Repo instance containing the Go project's repository details.
+//
+// templatePath: The path to the template file used to render each package.
+// Required for FormatMarkdown. For FormatHTML, the template is parsed with
+// html/template, and a built-in template is used if templatePath is empty.
+// Ignored for FormatJSON.
+//
+// format: The output format. An empty format is treated as FormatMarkdown.
+//
+// excludedPackages: Zero or more strings representing the names of packages
+// to be excluded from documentation generation.
+//
+// **Returns:**
+//
+// error: An error if the format is unknown, the template file doesn't
+// exist, or the package documentation can't be generated.
+func CreatePackageDocsWithFormat(fs afero.Fs, repo Repo, templatePath string, format OutputFormat, excludedPackages ...string) error {
+	return CreatePackageDocsWithOptions(fs, repo, DocOptions{
+		TemplatePath:     templatePath,
+		Format:           format,
+		ExcludedPackages: excludedPackages,
+	})
+}
+
+// DocOptions configures CreatePackageDocsWithOptions.
+//
+// **Attributes:**
+//
+// TemplatePath:     The path to the template file used to render each
+// package, as described for CreatePackageDocsWithFormat.
+// Format:           The output format. Empty means FormatMarkdown.
+// ExcludedPackages: The names of packages to exclude from documentation
+// generation.
+// Workers:          The number of packages documented concurrently.
+// Defaults to runtime.NumCPU().
+// CollectErrors:    Whether to document every package and return all
+// errors joined together. By default, no new packages are started after
+// the first error, which is returned alone.
+type DocOptions struct {
+	TemplatePath     string
+	Format           OutputFormat
+	ExcludedPackages []string
+	Workers          int
+	CollectErrors    bool
+}
+
+// CreatePackageDocsWithOptions generates package documentation for a Go
+// project like CreatePackageDocsWithFormat. Directories are walked first,
+// and the packages they contain are then parsed and rendered concurrently
+// by a bounded pool of workers, which speeds up large repositories.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+//
+// repo: A Repo instance containing the Go project's repository details.
+//
+// opts: DocOptions selecting the template, format, excluded packages,
+// concurrency, and error handling.
+//
+// **Returns:**
+//
+// error: An error if the format is unknown, the template file doesn't
+// exist, or the package documentation can't be generated. With
+// CollectErrors, the errors of every failed package are joined.
+func CreatePackageDocsWithOptions(fs afero.Fs, repo Repo, opts DocOptions) error {
+	format := opts.Format
+	if format == "" {
+		format = FormatMarkdown
+	}
+	if _, err := format.FileName(); err != nil {
+		return err
+	}
+
 	excludedPackagesMap := make(map[string]struct{})
-	for _, pkg := range excludedPackages {
+	for _, pkg := range opts.ExcludedPackages {
 		excludedPackagesMap[pkg] = struct{}{}
 	}
 
-	exists, err := afero.Exists(fs, templatePath)
-	if err != nil {
-		return fmt.Errorf("error checking if template file exists: %w", err)
+	if format == FormatMarkdown || (format == FormatHTML && opts.TemplatePath != "") {
+		exists, err := afero.Exists(fs, opts.TemplatePath)
+		if err != nil {
+			return fmt.Errorf("error checking if template file exists: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("template file does not exist")
+		}
 	}
-	if !exists {
-		return fmt.Errorf("template file does not exist")
+
+	changes, err := newChangeCollector(repo.Changes)
+	if err != nil {
+		return err
 	}
 
-	err = afero.Walk(fs, ".", handleDirectory(fs, repo, templatePath, excludedPackagesMap))
+	var dirs []string
+	err = afero.Walk(fs, ".", handleDirectory(fs, &dirs))
 	if err != nil {
 		return fmt.Errorf("error walking directories: %w", err)
 	}
 
-	return nil
+	cfg := docConfig{repo: repo, templatePath: opts.TemplatePath, format: format, excluded: excludedPackagesMap, changes: changes}
+	return processPackageDirs(fs, dirs, cfg, opts.Workers, opts.CollectErrors)
 }
 
 // generateReadmeFromTemplate generates a README.md file for a Go package using
@@ -232,7 +366,19 @@ func loadIgnoreList(fs afero.Fs, ignoreFilePath string) (map[string]struct{}, er
 	return ignoreList, nil
 }
 
-func handleDirectory(fs afero.Fs, repo Repo, templatePath string, excludedPackagesMap map[string]struct{}) filepath.WalkFunc {
+// docConfig holds the settings shared by every package that
+// CreatePackageDocsWithFormat documents.
+type docConfig struct {
+	repo         Repo
+	templatePath string
+	format       OutputFormat
+	excluded     map[string]struct{}
+	changes      *changeCollector
+}
+
+// handleDirectory returns a filepath.WalkFunc that appends every
+// directory containing Go files, and not listed in .docgenignore, to dirs.
+func handleDirectory(fs afero.Fs, dirs *[]string) filepath.WalkFunc {
 	return func(path string, info os.FileInfo, err error) error {
 		// General error handling
 		if err != nil {
@@ -267,8 +413,8 @@ func handleDirectory(fs afero.Fs, repo Repo, templatePath string, excludedPackag
 			return nil
 		}
 
-		// Process Go files in the directory
-		return processGoFiles(fs, path, repo, templatePath, excludedPackagesMap)
+		*dirs = append(*dirs, path)
+		return nil
 	}
 }
 
@@ -292,7 +438,7 @@ func directoryContainsGoFiles(fs afero.Fs, path string) (bool, error) {
 	return false, nil
 }
 
-func processGoFiles(fs afero.Fs, path string, repo Repo, tmplPath string, excludedPackagesMap map[string]struct{}) error {
+func processGoFiles(fs afero.Fs, path string, cfg docConfig) error {
 	fset := token.NewFileSet()
 
 	// Create a temporary directory
@@ -317,6 +463,26 @@ func processGoFiles(fs afero.Fs, path string, repo Repo, tmplPath string, exclud
 		return err
 	}
 
+	// Example functions live in the test files of both the package and
+	// its external _test package
+	testPkgs, err := parser.ParseDir(fset, tempDir, testFilter, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	testFileNames := []string{}
+	testFiles := map[string]*ast.File{}
+	for _, testPkg := range testPkgs {
+		for name, file := range testPkg.Files {
+			testFileNames = append(testFileNames, name)
+			testFiles[name] = file
+		}
+	}
+	sort.Strings(testFileNames)
+	sortedTestFiles := make([]*ast.File, 0, len(testFileNames))
+	for _, name := range testFileNames {
+		sortedTestFiles = append(sortedTestFiles, testFiles[name])
+	}
+
 	for _, pkg := range pkgs {
 		if filepath.Base(path) == "magefiles" && pkg.Name == "main" {
 			// treat magefiles as a separate package for documentation
@@ -324,10 +490,10 @@ func processGoFiles(fs afero.Fs, path string, repo Repo, tmplPath string, exclud
 		}
 
 		// check if the package name is in the excluded packages list
-		if _, exists := excludedPackagesMap[pkg.Name]; exists {
+		if _, exists := cfg.excluded[pkg.Name]; exists {
 			continue // if so, skip this package
 		}
-		if err := generateReadmeForPackage(fs, path, fset, pkg, repo, tmplPath); err != nil {
+		if err := generateDocsForPackage(fs, path, fset, pkg, sortedTestFiles, cfg); err != nil {
 			return err
 		}
 	}
@@ -339,15 +505,30 @@ func nonTestFilter(info os.FileInfo) bool {
 	return !strings.HasSuffix(info.Name(), "_test.go")
 }
 
-func generateReadmeForPackage(fs afero.Fs, path string, fset *token.FileSet, pkg *ast.Package, repo Repo, templatePath string) error {
+func testFilter(info os.FileInfo) bool {
+	return strings.HasSuffix(info.Name(), "_test.go")
+}
+
+func generateDocsForPackage(fs afero.Fs, path string, fset *token.FileSet, pkg *ast.Package, testFiles []*ast.File, cfg docConfig) error {
 	pkgDoc := &PackageDoc{
 		PackageName: pkg.Name,
-		GoGetPath:   fmt.Sprintf("github.com/%s/%s/%s", repo.Owner, repo.Name, pkg.Name),
+		GoGetPath:   fmt.Sprintf("github.com/%s/%s/%s", cfg.repo.Owner, cfg.repo.Name, pkg.Name),
 		Functions:   []FunctionDoc{},
+		Types:       []TypeDoc{},
+		Constants:   []ValueDoc{},
+		Variables:   []ValueDoc{},
+	}
+
+	// Process files in a stable order so constants and variables keep
+	// their source order across runs
+	fileNames := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		fileNames = append(fileNames, name)
 	}
+	sort.Strings(fileNames)
 
-	for _, file := range pkg.Files {
-		err := processFileDeclarations(fset, pkgDoc, file)
+	for _, name := range fileNames {
+		err := processFileDeclarations(fset, pkgDoc, pkg.Files[name])
 		if err != nil {
 			return err
 		}
@@ -358,12 +539,48 @@ func generateReadmeForPackage(fs afero.Fs, path string, fset *token.FileSet, pkg
 		return pkgDoc.Functions[i].Name < pkgDoc.Functions[j].Name
 	})
 
-	return generateReadmeFromTemplate(fs, pkgDoc, filepath.Join(path, "README.md"), templatePath)
+	// Sort type docs by type name
+	sort.Slice(pkgDoc.Types, func(i, j int) bool {
+		return pkgDoc.Types[i].Name < pkgDoc.Types[j].Name
+	})
+
+	if err := attachExamples(fset, pkgDoc, testFiles); err != nil {
+		return err
+	}
+
+	if cfg.changes != nil {
+		changes, err := cfg.changes.changes(path)
+		if err != nil {
+			return err
+		}
+		pkgDoc.Changes = changes
+	}
+
+	fileName, err := cfg.format.FileName()
+	if err != nil {
+		return err
+	}
+	outPath := filepath.Join(path, fileName)
 
+	switch cfg.format {
+	case FormatJSON:
+		return writeJSONDoc(fs, pkgDoc, outPath)
+	case FormatHTML:
+		return writeHTMLDoc(fs, pkgDoc, outPath, cfg.templatePath)
+	default:
+		return generateReadmeFromTemplate(fs, pkgDoc, outPath, cfg.templatePath)
+	}
 }
 
 func processFileDeclarations(fset *token.FileSet, pkgDoc *PackageDoc, file *ast.File) error {
 	for _, decl := range file.Decls {
+		if genDecl, isGen := decl.(*ast.GenDecl); isGen {
+			if err := processGenDecl(fset, pkgDoc, genDecl); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if fn, isFn := decl.(*ast.FuncDecl); isFn {
 			if !fn.Name.IsExported() || strings.HasPrefix(fn.Name.Name, "Test") {
 				continue
@@ -400,12 +617,26 @@ func createFunctionDoc(fset *token.FileSet, fn *ast.FuncDecl) (FunctionDoc, erro
 	}
 
 	// Extract receiver (struct) name
+	var receiver string
 	if fn.Recv != nil && len(fn.Recv.List) > 0 {
 		if se, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
 			structName = fmt.Sprintf("%s.", se.X)
 		} else {
 			structName = fmt.Sprintf("%s.", fn.Recv.List[0].Type)
 		}
+		receiver, err = formatNode(fset, fn.Recv.List[0].Type)
+		if err != nil {
+			return FunctionDoc{}, fmt.Errorf("error formatting function receiver: %w", err)
+		}
+	}
+
+	paramDocs, err := paramDocList(fset, fn.Type.Params)
+	if err != nil {
+		return FunctionDoc{}, fmt.Errorf("error formatting function parameters: %w", err)
+	}
+	resultDocs, err := paramDocList(fset, fn.Type.Results)
+	if err != nil {
+		return FunctionDoc{}, fmt.Errorf("error formatting function results: %w", err)
 	}
 
 	signature := fmt.Sprintf("%s(%s) %s", fn.Name.Name, params, results)
@@ -424,9 +655,37 @@ func createFunctionDoc(fset *token.FileSet, fn *ast.FuncDecl) (FunctionDoc, erro
 		Name:        funcName,
 		Signature:   signature,
 		Description: fn.Doc.Text(),
+		Receiver:    receiver,
+		Parameters:  paramDocs,
+		Results:     resultDocs,
 	}, nil
 }
 
+// paramDocList returns a ParamDoc for each name in the field list, or
+// for each field if its names are omitted.
+func paramDocList(fset *token.FileSet, fieldList *ast.FieldList) ([]ParamDoc, error) {
+	paramDocs := []ParamDoc{}
+	if fieldList == nil {
+		return paramDocs, nil
+	}
+
+	for _, field := range fieldList.List {
+		typ, err := formatNode(fset, field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if len(field.Names) == 0 {
+			paramDocs = append(paramDocs, ParamDoc{Type: typ})
+			continue
+		}
+		for _, name := range field.Names {
+			paramDocs = append(paramDocs, ParamDoc{Name: name.Name, Type: typ})
+		}
+	}
+
+	return paramDocs, nil
+}
+
 func splitLongSignature(signature string, maxLineLength int) string {
 	parts := strings.Split(signature, ",")
 	for i := 1; i < len(parts); i++ {
@@ -28,3 +28,17 @@ func ExampleCreatePackageDocs() {
 		fmt.Printf("failed to create package docs: %v", err)
 	}
 }
+
+func ExampleCreatePackageDocsWithFormat() {
+	fs := afero.NewMemMapFs()
+	repo := docs.Repo{
+		Owner: "l50",
+		Name:  "goutils",
+	}
+
+	// Write a docs.json file with the extracted documentation model for
+	// each package. JSON output doesn't need a template.
+	if err := docs.CreatePackageDocsWithFormat(fs, repo, "", docs.FormatJSON); err != nil {
+		fmt.Printf("failed to create package docs: %v", err)
+	}
+}
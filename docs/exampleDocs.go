@@ -0,0 +1,118 @@
+package docs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExampleDoc contains a runnable example extracted from an ExampleXxx
+// function in a package's test files.
+//
+// **Attributes:**
+//
+// Suffix: The example suffix, such as "withOptions" for
+// ExampleFoo_withOptions. Empty for the default example.
+// Code:   The body of the example function.
+// Output: The expected output from the example's "// Output:" comment.
+type ExampleDoc struct {
+	Suffix string `json:"suffix,omitempty"`
+	Code   string `json:"code"`
+	Output string `json:"output,omitempty"`
+}
+
+// attachExamples adds the examples in testFiles to the package, type, or
+// function they document, following the go test naming conventions:
+// Example for the package, ExampleF for function F, ExampleT for type T,
+// and ExampleT_M for method M of type T, each with an optional lowercase
+// suffix such as ExampleF_second.
+func attachExamples(fset *token.FileSet, pkgDoc *PackageDoc, testFiles []*ast.File) error {
+	if len(testFiles) == 0 {
+		return nil
+	}
+
+	functions := make(map[string]*FunctionDoc, len(pkgDoc.Functions))
+	for i := range pkgDoc.Functions {
+		functions[exampleKey(pkgDoc.Functions[i].Name)] = &pkgDoc.Functions[i]
+	}
+	types := make(map[string]*TypeDoc, len(pkgDoc.Types))
+	for i := range pkgDoc.Types {
+		types[pkgDoc.Types[i].Name] = &pkgDoc.Types[i]
+	}
+
+	for _, ex := range doc.Examples(testFiles...) {
+		exampleDoc, err := createExampleDoc(fset, ex)
+		if err != nil {
+			return err
+		}
+
+		base, suffix := splitExampleName(ex.Name)
+		exampleDoc.Suffix = suffix
+		switch {
+		case base == "":
+			pkgDoc.Examples = append(pkgDoc.Examples, exampleDoc)
+		case functions[base] != nil:
+			functions[base].Examples = append(functions[base].Examples, exampleDoc)
+		case types[base] != nil:
+			types[base].Examples = append(types[base].Examples, exampleDoc)
+		}
+	}
+
+	return nil
+}
+
+// exampleKey returns the example name of a FunctionDoc name, such as
+// "Client_Get" for "Client.Get(string)".
+func exampleKey(funcName string) string {
+	name, _, _ := strings.Cut(funcName, "(")
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+// splitExampleName splits an example name, without the Example prefix,
+// into the name of the documented identifier and the lowercase suffix.
+func splitExampleName(name string) (string, string) {
+	i := strings.LastIndex(name, "_")
+	if i < 0 {
+		return name, ""
+	}
+	r, _ := utf8.DecodeRuneInString(name[i+1:])
+	if !unicode.IsLower(r) {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
+func createExampleDoc(fset *token.FileSet, ex *doc.Example) (ExampleDoc, error) {
+	code, err := printNode(fset, &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments})
+	if err != nil {
+		return ExampleDoc{}, fmt.Errorf("error formatting example %s: %w", ex.Name, err)
+	}
+
+	// Unwrap the function body and drop the output comment, which is
+	// shown separately.
+	if _, ok := ex.Code.(*ast.BlockStmt); ok {
+		lines := strings.Split(code, "\n")
+		lines = lines[1 : len(lines)-1]
+		for i, line := range lines {
+			lines[i] = strings.TrimPrefix(line, "\t")
+		}
+		for i, line := range lines {
+			lower := strings.ToLower(strings.TrimSpace(line))
+			if strings.HasPrefix(lower, "// output:") || strings.HasPrefix(lower, "// unordered output:") {
+				lines = lines[:i]
+				break
+			}
+		}
+		code = strings.Join(lines, "\n")
+	}
+
+	return ExampleDoc{
+		Code:   strings.TrimSpace(code),
+		Output: strings.TrimSpace(ex.Output),
+	}, nil
+}
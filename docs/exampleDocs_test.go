@@ -0,0 +1,129 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+const greetSource = `package greet
+
+// Greeter greets people.
+type Greeter struct{}
+
+// Hello returns a greeting.
+func Hello(name string) string { return "hello " + name }
+
+// Greet prints a greeting.
+func (g *Greeter) Greet(name string) {}
+`
+
+const greetExamples = `package greet_test
+
+import (
+	"fmt"
+
+	"github.com/owner/name/greet"
+)
+
+func Example() {
+	fmt.Println("package example")
+}
+
+func ExampleHello() {
+	// Greet the world
+	fmt.Println(greet.Hello("world"))
+	// Output: hello world
+}
+
+func ExampleHello_twice() {
+	fmt.Println(greet.Hello("a"))
+	fmt.Println(greet.Hello("b"))
+	// Unordered output:
+	// hello b
+	// hello a
+}
+
+func ExampleGreeter() {
+	_ = &greet.Greeter{}
+}
+
+func ExampleGreeter_Greet() {
+	g := &greet.Greeter{}
+	g.Greet("world")
+}
+
+func ExampleMissing() {}
+`
+
+func TestCreatePackageDocsExamples(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "greet/greet.go", []byte(greetSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "greet/greet_examples_test.go", []byte(greetExamples), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.CreatePackageDocsWithFormat(fs, repo, "", docs.FormatJSON); err != nil {
+		t.Fatalf("CreatePackageDocsWithFormat() error = %v", err)
+	}
+
+	data, err := afero.ReadFile(fs, "greet/docs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pkgDoc docs.PackageDoc
+	if err := json.Unmarshal(data, &pkgDoc); err != nil {
+		t.Fatal(err)
+	}
+
+	functions := map[string]docs.FunctionDoc{}
+	for _, fn := range pkgDoc.Functions {
+		functions[fn.Name] = fn
+	}
+
+	testCases := []struct {
+		name string
+		got  []docs.ExampleDoc
+		want []docs.ExampleDoc
+	}{
+		{
+			name: "package",
+			got:  pkgDoc.Examples,
+			want: []docs.ExampleDoc{{Code: `fmt.Println("package example")`}},
+		},
+		{
+			name: "function",
+			got:  functions["Hello(string)"].Examples,
+			want: []docs.ExampleDoc{
+				{Code: "// Greet the world\nfmt.Println(greet.Hello(\"world\"))", Output: "hello world"},
+				{Suffix: "twice", Code: "fmt.Println(greet.Hello(\"a\"))\nfmt.Println(greet.Hello(\"b\"))", Output: "hello b\nhello a"},
+			},
+		},
+		{
+			name: "method",
+			got:  functions["Greeter.Greet(string)"].Examples,
+			want: []docs.ExampleDoc{{Code: "g := &greet.Greeter{}\ng.Greet(\"world\")"}},
+		},
+		{
+			name: "type",
+			got:  pkgDoc.Types[0].Examples,
+			want: []docs.ExampleDoc{{Code: "_ = &greet.Greeter{}"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.got) != len(tc.want) {
+				t.Fatalf("examples = %+v, want %+v", tc.got, tc.want)
+			}
+			for i := range tc.want {
+				if tc.got[i] != tc.want[i] {
+					t.Errorf("example %d = %+v, want %+v", i, tc.got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+
+	"github.com/spf13/afero"
+)
+
+// OutputFormat is the format of the documentation that
+// CreatePackageDocsWithFormat writes for each package.
+type OutputFormat string
+
+const (
+	// FormatMarkdown renders a README.md from a text template.
+	FormatMarkdown OutputFormat = "markdown"
+	// FormatJSON writes the PackageDoc model to docs.json.
+	FormatJSON OutputFormat = "json"
+	// FormatHTML renders docs.html from an HTML template.
+	FormatHTML OutputFormat = "html"
+)
+
+// FileName returns the name of the file written to each package
+// directory for the output format.
+//
+// **Returns:**
+//
+// string: The file name, such as "README.md".
+// error: An error if the output format is unknown.
+func (f OutputFormat) FileName() (string, error) {
+	switch f {
+	case FormatMarkdown:
+		return "README.md", nil
+	case FormatJSON:
+		return "docs.json", nil
+	case FormatHTML:
+		return "docs.html", nil
+	default:
+		return "", fmt.Errorf("unknown output format %q", string(f))
+	}
+}
+
+// defaultHTMLTemplate renders a package when FormatHTML is used without a
+// template file.
+const defaultHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.PackageName}}</title>
+</head>
+<body>
+<h1>{{.PackageName}}</h1>
+<pre><code>go get {{.GoGetPath}}</code></pre>
+<h2>Functions</h2>
+{{range .Functions}}<section id="{{.Name}}">
+<h3>{{.Name}}</h3>
+<pre><code>{{.Signature}}</code></pre>
+<pre>{{.Description}}</pre>
+</section>
+{{end}}{{if .Changes}}<h2>Recent changes</h2>
+<ul>
+{{range .Changes}}<li><code>{{.Hash}}</code> {{.Subject}} ({{.Author}}, {{.Date.Format "2006-01-02"}})</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`
+
+// writeJSONDoc writes pkgDoc as indented JSON to path.
+func writeJSONDoc(fs afero.Fs, pkgDoc *PackageDoc, path string) error {
+	data, err := json.MarshalIndent(pkgDoc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding package docs: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeHTMLDoc renders pkgDoc to path with the HTML template at
+// templatePath, or with defaultHTMLTemplate if templatePath is empty.
+func writeHTMLDoc(fs afero.Fs, pkgDoc *PackageDoc, path string, templatePath string) error {
+	tmplText := defaultHTMLTemplate
+	if templatePath != "" {
+		templateBytes, err := afero.ReadFile(fs, templatePath)
+		if err != nil {
+			return fmt.Errorf("error reading template file: %w", err)
+		}
+		tmplText = string(templateBytes)
+	}
+
+	tmpl, err := template.New("").Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pkgDoc); err != nil {
+		return fmt.Errorf("error rendering template: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+const sampleSource = `package sample
+
+import "context"
+
+// Client talks to the sample API.
+type Client struct{}
+
+// Fetch returns the items named <name>.
+func (c *Client) Fetch(ctx context.Context, name, kind string, opts ...string) ([]string, error) {
+	return nil, nil
+}
+
+// Version returns the version.
+func Version() string {
+	return ""
+}
+`
+
+func newSampleFs(t *testing.T) afero.Fs {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "sample/sample.go", []byte(sampleSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestCreatePackageDocsWithFormatJSON(t *testing.T) {
+	fs := newSampleFs(t)
+	if err := docs.CreatePackageDocsWithFormat(fs, repo, "", docs.FormatJSON); err != nil {
+		t.Fatalf("CreatePackageDocsWithFormat() error = %v", err)
+	}
+
+	data, err := afero.ReadFile(fs, "sample/docs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pkgDoc docs.PackageDoc
+	if err := json.Unmarshal(data, &pkgDoc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if pkgDoc.PackageName != "sample" || pkgDoc.GoGetPath != "github.com/owner/name/sample" {
+		t.Errorf("unexpected package fields: %+v", pkgDoc)
+	}
+	if len(pkgDoc.Functions) != 2 {
+		t.Fatalf("expected 2 functions, got %d", len(pkgDoc.Functions))
+	}
+
+	fetch := pkgDoc.Functions[0]
+	if fetch.Receiver != "*Client" {
+		t.Errorf("Receiver = %q, want %q", fetch.Receiver, "*Client")
+	}
+	wantParams := []docs.ParamDoc{
+		{Name: "ctx", Type: "context.Context"},
+		{Name: "name", Type: "string"},
+		{Name: "kind", Type: "string"},
+		{Name: "opts", Type: "...string"},
+	}
+	if len(fetch.Parameters) != len(wantParams) {
+		t.Fatalf("Parameters = %+v, want %+v", fetch.Parameters, wantParams)
+	}
+	for i, p := range wantParams {
+		if fetch.Parameters[i] != p {
+			t.Errorf("Parameters[%d] = %+v, want %+v", i, fetch.Parameters[i], p)
+		}
+	}
+	if len(fetch.Results) != 2 || fetch.Results[0].Type != "[]string" || fetch.Results[1].Type != "error" {
+		t.Errorf("unexpected results: %+v", fetch.Results)
+	}
+
+	version := pkgDoc.Functions[1]
+	if version.Receiver != "" || len(version.Parameters) != 0 || len(version.Results) != 1 {
+		t.Errorf("unexpected function doc: %+v", version)
+	}
+	if !strings.Contains(string(data), `"parameters": []`) {
+		t.Error("expected empty parameter lists to be encoded as []")
+	}
+}
+
+func TestCreatePackageDocsWithFormatHTML(t *testing.T) {
+	testCases := []struct {
+		name         string
+		templatePath string
+		template     string
+		wantContains []string
+		expectErr    bool
+	}{
+		{
+			name: "built-in template",
+			wantContains: []string{
+				"<h1>sample</h1>",
+				"Fetch returns the items named &lt;name&gt;.",
+				"go get github.com/owner/name/sample",
+			},
+		},
+		{
+			name:         "custom template",
+			templatePath: "templates/docs.html.tmpl",
+			template:     `<ul>{{range .Functions}}<li>{{.Name}} {{.Receiver}}</li>{{end}}</ul>`,
+			wantContains: []string{"<li>Version() </li>", "*Client"},
+		},
+		{
+			name:         "missing template",
+			templatePath: "templates/missing.tmpl",
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := newSampleFs(t)
+			if tc.template != "" {
+				if err := afero.WriteFile(fs, tc.templatePath, []byte(tc.template), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := docs.CreatePackageDocsWithFormat(fs, repo, tc.templatePath, docs.FormatHTML)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("CreatePackageDocsWithFormat() error = %v, expectErr %v", err, tc.expectErr)
+			}
+			if tc.expectErr {
+				return
+			}
+
+			content, err := afero.ReadFile(fs, "sample/docs.html")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tc.wantContains {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("docs.html missing %q:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePackageDocsWithFormatUnknown(t *testing.T) {
+	err := docs.CreatePackageDocsWithFormat(newSampleFs(t), repo, "", docs.OutputFormat("pdf"))
+	if err == nil {
+		t.Error("expected an error for an unknown output format")
+	}
+}
@@ -0,0 +1,268 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+)
+
+// TypeDoc contains the documentation for an exported type within a Go
+// package.
+//
+// **Attributes:**
+//
+// Name:        The type name.
+// Kind:        "struct", "interface", "alias", or "type" for any other
+// defined type.
+// Declaration: The type declaration. Unexported fields and methods are
+// replaced with a comment, like in go doc.
+// Description: The documentation of the type, including its attribute
+// doc block.
+// Fields:      The exported fields of a struct or the exported methods of
+// an interface.
+// Examples:    The ExampleT functions from the package's test files.
+type TypeDoc struct {
+	Name        string       `json:"name"`
+	Kind        string       `json:"kind"`
+	Declaration string       `json:"declaration"`
+	Description string       `json:"description"`
+	Fields      []FieldDoc   `json:"fields,omitempty"`
+	Examples    []ExampleDoc `json:"examples,omitempty"`
+}
+
+// ValueDoc contains the documentation for a group of exported constants
+// or package-level variables declared together.
+//
+// **Attributes:**
+//
+// Declaration: The const or var declaration.
+// Description: The documentation of the declaration group.
+// Values:      The exported constants or variables in the group.
+type ValueDoc struct {
+	Declaration string     `json:"declaration"`
+	Description string     `json:"description"`
+	Values      []FieldDoc `json:"values"`
+}
+
+// FieldDoc describes a struct field, an interface method, or a constant
+// or variable within a ValueDoc.
+//
+// **Attributes:**
+//
+// Name:        The name of the field, method, constant, or variable. For
+// embedded fields, the name of the embedded type.
+// Type:        The type, or the method signature for interface methods.
+// Empty for constants and variables without an explicit type.
+// Description: The documentation of the field, method, constant, or
+// variable.
+type FieldDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// processGenDecl adds the exported types, constants, and variables of a
+// general declaration to pkgDoc.
+func processGenDecl(fset *token.FileSet, pkgDoc *PackageDoc, decl *ast.GenDecl) error {
+	switch decl.Tok {
+	case token.TYPE:
+		for _, spec := range decl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if !typeSpec.Name.IsExported() {
+				continue
+			}
+			typeDoc, err := createTypeDoc(fset, decl, typeSpec)
+			if err != nil {
+				return err
+			}
+			pkgDoc.Types = append(pkgDoc.Types, typeDoc)
+		}
+	case token.CONST, token.VAR:
+		valueDoc, ok, err := createValueDoc(fset, decl)
+		if err != nil || !ok {
+			return err
+		}
+		if decl.Tok == token.CONST {
+			pkgDoc.Constants = append(pkgDoc.Constants, valueDoc)
+		} else {
+			pkgDoc.Variables = append(pkgDoc.Variables, valueDoc)
+		}
+	}
+
+	return nil
+}
+
+func createTypeDoc(fset *token.FileSet, decl *ast.GenDecl, spec *ast.TypeSpec) (TypeDoc, error) {
+	typeDoc := TypeDoc{Name: spec.Name.Name, Kind: "type"}
+
+	// An unparenthesized declaration has its doc comment on the GenDecl.
+	doc := spec.Doc
+	if doc == nil && !decl.Lparen.IsValid() {
+		doc = decl.Doc
+	}
+	typeDoc.Description = doc.Text()
+
+	var fields *ast.FieldList
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		typeDoc.Kind = "struct"
+		fields = t.Fields
+	case *ast.InterfaceType:
+		typeDoc.Kind = "interface"
+		fields = t.Methods
+	}
+	if spec.Assign.IsValid() {
+		typeDoc.Kind = "alias"
+	}
+
+	var exported []*ast.Field
+	if fields != nil {
+		for _, field := range fields.List {
+			fieldDocs, err := createFieldDocs(fset, field)
+			if err != nil {
+				return TypeDoc{}, err
+			}
+			if len(fieldDocs) > 0 {
+				exported = append(exported, field)
+				typeDoc.Fields = append(typeDoc.Fields, fieldDocs...)
+			}
+		}
+	}
+
+	// The doc comment is part of the description. The printer keeps the
+	// comments attached to the remaining fields and methods.
+	filtered := *spec
+	filtered.Doc, filtered.Comment = nil, nil
+	if fields != nil && len(exported) < len(fields.List) {
+		switch t := spec.Type.(type) {
+		case *ast.StructType:
+			st := *t
+			st.Fields = &ast.FieldList{Opening: t.Fields.Opening, List: exported, Closing: t.Fields.Closing}
+			st.Incomplete = true
+			filtered.Type = &st
+		case *ast.InterfaceType:
+			it := *t
+			it.Methods = &ast.FieldList{Opening: t.Methods.Opening, List: exported, Closing: t.Methods.Closing}
+			it.Incomplete = true
+			filtered.Type = &it
+		}
+	}
+	declaration, err := printNode(fset, &filtered)
+	if err != nil {
+		return TypeDoc{}, fmt.Errorf("error formatting type %s: %w", spec.Name.Name, err)
+	}
+	typeDoc.Declaration = "type " + declaration
+
+	return typeDoc, nil
+}
+
+// createFieldDocs returns a FieldDoc for each exported name of a struct
+// field or interface method, or for an exported embedded type.
+func createFieldDocs(fset *token.FileSet, field *ast.Field) ([]FieldDoc, error) {
+	typ, err := printNode(fset, field.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting field type: %w", err)
+	}
+
+	description := field.Doc.Text()
+	if description == "" {
+		description = field.Comment.Text()
+	}
+
+	if len(field.Names) == 0 {
+		name := embeddedTypeName(field.Type)
+		if !ast.IsExported(name) {
+			return nil, nil
+		}
+		return []FieldDoc{{Name: name, Type: typ, Description: description}}, nil
+	}
+
+	var fieldDocs []FieldDoc
+	for _, name := range field.Names {
+		if name.IsExported() {
+			fieldDocs = append(fieldDocs, FieldDoc{Name: name.Name, Type: typ, Description: description})
+		}
+	}
+	return fieldDocs, nil
+}
+
+// embeddedTypeName returns the name of an embedded type, such as "Mutex"
+// for "*sync.Mutex".
+func embeddedTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedTypeName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
+
+// createValueDoc returns the documentation for the exported constants or
+// variables of a declaration, or false if it has none.
+func createValueDoc(fset *token.FileSet, decl *ast.GenDecl) (ValueDoc, bool, error) {
+	valueDoc := ValueDoc{Description: decl.Doc.Text(), Values: []FieldDoc{}}
+
+	var exported []ast.Spec
+	for _, spec := range decl.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		var typ string
+		if valueSpec.Type != nil {
+			var err error
+			if typ, err = printNode(fset, valueSpec.Type); err != nil {
+				return ValueDoc{}, false, fmt.Errorf("error formatting value type: %w", err)
+			}
+		}
+		description := valueSpec.Doc.Text()
+		if description == "" {
+			description = valueSpec.Comment.Text()
+		}
+
+		isExported := false
+		for _, name := range valueSpec.Names {
+			if name.IsExported() {
+				isExported = true
+				valueDoc.Values = append(valueDoc.Values, FieldDoc{Name: name.Name, Type: typ, Description: description})
+			}
+		}
+		if isExported {
+			exported = append(exported, spec)
+		}
+	}
+	if len(exported) == 0 {
+		return ValueDoc{}, false, nil
+	}
+
+	// The doc comment is part of the description. The printer keeps the
+	// comments attached to the remaining values.
+	filtered := *decl
+	filtered.Doc = nil
+	filtered.Specs = exported
+
+	declaration, err := printNode(fset, &filtered)
+	if err != nil {
+		return ValueDoc{}, false, fmt.Errorf("error formatting %s declaration: %w", decl.Tok, err)
+	}
+	valueDoc.Declaration = declaration
+
+	return valueDoc, true, nil
+}
+
+// printNode formats node like gofmt, aligning struct fields and values.
+func printNode(fset *token.FileSet, node interface{}) (string, error) {
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	var buf bytes.Buffer
+	if err := cfg.Fprint(&buf, fset, node); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
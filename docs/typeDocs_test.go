@@ -0,0 +1,158 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+const typesSource = `package shapes
+
+import "sync"
+
+// Shape is implemented by every shape.
+type Shape interface {
+	// Area returns the area of the shape.
+	Area() float64
+	perimeter() float64
+}
+
+// Square is a shape with four equal sides.
+//
+// **Attributes:**
+//
+// Side: The length of each side.
+type Square struct {
+	*sync.Mutex
+	Side float64 // Side is in meters.
+	// cache holds computed values.
+	cache map[string]float64
+}
+
+type (
+	// Unit is a unit of length.
+	Unit string
+	// Length is an alias kept for compatibility.
+	Length = float64
+	internal int
+)
+
+// Units of length.
+const (
+	// Meter is the default unit.
+	Meter Unit = "m"
+	Foot  Unit = "ft"
+	// scale is not exported.
+	scale = 2
+)
+
+const hidden = 1
+
+// DefaultUnit is used when no unit is given.
+var DefaultUnit = Meter
+`
+
+func TestCreatePackageDocsTypes(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "shapes/shapes.go", []byte(typesSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.CreatePackageDocsWithFormat(fs, repo, "", docs.FormatJSON); err != nil {
+		t.Fatalf("CreatePackageDocsWithFormat() error = %v", err)
+	}
+
+	data, err := afero.ReadFile(fs, "shapes/docs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pkgDoc docs.PackageDoc
+	if err := json.Unmarshal(data, &pkgDoc); err != nil {
+		t.Fatal(err)
+	}
+
+	wantTypes := []struct {
+		name        string
+		kind        string
+		declaration string
+		description string
+		fields      []docs.FieldDoc
+	}{
+		{
+			name:        "Length",
+			kind:        "alias",
+			declaration: "type Length = float64",
+			description: "Length is an alias kept for compatibility.\n",
+		},
+		{
+			name:        "Shape",
+			kind:        "interface",
+			declaration: "type Shape interface {\n\t// Area returns the area of the shape.\n\tArea() float64\n\t// contains filtered or unexported methods\n}",
+			description: "Shape is implemented by every shape.\n",
+			fields:      []docs.FieldDoc{{Name: "Area", Type: "func() float64", Description: "Area returns the area of the shape.\n"}},
+		},
+		{
+			name:        "Square",
+			kind:        "struct",
+			declaration: "type Square struct {\n\t*sync.Mutex\n\tSide float64 // Side is in meters.\n\t// contains filtered or unexported fields\n}",
+			description: "Square is a shape with four equal sides.\n\n**Attributes:**\n\nSide: The length of each side.\n",
+			fields: []docs.FieldDoc{
+				{Name: "Mutex", Type: "*sync.Mutex"},
+				{Name: "Side", Type: "float64", Description: "Side is in meters.\n"},
+			},
+		},
+		{
+			name:        "Unit",
+			kind:        "type",
+			declaration: "type Unit string",
+			description: "Unit is a unit of length.\n",
+		},
+	}
+
+	if len(pkgDoc.Types) != len(wantTypes) {
+		t.Fatalf("got %d types, want %d: %+v", len(pkgDoc.Types), len(wantTypes), pkgDoc.Types)
+	}
+	for i, want := range wantTypes {
+		got := pkgDoc.Types[i]
+		if got.Name != want.name || got.Kind != want.kind {
+			t.Errorf("type %d = %s (%s), want %s (%s)", i, got.Name, got.Kind, want.name, want.kind)
+		}
+		if got.Declaration != want.declaration {
+			t.Errorf("%s declaration = %q, want %q", want.name, got.Declaration, want.declaration)
+		}
+		if got.Description != want.description {
+			t.Errorf("%s description = %q, want %q", want.name, got.Description, want.description)
+		}
+		if len(got.Fields) != len(want.fields) {
+			t.Errorf("%s fields = %+v, want %+v", want.name, got.Fields, want.fields)
+			continue
+		}
+		for j := range want.fields {
+			if got.Fields[j] != want.fields[j] {
+				t.Errorf("%s field %d = %+v, want %+v", want.name, j, got.Fields[j], want.fields[j])
+			}
+		}
+	}
+
+	if len(pkgDoc.Constants) != 1 {
+		t.Fatalf("got %d constant groups, want 1: %+v", len(pkgDoc.Constants), pkgDoc.Constants)
+	}
+	constants := pkgDoc.Constants[0]
+	wantConst := "const (\n\t// Meter is the default unit.\n\tMeter Unit = \"m\"\n\tFoot  Unit = \"ft\"\n)"
+	if constants.Declaration != wantConst {
+		t.Errorf("constant declaration = %q, want %q", constants.Declaration, wantConst)
+	}
+	if constants.Description != "Units of length.\n" {
+		t.Errorf("constant description = %q", constants.Description)
+	}
+	if len(constants.Values) != 2 || constants.Values[0].Name != "Meter" || constants.Values[0].Type != "Unit" ||
+		constants.Values[0].Description != "Meter is the default unit.\n" || constants.Values[1].Name != "Foot" {
+		t.Errorf("unexpected constant values: %+v", constants.Values)
+	}
+
+	if len(pkgDoc.Variables) != 1 || pkgDoc.Variables[0].Declaration != "var DefaultUnit = Meter" ||
+		pkgDoc.Variables[0].Description != "DefaultUnit is used when no unit is given.\n" {
+		t.Errorf("unexpected variables: %+v", pkgDoc.Variables)
+	}
+}
@@ -0,0 +1,63 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/afero"
+)
+
+// processPackageDirs documents the packages in dirs with a pool of
+// workers. Unless collectErrors is set, no new directories are started
+// after the first failure, and only the error of the first failed
+// directory, in walk order, is returned.
+func processPackageDirs(fs afero.Fs, dirs []string, cfg docConfig, workers int, collectErrors bool) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(dirs) {
+		workers = len(dirs)
+	}
+
+	// Errors are stored by directory index so the result doesn't depend
+	// on scheduling.
+	errs := make([]error, len(dirs))
+	var failed atomic.Bool
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				if err := processGoFiles(fs, dirs[idx], cfg); err != nil {
+					errs[idx] = fmt.Errorf("error documenting %s: %w", dirs[idx], err)
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	for idx := range dirs {
+		if !collectErrors && failed.Load() {
+			break
+		}
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	if collectErrors {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package docs_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/docs"
+	"github.com/spf13/afero"
+)
+
+func TestCreatePackageDocsWithOptions(t *testing.T) {
+	newFs := func(t *testing.T, broken ...string) afero.Fs {
+		fs := afero.NewMemMapFs()
+		for i := 0; i < 20; i++ {
+			name := fmt.Sprintf("pkg%02d", i)
+			src := fmt.Sprintf("package %s\n\n// F does nothing.\nfunc F() {}\n", name)
+			if err := afero.WriteFile(fs, name+"/"+name+".go", []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, name := range broken {
+			if err := afero.WriteFile(fs, name+"/broken.go", []byte("package "+name+"\n\nfunc {\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return fs
+	}
+
+	testCases := []struct {
+		name       string
+		broken     []string
+		opts       docs.DocOptions
+		wantErrs   []string
+		wantAllDoc bool
+	}{
+		{
+			name:       "Concurrent workers",
+			opts:       docs.DocOptions{Format: docs.FormatJSON, Workers: 4},
+			wantAllDoc: true,
+		},
+		{
+			name:       "Default workers",
+			opts:       docs.DocOptions{Format: docs.FormatJSON},
+			wantAllDoc: true,
+		},
+		{
+			name:     "Fail fast returns the first error",
+			broken:   []string{"pkg03", "pkg17"},
+			opts:     docs.DocOptions{Format: docs.FormatJSON, Workers: 1},
+			wantErrs: []string{"pkg03"},
+		},
+		{
+			name:     "Collect errors from every package",
+			broken:   []string{"pkg03", "pkg17"},
+			opts:     docs.DocOptions{Format: docs.FormatJSON, Workers: 4, CollectErrors: true},
+			wantErrs: []string{"pkg03", "pkg17"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := newFs(t, tc.broken...)
+			err := docs.CreatePackageDocsWithOptions(fs, repo, tc.opts)
+
+			if len(tc.wantErrs) == 0 && err != nil {
+				t.Fatalf("CreatePackageDocsWithOptions() error = %v", err)
+			}
+			if len(tc.wantErrs) > 0 {
+				if err == nil {
+					t.Fatal("CreatePackageDocsWithOptions() expected an error")
+				}
+				for _, want := range tc.wantErrs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error = %v, want it to mention %s", err, want)
+					}
+				}
+				if len(tc.wantErrs) == 1 && strings.Contains(err.Error(), "pkg17") {
+					t.Errorf("error = %v, want only the first failure", err)
+				}
+			}
+
+			for i := 0; i < 20; i++ {
+				name := fmt.Sprintf("pkg%02d", i)
+				exists, _ := afero.Exists(fs, name+"/docs.json")
+				isBroken := false
+				for _, b := range tc.broken {
+					isBroken = isBroken || b == name
+				}
+				if tc.wantAllDoc && !exists {
+					t.Errorf("%s/docs.json was not written", name)
+				}
+				if isBroken && exists {
+					t.Errorf("%s/docs.json was written for a broken package", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package file
+
+// DataStream describes an NTFS alternate data stream.
+//
+// **Attributes:**
+//
+// Name: The stream name without the ":$DATA" type suffix (e.g.,
+// "Zone.Identifier"). The stream can be read by opening "<path>:<Name>".
+// Size: The size of the stream in bytes.
+type DataStream struct {
+	Name string
+	Size int64
+}
+
+// ListADS returns the alternate data streams of a file on an NTFS
+// volume. The unnamed default stream is not included. Alternate data
+// streams only exist on Windows; use ListXattr on other platforms.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// []DataStream: The alternate data streams of the file.
+// error: An error if the streams can't be enumerated or the platform
+// isn't Windows.
+func ListADS(path string) ([]DataStream, error) {
+	return listADS(path)
+}
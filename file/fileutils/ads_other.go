@@ -0,0 +1,12 @@
+//go:build !windows
+
+package file
+
+import (
+	"errors"
+	"runtime"
+)
+
+func listADS(path string) ([]DataStream, error) {
+	return nil, errors.New("alternate data streams are not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modkernel32          = windows.NewLazySystemDLL("kernel32.dll")
+	procFindFirstStreamW = modkernel32.NewProc("FindFirstStreamW")
+	procFindNextStreamW  = modkernel32.NewProc("FindNextStreamW")
+)
+
+// win32FindStreamData mirrors WIN32_FIND_STREAM_DATA.
+type win32FindStreamData struct {
+	StreamSize int64
+	StreamName [windows.MAX_PATH + 36]uint16
+}
+
+func listADS(path string) ([]DataStream, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data win32FindStreamData
+	// FindStreamInfoStandard is the only defined info level (0).
+	handle, _, callErr := procFindFirstStreamW.Call(uintptr(unsafe.Pointer(pathPtr)), 0, uintptr(unsafe.Pointer(&data)), 0)
+	if windows.Handle(handle) == windows.InvalidHandle {
+		if errors.Is(callErr, windows.ERROR_HANDLE_EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to enumerate streams of %s: %w", path, callErr)
+	}
+	defer windows.FindClose(windows.Handle(handle))
+
+	var streams []DataStream
+	for {
+		name := strings.TrimSuffix(windows.UTF16ToString(data.StreamName[:]), ":$DATA")
+		name = strings.TrimPrefix(name, ":")
+		if name != "" {
+			streams = append(streams, DataStream{Name: name, Size: data.StreamSize})
+		}
+
+		ok, _, callErr := procFindNextStreamW.Call(handle, uintptr(unsafe.Pointer(&data)))
+		if ok == 0 {
+			if errors.Is(callErr, windows.ERROR_HANDLE_EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to enumerate streams of %s: %w", path, callErr)
+		}
+	}
+
+	return streams, nil
+}
@@ -0,0 +1,97 @@
+package file
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// HashAlgorithm represents a hash algorithm supported by Checksum.
+type HashAlgorithm string
+
+const (
+	// SHA256 represents the SHA-256 hash algorithm.
+	SHA256 HashAlgorithm = "sha256"
+	// SHA512 represents the SHA-512 hash algorithm.
+	SHA512 HashAlgorithm = "sha512"
+	// MD5 represents the MD5 hash algorithm. It should only be used to
+	// verify artifacts that are not published with a stronger digest.
+	MD5 HashAlgorithm = "md5"
+)
+
+// Checksum computes the hex-encoded digest of a file. The file is
+// streamed through the hash, so large artifacts are never loaded into
+// memory in full.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// algorithm: HashAlgorithm used to compute the digest.
+//
+// **Returns:**
+//
+// string: The lowercase hex-encoded digest of the file.
+// error: An error if the algorithm is unsupported or the file can't be read.
+func Checksum(path string, algorithm HashAlgorithm) (string, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyChecksum checks that the digest of a file matches an expected
+// value. The comparison ignores case and surrounding whitespace.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// expected: The expected hex-encoded digest.
+// algorithm: HashAlgorithm used to compute the digest.
+//
+// **Returns:**
+//
+// error: An error if the checksum can't be computed or doesn't match.
+func VerifyChecksum(path, expected string, algorithm HashAlgorithm) error {
+	actual, err := Checksum(path, algorithm)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("%s checksum mismatch for %s: expected %s, got %s",
+			algorithm, path, strings.TrimSpace(expected), actual)
+	}
+
+	return nil
+}
+
+func newHash(algorithm HashAlgorithm) (hash.Hash, error) {
+	switch HashAlgorithm(strings.ToLower(string(algorithm))) {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case MD5:
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+}
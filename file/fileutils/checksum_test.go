@@ -0,0 +1,103 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+func TestChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact")
+	if err := os.WriteFile(path, []byte("hello world\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		path      string
+		algorithm fileutils.HashAlgorithm
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "SHA256",
+			path:      path,
+			algorithm: fileutils.SHA256,
+			want:      "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447",
+		},
+		{
+			name:      "SHA512",
+			path:      path,
+			algorithm: fileutils.SHA512,
+			want:      "db3974a97f2407b7cae1ae637c0030687a11913274d578492558e39c16c017de84eacdc8c62fe34ee4e12b4b1428817f09b6a2760c3f8a664ceae94d2434a593",
+		},
+		{
+			name:      "MD5 with uppercase algorithm name",
+			path:      path,
+			algorithm: "MD5",
+			want:      "6f5902ac237024bdd0c176cb93063dc4",
+		},
+		{
+			name:      "Unsupported algorithm",
+			path:      path,
+			algorithm: "crc32",
+			wantErr:   true,
+		},
+		{
+			name:      "Missing file",
+			path:      filepath.Join(t.TempDir(), "missing"),
+			algorithm: fileutils.SHA256,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fileutils.Checksum(tc.path, tc.algorithm)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Checksum() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Checksum() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact")
+	if err := os.WriteFile(path, []byte("hello world\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Matching checksum",
+			expected: "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447",
+		},
+		{
+			name:     "Matching checksum with different case and whitespace",
+			expected: " A948904F2F0F479B8F8197694B30184B0D2ED1C1CD2A1EC0FB85D299A192A447\n",
+		},
+		{
+			name:     "Mismatched checksum",
+			expected: "0000000000000000000000000000000000000000000000000000000000000000",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := fileutils.VerifyChecksum(path, tc.expected, fileutils.SHA256)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("VerifyChecksum() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
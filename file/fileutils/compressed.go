@@ -0,0 +1,223 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/afero"
+	"github.com/ulikunitz/xz"
+)
+
+// Magic bytes identifying the supported compression formats.
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// compressedReader closes the decompressor and the underlying file.
+type compressedReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressor, if it needs closing, and the file.
+func (r *compressedReader) Close() error {
+	var errs []error
+	for _, c := range r.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// OpenMaybeCompressed opens a file for reading, transparently
+// decompressing it if it's compressed with gzip, bzip2, xz, or zstd. The
+// format is detected from the file's magic bytes rather than its
+// extension, so rotated logs such as "app.log.1" are handled too. Files
+// in any other format are read as they are.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// io.ReadCloser: A reader returning the decompressed content. Closing it
+// closes the file.
+// error: An error if the file can't be opened or its compressed header
+// is invalid.
+func OpenMaybeCompressed(path string) (io.ReadCloser, error) {
+	return OpenMaybeCompressedFs(osFs, path)
+}
+
+// OpenMaybeCompressedFs is like OpenMaybeCompressed, but operates on the
+// input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// io.ReadCloser: A reader returning the decompressed content. Closing it
+// closes the file.
+// error: An error if the file can't be opened or its compressed header
+// is invalid.
+func OpenMaybeCompressedFs(fs afero.Fs, path string) (io.ReadCloser, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+
+	br := bufio.NewReader(f)
+	// Files shorter than the longest magic number are simply read as
+	// they are.
+	header, err := br.Peek(len(xzMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		f.Close()
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	r := &compressedReader{Reader: br, closers: []io.Closer{f}}
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read gzip header of %s: %v", path, err)
+		}
+		r.Reader = gz
+		r.closers = []io.Closer{gz, f}
+	case bytes.HasPrefix(header, bzip2Magic):
+		r.Reader = bzip2.NewReader(br)
+	case bytes.HasPrefix(header, xzMagic):
+		xzr, err := xz.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read xz header of %s: %v", path, err)
+		}
+		r.Reader = xzr
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to create zstd reader for %s: %v", path, err)
+		}
+		zrc := zr.IOReadCloser()
+		r.Reader = zrc
+		r.closers = []io.Closer{zrc, f}
+	}
+
+	return r, nil
+}
+
+// ToSliceMaybeCompressed is like ToSlice, but transparently decompresses
+// files compressed with gzip, bzip2, xz, or zstd (see
+// OpenMaybeCompressed). Blank lines are omitted.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// []string: Slice of strings where each element represents a line in the file.
+// error: An error if the file cannot be read or decompressed.
+func ToSliceMaybeCompressed(path string) ([]string, error) {
+	return ToSliceMaybeCompressedFs(osFs, path)
+}
+
+// ToSliceMaybeCompressedFs is like ToSliceMaybeCompressed, but operates
+// on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// []string: Slice of strings where each element represents a line in the file.
+// error: An error if the file cannot be read or decompressed.
+func ToSliceMaybeCompressedFs(fs afero.Fs, path string) ([]string, error) {
+	var lines []string
+	err := LinesMaybeCompressedFs(fs, path, func(line string) error {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// LinesMaybeCompressed calls fn with each line of a file, without its
+// line ending, transparently decompressing files compressed with gzip,
+// bzip2, xz, or zstd (see OpenMaybeCompressed). Lines are streamed, so
+// large logs don't have to fit in memory.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// fn: The function called with each line. Returning an error stops
+// reading and returns the error.
+//
+// **Returns:**
+//
+// error: An error if the file cannot be read or decompressed, or the
+// error returned by fn.
+func LinesMaybeCompressed(path string, fn func(line string) error) error {
+	return LinesMaybeCompressedFs(osFs, path, fn)
+}
+
+// LinesMaybeCompressedFs is like LinesMaybeCompressed, but operates on
+// the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+// fn: The function called with each line. Returning an error stops
+// reading and returns the error.
+//
+// **Returns:**
+//
+// error: An error if the file cannot be read or decompressed, or the
+// error returned by fn.
+func LinesMaybeCompressedFs(fs afero.Fs, path string, fn func(line string) error) error {
+	rc, err := OpenMaybeCompressedFs(fs, path)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	br := bufio.NewReader(rc)
+	for {
+		// ReadString is used rather than a bufio.Scanner so that long
+		// lines aren't rejected.
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if fnErr := fn(strings.TrimRight(line, "\r\n")); fnErr != nil {
+				return fnErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+	}
+}
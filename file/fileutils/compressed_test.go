@@ -0,0 +1,177 @@
+package file_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/spf13/afero"
+	"github.com/ulikunitz/xz"
+)
+
+const compressedContent = "first line\n\nsecond line\n"
+
+// bzip2Content is compressedContent compressed with `bzip2 -9`. The
+// standard library can't write bzip2.
+var bzip2Content = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xe2, 0x51, 0x5d, 0xdf, 0x00, 0x00,
+	0x05, 0x51, 0x80, 0x00, 0x10, 0x40, 0x00, 0x0f, 0x25, 0x9c, 0x00, 0x20, 0x00, 0x21, 0xa1, 0x1a,
+	0x31, 0x90, 0x80, 0x68, 0x03, 0xa1, 0xd4, 0xf2, 0xd9, 0x4e, 0x0b, 0xa0, 0x48, 0x2f, 0xe5, 0x5a,
+	0x2e, 0xe4, 0x8a, 0x70, 0xa1, 0x21, 0xc4, 0xa2, 0xbb, 0xbe,
+}
+
+func compress(t *testing.T, newWriter func(io.Writer) (io.WriteCloser, error)) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := newWriter(&buf)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	if _, err := io.WriteString(w, compressedContent); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenMaybeCompressed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		contents func(t *testing.T) []byte
+		wantErr  bool
+	}{
+		{
+			name: "Gzip",
+			path: "app.log.gz",
+			contents: func(t *testing.T) []byte {
+				return compress(t, func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil })
+			},
+		},
+		{
+			name:     "Bzip2",
+			path:     "app.log.bz2",
+			contents: func(*testing.T) []byte { return bzip2Content },
+		},
+		{
+			name: "Xz",
+			path: "app.log.xz",
+			contents: func(t *testing.T) []byte {
+				return compress(t, func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) })
+			},
+		},
+		{
+			name: "Zstd without extension",
+			path: "app.log.1",
+			contents: func(t *testing.T) []byte {
+				return compress(t, func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) })
+			},
+		},
+		{
+			name:     "Plain text",
+			path:     "app.log",
+			contents: func(*testing.T) []byte { return []byte(compressedContent) },
+		},
+		{
+			name:     "Truncated gzip header",
+			path:     "broken.gz",
+			contents: func(*testing.T) []byte { return []byte{0x1f, 0x8b, 0x08} },
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.path)
+			if _, err := fileutils.Create(path, tc.contents(t), fileutils.CreateFile); err != nil {
+				t.Fatalf("failed to create %s: %v", path, err)
+			}
+
+			rc, err := fileutils.OpenMaybeCompressed(path)
+			if tc.wantErr {
+				if err == nil {
+					rc.Close()
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenMaybeCompressed() error = %v", err)
+			}
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("failed to read: %v", err)
+			}
+			if err := rc.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if string(got) != compressedContent {
+				t.Errorf("OpenMaybeCompressed() read %q, want %q", got, compressedContent)
+			}
+
+			lines, err := fileutils.ToSliceMaybeCompressed(path)
+			if err != nil {
+				t.Fatalf("ToSliceMaybeCompressed() error = %v", err)
+			}
+			want := []string{"first line", "second line"}
+			if !reflect.DeepEqual(lines, want) {
+				t.Errorf("ToSliceMaybeCompressed() = %q, want %q", lines, want)
+			}
+		})
+	}
+}
+
+func TestLinesMaybeCompressedFs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	data := compress(t, func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil })
+	if err := afero.WriteFile(fs, "/logs/app.log.gz", data, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := afero.WriteFile(fs, "/logs/crlf.log", []byte("one\r\ntwo"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	errStop := errors.New("stop")
+	testCases := []struct {
+		name    string
+		path    string
+		stopAt  int
+		want    []string
+		wantErr error
+	}{
+		{name: "All lines", path: "/logs/app.log.gz", want: []string{"first line", "", "second line"}},
+		{name: "Stops on error", path: "/logs/app.log.gz", stopAt: 1, want: []string{"first line"}, wantErr: errStop},
+		{name: "CRLF without trailing newline", path: "/logs/crlf.log", want: []string{"one", "two"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			err := fileutils.LinesMaybeCompressedFs(fs, tc.path, func(line string) error {
+				got = append(got, line)
+				if tc.stopAt > 0 && len(got) == tc.stopAt {
+					return errStop
+				}
+				return nil
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("LinesMaybeCompressedFs() error = %v, want %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("LinesMaybeCompressedFs() lines = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if err := fileutils.LinesMaybeCompressedFs(fs, "/logs/missing.gz", func(string) error { return nil }); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
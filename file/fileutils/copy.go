@@ -0,0 +1,360 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyOptions controls how CopyDir copies a directory tree.
+//
+// **Attributes:**
+//
+// Exclude: Patterns of entries to skip, matched in the same way as
+// ListROptions.Glob against paths relative to the source directory (e.g.,
+// ".git" or "build/**/*.o"). Excluded directories aren't descended into.
+// PreservePerms: Whether files and directories keep the permissions of
+// their source. Otherwise files are created with 0644 and directories
+// with 0755 permissions.
+// OnProgress: Optional callback invoked as data is copied with the number
+// of bytes copied so far and the total number of bytes to copy.
+// Link: Whether files are linked to their source instead of having their
+// contents copied. Defaults to LinkNone.
+type CopyOptions struct {
+	Exclude       []string
+	PreservePerms bool
+	OnProgress    func(copied, total int64)
+	Link          LinkMode
+}
+
+// LinkMode selects how a copied file shares data with its source.
+type LinkMode int
+
+const (
+	// LinkNone copies the contents of files.
+	LinkNone LinkMode = iota
+	// LinkReflink clones files with FICLONE, so the copy shares blocks
+	// with its source until either is modified. This needs a filesystem
+	// with copy-on-write support, such as Btrfs or XFS, on Linux. Files
+	// are copied when cloning isn't possible.
+	LinkReflink
+	// LinkHard hard links files to their source. Linked files have the
+	// permissions of their source regardless of PreservePerms, and
+	// modifying them modifies the source. Files are copied when linking
+	// isn't possible, e.g., across volumes.
+	LinkHard
+)
+
+// copyEntry is a single entry of the source tree to be copied.
+type copyEntry struct {
+	rel  string
+	mode fs.FileMode
+	size int64
+}
+
+// CopyDir recursively copies the contents of src into dst, creating dst
+// if it doesn't exist. Existing files in dst are overwritten. Symlinks are
+// recreated rather than followed, and other special files such as
+// sockets and devices are skipped.
+//
+// **Parameters:**
+//
+// src: String representing the path to the source directory.
+// dst: String representing the path to the destination directory.
+// opts: CopyOptions controlling exclusions, permissions, and progress
+// reporting.
+//
+// **Returns:**
+//
+// error: An error if an exclude pattern is invalid, dst is inside src,
+// or an entry can't be copied.
+func CopyDir(src, dst string, opts CopyOptions) error {
+	for _, pattern := range opts.Exclude {
+		if _, err := matchGlob(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+
+	if inside, err := isInside(dst, src); err != nil {
+		return err
+	} else if inside {
+		return fmt.Errorf("cannot copy %s into itself (%s)", src, dst)
+	}
+
+	entries, total, err := collectCopyEntries(src, opts.Exclude)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", dst, err)
+	}
+
+	var copied int64
+	progress := func(n int64) {
+		copied += n
+		if opts.OnProgress != nil {
+			opts.OnProgress(copied, total)
+		}
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.rel)
+		dstPath := filepath.Join(dst, entry.rel)
+
+		switch {
+		case entry.mode.IsDir():
+			if err := os.MkdirAll(dstPath, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %v", dstPath, err)
+			}
+		case entry.mode&fs.ModeSymlink != 0:
+			if err := copySymlink(srcPath, dstPath); err != nil {
+				return err
+			}
+		default:
+			perm := os.FileMode(0644)
+			if opts.PreservePerms {
+				perm = entry.mode.Perm()
+			}
+			if err := copyRegularFile(srcPath, dstPath, perm, entry.size, opts.Link, progress); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Directories are created writable so they can be filled, and only
+	// get their source permissions once their contents are copied.
+	if opts.PreservePerms {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].mode.IsDir() {
+				dstPath := filepath.Join(dst, entries[i].rel)
+				if err := os.Chmod(dstPath, entries[i].mode.Perm()); err != nil {
+					return fmt.Errorf("failed to set permissions on %s: %v", dstPath, err)
+				}
+			}
+		}
+		if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to set permissions on %s: %v", dst, err)
+		}
+	}
+
+	// Report completion even when there was nothing to copy.
+	if opts.OnProgress != nil && copied == 0 {
+		opts.OnProgress(0, total)
+	}
+
+	return nil
+}
+
+// CopyFile copies the regular file src to dst, overwriting dst if it
+// exists. Exclude is ignored.
+//
+// **Parameters:**
+//
+// src: String representing the path to the source file.
+// dst: String representing the path to the destination file.
+// opts: CopyOptions controlling permissions, linking, and progress
+// reporting.
+//
+// **Returns:**
+//
+// error: An error if src isn't a regular file or can't be copied.
+func CopyFile(src, dst string, opts CopyOptions) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	perm := os.FileMode(0644)
+	if opts.PreservePerms {
+		perm = info.Mode().Perm()
+	}
+
+	var copied int64
+	progress := func(n int64) {
+		copied += n
+		if opts.OnProgress != nil {
+			opts.OnProgress(copied, info.Size())
+		}
+	}
+	if err := copyRegularFile(src, dst, perm, info.Size(), opts.Link, progress); err != nil {
+		return err
+	}
+
+	if opts.OnProgress != nil && copied == 0 {
+		opts.OnProgress(0, info.Size())
+	}
+	return nil
+}
+
+// copyRegularFile copies src to dst, linking them according to link
+// when possible. Linked files are reported to progress as a whole.
+func copyRegularFile(src, dst string, perm os.FileMode, size int64, link LinkMode, progress func(n int64)) error {
+	switch link {
+	case LinkHard:
+		if err := hardLink(src, dst); err == nil {
+			progress(size)
+			return nil
+		}
+	case LinkReflink:
+		if err := reflink(src, dst, perm); err == nil {
+			progress(size)
+			return os.Chmod(dst, perm)
+		}
+	}
+
+	return copyFileWithProgress(src, dst, perm, progress)
+}
+
+// hardLink replaces dst with a hard link to src. dst is left alone if
+// it's already a link to src.
+func hardLink(src, dst string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if dstInfo, err := os.Lstat(dst); err == nil {
+		if os.SameFile(srcInfo, dstInfo) {
+			return nil
+		}
+		if err := os.Remove(dst); err != nil {
+			return err
+		}
+	}
+
+	return os.Link(src, dst)
+}
+
+// collectCopyEntries walks src and returns the entries to copy, in walk
+// order, along with the total size of the regular files among them.
+func collectCopyEntries(src string, exclude []string) ([]copyEntry, int64, error) {
+	var entries []copyEntry
+	var total int64
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == src {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range exclude {
+			if matched, _ := matchGlob(pattern, rel); matched {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode()
+		if !mode.IsDir() && !mode.IsRegular() && mode&fs.ModeSymlink == 0 {
+			return nil
+		}
+		if mode.IsRegular() {
+			total += info.Size()
+		}
+		entries = append(entries, copyEntry{rel: rel, mode: mode, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to walk %s: %v", src, err)
+	}
+
+	return entries, total, nil
+}
+
+// copySymlink recreates the symlink at src as dst, replacing any
+// existing file at dst.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return fmt.Errorf("failed to read symlink %s: %v", src, err)
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace %s: %v", dst, err)
+	}
+	if err := os.Symlink(target, dst); err != nil {
+		return fmt.Errorf("failed to create symlink %s: %v", dst, err)
+	}
+	return nil
+}
+
+// copyFileWithProgress copies src to dst, reporting each chunk written
+// to progress.
+func copyFileWithProgress(src, dst string, perm os.FileMode, progress func(n int64)) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", dst, err)
+	}
+
+	if _, err := io.Copy(&progressWriter{w: out, progress: progress}, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s to %s: %v", src, dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %v", dst, err)
+	}
+
+	// OpenFile only applies perm to new files.
+	return os.Chmod(dst, perm)
+}
+
+// progressWriter reports the number of bytes written through it.
+type progressWriter struct {
+	w        io.Writer
+	progress func(n int64)
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.progress(int64(n))
+	return n, err
+}
+
+// isInside reports whether path is dir or a path below it.
+func isInside(path, dir string) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false, nil
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))), nil
+}
@@ -0,0 +1,189 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDir(t *testing.T) {
+	newSrc := func(t *testing.T) string {
+		src := t.TempDir()
+		files := map[string]string{
+			"README.md":          "readme",
+			"cmd/app/main.go":    "package main",
+			"cmd/app/main.o":     "object",
+			".git/HEAD":          "ref: refs/heads/main",
+			".git/objects/aa/bb": "blob",
+			"scripts/run.sh":     "#!/bin/sh",
+		}
+		for rel, contents := range files {
+			path := filepath.Join(src, rel)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+		}
+		require.NoError(t, os.Chmod(filepath.Join(src, "scripts/run.sh"), 0755))
+		return src
+	}
+
+	testCases := []struct {
+		name          string
+		opts          fileutils.CopyOptions
+		wantFiles     []string
+		wantExecPerms bool
+	}{
+		{
+			name:      "Copies everything",
+			wantFiles: []string{".git/HEAD", ".git/objects/aa/bb", "README.md", "cmd/app/main.go", "cmd/app/main.o", "scripts/run.sh"},
+		},
+		{
+			name:      "Excludes directories and patterns",
+			opts:      fileutils.CopyOptions{Exclude: []string{".git", "*.o"}},
+			wantFiles: []string{"README.md", "cmd/app/main.go", "scripts/run.sh"},
+		},
+		{
+			name:          "Preserves permissions",
+			opts:          fileutils.CopyOptions{Exclude: []string{".git"}, PreservePerms: true},
+			wantFiles:     []string{"README.md", "cmd/app/main.go", "cmd/app/main.o", "scripts/run.sh"},
+			wantExecPerms: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := newSrc(t)
+			dst := filepath.Join(t.TempDir(), "mirror")
+
+			var calls int
+			var lastCopied, lastTotal int64
+			tc.opts.OnProgress = func(copied, total int64) {
+				require.GreaterOrEqual(t, copied, lastCopied, "progress must not go backwards")
+				calls++
+				lastCopied, lastTotal = copied, total
+			}
+
+			require.NoError(t, fileutils.CopyDir(src, dst, tc.opts))
+
+			got, err := fileutils.ListR(dst)
+			require.NoError(t, err)
+			var rels []string
+			var wantTotal int64
+			for _, path := range got {
+				rel, err := filepath.Rel(dst, path)
+				require.NoError(t, err)
+				rels = append(rels, filepath.ToSlash(rel))
+
+				srcData, err := os.ReadFile(filepath.Join(src, rel))
+				require.NoError(t, err)
+				dstData, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.Equal(t, srcData, dstData)
+				wantTotal += int64(len(dstData))
+			}
+			sort.Strings(rels)
+			require.Equal(t, tc.wantFiles, rels)
+
+			require.Positive(t, calls)
+			require.Equal(t, wantTotal, lastTotal)
+			require.Equal(t, lastTotal, lastCopied)
+
+			if runtime.GOOS != "windows" {
+				info, err := os.Stat(filepath.Join(dst, "scripts/run.sh"))
+				require.NoError(t, err)
+				wantPerm := os.FileMode(0644)
+				if tc.wantExecPerms {
+					wantPerm = 0755
+				}
+				require.Equal(t, wantPerm, info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestCopyDirErrors(t *testing.T) {
+	src := t.TempDir()
+	file := filepath.Join(src, "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+
+	testCases := []struct {
+		name string
+		src  string
+		dst  string
+		opts fileutils.CopyOptions
+	}{
+		{name: "Missing source", src: filepath.Join(src, "missing"), dst: t.TempDir()},
+		{name: "Source is a file", src: file, dst: t.TempDir()},
+		{name: "Destination inside source", src: src, dst: filepath.Join(src, "nested")},
+		{name: "Invalid exclude pattern", src: src, dst: t.TempDir(), opts: fileutils.CopyOptions{Exclude: []string{"["}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, fileutils.CopyDir(tc.src, tc.dst, tc.opts))
+		})
+	}
+}
+
+func TestCopyFileLinkModes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		link      fileutils.LinkMode
+		wantShare bool
+	}{
+		{name: "Copy contents", link: fileutils.LinkNone},
+		{name: "Reflink or copy", link: fileutils.LinkReflink},
+		{name: "Hard link", link: fileutils.LinkHard, wantShare: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "artifact.bin")
+			dst := filepath.Join(dir, "staged.bin")
+			require.NoError(t, os.WriteFile(src, []byte("artifact"), 0644))
+			require.NoError(t, os.WriteFile(dst, []byte("stale contents"), 0644))
+
+			var lastCopied, lastTotal int64
+			opts := fileutils.CopyOptions{Link: tc.link, OnProgress: func(copied, total int64) {
+				lastCopied, lastTotal = copied, total
+			}}
+			require.NoError(t, fileutils.CopyFile(src, dst, opts))
+			// Copying again over the result must be a no-op for links.
+			require.NoError(t, fileutils.CopyFile(src, dst, opts))
+
+			data, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, "artifact", string(data))
+			require.Equal(t, int64(len(data)), lastTotal)
+			require.Equal(t, lastTotal, lastCopied)
+
+			srcInfo, err := os.Stat(src)
+			require.NoError(t, err)
+			dstInfo, err := os.Stat(dst)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantShare, os.SameFile(srcInfo, dstInfo))
+		})
+	}
+
+	require.Error(t, fileutils.CopyFile(t.TempDir(), filepath.Join(t.TempDir(), "dst"), fileutils.CopyOptions{}))
+}
+
+func TestCopyDirHardLinks(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "bin", "tool"), []byte("binary"), 0755))
+	dst := filepath.Join(t.TempDir(), "stage")
+
+	require.NoError(t, fileutils.CopyDir(src, dst, fileutils.CopyOptions{Link: fileutils.LinkHard}))
+
+	srcInfo, err := os.Stat(filepath.Join(src, "bin", "tool"))
+	require.NoError(t, err)
+	dstInfo, err := os.Stat(filepath.Join(dst, "bin", "tool"))
+	require.NoError(t, err)
+	require.True(t, os.SameFile(srcInfo, dstInfo))
+}
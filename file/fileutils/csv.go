@@ -0,0 +1,207 @@
+package file
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LinesToCSV writes records to a CSV file, preceded by a header row if
+// headers is non-empty. The file is replaced atomically and keeps its
+// permissions if it already exists; new files are created with 0644
+// permissions.
+//
+// **Parameters:**
+//
+// path: String representing the path to the CSV file.
+// records: 2D slice of strings representing the rows and values to write.
+// headers: Column headers to write as the first row, or nil for none.
+//
+// **Returns:**
+//
+// error: An error if the records can't be encoded or the file can't be
+// written.
+func LinesToCSV(path string, records [][]string, headers []string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if len(headers) > 0 {
+		if err := w.Write(headers); err != nil {
+			return fmt.Errorf("failed to write CSV headers for %s: %w", path, err)
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV records for %s: %w", path, err)
+	}
+
+	return writeFileKeepPerm(path, buf.Bytes())
+}
+
+// CSVToStructs reads a CSV file and maps each row after the header row to
+// a value of struct type T. Columns are matched to fields by the `csv`
+// struct tag, or by a case-insensitive match on the field name when no tag
+// is set. Fields tagged `csv:"-"`, unexported fields, and columns without
+// a matching field are ignored. Empty cells leave the field at its zero
+// value.
+//
+// Supported field types are strings, booleans, integers, unsigned
+// integers, floats, time.Duration, types implementing
+// encoding.TextUnmarshaler (such as time.Time, parsed as RFC 3339), and
+// pointers to any of these.
+//
+// **Parameters:**
+//
+// path: String representing the path to the CSV file.
+//
+// **Returns:**
+//
+// []T: One value for each row of the CSV.
+// error: An error if T is not a struct, the file can't be read or parsed,
+// or a cell can't be converted to its field's type.
+func CSVToStructs[T any](path string) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("CSVToStructs requires a struct type, got %s", typ)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	headers, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV headers from %s: %w", path, err)
+	}
+
+	fields := csvFieldIndexes(typ, headers)
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV from %s: %w", path, err)
+	}
+
+	results := make([]T, 0, len(records))
+	for i, record := range records {
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		for col, value := range record {
+			idx, ok := fields[col]
+			if !ok || value == "" {
+				continue
+			}
+			if err := setCSVField(rv.Field(idx), value); err != nil {
+				// Rows are numbered from 1, with the header row first.
+				return nil, fmt.Errorf("%s: row %d, column %q: %w", path, i+2, headers[col], err)
+			}
+		}
+		results = append(results, v)
+	}
+
+	return results, nil
+}
+
+// csvFieldIndexes maps each CSV column to the index of the struct field
+// it populates.
+func csvFieldIndexes(typ reflect.Type, headers []string) map[int]int {
+	byName := make(map[string]int)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		byName[strings.ToLower(name)] = i
+	}
+
+	fields := make(map[int]int)
+	for col, header := range headers {
+		if idx, ok := byName[strings.ToLower(strings.TrimSpace(header))]; ok {
+			fields[col] = idx
+		}
+	}
+
+	return fields
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setCSVField parses value into the field v according to its type.
+func setCSVField(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setCSVField(ptr.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+type testResult struct {
+	Name     string        `csv:"test"`
+	Passed   bool          `csv:"passed"`
+	Duration time.Duration `csv:"duration"`
+	Retries  *int          `csv:"retries"`
+	Score    float64
+	Ignored  string `csv:"-"`
+}
+
+func TestLinesToCSV(t *testing.T) {
+	testCases := []struct {
+		name     string
+		records  [][]string
+		headers  []string
+		wantText string
+	}{
+		{
+			name:     "With headers",
+			records:  [][]string{{"TestA", "true"}, {"TestB", "false"}},
+			headers:  []string{"test", "passed"},
+			wantText: "test,passed\nTestA,true\nTestB,false\n",
+		},
+		{
+			name:     "Without headers",
+			records:  [][]string{{"a", "b"}},
+			wantText: "a,b\n",
+		},
+		{
+			name:     "Quotes fields that need it",
+			records:  [][]string{{"Doe, Jane", `say "hi"`}},
+			wantText: "\"Doe, Jane\",\"say \"\"hi\"\"\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.csv")
+			if err := fileutils.LinesToCSV(path, tc.records, tc.headers); err != nil {
+				t.Fatalf("LinesToCSV() failed: %v", err)
+			}
+
+			text, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tc.wantText {
+				t.Errorf("LinesToCSV() wrote %q, want %q", text, tc.wantText)
+			}
+
+			if len(tc.headers) > 0 {
+				got, err := fileutils.CSVToLines(path)
+				if err != nil {
+					t.Fatalf("CSVToLines() failed: %v", err)
+				}
+				if !reflect.DeepEqual(got, tc.records) {
+					t.Errorf("CSVToLines() = %q, want %q", got, tc.records)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVToStructs(t *testing.T) {
+	two := 2
+
+	testCases := []struct {
+		name    string
+		content string
+		want    []testResult
+		wantErr bool
+	}{
+		{
+			name:    "Maps tagged and untagged columns",
+			content: "test,passed,duration,retries,score,extra,Ignored\nTestA,true,1.5s,2,0.75,x,y\nTestB,false,20ms,,1,,\n",
+			want: []testResult{
+				{Name: "TestA", Passed: true, Duration: 1500 * time.Millisecond, Retries: &two, Score: 0.75},
+				{Name: "TestB", Duration: 20 * time.Millisecond, Score: 1},
+			},
+		},
+		{
+			name:    "Column order does not matter",
+			content: "passed,test\ntrue,TestA\n",
+			want:    []testResult{{Name: "TestA", Passed: true}},
+		},
+		{
+			name:    "Header only",
+			content: "test,passed\n",
+			want:    []testResult{},
+		},
+		{
+			name:    "Invalid value",
+			content: "test,passed\nTestA,maybe\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.csv")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fileutils.CSVToStructs[testResult](path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CSVToStructs() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CSVToStructs() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Non-struct type", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.csv")
+		if err := os.WriteFile(path, []byte("a\n1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fileutils.CSVToStructs[string](path); err == nil {
+			t.Error("CSVToStructs() expected an error for a non-struct type")
+		}
+	})
+}
@@ -9,8 +9,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/spf13/afero"
 )
 
+// osFs is the filesystem used by the functions that don't take an
+// afero.Fs.
+var osFs afero.Fs = afero.NewOsFs()
+
 // File is an interface representing a system file.
 //
 // **Methods:**
@@ -141,6 +147,98 @@ func (rf RealFile) Append(text string) error {
 	return rf.Write(fileContents, perm)
 }
 
+// FsFile is an implementation of the File interface backed by an
+// afero.Fs, which allows code that works with Files to be tested against
+// an in-memory filesystem.
+//
+// **Attributes:**
+//
+// Fs: The afero.Fs the file lives on.
+// Path: The path to the file on Fs.
+type FsFile struct {
+	Fs   afero.Fs
+	Path string
+}
+
+// Open is a method for the FsFile type that opens the file and
+// returns a io.ReadCloser and an error.
+//
+// **Returns:**
+//
+// io.ReadCloser: An object that allows reading from and closing the file.
+// error: An error if any issue occurs while trying to open the file.
+func (ff FsFile) Open() (io.ReadCloser, error) {
+	return ff.Fs.Open(ff.Path)
+}
+
+// RemoveAll is a method for the FsFile type that removes the file or
+// directory at its path, including any content of a directory.
+//
+// **Returns:**
+//
+// error: An error if any issue occurs while trying to remove the file or directory.
+func (ff FsFile) RemoveAll() error {
+	return ff.Fs.RemoveAll(ff.Path)
+}
+
+// Stat is a method for the FsFile type that retrieves the FileInfo
+// for the file or directory.
+//
+// **Returns:**
+//
+// os.FileInfo: FileInfo describing the file.
+// error: An error if any issue occurs while trying to get the FileInfo.
+func (ff FsFile) Stat() (os.FileInfo, error) {
+	return ff.Fs.Stat(ff.Path)
+}
+
+// Remove is a method for the FsFile type that removes the file or
+// directory. Note that it will not remove a directory unless it is empty.
+//
+// **Returns:**
+//
+// error: An error if any issue occurs while trying to remove the file or directory.
+func (ff FsFile) Remove() error {
+	return ff.Fs.Remove(ff.Path)
+}
+
+// Write is a method for the FsFile type that writes a slice of bytes
+// to the file with specified file permissions.
+//
+// **Parameters:**
+//
+// contents: A slice of bytes that should be written to the file.
+// perm: File permissions to use when creating the file.
+//
+// **Returns:**
+//
+// error: An error if any issue occurs while trying to write to the file.
+func (ff FsFile) Write(contents []byte, perm os.FileMode) error {
+	return afero.WriteFile(ff.Fs, ff.Path, contents, perm)
+}
+
+// Append adds a string to the end of the file. If the file doesn't
+// exist, it's created with 0644 permissions.
+//
+// **Parameters:**
+//
+// text: String to append to the end of the file.
+//
+// **Returns:**
+//
+// error: An error if the file can't be opened or the string can't be
+// written to the file.
+func (ff FsFile) Append(text string) error {
+	f, err := ff.Fs.OpenFile(ff.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(text)
+	return err
+}
+
 // CreateType represents the type of file creation action to execute.
 type CreateType int
 
@@ -169,15 +267,33 @@ const (
 // error: An error if the directory or file can't be created, if it
 // already exists (except for temporary files), or if there's a problem writing to the file.
 func Create(path string, contents []byte, createType CreateType) (string, error) {
+	return CreateFs(osFs, path, contents, createType)
+}
+
+// CreateFs is like Create, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: Path to the directory or file. For temporary files, this serves as a pattern.
+// contents: Content to write to the file as a byte slice.
+// createType: A CreateType value representing what kind of file creation action to execute.
+//
+// **Returns:**
+//
+// string: The path of the created directory or file.
+// error: An error if the directory or file can't be created, or if
+// there's a problem writing to the file.
+func CreateFs(fs afero.Fs, path string, contents []byte, createType CreateType) (string, error) {
 	switch createType {
 	case CreateDirectory:
-		return path, createDirectory(path)
+		return path, createDirectory(fs, path)
 	case CreateEmptyFile:
-		return path, createEmptyFile(path)
+		return path, createEmptyFile(fs, path)
 	case CreateFile:
-		return path, createFile(path, contents)
+		return path, createFile(fs, path, contents)
 	case CreateTempFile:
-		tempPath, err := createTempFile(path, "testTempFile", contents)
+		tempPath, err := createTempFile(fs, path, "testTempFile", contents)
 		return tempPath, err
 	default:
 		return path, fmt.Errorf("invalid createType %v", createType)
@@ -196,7 +312,23 @@ func Create(path string, contents []byte, createType CreateType) (string, error)
 // bool: Returns true if the string is found, otherwise false.
 // error: An error if the file cannot be read.
 func HasStr(path string, searchStr string) (bool, error) {
-	f, err := os.Open(path)
+	return HasStrFs(osFs, path, searchStr)
+}
+
+// HasStrFs is like HasStr, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+// searchStr: String to look for in the file.
+//
+// **Returns:**
+//
+// bool: Returns true if the string is found, otherwise false.
+// error: An error if the file cannot be read.
+func HasStrFs(fs afero.Fs, path string, searchStr string) (bool, error) {
+	f, err := fs.Open(path)
 	if err != nil {
 		return false, err
 	}
@@ -230,7 +362,22 @@ func HasStr(path string, searchStr string) (bool, error) {
 // [][]string: 2D slice of strings representing the rows and values of the CSV.
 // error: An error if the file cannot be read or parsed.
 func CSVToLines(path string) ([][]string, error) {
-	file, err := os.Open(path)
+	return CSVToLinesFs(osFs, path)
+}
+
+// CSVToLinesFs is like CSVToLines, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the CSV file.
+//
+// **Returns:**
+//
+// [][]string: 2D slice of strings representing the rows and values of the CSV.
+// error: An error if the file cannot be read or parsed.
+func CSVToLinesFs(fs afero.Fs, path string) ([][]string, error) {
+	file, err := fs.Open(path)
 	if err != nil {
 		return [][]string{}, err
 	}
@@ -262,11 +409,25 @@ func CSVToLines(path string) ([][]string, error) {
 //
 // error: An error if the file cannot be deleted.
 func Delete(path string) error {
-	if !Exists(path) {
+	return DeleteFs(osFs, path)
+}
+
+// DeleteFs is like Delete, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// error: An error if the file cannot be deleted.
+func DeleteFs(fs afero.Fs, path string) error {
+	if !ExistsFs(fs, path) {
 		return fmt.Errorf("file or directory at path %s does not exist", path)
 	}
 
-	if err := os.Remove(path); err != nil {
+	if err := fs.Remove(path); err != nil {
 		return err
 	}
 
@@ -283,7 +444,21 @@ func Delete(path string) error {
 //
 // bool: Returns true if the file exists, otherwise false.
 func Exists(fileLoc string) bool {
-	if _, err := os.Stat(fileLoc); err != nil {
+	return ExistsFs(osFs, fileLoc)
+}
+
+// ExistsFs is like Exists, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// fileLoc: String representing the path to the file.
+//
+// **Returns:**
+//
+// bool: Returns true if the file exists, otherwise false.
+func ExistsFs(fs afero.Fs, fileLoc string) bool {
+	if _, err := fs.Stat(fileLoc); err != nil {
 		if os.IsNotExist(err) {
 			return false
 		}
@@ -303,7 +478,22 @@ func Exists(fileLoc string) bool {
 // []string: Slice of strings where each element represents a line in the file.
 // error: An error if the file cannot be read.
 func ToSlice(path string) ([]string, error) {
-	b, err := os.ReadFile(path)
+	return ToSliceFs(osFs, path)
+}
+
+// ToSliceFs is like ToSlice, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// []string: Slice of strings where each element represents a line in the file.
+// error: An error if the file cannot be read.
+func ToSliceFs(fs afero.Fs, path string) ([]string, error) {
+	b, err := afero.ReadFile(fs, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %v", path, err)
 	}
@@ -331,9 +521,25 @@ func ToSlice(path string) ([]string, error) {
 // []string: Slice of file paths if the file is found.
 // error: An error if the file cannot be found.
 func Find(fileName string, dirs []string) ([]string, error) {
+	return FindFs(osFs, fileName, dirs)
+}
+
+// FindFs is like Find, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// fileName: Name of the file to find.
+// dirs: Slice of strings representing the directories to search in.
+//
+// **Returns:**
+//
+// []string: Slice of file paths if the file is found.
+// error: An error if the file cannot be found.
+func FindFs(fs afero.Fs, fileName string, dirs []string) ([]string, error) {
 	var files []string
 	for _, dir := range dirs {
-		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		err := afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -353,7 +559,8 @@ func Find(fileName string, dirs []string) ([]string, error) {
 	return files, nil
 }
 
-// ListR lists all files in a directory and its subdirectories.
+// ListR recursively lists all files in a directory and its subdirectories.
+// Use ListRWithOptions to filter the results or include directories.
 //
 // **Parameters:**
 //
@@ -361,21 +568,28 @@ func Find(fileName string, dirs []string) ([]string, error) {
 //
 // **Returns:**
 //
-// []string: Slice of strings representing the paths of the files found.
+// []string: Slice of strings representing the full paths of the files found.
 // error: An error if the files cannot be listed.
 func ListR(dirPath string) ([]string, error) {
-	fis, err := os.ReadDir(dirPath)
-	if err != nil {
-		return []string{}, err
-	}
-	fileList := make([]string, len(fis))
-	for i, fi := range fis {
-		fileList[i] = fi.Name()
-	}
-	return fileList, nil
+	return ListRFs(osFs, dirPath)
+}
+
+// ListRFs is like ListR, but operates on the input afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// dirPath: String representing the path to the directory.
+//
+// **Returns:**
+//
+// []string: Slice of strings representing the full paths of the files found.
+// error: An error if the files cannot be listed.
+func ListRFs(fs afero.Fs, dirPath string) ([]string, error) {
+	return ListRWithOptionsFs(fs, dirPath, ListROptions{})
 }
 
-func createDirectory(path string) error {
+func createDirectory(fs afero.Fs, path string) error {
 	if !filepath.IsAbs(path) {
 		absDir, err := filepath.Abs(path)
 		if err != nil {
@@ -384,34 +598,36 @@ func createDirectory(path string) error {
 		path = absDir
 	}
 
-	if err := os.MkdirAll(path, 0755); err != nil {
+	if err := fs.MkdirAll(path, 0755); err != nil {
 		return fmt.Errorf("failed to create new directory at %s: %v", path, err)
 	}
 
 	return nil
 }
 
-func createEmptyFile(name string) error {
-	file, err := os.Create(name)
+func createEmptyFile(fs afero.Fs, name string) error {
+	file, err := fs.Create(name)
 	if err != nil {
 		return err
 	}
 	return file.Close()
 }
 
-func createFile(filePath string, fileContents []byte) error {
-	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+func createFile(fs afero.Fs, filePath string, fileContents []byte) error {
+	if err := fs.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
 		return fmt.Errorf("cannot create dir portion of filepath %s: %v", filePath, err)
 	}
 
-	if err := os.WriteFile(filePath, fileContents, os.ModePerm); err != nil {
+	if err := afero.WriteFile(fs, filePath, fileContents, os.ModePerm); err != nil {
 		return fmt.Errorf("cannot write to file %s: %v", filePath, err)
 	}
 
 	return nil
 }
 
-// SeekAndDestroy walks through a directory and deletes all files that match the pattern
+// SeekAndDestroy walks through a directory and deletes all files and
+// directories that match the pattern. Patterns are matched in the same
+// way as ListROptions.Glob.
 //
 // **Parameters:**
 //
@@ -422,24 +638,49 @@ func createFile(filePath string, fileContents []byte) error {
 //
 // error: An error if the files cannot be deleted.
 func SeekAndDestroy(path string, pattern string) error {
-	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	return SeekAndDestroyFs(osFs, path, pattern)
+}
 
-		matched, err := filepath.Match(pattern, info.Name())
-		if err != nil {
-			return err
-		}
+// SeekAndDestroyFs is like SeekAndDestroy, but operates on the input
+// afero.Fs.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// path: String representing the path to the directory.
+// pattern: String representing the pattern to match.
+//
+// **Returns:**
+//
+// error: An error if the files cannot be deleted.
+func SeekAndDestroyFs(fs afero.Fs, path string, pattern string) error {
+	matches, err := ListRWithOptionsFs(fs, path, ListROptions{Glob: pattern, IncludeDirs: true})
+	if err != nil {
+		return err
+	}
 
-		if matched {
-			if err := os.RemoveAll(path); err != nil {
-				return fmt.Errorf("failed to delete file or directory: %v", err)
-			}
+	var removed []string
+	for _, match := range matches {
+		if isUnderAny(match, removed) {
+			continue
+		}
+		if err := fs.RemoveAll(match); err != nil {
+			return fmt.Errorf("failed to delete file or directory: %v", err)
 		}
+		removed = append(removed, match)
+	}
+
+	return nil
+}
 
-		return nil
-	})
+// isUnderAny reports whether path is inside one of the input directories.
+func isUnderAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
 }
 
 // WriteTempFile creates a temporary file in the system default temp directory,
@@ -468,8 +709,8 @@ func WriteTempFile(workloadName string, jobFile *bytes.Buffer) (string, error) {
 	return tempFilePath.Name(), nil
 }
 
-func createTempFile(dir, fileName string, contents []byte) (string, error) {
-	tempFile, err := os.CreateTemp(dir, fileName+"-*")
+func createTempFile(fs afero.Fs, dir, fileName string, contents []byte) (string, error) {
+	tempFile, err := afero.TempFile(fs, dir, fileName+"-*")
 	if err != nil {
 		return "", err
 	}
@@ -13,6 +13,7 @@ import (
 
 	fileutils "github.com/l50/goutils/v2/file/fileutils"
 	"github.com/l50/goutils/v2/str"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
 
@@ -653,3 +654,127 @@ func TestSeekAndDestroy(t *testing.T) {
 		})
 	}
 }
+
+func TestFsFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	f := fileutils.FsFile{Fs: fs, Path: "/data/notes.txt"}
+
+	require.NoError(t, fs.MkdirAll("/data", 0755))
+	require.NoError(t, f.Write([]byte("first\n"), 0600))
+	require.NoError(t, f.Append("second\n"))
+
+	rc, err := f.Open()
+	require.NoError(t, err)
+	contents, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, "first\nsecond\n", string(contents))
+
+	info, err := f.Stat()
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, f.Remove())
+	_, err = f.Stat()
+	require.True(t, os.IsNotExist(err))
+
+	dir := fileutils.FsFile{Fs: fs, Path: "/data"}
+	require.NoError(t, afero.WriteFile(fs, "/data/nested/file", nil, 0644))
+	require.NoError(t, dir.RemoveAll())
+	require.False(t, fileutils.ExistsFs(fs, "/data"))
+}
+
+func TestFsVariants(t *testing.T) {
+	newFs := func(t *testing.T) afero.Fs {
+		fs := afero.NewMemMapFs()
+		for path, contents := range map[string]string{
+			"/repo/main.go":          "package main\n",
+			"/repo/report.csv":       "name,score\nalice,10\nbob,7\n",
+			"/repo/notes.txt":        "one\n\ntwo\n",
+			"/repo/cache/a.tmp":      "",
+			"/repo/cache/deep/b.tmp": "",
+			"/repo/docs/main.go.bak": "",
+		} {
+			require.NoError(t, afero.WriteFile(fs, path, []byte(contents), 0644))
+		}
+		return fs
+	}
+
+	testCases := []struct {
+		name string
+		run  func(t *testing.T, fs afero.Fs)
+	}{
+		{
+			name: "FindFs",
+			run: func(t *testing.T, fs afero.Fs) {
+				files, err := fileutils.FindFs(fs, "main.go", []string{"/repo"})
+				require.NoError(t, err)
+				require.Equal(t, []string{"/repo/main.go"}, files)
+
+				_, err = fileutils.FindFs(fs, "missing.go", []string{"/repo"})
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "ListRWithOptionsFs",
+			run: func(t *testing.T, fs afero.Fs) {
+				files, err := fileutils.ListRWithOptionsFs(fs, "/repo", fileutils.ListROptions{Glob: "*.tmp"})
+				require.NoError(t, err)
+				require.Equal(t, []string{"/repo/cache/a.tmp", "/repo/cache/deep/b.tmp"}, files)
+
+				all, err := fileutils.ListRFs(fs, "/repo")
+				require.NoError(t, err)
+				require.Len(t, all, 6)
+			},
+		},
+		{
+			name: "SeekAndDestroyFs",
+			run: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, fileutils.SeekAndDestroyFs(fs, "/repo", "cache"))
+				require.False(t, fileutils.ExistsFs(fs, "/repo/cache"))
+				require.True(t, fileutils.ExistsFs(fs, "/repo/main.go"))
+			},
+		},
+		{
+			name: "ReadHelpers",
+			run: func(t *testing.T, fs afero.Fs) {
+				found, err := fileutils.HasStrFs(fs, "/repo/main.go", "package main")
+				require.NoError(t, err)
+				require.True(t, found)
+
+				lines, err := fileutils.ToSliceFs(fs, "/repo/notes.txt")
+				require.NoError(t, err)
+				require.Equal(t, []string{"one", "two"}, lines)
+
+				records, err := fileutils.CSVToLinesFs(fs, "/repo/report.csv")
+				require.NoError(t, err)
+				require.Equal(t, [][]string{{"alice", "10"}, {"bob", "7"}}, records)
+			},
+		},
+		{
+			name: "CreateFsAndDeleteFs",
+			run: func(t *testing.T, fs afero.Fs) {
+				_, err := fileutils.CreateFs(fs, "/new/dir/file.txt", []byte("hi"), fileutils.CreateFile)
+				require.NoError(t, err)
+				require.True(t, fileutils.ExistsFs(fs, "/new/dir/file.txt"))
+
+				tempPath, err := fileutils.CreateFs(fs, "/new", []byte("tmp"), fileutils.CreateTempFile)
+				require.NoError(t, err)
+				require.True(t, strings.HasPrefix(tempPath, "/new/"))
+
+				require.NoError(t, fileutils.DeleteFs(fs, "/new/dir/file.txt"))
+				require.Error(t, fileutils.DeleteFs(fs, "/new/dir/file.txt"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := newFs(t)
+			tc.run(t, fs)
+
+			// None of the Fs variants may touch the real disk.
+			require.False(t, fileutils.Exists("/repo/main.go"))
+		})
+	}
+}
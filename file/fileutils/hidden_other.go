@@ -0,0 +1,7 @@
+//go:build !windows
+
+package file
+
+func hasHiddenAttribute(path string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package file
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+func hasHiddenAttribute(path string) (bool, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return false, err
+	}
+
+	attrs, err := windows.GetFileAttributes(pathPtr)
+	if err != nil {
+		return false, fmt.Errorf("failed to get attributes of %s: %w", path, err)
+	}
+
+	return attrs&windows.FILE_ATTRIBUTE_HIDDEN != 0, nil
+}
@@ -0,0 +1,179 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrInvalidRecord is returned by Journal.Append for records that contain
+// a newline, which would split them across lines.
+var ErrInvalidRecord = errors.New("journal record must not contain a newline")
+
+// JournalOptions configures a Journal.
+//
+// **Attributes:**
+//
+// MaxSize: Size in bytes after which the journal is rotated before the
+// next append. 0 disables size-based rotation.
+// RotatedName: Optional function returning the path the journal is moved
+// to when it's rotated. Defaults to the journal path with a UTC
+// timestamp suffix, e.g., "audit.log.20240102T150405.000000000".
+// OnRotate: Optional hook called with the path of the rotated file, e.g.,
+// to compress or upload it. It runs while the journal is locked, so
+// writers wait for it.
+// Sync: Whether every append is flushed to stable storage before
+// Append returns.
+type JournalOptions struct {
+	MaxSize     int64
+	RotatedName func(path string, now time.Time) string
+	OnRotate    func(rotatedPath string) error
+	Sync        bool
+}
+
+// Journal is an append-only file of newline-terminated records that can
+// be shared by concurrent writers, both goroutines and separate
+// processes, such as an audit trail written by several automation jobs.
+// Every append holds an exclusive lock on a "<path>.lock" file, so
+// records are never interleaved and rotation doesn't race with writers.
+//
+// **Attributes:**
+//
+// Path: The path of the journal file.
+// Options: The JournalOptions controlling rotation and syncing.
+type Journal struct {
+	Path    string
+	Options JournalOptions
+}
+
+// NewJournal returns a Journal writing to path. The parent directory is
+// created if it doesn't exist.
+//
+// **Parameters:**
+//
+// path: String representing the path to the journal file.
+// opts: JournalOptions controlling rotation and syncing.
+//
+// **Returns:**
+//
+// *Journal: The journal.
+// error: An error if the parent directory can't be created.
+func NewJournal(path string, opts JournalOptions) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %v", err)
+	}
+	return &Journal{Path: path, Options: opts}, nil
+}
+
+// Append writes record to the journal as a single line. A trailing
+// newline is added if record doesn't end with one. The journal is rotated
+// first if the record would grow it beyond MaxSize.
+//
+// **Parameters:**
+//
+// record: The record to append. It must not contain a newline other than
+// a trailing one.
+//
+// **Returns:**
+//
+// error: ErrInvalidRecord if record spans several lines, or an error if
+// the journal can't be locked, rotated, or written.
+func (j *Journal) Append(record []byte) error {
+	line := bytes.TrimSuffix(record, []byte("\n"))
+	if bytes.ContainsAny(line, "\r\n") {
+		return ErrInvalidRecord
+	}
+	line = append(line[:len(line):len(line)], '\n')
+
+	unlock, err := LockFile(j.lockPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if j.Options.MaxSize > 0 {
+		info, err := os.Stat(j.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to stat journal %s: %v", j.Path, err)
+		}
+		if err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > j.Options.MaxSize {
+			if _, err := j.rotate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// O_APPEND makes every write land at the current end of the file,
+	// even if another process wrote since the file was opened.
+	f, err := os.OpenFile(j.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal %s: %v", j.Path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to append to journal %s: %v", j.Path, err)
+	}
+	if j.Options.Sync {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync journal %s: %v", j.Path, err)
+		}
+	}
+
+	return f.Close()
+}
+
+// Rotate moves the current journal file aside and calls the OnRotate
+// hook, so that the next append starts a new file.
+//
+// **Returns:**
+//
+// string: The path of the rotated file, or an empty string if the journal
+// didn't exist yet.
+// error: An error if the journal can't be locked or moved, or the OnRotate
+// hook fails.
+func (j *Journal) Rotate() (string, error) {
+	unlock, err := LockFile(j.lockPath())
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	return j.rotate()
+}
+
+// rotate moves the journal aside. The caller must hold the lock.
+func (j *Journal) rotate() (string, error) {
+	if _, err := os.Stat(j.Path); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	nameFn := j.Options.RotatedName
+	if nameFn == nil {
+		nameFn = defaultRotatedName
+	}
+	rotated := nameFn(j.Path, time.Now())
+
+	if err := os.Rename(j.Path, rotated); err != nil {
+		return "", fmt.Errorf("failed to rotate journal %s: %v", j.Path, err)
+	}
+
+	if j.Options.OnRotate != nil {
+		if err := j.Options.OnRotate(rotated); err != nil {
+			return rotated, fmt.Errorf("journal rotation hook failed for %s: %v", rotated, err)
+		}
+	}
+
+	return rotated, nil
+}
+
+func (j *Journal) lockPath() string {
+	return j.Path + ".lock"
+}
+
+func defaultRotatedName(path string, now time.Time) string {
+	return path + "." + now.UTC().Format("20060102T150405.000000000")
+}
@@ -0,0 +1,152 @@
+package file_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalConcurrentAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	journal, err := fileutils.NewJournal(path, fileutils.JournalOptions{})
+	require.NoError(t, err)
+
+	const writers, records = 8, 50
+	payload := strings.Repeat("x", 4096)
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < records; r++ {
+				if err := journal.Append([]byte(fmt.Sprintf("writer=%d record=%d %s", w, r, payload))); err != nil {
+					t.Errorf("Append() failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, writers*records)
+	seen := map[string]bool{}
+	for _, line := range lines {
+		var w, r int
+		_, err := fmt.Sscanf(line, "writer=%d record=%d", &w, &r)
+		require.NoError(t, err, "corrupted line %q", line)
+		require.True(t, strings.HasSuffix(line, " "+payload), "corrupted line %q", line)
+		seen[line[:strings.Index(line, " x")]] = true
+	}
+	require.Len(t, seen, writers*records)
+}
+
+func TestJournalAppend(t *testing.T) {
+	testCases := []struct {
+		name    string
+		records []string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Adds trailing newlines",
+			records: []string{`{"event":"start"}`, "done\n"},
+			want:    "{\"event\":\"start\"}\ndone\n",
+		},
+		{
+			name:    "Rejects multi-line records",
+			records: []string{"first\nsecond"},
+			wantErr: fileutils.ErrInvalidRecord,
+		},
+		{
+			name:    "Rejects carriage returns",
+			records: []string{"first\r"},
+			wantErr: fileutils.ErrInvalidRecord,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal.log")
+			journal, err := fileutils.NewJournal(path, fileutils.JournalOptions{Sync: true})
+			require.NoError(t, err)
+
+			for _, record := range tc.records {
+				err = journal.Append([]byte(record))
+				if err != nil {
+					break
+				}
+			}
+			if tc.wantErr != nil {
+				require.True(t, errors.Is(err, tc.wantErr), "Append() error = %v, want %v", err, tc.wantErr)
+				require.NoFileExists(t, path)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestJournalRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.log")
+
+	var rotated []string
+	journal, err := fileutils.NewJournal(path, fileutils.JournalOptions{
+		MaxSize: 10,
+		RotatedName: func(path string, _ time.Time) string {
+			return fmt.Sprintf("%s.%d", path, len(rotated)+1)
+		},
+		OnRotate: func(rotatedPath string) error {
+			rotated = append(rotated, rotatedPath)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	name, err := journal.Rotate()
+	require.NoError(t, err)
+	require.Empty(t, name, "rotating a missing journal does nothing")
+
+	// "aaaa\n" and "bbbb\n" fill the 10 bytes, so "cccc" starts a new file.
+	for _, record := range []string{"aaaa", "bbbb", "cccc"} {
+		require.NoError(t, journal.Append([]byte(record)))
+	}
+	require.Equal(t, []string{path + ".1"}, rotated)
+	require.Equal(t, "aaaa\nbbbb\n", readJournal(t, path+".1"))
+	require.Equal(t, "cccc\n", readJournal(t, path))
+
+	name, err = journal.Rotate()
+	require.NoError(t, err)
+	require.Equal(t, path+".2", name)
+	require.NoFileExists(t, path)
+	require.Equal(t, "cccc\n", readJournal(t, name))
+
+	failing, err := fileutils.NewJournal(path, fileutils.JournalOptions{
+		OnRotate: func(string) error { return errors.New("upload failed") },
+	})
+	require.NoError(t, err)
+	require.NoError(t, failing.Append([]byte("dddd")))
+	_, err = failing.Rotate()
+	require.ErrorContains(t, err, "upload failed")
+}
+
+func readJournal(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
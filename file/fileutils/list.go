@@ -0,0 +1,209 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ListROptions controls which entries ListRWithOptions returns.
+//
+// **Attributes:**
+//
+// Glob: Optional pattern entries must match. A pattern without a path
+// separator is matched against the entry's base name (e.g., "*.go"). A
+// pattern with separators is matched against the path relative to the
+// listed directory, and "**" matches any number of directories (e.g.,
+// "cmd/**/*.go").
+// MaxDepth: Maximum depth to descend to, where 1 lists only the entries
+// of the directory itself. 0 means no limit.
+// IncludeDirs: Whether directories are included in the results.
+// FollowSymlinks: Whether symlinked directories are descended into.
+// Symlinks that point to a directory being walked are not followed, so
+// symlink loops are not an issue.
+type ListROptions struct {
+	Glob           string
+	MaxDepth       int
+	IncludeDirs    bool
+	FollowSymlinks bool
+}
+
+// ListRWithOptions recursively lists the entries of a directory that
+// match the input options.
+//
+// **Parameters:**
+//
+// dirPath: String representing the path to the directory.
+// opts: ListROptions controlling matching, depth, and traversal.
+//
+// **Returns:**
+//
+// []string: Full paths of the matching entries, in lexical walk order.
+// error: An error if the glob is invalid or the directory can't be read.
+func ListRWithOptions(dirPath string, opts ListROptions) ([]string, error) {
+	return ListRWithOptionsFs(osFs, dirPath, opts)
+}
+
+// ListRWithOptionsFs is like ListRWithOptions, but operates on the input
+// afero.Fs. Symlinks are only followed on filesystems that report them,
+// such as the OS filesystem.
+//
+// **Parameters:**
+//
+// fs: An afero.Fs instance representing the filesystem.
+// dirPath: String representing the path to the directory.
+// opts: ListROptions controlling matching, depth, and traversal.
+//
+// **Returns:**
+//
+// []string: Full paths of the matching entries, in lexical walk order.
+// error: An error if the glob is invalid or the directory can't be read.
+func ListRWithOptionsFs(fs afero.Fs, dirPath string, opts ListROptions) ([]string, error) {
+	if opts.Glob != "" {
+		if _, err := matchGlob(opts.Glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %v", opts.Glob, err)
+		}
+	}
+
+	info, err := fs.Stat(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dirPath)
+	}
+
+	ancestors := make(map[string]bool)
+	if real, err := resolvePath(fs, dirPath); err == nil {
+		ancestors[real] = true
+	}
+
+	var results []string
+	if err := listR(fs, dirPath, "", 1, opts, ancestors, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// listR appends the matching entries below dir to results. ancestors
+// holds the resolved paths of the directories being walked and is used
+// to avoid following symlink loops.
+func listR(fs afero.Fs, dir, rel string, depth int, opts ListROptions, ancestors map[string]bool, results *[]string) error {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fullPath := filepath.Join(dir, entry.Name())
+		relPath := path.Join(rel, entry.Name())
+
+		isDir := entry.IsDir()
+		descend := isDir
+		var real string
+		if isDir && opts.FollowSymlinks {
+			real, _ = resolvePath(fs, fullPath)
+		}
+		if entry.Mode()&os.ModeSymlink != 0 && opts.FollowSymlinks {
+			if info, err := fs.Stat(fullPath); err == nil && info.IsDir() {
+				isDir = true
+				real, err = resolvePath(fs, fullPath)
+				descend = err == nil && !ancestors[real]
+			}
+		}
+
+		if !isDir || opts.IncludeDirs {
+			matched := true
+			if opts.Glob != "" {
+				matched, _ = matchGlob(opts.Glob, relPath)
+			}
+			if matched {
+				*results = append(*results, fullPath)
+			}
+		}
+
+		if descend && (opts.MaxDepth <= 0 || depth < opts.MaxDepth) {
+			if real != "" {
+				ancestors[real] = true
+			}
+			err := listR(fs, fullPath, relPath, depth+1, opts, ancestors, results)
+			delete(ancestors, real)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolvePath returns the path p refers to once symlinks are resolved.
+// Only the OS filesystem resolves every symlink along the path; other
+// filesystems resolve p itself if it's a link.
+func resolvePath(fs afero.Fs, p string) (string, error) {
+	if _, ok := fs.(*afero.OsFs); ok {
+		return filepath.EvalSymlinks(p)
+	}
+
+	if lr, ok := fs.(afero.LinkReader); ok {
+		if target, err := lr.ReadlinkIfPossible(p); err == nil {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(p), target)
+			}
+			return filepath.Clean(target), nil
+		}
+	}
+
+	return filepath.Clean(p), nil
+}
+
+// matchGlob reports whether a slash-separated relative path matches a
+// glob pattern. Patterns without a separator only match the base name,
+// and "**" segments match zero or more directories.
+func matchGlob(pattern, relPath string) (bool, error) {
+	pattern = filepath.ToSlash(pattern)
+	relPath = filepath.ToSlash(relPath)
+
+	if !strings.Contains(pattern, "/") {
+		return path.Match(pattern, path.Base(relPath))
+	}
+
+	// Validate every segment so bad patterns are reported even when an
+	// early segment already rules out a match.
+	patternParts := strings.Split(pattern, "/")
+	for _, part := range patternParts {
+		if _, err := path.Match(part, ""); err != nil {
+			return false, err
+		}
+	}
+
+	return matchSegments(patternParts, strings.Split(relPath, "/")), nil
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
+}
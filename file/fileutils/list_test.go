@@ -0,0 +1,122 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+// createListTree creates the following tree under a temporary directory:
+//
+//	a.go
+//	b.txt
+//	cmd/main.go
+//	cmd/tool/tool.go
+//	link -> cmd (symlink)
+func createListTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, file := range []string{"a.go", "b.txt", "cmd/main.go", "cmd/tool/tool.go"} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package main"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(root, "cmd"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	// A symlink loop, which must not be followed forever.
+	if err := os.Symlink(root, filepath.Join(root, "cmd", "tool", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+func TestListRWithOptions(t *testing.T) {
+	root := createListTree(t)
+
+	testCases := []struct {
+		name    string
+		opts    fileutils.ListROptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "All files recursively",
+			want: []string{"a.go", "b.txt", "cmd/main.go", "cmd/tool/loop", "cmd/tool/tool.go", "link"},
+		},
+		{
+			name: "Base name glob",
+			opts: fileutils.ListROptions{Glob: "*.go"},
+			want: []string{"a.go", "cmd/main.go", "cmd/tool/tool.go"},
+		},
+		{
+			name: "Double star glob",
+			opts: fileutils.ListROptions{Glob: "cmd/**/*.go"},
+			want: []string{"cmd/main.go", "cmd/tool/tool.go"},
+		},
+		{
+			name: "Max depth",
+			opts: fileutils.ListROptions{MaxDepth: 2, IncludeDirs: true},
+			want: []string{"a.go", "b.txt", "cmd", "cmd/main.go", "cmd/tool", "link"},
+		},
+		{
+			name: "Follow symlinks without looping",
+			opts: fileutils.ListROptions{Glob: "*.go", FollowSymlinks: true},
+			want: []string{"a.go", "cmd/main.go", "cmd/tool/tool.go", "link/main.go", "link/tool/tool.go"},
+		},
+		{
+			name:    "Invalid glob",
+			opts:    fileutils.ListROptions{Glob: "[a-"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fileutils.ListRWithOptions(root, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ListRWithOptions() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			var want []string
+			for _, rel := range tc.want {
+				want = append(want, filepath.Join(root, filepath.FromSlash(rel)))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ListRWithOptions() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSeekAndDestroyRecursive(t *testing.T) {
+	root := createListTree(t)
+
+	if err := fileutils.SeekAndDestroy(root, "tool"); err != nil {
+		t.Fatalf("SeekAndDestroy() failed: %v", err)
+	}
+	if err := fileutils.SeekAndDestroy(root, "*.txt"); err != nil {
+		t.Fatalf("SeekAndDestroy() failed: %v", err)
+	}
+
+	got, err := fileutils.ListR(root)
+	if err != nil {
+		t.Fatalf("ListR() failed: %v", err)
+	}
+	want := []string{filepath.Join(root, "a.go"), filepath.Join(root, "cmd", "main.go"), filepath.Join(root, "link")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("remaining files = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrLockTimeout is returned by TryLockFile when the lock is still held
+// by someone else once the timeout expires.
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
+// errLockHeld is returned by the platform implementations of tryLock when
+// the lock is held elsewhere.
+var errLockHeld = errors.New("file lock is held")
+
+// lockRetryInterval is how often TryLockFile retries a held lock.
+const lockRetryInterval = 10 * time.Millisecond
+
+// LockFile acquires an exclusive advisory lock on the file at path,
+// blocking until it's available. The file is created if it doesn't exist.
+// Locks coordinate both separate processes and goroutines within a
+// process, as each call opens its own handle to the file. To guard a
+// directory, lock a file inside it (e.g., "cache/.lock"). Locks use flock
+// on Unix and LockFileEx on Windows.
+//
+// **Parameters:**
+//
+// path: String representing the path to the lock file.
+//
+// **Returns:**
+//
+// func() error: A function that releases the lock. It's safe to call more
+// than once.
+// error: An error if the file can't be opened or locked.
+func LockFile(path string) (func() error, error) {
+	f, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lock(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %v", path, err)
+	}
+
+	return unlockFunc(f), nil
+}
+
+// TryLockFile acquires an exclusive advisory lock on the file at path
+// like LockFile, but gives up once timeout has elapsed.
+//
+// **Parameters:**
+//
+// path: String representing the path to the lock file.
+// timeout: The maximum time to wait for the lock. A timeout of 0 makes a
+// single attempt.
+//
+// **Returns:**
+//
+// func() error: A function that releases the lock. It's safe to call more
+// than once.
+// error: ErrLockTimeout if the lock is still held when the timeout
+// expires, or another error if the file can't be opened or locked.
+func TryLockFile(path string, timeout time.Duration) (func() error, error) {
+	f, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			return unlockFunc(f), nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %v", path, err)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, path)
+		}
+		time.Sleep(min(lockRetryInterval, remaining))
+	}
+}
+
+// openLockFile opens the lock file at path, creating it if needed.
+func openLockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %v", path, err)
+	}
+	return f, nil
+}
+
+// unlockFunc returns a function that releases the lock held on f and
+// closes it.
+func unlockFunc(f *os.File) func() error {
+	var once sync.Once
+	var err error
+	return func() error {
+		once.Do(func() {
+			err = unlock(f)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		})
+		return err
+	}
+}
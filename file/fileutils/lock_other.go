@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package file
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+var errLockUnsupported = errors.New("file locking is not supported on " + runtime.GOOS)
+
+func lock(f *os.File) error {
+	return errLockUnsupported
+}
+
+func tryLock(f *os.File) error {
+	return errLockUnsupported
+}
+
+func unlock(f *os.File) error {
+	return errLockUnsupported
+}
@@ -0,0 +1,110 @@
+package file_test
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	// Count how many goroutines hold the lock at once, which must never
+	// be more than one.
+	var acquired, maxHolders, holders int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := fileutils.LockFile(path)
+			if err != nil {
+				t.Errorf("LockFile() failed: %v", err)
+				return
+			}
+
+			mu.Lock()
+			holders++
+			acquired++
+			maxHolders = max(maxHolders, holders)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+
+			if err := unlock(); err != nil {
+				t.Errorf("unlock failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 8, acquired)
+	require.Equal(t, 1, maxHolders)
+}
+
+func TestTryLockFile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		held        bool
+		releaseHeld time.Duration
+		timeout     time.Duration
+		wantErr     error
+	}{
+		{
+			name:    "Free lock",
+			timeout: 0,
+		},
+		{
+			name:    "Held lock times out",
+			held:    true,
+			timeout: 50 * time.Millisecond,
+			wantErr: fileutils.ErrLockTimeout,
+		},
+		{
+			name:    "Held lock with single attempt",
+			held:    true,
+			timeout: 0,
+			wantErr: fileutils.ErrLockTimeout,
+		},
+		{
+			name:        "Lock released before timeout",
+			held:        true,
+			releaseHeld: 50 * time.Millisecond,
+			timeout:     5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".lock")
+
+			if tc.held {
+				unlock, err := fileutils.LockFile(path)
+				require.NoError(t, err)
+				defer unlock()
+				if tc.releaseHeld > 0 {
+					time.AfterFunc(tc.releaseHeld, func() { _ = unlock() })
+				}
+			}
+
+			unlock, err := fileutils.TryLockFile(path, tc.timeout)
+			if tc.wantErr != nil {
+				require.True(t, errors.Is(err, tc.wantErr), "TryLockFile() error = %v, want %v", err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, unlock())
+			require.NoError(t, unlock(), "unlock should be idempotent")
+		})
+	}
+}
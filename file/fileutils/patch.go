@@ -0,0 +1,163 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReplaceBytesOptions configures how ReplaceBytes patches a file.
+//
+// **Attributes:**
+//
+// Pad: When true, a replacement shorter than the find pattern is padded
+// with PadByte up to the pattern's length. When false, both must be the
+// same length.
+// PadByte: Byte used for padding (defaults to 0x00).
+// Count: Maximum number of occurrences to replace. 0 replaces all of them.
+// SkipBackup: When true, no backup of the original file is written.
+// BackupSuffix: Suffix appended to the path of the backup file
+// (defaults to ".bak").
+type ReplaceBytesOptions struct {
+	Pad          bool
+	PadByte      byte
+	Count        int
+	SkipBackup   bool
+	BackupSuffix string
+}
+
+// ReplaceBytes replaces occurrences of a byte pattern inside a file
+// without changing the file's size, which makes it safe to use on
+// binaries with embedded strings or paths. Unless disabled, the original
+// file is copied to a backup before it is modified, and the patched
+// contents are written atomically by renaming a temporary file over the
+// original.
+//
+// **Parameters:**
+//
+// path: Path to the file to patch.
+// find: Byte pattern to search for.
+// replace: Bytes to write in place of each occurrence of find.
+// opts: ReplaceBytesOptions controlling padding, count, and backups.
+//
+// **Returns:**
+//
+// int: The number of occurrences replaced.
+// error: An error if the lengths are incompatible or the file cannot be
+// read, backed up, or written.
+func ReplaceBytes(path string, find, replace []byte, opts ReplaceBytesOptions) (int, error) {
+	if len(find) == 0 {
+		return 0, fmt.Errorf("find pattern cannot be empty")
+	}
+
+	patch, err := padReplacement(find, replace, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %v", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	count := 0
+	for offset := 0; offset < len(data); {
+		idx := bytes.Index(data[offset:], find)
+		if idx == -1 {
+			break
+		}
+		copy(data[offset+idx:], patch)
+		offset += idx + len(find)
+		count++
+		if opts.Count > 0 && count >= opts.Count {
+			break
+		}
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	if !opts.SkipBackup {
+		suffix := opts.BackupSuffix
+		if suffix == "" {
+			suffix = ".bak"
+		}
+		if err := copyFileContents(path, path+suffix, info.Mode().Perm()); err != nil {
+			return 0, fmt.Errorf("failed to back up %s: %v", path, err)
+		}
+	}
+
+	if err := writeFileAtomic(path, data, info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// padReplacement validates the length of replace against find and
+// pads it when requested.
+func padReplacement(find, replace []byte, opts ReplaceBytesOptions) ([]byte, error) {
+	switch {
+	case len(replace) > len(find):
+		return nil, fmt.Errorf("replacement (%d bytes) is longer than the find pattern (%d bytes)",
+			len(replace), len(find))
+	case len(replace) < len(find) && !opts.Pad:
+		return nil, fmt.Errorf("replacement (%d bytes) must be the same length as the find pattern (%d bytes) when padding is disabled",
+			len(replace), len(find))
+	}
+
+	patch := make([]byte, len(find))
+	copy(patch, replace)
+	for i := len(replace); i < len(patch); i++ {
+		patch[i] = opts.PadByte
+	}
+
+	return patch, nil
+}
+
+func copyFileContents(src, dst string, perm os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, data, perm)
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %v", path, err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write temp file for %s: %v", path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close temp file for %s: %v", path, err)
+	}
+
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to set permissions on %s: %v", tmpName, err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace %s: %v", path, err)
+	}
+
+	return nil
+}
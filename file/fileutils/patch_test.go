@@ -0,0 +1,116 @@
+package file_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+func TestReplaceBytes(t *testing.T) {
+	original := []byte("\x7fELF\x00/usr/local/lib\x00data\x00/usr/local/lib\x00")
+
+	testCases := []struct {
+		name      string
+		find      []byte
+		replace   []byte
+		opts      fileutils.ReplaceBytesOptions
+		wantCount int
+		want      []byte
+		wantErr   bool
+	}{
+		{
+			name:      "Exact length replacement of all occurrences",
+			find:      []byte("/usr/local/lib"),
+			replace:   []byte("/opt/tools/lib"),
+			wantCount: 2,
+			want:      []byte("\x7fELF\x00/opt/tools/lib\x00data\x00/opt/tools/lib\x00"),
+		},
+		{
+			name:      "Padded replacement limited by count",
+			find:      []byte("/usr/local/lib"),
+			replace:   []byte("/lib"),
+			opts:      fileutils.ReplaceBytesOptions{Pad: true, Count: 1},
+			wantCount: 1,
+			want:      []byte("\x7fELF\x00/lib\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00data\x00/usr/local/lib\x00"),
+		},
+		{
+			name:    "Shorter replacement without padding",
+			find:    []byte("/usr/local/lib"),
+			replace: []byte("/lib"),
+			wantErr: true,
+		},
+		{
+			name:    "Longer replacement",
+			find:    []byte("data"),
+			replace: []byte("longer"),
+			wantErr: true,
+		},
+		{
+			name:    "Empty find pattern",
+			find:    []byte{},
+			replace: []byte{},
+			wantErr: true,
+		},
+		{
+			name:      "Pattern not present",
+			find:      []byte("missing"),
+			replace:   []byte("MISSING"),
+			wantCount: 0,
+			want:      original,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "binary")
+			if err := os.WriteFile(path, original, 0755); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			count, err := fileutils.ReplaceBytes(path, tc.find, tc.replace, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ReplaceBytes() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if count != tc.wantCount {
+				t.Errorf("expected %d replacements, got %d", tc.wantCount, count)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read patched file: %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("unexpected contents: got %q, want %q", got, tc.want)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("failed to stat patched file: %v", err)
+			}
+			if info.Mode().Perm() != 0755 {
+				t.Errorf("expected permissions to be preserved, got %v", info.Mode().Perm())
+			}
+
+			backup, err := os.ReadFile(path + ".bak")
+			if tc.wantCount == 0 {
+				if err == nil {
+					t.Error("expected no backup when nothing was replaced")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read backup: %v", err)
+			}
+			if !bytes.Equal(backup, original) {
+				t.Errorf("backup does not match the original contents")
+			}
+		})
+	}
+}
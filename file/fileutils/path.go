@@ -0,0 +1,87 @@
+package file
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ToSlashAll returns path with every backslash and forward slash
+// replaced by a forward slash, regardless of the current platform.
+// Unlike filepath.ToSlash, which only replaces the separator of the
+// current platform, it also converts Windows paths on other platforms,
+// such as paths read from a config file written on Windows.
+//
+// **Parameters:**
+//
+// path: The path to convert.
+//
+// **Returns:**
+//
+// string: The path with forward slashes as separators.
+func ToSlashAll(path string) string {
+	return strings.ReplaceAll(path, `\`, "/")
+}
+
+// FromSlashAll returns path with every backslash and forward slash
+// replaced by the separator of the current platform. Note that on
+// platforms other than Windows, a backslash is a valid file name
+// character that this function will treat as a separator.
+//
+// **Parameters:**
+//
+// path: The path to convert.
+//
+// **Returns:**
+//
+// string: The path with the platform's separators.
+func FromSlashAll(path string) string {
+	return filepath.FromSlash(ToSlashAll(path))
+}
+
+// CaseInsensitiveFS reports whether the default filesystem of the current
+// platform is case-insensitive, which is the case on Windows (NTFS) and
+// macOS (APFS and HFS+). Individual volumes can be configured otherwise.
+var CaseInsensitiveFS = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+// NormalizeCase returns path lowercased if the platform's filesystem is
+// case-insensitive (see CaseInsensitiveFS), and unchanged otherwise, so
+// that paths referring to the same file compare equal, such as when
+// they're used as map keys.
+//
+// **Parameters:**
+//
+// path: The path to normalize.
+//
+// **Returns:**
+//
+// string: The normalized path.
+func NormalizeCase(path string) string {
+	if CaseInsensitiveFS {
+		return strings.ToLower(path)
+	}
+
+	return path
+}
+
+// IsHidden reports whether the file at path is hidden: its name starts
+// with a dot, or, on Windows, it has the hidden attribute set. Dotfiles
+// are reported as hidden on every platform, because Windows tools such
+// as git and most editors treat them as hidden too.
+//
+// **Parameters:**
+//
+// path: The path to the file.
+//
+// **Returns:**
+//
+// bool: True if the file is hidden.
+// error: An error if the file's attributes can't be read on Windows.
+func IsHidden(path string) (bool, error) {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") && name != "." && name != ".." {
+		return true, nil
+	}
+
+	return hasHiddenAttribute(path)
+}
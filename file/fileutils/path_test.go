@@ -0,0 +1,87 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+func TestToSlashAll(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "Windows path", path: `C:\Users\dev\go\bin`, want: "C:/Users/dev/go/bin"},
+		{name: "Mixed separators", path: `dist\linux/amd64\tool`, want: "dist/linux/amd64/tool"},
+		{name: "Already slashed", path: "dist/tool", want: "dist/tool"},
+		{name: "Empty", path: "", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := fileutils.ToSlashAll(tc.path); got != tc.want {
+				t.Errorf("ToSlashAll(%q) = %q, want %q", tc.path, got, tc.want)
+			}
+
+			want := strings.ReplaceAll(tc.want, "/", string(filepath.Separator))
+			if got := fileutils.FromSlashAll(tc.path); got != want {
+				t.Errorf("FromSlashAll(%q) = %q, want %q", tc.path, got, want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCase(t *testing.T) {
+	got := fileutils.NormalizeCase("Build/Output.TXT")
+	want := "Build/Output.TXT"
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		want = "build/output.txt"
+	}
+	if got != want {
+		t.Errorf("NormalizeCase() = %q, want %q", got, want)
+	}
+}
+
+func TestIsHidden(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", "visible.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "Dotfile", path: filepath.Join(dir, ".env"), want: true},
+		{name: "Dot directory", path: filepath.Join(dir, ".git") + string(filepath.Separator), want: true},
+		{name: "Visible file", path: filepath.Join(dir, "visible.txt")},
+		{name: "Current directory", path: dir},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fileutils.IsHidden(tc.path)
+			if err != nil {
+				t.Fatalf("IsHidden() failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("IsHidden(%q) = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+
+	// File attributes are only read on Windows.
+	if runtime.GOOS == "windows" {
+		if _, err := fileutils.IsHidden(filepath.Join(dir, "missing.txt")); err == nil {
+			t.Error("IsHidden() expected an error for a missing file")
+		}
+	}
+}
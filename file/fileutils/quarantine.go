@@ -0,0 +1,165 @@
+package file
+
+import (
+	"bufio"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// QuarantineXattr is the extended attribute macOS Gatekeeper checks
+	// before running a downloaded file.
+	QuarantineXattr = "com.apple.quarantine"
+	// ZoneIdentifierStream is the alternate data stream holding the
+	// Windows Mark-of-the-Web of a downloaded file.
+	ZoneIdentifierStream = "Zone.Identifier"
+)
+
+// Quarantine describes the marker that macOS and Windows attach to
+// downloaded files, which prevents or warns about running them.
+//
+// **Attributes:**
+//
+// Raw: The raw value of the com.apple.quarantine attribute or
+// Zone.Identifier stream.
+// Agent: The application that downloaded the file. macOS only.
+// Time: When the file was downloaded. macOS only; zero if unknown.
+// ZoneID: The security zone the file came from, e.g., 3 for the
+// Internet. Windows only; -1 if unknown.
+// ReferrerURL: The page the file was downloaded from. Windows only.
+// HostURL: The URL the file was downloaded from. Windows only.
+type Quarantine struct {
+	Raw         string
+	Agent       string
+	Time        time.Time
+	ZoneID      int
+	ReferrerURL string
+	HostURL     string
+}
+
+// ParseQuarantine parses the value of a com.apple.quarantine attribute,
+// e.g., "0083;65a1b2c3;Safari;", or of a Zone.Identifier stream.
+// Fields that can't be parsed are left empty.
+//
+// **Parameters:**
+//
+// raw: The value to parse.
+//
+// **Returns:**
+//
+// Quarantine: The parsed marker.
+func ParseQuarantine(raw string) Quarantine {
+	q := Quarantine{Raw: raw, ZoneID: -1}
+
+	if strings.HasPrefix(strings.TrimPrefix(raw, "\ufeff"), "[ZoneTransfer]") {
+		scanner := bufio.NewScanner(strings.NewReader(raw))
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "ZoneId":
+				if id, err := strconv.Atoi(value); err == nil {
+					q.ZoneID = id
+				}
+			case "ReferrerUrl":
+				q.ReferrerURL = value
+			case "HostUrl":
+				q.HostURL = value
+			}
+		}
+		return q
+	}
+
+	// flags;hex timestamp;agent;event UUID
+	fields := strings.Split(strings.TrimRight(raw, "\x00"), ";")
+	if len(fields) > 1 {
+		if sec, err := strconv.ParseInt(fields[1], 16, 64); err == nil && sec > 0 {
+			q.Time = time.Unix(sec, 0)
+		}
+	}
+	if len(fields) > 2 {
+		q.Agent = fields[2]
+	}
+
+	return q
+}
+
+// GetQuarantine returns the quarantine marker of a file: its
+// com.apple.quarantine attribute on macOS, or its Zone.Identifier
+// stream on Windows. Other platforms don't mark downloads, so no marker
+// is ever found there.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// *Quarantine: The marker, or nil if the file isn't quarantined.
+// error: An error if the marker can't be read.
+func GetQuarantine(path string) (*Quarantine, error) {
+	raw, found, err := getQuarantine(path)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	q := ParseQuarantine(raw)
+	return &q, nil
+}
+
+// RemoveQuarantine removes the quarantine marker of a file, so that a
+// downloaded binary can be run without Gatekeeper or SmartScreen
+// blocking it. Only call it for files from trusted sources, e.g., after
+// verifying their checksum. It does nothing on platforms that don't
+// mark downloads, so it can be called unconditionally.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// bool: True if a marker was removed.
+// error: An error if the marker can't be removed.
+func RemoveQuarantine(path string) (bool, error) {
+	return removeQuarantine(path)
+}
+
+// RemoveQuarantineTree removes the quarantine markers of a directory
+// and everything in it, e.g., the files extracted from a downloaded
+// archive, which inherit the archive's marker. See RemoveQuarantine.
+// Symbolic links aren't followed.
+//
+// **Parameters:**
+//
+// root: String representing the path to the directory.
+//
+// **Returns:**
+//
+// int: The number of markers removed.
+// error: An error if the tree can't be walked or a marker can't be
+// removed.
+func RemoveQuarantineTree(root string) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		ok, err := removeQuarantine(path)
+		if ok {
+			removed++
+		}
+		return err
+	})
+
+	return removed, err
+}
@@ -0,0 +1,33 @@
+//go:build darwin
+
+package file
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+func getQuarantine(path string) (string, bool, error) {
+	value, err := getXattr(path, QuarantineXattr)
+	if errors.Is(err, unix.ENOATTR) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(value), true, nil
+}
+
+func removeQuarantine(path string) (bool, error) {
+	err := removeXattr(path, QuarantineXattr)
+	if errors.Is(err, unix.ENOATTR) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,25 @@
+//go:build !darwin && !windows
+
+package file
+
+import (
+	"fmt"
+	"os"
+)
+
+// getQuarantine finds no marker, since this platform doesn't mark
+// downloaded files.
+func getQuarantine(path string) (string, bool, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return "", false, fmt.Errorf("failed to get quarantine of %s: %w", path, err)
+	}
+
+	return "", false, nil
+}
+
+// removeQuarantine does nothing, since this platform doesn't mark
+// downloaded files.
+func removeQuarantine(path string) (bool, error) {
+	_, _, err := getQuarantine(path)
+	return false, err
+}
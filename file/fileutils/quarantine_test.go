@@ -0,0 +1,94 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+func TestParseQuarantine(t *testing.T) {
+	testCases := []struct {
+		name string
+		raw  string
+		want fileutils.Quarantine
+	}{
+		{
+			name: "macOS attribute",
+			raw:  "0083;65a1b2c3;Safari;2F7C5F4E-1B2A-4C3D-9E8F-0A1B2C3D4E5F",
+			want: fileutils.Quarantine{Agent: "Safari", Time: time.Unix(0x65a1b2c3, 0), ZoneID: -1},
+		},
+		{
+			name: "macOS attribute without agent",
+			raw:  "0081;00000000;;",
+			want: fileutils.Quarantine{ZoneID: -1},
+		},
+		{
+			name: "Zone.Identifier",
+			raw:  "[ZoneTransfer]\r\nZoneId=3\r\nReferrerUrl=https://example.com/\r\nHostUrl=https://example.com/tool.exe\r\n",
+			want: fileutils.Quarantine{
+				ZoneID: 3, ReferrerURL: "https://example.com/", HostURL: "https://example.com/tool.exe",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fileutils.ParseQuarantine(tc.raw)
+			tc.want.Raw = tc.raw
+			if got.Raw != tc.want.Raw || got.Agent != tc.want.Agent || !got.Time.Equal(tc.want.Time) ||
+				got.ZoneID != tc.want.ZoneID || got.ReferrerURL != tc.want.ReferrerURL || got.HostURL != tc.want.HostURL {
+				t.Errorf("ParseQuarantine() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRemoveQuarantine(t *testing.T) {
+	root := t.TempDir()
+	binDir := filepath.Join(root, "bin")
+	if err := os.Mkdir(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tool := filepath.Join(binDir, "tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	marked := false
+	switch runtime.GOOS {
+	case "darwin":
+		marked = fileutils.SetXattr(tool, fileutils.QuarantineXattr, []byte("0083;65a1b2c3;curl;")) == nil
+	case "windows":
+		marked = os.WriteFile(tool+":"+fileutils.ZoneIdentifierStream, []byte("[ZoneTransfer]\r\nZoneId=3\r\n"), 0644) == nil
+	}
+
+	q, err := fileutils.GetQuarantine(tool)
+	if err != nil {
+		t.Fatalf("GetQuarantine() failed: %v", err)
+	}
+	if marked != (q != nil) {
+		t.Fatalf("GetQuarantine() = %+v, want a marker: %v", q, marked)
+	}
+
+	removed, err := fileutils.RemoveQuarantineTree(root)
+	if err != nil {
+		t.Fatalf("RemoveQuarantineTree() failed: %v", err)
+	}
+	if want := map[bool]int{true: 1}[marked]; removed != want {
+		t.Errorf("RemoveQuarantineTree() = %d, want %d", removed, want)
+	}
+
+	if q, err := fileutils.GetQuarantine(tool); err != nil || q != nil {
+		t.Errorf("GetQuarantine() after removal = %+v, %v, want no marker", q, err)
+	}
+	if ok, err := fileutils.RemoveQuarantine(tool); ok || err != nil {
+		t.Errorf("RemoveQuarantine() of an unmarked file = %v, %v, want false", ok, err)
+	}
+	if _, err := fileutils.RemoveQuarantine(filepath.Join(root, "missing")); err == nil {
+		t.Error("RemoveQuarantine() expected an error for a missing file")
+	}
+}
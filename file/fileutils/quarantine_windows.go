@@ -0,0 +1,34 @@
+//go:build windows
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+func getQuarantine(path string) (string, bool, error) {
+	data, err := os.ReadFile(path + ":" + ZoneIdentifierStream)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s stream of %s: %w", ZoneIdentifierStream, path, err)
+	}
+
+	return string(data), true, nil
+}
+
+func removeQuarantine(path string) (bool, error) {
+	err := os.Remove(path + ":" + ZoneIdentifierStream)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to remove %s stream of %s: %w", ZoneIdentifierStream, path, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package file
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones src to dst with the FICLONE ioctl, creating or
+// truncating dst.
+func reflink(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd())); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to clone %s to %s: %w", src, dst, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package file
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+func reflink(src, dst string, perm os.FileMode) error {
+	return errors.New("reflinks are not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,122 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReadJSON reads a JSON file and unmarshals it into a value of type T.
+//
+// **Parameters:**
+//
+// path: String representing the path to the JSON file.
+//
+// **Returns:**
+//
+// T: The unmarshalled value.
+// error: An error if the file can't be read or isn't valid JSON for T.
+func ReadJSON[T any](path string) (T, error) {
+	var v T
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return v, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("failed to parse JSON from %s: %w", path, err)
+	}
+
+	return v, nil
+}
+
+// WriteJSON marshals a value to JSON and writes it to a file. The file is
+// replaced atomically and keeps its permissions if it already exists;
+// new files are created with 0644 permissions.
+//
+// **Parameters:**
+//
+// path: String representing the path to the JSON file.
+// v: The value to marshal.
+// indent: The indentation for each nesting level (e.g., "  "). An empty
+// string writes compact JSON.
+//
+// **Returns:**
+//
+// error: An error if the value can't be marshalled or the file can't be
+// written.
+func WriteJSON(path string, v any, indent string) error {
+	var data []byte
+	var err error
+	if indent == "" {
+		data, err = json.Marshal(v)
+	} else {
+		data, err = json.MarshalIndent(v, "", indent)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON for %s: %w", path, err)
+	}
+
+	return writeFileKeepPerm(path, append(data, '\n'))
+}
+
+// ReadYAML reads a YAML file and unmarshals it into a value of type T.
+//
+// **Parameters:**
+//
+// path: String representing the path to the YAML file.
+//
+// **Returns:**
+//
+// T: The unmarshalled value.
+// error: An error if the file can't be read or isn't valid YAML for T.
+func ReadYAML[T any](path string) (T, error) {
+	var v T
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return v, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("failed to parse YAML from %s: %w", path, err)
+	}
+
+	return v, nil
+}
+
+// WriteYAML marshals a value to YAML and writes it to a file. The file is
+// replaced atomically and keeps its permissions if it already exists;
+// new files are created with 0644 permissions.
+//
+// **Parameters:**
+//
+// path: String representing the path to the YAML file.
+// v: The value to marshal.
+//
+// **Returns:**
+//
+// error: An error if the value can't be marshalled or the file can't be
+// written.
+func WriteYAML(path string, v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML for %s: %w", path, err)
+	}
+
+	return writeFileKeepPerm(path, data)
+}
+
+// writeFileKeepPerm atomically writes data to path, preserving the
+// permissions of an existing file.
+func writeFileKeepPerm(path string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	return writeFileAtomic(path, data, perm)
+}
@@ -0,0 +1,112 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+type testConfig struct {
+	Name    string            `json:"name" yaml:"name"`
+	Port    int               `json:"port" yaml:"port"`
+	Tags    []string          `json:"tags" yaml:"tags"`
+	Options map[string]string `json:"options" yaml:"options"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	want := testConfig{Name: "api", Port: 8080, Tags: []string{"a", "b"}, Options: map[string]string{"debug": "true"}}
+
+	testCases := []struct {
+		name     string
+		indent   string
+		wantText string
+	}{
+		{
+			name:     "Compact",
+			wantText: `{"name":"api","port":8080,"tags":["a","b"],"options":{"debug":"true"}}` + "\n",
+		},
+		{
+			name:     "Indented",
+			indent:   "  ",
+			wantText: "{\n  \"name\": \"api\",\n  \"port\": 8080,\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ],\n  \"options\": {\n    \"debug\": \"true\"\n  }\n}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := fileutils.WriteJSON(path, want, tc.indent); err != nil {
+				t.Fatalf("WriteJSON() failed: %v", err)
+			}
+
+			text, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tc.wantText {
+				t.Errorf("WriteJSON() wrote %q, want %q", text, tc.wantText)
+			}
+
+			got, err := fileutils.ReadJSON[testConfig](path)
+			if err != nil {
+				t.Fatalf("ReadJSON() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadJSON() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	want := testConfig{Name: "api", Port: 8080, Tags: []string{"a", "b"}, Options: map[string]string{"debug": "true"}}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileutils.WriteYAML(path, want); err != nil {
+		t.Fatalf("WriteYAML() failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("WriteYAML() changed permissions to %v", info.Mode().Perm())
+	}
+
+	got, err := fileutils.ReadYAML[testConfig](path)
+	if err != nil {
+		t.Fatalf("ReadYAML() failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadYAML() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadSerializedErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte("{not: [valid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	for _, path := range []string{invalid, missing} {
+		if _, err := fileutils.ReadJSON[testConfig](path); err == nil {
+			t.Errorf("expected ReadJSON(%s) to fail", path)
+		}
+		if _, err := fileutils.ReadYAML[testConfig](path); err == nil {
+			t.Errorf("expected ReadYAML(%s) to fail", path)
+		}
+	}
+
+	if err := fileutils.WriteJSON(filepath.Join(dir, "bad.json"), make(chan int), ""); err == nil {
+		t.Error("expected WriteJSON() to fail for an unsupported type")
+	}
+}
@@ -0,0 +1,209 @@
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SnapshotEntry describes a single entry of a directory tree at the time
+// a snapshot was taken.
+//
+// **Attributes:**
+//
+// Path: The slash-separated path of the entry relative to the snapshot
+// root.
+// Size: The size of the entry in bytes.
+// Mode: The mode and permission bits of the entry.
+// Hash: The hex-encoded SHA-256 digest of a regular file's content. Empty
+// for other entries.
+// Target: The target of a symlink. Empty for other entries.
+type SnapshotEntry struct {
+	Path   string      `json:"path"`
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	Hash   string      `json:"hash,omitempty"`
+	Target string      `json:"target,omitempty"`
+}
+
+// Snapshot is a manifest of every entry in a directory tree. It can be
+// saved with WriteJSON and loaded with ReadJSON to compare host state
+// across runs.
+//
+// **Attributes:**
+//
+// Root: The path of the directory the snapshot was taken of.
+// Entries: The entries of the tree, sorted by path.
+type Snapshot struct {
+	Root    string          `json:"root"`
+	Entries []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotChange describes an entry present in both snapshots whose size,
+// mode, content, or symlink target differs.
+//
+// **Attributes:**
+//
+// Path: The path of the entry relative to the snapshot root.
+// Old: The entry in the first snapshot.
+// New: The entry in the second snapshot.
+type SnapshotChange struct {
+	Path string
+	Old  SnapshotEntry
+	New  SnapshotEntry
+}
+
+// SnapshotDiff holds the differences between two snapshots.
+//
+// **Attributes:**
+//
+// Added: Entries only present in the second snapshot, sorted by path.
+// Removed: Entries only present in the first snapshot, sorted by path.
+// Modified: Entries present in both snapshots that differ, sorted by
+// path.
+type SnapshotDiff struct {
+	Added    []SnapshotEntry
+	Removed  []SnapshotEntry
+	Modified []SnapshotChange
+}
+
+// Empty reports whether the snapshots compared were identical.
+//
+// **Returns:**
+//
+// bool: True if nothing was added, removed, or modified.
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// SnapshotTree records the path, size, mode, and content hash of every
+// entry below root. Symlinks are recorded rather than followed.
+//
+// **Parameters:**
+//
+// root: String representing the path to the directory to snapshot.
+//
+// **Returns:**
+//
+// *Snapshot: The manifest of the directory tree.
+// error: An error if root isn't a directory or an entry can't be read.
+func SnapshotTree(root string) (*Snapshot, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %v", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	snapshot := &Snapshot{Root: root}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		entry, err := snapshotEntry(root, path, d)
+		if err != nil {
+			return err
+		}
+		snapshot.Entries = append(snapshot.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s: %v", root, err)
+	}
+	sort.Slice(snapshot.Entries, func(i, j int) bool {
+		return snapshot.Entries[i].Path < snapshot.Entries[j].Path
+	})
+
+	return snapshot, nil
+}
+
+// snapshotEntry builds the SnapshotEntry for the entry at path.
+func snapshotEntry(root, path string, d fs.DirEntry) (SnapshotEntry, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return SnapshotEntry{}, err
+	}
+	info, err := d.Info()
+	if err != nil {
+		return SnapshotEntry{}, err
+	}
+
+	entry := SnapshotEntry{
+		Path: filepath.ToSlash(rel),
+		Size: info.Size(),
+		Mode: info.Mode(),
+	}
+	switch {
+	case info.Mode().IsRegular():
+		if entry.Hash, err = Checksum(path, SHA256); err != nil {
+			return SnapshotEntry{}, err
+		}
+	case info.Mode()&fs.ModeSymlink != 0:
+		if entry.Target, err = os.Readlink(path); err != nil {
+			return SnapshotEntry{}, err
+		}
+	case info.IsDir():
+		// Directory sizes depend on the filesystem rather than on their
+		// contents, so they aren't compared.
+		entry.Size = 0
+	}
+
+	return entry, nil
+}
+
+// CompareSnapshots returns the entries that were added, removed, or
+// modified between two snapshots, such as before and after running an
+// installer.
+//
+// **Parameters:**
+//
+// a: The earlier snapshot.
+// b: The later snapshot.
+//
+// **Returns:**
+//
+// SnapshotDiff: The differences between the snapshots.
+func CompareSnapshots(a, b *Snapshot) SnapshotDiff {
+	var diff SnapshotDiff
+
+	before := make(map[string]SnapshotEntry)
+	if a != nil {
+		for _, entry := range a.Entries {
+			before[entry.Path] = entry
+		}
+	}
+	after := make(map[string]SnapshotEntry)
+	if b != nil {
+		for _, entry := range b.Entries {
+			after[entry.Path] = entry
+		}
+	}
+
+	for path, newEntry := range after {
+		oldEntry, ok := before[path]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, newEntry)
+		case oldEntry != newEntry:
+			diff.Modified = append(diff.Modified, SnapshotChange{Path: path, Old: oldEntry, New: newEntry})
+		}
+	}
+	for path, oldEntry := range before {
+		if _, ok := after[path]; !ok {
+			diff.Removed = append(diff.Removed, oldEntry)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Path < diff.Added[j].Path })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Path < diff.Removed[j].Path })
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].Path < diff.Modified[j].Path })
+
+	return diff
+}
@@ -0,0 +1,140 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotTree(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "etc", "app.conf"), []byte("port=80"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "etc.bak"), []byte("old"), 0600))
+
+	snapshot, err := fileutils.SnapshotTree(root)
+	require.NoError(t, err)
+	require.Equal(t, root, snapshot.Root)
+
+	var paths []string
+	for _, entry := range snapshot.Entries {
+		paths = append(paths, entry.Path)
+	}
+	require.Equal(t, []string{"etc", "etc.bak", "etc/app.conf"}, paths)
+
+	conf := snapshot.Entries[2]
+	require.Equal(t, int64(7), conf.Size)
+	require.True(t, conf.Mode.IsRegular())
+	want, err := fileutils.Checksum(filepath.Join(root, "etc", "app.conf"), fileutils.SHA256)
+	require.NoError(t, err)
+	require.Equal(t, want, conf.Hash)
+
+	require.True(t, snapshot.Entries[0].Mode.IsDir())
+	require.Empty(t, snapshot.Entries[0].Hash)
+
+	_, err = fileutils.SnapshotTree(filepath.Join(root, "etc.bak"))
+	require.Error(t, err)
+	_, err = fileutils.SnapshotTree(filepath.Join(root, "missing"))
+	require.Error(t, err)
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	newRoot := func(t *testing.T) string {
+		root := t.TempDir()
+		files := map[string]string{
+			"bin/tool":      "v1",
+			"etc/tool.conf": "debug=false",
+			"var/log/x.log": "log",
+		}
+		for rel, contents := range files {
+			path := filepath.Join(root, rel)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+		}
+		return root
+	}
+
+	testCases := []struct {
+		name         string
+		change       func(t *testing.T, root string)
+		wantAdded    []string
+		wantRemoved  []string
+		wantModified []string
+	}{
+		{
+			name:   "No changes",
+			change: func(t *testing.T, root string) {},
+		},
+		{
+			name: "Added and removed files",
+			change: func(t *testing.T, root string) {
+				require.NoError(t, os.MkdirAll(filepath.Join(root, "opt"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, "opt/new"), []byte("new"), 0644))
+				require.NoError(t, os.RemoveAll(filepath.Join(root, "var")))
+			},
+			wantAdded:   []string{"opt", "opt/new"},
+			wantRemoved: []string{"var", "var/log", "var/log/x.log"},
+		},
+		{
+			name: "Content changed with the same size",
+			change: func(t *testing.T, root string) {
+				require.NoError(t, os.WriteFile(filepath.Join(root, "bin/tool"), []byte("v2"), 0644))
+			},
+			wantModified: []string{"bin/tool"},
+		},
+		{
+			name: "Permissions changed",
+			change: func(t *testing.T, root string) {
+				if runtime.GOOS == "windows" {
+					t.Skip("permission bits aren't supported on Windows")
+				}
+				require.NoError(t, os.Chmod(filepath.Join(root, "etc/tool.conf"), 0600))
+			},
+			wantModified: []string{"etc/tool.conf"},
+		},
+		{
+			name: "File replaced by a directory",
+			change: func(t *testing.T, root string) {
+				require.NoError(t, os.Remove(filepath.Join(root, "bin/tool")))
+				require.NoError(t, os.Mkdir(filepath.Join(root, "bin/tool"), 0755))
+			},
+			wantModified: []string{"bin/tool"},
+		},
+	}
+
+	paths := func(entries []fileutils.SnapshotEntry) []string {
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.Path)
+		}
+		return out
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := newRoot(t)
+			before, err := fileutils.SnapshotTree(root)
+			require.NoError(t, err)
+
+			tc.change(t, root)
+			after, err := fileutils.SnapshotTree(root)
+			require.NoError(t, err)
+
+			diff := fileutils.CompareSnapshots(before, after)
+			require.Equal(t, tc.wantAdded, paths(diff.Added))
+			require.Equal(t, tc.wantRemoved, paths(diff.Removed))
+
+			var modified []string
+			for _, change := range diff.Modified {
+				modified = append(modified, change.Path)
+				require.NotEqual(t, change.Old, change.New)
+			}
+			require.Equal(t, tc.wantModified, modified)
+			require.Equal(t, tc.wantAdded == nil && tc.wantRemoved == nil && tc.wantModified == nil, diff.Empty())
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// followPollInterval is how often Follow checks for new data and for
+// rotation of the followed file.
+var followPollInterval = 100 * time.Millisecond
+
+// Tail returns the last n lines of a file. The file is read backwards
+// in chunks, so only the end of a large file is loaded into memory.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// n: The number of lines to return.
+//
+// **Returns:**
+//
+// []string: Up to n lines, oldest first, without line endings.
+// error: An error if the file can't be read.
+func Tail(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var data []byte
+	offset := info.Size()
+	newlines := 0
+	// Read until n+1 newlines are found: a trailing newline ends the last
+	// line instead of starting a new one.
+	for offset > 0 && newlines <= n {
+		readSize := min(chunkSize, offset)
+		offset -= readSize
+
+		chunk := make([]byte, readSize)
+		if _, err := f.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		newlines += bytes.Count(chunk, []byte{'\n'})
+		data = append(chunk, data...)
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines, nil
+}
+
+// Follow streams the lines appended to a file, like `tail -f`. Only lines
+// written after Follow is called are sent. If the file is rotated (moved
+// or replaced by a new file at the same path), Follow finishes reading the
+// old file and continues from the start of the new one. If the file is
+// truncated, Follow continues from its start.
+//
+// **Parameters:**
+//
+// ctx: Context that stops following the file when canceled.
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// <-chan string: Channel receiving each appended line without its line
+// ending. It is closed once ctx is done.
+// error: An error if the file can't be opened.
+func Follow(ctx context.Context, path string) (<-chan string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	lines := make(chan string)
+	go follow(ctx, path, f, lines)
+
+	return lines, nil
+}
+
+func follow(ctx context.Context, path string, f *os.File, lines chan<- string) {
+	defer close(lines)
+	defer func() { f.Close() }()
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	var partial []byte
+	buf := make([]byte, 32*1024)
+
+	// emit sends every complete line in partial, keeping the remainder.
+	emit := func() bool {
+		for {
+			i := bytes.IndexByte(partial, '\n')
+			if i < 0 {
+				return true
+			}
+			line := strings.TrimSuffix(string(partial[:i]), "\r")
+			partial = partial[i+1:]
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+
+	for {
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				partial = append(partial, buf[:n]...)
+				if !emit() {
+					return
+				}
+			}
+			if n == 0 || err != nil {
+				break
+			}
+		}
+
+		pathInfo, pathErr := os.Stat(path)
+		fileInfo, fileErr := f.Stat()
+		if pathErr == nil && fileErr == nil {
+			switch pos, _ := f.Seek(0, io.SeekCurrent); {
+			case !os.SameFile(pathInfo, fileInfo):
+				// Rotated: the old file has been drained, so flush any
+				// unterminated last line and switch to the new file.
+				if newFile, err := os.Open(path); err == nil {
+					if len(partial) > 0 {
+						partial = append(partial, '\n')
+						if !emit() {
+							newFile.Close()
+							return
+						}
+					}
+					f.Close()
+					f = newFile
+				}
+			case pathInfo.Size() < pos:
+				// Truncated: continue from the start of the file.
+				partial = nil
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package file_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+func TestTail(t *testing.T) {
+	var long strings.Builder
+	for i := 1; i <= 5000; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+
+	testCases := []struct {
+		name     string
+		contents string
+		n        int
+		want     []string
+	}{
+		{name: "Fewer lines than requested", contents: "a\nb\n", n: 5, want: []string{"a", "b"}},
+		{name: "Last lines", contents: "a\nb\nc\nd\n", n: 2, want: []string{"c", "d"}},
+		{name: "No trailing newline", contents: "a\nb\nc", n: 2, want: []string{"b", "c"}},
+		{name: "CRLF line endings", contents: "a\r\nb\r\n", n: 1, want: []string{"b"}},
+		{name: "Spans multiple chunks", contents: long.String(), n: 3, want: []string{"line 4998", "line 4999", "line 5000"}},
+		{name: "Empty file", contents: "", n: 3},
+		{name: "Zero lines", contents: "a\n", n: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "log.txt")
+			if err := os.WriteFile(path, []byte(tc.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fileutils.Tail(path, tc.n)
+			if err != nil {
+				t.Fatalf("Tail() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Tail() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := fileutils.Tail(filepath.Join(t.TempDir(), "missing"), 1); err == nil {
+		t.Error("expected Tail() to fail for a missing file")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("existing line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lines, err := fileutils.Follow(ctx, path)
+	if err != nil {
+		t.Fatalf("Follow() failed: %v", err)
+	}
+
+	appendTo := func(p, text string) {
+		f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(want ...string) {
+		t.Helper()
+		for _, w := range want {
+			select {
+			case got := <-lines:
+				if got != w {
+					t.Fatalf("Follow() sent %q, want %q", got, w)
+				}
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for %q", w)
+			}
+		}
+	}
+
+	appendTo(path, "first\nsec")
+	appendTo(path, "ond\n")
+	expect("first", "second")
+
+	// Rotate: the unterminated last line of the old file is flushed, and
+	// lines written to the new file are followed.
+	appendTo(path, "last old")
+	time.Sleep(300 * time.Millisecond)
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendTo(path, "new file\n")
+	expect("last old", "new file")
+
+	// Truncate: following continues from the start of the file.
+	time.Sleep(300 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("after\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect("after")
+
+	cancel()
+	for range lines {
+	}
+
+	if _, err := fileutils.Follow(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected Follow() to fail for a missing file")
+	}
+}
@@ -0,0 +1,67 @@
+package file
+
+// GetXattr returns the value of an extended attribute of a file. On
+// Linux, user-defined attributes must be in the "user." namespace.
+// Extended attributes are supported on Linux, macOS, FreeBSD, and NetBSD;
+// use ListADS on Windows.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// name: The name of the extended attribute.
+//
+// **Returns:**
+//
+// []byte: The value of the extended attribute.
+// error: An error if the attribute doesn't exist, can't be read, or
+// extended attributes are unsupported on this platform.
+func GetXattr(path, name string) ([]byte, error) {
+	return getXattr(path, name)
+}
+
+// SetXattr sets an extended attribute of a file, creating or replacing
+// it as needed.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// name: The name of the extended attribute.
+// value: The value to store.
+//
+// **Returns:**
+//
+// error: An error if the attribute can't be set or extended attributes
+// are unsupported on this platform.
+func SetXattr(path, name string, value []byte) error {
+	return setXattr(path, name, value)
+}
+
+// RemoveXattr removes an extended attribute from a file.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+// name: The name of the extended attribute.
+//
+// **Returns:**
+//
+// error: An error if the attribute doesn't exist, can't be removed, or
+// extended attributes are unsupported on this platform.
+func RemoveXattr(path, name string) error {
+	return removeXattr(path, name)
+}
+
+// ListXattr returns the names of the extended attributes of a file.
+//
+// **Parameters:**
+//
+// path: String representing the path to the file.
+//
+// **Returns:**
+//
+// []string: The names of the extended attributes.
+// error: An error if the attributes can't be listed or extended
+// attributes are unsupported on this platform.
+func ListXattr(path string) ([]string, error) {
+	return listXattr(path)
+}
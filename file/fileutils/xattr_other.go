@@ -0,0 +1,26 @@
+//go:build !(linux || darwin || freebsd || netbsd)
+
+package file
+
+import (
+	"errors"
+	"runtime"
+)
+
+var errXattrUnsupported = errors.New("extended attributes are not supported on " + runtime.GOOS)
+
+func getXattr(path, name string) ([]byte, error) {
+	return nil, errXattrUnsupported
+}
+
+func setXattr(path, name string, value []byte) error {
+	return errXattrUnsupported
+}
+
+func removeXattr(path, name string) error {
+	return errXattrUnsupported
+}
+
+func listXattr(path string) ([]string, error) {
+	return nil, errXattrUnsupported
+}
@@ -0,0 +1,78 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func getXattr(path, name string) ([]byte, error) {
+	for {
+		size, err := unix.Getxattr(path, name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get xattr %s on %s: %w", name, path, err)
+		}
+
+		buf := make([]byte, size)
+		n, err := unix.Getxattr(path, name, buf)
+		if errors.Is(err, unix.ERANGE) {
+			// The value grew between the two calls.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get xattr %s on %s: %w", name, path, err)
+		}
+
+		return buf[:n], nil
+	}
+}
+
+func setXattr(path, name string, value []byte) error {
+	if err := unix.Setxattr(path, name, value, 0); err != nil {
+		return fmt.Errorf("failed to set xattr %s on %s: %w", name, path, err)
+	}
+
+	return nil
+}
+
+func removeXattr(path, name string) error {
+	if err := unix.Removexattr(path, name); err != nil {
+		return fmt.Errorf("failed to remove xattr %s on %s: %w", name, path, err)
+	}
+
+	return nil
+}
+
+func listXattr(path string) ([]string, error) {
+	for {
+		size, err := unix.Listxattr(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list xattrs on %s: %w", path, err)
+		}
+		if size == 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size)
+		n, err := unix.Listxattr(path, buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list xattrs on %s: %w", path, err)
+		}
+
+		var names []string
+		for _, name := range bytes.Split(buf[:n], []byte{0}) {
+			if len(name) > 0 {
+				names = append(names, string(name))
+			}
+		}
+
+		return names, nil
+	}
+}
@@ -0,0 +1,59 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"golang.org/x/sys/unix"
+)
+
+func TestXattr(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const name = "user.goutils.test"
+	if err := fileutils.SetXattr(path, name, []byte("value")); err != nil {
+		if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) {
+			t.Skipf("extended attributes unsupported by the test filesystem: %v", err)
+		}
+		t.Fatalf("SetXattr() failed: %v", err)
+	}
+
+	value, err := fileutils.GetXattr(path, name)
+	if err != nil {
+		t.Fatalf("GetXattr() failed: %v", err)
+	}
+	if string(value) != "value" {
+		t.Errorf("GetXattr() = %q, want %q", value, "value")
+	}
+
+	names, err := fileutils.ListXattr(path)
+	if err != nil {
+		t.Fatalf("ListXattr() failed: %v", err)
+	}
+	found := false
+	for _, n := range names {
+		found = found || n == name
+	}
+	if !found {
+		t.Errorf("ListXattr() = %v, want it to include %s", names, name)
+	}
+
+	if err := fileutils.RemoveXattr(path, name); err != nil {
+		t.Fatalf("RemoveXattr() failed: %v", err)
+	}
+	if _, err := fileutils.GetXattr(path, name); err == nil {
+		t.Error("expected GetXattr() to fail after RemoveXattr()")
+	}
+
+	if _, err := fileutils.GetXattr(filepath.Join(t.TempDir(), "missing"), name); err == nil {
+		t.Error("expected GetXattr() to fail for a missing file")
+	}
+}
@@ -0,0 +1,187 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CreateBranch creates a local branch pointing at the input revision
+// without checking it out.
+//
+// **Parameters:**
+//
+// repo: Repository where the branch should be created.
+// name: The name of the branch (e.g., "feature/login").
+// startRev: The revision the branch should point at (e.g., "main" or a
+// commit hash). An empty string means HEAD.
+//
+// **Returns:**
+//
+// error: Error if the branch already exists or the revision can't be
+// resolved.
+func CreateBranch(repo *git.Repository, name, startRev string) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+	if startRev == "" {
+		startRev = "HEAD"
+	}
+
+	refName := plumbing.NewBranchReferenceName(name)
+	if err := refName.Validate(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %v", name, err)
+	}
+	if _, err := repo.Reference(refName, false); err == nil {
+		return fmt.Errorf("error creating branch %s: it already exists", name)
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fmt.Errorf("failed to check for branch %s: %v", name, err)
+	}
+
+	commit, err := resolveCommit(repo, startRev)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, commit.Hash)); err != nil {
+		return fmt.Errorf("error creating branch %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// CheckoutBranch checks out an existing local branch in the repository's
+// worktree.
+//
+// **Parameters:**
+//
+// repo: Repository whose worktree should be updated.
+// name: The name of the branch to check out.
+//
+// **Returns:**
+//
+// error: Error if the branch doesn't exist or the worktree can't be
+// updated, such as when it has uncommitted changes.
+func CheckoutBranch(repo *git.Repository, name string) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	if err := w.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(name),
+	}); err != nil {
+		return fmt.Errorf("error checking out branch %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// DeleteBranch deletes a local branch and its configuration. The branch
+// that is currently checked out can't be deleted.
+//
+// **Parameters:**
+//
+// repo: Repository where the branch should be deleted.
+// name: The name of the branch to delete.
+//
+// **Returns:**
+//
+// error: Error if the branch doesn't exist, is checked out, or can't be
+// deleted.
+func DeleteBranch(repo *git.Repository, name string) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+
+	refName := plumbing.NewBranchReferenceName(name)
+	if _, err := repo.Reference(refName, false); err != nil {
+		return fmt.Errorf("error deleting branch %s: %v", name, err)
+	}
+
+	if current, err := CurrentBranch(repo); err == nil && current == name {
+		return fmt.Errorf("error deleting branch %s: it is checked out", name)
+	}
+
+	if err := repo.Storer.RemoveReference(refName); err != nil {
+		return fmt.Errorf("error deleting branch %s: %v", name, err)
+	}
+
+	// Branches created by go-git or the git CLI don't always have a
+	// config section, so a missing one isn't an error.
+	if err := repo.DeleteBranch(name); err != nil && !errors.Is(err, git.ErrBranchNotFound) {
+		return fmt.Errorf("error deleting config of branch %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// CurrentBranch returns the name of the branch HEAD points at. This
+// works in a repository without commits, where HEAD points at a branch
+// that doesn't exist yet.
+//
+// **Parameters:**
+//
+// repo: Repository to inspect.
+//
+// **Returns:**
+//
+// string: The short name of the current branch (e.g., "main").
+// error: Error if HEAD can't be read or is detached.
+func CurrentBranch(repo *git.Repository) (string, error) {
+	if repo == nil {
+		return "", errors.New("repository is nil")
+	}
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repo head: %v", err)
+	}
+
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", errors.New("HEAD is detached")
+	}
+
+	return head.Target().Short(), nil
+}
+
+// ListBranches returns the names of the local branches in the
+// repository.
+//
+// **Parameters:**
+//
+// repo: Repository to inspect.
+//
+// **Returns:**
+//
+// []string: The short names of the local branches, sorted
+// alphabetically.
+// error: Error if the branches can't be listed.
+func ListBranches(repo *git.Repository) ([]string, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	refs, err := repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve repo branches: %v", err)
+	}
+
+	var branches []string
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		branches = append(branches, ref.Name().Short())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to retrieve repo branches: %v", err)
+	}
+	sort.Strings(branches)
+
+	return branches, nil
+}
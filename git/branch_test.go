@@ -0,0 +1,79 @@
+package git_test
+
+import (
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranches(t *testing.T) {
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+
+	current, err := gitutils.CurrentBranch(repo)
+	require.NoError(t, err)
+	require.Equal(t, "master", current)
+
+	require.NoError(t, gitutils.CreateBranch(repo, "feature/login", ""))
+	require.NoError(t, gitutils.CreateBranch(repo, "old", "HEAD~1"))
+	require.Error(t, gitutils.CreateBranch(repo, "old", ""), "existing branch")
+	require.Error(t, gitutils.CreateBranch(repo, "bad..name", ""), "invalid name")
+	require.Error(t, gitutils.CreateBranch(repo, "missing", "does-not-exist"), "unknown revision")
+
+	branches, err := gitutils.ListBranches(repo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"feature/login", "master", "old"}, branches)
+
+	require.NoError(t, gitutils.CheckoutBranch(repo, "old"))
+	current, err = gitutils.CurrentBranch(repo)
+	require.NoError(t, err)
+	require.Equal(t, "old", current)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	parent, err := repo.ResolveRevision(plumbing.Revision("master~1"))
+	require.NoError(t, err)
+	require.Equal(t, *parent, head.Hash())
+	require.Error(t, gitutils.CheckoutBranch(repo, "does-not-exist"))
+
+	require.Error(t, gitutils.DeleteBranch(repo, "old"), "checked out branch")
+	require.NoError(t, gitutils.DeleteBranch(repo, "feature/login"))
+	require.Error(t, gitutils.DeleteBranch(repo, "feature/login"), "deleted branch")
+
+	branches, err = gitutils.ListBranches(repo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"master", "old"}, branches)
+
+	require.NoError(t, gitutils.CheckoutBranch(repo, "master"))
+	require.NoError(t, gitutils.DeleteBranch(repo, "old"))
+}
+
+func TestCurrentBranch(t *testing.T) {
+	t.Run("Repository without commits", func(t *testing.T) {
+		repo, err := git.PlainInit(t.TempDir(), false)
+		require.NoError(t, err)
+
+		current, err := gitutils.CurrentBranch(repo)
+		require.NoError(t, err)
+		require.Equal(t, "master", current)
+	})
+
+	t.Run("Detached HEAD", func(t *testing.T) {
+		repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+		head, err := repo.Head()
+		require.NoError(t, err)
+		w, err := repo.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, w.Checkout(&git.CheckoutOptions{Hash: head.Hash()}))
+
+		_, err = gitutils.CurrentBranch(repo)
+		require.Error(t, err)
+	})
+
+	t.Run("Nil repository", func(t *testing.T) {
+		_, err := gitutils.CurrentBranch(nil)
+		require.Error(t, err)
+	})
+}
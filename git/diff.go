@@ -0,0 +1,312 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// defaultSimilarityThreshold is the similarity, as a percentage, used
+// when DiffOptions.SimilarityThreshold is unset. It matches git's default
+// for `git diff -M`.
+const defaultSimilarityThreshold = 50
+
+// ChangeType describes how a path changed between two trees.
+type ChangeType string
+
+const (
+	// ChangeAdded indicates a path that only exists in the new tree.
+	ChangeAdded ChangeType = "added"
+	// ChangeModified indicates a path whose content or mode changed.
+	ChangeModified ChangeType = "modified"
+	// ChangeDeleted indicates a path that only exists in the old tree.
+	ChangeDeleted ChangeType = "deleted"
+	// ChangeRenamed indicates a file that was moved, possibly with
+	// changes to its content.
+	ChangeRenamed ChangeType = "renamed"
+	// ChangeCopied indicates a new file whose content is the same as or
+	// similar to a file in the old tree, which still exists.
+	ChangeCopied ChangeType = "copied"
+)
+
+// ChangedPath describes a single change between two trees.
+//
+// **Attributes:**
+//
+// Type: How the path changed.
+// OldPath: The path in the old tree, or the source of a copy. Empty for
+// added paths.
+// NewPath: The path in the new tree. Empty for deleted paths.
+type ChangedPath struct {
+	Type    ChangeType
+	OldPath string
+	NewPath string
+}
+
+// Path returns the path a change applies to: NewPath, or OldPath for
+// deleted paths.
+//
+// **Returns:**
+//
+// string: The path of the change.
+func (c ChangedPath) Path() string {
+	if c.NewPath == "" {
+		return c.OldPath
+	}
+	return c.NewPath
+}
+
+// DiffOptions controls how ChangedPaths and DiffCommits compare trees.
+//
+// **Attributes:**
+//
+// DetectRenames: Whether deleted and added files with similar content are
+// reported as a single rename.
+// DetectCopies: Whether added files with content similar to an existing
+// file are reported as copies. Exact copies of any file in the old tree
+// are detected; inexact copies are only detected from files that were
+// also modified or renamed, as with `git diff -C`.
+// SimilarityThreshold: The minimum similarity, as a percentage from 1 to
+// 100, for two files to be considered a rename or copy. 0 uses the
+// default of 50.
+// RenameLimit: The maximum number of files compared when detecting
+// inexact renames and copies. 0 means no limit.
+type DiffOptions struct {
+	DetectRenames       bool
+	DetectCopies        bool
+	SimilarityThreshold uint
+	RenameLimit         uint
+}
+
+// ChangedPaths returns the paths that changed between two revisions of a
+// repository, such as the files touched by a branch relative to its merge
+// target.
+//
+// **Parameters:**
+//
+// repo: A pointer to the git.Repository to inspect.
+// fromRev: The old revision (e.g., "main", "v1.2.0", or a commit hash).
+// toRev: The new revision. An empty string means HEAD.
+// opts: DiffOptions controlling rename and copy detection.
+//
+// **Returns:**
+//
+// []ChangedPath: The changes, sorted by path.
+// error: An error if a revision can't be resolved or the trees can't be
+// compared.
+func ChangedPaths(repo *git.Repository, fromRev, toRev string, opts DiffOptions) ([]ChangedPath, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+	if toRev == "" {
+		toRev = "HEAD"
+	}
+
+	from, err := resolveCommit(repo, fromRev)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolveCommit(repo, toRev)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffCommits(from, to, opts)
+}
+
+// DiffCommits returns the paths that changed between the trees of two
+// commits.
+//
+// **Parameters:**
+//
+// from: The old commit.
+// to: The new commit.
+// opts: DiffOptions controlling rename and copy detection.
+//
+// **Returns:**
+//
+// []ChangedPath: The changes, sorted by path.
+// error: An error if the trees can't be read or compared.
+func DiffCommits(from, to *object.Commit, opts DiffOptions) ([]ChangedPath, error) {
+	threshold := opts.SimilarityThreshold
+	if threshold == 0 {
+		threshold = defaultSimilarityThreshold
+	}
+	if threshold > 100 {
+		return nil, fmt.Errorf("similarity threshold must be between 1 and 100, got %d", threshold)
+	}
+
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %s: %v", from.Hash, err)
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %s: %v", to.Hash, err)
+	}
+
+	treeOpts := &object.DiffTreeOptions{
+		DetectRenames: opts.DetectRenames,
+		RenameScore:   threshold,
+		RenameLimit:   opts.RenameLimit,
+	}
+	changes, err := object.DiffTreeWithOptions(context.Background(), fromTree, toTree, treeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s and %s: %v", from.Hash, to.Hash, err)
+	}
+
+	var paths []ChangedPath
+	var added, sources object.Changes
+	deleted := make(map[string]bool)
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify change: %v", err)
+		}
+
+		switch action {
+		case merkletrie.Insert:
+			added = append(added, change)
+		case merkletrie.Delete:
+			paths = append(paths, ChangedPath{Type: ChangeDeleted, OldPath: change.From.Name})
+			deleted[change.From.Name] = true
+		case merkletrie.Modify:
+			changeType := ChangeModified
+			if change.From.Name != change.To.Name {
+				changeType = ChangeRenamed
+			}
+			paths = append(paths, ChangedPath{Type: changeType, OldPath: change.From.Name, NewPath: change.To.Name})
+			sources = append(sources, &object.Change{From: change.From})
+		}
+	}
+
+	copies := make(map[string]string)
+	if opts.DetectCopies && len(added) > 0 {
+		if copies, err = detectCopies(fromTree, deleted, sources, added, treeOpts); err != nil {
+			return nil, err
+		}
+	}
+	for _, change := range added {
+		if src, ok := copies[change.To.Name]; ok {
+			paths = append(paths, ChangedPath{Type: ChangeCopied, OldPath: src, NewPath: change.To.Name})
+		} else {
+			paths = append(paths, ChangedPath{Type: ChangeAdded, NewPath: change.To.Name})
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].Path() < paths[j].Path()
+	})
+
+	return paths, nil
+}
+
+// detectCopies maps the added files that are copies to the path of their
+// source. Exact copies of any file in fromTree that wasn't deleted are
+// detected first, then inexact copies of the files in sources.
+func detectCopies(fromTree *object.Tree, deleted map[string]bool, sources, added object.Changes,
+	opts *object.DiffTreeOptions) (map[string]string, error) {
+	copies := make(map[string]string)
+
+	byHash, err := regularFilesByHash(fromTree, deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining object.Changes
+	for _, change := range added {
+		if src, ok := byHash[change.To.TreeEntry.Hash]; ok && change.To.TreeEntry.Mode.IsFile() {
+			copies[change.To.Name] = src
+			continue
+		}
+		remaining = append(remaining, change)
+	}
+
+	if len(sources) == 0 {
+		return copies, nil
+	}
+
+	// Rename detection pairs each source with at most one destination, so
+	// repeat it until no more copies are found to allow a source to be
+	// copied several times.
+	renameOpts := *opts
+	renameOpts.DetectRenames = true
+	for len(remaining) > 0 {
+		candidates := append(append(object.Changes{}, sources...), remaining...)
+		detected, err := object.DetectRenames(candidates, &renameOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect copies: %v", err)
+		}
+
+		found := make(map[string]bool)
+		for _, change := range detected {
+			if change.From.Name != "" && change.To.Name != "" {
+				copies[change.To.Name] = change.From.Name
+				found[change.To.Name] = true
+			}
+		}
+		if len(found) == 0 {
+			break
+		}
+
+		var next object.Changes
+		for _, change := range remaining {
+			if !found[change.To.Name] {
+				next = append(next, change)
+			}
+		}
+		remaining = next
+	}
+
+	return copies, nil
+}
+
+// regularFilesByHash maps the blob hash of each regular file in tree,
+// except those in skip, to the first path, in walk order, at which it's
+// found.
+func regularFilesByHash(tree *object.Tree, skip map[string]bool) (map[plumbing.Hash]string, error) {
+	byHash := make(map[plumbing.Hash]string)
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk tree %s: %v", tree.Hash, err)
+		}
+		if skip[name] || (entry.Mode != filemode.Regular && entry.Mode != filemode.Executable) {
+			continue
+		}
+		if _, ok := byHash[entry.Hash]; !ok {
+			byHash[entry.Hash] = name
+		}
+	}
+
+	return byHash, nil
+}
+
+// resolveCommit resolves a revision to the commit it refers to.
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %s: %v", rev, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %v", hash, err)
+	}
+
+	return commit, nil
+}
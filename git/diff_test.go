@@ -0,0 +1,195 @@
+package git_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// numberedLines returns n lines of text that start with prefix.
+func numberedLines(prefix string, n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "%s line %d\n", prefix, i)
+	}
+	return sb.String()
+}
+
+// createRepoWithChanges creates a repository with a base commit of a few
+// files and a second commit that applies changes on top of it. An empty
+// string in changes removes the file.
+func createRepoWithChanges(t *testing.T, changes map[string]string) *git.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(msg string, files map[string]string) {
+		for name, content := range files {
+			if content == "" {
+				_, err := w.Remove(name)
+				require.NoError(t, err)
+				continue
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			_, err := w.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := w.Commit(msg, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("base", map[string]string{
+		"a.txt": numberedLines("alpha", 20),
+		"b.txt": numberedLines("bravo", 20),
+		"c.txt": numberedLines("charlie", 20),
+	})
+	commit("changes", changes)
+
+	return repo
+}
+
+func TestChangedPaths(t *testing.T) {
+	alphaEdited := strings.Replace(numberedLines("alpha", 20), "alpha line 3\n", "edited\n", 1)
+
+	testCases := []struct {
+		name    string
+		changes map[string]string
+		opts    gitutils.DiffOptions
+		want    []gitutils.ChangedPath
+		wantErr bool
+	}{
+		{
+			name: "add modify and delete",
+			changes: map[string]string{
+				"a.txt": alphaEdited,
+				"b.txt": "",
+				"d.txt": "new file\n",
+			},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeModified, OldPath: "a.txt", NewPath: "a.txt"},
+				{Type: gitutils.ChangeDeleted, OldPath: "b.txt"},
+				{Type: gitutils.ChangeAdded, NewPath: "d.txt"},
+			},
+		},
+		{
+			name:    "rename without detection is a delete and an add",
+			changes: map[string]string{"a.txt": "", "moved.txt": numberedLines("alpha", 20)},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeDeleted, OldPath: "a.txt"},
+				{Type: gitutils.ChangeAdded, NewPath: "moved.txt"},
+			},
+		},
+		{
+			name:    "exact rename",
+			changes: map[string]string{"a.txt": "", "moved.txt": numberedLines("alpha", 20)},
+			opts:    gitutils.DiffOptions{DetectRenames: true},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeRenamed, OldPath: "a.txt", NewPath: "moved.txt"},
+			},
+		},
+		{
+			name:    "similar rename",
+			changes: map[string]string{"a.txt": "", "moved.txt": alphaEdited},
+			opts:    gitutils.DiffOptions{DetectRenames: true},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeRenamed, OldPath: "a.txt", NewPath: "moved.txt"},
+			},
+		},
+		{
+			name:    "similar rename below threshold",
+			changes: map[string]string{"a.txt": "", "moved.txt": alphaEdited},
+			opts:    gitutils.DiffOptions{DetectRenames: true, SimilarityThreshold: 100},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeDeleted, OldPath: "a.txt"},
+				{Type: gitutils.ChangeAdded, NewPath: "moved.txt"},
+			},
+		},
+		{
+			name:    "exact copy",
+			changes: map[string]string{"copy.txt": numberedLines("bravo", 20)},
+			opts:    gitutils.DiffOptions{DetectCopies: true},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeCopied, OldPath: "b.txt", NewPath: "copy.txt"},
+			},
+		},
+		{
+			name:    "copy without detection is an add",
+			changes: map[string]string{"copy.txt": numberedLines("bravo", 20)},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeAdded, NewPath: "copy.txt"},
+			},
+		},
+		{
+			name:    "moved file isn't a copy of its deleted source",
+			changes: map[string]string{"a.txt": "", "moved.txt": numberedLines("alpha", 20)},
+			opts:    gitutils.DiffOptions{DetectCopies: true},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeDeleted, OldPath: "a.txt"},
+				{Type: gitutils.ChangeAdded, NewPath: "moved.txt"},
+			},
+		},
+		{
+			name: "similar copies of a modified file",
+			changes: map[string]string{
+				"a.txt":     strings.Replace(numberedLines("alpha", 20), "alpha line 20\n", "", 1),
+				"copy1.txt": alphaEdited,
+				"copy2.txt": alphaEdited + "extra\n",
+			},
+			opts: gitutils.DiffOptions{DetectRenames: true, DetectCopies: true},
+			want: []gitutils.ChangedPath{
+				{Type: gitutils.ChangeModified, OldPath: "a.txt", NewPath: "a.txt"},
+				{Type: gitutils.ChangeCopied, OldPath: "a.txt", NewPath: "copy1.txt"},
+				{Type: gitutils.ChangeCopied, OldPath: "a.txt", NewPath: "copy2.txt"},
+			},
+		},
+		{
+			name:    "similarity threshold above 100",
+			changes: map[string]string{"d.txt": "new file\n"},
+			opts:    gitutils.DiffOptions{SimilarityThreshold: 101},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, tc.changes)
+
+			got, err := gitutils.ChangedPaths(repo, "HEAD~1", "", tc.opts)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestChangedPathsInvalidInput(t *testing.T) {
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+
+	_, err := gitutils.ChangedPaths(nil, "HEAD~1", "HEAD", gitutils.DiffOptions{})
+	require.Error(t, err)
+
+	_, err = gitutils.ChangedPaths(repo, "does-not-exist", "HEAD", gitutils.DiffOptions{})
+	require.Error(t, err)
+
+	got, err := gitutils.ChangedPaths(repo, "HEAD", "HEAD", gitutils.DiffOptions{})
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
@@ -1,18 +1,19 @@
 package git
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/l50/goutils/v2/logging"
 	"github.com/l50/goutils/v2/sys"
 
 	"github.com/magefile/mage/sh"
@@ -76,22 +77,28 @@ func AddFile(filePath string) error {
 }
 
 // Commit generates a new commit in the specified repository with
-// the given message. The commit's author is extracted from the
-// global Git user settings.
+// the given message. The commit's author is the repository's
+// identity, as returned by RepoIdentity.
 //
 // **Parameters:**
 //
 // repo: A pointer to the Repository struct symbolizing the
 // repository where the commit should be made.
 // msg: A string depicting the commit message.
+// opts: Optional signing options, such as WithSigner.
 //
 // **Returns:**
 //
 // error: An error if the commit can't be created.
-func Commit(repo *git.Repository, msg string) error {
-	cfg, err := repo.Config()
+func Commit(repo *git.Repository, msg string, opts ...SignOption) error {
+	options := &SignOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	identity, err := RepoIdentity(repo)
 	if err != nil {
-		return fmt.Errorf("failed to get repo config: %v", err)
+		return fmt.Errorf("failed to get commit identity: %v", err)
 	}
 
 	w, err := repo.Worktree()
@@ -101,10 +108,11 @@ func Commit(repo *git.Repository, msg string) error {
 
 	commit, err := w.Commit(msg, &git.CommitOptions{
 		Author: &object.Signature{
-			Name:  cfg.User.Name,
-			Email: cfg.User.Email,
+			Name:  identity.User,
+			Email: identity.Email,
 			When:  time.Now(),
 		},
+		Signer: options.Signer,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to commit current staging area: %v", err)
@@ -115,7 +123,7 @@ func Commit(repo *git.Repository, msg string) error {
 		return fmt.Errorf("failed to run `git show`: %v", err)
 	}
 
-	if obj.Author.Email != cfg.User.Email {
+	if obj.Author.Email != identity.Email {
 		return fmt.Errorf("author email in commit doesn't match repo config email - Commit() failed: %v", err)
 	}
 
@@ -255,12 +263,19 @@ func GetGlobalUserCfg() (ConfigUserInfo, error) {
 //
 // repo: Pointer to the Repository struct, the repository where the tag is created.
 // tag: String, the name of the tag to create.
+// opts: Optional signing options, such as WithSigner. Signed tags are
+// always annotated.
 //
 // **Returns:**
 //
-// error: Error if the tag can't be created, already exists, or if the global git
-// user settings can't be retrieved.
-func CreateTag(repo *git.Repository, tag string) error {
+// error: Error if the tag can't be created, already exists, or if no git
+// identity is configured for the repository (see RepoIdentity).
+func CreateTag(repo *git.Repository, tag string, opts ...SignOption) error {
+	options := &SignOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	exists, err := tagExists(repo, tag)
 	if err != nil {
 		return fmt.Errorf(
@@ -272,7 +287,7 @@ func CreateTag(repo *git.Repository, tag string) error {
 			"error creating input tag %s: it already exists", tag)
 	}
 
-	cfg, err := GetGlobalUserCfg()
+	cfg, err := RepoIdentity(repo)
 	if err != nil {
 		return fmt.Errorf(
 			"failed get repo config: %v", err)
@@ -284,14 +299,23 @@ func CreateTag(repo *git.Repository, tag string) error {
 			"failed to get repo head: %v", err)
 	}
 
-	_, err = repo.CreateTag(tag, h.Hash(), &git.CreateTagOptions{
-		Tagger: &object.Signature{
-			Name:  cfg.User,
-			Email: cfg.Email,
-			When:  time.Now(),
-		},
-		Message: tag,
-	})
+	tagger := object.Signature{
+		Name:  cfg.User,
+		Email: cfg.Email,
+		When:  time.Now(),
+	}
+	if options.Signer != nil {
+		var tagHash plumbing.Hash
+		tagHash, err = createSignedTag(repo, tag, h.Hash(), tagger, tag, options.Signer)
+		if err == nil {
+			err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(tag), tagHash))
+		}
+	} else {
+		_, err = repo.CreateTag(tag, h.Hash(), &git.CreateTagOptions{
+			Tagger:  &tagger,
+			Message: tag,
+		})
+	}
 
 	if err != nil {
 		return fmt.Errorf(
@@ -314,6 +338,24 @@ func CreateTag(repo *git.Repository, tag string) error {
 //
 // error: Error if the push fails.
 func Push(repo *git.Repository, auth transport.AuthMethod) error {
+	return PushContext(context.Background(), repo, auth)
+}
+
+// PushContext is like Push, but pushes with the input context, so that
+// canceling it aborts the push and a logger attached with
+// logging.IntoContext receives its messages.
+//
+// **Parameters:**
+//
+// ctx: The context of the push.
+// repo: Pointer to the Repository struct, the repository to push.
+// auth: A transport.AuthMethod interface, the authentication method for the push.
+// If it's nil, no authentication is used.
+//
+// **Returns:**
+//
+// error: Error if the push fails.
+func PushContext(ctx context.Context, repo *git.Repository, auth transport.AuthMethod) error {
 	var pushOptions *git.PushOptions
 
 	if auth != nil {
@@ -329,10 +371,10 @@ func Push(repo *git.Repository, auth transport.AuthMethod) error {
 		}
 	}
 
-	err := repo.Push(pushOptions)
+	err := repo.PushContext(ctx, pushOptions)
 	if err != nil {
 		if err == git.NoErrAlreadyUpToDate {
-			fmt.Print("origin remote is up-to-date, no push was executed.")
+			logging.FromContext(ctx).Printf("origin remote is up-to-date, no push was executed.")
 			return nil
 		}
 		return fmt.Errorf(
@@ -354,6 +396,24 @@ func Push(repo *git.Repository, auth transport.AuthMethod) error {
 //
 // error: Error if the push fails.
 func PushTag(repo *git.Repository, tag string, auth transport.AuthMethod) error {
+	return PushTagContext(context.Background(), repo, tag, auth)
+}
+
+// PushTagContext is like PushTag, but pushes with the input context, so
+// that canceling it aborts the push and a logger attached with
+// logging.IntoContext receives its messages.
+//
+// **Parameters:**
+//
+// ctx: The context of the push.
+// repo: Repository where the tag should be pushed.
+// tag: Name of the tag to push.
+// auth: Authentication method for the push. If nil, no authentication is used.
+//
+// **Returns:**
+//
+// error: Error if the push fails.
+func PushTagContext(ctx context.Context, repo *git.Repository, tag string, auth transport.AuthMethod) error {
 	var pushOptions *git.PushOptions
 
 	if auth != nil {
@@ -373,11 +433,10 @@ func PushTag(repo *git.Repository, tag string, auth transport.AuthMethod) error
 		}
 	}
 
-	err := repo.Push(pushOptions)
+	err := repo.PushContext(ctx, pushOptions)
 	if err != nil {
 		if err == git.NoErrAlreadyUpToDate {
-			fmt.Print(color.YellowString(
-				"origin remote is up-to-date, no push was executed."))
+			logging.FromContext(ctx).Warnf("origin remote is up-to-date, no push was executed.")
 			return nil
 		}
 
@@ -419,7 +478,25 @@ func DeleteTag(repo *git.Repository, tag string) error {
 //
 // error: Error if the tag cannot be deleted.
 func DeletePushedTag(repo *git.Repository, tag string, auth transport.AuthMethod) error {
-	err := repo.Push(&git.PushOptions{
+	return DeletePushedTagContext(context.Background(), repo, tag, auth)
+}
+
+// DeletePushedTagContext is like DeletePushedTag, but pushes the deletion
+// with the input context, so that canceling it aborts the push and a
+// logger attached with logging.IntoContext receives its messages.
+//
+// **Parameters:**
+//
+// ctx: The context of the push.
+// repo: Repository where the tag should be deleted.
+// tag: The tag that should be deleted.
+// auth: Authentication method for the push.
+//
+// **Returns:**
+//
+// error: Error if the tag cannot be deleted.
+func DeletePushedTagContext(ctx context.Context, repo *git.Repository, tag string, auth transport.AuthMethod) error {
+	err := repo.PushContext(ctx, &git.PushOptions{
 		RemoteName: "origin",
 		Progress:   os.Stdout,
 		RefSpecs: []config.RefSpec{config.RefSpec(
@@ -429,7 +506,7 @@ func DeletePushedTag(repo *git.Repository, tag string, auth transport.AuthMethod
 
 	if err != nil {
 		if err == git.NoErrAlreadyUpToDate {
-			fmt.Print("origin remote is up-to-date, no delete was executed.")
+			logging.FromContext(ctx).Printf("origin remote is up-to-date, no delete was executed.")
 			return nil
 		}
 
@@ -449,13 +526,33 @@ func DeletePushedTag(repo *git.Repository, tag string, auth transport.AuthMethod
 //
 // error: Error if there's a problem with pulling the repositories.
 func PullRepos(dirs ...string) error {
+	return PullReposContext(context.Background(), dirs...)
+}
+
+// PullReposContext is like PullRepos, but stops before the next
+// repository once the input context is done, and writes its messages to
+// the logger attached to it with logging.IntoContext.
+//
+// **Parameters:**
+//
+// ctx: The context of the updates.
+// dirs: Paths to directories to be searched for git repositories.
+//
+// **Returns:**
+//
+// error: Error if there's a problem with pulling the repositories, or
+// the context's error if it's done.
+func PullReposContext(ctx context.Context, dirs ...string) error {
 	for _, dir := range dirs {
 		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
 			if info.IsDir() && info.Name() == ".git" {
-				return updateRepo(filepath.Dir(path))
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return updateRepo(ctx, filepath.Dir(path))
 			}
 			return nil
 		})
@@ -466,7 +563,7 @@ func PullRepos(dirs ...string) error {
 	return nil
 }
 
-func updateRepo(repoDir string) error {
+func updateRepo(ctx context.Context, repoDir string) error {
 	// Change to the repository directory
 	if err := os.Chdir(repoDir); err != nil {
 		return fmt.Errorf("failed to change directory to %s: %w", repoDir, err)
@@ -475,7 +572,7 @@ func updateRepo(repoDir string) error {
 	// Ensure that we switch back after the function returns
 	defer func() {
 		if err := os.Chdir(".."); err != nil {
-			log.Printf("failed to change directory: %v\n", err)
+			logging.FromContext(ctx).Errorf("failed to change directory: %v", err)
 		}
 	}()
 
@@ -492,11 +589,12 @@ func updateRepo(repoDir string) error {
 
 	// Pull changes in the current branch
 	ref := strings.TrimSpace(refOutput)
+	logger := logging.FromContext(ctx)
 	res, err := sys.RunCommand("git", "pull", "origin", ref)
 	if err != nil {
-		fmt.Printf("failed to update %s: %s\n", repoDir, res)
+		logger.Errorf("failed to update %s: %s", repoDir, res)
 	} else if strings.TrimSpace(res) != "Already up to date." {
-		fmt.Printf("Now pulling the latest from upstream for %s\n", repoDir)
+		logger.Printf("Now pulling the latest from upstream for %s", repoDir)
 	}
 
 	return nil
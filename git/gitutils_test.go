@@ -1,6 +1,7 @@
 package git_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -478,6 +479,32 @@ func TestPullRepos(t *testing.T) {
 	}
 }
 
+func TestPullReposContext(t *testing.T) {
+	defer cleanup(t)
+	filename, err := str.GenRandom(10)
+	require.NoError(t, err)
+
+	_, tmpDirRemote, err := createGitRepoWithCommit(filename, "test commit")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDirRemote)
+
+	tmpDir, err := cloneGitRepo(t, tmpDirRemote, "test1")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	require.NoError(t, updateFileInRepo(t, tmpDirRemote, "file.txt", "test2"))
+	require.NoError(t, commitChangesInRepo(t, tmpDirRemote, "test2 commit"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = gitutils.PullReposContext(ctx, tmpDir)
+	require.ErrorIs(t, err, context.Canceled)
+
+	out, err := sys.RunCommand("git", "-C", tmpDir, "log", "--oneline")
+	require.NoError(t, err)
+	require.NotContains(t, out, "test2 commit", "repo in %s was updated after the context was canceled", tmpDir)
+}
+
 func TestRepoRoot(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -0,0 +1,216 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// largestBlobsLimit is the number of blobs reported in
+// RepoHealthReport.LargestBlobs.
+const largestBlobsLimit = 10
+
+// BlobInfo describes a blob stored in a repository.
+//
+// **Attributes:**
+//
+// Hash: The hash of the blob.
+// Path: The first path the blob was found at while walking history, or
+// empty if the blob isn't reachable from any commit.
+// Size: The uncompressed size of the blob in bytes.
+// Commit: The hash of a commit that references the blob at Path.
+type BlobInfo struct {
+	Hash   plumbing.Hash
+	Path   string
+	Size   int64
+	Commit plumbing.Hash
+}
+
+// RepoHealthReport summarizes the size and layout of a repository.
+//
+// **Attributes:**
+//
+// LargestBlobs: The largest blobs in the repository, largest first.
+// ObjectCount: The number of objects in the repository.
+// TotalObjectSize: The combined uncompressed size of every object in bytes.
+// PackCount: The number of packfiles, or 0 if the storage isn't packed.
+// RefsCount: The number of references (branches, tags, remotes, HEAD).
+type RepoHealthReport struct {
+	LargestBlobs    []BlobInfo
+	ObjectCount     int
+	TotalObjectSize int64
+	PackCount       int
+	RefsCount       int
+}
+
+// RepoHealth inspects a repository and reports its largest blobs, total
+// object size, pack count, and refs count. It is intended for pre-push
+// hooks and repository hygiene tooling.
+//
+// **Parameters:**
+//
+// repo: A pointer to the git.Repository to inspect.
+//
+// **Returns:**
+//
+// *RepoHealthReport: The repository health report.
+// error: An error if the repository can't be read.
+func RepoHealth(repo *git.Repository) (*RepoHealthReport, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	report := &RepoHealthReport{}
+
+	objects, err := repo.Storer.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	var blobs []BlobInfo
+	err = objects.ForEach(func(obj plumbing.EncodedObject) error {
+		report.ObjectCount++
+		report.TotalObjectSize += obj.Size()
+		if obj.Type() == plumbing.BlobObject {
+			blobs = append(blobs, BlobInfo{Hash: obj.Hash(), Size: obj.Size()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects: %v", err)
+	}
+
+	sortBlobs(blobs)
+	if len(blobs) > largestBlobsLimit {
+		blobs = blobs[:largestBlobsLimit]
+	}
+
+	paths, err := blobPaths(repo)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blobs {
+		if loc, ok := paths[blobs[i].Hash]; ok {
+			blobs[i].Path = loc.Path
+			blobs[i].Commit = loc.Commit
+		}
+	}
+	report.LargestBlobs = blobs
+
+	if packed, ok := repo.Storer.(storer.PackedObjectStorer); ok {
+		packs, err := packed.ObjectPacks()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packfiles: %v", err)
+		}
+		report.PackCount = len(packs)
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %v", err)
+	}
+	err = refs.ForEach(func(*plumbing.Reference) error {
+		report.RefsCount++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read references: %v", err)
+	}
+
+	return report, nil
+}
+
+// FindLargeFiles walks the history reachable from every reference in a
+// repository and returns the files whose size is at least threshold
+// bytes. Files that were deleted in later commits are still reported,
+// since they continue to bloat every clone.
+//
+// **Parameters:**
+//
+// repo: A pointer to the git.Repository to inspect.
+// threshold: The minimum size in bytes for a file to be reported.
+//
+// **Returns:**
+//
+// []BlobInfo: The large files found, largest first. Each blob is
+// reported once, at the first path it was found at.
+// error: An error if the repository history can't be read.
+func FindLargeFiles(repo *git.Repository, threshold int64) ([]BlobInfo, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	var large []BlobInfo
+	err := walkBlobs(repo, func(info BlobInfo) {
+		if info.Size >= threshold {
+			large = append(large, info)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortBlobs(large)
+
+	return large, nil
+}
+
+// blobPaths maps every blob reachable from a reference to the first
+// path and commit it was found at.
+func blobPaths(repo *git.Repository) (map[plumbing.Hash]BlobInfo, error) {
+	paths := make(map[plumbing.Hash]BlobInfo)
+	err := walkBlobs(repo, func(info BlobInfo) {
+		paths[info.Hash] = info
+	})
+
+	return paths, err
+}
+
+// walkBlobs calls fn once for every distinct blob reachable from any
+// reference in the repository.
+func walkBlobs(repo *git.Repository, fn func(BlobInfo)) error {
+	commits, err := repo.Log(&git.LogOptions{All: true})
+	if err != nil {
+		// An empty repository has no history to walk.
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to read history: %v", err)
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	err = commits.ForEach(func(c *object.Commit) error {
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+
+		return tree.Files().ForEach(func(f *object.File) error {
+			if seen[f.Hash] {
+				return nil
+			}
+			seen[f.Hash] = true
+			fn(BlobInfo{Hash: f.Hash, Path: f.Name, Size: f.Size, Commit: c.Hash})
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk history: %v", err)
+	}
+
+	return nil
+}
+
+func sortBlobs(blobs []BlobInfo) {
+	sort.Slice(blobs, func(i, j int) bool {
+		if blobs[i].Size != blobs[j].Size {
+			return blobs[i].Size > blobs[j].Size
+		}
+		return blobs[i].Path < blobs[j].Path
+	})
+}
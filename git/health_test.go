@@ -0,0 +1,95 @@
+package git_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// createRepoWithLargeFile creates a repository whose history contains a
+// large file that is deleted by the latest commit.
+func createRepoWithLargeFile(t *testing.T) *git.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(msg string) {
+		_, err := w.Commit(msg, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "small.txt"), []byte("small"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "big.bin"), bytes.Repeat([]byte{'x'}, 4096), 0644))
+	_, err = w.Add(".")
+	require.NoError(t, err)
+	commit("add files")
+
+	_, err = w.Remove("big.bin")
+	require.NoError(t, err)
+	commit("remove big file")
+
+	return repo
+}
+
+func TestFindLargeFiles(t *testing.T) {
+	repo := createRepoWithLargeFile(t)
+
+	testCases := []struct {
+		name      string
+		threshold int64
+		wantPaths []string
+	}{
+		{name: "deleted large file is still reported", threshold: 1024, wantPaths: []string{"big.bin"}},
+		{name: "low threshold reports every file largest first", threshold: 1, wantPaths: []string{"big.bin", "small.txt"}},
+		{name: "high threshold reports nothing", threshold: 1 << 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := gitutils.FindLargeFiles(repo, tc.threshold)
+			require.NoError(t, err)
+
+			var paths []string
+			for _, f := range files {
+				paths = append(paths, f.Path)
+			}
+			require.Equal(t, tc.wantPaths, paths)
+		})
+	}
+
+	_, err := gitutils.FindLargeFiles(nil, 1)
+	require.Error(t, err)
+}
+
+func TestRepoHealth(t *testing.T) {
+	repo := createRepoWithLargeFile(t)
+
+	report, err := gitutils.RepoHealth(repo)
+	require.NoError(t, err)
+
+	// 2 commits, 2 trees, and 2 blobs.
+	require.Equal(t, 6, report.ObjectCount)
+	require.Greater(t, report.TotalObjectSize, int64(4096))
+	require.Equal(t, 0, report.PackCount)
+	require.GreaterOrEqual(t, report.RefsCount, 2) // HEAD and the default branch
+	require.Len(t, report.LargestBlobs, 2)
+	require.Equal(t, "big.bin", report.LargestBlobs[0].Path)
+	require.Equal(t, int64(4096), report.LargestBlobs[0].Size)
+
+	_, err = gitutils.RepoHealth(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// SetRepoIdentity sets the user name and email in the repository's local
+// git config, so that commits and tags made in it by Commit, CreateTag,
+// or the git CLI use that identity without changing the global config.
+//
+// **Parameters:**
+//
+// repo: The repository to set the identity for.
+// name: The user name to commit as (e.g., "release-bot").
+// email: The email to commit as.
+//
+// **Returns:**
+//
+// error: An error if name or email is empty or the config can't be
+// written.
+func SetRepoIdentity(repo *git.Repository, name, email string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(email) == "" {
+		return errors.New("identity name and email must not be empty")
+	}
+
+	return setLocalUser(repo, name, email)
+}
+
+// RepoIdentity returns the identity that Commit and CreateTag use for
+// the repository: the user name and email from its local git config,
+// falling back to the global git config for any that aren't set.
+//
+// **Parameters:**
+//
+// repo: The repository to get the identity for.
+//
+// **Returns:**
+//
+// ConfigUserInfo: The user name and email.
+// error: An error if no user name or email is configured.
+func RepoIdentity(repo *git.Repository) (ConfigUserInfo, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return ConfigUserInfo{}, fmt.Errorf("failed to get repo config: %v", err)
+	}
+
+	identity := ConfigUserInfo{User: cfg.User.Name, Email: cfg.User.Email}
+	if identity.User != "" && identity.Email != "" {
+		return identity, nil
+	}
+
+	global, err := GetGlobalUserCfg()
+	if err != nil {
+		return identity, fmt.Errorf("no identity set in repo config: %v", err)
+	}
+	if identity.User == "" {
+		identity.User = global.User
+	}
+	if identity.Email == "" {
+		identity.Email = global.Email
+	}
+
+	return identity, nil
+}
+
+// WithIdentity runs fn with the repository's local git config set to the
+// given identity, then restores the previous local identity, even if fn
+// fails. It lets automation commit as a bot identity in one repository
+// without affecting other repositories or the global config. It isn't
+// safe to call concurrently for the same repository.
+//
+// **Parameters:**
+//
+// repo: The repository to commit in.
+// name: The user name to commit as while fn runs.
+// email: The email to commit as while fn runs.
+// fn: The function that makes the commits or tags.
+//
+// **Returns:**
+//
+// error: The error returned by fn, or an error if the identity can't be
+// set or restored.
+func WithIdentity(repo *git.Repository, name, email string, fn func() error) (err error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to get repo config: %v", err)
+	}
+	prevName, prevEmail := cfg.User.Name, cfg.User.Email
+
+	if err := SetRepoIdentity(repo, name, email); err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := setLocalUser(repo, prevName, prevEmail); restoreErr != nil {
+			restoreErr = fmt.Errorf("failed to restore repo identity: %v", restoreErr)
+			if err == nil {
+				err = restoreErr
+			} else {
+				err = fmt.Errorf("%v; %v", err, restoreErr)
+			}
+		}
+	}()
+
+	return fn()
+}
+
+// setLocalUser writes the user name and email to the repository's local
+// config. Empty values remove the setting.
+func setLocalUser(repo *git.Repository, name, email string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to get repo config: %v", err)
+	}
+
+	cfg.User.Name = name
+	cfg.User.Email = email
+	// Empty fields aren't written, so they have to be removed from the
+	// raw config to unset them.
+	section := cfg.Raw.Section("user")
+	if name == "" {
+		section.RemoveOption("name")
+	}
+	if email == "" {
+		section.RemoveOption("email")
+	}
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("failed to set repo config: %v", err)
+	}
+
+	return nil
+}
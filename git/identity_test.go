@@ -0,0 +1,105 @@
+package git_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRepoIdentity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		user    string
+		email   string
+		wantErr bool
+	}{
+		{name: "Valid identity", user: "release-bot", email: "bot@example.com"},
+		{name: "Empty name", user: " ", email: "bot@example.com", wantErr: true},
+		{name: "Empty email", user: "release-bot", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, err := git.PlainInit(t.TempDir(), false)
+			require.NoError(t, err)
+
+			err = gitutils.SetRepoIdentity(repo, tc.user, tc.email)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			identity, err := gitutils.RepoIdentity(repo)
+			require.NoError(t, err)
+			require.Equal(t, gitutils.ConfigUserInfo{User: tc.user, Email: tc.email}, identity)
+		})
+	}
+}
+
+func TestWithIdentity(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	require.NoError(t, err)
+	require.NoError(t, gitutils.SetRepoIdentity(repo, "Your Name", "you@example.com"))
+
+	commitFile := func(name string) error {
+		wt, err := repo.Worktree()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), name), []byte(name), 0644); err != nil {
+			return err
+		}
+		if _, err := wt.Add(name); err != nil {
+			return err
+		}
+		return gitutils.Commit(repo, "add "+name)
+	}
+
+	err = gitutils.WithIdentity(repo, "release-bot", "bot@example.com", func() error {
+		return commitFile("bot.txt")
+	})
+	require.NoError(t, err)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	require.Equal(t, "release-bot", commit.Author.Name)
+	require.Equal(t, "bot@example.com", commit.Author.Email)
+
+	identity, err := gitutils.RepoIdentity(repo)
+	require.NoError(t, err)
+	require.Equal(t, gitutils.ConfigUserInfo{User: "Your Name", Email: "you@example.com"}, identity,
+		"previous identity should be restored")
+
+	fnErr := errors.New("commit failed")
+	err = gitutils.WithIdentity(repo, "release-bot", "bot@example.com", func() error { return fnErr })
+	require.ErrorIs(t, err, fnErr)
+	identity, err = gitutils.RepoIdentity(repo)
+	require.NoError(t, err)
+	require.Equal(t, "Your Name", identity.User, "identity should be restored when fn fails")
+
+	require.Error(t, gitutils.WithIdentity(repo, "", "", func() error {
+		t.Fatal("fn should not run with an empty identity")
+		return nil
+	}))
+}
+
+func TestWithIdentityUnsetsLocalIdentity(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	require.NoError(t, err)
+
+	require.NoError(t, gitutils.WithIdentity(repo, "release-bot", "bot@example.com", func() error { return nil }))
+
+	cfg, err := repo.Config()
+	require.NoError(t, err)
+	require.Empty(t, cfg.User.Name)
+	require.Empty(t, cfg.User.Email)
+}
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// LogOptions filters the commits returned by Log.
+//
+// **Attributes:**
+//
+// Since: Only include commits committed at or after this time. The zero
+// value means no lower bound.
+// Until: Only include commits committed at or before this time. The
+// zero value means no upper bound.
+// Author: Only include commits whose author name or email contains this
+// string, ignoring case.
+// PathFilter: Only include commits that changed this file, or a file
+// below it if it's a directory (e.g., "docs" or "go.mod").
+// MaxCount: The maximum number of commits to return. 0 means no limit.
+type LogOptions struct {
+	Since      time.Time
+	Until      time.Time
+	Author     string
+	PathFilter string
+	MaxCount   int
+}
+
+// CommitInfo describes a single commit returned by Log.
+//
+// **Attributes:**
+//
+// Hash: The full hash of the commit.
+// Author: The name of the commit author.
+// Email: The email of the commit author.
+// Date: When the commit was authored.
+// Message: The full commit message.
+// Files: The files changed by the commit relative to its first parent,
+// sorted alphabetically.
+type CommitInfo struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Message string
+	Files   []string
+}
+
+// Log returns the history reachable from HEAD, newest first, such as to
+// build a changelog or find when a file was last modified.
+//
+// **Parameters:**
+//
+// repo: Repository whose history should be read.
+// opts: LogOptions filtering the commits returned.
+//
+// **Returns:**
+//
+// []CommitInfo: The matching commits, newest first.
+// error: Error if HEAD can't be resolved or the history can't be read.
+func Log(repo *git.Repository, opts LogOptions) ([]CommitInfo, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo head: %v", err)
+	}
+
+	logOpts := &git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime}
+	if !opts.Since.IsZero() {
+		logOpts.Since = &opts.Since
+	}
+	if !opts.Until.IsZero() {
+		logOpts.Until = &opts.Until
+	}
+
+	commits, err := repo.Log(logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repo log: %v", err)
+	}
+	defer commits.Close()
+
+	author := strings.ToLower(opts.Author)
+	pathFilter := strings.Trim(opts.PathFilter, "/")
+
+	var infos []CommitInfo
+	err = commits.ForEach(func(c *object.Commit) error {
+		if author != "" &&
+			!strings.Contains(strings.ToLower(c.Author.Name), author) &&
+			!strings.Contains(strings.ToLower(c.Author.Email), author) {
+			return nil
+		}
+
+		files, err := commitFiles(c)
+		if err != nil {
+			return err
+		}
+		if pathFilter != "" && !anyUnderPath(files, pathFilter) {
+			return nil
+		}
+
+		infos = append(infos, CommitInfo{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Date:    c.Author.When,
+			Message: c.Message,
+			Files:   files,
+		})
+		if opts.MaxCount > 0 && len(infos) >= opts.MaxCount {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repo log: %v", err)
+	}
+
+	return infos, nil
+}
+
+// commitFiles returns the files changed by a commit relative to its first
+// parent, or every file for a root commit.
+func commitFiles(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %s: %v", c.Hash, err)
+	}
+
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of commit %s: %v", c.Hash, err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, fmt.Errorf("failed to get tree of commit %s: %v", parent.Hash, err)
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff commit %s: %v", c.Hash, err)
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify change: %v", err)
+		}
+		if action == merkletrie.Delete {
+			files = append(files, change.From.Name)
+		} else {
+			files = append(files, change.To.Name)
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// anyUnderPath reports whether any of the files is path or is below it.
+func anyUnderPath(files []string, path string) bool {
+	for _, f := range files {
+		if f == path || strings.HasPrefix(f, path+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(day int, author, msg string, files ...string) {
+		for _, name := range files {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(msg), 0644))
+			_, err := w.Add(name)
+			require.NoError(t, err)
+		}
+		sig := &object.Signature{Name: author, Email: author + "@example.com", When: base.AddDate(0, 0, day)}
+		_, err := w.Commit(msg, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err)
+	}
+
+	commit(0, "alice", "initial commit", "README.md", "go.mod")
+	commit(1, "bob", "add docs", "docs/index.md", "docs/usage.md")
+	commit(2, "alice", "update usage", "docs/usage.md")
+	commit(3, "Carol", "bump module", "go.mod")
+
+	testCases := []struct {
+		name         string
+		opts         gitutils.LogOptions
+		wantMessages []string
+	}{
+		{
+			name:         "Full history newest first",
+			wantMessages: []string{"bump module", "update usage", "add docs", "initial commit"},
+		},
+		{
+			name:         "Max count",
+			opts:         gitutils.LogOptions{MaxCount: 2},
+			wantMessages: []string{"bump module", "update usage"},
+		},
+		{
+			name:         "Author matches name or email ignoring case",
+			opts:         gitutils.LogOptions{Author: "ALICE@"},
+			wantMessages: []string{"update usage", "initial commit"},
+		},
+		{
+			name:         "Directory path filter",
+			opts:         gitutils.LogOptions{PathFilter: "docs/"},
+			wantMessages: []string{"update usage", "add docs"},
+		},
+		{
+			name:         "File path filter with max count",
+			opts:         gitutils.LogOptions{PathFilter: "go.mod", MaxCount: 1},
+			wantMessages: []string{"bump module"},
+		},
+		{
+			name:         "Time range",
+			opts:         gitutils.LogOptions{Since: base.AddDate(0, 0, 1), Until: base.AddDate(0, 0, 2)},
+			wantMessages: []string{"update usage", "add docs"},
+		},
+		{
+			name: "No matches",
+			opts: gitutils.LogOptions{Author: "mallory"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			commits, err := gitutils.Log(repo, tc.opts)
+			require.NoError(t, err)
+
+			var messages []string
+			for _, c := range commits {
+				messages = append(messages, c.Message)
+			}
+			require.Equal(t, tc.wantMessages, messages)
+		})
+	}
+
+	commits, err := gitutils.Log(repo, gitutils.LogOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"docs/index.md", "docs/usage.md"}, commits[2].Files)
+	require.Equal(t, []string{"README.md", "go.mod"}, commits[3].Files)
+	require.Equal(t, "bob", commits[2].Author)
+	require.Equal(t, "bob@example.com", commits[2].Email)
+	require.True(t, base.AddDate(0, 0, 1).Equal(commits[2].Date))
+	require.Len(t, commits[2].Hash, 40)
+
+	_, err = gitutils.Log(nil, gitutils.LogOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// MergeStrategy selects how Merge integrates a branch.
+type MergeStrategy int
+
+const (
+	// FastForwardOnly moves the current branch to the merged branch. It
+	// fails unless the current branch is an ancestor of the merged one,
+	// so no merge commit is ever created.
+	FastForwardOnly MergeStrategy = iota
+
+	// Squash applies the changes of the merged branch as a single new
+	// commit on the current branch.
+	Squash
+)
+
+// MergeOptions configures Merge.
+//
+// **Attributes:**
+//
+// Strategy: How the branch is merged: FastForwardOnly or Squash.
+// Message: The message of the squash commit. If empty, git's default
+// message listing the squashed commits is used. Ignored by
+// FastForwardOnly.
+type MergeOptions struct {
+	Strategy MergeStrategy
+	Message  string
+}
+
+// ErrNotFastForward is returned by Merge with FastForwardOnly when the
+// current branch has commits that the merged branch doesn't.
+var ErrNotFastForward = errors.New("the branches have diverged")
+
+// MergeConflictError is returned by Merge when the merged branch
+// conflicts with the current branch. Nothing is changed in that case, so
+// the branch and worktree are left as they were before Merge was called.
+//
+// **Attributes:**
+//
+// Branch: The branch that was being merged.
+// Paths: The conflicting paths, relative to the worktree root.
+type MergeConflictError struct {
+	Branch string
+	Paths  []string
+}
+
+// Error describes the conflicting paths.
+//
+// **Returns:**
+//
+// string: The error message.
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merging %s conflicts in: %s", e.Branch, strings.Join(e.Paths, ", "))
+}
+
+// Merge merges a branch into the current branch of the repository using
+// one of the strategies that are safe to run unattended, such as when
+// promoting changes between long-lived branches. Merging a branch that
+// the current branch already contains does nothing. Squash merges
+// changes per file: the branches conflict if both changed a file
+// differently, even if the changes don't overlap. Conflicts aren't
+// resolved: a *MergeConflictError listing the conflicting paths is
+// returned and the branch is left untouched.
+//
+// **Parameters:**
+//
+// repo: Repository whose current branch the branch is merged into.
+// branch: The branch or revision to merge (e.g., "develop").
+// opts: The merge strategy and squash commit message.
+//
+// **Returns:**
+//
+// error: Error if the worktree has uncommitted changes to tracked files,
+// the branch can't be fast-forwarded (wrapping ErrNotFastForward), or
+// the merge conflicts.
+func Merge(repo *git.Repository, branch string, opts MergeOptions) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+	if opts.Strategy != FastForwardOnly && opts.Strategy != Squash {
+		return fmt.Errorf("unsupported merge strategy: %d", opts.Strategy)
+	}
+	target, err := resolveCommit(repo, branch)
+	if err != nil {
+		return err
+	}
+
+	files, err := Status(repo)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.Worktree != StateUntracked {
+			return fmt.Errorf("error merging %s: the worktree has uncommitted changes", branch)
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get repo head: %v", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to get head commit: %v", err)
+	}
+	bases, err := headCommit.MergeBase(target)
+	if err != nil {
+		return fmt.Errorf("failed to find the merge base of HEAD and %s: %v", branch, err)
+	}
+	if len(bases) > 0 && bases[0].Hash == target.Hash {
+		// The current branch already contains the branch.
+		return nil
+	}
+
+	if opts.Strategy == FastForwardOnly {
+		return fastForward(repo, headCommit, target, branch)
+	}
+	return squashMerge(repo, headCommit, target, bases, branch, opts.Message)
+}
+
+// fastForward points the current branch at target and checks it out.
+func fastForward(repo *git.Repository, head, target *object.Commit, branch string) error {
+	before, err := flattenTree(head)
+	if err != nil {
+		return err
+	}
+	after, err := flattenTree(target)
+	if err != nil {
+		return err
+	}
+
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), target.Hash)
+	if err := repo.Merge(*ref, git.MergeOptions{Strategy: git.FastForwardMerge}); err != nil {
+		if errors.Is(err, git.ErrFastForwardMergeNotPossible) {
+			return fmt.Errorf("error fast-forwarding to %s: %w", branch, ErrNotFastForward)
+		}
+		return fmt.Errorf("error fast-forwarding to %s: %v", branch, err)
+	}
+
+	if err := checkoutTree(repo, before, after, target.Hash); err != nil {
+		return fmt.Errorf("failed to check out %s: %v", branch, err)
+	}
+
+	return nil
+}
+
+// squashMerge applies the changes target made since the merge bases to
+// the current branch as a single commit, authored by the repository's
+// identity, and checks it out.
+func squashMerge(repo *git.Repository, head, target *object.Commit, bases []*object.Commit, branch, message string) error {
+	if len(bases) == 0 {
+		return fmt.Errorf("error squashing %s: it has no history in common with HEAD", branch)
+	}
+	base, err := flattenTree(bases[0])
+	if err != nil {
+		return err
+	}
+	theirs, err := flattenTree(target)
+	if err != nil {
+		return err
+	}
+	ours, err := flattenTree(head)
+	if err != nil {
+		return err
+	}
+
+	tree := make(map[string]treeFile, len(ours))
+	for p, file := range ours {
+		tree[p] = file
+	}
+	applied, conflicts := applyChanges(tree, base, theirs)
+	if len(conflicts) > 0 {
+		return &MergeConflictError{Branch: branch, Paths: conflicts}
+	}
+	if !applied {
+		// The changes of the branch are already on the current branch.
+		return nil
+	}
+
+	identity, err := RepoIdentity(repo)
+	if err != nil {
+		return fmt.Errorf("error committing squash of %s: %v", branch, err)
+	}
+	if message == "" {
+		if message, err = squashMessage(target, bases); err != nil {
+			return err
+		}
+	}
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+	signature := object.Signature{Name: identity.User, Email: identity.Email, When: time.Now()}
+	hash, err := writeCommit(repo, tree, head.Hash, signature, signature, message)
+	if err != nil {
+		return fmt.Errorf("error committing squash of %s: %v", branch, err)
+	}
+
+	if err := checkoutTree(repo, ours, tree, hash); err != nil {
+		return fmt.Errorf("failed to check out squash of %s: %v", branch, err)
+	}
+
+	return nil
+}
+
+// squashMessage returns git's default message of a squash commit, which
+// lists the squashed commits from target back to the merge bases.
+func squashMessage(target *object.Commit, bases []*object.Commit) (string, error) {
+	ignore := make([]plumbing.Hash, 0, len(bases))
+	for _, base := range bases {
+		ignore = append(ignore, base.Hash)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Squashed commit of the following:\n")
+	err := object.NewCommitPreorderIter(target, nil, ignore).ForEach(func(c *object.Commit) error {
+		fmt.Fprintf(&sb, "\ncommit %s\nAuthor: %s <%s>\nDate:   %s\n\n",
+			c.Hash, c.Author.Name, c.Author.Email, c.Author.When.Format("Mon Jan 2 15:04:05 2006 -0700"))
+		for _, line := range strings.Split(strings.TrimRight(c.Message, "\n"), "\n") {
+			sb.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the squashed commits: %v", err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,205 @@
+package git_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// commitOnBranch checks out branch, creating it from HEAD if needed,
+// commits files to it, and checks out master again.
+func commitOnBranch(t *testing.T, repo *git.Repository, branch string, files map[string]string) plumbing.Hash {
+	t.Helper()
+
+	if branch != "master" {
+		if err := gitutils.CreateBranch(repo, branch, ""); err != nil {
+			require.Contains(t, err.Error(), "already exists")
+		}
+		require.NoError(t, gitutils.CheckoutBranch(repo, branch))
+		defer func() { require.NoError(t, gitutils.CheckoutBranch(repo, "master")) }()
+	}
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(w.Filesystem.Root(), name), []byte(content), 0644))
+		_, err := w.Add(name)
+		require.NoError(t, err)
+	}
+	hash, err := w.Commit("update "+branch, &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	return hash
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name          string
+		setup         func(t *testing.T, repo *git.Repository)
+		opts          gitutils.MergeOptions
+		wantConflicts []string
+		wantErr       string
+		wantErrIs     error
+		wantHead      string
+		wantFiles     map[string]string
+		wantMessage   string
+	}{
+		{
+			name: "Fast-forward",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "feature\n"})
+			},
+			opts:      gitutils.MergeOptions{Strategy: gitutils.FastForwardOnly},
+			wantHead:  "develop",
+			wantFiles: map[string]string{"e.txt": "feature\n"},
+		},
+		{
+			name: "Fast-forward of diverged branches",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "feature\n"})
+				commitOnBranch(t, repo, "master", map[string]string{"f.txt": "hotfix\n"})
+			},
+			opts:      gitutils.MergeOptions{Strategy: gitutils.FastForwardOnly},
+			wantErr:   "error fast-forwarding to develop",
+			wantErrIs: gitutils.ErrNotFastForward,
+		},
+		{
+			name: "Fast-forward of merged branch",
+			setup: func(t *testing.T, repo *git.Repository) {
+				require.NoError(t, gitutils.CreateBranch(repo, "develop", "HEAD~1"))
+			},
+			opts:     gitutils.MergeOptions{Strategy: gitutils.FastForwardOnly},
+			wantHead: "master",
+		},
+		{
+			name: "Squash with message",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "one\n"})
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "two\n"})
+				commitOnBranch(t, repo, "master", map[string]string{"f.txt": "hotfix\n"})
+			},
+			opts:        gitutils.MergeOptions{Strategy: gitutils.Squash, Message: "Promote develop"},
+			wantFiles:   map[string]string{"e.txt": "two\n", "f.txt": "hotfix\n"},
+			wantMessage: "Promote develop\n",
+		},
+		{
+			name: "Squash with default message",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "one\n"})
+			},
+			opts:        gitutils.MergeOptions{Strategy: gitutils.Squash},
+			wantFiles:   map[string]string{"e.txt": "one\n"},
+			wantMessage: "Squashed commit of the following:",
+		},
+		{
+			name: "Squash conflict",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"a.txt": "develop\n", "e.txt": "new\n"})
+				commitOnBranch(t, repo, "master", map[string]string{"a.txt": "master\n"})
+			},
+			opts:          gitutils.MergeOptions{Strategy: gitutils.Squash},
+			wantConflicts: []string{"a.txt"},
+			wantHead:      "master",
+			wantFiles:     map[string]string{"a.txt": "master\n"},
+		},
+		{
+			name: "Squash conflict in path with spaces",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"release notes.txt": "develop\n"})
+				commitOnBranch(t, repo, "master", map[string]string{"release notes.txt": "master\n"})
+			},
+			opts:          gitutils.MergeOptions{Strategy: gitutils.Squash},
+			wantConflicts: []string{"release notes.txt"},
+			wantHead:      "master",
+			wantFiles:     map[string]string{"release notes.txt": "master\n"},
+		},
+		{
+			name: "Squash of merged branch",
+			setup: func(t *testing.T, repo *git.Repository) {
+				require.NoError(t, gitutils.CreateBranch(repo, "develop", "HEAD~1"))
+			},
+			opts:     gitutils.MergeOptions{Strategy: gitutils.Squash},
+			wantHead: "master",
+		},
+		{
+			name: "Uncommitted changes",
+			setup: func(t *testing.T, repo *git.Repository) {
+				commitOnBranch(t, repo, "develop", map[string]string{"e.txt": "feature\n"})
+				dirtyWorktree(t, repo)
+			},
+			opts:    gitutils.MergeOptions{Strategy: gitutils.FastForwardOnly},
+			wantErr: "uncommitted changes",
+		},
+		{
+			name:    "Unknown branch",
+			setup:   func(t *testing.T, repo *git.Repository) {},
+			opts:    gitutils.MergeOptions{Strategy: gitutils.Squash},
+			wantErr: "develop",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			require.NoError(t, gitutils.SetRepoIdentity(repo, "Test", "test@example.com"))
+			tc.setup(t, repo)
+			before, err := repo.Head()
+			require.NoError(t, err)
+
+			err = gitutils.Merge(repo, "develop", tc.opts)
+			switch {
+			case tc.wantConflicts != nil:
+				var conflictErr *gitutils.MergeConflictError
+				require.True(t, errors.As(err, &conflictErr), "expected a MergeConflictError, got %v", err)
+				require.Equal(t, tc.wantConflicts, conflictErr.Paths)
+				dirty, err := gitutils.IsDirty(repo)
+				require.NoError(t, err)
+				require.False(t, dirty, "aborted merge should leave a clean worktree")
+			case tc.wantErr != "":
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				if tc.wantErrIs != nil {
+					require.ErrorIs(t, err, tc.wantErrIs)
+				}
+				return
+			default:
+				require.NoError(t, err)
+				dirty, err := gitutils.IsDirty(repo)
+				require.NoError(t, err)
+				require.False(t, dirty, "merge should leave a clean worktree")
+			}
+
+			head, err := repo.Head()
+			require.NoError(t, err)
+			if tc.wantHead != "" {
+				want, err := repo.ResolveRevision(plumbing.Revision(tc.wantHead))
+				require.NoError(t, err)
+				require.Equal(t, *want, head.Hash())
+			}
+
+			w, err := repo.Worktree()
+			require.NoError(t, err)
+			for name, content := range tc.wantFiles {
+				require.Equal(t, content, readFile(t, filepath.Join(w.Filesystem.Root(), name)))
+			}
+
+			if tc.wantMessage != "" {
+				commit, err := repo.CommitObject(head.Hash())
+				require.NoError(t, err)
+				require.Contains(t, commit.Message, tc.wantMessage)
+				require.Equal(t, []plumbing.Hash{before.Hash()}, commit.ParentHashes, "squash commit should have a single parent")
+			}
+		})
+	}
+}
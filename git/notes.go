@@ -0,0 +1,267 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultNotesRef is the notes ref used by git notes, and by AddNote and
+// GetNotes when no ref is given.
+const DefaultNotesRef = "refs/notes/commits"
+
+// ErrNoteNotFound is returned by GetNote when a commit has no note.
+var ErrNoteNotFound = errors.New("note not found")
+
+// AddNote attaches a note to a commit, like `git notes add -f`,
+// replacing any note the commit already has in the notes ref. Notes
+// let automation record metadata, such as build IDs or scan results,
+// without changing commits. Notes refs aren't pushed by Push; push them
+// with a refspec such as "refs/notes/*:refs/notes/*".
+//
+// **Parameters:**
+//
+// repo: The repository containing the commit.
+// commitHash: The commit to annotate.
+// note: The content of the note.
+// ref: The notes ref to add the note to, either in full or relative to
+// refs/notes (e.g., "ci"). If empty, DefaultNotesRef is used.
+//
+// **Returns:**
+//
+// error: An error if the commit doesn't exist, no git identity is
+// configured for the repository (see RepoIdentity), or the note can't be
+// written.
+func AddNote(repo *git.Repository, commitHash plumbing.Hash, note string, ref string) error {
+	refName := notesRefName(ref)
+	if _, err := repo.CommitObject(commitHash); err != nil {
+		return fmt.Errorf("failed to get commit %s: %v", commitHash, err)
+	}
+
+	notes, parent, err := readNotes(repo, refName)
+	if err != nil {
+		return err
+	}
+
+	blobHash, err := storeBlob(repo, []byte(note))
+	if err != nil {
+		return fmt.Errorf("failed to store note: %v", err)
+	}
+	notes[commitHash] = blobHash
+
+	identity, err := RepoIdentity(repo)
+	if err != nil {
+		return fmt.Errorf("failed to get notes identity: %v", err)
+	}
+	treeHash, err := storeNotesTree(repo, notes)
+	if err != nil {
+		return fmt.Errorf("failed to store notes tree: %v", err)
+	}
+
+	signature := object.Signature{Name: identity.User, Email: identity.Email, When: time.Now()}
+	commit := &object.Commit{
+		Author:    signature,
+		Committer: signature,
+		Message:   fmt.Sprintf("Notes added by AddNote for %s\n", commitHash),
+		TreeHash:  treeHash,
+	}
+	if !parent.IsZero() {
+		commit.ParentHashes = []plumbing.Hash{parent}
+	}
+	notesCommit, err := storeObject(repo, commit)
+	if err != nil {
+		return fmt.Errorf("failed to store notes commit: %v", err)
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, notesCommit)); err != nil {
+		return fmt.Errorf("failed to update %s: %v", refName, err)
+	}
+
+	return nil
+}
+
+// GetNote returns the note attached to a commit.
+//
+// **Parameters:**
+//
+// repo: The repository containing the notes.
+// commitHash: The annotated commit.
+// ref: The notes ref to read, either in full or relative to refs/notes.
+// If empty, DefaultNotesRef is used.
+//
+// **Returns:**
+//
+// string: The content of the note.
+// error: ErrNoteNotFound if the commit has no note, or an error if the
+// notes can't be read.
+func GetNote(repo *git.Repository, commitHash plumbing.Hash, ref string) (string, error) {
+	notes, _, err := readNotes(repo, notesRefName(ref))
+	if err != nil {
+		return "", err
+	}
+
+	blobHash, ok := notes[commitHash]
+	if !ok {
+		return "", fmt.Errorf("%w for commit %s", ErrNoteNotFound, commitHash)
+	}
+
+	return readBlob(repo, blobHash)
+}
+
+// GetNotes returns every note of a notes ref, including notes written by
+// git notes.
+//
+// **Parameters:**
+//
+// repo: The repository containing the notes.
+// ref: The notes ref to read, either in full or relative to refs/notes.
+// If empty, DefaultNotesRef is used.
+//
+// **Returns:**
+//
+// map[plumbing.Hash]string: The content of the notes, keyed by the
+// annotated commit. Empty if the notes ref doesn't exist.
+// error: An error if the notes can't be read.
+func GetNotes(repo *git.Repository, ref string) (map[plumbing.Hash]string, error) {
+	notes, _, err := readNotes(repo, notesRefName(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make(map[plumbing.Hash]string, len(notes))
+	for commitHash, blobHash := range notes {
+		content, err := readBlob(repo, blobHash)
+		if err != nil {
+			return nil, err
+		}
+		contents[commitHash] = content
+	}
+
+	return contents, nil
+}
+
+// notesRefName expands ref the way git notes does.
+func notesRefName(ref string) plumbing.ReferenceName {
+	switch {
+	case ref == "":
+		return DefaultNotesRef
+	case strings.HasPrefix(ref, "refs/"):
+		return plumbing.ReferenceName(ref)
+	default:
+		return plumbing.ReferenceName("refs/notes/" + ref)
+	}
+}
+
+// readNotes returns the note blobs of a notes ref, keyed by the
+// annotated object, and the commit the ref points to. git splits the
+// names of notes into fanout directories (e.g., "ab/cdef...") once a
+// notes tree grows large, so the names are read without slashes.
+func readNotes(repo *git.Repository, refName plumbing.ReferenceName) (map[plumbing.Hash]plumbing.Hash, plumbing.Hash, error) {
+	notes := make(map[plumbing.Hash]plumbing.Hash)
+
+	ref, err := repo.Reference(refName, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return notes, plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("failed to get %s: %v", refName, err)
+	}
+
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("failed to get commit of %s: %v", refName, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("failed to get tree of %s: %v", refName, err)
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		name := strings.ReplaceAll(f.Name, "/", "")
+		if len(name) == 40 && plumbing.IsHash(name) {
+			notes[plumbing.NewHash(name)] = f.Hash
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("failed to read %s: %v", refName, err)
+	}
+
+	return notes, ref.Hash(), nil
+}
+
+// storeNotesTree stores a flat notes tree, which git reads as well as a
+// fanout one.
+func storeNotesTree(repo *git.Repository, notes map[plumbing.Hash]plumbing.Hash) (plumbing.Hash, error) {
+	tree := &object.Tree{Entries: make([]object.TreeEntry, 0, len(notes))}
+	for commitHash, blobHash := range notes {
+		tree.Entries = append(tree.Entries, object.TreeEntry{
+			Name: commitHash.String(),
+			Mode: filemode.Regular,
+			Hash: blobHash,
+		})
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool { return tree.Entries[i].Name < tree.Entries[j].Name })
+
+	return storeObject(repo, tree)
+}
+
+// storeObject encodes and stores a tree or commit.
+func storeObject(repo *git.Repository, o interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	if err := o.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// storeBlob stores content as a blob.
+func storeBlob(repo *git.Repository, content []byte) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	obj.SetSize(int64(len(content)))
+
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := w.Write(content); err != nil {
+		_ = w.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// readBlob returns the content of a blob.
+func readBlob(repo *git.Repository, hash plumbing.Hash) (string, error) {
+	blob, err := repo.BlobObject(hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get note %s: %v", hash, err)
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return "", fmt.Errorf("failed to read note %s: %v", hash, err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read note %s: %v", hash, err)
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,96 @@
+package git_test
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotes(t *testing.T) {
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+	require.NoError(t, gitutils.SetRepoIdentity(repo, "Bot", "bot@example.com"))
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	headCommit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	base := headCommit.ParentHashes[0]
+
+	require.NoError(t, gitutils.AddNote(repo, head.Hash(), `{"build":1}`, ""))
+	require.NoError(t, gitutils.AddNote(repo, base, "scanned", ""))
+	require.NoError(t, gitutils.AddNote(repo, head.Hash(), `{"build":2}`, ""))
+	require.NoError(t, gitutils.AddNote(repo, head.Hash(), "passed", "ci"))
+
+	testCases := []struct {
+		name    string
+		commit  plumbing.Hash
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{name: "Replaced note", commit: head.Hash(), want: `{"build":2}`},
+		{name: "Other commit", commit: base, want: "scanned"},
+		{name: "Short ref", commit: head.Hash(), ref: "ci", want: "passed"},
+		{name: "Full ref", commit: head.Hash(), ref: "refs/notes/ci", want: "passed"},
+		{name: "No note", commit: base, ref: "ci", wantErr: gitutils.ErrNoteNotFound},
+		{name: "No notes ref", commit: base, ref: "missing", wantErr: gitutils.ErrNoteNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := gitutils.GetNote(repo, tc.commit, tc.ref)
+			if tc.wantErr != nil {
+				require.True(t, errors.Is(err, tc.wantErr), "GetNote() error = %v, want %v", err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	notes, err := gitutils.GetNotes(repo, gitutils.DefaultNotesRef)
+	require.NoError(t, err)
+	require.Equal(t, map[plumbing.Hash]string{head.Hash(): `{"build":2}`, base: "scanned"}, notes)
+
+	ref, err := repo.Reference(gitutils.DefaultNotesRef, true)
+	require.NoError(t, err)
+	notesCommit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	require.Len(t, notesCommit.ParentHashes, 1, "each note should extend the notes history")
+
+	require.Error(t, gitutils.AddNote(repo, plumbing.NewHash(strings.Repeat("ab", 20)), "note", ""))
+}
+
+func TestNotesInteroperability(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+	require.NoError(t, gitutils.SetRepoIdentity(repo, "Bot", "bot@example.com"))
+	dir := originWorktree(t, repo)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, gitutils.AddNote(repo, head.Hash(), "from goutils", ""))
+	out, err := exec.Command("git", "-C", dir, "notes", "show", head.Hash().String()).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "from goutils", strings.TrimSpace(string(out)))
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	base := headCommit.ParentHashes[0]
+	out, err = exec.Command("git", "-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com",
+		"notes", "--ref", "ci", "add", "-m", "from git", base.String()).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	got, err := gitutils.GetNote(repo, base, "ci")
+	require.NoError(t, err)
+	require.Equal(t, "from git\n", got)
+}
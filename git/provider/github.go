@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultGitHubURL is the base URL of the GitHub REST API.
+const DefaultGitHubURL = "https://api.github.com"
+
+var _ Provider = (*GitHub)(nil)
+
+// GitHub is a Provider for a repository hosted on GitHub or GitHub
+// Enterprise Server.
+//
+// **Attributes:**
+//
+// Owner: The user or organization that owns the repository.
+// Repo: The name of the repository.
+// Token: A personal access token or GitHub App token used to authenticate.
+// BaseURL: The base URL of the REST API (e.g., DefaultGitHubURL or
+// "https://github.example.com/api/v3").
+// Client: The HTTP client used to send requests. If nil,
+// http.DefaultClient is used.
+type GitHub struct {
+	Owner   string
+	Repo    string
+	Token   string
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewGitHub creates a GitHub provider for owner/repo on github.com.
+//
+// **Parameters:**
+//
+// owner: The user or organization that owns the repository.
+// repo: The name of the repository.
+// token: The token used to authenticate, such as the value of $GITHUB_TOKEN.
+//
+// **Returns:**
+//
+// *GitHub: A GitHub provider for the repository.
+func NewGitHub(owner, repo, token string) *GitHub {
+	return &GitHub{Owner: owner, Repo: repo, Token: token, BaseURL: DefaultGitHubURL}
+}
+
+type githubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+}
+
+type githubRelease struct {
+	TagName    string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	HTMLURL    string    `json:"html_url"`
+	Draft      bool      `json:"draft"`
+	Prerelease bool      `json:"prerelease"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r githubRelease) release() Release {
+	return Release{
+		Tag:        r.TagName,
+		Name:       r.Name,
+		Body:       r.Body,
+		URL:        r.HTMLURL,
+		Draft:      r.Draft,
+		Prerelease: r.Prerelease,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// CreatePullRequest opens a pull request on the repository.
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// opts: The pull request to open.
+//
+// **Returns:**
+//
+// *PullRequest: The created pull request.
+// error: An *APIError if GitHub rejects the request, or another error if
+// it can't be sent.
+func (g *GitHub) CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+		"draft": opts.Draft,
+	}
+
+	var pr githubPullRequest
+	if err := g.api().do(ctx, http.MethodPost, g.repoPath("/pulls"), body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request %s -> %s: %w", opts.Head, opts.Base, err)
+	}
+
+	return &PullRequest{Number: pr.Number, Title: pr.Title, URL: pr.HTMLURL, Draft: pr.Draft}, nil
+}
+
+// ListReleases lists the releases of the repository, newest first.
+// Draft releases are only included if the token has push access.
+//
+// **Parameters:**
+//
+// ctx: The context for the requests.
+//
+// **Returns:**
+//
+// []Release: The releases of the repository.
+// error: An *APIError if GitHub rejects a request, or another error if
+// one can't be sent.
+func (g *GitHub) ListReleases(ctx context.Context) ([]Release, error) {
+	var releases []Release
+	for page := 1; page <= maxPages; page++ {
+		var batch []githubRelease
+		path := g.repoPath(fmt.Sprintf("/releases?per_page=100&page=%d", page))
+		if err := g.api().do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		for _, r := range batch {
+			releases = append(releases, r.release())
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+// CreateRelease creates a release on the repository.
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// opts: The release to create.
+//
+// **Returns:**
+//
+// *Release: The created release.
+// error: An *APIError if GitHub rejects the request, or another error if
+// it can't be sent.
+func (g *GitHub) CreateRelease(ctx context.Context, opts ReleaseOptions) (*Release, error) {
+	name := opts.Name
+	if name == "" {
+		name = opts.Tag
+	}
+	body := map[string]interface{}{
+		"tag_name":   opts.Tag,
+		"name":       name,
+		"body":       opts.Body,
+		"draft":      opts.Draft,
+		"prerelease": opts.Prerelease,
+	}
+	if opts.Target != "" {
+		body["target_commitish"] = opts.Target
+	}
+
+	var created githubRelease
+	if err := g.api().do(ctx, http.MethodPost, g.repoPath("/releases"), body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create release %s: %w", opts.Tag, err)
+	}
+
+	release := created.release()
+	return &release, nil
+}
+
+// GetLatestTag returns the highest semver tag of the repository. If the
+// repository has no semver tags, the most recent tag GitHub lists is
+// returned instead.
+//
+// **Parameters:**
+//
+// ctx: The context for the requests.
+//
+// **Returns:**
+//
+// string: The latest tag.
+// error: An error if the repository has no tags or they can't be listed.
+func (g *GitHub) GetLatestTag(ctx context.Context) (string, error) {
+	var tags []string
+	for page := 1; page <= maxPages; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		path := g.repoPath(fmt.Sprintf("/tags?per_page=100&page=%d", page))
+		if err := g.api().do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return "", fmt.Errorf("failed to list tags: %w", err)
+		}
+		for _, t := range batch {
+			tags = append(tags, t.Name)
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	if len(tags) == 0 {
+		return "", fmt.Errorf("no tags found in %s/%s", g.Owner, g.Repo)
+	}
+	if latest := latestSemver(tags); latest != "" {
+		return latest, nil
+	}
+
+	return tags[0], nil
+}
+
+// Do authenticates and sends a request to an endpoint of the GitHub API
+// that Provider doesn't cover, such as release asset uploads or issue
+// comments.
+//
+// **Parameters:**
+//
+// req: The request to send. Its URL must be absolute, e.g., built from
+// BaseURL or an upload URL returned by the API.
+// out: The value the JSON response is decoded into. May be nil.
+//
+// **Returns:**
+//
+// error: An *APIError if GitHub rejects the request, or another error if
+// it can't be sent.
+func (g *GitHub) Do(req *http.Request, out interface{}) error {
+	return g.api().send(req, out)
+}
+
+func (g *GitHub) repoPath(suffix string) string {
+	return fmt.Sprintf("/repos/%s/%s%s", url.PathEscape(g.Owner), url.PathEscape(g.Repo), suffix)
+}
+
+func (g *GitHub) api() apiClient {
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitHubURL
+	}
+
+	return apiClient{
+		baseURL: baseURL,
+		client:  g.Client,
+		auth: func(req *http.Request) {
+			req.Header.Set("Accept", "application/vnd.github+json")
+			req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+			if g.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+g.Token)
+			}
+		},
+	}
+}
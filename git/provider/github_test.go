@@ -0,0 +1,191 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/l50/goutils/v2/git/provider"
+)
+
+// newGitHubServer returns a GitHub provider for octo/repo backed by a
+// test server that serves handler and fails requests without the token.
+func newGitHubServer(t *testing.T, handler http.HandlerFunc) *provider.GitHub {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	gh := provider.NewGitHub("octo", "repo", "secret")
+	gh.BaseURL = srv.URL
+	gh.Client = srv.Client()
+	return gh
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	var got map[string]interface{}
+	gh := newGitHubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/octo/repo/pulls" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":7,"title":"Add feature","html_url":"https://github.com/octo/repo/pull/7","draft":true}`)
+	})
+
+	pr, err := gh.CreatePullRequest(context.Background(), provider.PullRequestOptions{
+		Title: "Add feature", Body: "Details", Head: "feature", Base: "main", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() failed: %v", err)
+	}
+
+	want := provider.PullRequest{Number: 7, Title: "Add feature", URL: "https://github.com/octo/repo/pull/7", Draft: true}
+	if *pr != want {
+		t.Errorf("CreatePullRequest() = %+v, want %+v", *pr, want)
+	}
+	if got["head"] != "feature" || got["base"] != "main" || got["draft"] != true || got["body"] != "Details" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitHubReleases(t *testing.T) {
+	var created map[string]interface{}
+	gh := newGitHubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/repo/releases":
+			if r.URL.Query().Get("page") != "1" {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprint(w, `[
+				{"tag_name":"v1.1.0","name":"v1.1.0","body":"notes","html_url":"https://example.com/v1.1.0","created_at":"2024-05-01T00:00:00Z"},
+				{"tag_name":"v1.1.0-rc1","name":"RC","prerelease":true,"created_at":"2024-04-01T00:00:00Z"}
+			]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/repo/releases":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"tag_name":%q,"name":%q,"body":%q,"html_url":"https://example.com/new"}`,
+				created["tag_name"], created["name"], created["body"])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	releases, err := gh.ListReleases(context.Background())
+	if err != nil {
+		t.Fatalf("ListReleases() failed: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Tag != "v1.1.0" || releases[0].Body != "notes" ||
+		!releases[1].Prerelease || releases[0].CreatedAt.Year() != 2024 {
+		t.Errorf("ListReleases() = %+v", releases)
+	}
+
+	release, err := gh.CreateRelease(context.Background(), provider.ReleaseOptions{Tag: "v1.2.0", Target: "main", Body: "changes"})
+	if err != nil {
+		t.Fatalf("CreateRelease() failed: %v", err)
+	}
+	if release.Tag != "v1.2.0" || release.Name != "v1.2.0" || release.URL != "https://example.com/new" {
+		t.Errorf("CreateRelease() = %+v", release)
+	}
+	if created["target_commitish"] != "main" || created["draft"] != false {
+		t.Errorf("unexpected request body: %v", created)
+	}
+}
+
+func TestGitHubGetLatestTag(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tags    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Highest semver tag",
+			tags: `[{"name":"v1.10.0-rc1"},{"name":"v1.9.3"},{"name":"nightly"},{"name":"v1.10.0"},{"name":"v1.2.0"}]`,
+			want: "v1.10.0",
+		},
+		{
+			name: "No semver tags",
+			tags: `[{"name":"nightly"},{"name":"stable"}]`,
+			want: "nightly",
+		},
+		{
+			name:    "No tags",
+			tags:    `[]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gh := newGitHubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tc.tags)
+			})
+
+			got, err := gh.GetLatestTag(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetLatestTag() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("GetLatestTag() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGitHubAPIError(t *testing.T) {
+	gh := newGitHubServer(t, nil)
+	gh.Token = "wrong"
+
+	_, err := gh.ListReleases(context.Background())
+	var apiErr *provider.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("ListReleases() error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Bad credentials" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestGitHubDo(t *testing.T) {
+	gh := newGitHubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repos/octo/repo/releases/tags/v1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("unexpected Accept header %q", r.Header.Get("Accept"))
+		}
+		fmt.Fprint(w, `{"id":7}`)
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, gh.BaseURL+"/repos/octo/repo/releases/tags/v1.0.0", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	var release struct {
+		ID int `json:"id"`
+	}
+	if err := gh.Do(req, &release); err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	if release.ID != 7 {
+		t.Errorf("Do() decoded ID %d, want 7", release.ID)
+	}
+}
@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultGitLabURL is the base URL of the GitLab.com REST API.
+const DefaultGitLabURL = "https://gitlab.com/api/v4"
+
+var _ Provider = (*GitLab)(nil)
+
+// GitLab is a Provider for a project hosted on GitLab.com or a
+// self-managed GitLab instance. Pull requests are GitLab merge requests.
+//
+// **Attributes:**
+//
+// Project: The ID or full path of the project (e.g., "group/project").
+// Token: A personal, project, or group access token used to authenticate.
+// BaseURL: The base URL of the REST API (e.g., DefaultGitLabURL or
+// "https://gitlab.example.com/api/v4").
+// Client: The HTTP client used to send requests. If nil,
+// http.DefaultClient is used.
+type GitLab struct {
+	Project string
+	Token   string
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewGitLab creates a GitLab provider for a project on GitLab.com.
+//
+// **Parameters:**
+//
+// project: The ID or full path of the project (e.g., "group/project").
+// token: The token used to authenticate, such as the value of $GITLAB_TOKEN.
+//
+// **Returns:**
+//
+// *GitLab: A GitLab provider for the project.
+func NewGitLab(project, token string) *GitLab {
+	return &GitLab{Project: project, Token: token, BaseURL: DefaultGitLabURL}
+}
+
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	WebURL string `json:"web_url"`
+	Draft  bool   `json:"draft"`
+}
+
+type gitlabRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+func (r gitlabRelease) release() Release {
+	return Release{
+		Tag:        r.TagName,
+		Name:       r.Name,
+		Body:       r.Description,
+		URL:        r.Links.Self,
+		Prerelease: r.Upcoming,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// CreatePullRequest opens a merge request on the project. GitLab marks
+// merge requests as drafts by their title, so Draft prefixes the title
+// with "Draft: ".
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// opts: The merge request to open.
+//
+// **Returns:**
+//
+// *PullRequest: The created merge request, numbered by its IID.
+// error: An *APIError if GitLab rejects the request, or another error if
+// it can't be sent.
+func (g *GitLab) CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+	body := map[string]string{
+		"title":         title,
+		"description":   opts.Body,
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+	}
+
+	var mr gitlabMergeRequest
+	if err := g.api().do(ctx, http.MethodPost, g.projectPath("/merge_requests"), body, &mr); err != nil {
+		return nil, fmt.Errorf("failed to create merge request %s -> %s: %w", opts.Head, opts.Base, err)
+	}
+
+	return &PullRequest{Number: mr.IID, Title: mr.Title, URL: mr.WebURL, Draft: mr.Draft}, nil
+}
+
+// ListReleases lists the releases of the project, newest first.
+//
+// **Parameters:**
+//
+// ctx: The context for the requests.
+//
+// **Returns:**
+//
+// []Release: The releases of the project.
+// error: An *APIError if GitLab rejects a request, or another error if
+// one can't be sent.
+func (g *GitLab) ListReleases(ctx context.Context) ([]Release, error) {
+	var releases []Release
+	for page := 1; page <= maxPages; page++ {
+		var batch []gitlabRelease
+		path := g.projectPath(fmt.Sprintf("/releases?per_page=100&page=%d", page))
+		if err := g.api().do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		for _, r := range batch {
+			releases = append(releases, r.release())
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+// CreateRelease creates a release on the project. GitLab has no draft or
+// pre-release flags, so opts.Draft and opts.Prerelease are ignored.
+//
+// **Parameters:**
+//
+// ctx: The context for the request.
+// opts: The release to create. Target is required if Tag doesn't exist.
+//
+// **Returns:**
+//
+// *Release: The created release.
+// error: An *APIError if GitLab rejects the request, or another error if
+// it can't be sent.
+func (g *GitLab) CreateRelease(ctx context.Context, opts ReleaseOptions) (*Release, error) {
+	name := opts.Name
+	if name == "" {
+		name = opts.Tag
+	}
+	body := map[string]string{
+		"tag_name":    opts.Tag,
+		"name":        name,
+		"description": opts.Body,
+	}
+	if opts.Target != "" {
+		body["ref"] = opts.Target
+	}
+
+	var created gitlabRelease
+	if err := g.api().do(ctx, http.MethodPost, g.projectPath("/releases"), body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create release %s: %w", opts.Tag, err)
+	}
+
+	release := created.release()
+	return &release, nil
+}
+
+// GetLatestTag returns the highest semver tag of the project. If the
+// project has no semver tags, the most recently updated tag is returned
+// instead.
+//
+// **Parameters:**
+//
+// ctx: The context for the requests.
+//
+// **Returns:**
+//
+// string: The latest tag.
+// error: An error if the project has no tags or they can't be listed.
+func (g *GitLab) GetLatestTag(ctx context.Context) (string, error) {
+	var tags []string
+	for page := 1; page <= maxPages; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		path := g.projectPath(fmt.Sprintf("/repository/tags?order_by=updated&sort=desc&per_page=100&page=%d", page))
+		if err := g.api().do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return "", fmt.Errorf("failed to list tags: %w", err)
+		}
+		for _, t := range batch {
+			tags = append(tags, t.Name)
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	if len(tags) == 0 {
+		return "", fmt.Errorf("no tags found in %s", g.Project)
+	}
+	if latest := latestSemver(tags); latest != "" {
+		return latest, nil
+	}
+
+	return tags[0], nil
+}
+
+// projectPath returns the API path of the project, with its full path
+// URL-encoded as an ID.
+func (g *GitLab) projectPath(suffix string) string {
+	return "/projects/" + url.PathEscape(g.Project) + suffix
+}
+
+func (g *GitLab) api() apiClient {
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+
+	return apiClient{
+		baseURL: baseURL,
+		client:  g.Client,
+		auth: func(req *http.Request) {
+			if g.Token != "" {
+				req.Header.Set("PRIVATE-TOKEN", g.Token)
+			}
+		},
+	}
+}
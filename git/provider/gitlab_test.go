@@ -0,0 +1,150 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/git/provider"
+)
+
+const gitlabProjectPath = "/projects/group%2Fproject"
+
+// newGitLabServer returns a GitLab provider for group/project backed by
+// a test server that serves handler and fails requests without the token.
+func newGitLabServer(t *testing.T, handler http.HandlerFunc) *provider.GitLab {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"401 Unauthorized"}`)
+			return
+		}
+		if !strings.HasPrefix(r.URL.EscapedPath(), gitlabProjectPath+"/") {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	gl := provider.NewGitLab("group/project", "secret")
+	gl.BaseURL = srv.URL
+	gl.Client = srv.Client()
+	return gl
+}
+
+func TestGitLabCreatePullRequest(t *testing.T) {
+	var got map[string]string
+	gl := newGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/merge_requests") {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"iid":3,"title":%q,"web_url":"https://gitlab.com/group/project/-/merge_requests/3","draft":true}`, got["title"])
+	})
+
+	pr, err := gl.CreatePullRequest(context.Background(), provider.PullRequestOptions{
+		Title: "Add feature", Head: "feature", Base: "main", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() failed: %v", err)
+	}
+
+	want := provider.PullRequest{
+		Number: 3, Title: "Draft: Add feature", URL: "https://gitlab.com/group/project/-/merge_requests/3", Draft: true,
+	}
+	if *pr != want {
+		t.Errorf("CreatePullRequest() = %+v, want %+v", *pr, want)
+	}
+	if got["source_branch"] != "feature" || got["target_branch"] != "main" {
+		t.Errorf("unexpected request body: %v", got)
+	}
+}
+
+func TestGitLabReleases(t *testing.T) {
+	var created map[string]string
+	gl := newGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/releases") {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"tag_name":"v2.0.0","name":"Two","description":"notes",
+				"created_at":"2024-05-01T00:00:00Z","_links":{"self":"https://example.com/v2.0.0"}}]`)
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"tag_name":%q,"name":%q,"description":%q}`,
+				created["tag_name"], created["name"], created["description"])
+		}
+	})
+
+	releases, err := gl.ListReleases(context.Background())
+	if err != nil {
+		t.Fatalf("ListReleases() failed: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Tag != "v2.0.0" || releases[0].Body != "notes" ||
+		releases[0].URL != "https://example.com/v2.0.0" {
+		t.Errorf("ListReleases() = %+v", releases)
+	}
+
+	release, err := gl.CreateRelease(context.Background(), provider.ReleaseOptions{
+		Tag: "v2.1.0", Name: "Two one", Target: "main", Body: "changes",
+	})
+	if err != nil {
+		t.Fatalf("CreateRelease() failed: %v", err)
+	}
+	if release.Tag != "v2.1.0" || release.Name != "Two one" || release.Body != "changes" {
+		t.Errorf("CreateRelease() = %+v", release)
+	}
+	if created["ref"] != "main" {
+		t.Errorf("unexpected request body: %v", created)
+	}
+}
+
+func TestGitLabGetLatestTag(t *testing.T) {
+	gl := newGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/repository/tags") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"v0.9.0"},{"name":"v1.0.0"},{"name":"v1.0.0-beta"}]`)
+	})
+
+	got, err := gl.GetLatestTag(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestTag() failed: %v", err)
+	}
+	if got != "v1.0.0" {
+		t.Errorf("GetLatestTag() = %q, want %q", got, "v1.0.0")
+	}
+}
+
+func TestGitLabAPIError(t *testing.T) {
+	gl := newGitLabServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"message":"Release already exists"}`)
+	})
+
+	_, err := gl.CreateRelease(context.Background(), provider.ReleaseOptions{Tag: "v1.0.0"})
+	var apiErr *provider.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreateRelease() error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusConflict || apiErr.Message != "Release already exists" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+}
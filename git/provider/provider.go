@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider is a git hosting service, such as GitHub or GitLab, that
+// pull requests and releases can be managed on through its REST API.
+//
+// **Attributes:**
+//
+// CreatePullRequest: Opens a pull (or merge) request.
+// ListReleases: Lists the releases of the repository, newest first.
+// CreateRelease: Creates a release for a tag.
+// GetLatestTag: Returns the highest semver tag of the repository.
+type Provider interface {
+	CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error)
+	ListReleases(ctx context.Context) ([]Release, error)
+	CreateRelease(ctx context.Context, opts ReleaseOptions) (*Release, error)
+	GetLatestTag(ctx context.Context) (string, error)
+}
+
+// PullRequestOptions describes a pull request to open.
+//
+// **Attributes:**
+//
+// Title: The title of the pull request.
+// Body: The description of the pull request.
+// Head: The branch containing the changes.
+// Base: The branch the changes are merged into.
+// Draft: Whether to open the pull request as a draft.
+type PullRequestOptions struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+	Draft bool
+}
+
+// PullRequest is a pull request (or GitLab merge request).
+//
+// **Attributes:**
+//
+// Number: The number of the pull request within the repository.
+// Title: The title of the pull request.
+// URL: The web URL of the pull request.
+// Draft: Whether the pull request is a draft.
+type PullRequest struct {
+	Number int
+	Title  string
+	URL    string
+	Draft  bool
+}
+
+// ReleaseOptions describes a release to create.
+//
+// **Attributes:**
+//
+// Tag: The tag to release. It's created from Target if it doesn't exist.
+// Target: The branch or commit to create Tag from. An empty string uses
+// the default branch.
+// Name: The name of the release. An empty string uses Tag.
+// Body: The release notes.
+// Draft: Whether to create the release as a draft (GitHub only).
+// Prerelease: Whether to mark the release as a pre-release (GitHub only).
+type ReleaseOptions struct {
+	Tag        string
+	Target     string
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+}
+
+// Release is a release of a repository.
+//
+// **Attributes:**
+//
+// Tag: The tag the release is for.
+// Name: The name of the release.
+// Body: The release notes.
+// URL: The web URL of the release.
+// Draft: Whether the release is a draft.
+// Prerelease: Whether the release is a pre-release.
+// CreatedAt: When the release was created.
+type Release struct {
+	Tag        string
+	Name       string
+	Body       string
+	URL        string
+	Draft      bool
+	Prerelease bool
+	CreatedAt  time.Time
+}
+
+// APIError is returned when a provider's API responds with an error
+// status.
+//
+// **Attributes:**
+//
+// StatusCode: The HTTP status code of the response.
+// Message: The error message from the response body.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns a description of the API error.
+//
+// **Returns:**
+//
+// string: The status code and message of the error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// maxPages bounds how many pages of results are fetched from a listing
+// endpoint.
+const maxPages = 50
+
+// apiClient sends JSON requests to a REST API.
+type apiClient struct {
+	baseURL string
+	client  *http.Client
+	auth    func(req *http.Request)
+}
+
+// do sends a request with an optional JSON body to path, relative to the
+// base URL, and decodes the JSON response into out when it isn't nil.
+func (c apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := strings.TrimSuffix(c.baseURL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(req, out)
+}
+
+// send authenticates and sends req, and decodes the JSON response into
+// out when it isn't nil. Error statuses are returned as an *APIError.
+func (c apiClient) send(req *http.Request, out interface{}) error {
+	if c.auth != nil {
+		c.auth(req)
+	}
+
+	client := c.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v", req.Method, req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %v", req.URL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response from %s: %v", req.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// errorMessage extracts the error message from a GitHub or GitLab error
+// response body, falling back to the raw body.
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		var msg string
+		if json.Unmarshal(parsed.Message, &msg) == nil && msg != "" {
+			return msg
+		}
+		// GitLab returns validation errors as an object or array.
+		if len(parsed.Message) > 0 {
+			return string(parsed.Message)
+		}
+		if parsed.Error != "" {
+			return parsed.Error
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+// latestSemver returns the highest "vMAJOR.MINOR.PATCH" version among
+// tags, or an empty string if none of them are semver tags. Pre-release
+// versions rank below the release they precede.
+func latestSemver(tags []string) string {
+	var latest string
+	var latestParts [3]int
+	var latestPre bool
+	for _, tag := range tags {
+		parts, pre, ok := parseSemver(tag)
+		if !ok {
+			continue
+		}
+		if latest == "" || compareSemver(parts, pre, latestParts, latestPre) > 0 {
+			latest, latestParts, latestPre = tag, parts, pre
+		}
+	}
+
+	return latest
+}
+
+// parseSemver parses a "vMAJOR.MINOR.PATCH" version, reporting whether it
+// has a pre-release suffix. Build metadata is ignored.
+func parseSemver(version string) ([3]int, bool, bool) {
+	var parts [3]int
+	core := strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core = core[:i]
+	}
+	pre := false
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		core, pre = core[:i], true
+	}
+
+	fields := strings.Split(core, ".")
+	if len(fields) != 3 {
+		return parts, false, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false, false
+		}
+		parts[i] = n
+	}
+
+	return parts, pre, true
+}
+
+func compareSemver(a [3]int, aPre bool, b [3]int, bPre bool) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case bPre:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,478 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// RebaseConflictError is returned by RebaseOnto when a commit of the
+// current branch conflicts with the upstream branch. Nothing is
+// rewritten in that case, so the branch and worktree are left as they
+// were before RebaseOnto was called.
+//
+// **Attributes:**
+//
+// Upstream: The upstream branch the current branch was rebased onto,
+// e.g., "origin/main".
+// Commit: The commit of the current branch that failed to apply.
+// Subject: The first line of the message of Commit.
+// Paths: The conflicting paths, relative to the worktree root.
+type RebaseConflictError struct {
+	Upstream string
+	Commit   plumbing.Hash
+	Subject  string
+	Paths    []string
+}
+
+// Error describes the conflicting commit and paths.
+//
+// **Returns:**
+//
+// string: The error message.
+func (e *RebaseConflictError) Error() string {
+	return fmt.Sprintf("rebasing %s (%s) onto %s conflicts in: %s",
+		e.Commit.String()[:7], e.Subject, e.Upstream, strings.Join(e.Paths, ", "))
+}
+
+// RebaseOnto fetches a branch from a remote and rebases the current
+// branch onto it, e.g., to keep a bot-maintained branch up to date with
+// the default branch. The commits of the current branch are replayed
+// onto the fetched branch one at a time, like `git rebase` does, and
+// commits whose changes are already upstream are dropped. Changes are
+// merged per file: a commit conflicts if it changes a file that was
+// changed differently upstream, even if the changes don't overlap.
+// Conflicts aren't resolved: a *RebaseConflictError is returned and the
+// branch is left untouched.
+//
+// **Parameters:**
+//
+// repo: Repository whose current branch is rebased.
+// remote: The remote to fetch from (e.g., "origin").
+// upstreamBranch: The branch on remote to rebase onto (e.g., "main").
+// auth: Authentication method for the fetch. If nil, no authentication
+// is used.
+//
+// **Returns:**
+//
+// bool: Whether the current branch changed. It's false if the branch
+// already contained the upstream branch.
+// error: Error if HEAD is detached, the worktree has uncommitted changes
+// to tracked files, the fetch fails, the branch contains merge commits,
+// or the rebase conflicts.
+func RebaseOnto(repo *git.Repository, remote, upstreamBranch string, auth transport.AuthMethod) (bool, error) {
+	if repo == nil {
+		return false, errors.New("repository is nil")
+	}
+	branch, err := CurrentBranch(repo)
+	if err != nil {
+		return false, err
+	}
+
+	files, err := Status(repo)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if file.Worktree != StateUntracked {
+			return false, fmt.Errorf("error rebasing %s: the worktree has uncommitted changes", branch)
+		}
+	}
+
+	upstream := remote + "/" + upstreamBranch
+	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s", upstreamBranch, upstream))
+	err = repo.Fetch(&git.FetchOptions{RemoteName: remote, RefSpecs: []config.RefSpec{refSpec}, Auth: auth})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return false, fmt.Errorf("error fetching %s: %v", upstream, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("failed to get repo head: %v", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to get head commit: %v", err)
+	}
+	upstreamRef, err := repo.Reference(plumbing.NewRemoteReferenceName(remote, upstreamBranch), true)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %v", upstream, err)
+	}
+	upstreamCommit, err := repo.CommitObject(upstreamRef.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s commit: %v", upstream, err)
+	}
+
+	bases, err := headCommit.MergeBase(upstreamCommit)
+	if err != nil {
+		return false, fmt.Errorf("failed to find the merge base of %s and %s: %v", branch, upstream, err)
+	}
+	if len(bases) == 0 {
+		return false, fmt.Errorf("error rebasing %s: it has no history in common with %s", branch, upstream)
+	}
+	if bases[0].Hash == upstreamCommit.Hash {
+		// The branch already contains the upstream branch.
+		return false, nil
+	}
+
+	commits, err := commitsSince(headCommit, bases[0].Hash)
+	if err != nil {
+		return false, err
+	}
+
+	newHead := upstreamCommit.Hash
+	tree, err := flattenTree(upstreamCommit)
+	if err != nil {
+		return false, err
+	}
+	for _, commit := range commits {
+		var applied bool
+		applied, err = cherryPick(commit, tree, upstream)
+		if err != nil {
+			return false, err
+		}
+		if !applied {
+			// Like `git rebase`, drop commits whose changes are already
+			// upstream.
+			continue
+		}
+		if newHead, err = writeRebasedCommit(repo, commit, tree, newHead); err != nil {
+			return false, err
+		}
+	}
+
+	if newHead == head.Hash() {
+		return false, nil
+	}
+	headFiles, err := flattenTree(headCommit)
+	if err != nil {
+		return false, err
+	}
+	if err := checkoutTree(repo, headFiles, tree, newHead); err != nil {
+		return false, fmt.Errorf("failed to check out rebased %s: %v", branch, err)
+	}
+
+	return true, nil
+}
+
+// treeFile is a non-tree entry of a flattened tree.
+type treeFile struct {
+	mode filemode.FileMode
+	hash plumbing.Hash
+}
+
+// commitsSince returns the commits reachable from head but not from
+// base, oldest first. Only linear history can be replayed.
+func commitsSince(head *object.Commit, base plumbing.Hash) ([]*object.Commit, error) {
+	var commits []*object.Commit
+	for commit := head; commit.Hash != base; {
+		if commit.NumParents() != 1 {
+			return nil, fmt.Errorf("error rebasing: %s is a merge or root commit and can't be replayed", commit.Hash.String()[:7])
+		}
+		commits = append(commits, commit)
+
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of %s: %v", commit.Hash, err)
+		}
+		commit = parent
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// flattenTree returns the files of the tree of a commit keyed by path.
+func flattenTree(commit *object.Commit) (map[string]treeFile, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %v", commit.Hash, err)
+	}
+
+	files := make(map[string]treeFile)
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk tree of %s: %v", commit.Hash, err)
+		}
+		if entry.Mode != filemode.Dir {
+			files[name] = treeFile{mode: entry.Mode, hash: entry.Hash}
+		}
+	}
+
+	return files, nil
+}
+
+// cherryPick applies the changes commit made to its parent to tree,
+// reporting whether tree changed.
+func cherryPick(commit *object.Commit, tree map[string]treeFile, upstream string) (bool, error) {
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return false, fmt.Errorf("failed to get parent of %s: %v", commit.Hash, err)
+	}
+	before, err := flattenTree(parent)
+	if err != nil {
+		return false, err
+	}
+	after, err := flattenTree(commit)
+	if err != nil {
+		return false, err
+	}
+
+	applied, conflicts := applyChanges(tree, before, after)
+	if len(conflicts) > 0 {
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		return false, &RebaseConflictError{Upstream: upstream, Commit: commit.Hash, Subject: subject, Paths: conflicts}
+	}
+
+	return applied, nil
+}
+
+// applyChanges applies the changes from before to after to tree,
+// reporting whether tree changed and the sorted conflicting paths. A
+// path conflicts if both tree and after changed it from before, to
+// different contents. tree is left as it was if any path conflicts.
+func applyChanges(tree, before, after map[string]treeFile) (bool, []string) {
+	changed := make(map[string]bool)
+	for p, file := range before {
+		if after[p] != file {
+			changed[p] = true
+		}
+	}
+	for p, file := range after {
+		if before[p] != file {
+			changed[p] = true
+		}
+	}
+
+	var conflicts []string
+	for p := range changed {
+		ours, theirs := tree[p], after[p]
+		if ours != before[p] && ours != theirs {
+			conflicts = append(conflicts, p)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return false, conflicts
+	}
+
+	applied := false
+	for p := range changed {
+		theirs, ok := after[p]
+		if tree[p] == theirs {
+			continue
+		}
+		applied = true
+		if ok {
+			tree[p] = theirs
+		} else {
+			delete(tree, p)
+		}
+	}
+
+	return applied, nil
+}
+
+// writeRebasedCommit stores tree and a copy of commit with tree as its
+// tree and parent as its parent, returning the hash of the copy. The
+// author is kept, and the repository's identity becomes the committer.
+func writeRebasedCommit(repo *git.Repository, commit *object.Commit, tree map[string]treeFile, parent plumbing.Hash) (plumbing.Hash, error) {
+	committer := commit.Committer
+	if identity, err := RepoIdentity(repo); err == nil {
+		committer.Name, committer.Email = identity.User, identity.Email
+	}
+	committer.When = time.Now()
+
+	hash, err := writeCommit(repo, tree, parent, commit.Author, committer, commit.Message)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to write rebased %s: %v", commit.Hash, err)
+	}
+
+	return hash, nil
+}
+
+// writeCommit stores tree and a commit of it with the input parent,
+// signatures, and message, returning the hash of the commit.
+func writeCommit(repo *git.Repository, tree map[string]treeFile, parent plumbing.Hash, author, committer object.Signature, message string) (plumbing.Hash, error) {
+	treeHash, err := writeTree(repo, tree, "")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	commit := &object.Commit{
+		Author:       author,
+		Committer:    committer,
+		Message:      message,
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{parent},
+	}
+	obj := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode commit: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store commit: %v", err)
+	}
+
+	return hash, nil
+}
+
+// writeTree stores the tree of the directory dir of the flattened tree
+// files and its subtrees, returning the hash of the tree.
+func writeTree(repo *git.Repository, files map[string]treeFile, dir string) (plumbing.Hash, error) {
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	var entries []object.TreeEntry
+	subdirs := make(map[string]bool)
+	for p, file := range files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		name, _, nested := strings.Cut(strings.TrimPrefix(p, prefix), "/")
+		if !nested {
+			entries = append(entries, object.TreeEntry{Name: name, Mode: file.mode, Hash: file.hash})
+			continue
+		}
+		if subdirs[name] {
+			continue
+		}
+		subdirs[name] = true
+		hash, err := writeTree(repo, files, path.Join(dir, name))
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: hash})
+	}
+
+	// Git orders entries by name, comparing directories as if their
+	// names ended with a slash.
+	sortKey := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+
+	tree := &object.Tree{Entries: entries}
+	obj := repo.Storer.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode tree: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store tree: %v", err)
+	}
+
+	return hash, nil
+}
+
+// checkoutTree updates the tracked files of the worktree from before to
+// after and then points the current branch and the index at head.
+// Untracked files are kept, which a hard reset wouldn't do.
+func checkoutTree(repo *git.Repository, before, after map[string]treeFile, head plumbing.Hash) error {
+	w, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	for p, file := range before {
+		if _, ok := after[p]; ok || file.mode == filemode.Submodule {
+			continue
+		}
+		if err := w.Filesystem.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removeEmptyDirs(w.Filesystem, path.Dir(p))
+	}
+	for p, file := range after {
+		if before[p] == file || file.mode == filemode.Submodule {
+			continue
+		}
+		if err := writeWorktreeFile(repo, w.Filesystem, p, file); err != nil {
+			return err
+		}
+	}
+
+	// A mixed reset moves the branch and the index without touching the
+	// worktree.
+	return w.Reset(&git.ResetOptions{Commit: head, Mode: git.MixedReset})
+}
+
+// writeWorktreeFile writes the blob of file to name in the worktree.
+func writeWorktreeFile(repo *git.Repository, wfs billy.Filesystem, name string, file treeFile) error {
+	blob, err := repo.BlobObject(file.hash)
+	if err != nil {
+		return fmt.Errorf("failed to get blob of %s: %v", name, err)
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := wfs.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := wfs.MkdirAll(path.Dir(name), 0755); err != nil {
+		return err
+	}
+	if file.mode == filemode.Symlink {
+		target, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return wfs.Symlink(string(target), name)
+	}
+
+	perm := os.FileMode(0644)
+	if file.mode == filemode.Executable {
+		perm = 0755
+	}
+	f, err := wfs.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, reader); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// removeEmptyDirs removes dir and its parents in the worktree while they
+// are empty, like git does when checking out the removal of a file.
+func removeEmptyDirs(wfs billy.Filesystem, dir string) {
+	for ; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		entries, err := wfs.ReadDir(dir)
+		if err != nil || len(entries) > 0 || wfs.Remove(dir) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package git_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+	"github.com/l50/goutils/v2/sys"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebaseOnto(t *testing.T) {
+	testCases := []struct {
+		name          string
+		upstream      map[string]string
+		bot           map[string]string
+		dirty         bool
+		wantChanged   bool
+		wantConflicts []string
+		wantErr       string
+		wantFiles     map[string]string
+	}{
+		{
+			name:        "Rebase onto updated upstream",
+			upstream:    map[string]string{"e.txt": "upstream\n"},
+			bot:         map[string]string{"f.txt": "bot\n"},
+			wantChanged: true,
+			wantFiles:   map[string]string{"e.txt": "upstream\n", "f.txt": "bot\n"},
+		},
+		{
+			name:      "Up to date",
+			bot:       map[string]string{"f.txt": "bot\n"},
+			wantFiles: map[string]string{"f.txt": "bot\n"},
+		},
+		{
+			name:          "Conflict",
+			upstream:      map[string]string{"a.txt": "upstream\n"},
+			bot:           map[string]string{"a.txt": "bot\n"},
+			wantConflicts: []string{"a.txt"},
+			wantFiles:     map[string]string{"a.txt": "bot\n"},
+		},
+		{
+			name:    "Uncommitted changes",
+			bot:     map[string]string{"f.txt": "bot\n"},
+			dirty:   true,
+			wantErr: "uncommitted changes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			origin := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			originDir := originWorktree(t, origin)
+
+			cloneDir := filepath.Join(t.TempDir(), "clone")
+			repo, err := git.PlainClone(cloneDir, false, &git.CloneOptions{URL: originDir})
+			require.NoError(t, err)
+			require.NoError(t, gitutils.SetRepoIdentity(repo, "Bot", "bot@example.com"))
+
+			require.NoError(t, gitutils.CreateBranch(repo, "bot", ""))
+			require.NoError(t, gitutils.CheckoutBranch(repo, "bot"))
+			commitOnBranch(t, repo, "bot", tc.bot)
+			require.NoError(t, gitutils.CheckoutBranch(repo, "bot"))
+			if tc.upstream != nil {
+				commitOnBranch(t, origin, "master", tc.upstream)
+			}
+			if tc.dirty {
+				dirtyWorktree(t, repo)
+			}
+			before, err := repo.Head()
+			require.NoError(t, err)
+
+			changed, err := gitutils.RebaseOnto(repo, "origin", "master", nil)
+			switch {
+			case tc.wantConflicts != nil:
+				var conflictErr *gitutils.RebaseConflictError
+				require.True(t, errors.As(err, &conflictErr), "expected a RebaseConflictError, got %v", err)
+				require.Equal(t, tc.wantConflicts, conflictErr.Paths)
+				require.Equal(t, "origin/master", conflictErr.Upstream)
+				require.Equal(t, "update bot", conflictErr.Subject)
+				require.Equal(t, before.Hash(), conflictErr.Commit)
+				dirty, err := gitutils.IsDirty(repo)
+				require.NoError(t, err)
+				require.False(t, dirty, "aborted rebase should leave a clean worktree")
+			case tc.wantErr != "":
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				return
+			default:
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantChanged, changed)
+
+			branch, err := gitutils.CurrentBranch(repo)
+			require.NoError(t, err)
+			require.Equal(t, "bot", branch)
+
+			head, err := repo.Head()
+			require.NoError(t, err)
+			if !tc.wantChanged {
+				require.Equal(t, before.Hash(), head.Hash())
+			} else {
+				upstream, err := repo.ResolveRevision(plumbing.Revision("origin/master"))
+				require.NoError(t, err)
+				commit, err := repo.CommitObject(head.Hash())
+				require.NoError(t, err)
+				require.Equal(t, []plumbing.Hash{*upstream}, commit.ParentHashes)
+			}
+
+			for name, content := range tc.wantFiles {
+				require.Equal(t, content, readFile(t, filepath.Join(cloneDir, name)))
+			}
+		})
+	}
+}
+
+func TestRebaseOntoNestedPathsAndRemote(t *testing.T) {
+	origin := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+	originDir := originWorktree(t, origin)
+	require.NoError(t, os.MkdirAll(filepath.Join(originDir, "docs"), 0755))
+
+	cloneDir := filepath.Join(t.TempDir(), "clone")
+	repo, err := git.PlainClone(cloneDir, false, &git.CloneOptions{URL: originDir, RemoteName: "upstream"})
+	require.NoError(t, err)
+	require.NoError(t, gitutils.SetRepoIdentity(repo, "Bot", "bot@example.com"))
+	require.NoError(t, os.MkdirAll(filepath.Join(cloneDir, "docs"), 0755))
+
+	commitOnBranch(t, repo, "bot", map[string]string{"docs/bot.txt": "bot\n"})
+	require.NoError(t, gitutils.CheckoutBranch(repo, "bot"))
+	commitOnBranch(t, origin, "master", map[string]string{"docs/upstream.txt": "upstream\n"})
+	require.NoError(t, os.WriteFile(filepath.Join(cloneDir, "notes.txt"), []byte("untracked\n"), 0644))
+
+	changed, err := gitutils.RebaseOnto(repo, "upstream", "master", nil)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	for name, content := range map[string]string{
+		"docs/bot.txt":      "bot\n",
+		"docs/upstream.txt": "upstream\n",
+		"notes.txt":         "untracked\n",
+	} {
+		require.Equal(t, content, readFile(t, filepath.Join(cloneDir, name)))
+	}
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	require.Equal(t, "Test", commit.Author.Name)
+	require.Equal(t, "Bot", commit.Committer.Name)
+
+	out, err := sys.RunCommand("git", "-C", cloneDir, "fsck", "--strict")
+	require.NoError(t, err, out)
+	out, err = sys.RunCommand("git", "-C", cloneDir, "status", "--porcelain")
+	require.NoError(t, err)
+	require.Equal(t, "?? notes.txt", strings.TrimSpace(out))
+}
+
+func originWorktree(t *testing.T, repo *git.Repository) string {
+	t.Helper()
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	return w.Filesystem.Root()
+}
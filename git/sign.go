@@ -0,0 +1,432 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	pgpSignaturePrefix = "-----BEGIN PGP SIGNATURE-----"
+	sshSignaturePrefix = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureSuffix = "-----END SSH SIGNATURE-----"
+
+	// sshSigMagic, sshSigNamespace, and sshSigHash are the values git
+	// uses for SSH signatures, as described in OpenSSH's PROTOCOL.sshsig.
+	sshSigMagic     = "SSHSIG"
+	sshSigNamespace = "git"
+	sshSigHash      = "sha512"
+)
+
+// SignatureType is the kind of key a commit or tag is signed with.
+type SignatureType string
+
+const (
+	// SignatureGPG is an OpenPGP signature.
+	SignatureGPG SignatureType = "gpg"
+	// SignatureSSH is an SSH signature, supported since git 2.34.
+	SignatureSSH SignatureType = "ssh"
+)
+
+// ErrUnsigned is returned by VerifyCommitSignature for commits without
+// a signature.
+var ErrUnsigned = errors.New("object is not signed")
+
+// SignOptions configures how Commit and CreateTag sign the objects they
+// create.
+//
+// **Attributes:**
+//
+// Signer: Signs commits and tags. A nil value creates unsigned objects.
+type SignOptions struct {
+	Signer git.Signer
+}
+
+// SignOption is a function that modifies SignOptions.
+type SignOption func(*SignOptions)
+
+// WithSigner signs the commit or tag with signer, which can be created
+// with GPGSigner or SSHSigner.
+//
+// **Parameters:**
+//
+// signer: The signer to sign with.
+//
+// **Returns:**
+//
+// SignOption: An option that sets the signer.
+func WithSigner(signer git.Signer) SignOption {
+	return func(o *SignOptions) {
+		o.Signer = signer
+	}
+}
+
+type gpgSigner struct {
+	entity *openpgp.Entity
+}
+
+// GPGSigner returns a signer that creates OpenPGP signatures with
+// entity, such as a key read with openpgp.ReadArmoredKeyRing.
+//
+// **Parameters:**
+//
+// entity: The OpenPGP entity to sign with. Its private key must be
+// present and already decrypted.
+//
+// **Returns:**
+//
+// git.Signer: The signer.
+// error: An error if entity has no usable private key.
+func GPGSigner(entity *openpgp.Entity) (git.Signer, error) {
+	if entity == nil || entity.PrivateKey == nil {
+		return nil, errors.New("GPG entity has no private key")
+	}
+	if entity.PrivateKey.Encrypted {
+		return nil, errors.New("GPG private key must be decrypted before signing")
+	}
+
+	return gpgSigner{entity: entity}, nil
+}
+
+func (s gpgSigner) Sign(message io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, s.entity, message, nil); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+// SSHSigner returns a signer that creates git SSH signatures, the
+// format used by git with gpg.format=ssh, with signer, such as a key
+// parsed with ssh.ParsePrivateKey.
+//
+// **Parameters:**
+//
+// signer: The SSH private key to sign with.
+//
+// **Returns:**
+//
+// git.Signer: The signer.
+func SSHSigner(signer ssh.Signer) git.Signer {
+	return sshSigner{signer: signer}
+}
+
+// sshSigBlob is the body of an SSH signature that follows the magic
+// preamble.
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is the data that an SSH signature signs, following the
+// magic preamble.
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+func (s sshSigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+	signed := append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Hash:          h.Sum(nil),
+	})...)
+
+	var sig *ssh.Signature
+	var err error
+	// RSA keys must use SHA-512 rather than the legacy SHA-1 algorithm.
+	if algSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, signed, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH signature: %v", err)
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSigBlob{
+		Version:       1,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Signature:     ssh.Marshal(sig),
+	})...)
+
+	return armorSSHSignature(blob), nil
+}
+
+// armorSSHSignature encodes blob in the PEM-like armor used by
+// ssh-keygen -Y sign, with 70 base64 characters per line.
+func armorSSHSignature(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	var buf bytes.Buffer
+	buf.WriteString(sshSignaturePrefix + "\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString(sshSignatureSuffix + "\n")
+
+	return buf.Bytes()
+}
+
+// SignatureInfo describes a verified signature.
+//
+// **Attributes:**
+//
+// Type: The kind of signature.
+// KeyID: The long key ID for GPG signatures, or the SHA256 fingerprint of
+// the public key (e.g., "SHA256:...") for SSH signatures.
+// Signer: The primary identity of the GPG key (e.g., "Bot <bot@example.com>").
+// Empty for SSH signatures.
+type SignatureInfo struct {
+	Type   SignatureType
+	KeyID  string
+	Signer string
+}
+
+// VerifyOptions lists the keys that VerifyCommitSignature trusts.
+//
+// **Attributes:**
+//
+// GPGKeys: The trusted OpenPGP public keys.
+// SSHKeys: The trusted SSH public keys.
+type VerifyOptions struct {
+	GPGKeys openpgp.EntityList
+	SSHKeys []ssh.PublicKey
+}
+
+// VerifyOption is a function that modifies VerifyOptions.
+type VerifyOption func(*VerifyOptions)
+
+// WithTrustedGPGKeys trusts signatures made by any of keys, such as a
+// key ring read with openpgp.ReadArmoredKeyRing.
+//
+// **Parameters:**
+//
+// keys: The trusted OpenPGP public keys.
+//
+// **Returns:**
+//
+// VerifyOption: An option that adds the keys.
+func WithTrustedGPGKeys(keys openpgp.EntityList) VerifyOption {
+	return func(o *VerifyOptions) {
+		o.GPGKeys = append(o.GPGKeys, keys...)
+	}
+}
+
+// WithTrustedSSHKeys trusts signatures made by any of keys, such as keys
+// parsed from an allowed signers file with ssh.ParseAuthorizedKey.
+//
+// **Parameters:**
+//
+// keys: The trusted SSH public keys.
+//
+// **Returns:**
+//
+// VerifyOption: An option that adds the keys.
+func WithTrustedSSHKeys(keys ...ssh.PublicKey) VerifyOption {
+	return func(o *VerifyOptions) {
+		o.SSHKeys = append(o.SSHKeys, keys...)
+	}
+}
+
+// VerifyCommitSignature verifies that the commit with the given hash has
+// a valid GPG or SSH signature made by one of the trusted keys.
+//
+// **Parameters:**
+//
+// repo: The repository containing the commit.
+// hash: The hash of the commit.
+// opts: The trusted keys. At least one key of the commit's signature
+// type must be trusted.
+//
+// **Returns:**
+//
+// *SignatureInfo: The verified signature.
+// error: ErrUnsigned if the commit isn't signed, or an error if the
+// signature is invalid or wasn't made by a trusted key.
+func VerifyCommitSignature(repo *git.Repository, hash plumbing.Hash, opts ...VerifyOption) (*SignatureInfo, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %v", hash, err)
+	}
+	if commit.PGPSignature == "" {
+		return nil, fmt.Errorf("commit %s: %w", hash, ErrUnsigned)
+	}
+
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return nil, fmt.Errorf("failed to encode commit %s: %v", hash, err)
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+	message, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &VerifyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	info, err := verifySignature(message, commit.PGPSignature, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signature of commit %s: %v", hash, err)
+	}
+
+	return info, nil
+}
+
+// verifySignature verifies that signature is a valid signature of
+// message made by one of the trusted keys in opts.
+func verifySignature(message []byte, signature string, opts *VerifyOptions) (*SignatureInfo, error) {
+	switch {
+	case strings.HasPrefix(signature, pgpSignaturePrefix):
+		if len(opts.GPGKeys) == 0 {
+			return nil, errors.New("no trusted GPG keys")
+		}
+		entity, err := openpgp.CheckArmoredDetachedSignature(opts.GPGKeys, bytes.NewReader(message),
+			strings.NewReader(signature), nil)
+		if err != nil {
+			return nil, err
+		}
+		info := &SignatureInfo{Type: SignatureGPG, KeyID: fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)}
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			info.Signer = identity.Name
+		}
+		return info, nil
+
+	case strings.HasPrefix(signature, sshSignaturePrefix):
+		if len(opts.SSHKeys) == 0 {
+			return nil, errors.New("no trusted SSH keys")
+		}
+		pub, err := verifySSHSignature(message, signature)
+		if err != nil {
+			return nil, err
+		}
+		for _, trusted := range opts.SSHKeys {
+			if bytes.Equal(trusted.Marshal(), pub.Marshal()) {
+				return &SignatureInfo{Type: SignatureSSH, KeyID: ssh.FingerprintSHA256(pub)}, nil
+			}
+		}
+		return nil, fmt.Errorf("signed by untrusted SSH key %s", ssh.FingerprintSHA256(pub))
+
+	default:
+		return nil, errors.New("unsupported signature format")
+	}
+}
+
+// verifySSHSignature checks an armored git SSH signature of message and
+// returns the public key that made it.
+func verifySSHSignature(message []byte, armored string) (ssh.PublicKey, error) {
+	body := strings.TrimSpace(armored)
+	body = strings.TrimPrefix(body, sshSignaturePrefix)
+	body = strings.TrimSuffix(body, sshSignatureSuffix)
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH signature encoding: %v", err)
+	}
+	if !bytes.HasPrefix(blob, []byte(sshSigMagic)) {
+		return nil, errors.New("invalid SSH signature preamble")
+	}
+
+	var sig sshSigBlob
+	if err := ssh.Unmarshal(blob[len(sshSigMagic):], &sig); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %v", err)
+	}
+	if sig.Version != 1 || sig.Namespace != sshSigNamespace {
+		return nil, fmt.Errorf("unsupported SSH signature version %d or namespace %q", sig.Version, sig.Namespace)
+	}
+	if sig.HashAlgorithm != sshSigHash {
+		return nil, fmt.Errorf("unsupported SSH signature hash algorithm %q", sig.HashAlgorithm)
+	}
+
+	pub, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH signature public key: %v", err)
+	}
+	var inner ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &inner); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %v", err)
+	}
+
+	hash := sha512.Sum512(message)
+	signed := append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Hash:          hash[:],
+	})...)
+	if err := pub.Verify(signed, &inner); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %v", err)
+	}
+
+	return pub, nil
+}
+
+// createSignedTag creates an annotated tag object for target signed with
+// signer and returns its hash. go-git can only sign tags with GPG keys.
+func createSignedTag(repo *git.Repository, name string, target plumbing.Hash, tagger object.Signature, message string, signer git.Signer) (plumbing.Hash, error) {
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     tagger,
+		Message:    message,
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(unsigned); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	sig, err := signer.Sign(reader)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to sign tag %s: %v", name, err)
+	}
+	tag.PGPSignature = string(sig)
+
+	obj := repo.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return repo.Storer.SetEncodedObject(obj)
+}
@@ -0,0 +1,208 @@
+package git_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	gitutils "github.com/l50/goutils/v2/git"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newSigningRepo creates a repository with an identity and a staged
+// file, ready for Commit.
+func newSigningRepo(t *testing.T) *git.Repository {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, gitutils.SetRepoIdentity(repo, "Release Bot", "bot@example.com"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("data"), 0644))
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add("file.txt")
+	require.NoError(t, err)
+
+	return repo
+}
+
+func newSSHSigner(t *testing.T, keyType string) ssh.Signer {
+	t.Helper()
+	var key interface{}
+	var err error
+	switch keyType {
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSignedCommits(t *testing.T) {
+	entity, err := openpgp.NewEntity("Release Bot", "", "bot@example.com", nil)
+	require.NoError(t, err)
+	gpgSigner, err := gitutils.GPGSigner(entity)
+	require.NoError(t, err)
+	otherEntity, err := openpgp.NewEntity("Someone Else", "", "else@example.com", nil)
+	require.NoError(t, err)
+
+	ed25519Key := newSSHSigner(t, "ed25519")
+	rsaKey := newSSHSigner(t, "rsa")
+	otherSSHKey := newSSHSigner(t, "ed25519")
+
+	testCases := []struct {
+		name       string
+		signer     git.Signer
+		trust      []gitutils.VerifyOption
+		wantType   gitutils.SignatureType
+		wantKeyID  string
+		wantSigner string
+		wantErr    bool
+	}{
+		{
+			name:       "GPG",
+			signer:     gpgSigner,
+			trust:      []gitutils.VerifyOption{gitutils.WithTrustedGPGKeys(openpgp.EntityList{entity})},
+			wantType:   gitutils.SignatureGPG,
+			wantSigner: "Release Bot <bot@example.com>",
+		},
+		{
+			name:      "SSH ed25519",
+			signer:    gitutils.SSHSigner(ed25519Key),
+			trust:     []gitutils.VerifyOption{gitutils.WithTrustedSSHKeys(otherSSHKey.PublicKey(), ed25519Key.PublicKey())},
+			wantType:  gitutils.SignatureSSH,
+			wantKeyID: ssh.FingerprintSHA256(ed25519Key.PublicKey()),
+		},
+		{
+			name:      "SSH RSA",
+			signer:    gitutils.SSHSigner(rsaKey),
+			trust:     []gitutils.VerifyOption{gitutils.WithTrustedSSHKeys(rsaKey.PublicKey())},
+			wantType:  gitutils.SignatureSSH,
+			wantKeyID: ssh.FingerprintSHA256(rsaKey.PublicKey()),
+		},
+		{
+			name:    "Untrusted GPG key",
+			signer:  gpgSigner,
+			trust:   []gitutils.VerifyOption{gitutils.WithTrustedGPGKeys(openpgp.EntityList{otherEntity})},
+			wantErr: true,
+		},
+		{
+			name:    "Untrusted SSH key",
+			signer:  gitutils.SSHSigner(ed25519Key),
+			trust:   []gitutils.VerifyOption{gitutils.WithTrustedSSHKeys(otherSSHKey.PublicKey())},
+			wantErr: true,
+		},
+		{
+			name:    "No trusted keys of the signature type",
+			signer:  gitutils.SSHSigner(ed25519Key),
+			trust:   []gitutils.VerifyOption{gitutils.WithTrustedGPGKeys(openpgp.EntityList{entity})},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newSigningRepo(t)
+			require.NoError(t, gitutils.Commit(repo, "signed commit", gitutils.WithSigner(tc.signer)))
+			head, err := repo.Head()
+			require.NoError(t, err)
+
+			info, err := gitutils.VerifyCommitSignature(repo, head.Hash(), tc.trust...)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantType, info.Type)
+			require.Equal(t, tc.wantSigner, info.Signer)
+			if tc.wantKeyID != "" {
+				require.Equal(t, tc.wantKeyID, info.KeyID)
+			}
+			require.NotEmpty(t, info.KeyID)
+		})
+	}
+}
+
+func TestVerifyCommitSignatureUnsigned(t *testing.T) {
+	repo := newSigningRepo(t)
+	require.NoError(t, gitutils.Commit(repo, "unsigned commit"))
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	_, err = gitutils.VerifyCommitSignature(repo, head.Hash())
+	require.True(t, errors.Is(err, gitutils.ErrUnsigned), "VerifyCommitSignature() error = %v", err)
+}
+
+func TestSignedTags(t *testing.T) {
+	entity, err := openpgp.NewEntity("Release Bot", "", "bot@example.com", nil)
+	require.NoError(t, err)
+	gpgSigner, err := gitutils.GPGSigner(entity)
+	require.NoError(t, err)
+	sshKey := newSSHSigner(t, "ed25519")
+
+	testCases := []struct {
+		name       string
+		signer     git.Signer
+		wantPrefix string
+	}{
+		{name: "GPG", signer: gpgSigner, wantPrefix: "-----BEGIN PGP SIGNATURE-----"},
+		{name: "SSH", signer: gitutils.SSHSigner(sshKey), wantPrefix: "-----BEGIN SSH SIGNATURE-----"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newSigningRepo(t)
+			require.NoError(t, gitutils.Commit(repo, "release"))
+			require.NoError(t, gitutils.CreateTag(repo, "v1.0.0", gitutils.WithSigner(tc.signer)))
+
+			ref, err := repo.Tag("v1.0.0")
+			require.NoError(t, err)
+			tag, err := repo.TagObject(ref.Hash())
+			require.NoError(t, err)
+			require.Equal(t, "v1.0.0\n", tag.Message)
+			require.Equal(t, "Release Bot", tag.Tagger.Name)
+			require.True(t, strings.HasPrefix(tag.PGPSignature, tc.wantPrefix), "signature: %q", tag.PGPSignature)
+
+			head, err := repo.Head()
+			require.NoError(t, err)
+			require.Equal(t, head.Hash(), tag.Target)
+
+			if tc.name == "GPG" {
+				var armored strings.Builder
+				w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+				require.NoError(t, err)
+				require.NoError(t, entity.Serialize(w))
+				require.NoError(t, w.Close())
+				_, err = tag.Verify(armored.String())
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGPGSignerRequiresPrivateKey(t *testing.T) {
+	_, err := gitutils.GPGSigner(nil)
+	require.Error(t, err)
+
+	entity, err := openpgp.NewEntity("Release Bot", "", "bot@example.com", nil)
+	require.NoError(t, err)
+	entity.PrivateKey = nil
+	_, err = gitutils.GPGSigner(entity)
+	require.Error(t, err)
+}
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// saveStateMessage is the description of the stash entries created by
+// SaveState.
+const saveStateMessage = "goutils: saved repository state"
+
+// RepoState is a snapshot of a repository taken by SaveState.
+//
+// **Attributes:**
+//
+// Head: The hash of the commit HEAD pointed to.
+// Branch: The short name of the checked out branch. Empty if HEAD was
+// detached.
+// Stash: The hash of the stash entry holding the local changes, including
+// untracked files. Empty if the worktree was clean.
+type RepoState struct {
+	Head   string
+	Branch string
+	Stash  string
+}
+
+// SaveState records the checked out branch, the HEAD commit, and the
+// local changes of a repository, so that automation which rewrites files
+// or creates commits (e.g., docs generation or license headers) can roll
+// back with RestoreState if it fails. The local changes are saved as a
+// stash entry and left in place in the worktree. Call DiscardState once
+// the saved state is no longer needed.
+//
+// **Parameters:**
+//
+// repo: Repository whose state should be saved.
+//
+// **Returns:**
+//
+// *RepoState: The saved state.
+// error: Error if HEAD can't be read or the local changes can't be saved.
+func SaveState(repo *git.Repository) (*RepoState, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo head: %v", err)
+	}
+
+	state := &RepoState{Head: head.Hash().String()}
+	if head.Name().IsBranch() {
+		state.Branch = head.Name().Short()
+	}
+
+	stashed, err := Stash(repo, saveStateMessage, true)
+	if err != nil {
+		return nil, fmt.Errorf("error saving local changes: %v", err)
+	}
+	if !stashed {
+		return state, nil
+	}
+
+	hash, err := stashHash(repo)
+	if err != nil {
+		return nil, err
+	}
+	state.Stash = hash.String()
+
+	// Stashing reverted the worktree; put the changes back while keeping
+	// the entry for RestoreState.
+	if err := runGitInWorktree(repo, "stash", "apply", "--index", state.Stash); err != nil {
+		return nil, fmt.Errorf("error reapplying local changes: %v", err)
+	}
+
+	return state, nil
+}
+
+// RestoreState rolls a repository back to a state saved by SaveState. The
+// saved branch is checked out and reset to the saved commit, changes and
+// untracked files created since are discarded, and the saved local
+// changes are reapplied and removed from the stash. Ignored files are
+// kept.
+//
+// **Parameters:**
+//
+// repo: Repository to restore.
+// state: The state returned by SaveState.
+//
+// **Returns:**
+//
+// error: Error if the saved commit can't be checked out or the saved
+// local changes can't be reapplied.
+func RestoreState(repo *git.Repository, state *RepoState) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+	if state == nil || state.Head == "" {
+		return errors.New("repository state is empty")
+	}
+
+	if err := Clean(repo, true); err != nil {
+		return err
+	}
+
+	checkoutArgs := []string{"checkout", "--quiet", "--force", "--detach", state.Head}
+	if state.Branch != "" {
+		checkoutArgs = []string{"checkout", "--quiet", "--force", state.Branch}
+	}
+	if err := runGitInWorktree(repo, checkoutArgs...); err != nil {
+		return fmt.Errorf("error checking out saved state: %v", err)
+	}
+	if err := ResetHard(repo, state.Head); err != nil {
+		return err
+	}
+
+	if state.Stash == "" {
+		return nil
+	}
+	if err := runGitInWorktree(repo, "stash", "apply", "--index", state.Stash); err != nil {
+		return fmt.Errorf("error reapplying saved local changes: %v", err)
+	}
+
+	return DiscardState(repo, state)
+}
+
+// DiscardState removes the stash entry that SaveState created for a
+// state, for example after the guarded operation succeeded. It does
+// nothing if the worktree was clean or the entry was already removed.
+//
+// **Parameters:**
+//
+// repo: Repository the state was saved from.
+// state: The state returned by SaveState.
+//
+// **Returns:**
+//
+// error: Error if the stash can't be read or the entry can't be removed.
+func DiscardState(repo *git.Repository, state *RepoState) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+	if state == nil || state.Stash == "" {
+		return nil
+	}
+
+	out, err := gitOutputInWorktree(repo, "stash", "list", "--format=%H")
+	if err != nil {
+		return err
+	}
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if plumbing.NewHash(strings.TrimSpace(line)).String() == state.Stash {
+			return runGitInWorktree(repo, "stash", "drop", "--quiet", fmt.Sprintf("stash@{%d}", i))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndRestoreState(t *testing.T) {
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	testCases := []struct {
+		name      string
+		dirty     bool
+		switchOff bool
+	}{
+		{name: "Dirty worktree", dirty: true},
+		{name: "Clean worktree"},
+		{name: "Operation switched branches", dirty: true, switchOff: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			w, err := repo.Worktree()
+			require.NoError(t, err)
+			dir := w.Filesystem.Root()
+			if tc.dirty {
+				dirtyWorktree(t, repo)
+			}
+
+			head, err := repo.Head()
+			require.NoError(t, err)
+
+			state, err := gitutils.SaveState(repo)
+			require.NoError(t, err)
+			require.Equal(t, head.Hash().String(), state.Head)
+			require.Equal(t, "master", state.Branch)
+			require.Equal(t, tc.dirty, state.Stash != "")
+			if tc.dirty {
+				require.Equal(t, "local change\n", readFile(t, filepath.Join(dir, "a.txt")), "changes stay in place")
+				require.FileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			}
+
+			// Simulate a failed operation that rewrote files and committed.
+			if tc.switchOff {
+				require.NoError(t, gitutils.CreateBranch(repo, "automation", ""))
+				require.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("automation"), Force: true}))
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("rewritten\n"), 0644))
+			_, err = w.Add("b.txt")
+			require.NoError(t, err)
+			_, err = w.Commit("automation", &git.CommitOptions{Author: &object.Signature{Name: "Test", Email: "test@example.com"}})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "generated.txt"), []byte("generated\n"), 0644))
+
+			require.NoError(t, gitutils.RestoreState(repo, state))
+
+			restored, err := repo.Head()
+			require.NoError(t, err)
+			require.Equal(t, head.Hash(), restored.Hash())
+			require.Equal(t, plumbing.NewBranchReferenceName("master"), restored.Name())
+			require.Equal(t, numberedLines("bravo", 20), readFile(t, filepath.Join(dir, "b.txt")))
+			require.NoFileExists(t, filepath.Join(dir, "generated.txt"))
+			if tc.dirty {
+				require.Equal(t, "local change\n", readFile(t, filepath.Join(dir, "a.txt")))
+				require.FileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			}
+			// go-git's forced checkout above deletes ignored files itself.
+			if tc.dirty && !tc.switchOff {
+				require.FileExists(t, filepath.Join(dir, "build.log"), "ignored files are kept")
+			}
+			require.Error(t, gitutils.StashPop(repo), "the saved stash entry is removed")
+		})
+	}
+}
+
+func TestDiscardState(t *testing.T) {
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+	dir := dirtyWorktree(t, repo)
+
+	state, err := gitutils.SaveState(repo)
+	require.NoError(t, err)
+	require.NoError(t, gitutils.DiscardState(repo, state))
+	require.NoError(t, gitutils.DiscardState(repo, state), "discarding twice is a no-op")
+	require.Error(t, gitutils.StashPop(repo), "the saved stash entry is removed")
+	require.Equal(t, "local change\n", readFile(t, filepath.Join(dir, "a.txt")))
+
+	require.Error(t, gitutils.RestoreState(repo, nil))
+	_, err = gitutils.SaveState(nil)
+	require.Error(t, err)
+}
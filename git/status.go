@@ -0,0 +1,168 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// FileState describes the state of a file in the index or the worktree
+// relative to the commit or index before it.
+type FileState string
+
+const (
+	// StateUnmodified indicates a file without changes.
+	StateUnmodified FileState = "unmodified"
+	// StateModified indicates a file whose content or mode changed.
+	StateModified FileState = "modified"
+	// StateAdded indicates a file that was added to the index.
+	StateAdded FileState = "added"
+	// StateDeleted indicates a tracked file that was deleted.
+	StateDeleted FileState = "deleted"
+	// StateRenamed indicates a file that was renamed in the index.
+	StateRenamed FileState = "renamed"
+	// StateCopied indicates a file that was copied in the index.
+	StateCopied FileState = "copied"
+	// StateUntracked indicates a file that isn't tracked or ignored.
+	StateUntracked FileState = "untracked"
+	// StateConflicted indicates a file with unresolved merge conflicts.
+	StateConflicted FileState = "conflicted"
+)
+
+// FileStatus describes the uncommitted changes to a single file.
+//
+// **Attributes:**
+//
+// Path: The path of the file, relative to the worktree root and using
+// forward slashes.
+// Staged: The state of the file in the index compared to HEAD.
+// Worktree: The state of the file in the worktree compared to the index.
+// OrigPath: The previous path of a renamed or copied file.
+type FileStatus struct {
+	Path     string
+	Staged   FileState
+	Worktree FileState
+	OrigPath string
+}
+
+// Status returns the files in the repository's worktree that have
+// uncommitted changes, including untracked files. Ignored files aren't
+// included.
+//
+// **Parameters:**
+//
+// repo: Repository whose worktree should be inspected.
+//
+// **Returns:**
+//
+// []FileStatus: The changed files, sorted by path.
+// error: Error if the worktree status can't be determined.
+func Status(repo *git.Repository) ([]FileStatus, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil")
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree status: %v", err)
+	}
+
+	var files []FileStatus
+	for path, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Unmodified {
+			continue
+		}
+		files = append(files, FileStatus{
+			Path:     path,
+			Staged:   fileState(s.Staging),
+			Worktree: fileState(s.Worktree),
+			OrigPath: s.Extra,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files, nil
+}
+
+// IsDirty reports whether the repository's worktree has uncommitted
+// changes, such as before tagging a release. Untracked files count as
+// changes; ignored files don't.
+//
+// **Parameters:**
+//
+// repo: Repository whose worktree should be inspected.
+//
+// **Returns:**
+//
+// bool: True if there are staged, unstaged, or untracked changes.
+// error: Error if the worktree status can't be determined.
+func IsDirty(repo *git.Repository) (bool, error) {
+	files, err := Status(repo)
+	if err != nil {
+		return false, err
+	}
+
+	return len(files) > 0, nil
+}
+
+// DiscardChanges reverts the input paths to their state in HEAD, in both
+// the index and the worktree. Files that were added since HEAD are
+// removed. Untracked files are left in place; use Clean to remove them.
+//
+// **Parameters:**
+//
+// repo: Repository whose changes should be discarded.
+// paths: The paths to revert, relative to the worktree root. Directories
+// revert every file below them. If no paths are given, all changes to
+// tracked files are discarded.
+//
+// **Returns:**
+//
+// error: Error if a path doesn't match any tracked file or the changes
+// can't be discarded.
+func DiscardChanges(repo *git.Repository, paths ...string) error {
+	if len(paths) == 0 {
+		return ResetHard(repo, "")
+	}
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+
+	// go-git can't restore individual paths from HEAD, so the git CLI is
+	// used.
+	args := append([]string{"restore", "--source=HEAD", "--staged", "--worktree", "--"}, paths...)
+	if err := runGitInWorktree(repo, args...); err != nil {
+		return fmt.Errorf("error discarding changes: %v", err)
+	}
+
+	return nil
+}
+
+// fileState converts a go-git status code to a FileState.
+func fileState(code git.StatusCode) FileState {
+	switch code {
+	case git.Modified:
+		return StateModified
+	case git.Added:
+		return StateAdded
+	case git.Deleted:
+		return StateDeleted
+	case git.Renamed:
+		return StateRenamed
+	case git.Copied:
+		return StateCopied
+	case git.Untracked:
+		return StateUntracked
+	case git.UpdatedButUnmerged:
+		return StateConflicted
+	default:
+		return StateUnmodified
+	}
+}
@@ -0,0 +1,118 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+
+	dirty, err := gitutils.IsDirty(repo)
+	require.NoError(t, err)
+	require.False(t, dirty, "fresh repository should be clean")
+	files, err := gitutils.Status(repo)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	dir := dirtyWorktree(t, repo)
+	require.NoError(t, os.Remove(filepath.Join(dir, "b.txt")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("staged\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("staged change\n"), 0644))
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("staged.txt")
+	require.NoError(t, err)
+	_, err = w.Add("c.txt")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("unstaged change\n"), 0644))
+
+	dirty, err = gitutils.IsDirty(repo)
+	require.NoError(t, err)
+	require.True(t, dirty)
+
+	files, err = gitutils.Status(repo)
+	require.NoError(t, err)
+	require.Equal(t, []gitutils.FileStatus{
+		{Path: "a.txt", Staged: gitutils.StateUnmodified, Worktree: gitutils.StateModified},
+		{Path: "b.txt", Staged: gitutils.StateUnmodified, Worktree: gitutils.StateDeleted},
+		{Path: "c.txt", Staged: gitutils.StateModified, Worktree: gitutils.StateModified},
+		{Path: "staged.txt", Staged: gitutils.StateAdded, Worktree: gitutils.StateUnmodified},
+		{Path: "tmp/untracked.txt", Staged: gitutils.StateUntracked, Worktree: gitutils.StateUntracked},
+	}, files)
+
+	_, err = gitutils.Status(nil)
+	require.Error(t, err)
+}
+
+func TestDiscardChanges(t *testing.T) {
+	testCases := []struct {
+		name      string
+		paths     []string
+		wantPaths []string
+		wantErr   bool
+	}{
+		{
+			name:      "Single modified file",
+			paths:     []string{"a.txt"},
+			wantPaths: []string{"c.txt", "staged.txt", "tmp/untracked.txt"},
+		},
+		{
+			name:      "Staged files",
+			paths:     []string{"c.txt", "staged.txt"},
+			wantPaths: []string{"a.txt", "tmp/untracked.txt"},
+		},
+		{
+			name:      "All changes",
+			wantPaths: []string{"tmp/untracked.txt"},
+		},
+		{
+			name:    "Untracked file",
+			paths:   []string{"tmp/untracked.txt"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			dir := dirtyWorktree(t, repo)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("staged\n"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("staged change\n"), 0644))
+			w, err := repo.Worktree()
+			require.NoError(t, err)
+			_, err = w.Add("staged.txt")
+			require.NoError(t, err)
+			_, err = w.Add("c.txt")
+			require.NoError(t, err)
+
+			err = gitutils.DiscardChanges(repo, tc.paths...)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.wantPaths, statusPaths(t, repo))
+			require.FileExists(t, filepath.Join(dir, "build.log"), "ignored files should be kept")
+		})
+	}
+}
+
+func statusPaths(t *testing.T, repo *git.Repository) []string {
+	t.Helper()
+	files, err := gitutils.Status(repo)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
@@ -0,0 +1,193 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/l50/goutils/v2/sys"
+)
+
+// stashRefName is the reference holding the most recent stash entry.
+const stashRefName = plumbing.ReferenceName("refs/stash")
+
+// Stash saves the local changes of the repository's worktree as a new
+// stash entry and reverts the worktree to HEAD. go-git doesn't support
+// stashing, so the git CLI is used.
+//
+// **Parameters:**
+//
+// repo: Repository whose changes should be stashed.
+// message: The description of the stash entry. An empty string uses
+// git's default description.
+// includeUntracked: Whether untracked files are also stashed and removed.
+//
+// **Returns:**
+//
+// bool: Whether a stash entry was created. False if there were no local
+// changes to stash.
+// error: Error if the changes can't be stashed.
+func Stash(repo *git.Repository, message string, includeUntracked bool) (bool, error) {
+	before, err := stashHash(repo)
+	if err != nil {
+		return false, err
+	}
+
+	args := []string{"stash", "push"}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "--message", message)
+	}
+	if err := runGitInWorktree(repo, args...); err != nil {
+		return false, err
+	}
+
+	after, err := stashHash(repo)
+	if err != nil {
+		return false, err
+	}
+
+	return after != before, nil
+}
+
+// StashPop applies the most recent stash entry to the repository's
+// worktree and removes it from the stash. The entry is kept if applying
+// it conflicts with the worktree.
+//
+// **Parameters:**
+//
+// repo: Repository whose most recent stash entry should be applied.
+//
+// **Returns:**
+//
+// error: Error if there is no stash entry or it can't be applied.
+func StashPop(repo *git.Repository) error {
+	hash, err := stashHash(repo)
+	if err != nil {
+		return err
+	}
+	if hash.IsZero() {
+		return errors.New("error popping stash: no stash entries found")
+	}
+
+	return runGitInWorktree(repo, "stash", "pop")
+}
+
+// ResetHard resets the current branch, the index, and the worktree to
+// the input revision, discarding all changes to tracked files.
+// Untracked files are left in place; use Clean to remove them.
+//
+// **Parameters:**
+//
+// repo: Repository to reset.
+// ref: The revision to reset to (e.g., "origin/main" or a commit hash).
+// An empty string means HEAD.
+//
+// **Returns:**
+//
+// error: Error if the revision can't be resolved or the reset fails.
+func ResetHard(repo *git.Repository, ref string) error {
+	if repo == nil {
+		return errors.New("repository is nil")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	commit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return err
+	}
+
+	// go-git's hard reset also deletes untracked and ignored files, so
+	// the git CLI is used instead.
+	if err := runGitInWorktree(repo, "reset", "--hard", "--quiet", commit.Hash.String()); err != nil {
+		return fmt.Errorf("error resetting to %s: %v", ref, err)
+	}
+
+	return nil
+}
+
+// Clean discards the local changes to tracked files in the repository's
+// worktree and, optionally, removes untracked files and directories.
+// Ignored files are always kept.
+//
+// **Parameters:**
+//
+// repo: Repository to clean.
+// includeUntracked: Whether untracked files and directories are removed.
+//
+// **Returns:**
+//
+// error: Error if the worktree can't be cleaned.
+func Clean(repo *git.Repository, includeUntracked bool) error {
+	if err := ResetHard(repo, ""); err != nil {
+		return err
+	}
+	if !includeUntracked {
+		return nil
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	if err := w.Clean(&git.CleanOptions{Dir: true}); err != nil {
+		return fmt.Errorf("error removing untracked files: %v", err)
+	}
+
+	return nil
+}
+
+// stashHash returns the hash of the most recent stash entry, or the zero
+// hash if there is none.
+func stashHash(repo *git.Repository) (plumbing.Hash, error) {
+	if repo == nil {
+		return plumbing.ZeroHash, errors.New("repository is nil")
+	}
+
+	ref, err := repo.Reference(stashRefName, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read stash: %v", err)
+	}
+
+	return ref.Hash(), nil
+}
+
+// runGitInWorktree runs the git CLI with the input arguments from the
+// root of the repository's worktree.
+func runGitInWorktree(repo *git.Repository, args ...string) error {
+	_, err := gitOutputInWorktree(repo, args...)
+	return err
+}
+
+// gitOutputInWorktree runs the git CLI like runGitInWorktree and returns
+// its output.
+func gitOutputInWorktree(repo *git.Repository, args ...string) (string, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %v", err)
+	}
+
+	cmd := sys.Cmd{
+		CmdString:     "git",
+		Args:          args,
+		Dir:           w.Filesystem.Root(),
+		OutputHandler: func(string) {},
+	}
+	out, err := cmd.RunCmd()
+	if err != nil {
+		return "", fmt.Errorf("failed to run `git %s`: %v: %s",
+			strings.Join(args, " "), err, strings.TrimSpace(out))
+	}
+
+	return out, nil
+}
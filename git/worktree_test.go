@@ -0,0 +1,143 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitutils "github.com/l50/goutils/v2/git"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+// dirtyWorktree modifies a tracked file and creates an untracked file and
+// an ignored file in the worktree of repo.
+func dirtyWorktree(t *testing.T, repo *git.Repository) string {
+	t.Helper()
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	dir := w.Filesystem.Root()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("local change\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tmp"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tmp", "untracked.txt"), []byte("untracked\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git", "info"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "info", "exclude"), []byte("*.log\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build.log"), []byte("ignored\n"), 0644))
+
+	return dir
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestResetHardAndClean(t *testing.T) {
+	testCases := []struct {
+		name          string
+		clean         func(repo *git.Repository) error
+		wantUntracked bool
+		wantHead      string
+	}{
+		{
+			name:          "Reset to HEAD",
+			clean:         func(repo *git.Repository) error { return gitutils.ResetHard(repo, "") },
+			wantUntracked: true,
+			wantHead:      "HEAD",
+		},
+		{
+			name:          "Reset to previous commit",
+			clean:         func(repo *git.Repository) error { return gitutils.ResetHard(repo, "HEAD~1") },
+			wantUntracked: true,
+			wantHead:      "HEAD~1",
+		},
+		{
+			name:          "Clean tracked files only",
+			clean:         func(repo *git.Repository) error { return gitutils.Clean(repo, false) },
+			wantUntracked: true,
+			wantHead:      "HEAD",
+		},
+		{
+			name:     "Clean including untracked files",
+			clean:    func(repo *git.Repository) error { return gitutils.Clean(repo, true) },
+			wantHead: "HEAD",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			wantHead, err := repo.ResolveRevision(plumbing.Revision(tc.wantHead))
+			require.NoError(t, err)
+			dir := dirtyWorktree(t, repo)
+
+			require.NoError(t, tc.clean(repo))
+
+			head, err := repo.Head()
+			require.NoError(t, err)
+			require.Equal(t, *wantHead, head.Hash())
+			require.Equal(t, numberedLines("alpha", 20), readFile(t, filepath.Join(dir, "a.txt")))
+			require.FileExists(t, filepath.Join(dir, "build.log"), "ignored files are kept")
+			if tc.wantUntracked {
+				require.FileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			} else {
+				require.NoFileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+				require.NoDirExists(t, filepath.Join(dir, "tmp"))
+			}
+		})
+	}
+
+	repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+	require.Error(t, gitutils.ResetHard(repo, "does-not-exist"))
+	require.Error(t, gitutils.ResetHard(nil, ""))
+}
+
+func TestStash(t *testing.T) {
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	testCases := []struct {
+		name             string
+		includeUntracked bool
+	}{
+		{name: "Tracked changes only"},
+		{name: "Including untracked files", includeUntracked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := createRepoWithChanges(t, map[string]string{"d.txt": "new file\n"})
+			require.Error(t, gitutils.StashPop(repo), "no stash entries")
+
+			stashed, err := gitutils.Stash(repo, "", tc.includeUntracked)
+			require.NoError(t, err)
+			require.False(t, stashed, "clean worktree has nothing to stash")
+
+			dir := dirtyWorktree(t, repo)
+			stashed, err = gitutils.Stash(repo, "before pull", tc.includeUntracked)
+			require.NoError(t, err)
+			require.True(t, stashed)
+			require.Equal(t, numberedLines("alpha", 20), readFile(t, filepath.Join(dir, "a.txt")))
+			if tc.includeUntracked {
+				require.NoFileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			} else {
+				require.FileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			}
+
+			require.NoError(t, gitutils.StashPop(repo))
+			require.Equal(t, "local change\n", readFile(t, filepath.Join(dir, "a.txt")))
+			require.FileExists(t, filepath.Join(dir, "tmp", "untracked.txt"))
+			require.Error(t, gitutils.StashPop(repo), "stash is empty after pop")
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	core "github.com/l50/goutils/v2/k8s/core"
+	loggers "github.com/l50/goutils/v2/k8s/loggers"
+	manifests "github.com/l50/goutils/v2/k8s/manifests"
+	waitK8s "github.com/l50/goutils/v2/k8s/wait"
+	"github.com/l50/goutils/v2/logging"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TeardownPolicy defines when DeployBundle deletes what it created.
+//
+// **Values:**
+//
+// TeardownAlways: Tear down after the bundle completes or fails. This is
+// the default.
+// TeardownOnSuccess: Tear down only after the bundle completes, keeping
+// the resources of a failed bundle for debugging.
+// TeardownNever: Keep the resources, e.g., for a long-running workload.
+type TeardownPolicy int
+
+const (
+	TeardownAlways TeardownPolicy = iota
+	TeardownOnSuccess
+	TeardownNever
+)
+
+// RegistryCredential describes the image pull secret of a bundle.
+//
+// **Attributes:**
+//
+// Name: The name of the Secret. Pods reference it in imagePullSecrets.
+// Server: The registry server, e.g., "ghcr.io".
+// Username: The user to authenticate as.
+// Password: The password or access token of the user.
+type RegistryCredential struct {
+	Name     string
+	Server   string
+	Username string
+	Password string
+}
+
+// WaitTarget describes a resource DeployBundle waits for after applying
+// the manifest.
+//
+// **Attributes:**
+//
+// GVR: The GroupVersionResource of the resource.
+// Name: The name of the resource.
+// Condition: The condition to wait for, e.g., JobComplete or
+// DeploymentAvailable of the k8s/wait package.
+type WaitTarget struct {
+	GVR       schema.GroupVersionResource
+	Name      string
+	Condition waitK8s.Condition
+}
+
+// BundleSpec describes a workload deployed with everything it needs by
+// DeployBundle.
+//
+// **Attributes:**
+//
+// Name: The name of the bundle, a DNS label. It's used as the run ID of
+// the created resources (see manifests.ResourceMetadata) and to name the
+// inventory ConfigMap of the manifest.
+// Namespace: The namespace to deploy to. It's created if it doesn't
+// exist, and deleted on teardown only in that case.
+// NamespaceLabels: Optional labels added to the namespace.
+// ImagePullSecret: Optional registry credential stored as a
+// kubernetes.io/dockerconfigjson Secret.
+// ConfigMaps: ConfigMaps to create, e.g., from scripts. Their namespace
+// defaults to Namespace.
+// Secrets: Secrets to create. Their namespace defaults to Namespace.
+// ManifestPath: Optional raw manifest file, or kustomization directory,
+// of the workload, e.g., a Job.
+// Values: Optional values the manifest is rendered with, see
+// manifests.ManifestConfig.
+// WaitFor: The resources to wait for after applying the manifest, in
+// order.
+// WaitTimeout: The timeout of each wait. Defaults to the timeout of
+// waitK8s.WaitFor.
+// LogSelector: Optional label selector of the pods whose logs are
+// written to LogOutput after waiting, whether or not the wait succeeded,
+// e.g., "job-name=scan".
+// LogOutput: The writer the logs are written to. Defaults to os.Stdout.
+// Teardown: When the created resources are deleted.
+type BundleSpec struct {
+	Name            string
+	Namespace       string
+	NamespaceLabels map[string]string
+	ImagePullSecret *RegistryCredential
+	ConfigMaps      []core.DataSpec
+	Secrets         []core.DataSpec
+	ManifestPath    string
+	Values          map[string]interface{}
+	WaitFor         []WaitTarget
+	WaitTimeout     time.Duration
+	LogSelector     string
+	LogOutput       io.Writer
+	Teardown        TeardownPolicy
+}
+
+// bundleRun tracks what a DeployBundle call created, for its teardown.
+type bundleRun struct {
+	kc               *client.KubernetesClient
+	spec             BundleSpec
+	metadata         *manifests.ResourceMetadata
+	createdNamespace bool
+	configMaps       []string
+	secrets          []string
+	manifest         *manifests.ManifestConfig
+}
+
+// DeployBundle runs the common end-to-end flow of deploying a workload:
+// it creates the namespace, the image pull secret, ConfigMaps, and
+// Secrets, applies the manifest, waits for the WaitFor resources, writes
+// the logs of the LogSelector pods, and then tears down what it created
+// according to spec.Teardown. Teardown also runs when a step fails or
+// ctx is canceled.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to deploy the bundle. Both its Clientset
+// and DynamicClient are used.
+// spec: The bundle to deploy.
+//
+// **Returns:**
+//
+// error: An error if the spec is invalid or a step fails, joined with
+// any teardown error.
+func DeployBundle(ctx context.Context, kc *client.KubernetesClient, spec BundleSpec) error {
+	if kc == nil || kc.Clientset == nil || kc.DynamicClient == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+	if errs := validation.IsDNS1123Label(spec.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid bundle name %q: %s", spec.Name, strings.Join(errs, "; "))
+	}
+	if spec.Namespace == "" {
+		return errors.New("bundle namespace is required")
+	}
+
+	run := &bundleRun{
+		kc:       kc,
+		spec:     spec,
+		metadata: &manifests.ResourceMetadata{RunID: spec.Name},
+	}
+	deployErr := run.deploy(ctx)
+
+	if spec.Teardown == TeardownNever || (spec.Teardown == TeardownOnSuccess && deployErr != nil) {
+		return deployErr
+	}
+	// Tear down even if ctx was canceled, which is what ends the
+	// deployment of a bundle interrupted by the user.
+	return errors.Join(deployErr, run.teardown(context.WithoutCancel(ctx)))
+}
+
+// deploy creates the resources of the bundle and waits for them.
+func (r *bundleRun) deploy(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	spec := r.spec
+
+	exists, err := core.NamespaceExists(ctx, r.kc, spec.Namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := core.CreateNamespace(ctx, r.kc, spec.Namespace, spec.NamespaceLabels); err != nil {
+		return err
+	}
+	r.createdNamespace = !exists
+
+	if spec.ImagePullSecret != nil {
+		if err := r.createImagePullSecret(ctx, *spec.ImagePullSecret); err != nil {
+			return err
+		}
+	}
+	for _, cm := range spec.ConfigMaps {
+		created, err := core.CreateOrUpdateConfigMap(ctx, r.kc, r.dataSpec(cm))
+		if err != nil {
+			return err
+		}
+		r.configMaps = append(r.configMaps, created.Name)
+	}
+	for _, secret := range spec.Secrets {
+		if err := r.createSecret(ctx, r.dataSpec(secret)); err != nil {
+			return err
+		}
+	}
+
+	if spec.ManifestPath != "" {
+		if err := r.applyManifest(ctx); err != nil {
+			return err
+		}
+	}
+
+	var waitErr error
+	for _, target := range spec.WaitFor {
+		var opts []waitK8s.WaitOption
+		if spec.WaitTimeout > 0 {
+			opts = append(opts, waitK8s.WithTimeout(spec.WaitTimeout))
+		}
+		logger.Debugf("Waiting for %s %s to be %s", target.GVR.Resource, target.Name, target.Condition.Name)
+		if err := waitK8s.WaitFor(ctx, r.kc, target.GVR, target.Name, spec.Namespace, target.Condition, opts...); err != nil {
+			waitErr = err
+			break
+		}
+	}
+
+	// The logs of a workload that failed are the most useful ones.
+	if spec.LogSelector != "" {
+		output := spec.LogOutput
+		if output == nil {
+			output = os.Stdout
+		}
+		err := loggers.StreamLogsForSelector(ctx, r.kc.Clientset, spec.Namespace, spec.LogSelector,
+			loggers.WithFollow(false), loggers.WithOutput(output))
+		if err != nil {
+			logger.Warnf("Failed to get the logs of bundle %s: %v", spec.Name, err)
+		}
+	}
+
+	return waitErr
+}
+
+// dataSpec defaults the namespace of a ConfigMap or Secret to the
+// bundle's and adds the bundle's run ID label.
+func (r *bundleRun) dataSpec(spec core.DataSpec) core.DataSpec {
+	if spec.Namespace == "" {
+		spec.Namespace = r.spec.Namespace
+	}
+	labels := make(map[string]string, len(spec.Labels)+3)
+	for key, value := range spec.Labels {
+		labels[key] = value
+	}
+	for key, value := range r.metadata.TrackingLabels() {
+		labels[key] = value
+	}
+	spec.Labels = labels
+	return spec
+}
+
+// createSecret creates a Secret and records it for the teardown.
+func (r *bundleRun) createSecret(ctx context.Context, spec core.DataSpec) error {
+	created, err := core.CreateOrUpdateSecret(ctx, r.kc, spec)
+	if err != nil {
+		return err
+	}
+	r.secrets = append(r.secrets, created.Name)
+	return nil
+}
+
+// createImagePullSecret stores a registry credential in the format of
+// `kubectl create secret docker-registry`.
+func (r *bundleRun) createImagePullSecret(ctx context.Context, cred RegistryCredential) error {
+	if cred.Name == "" || cred.Server == "" {
+		return errors.New("image pull secret name and server are required")
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + cred.Password))
+	config, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			cred.Server: map[string]string{"username": cred.Username, "password": cred.Password, "auth": auth},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode image pull secret '%s': %v", cred.Name, err)
+	}
+
+	return r.createSecret(ctx, r.dataSpec(core.DataSpec{
+		Name:     cred.Name,
+		Literals: map[string]string{corev1.DockerConfigJsonKey: string(config)},
+		Type:     corev1.SecretTypeDockerConfigJson,
+	}))
+}
+
+// applyManifest applies the workload manifest, recording its objects in
+// an inventory so that the teardown knows their resource types.
+func (r *bundleRun) applyManifest(ctx context.Context) error {
+	manifestType := manifests.ManifestRaw
+	if info, err := os.Stat(r.spec.ManifestPath); err == nil && info.IsDir() {
+		manifestType = manifests.ManifestKustomize
+	}
+
+	r.manifest = &manifests.ManifestConfig{
+		ManifestPath:     r.spec.ManifestPath,
+		Namespace:        r.spec.Namespace,
+		Type:             manifestType,
+		Operation:        manifests.OperationApply,
+		Client:           r.kc.DynamicClient,
+		ReadFile:         os.ReadFile,
+		ResourceMetadata: r.metadata,
+		Values:           r.spec.Values,
+		Inventory:        r.spec.Name + "-inventory",
+	}
+	if err := r.manifest.ApplyOrDeleteManifest(ctx); err != nil {
+		return fmt.Errorf("failed to apply manifest of bundle %s: %v", r.spec.Name, err)
+	}
+
+	return nil
+}
+
+// teardown deletes the resources created by deploy, continuing past
+// errors so that as much as possible is cleaned up.
+func (r *bundleRun) teardown(ctx context.Context) error {
+	var errs []error
+
+	if r.manifest != nil {
+		errs = append(errs, r.deleteManifestObjects(ctx))
+	}
+	for _, name := range r.configMaps {
+		err := r.kc.Clientset.CoreV1().ConfigMaps(r.spec.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete configmap '%s': %v", name, err))
+		}
+	}
+	for _, name := range r.secrets {
+		err := r.kc.Clientset.CoreV1().Secrets(r.spec.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete secret '%s': %v", name, err))
+		}
+	}
+	if r.createdNamespace {
+		errs = append(errs, core.DeleteNamespace(ctx, r.kc, r.spec.Namespace))
+	}
+
+	return errors.Join(errs...)
+}
+
+// deleteManifestObjects deletes the objects recorded in the manifest's
+// inventory, and the inventory itself, by their run ID. Objects that
+// are already gone, such as Jobs removed by their TTL, are skipped.
+func (r *bundleRun) deleteManifestObjects(ctx context.Context) error {
+	entries, err := r.manifest.ReadInventory(ctx, r.kc.DynamicClient)
+	if err != nil {
+		return err
+	}
+
+	// The inventory is a ConfigMap labeled with the run ID.
+	gvrs := []schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}}
+	seen := map[schema.GroupVersionResource]bool{gvrs[0]: true}
+	for _, entry := range entries {
+		gvr := schema.GroupVersionResource{Group: entry.Group, Version: entry.Version, Resource: entry.Resource}
+		if !seen[gvr] {
+			seen[gvr] = true
+			gvrs = append(gvrs, gvr)
+		}
+	}
+
+	if _, err := manifests.DeleteByRunID(ctx, r.kc.DynamicClient, r.spec.Name, r.spec.Namespace, gvrs); err != nil {
+		return fmt.Errorf("failed to delete manifest objects of bundle %s: %v", r.spec.Name, err)
+	}
+
+	return nil
+}
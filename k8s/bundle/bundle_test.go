@@ -0,0 +1,181 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bundle "github.com/l50/goutils/v2/k8s/bundle"
+	client "github.com/l50/goutils/v2/k8s/client"
+	core "github.com/l50/goutils/v2/k8s/core"
+	waitK8s "github.com/l50/goutils/v2/k8s/wait"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const jobManifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: scan
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: scan
+          image: {{ .image }}
+`
+
+var (
+	jobsGVR       = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+func TestDeployBundle(t *testing.T) {
+	tests := []struct {
+		name           string
+		teardown       bundle.TeardownPolicy
+		condition      waitK8s.Condition
+		expectErr      bool
+		expectTornDown bool
+	}{
+		{
+			name:           "Tears down after completion",
+			condition:      waitK8s.Exists(),
+			expectTornDown: true,
+		},
+		{
+			name:           "Tears down after failure",
+			condition:      waitK8s.JSONPathEquals(".status.succeeded", "1"),
+			expectErr:      true,
+			expectTornDown: true,
+		},
+		{
+			name:      "Keeps failed bundle",
+			teardown:  bundle.TeardownOnSuccess,
+			condition: waitK8s.JSONPathEquals(".status.succeeded", "1"),
+			expectErr: true,
+		},
+		{
+			name:      "Never tears down",
+			teardown:  bundle.TeardownNever,
+			condition: waitK8s.Exists(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "job.yaml")
+			require.NoError(t, os.WriteFile(manifestPath, []byte(jobManifest), 0644))
+			scriptPath := filepath.Join(dir, "scan.sh")
+			require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\necho scanning\n"), 0755))
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "scan-abcde", Namespace: "scans", Labels: map[string]string{"job-name": "scan"},
+			}}
+			kc := &client.KubernetesClient{
+				Clientset: fake.NewSimpleClientset(pod),
+				DynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+					map[schema.GroupVersionResource]string{jobsGVR: "JobList", configMapsGVR: "ConfigMapList"}),
+			}
+
+			var logs bytes.Buffer
+			err := bundle.DeployBundle(ctx, kc, bundle.BundleSpec{
+				Name:      "scan",
+				Namespace: "scans",
+				ImagePullSecret: &bundle.RegistryCredential{
+					Name: "registry", Server: "ghcr.io", Username: "bot", Password: "token",
+				},
+				ConfigMaps:   []core.DataSpec{{Name: "scripts", Files: []string{scriptPath}}},
+				ManifestPath: manifestPath,
+				Values:       map[string]interface{}{"image": "alpine"},
+				WaitFor:      []bundle.WaitTarget{{GVR: jobsGVR, Name: "scan", Condition: tc.condition}},
+				WaitTimeout:  50 * time.Millisecond,
+				LogSelector:  "job-name=scan",
+				LogOutput:    &logs,
+				Teardown:     tc.teardown,
+			})
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Contains(t, logs.String(), "[scan-abcde]")
+
+			_, jobErr := kc.DynamicClient.Resource(jobsGVR).Namespace("scans").Get(ctx, "scan", metav1.GetOptions{})
+			_, invErr := kc.DynamicClient.Resource(configMapsGVR).Namespace("scans").Get(ctx, "scan-inventory", metav1.GetOptions{})
+			_, cmErr := kc.Clientset.CoreV1().ConfigMaps("scans").Get(ctx, "scripts", metav1.GetOptions{})
+			secret, secretErr := kc.Clientset.CoreV1().Secrets("scans").Get(ctx, "registry", metav1.GetOptions{})
+			_, nsErr := kc.Clientset.CoreV1().Namespaces().Get(ctx, "scans", metav1.GetOptions{})
+
+			if tc.expectTornDown {
+				for _, err := range []error{jobErr, invErr, cmErr, secretErr, nsErr} {
+					assert.True(t, apierrors.IsNotFound(err), "expected deletion, got %v", err)
+				}
+				return
+			}
+			for _, err := range []error{jobErr, invErr, cmErr, secretErr, nsErr} {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type)
+			assert.True(t, strings.Contains(string(secret.Data[corev1.DockerConfigJsonKey]), `"ghcr.io"`))
+			assert.Equal(t, "scan", secret.Labels["goutils/run-id"])
+		})
+	}
+}
+
+func TestDeployBundleKeepsExistingNamespace(t *testing.T) {
+	ctx := context.Background()
+	kc := &client.KubernetesClient{
+		Clientset:     fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared"}}),
+		DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	}
+
+	err := bundle.DeployBundle(ctx, kc, bundle.BundleSpec{
+		Name:       "config",
+		Namespace:  "shared",
+		ConfigMaps: []core.DataSpec{{Name: "settings", Literals: map[string]string{"mode": "fast"}}},
+	})
+	require.NoError(t, err)
+
+	_, err = kc.Clientset.CoreV1().Namespaces().Get(ctx, "shared", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = kc.Clientset.CoreV1().ConfigMaps("shared").Get(ctx, "settings", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the configmap to be deleted, got %v", err)
+}
+
+func TestDeployBundleInvalid(t *testing.T) {
+	kc := &client.KubernetesClient{
+		Clientset:     fake.NewSimpleClientset(),
+		DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	}
+
+	tests := []struct {
+		name string
+		kc   *client.KubernetesClient
+		spec bundle.BundleSpec
+	}{
+		{name: "No client", spec: bundle.BundleSpec{Name: "scan", Namespace: "scans"}},
+		{name: "Invalid name", kc: kc, spec: bundle.BundleSpec{Name: "Scan_1", Namespace: "scans"}},
+		{name: "No namespace", kc: kc, spec: bundle.BundleSpec{Name: "scan"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, bundle.DeployBundle(context.Background(), tc.kc, tc.spec))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// clientSource holds what NewKubernetesClient used to create a client, so
+// that the kubeconfig can be read again when credentials change.
+type clientSource struct {
+	kubeconfig string
+	reader     FileReaderFunc
+	client     KubernetesClientInterface
+	// load builds the REST configuration instead of reader, e.g., for a
+	// named context or the in-cluster configuration.
+	load func() (*rest.Config, error)
+}
+
+// restConfig reads the kubeconfig and builds a REST configuration from it.
+func (s *clientSource) restConfig() (*rest.Config, error) {
+	if s.load != nil {
+		return s.load()
+	}
+
+	configData, err := s.reader(s.kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error reading kubeconfig: %v", err)
+	}
+
+	config, err := s.client.RESTConfigFromKubeConfig(configData)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubeconfig: %v", err)
+	}
+
+	return config, nil
+}
+
+// clients creates the typed and dynamic clients for a REST configuration.
+func (s *clientSource) clients(config *rest.Config) (kubernetes.Interface, dynamic.Interface, error) {
+	clientset, err := s.client.NewForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating Kubernetes client: %v", err)
+	}
+
+	dynamicClient, err := s.client.NewDynamicForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating dynamic Kubernetes client: %v", err)
+	}
+
+	return clientset, dynamicClient, nil
+}
+
+// RefreshAuth rebuilds the client's configuration, clientset, and dynamic
+// client so that new requests use current credentials. Clients created
+// with NewKubernetesClient or NewKubernetesClientForContext read their
+// kubeconfig again, picking up tokens that a cloud provider CLI rewrote
+// since, and in-cluster clients read their service account token again;
+// other clients are rebuilt from a copy of Config, which rereads
+// BearerTokenFile. Credentials from exec plugins (e.g., aws eks get-token
+// or gke-gcloud-auth-plugin) are cached by client-go until they expire or
+// the API server rejects them, after which the plugin is run again for
+// the next request.
+//
+// RefreshAuth replaces the Clientset, DynamicClient, and Config fields, so
+// long-running code should read them again after it returns rather than
+// holding on to the old clients. Concurrent calls are serialized.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+//
+// **Returns:**
+//
+// error: An error if the kubeconfig can't be read or the clients can't be
+// created. The existing clients are kept in that case.
+func (kc *KubernetesClient) RefreshAuth(ctx context.Context) error {
+	kc.refreshMu.Lock()
+	defer kc.refreshMu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	source := kc.source
+	var config *rest.Config
+	switch {
+	case source != nil:
+		var err error
+		if config, err = source.restConfig(); err != nil {
+			return err
+		}
+	case kc.Config != nil:
+		source = &clientSource{client: &RealKubernetesClient{}}
+		config = rest.CopyConfig(kc.Config)
+	default:
+		return errors.New("cannot refresh credentials: client has no kubeconfig or REST configuration")
+	}
+
+	clientset, dynamicClient, err := source.clients(config)
+	if err != nil {
+		return err
+	}
+
+	kc.Config = config
+	kc.Clientset = clientset
+	kc.DynamicClient = dynamicClient
+
+	return nil
+}
+
+// RetryOnUnauthorized runs fn and, if it fails because the API server
+// rejected the client's credentials (HTTP 401), refreshes them with
+// RefreshAuth and runs fn once more. It's intended to wrap calls made
+// through the typed or dynamic client in long-running automation, such
+// as log streaming, that would otherwise fail when short-lived cloud
+// provider tokens expire mid-run. fn should read the clients from kc
+// each time it's called so the retry uses the refreshed clients.
+//
+// Errors are matched with apierrors.IsUnauthorized, so fn must return or
+// wrap (with %w) the error from client-go.
+//
+// **Parameters:**
+//
+// ctx: A context.Context passed to fn and RefreshAuth.
+// fn: The function making requests with the client.
+//
+// **Returns:**
+//
+// error: The error returned by fn, or an error if the credentials can't
+// be refreshed.
+func (kc *KubernetesClient) RetryOnUnauthorized(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	if !apierrors.IsUnauthorized(err) {
+		return err
+	}
+
+	if refreshErr := kc.RefreshAuth(ctx); refreshErr != nil {
+		return fmt.Errorf("failed to refresh credentials after %v: %w", err, refreshErr)
+	}
+
+	return fn(ctx)
+}
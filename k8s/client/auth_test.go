@@ -0,0 +1,154 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// rotatingKubeconfig returns a reader serving a kubeconfig for server
+// whose token is the next entry of tokens on every read.
+func rotatingKubeconfig(server string, tokens ...string) (client.FileReaderFunc, *atomic.Int32) {
+	reads := &atomic.Int32{}
+	return func(string) ([]byte, error) {
+		i := int(reads.Add(1)) - 1
+		if i >= len(tokens) {
+			i = len(tokens) - 1
+		}
+		return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: test-user
+  name: test-context
+current-context: test-context
+users:
+- name: test-user
+  user:
+    token: %s
+`, server, tokens[i])), nil
+	}, reads
+}
+
+// tokenServer returns an API server that only accepts the "fresh" token.
+// It uses TLS because client-go only sends bearer tokens over HTTPS.
+func tokenServer(t *testing.T) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`)
+			return
+		}
+		fmt.Fprint(w, `{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"default"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRefreshAuth(t *testing.T) {
+	reader, reads := rotatingKubeconfig("https://localhost:6443", "expired", "fresh")
+	kc, err := client.NewKubernetesClient("kubeconfig", reader, &client.RealKubernetesClient{})
+	require.NoError(t, err)
+	require.Equal(t, "expired", kc.Config.BearerToken)
+	oldClientset := kc.Clientset
+
+	require.NoError(t, kc.RefreshAuth(context.Background()))
+	assert.Equal(t, int32(2), reads.Load())
+	assert.Equal(t, "fresh", kc.Config.BearerToken)
+	assert.NotSame(t, oldClientset, kc.Clientset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, kc.RefreshAuth(ctx), context.Canceled)
+
+	literal := &client.KubernetesClient{Config: &rest.Config{Host: "https://localhost:6443", BearerToken: "token"}}
+	require.NoError(t, literal.RefreshAuth(context.Background()))
+	assert.NotNil(t, literal.Clientset)
+	assert.NotNil(t, literal.DynamicClient)
+
+	assert.Error(t, (&client.KubernetesClient{}).RefreshAuth(context.Background()))
+}
+
+func TestRetryOnUnauthorized(t *testing.T) {
+	server := tokenServer(t)
+
+	testCases := []struct {
+		name      string
+		tokens    []string
+		fnErr     error
+		wantCalls int
+		wantReads int32
+		wantErr   bool
+	}{
+		{
+			name:      "Expired token is refreshed",
+			tokens:    []string{"expired", "fresh"},
+			wantCalls: 2,
+			wantReads: 2,
+		},
+		{
+			name:      "Valid token isn't refreshed",
+			tokens:    []string{"fresh"},
+			wantCalls: 1,
+			wantReads: 1,
+		},
+		{
+			name:      "Rejected after refresh",
+			tokens:    []string{"expired", "revoked"},
+			wantCalls: 2,
+			wantReads: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "Other errors aren't retried",
+			tokens:    []string{"fresh"},
+			fnErr:     errors.New("boom"),
+			wantCalls: 1,
+			wantReads: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, reads := rotatingKubeconfig(server.URL, tc.tokens...)
+			kc, err := client.NewKubernetesClient("kubeconfig", reader, &client.RealKubernetesClient{})
+			require.NoError(t, err)
+
+			calls := 0
+			err = kc.RetryOnUnauthorized(context.Background(), func(ctx context.Context) error {
+				calls++
+				if tc.fnErr != nil {
+					return tc.fnErr
+				}
+				_, err := kc.Clientset.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+				return err
+			})
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantReads, reads.Load())
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/l50/goutils/v2/sys"
 	"k8s.io/client-go/dynamic"
@@ -31,6 +32,11 @@ type KubernetesClient struct {
 	Clientset     kubernetes.Interface
 	DynamicClient dynamic.Interface
 	Config        *rest.Config
+
+	// source records how the client was created so RefreshAuth can
+	// rebuild it. It's nil for clients created with a struct literal.
+	source    *clientSource
+	refreshMu sync.Mutex
 }
 
 // KubernetesClientInterface defines the interface for the KubernetesClient.
@@ -114,27 +120,19 @@ func (r *RealKubernetesClient) RESTConfigFromKubeConfig(configData []byte) (*res
 // specified kubeconfig.
 // error: An error if any issue occurs while creating the Kubernetes client.
 func NewKubernetesClient(kubeconfig string, reader FileReaderFunc, client KubernetesClientInterface) (*KubernetesClient, error) {
-	configData, err := reader(kubeconfig)
-	if err != nil {
-		return nil, fmt.Errorf("error reading kubeconfig: %v", err)
-	}
-
-	config, err := client.RESTConfigFromKubeConfig(configData)
-	if err != nil {
-		return nil, fmt.Errorf("error building kubeconfig: %v", err)
-	}
+	source := &clientSource{kubeconfig: kubeconfig, reader: reader, client: client}
 
-	clientset, err := client.NewForConfig(config)
+	config, err := source.restConfig()
 	if err != nil {
-		return nil, fmt.Errorf("error creating Kubernetes client: %v", err)
+		return nil, err
 	}
 
-	dynamicClient, err := client.NewDynamicForConfig(config)
+	clientset, dynamicClient, err := source.clients(config)
 	if err != nil {
-		return nil, fmt.Errorf("error creating dynamic Kubernetes client: %v", err)
+		return nil, err
 	}
 
-	return &KubernetesClient{Clientset: clientset, DynamicClient: dynamicClient, Config: config}, nil
+	return &KubernetesClient{Clientset: clientset, DynamicClient: dynamicClient, Config: config, source: source}, nil
 }
 
 // SetupKubeConfig sets the KUBECONFIG environment variable to the default path
@@ -0,0 +1,221 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeContext describes a context of a kubeconfig.
+//
+// **Attributes:**
+//
+// Name: The name of the context.
+// Cluster: The name of the context's cluster.
+// Server: The API server URL of the context's cluster.
+// User: The name of the context's user.
+// Namespace: The default namespace of the context. Empty if unset.
+// Current: Whether the context is the current context.
+type KubeContext struct {
+	Name      string
+	Cluster   string
+	Server    string
+	User      string
+	Namespace string
+	Current   bool
+}
+
+// ListContexts lists the contexts of a kubeconfig, sorted by name.
+//
+// **Parameters:**
+//
+// kubeconfig: Path to the kubeconfig file. If empty, the files in the
+// KUBECONFIG environment variable, or ~/.kube/config, are used, like
+// kubectl does.
+//
+// **Returns:**
+//
+// []KubeContext: The contexts.
+// error: An error if the kubeconfig can't be loaded.
+func ListContexts(kubeconfig string) ([]KubeContext, error) {
+	config, err := loadingRules(kubeconfig).Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+
+	contexts := make([]KubeContext, 0, len(config.Contexts))
+	for name, kubeCtx := range config.Contexts {
+		c := KubeContext{
+			Name:      name,
+			Cluster:   kubeCtx.Cluster,
+			User:      kubeCtx.AuthInfo,
+			Namespace: kubeCtx.Namespace,
+			Current:   name == config.CurrentContext,
+		}
+		if cluster, ok := config.Clusters[kubeCtx.Cluster]; ok {
+			c.Server = cluster.Server
+		}
+		contexts = append(contexts, c)
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Name < contexts[j].Name })
+
+	return contexts, nil
+}
+
+// CurrentContext returns the name of the current context of a kubeconfig.
+//
+// **Parameters:**
+//
+// kubeconfig: Path to the kubeconfig file. If empty, the files in the
+// KUBECONFIG environment variable, or ~/.kube/config, are used.
+//
+// **Returns:**
+//
+// string: The name of the current context.
+// error: An error if the kubeconfig can't be loaded or has no current
+// context.
+func CurrentContext(kubeconfig string) (string, error) {
+	config, err := loadingRules(kubeconfig).Load()
+	if err != nil {
+		return "", fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+	if config.CurrentContext == "" {
+		return "", errors.New("kubeconfig has no current context")
+	}
+
+	return config.CurrentContext, nil
+}
+
+// UseContext switches the current context of a kubeconfig, like
+// `kubectl config use-context`. This changes the context for every
+// program using the kubeconfig; use NewKubernetesClientForContext to
+// target a context without changing the file.
+//
+// **Parameters:**
+//
+// kubeconfig: Path to the kubeconfig file. If empty, the files in the
+// KUBECONFIG environment variable, or ~/.kube/config, are used.
+// name: The name of the context to switch to.
+//
+// **Returns:**
+//
+// error: An error if the kubeconfig can't be loaded or written, or
+// doesn't have the context.
+func UseContext(kubeconfig, name string) error {
+	rules := loadingRules(kubeconfig)
+	config, err := rules.Load()
+	if err != nil {
+		return fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+	if _, ok := config.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found in kubeconfig", name)
+	}
+
+	config.CurrentContext = name
+	if err := clientcmd.ModifyConfig(rules, *config, true); err != nil {
+		return fmt.Errorf("error writing kubeconfig: %v", err)
+	}
+
+	return nil
+}
+
+// NewKubernetesClientForContext creates a new KubernetesClient for a
+// named context of a kubeconfig, regardless of its current context. This
+// allows a single program to target several clusters. RefreshAuth reads
+// the kubeconfig again for the same context.
+//
+// **Parameters:**
+//
+// kubeconfig: Path to the kubeconfig file. If empty, the files in the
+// KUBECONFIG environment variable, or ~/.kube/config, are used.
+// contextName: The name of the context. If empty, the current context
+// is used.
+// client: The KubernetesClientInterface used to create the clients. If
+// nil, RealKubernetesClient is used.
+//
+// **Returns:**
+//
+// *KubernetesClient: A new KubernetesClient instance configured for the
+// context.
+// error: An error if the kubeconfig can't be loaded, doesn't have the
+// context, or the clients can't be created.
+func NewKubernetesClientForContext(kubeconfig, contextName string, client KubernetesClientInterface) (*KubernetesClient, error) {
+	if contextName != "" {
+		config, err := loadingRules(kubeconfig).Load()
+		if err != nil {
+			return nil, fmt.Errorf("error loading kubeconfig: %v", err)
+		}
+		if _, ok := config.Contexts[contextName]; !ok {
+			return nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
+		}
+	}
+
+	return newClientFromLoader(client, func() (*rest.Config, error) {
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			loadingRules(kubeconfig), &clientcmd.ConfigOverrides{CurrentContext: contextName})
+		config, err := clientConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error building kubeconfig: %v", err)
+		}
+		return config, nil
+	})
+}
+
+// NewInClusterClient creates a new KubernetesClient from the service
+// account of the pod it runs in. RefreshAuth reads the service account
+// token again, which the kubelet rotates.
+//
+// **Parameters:**
+//
+// client: The KubernetesClientInterface used to create the clients. If
+// nil, RealKubernetesClient is used.
+//
+// **Returns:**
+//
+// *KubernetesClient: A new KubernetesClient instance configured from the
+// in-cluster configuration.
+// error: An error if the program isn't running in a pod or the clients
+// can't be created.
+func NewInClusterClient(client KubernetesClientInterface) (*KubernetesClient, error) {
+	return newClientFromLoader(client, func() (*rest.Config, error) {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error building in-cluster config: %v", err)
+		}
+		return config, nil
+	})
+}
+
+// newClientFromLoader creates a KubernetesClient whose configuration is
+// built by load, both initially and on RefreshAuth.
+func newClientFromLoader(client KubernetesClientInterface, load func() (*rest.Config, error)) (*KubernetesClient, error) {
+	if client == nil {
+		client = &RealKubernetesClient{}
+	}
+	source := &clientSource{client: client, load: load}
+
+	config, err := source.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, dynamicClient, err := source.clients(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubernetesClient{Clientset: clientset, DynamicClient: dynamicClient, Config: config, source: source}, nil
+}
+
+// loadingRules returns the rules to load a kubeconfig, using kubectl's
+// defaults if kubeconfig is empty.
+func loadingRules(kubeconfig string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	return rules
+}
@@ -0,0 +1,116 @@
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMultiClusterKubeconfig(t *testing.T, path, stagingServer string) string {
+	t.Helper()
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://prod.example.com:6443
+  name: prod
+- cluster:
+    server: %s
+  name: staging
+contexts:
+- context:
+    cluster: prod
+    user: admin
+  name: prod-admin
+- context:
+    cluster: staging
+    user: admin
+    namespace: qa
+  name: staging-admin
+current-context: prod-admin
+users:
+- name: admin
+  user:
+    token: secret
+`, stagingServer)
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+	return path
+}
+
+func TestContexts(t *testing.T) {
+	path := writeMultiClusterKubeconfig(t, filepath.Join(t.TempDir(), "config"), "https://staging.example.com:6443")
+
+	contexts, err := client.ListContexts(path)
+	require.NoError(t, err)
+	require.Equal(t, []client.KubeContext{
+		{Name: "prod-admin", Cluster: "prod", Server: "https://prod.example.com:6443", User: "admin", Current: true},
+		{Name: "staging-admin", Cluster: "staging", Server: "https://staging.example.com:6443", User: "admin", Namespace: "qa"},
+	}, contexts)
+
+	current, err := client.CurrentContext(path)
+	require.NoError(t, err)
+	require.Equal(t, "prod-admin", current)
+
+	require.NoError(t, client.UseContext(path, "staging-admin"))
+	current, err = client.CurrentContext(path)
+	require.NoError(t, err)
+	require.Equal(t, "staging-admin", current)
+
+	require.Error(t, client.UseContext(path, "missing"))
+	_, err = client.ListContexts(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestNewKubernetesClientForContext(t *testing.T) {
+	path := writeMultiClusterKubeconfig(t, filepath.Join(t.TempDir(), "config"), "https://staging.example.com:6443")
+
+	tests := []struct {
+		name         string
+		context      string
+		expectedHost string
+		wantErr      bool
+	}{
+		{name: "Current context", expectedHost: "https://prod.example.com:6443"},
+		{name: "Named context", context: "staging-admin", expectedHost: "https://staging.example.com:6443"},
+		{name: "Unknown context", context: "missing", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc, err := client.NewKubernetesClientForContext(path, tc.context, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedHost, kc.Config.Host)
+			require.Equal(t, "secret", kc.Config.BearerToken)
+			require.NotNil(t, kc.Clientset)
+			require.NotNil(t, kc.DynamicClient)
+		})
+	}
+}
+
+func TestNewKubernetesClientForContextRefresh(t *testing.T) {
+	path := writeMultiClusterKubeconfig(t, filepath.Join(t.TempDir(), "config"), "https://staging.example.com:6443")
+	kc, err := client.NewKubernetesClientForContext(path, "staging-admin", nil)
+	require.NoError(t, err)
+
+	writeMultiClusterKubeconfig(t, path, "https://staging-new.example.com:6443")
+	require.NoError(t, kc.RefreshAuth(context.Background()))
+	require.Equal(t, "https://staging-new.example.com:6443", kc.Config.Host)
+}
+
+func TestNewInClusterClient(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+	_, err := client.NewInClusterClient(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// lastAppliedAnnotation is set by kubectl apply and holds a copy of the
+// previously applied manifest.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// serverSideMetadata lists the metadata fields that the API server sets
+// and that can't be used to create an object.
+var serverSideMetadata = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"selfLink",
+	"managedFields",
+	"ownerReferences",
+}
+
+// ExportResources dumps the live objects of each resource type to YAML
+// files, with the fields set by the API server (status, managedFields,
+// uid, resourceVersion, and so on) stripped, so the files can be used as
+// a backup, redeployed with kubectl apply, or diffed against the source
+// manifests. Each object is written to
+// destDir/<resource>[.<group>]/[<namespace>/]<name>.yaml.
+//
+// **Parameters:**
+//
+// ctx: The context for the API requests.
+// kc: The Kubernetes client, whose DynamicClient is used.
+// namespace: The namespace to export namespaced resources from. An empty
+// string exports them from all namespaces. Cluster-scoped resources
+// should be exported with an empty namespace.
+// gvrs: The resource types to export (e.g., {Group: "apps", Version: "v1",
+// Resource: "deployments"}).
+// destDir: The directory to write the YAML files to. It's created if it
+// doesn't exist.
+//
+// **Returns:**
+//
+// []string: The paths of the written files, sorted.
+// error: An error if a resource type can't be listed or a file can't be
+// written.
+func ExportResources(ctx context.Context, kc *KubernetesClient, namespace string, gvrs []schema.GroupVersionResource, destDir string) ([]string, error) {
+	if kc == nil || kc.DynamicClient == nil {
+		return nil, fmt.Errorf("a Kubernetes client with a dynamic client is required")
+	}
+
+	var written []string
+	for _, gvr := range gvrs {
+		list, err := kc.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return written, fmt.Errorf("failed to list %s: %v", gvr.String(), err)
+		}
+
+		resourceDir := filepath.Join(destDir, gvr.GroupResource().String())
+		for i := range list.Items {
+			obj := &list.Items[i]
+			path, err := writeExportedObject(obj, list.GetAPIVersion(), gvr, resourceDir)
+			if err != nil {
+				return written, err
+			}
+			written = append(written, path)
+		}
+	}
+	sort.Strings(written)
+
+	return written, nil
+}
+
+// writeExportedObject strips obj and writes it to its file under
+// resourceDir, returning the file's path.
+func writeExportedObject(obj *unstructured.Unstructured, listAPIVersion string, gvr schema.GroupVersionResource, resourceDir string) (string, error) {
+	// Items returned by a List don't always carry their own apiVersion
+	// and kind.
+	if obj.GetAPIVersion() == "" {
+		obj.SetAPIVersion(listAPIVersion)
+	}
+	if obj.GetKind() == "" {
+		return "", fmt.Errorf("%s %s has no kind", gvr.Resource, obj.GetName())
+	}
+	stripServerSideFields(obj)
+
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s %s: %v", gvr.Resource, obj.GetName(), err)
+	}
+
+	dir := resourceDir
+	if ns := obj.GetNamespace(); ns != "" {
+		dir = filepath.Join(resourceDir, ns)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	path := filepath.Join(dir, obj.GetName()+".yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+
+	return path, nil
+}
+
+// stripServerSideFields removes the fields of obj that are set by the API
+// server rather than by whoever created it.
+func stripServerSideFields(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range serverSideMetadata {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[lastAppliedAnnotation]; ok {
+		delete(annotations, lastAppliedAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+	}
+
+	// A Service's cluster IPs are allocated by the API server and
+	// conflict with the original Service when redeployed elsewhere.
+	if obj.GetKind() == "Service" && obj.GroupVersionKind().Group == "" {
+		if ip, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); ip != "None" {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package k8s_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	servicesGVR    = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	namespacesGVR  = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
+
+func liveObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":              name,
+		"uid":               "0b7c6c5e-1111-2222-3333-444455556666",
+		"resourceVersion":   "12345",
+		"generation":        int64(3),
+		"creationTimestamp": "2024-05-01T00:00:00Z",
+		"managedFields":     []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		"labels":            map[string]interface{}{"app": name},
+		"annotations": map[string]interface{}{
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		},
+	}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+
+	obj := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   metadata,
+		"status":     map[string]interface{}{"observedGeneration": int64(3)},
+	}
+	if spec != nil {
+		obj["spec"] = spec
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestExportResources(t *testing.T) {
+	objects := []runtime.Object{
+		liveObject("apps/v1", "Deployment", "default", "web", map[string]interface{}{"replicas": int64(2)}),
+		liveObject("apps/v1", "Deployment", "other", "api", map[string]interface{}{"replicas": int64(1)}),
+		liveObject("v1", "Service", "default", "web", map[string]interface{}{
+			"clusterIP":  "10.0.0.10",
+			"clusterIPs": []interface{}{"10.0.0.10"},
+			"ports":      []interface{}{map[string]interface{}{"port": int64(80)}},
+		}),
+		liveObject("v1", "Service", "default", "headless", map[string]interface{}{"clusterIP": "None"}),
+		liveObject("v1", "Namespace", "", "default", nil),
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList",
+		servicesGVR:    "ServiceList",
+		namespacesGVR:  "NamespaceList",
+	}, objects...)
+	kc := &client.KubernetesClient{DynamicClient: dyn}
+
+	readExported := func(t *testing.T, path string) map[string]interface{} {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var obj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(data, &obj))
+		return obj
+	}
+
+	testCases := []struct {
+		name      string
+		namespace string
+		gvrs      []schema.GroupVersionResource
+		wantFiles []string
+	}{
+		{
+			name:      "Single namespace",
+			namespace: "default",
+			gvrs:      []schema.GroupVersionResource{deploymentsGVR, servicesGVR},
+			wantFiles: []string{
+				"deployments.apps/default/web.yaml",
+				"services/default/headless.yaml",
+				"services/default/web.yaml",
+			},
+		},
+		{
+			name: "All namespaces",
+			gvrs: []schema.GroupVersionResource{deploymentsGVR},
+			wantFiles: []string{
+				"deployments.apps/default/web.yaml",
+				"deployments.apps/other/api.yaml",
+			},
+		},
+		{
+			name:      "Cluster-scoped",
+			gvrs:      []schema.GroupVersionResource{namespacesGVR},
+			wantFiles: []string{"namespaces/default.yaml"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			destDir := t.TempDir()
+			written, err := client.ExportResources(context.Background(), kc, tc.namespace, tc.gvrs, destDir)
+			require.NoError(t, err)
+
+			var got []string
+			for _, path := range written {
+				rel, err := filepath.Rel(destDir, path)
+				require.NoError(t, err)
+				got = append(got, filepath.ToSlash(rel))
+
+				obj := readExported(t, path)
+				assert.NotContains(t, obj, "status")
+				metadata := obj["metadata"].(map[string]interface{})
+				for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "annotations"} {
+					assert.NotContains(t, metadata, field, "%s in %s", field, rel)
+				}
+				assert.Contains(t, metadata, "labels")
+				assert.NotEmpty(t, obj["kind"])
+				assert.NotEmpty(t, obj["apiVersion"])
+			}
+			assert.Equal(t, tc.wantFiles, got)
+		})
+	}
+
+	t.Run("Service cluster IPs", func(t *testing.T) {
+		destDir := t.TempDir()
+		_, err := client.ExportResources(context.Background(), kc, "default", []schema.GroupVersionResource{servicesGVR}, destDir)
+		require.NoError(t, err)
+
+		web := readExported(t, filepath.Join(destDir, "services", "default", "web.yaml"))
+		spec := web["spec"].(map[string]interface{})
+		assert.NotContains(t, spec, "clusterIP")
+		assert.NotContains(t, spec, "clusterIPs")
+		assert.Contains(t, spec, "ports")
+
+		headless := readExported(t, filepath.Join(destDir, "services", "default", "headless.yaml"))
+		assert.Equal(t, "None", headless["spec"].(map[string]interface{})["clusterIP"])
+	})
+
+	t.Run("No dynamic client", func(t *testing.T) {
+		_, err := client.ExportResources(context.Background(), &client.KubernetesClient{}, "", nil, t.TempDir())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,266 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// systemNamespace is the namespace holding the cluster's core components.
+const systemNamespace = "kube-system"
+
+// NodeHealth describes the readiness of a single node.
+//
+// **Attributes:**
+//
+// Name: The name of the node.
+// Ready: Whether the node's Ready condition is True.
+// Unschedulable: Whether the node is cordoned.
+// Reason: The reason reported by the Ready condition when the node isn't
+// ready.
+type NodeHealth struct {
+	Name          string
+	Ready         bool
+	Unschedulable bool
+	Reason        string
+}
+
+// WorkloadHealth describes a kube-system workload that isn't fully ready.
+//
+// **Attributes:**
+//
+// Kind: The kind of workload: "Deployment", "DaemonSet", "StatefulSet", or
+// "Pod" for static and unmanaged pods.
+// Name: The name of the workload.
+// Desired: The number of replicas the workload should have ready.
+// Ready: The number of replicas that are ready.
+// Reason: A description of why the workload isn't healthy.
+type WorkloadHealth struct {
+	Kind    string
+	Name    string
+	Desired int32
+	Ready   int32
+	Reason  string
+}
+
+// ClusterHealthReport is the result of ClusterHealthCheck.
+//
+// **Attributes:**
+//
+// Nodes: The readiness of every node in the cluster.
+// UnschedulableNodes: The names of cordoned nodes.
+// PendingPods: The number of pods in the Pending phase across all
+// namespaces.
+// FailingSystemWorkloads: The kube-system workloads that aren't fully
+// ready.
+// APILatency: The round-trip time of a request to the API server.
+// Healthy: Whether every node is ready, at least one node is schedulable,
+// and every kube-system workload is ready.
+// Problems: Human-readable reasons the cluster isn't healthy.
+type ClusterHealthReport struct {
+	Nodes                  []NodeHealth
+	UnschedulableNodes     []string
+	PendingPods            int
+	FailingSystemWorkloads []WorkloadHealth
+	APILatency             time.Duration
+	Healthy                bool
+	Problems               []string
+}
+
+// ClusterHealthCheck inspects a cluster and reports whether it's ready to
+// run workloads. It's intended as a single gate for automation that
+// deploys onto a freshly provisioned cluster, and is typically polled
+// until the report is healthy.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to query the cluster.
+//
+// **Returns:**
+//
+// *ClusterHealthReport: The health of the cluster.
+// error: An error if the cluster can't be queried.
+func ClusterHealthCheck(ctx context.Context, kc *KubernetesClient) (*ClusterHealthReport, error) {
+	report := &ClusterHealthReport{}
+
+	start := time.Now()
+	if _, err := kc.Clientset.Discovery().ServerVersion(); err != nil {
+		return nil, fmt.Errorf("failed to reach API server: %v", err)
+	}
+	report.APILatency = time.Since(start)
+
+	if err := checkNodes(ctx, kc, report); err != nil {
+		return nil, err
+	}
+	if err := checkSystemWorkloads(ctx, kc, report); err != nil {
+		return nil, err
+	}
+
+	pods, err := kc.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodPending {
+			report.PendingPods++
+		}
+		if pod.Namespace == systemNamespace && isUnmanagedPod(pod) {
+			if reason, failing := podFailure(pod); failing {
+				report.FailingSystemWorkloads = append(report.FailingSystemWorkloads,
+					WorkloadHealth{Kind: "Pod", Name: pod.Name, Desired: 1, Reason: reason})
+			}
+		}
+	}
+
+	for _, w := range report.FailingSystemWorkloads {
+		report.Problems = append(report.Problems, fmt.Sprintf("%s %s/%s: %s", w.Kind, systemNamespace, w.Name, w.Reason))
+	}
+	report.Healthy = len(report.Problems) == 0
+
+	return report, nil
+}
+
+// checkNodes records the readiness of each node in report.
+func checkNodes(ctx context.Context, kc *KubernetesClient, report *ClusterHealthReport) error {
+	nodes, err := kc.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	if len(nodes.Items) == 0 {
+		report.Problems = append(report.Problems, "cluster has no nodes")
+	}
+
+	schedulable := 0
+	for _, node := range nodes.Items {
+		health := NodeHealth{
+			Name:          node.Name,
+			Unschedulable: node.Spec.Unschedulable,
+			Reason:        "node has no Ready condition",
+		}
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				health.Ready = cond.Status == corev1.ConditionTrue
+				health.Reason = cond.Reason
+				if cond.Message != "" {
+					health.Reason = fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+				}
+			}
+		}
+		if health.Ready {
+			health.Reason = ""
+		} else {
+			report.Problems = append(report.Problems, fmt.Sprintf("node %s is not ready: %s", node.Name, health.Reason))
+		}
+
+		if health.Unschedulable {
+			report.UnschedulableNodes = append(report.UnschedulableNodes, node.Name)
+		} else if health.Ready {
+			schedulable++
+		}
+		report.Nodes = append(report.Nodes, health)
+	}
+
+	if len(nodes.Items) > 0 && schedulable == 0 {
+		report.Problems = append(report.Problems, "no ready node is schedulable")
+	}
+
+	return nil
+}
+
+// checkSystemWorkloads records the kube-system deployments, daemonsets,
+// and statefulsets that aren't fully ready in report.
+func checkSystemWorkloads(ctx context.Context, kc *KubernetesClient, report *ClusterHealthReport) error {
+	apps := kc.Clientset.AppsV1()
+
+	deployments, err := apps.Deployments(systemNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list %s deployments: %v", systemNamespace, err)
+	}
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		addIfNotReady(report, "Deployment", d.Name, desired, d.Status.ReadyReplicas)
+	}
+
+	daemonSets, err := apps.DaemonSets(systemNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list %s daemonsets: %v", systemNamespace, err)
+	}
+	for _, ds := range daemonSets.Items {
+		addIfNotReady(report, "DaemonSet", ds.Name, ds.Status.DesiredNumberScheduled, ds.Status.NumberReady)
+	}
+
+	statefulSets, err := apps.StatefulSets(systemNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list %s statefulsets: %v", systemNamespace, err)
+	}
+	for _, sts := range statefulSets.Items {
+		desired := int32(1)
+		if sts.Spec.Replicas != nil {
+			desired = *sts.Spec.Replicas
+		}
+		addIfNotReady(report, "StatefulSet", sts.Name, desired, sts.Status.ReadyReplicas)
+	}
+
+	return nil
+}
+
+// addIfNotReady records a workload in report if fewer than desired of its
+// replicas are ready.
+func addIfNotReady(report *ClusterHealthReport, kind, name string, desired, ready int32) {
+	if ready >= desired {
+		return
+	}
+	report.FailingSystemWorkloads = append(report.FailingSystemWorkloads, WorkloadHealth{
+		Kind:    kind,
+		Name:    name,
+		Desired: desired,
+		Ready:   ready,
+		Reason:  fmt.Sprintf("%d/%d replicas ready", ready, desired),
+	})
+}
+
+// isUnmanagedPod reports whether a pod isn't owned by a workload
+// controller, as is the case for static control plane pods such as
+// kube-apiserver.
+func isUnmanagedPod(pod corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && ref.Kind != "Node" {
+			return false
+		}
+	}
+	return true
+}
+
+// podFailure reports whether a pod is not running and ready, along with
+// the reason.
+func podFailure(pod corev1.Pod) (string, bool) {
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return "", false
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return fmt.Sprintf("container %s is waiting: %s", cs.Name, cs.State.Waiting.Reason), true
+		}
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Sprintf("pod is %s", pod.Status.Phase), true
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+			return "pod is not ready", true
+		}
+	}
+
+	return "", false
+}
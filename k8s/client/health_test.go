@@ -0,0 +1,125 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readyNode(name string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	reason := "KubeletReady"
+	if !ready {
+		status = corev1.ConditionFalse
+		reason = "KubeletNotReady"
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status, Reason: reason}},
+		},
+	}
+}
+
+func podWithPhase(namespace, name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestClusterHealthCheck(t *testing.T) {
+	two := int32(2)
+
+	testCases := []struct {
+		name              string
+		objects           []runtime.Object
+		wantHealthy       bool
+		wantPending       int
+		wantUnschedulable []string
+		wantFailing       []string
+	}{
+		{
+			name: "healthy cluster",
+			objects: []runtime.Object{
+				readyNode("node-1", true, false),
+				readyNode("node-2", true, true),
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &two},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+				},
+				podWithPhase("default", "job-1", corev1.PodPending),
+			},
+			wantHealthy:       true,
+			wantPending:       1,
+			wantUnschedulable: []string{"node-2"},
+		},
+		{
+			name: "unready node and failing system workloads",
+			objects: []runtime.Object{
+				readyNode("node-1", true, false),
+				readyNode("node-2", false, false),
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &two},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+				},
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system"},
+					Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 2},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-node-1", Namespace: "kube-system"},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "kube-apiserver",
+							State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						}},
+					},
+				},
+			},
+			wantFailing: []string{"coredns", "kube-apiserver-node-1"},
+		},
+		{
+			name: "all nodes cordoned",
+			objects: []runtime.Object{
+				readyNode("node-1", true, true),
+			},
+			wantUnschedulable: []string{"node-1"},
+		},
+		{
+			name: "no nodes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset(tc.objects...)}
+
+			report, err := client.ClusterHealthCheck(context.Background(), kc)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantHealthy, report.Healthy, "problems: %v", report.Problems)
+			assert.Equal(t, tc.wantHealthy, len(report.Problems) == 0)
+			assert.Equal(t, tc.wantPending, report.PendingPods)
+			assert.Equal(t, tc.wantUnschedulable, report.UnschedulableNodes)
+
+			var failing []string
+			for _, w := range report.FailingSystemWorkloads {
+				failing = append(failing, w.Name)
+			}
+			assert.Equal(t, tc.wantFailing, failing)
+		})
+	}
+}
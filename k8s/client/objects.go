@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsPodReady reports whether a pod's Ready condition is True.
+//
+// **Parameters:**
+//
+// pod: The pod to check.
+//
+// **Returns:**
+//
+// bool: True if the pod is ready, false otherwise, including when the
+// pod doesn't report a Ready condition.
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// MergeMetadata returns existing labels or annotations overridden by the
+// desired ones. existing isn't modified.
+//
+// **Parameters:**
+//
+// existing: The labels or annotations of an object.
+// desired: The entries to add or override.
+//
+// **Returns:**
+//
+// map[string]string: The merged entries, or existing if desired is
+// empty.
+func MergeMetadata(existing, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return existing
+	}
+	merged := make(map[string]string, len(existing)+len(desired))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,82 @@
+package k8s_test
+
+import (
+	"maps"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsPodReady(t *testing.T) {
+	testCases := []struct {
+		name       string
+		conditions []corev1.PodCondition
+		want       bool
+	}{
+		{
+			name:       "ready",
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			want:       true,
+		},
+		{
+			name: "not ready",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+		{
+			name: "no ready condition",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			if got := client.IsPodReady(pod); got != tc.want {
+				t.Errorf("IsPodReady() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		existing map[string]string
+		desired  map[string]string
+		want     map[string]string
+	}{
+		{
+			name:     "desired overrides existing",
+			existing: map[string]string{"app": "web", "tier": "frontend"},
+			desired:  map[string]string{"tier": "backend", "team": "platform"},
+			want:     map[string]string{"app": "web", "tier": "backend", "team": "platform"},
+		},
+		{
+			name:     "no desired entries",
+			existing: map[string]string{"app": "web"},
+			want:     map[string]string{"app": "web"},
+		},
+		{
+			name:    "no existing entries",
+			desired: map[string]string{"app": "web"},
+			want:    map[string]string{"app": "web"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := maps.Clone(tc.existing)
+
+			got := client.MergeMetadata(existing, tc.desired)
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("MergeMetadata() = %v, want %v", got, tc.want)
+			}
+			if !maps.Equal(existing, tc.existing) {
+				t.Errorf("MergeMetadata() modified existing: %v", existing)
+			}
+		})
+	}
+}
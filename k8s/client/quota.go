@@ -0,0 +1,351 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuotaViolationSource identifies the kind of object that would reject a
+// pod at admission.
+type QuotaViolationSource string
+
+const (
+	// SourceResourceQuota indicates a violation of a ResourceQuota.
+	SourceResourceQuota QuotaViolationSource = "ResourceQuota"
+	// SourceLimitRange indicates a violation of a LimitRange.
+	SourceLimitRange QuotaViolationSource = "LimitRange"
+)
+
+// QuotaViolation describes a single constraint a pod wouldn't satisfy.
+//
+// **Attributes:**
+//
+// Source: The kind of object defining the constraint.
+// Name: The name of the ResourceQuota or LimitRange.
+// Container: The container violating a per-container LimitRange. Empty
+// for constraints on the whole pod.
+// Resource: The constrained resource (e.g., "requests.cpu" for a quota or
+// "memory" for a LimitRange).
+// Requested: The amount the pod would use. Zero if the pod doesn't
+// specify the resource.
+// Allowed: The amount the constraint allows, such as the remaining quota.
+// Reason: A human-readable description of the violation.
+type QuotaViolation struct {
+	Source    QuotaViolationSource
+	Name      string
+	Container string
+	Resource  corev1.ResourceName
+	Requested resource.Quantity
+	Allowed   resource.Quantity
+	Reason    string
+}
+
+// QuotaVerdict is the result of CheckFitsQuota.
+//
+// **Attributes:**
+//
+// Fits: Whether the pod satisfies every ResourceQuota and LimitRange in
+// the namespace.
+// Requests: The effective requests of the pod after LimitRange defaults
+// are applied.
+// Limits: The effective limits of the pod after LimitRange defaults are
+// applied.
+// Violations: The constraints the pod wouldn't satisfy.
+type QuotaVerdict struct {
+	Fits       bool
+	Requests   corev1.ResourceList
+	Limits     corev1.ResourceList
+	Violations []QuotaViolation
+}
+
+// containerResources holds the effective resources of a container.
+type containerResources struct {
+	name     string
+	init     bool
+	requests corev1.ResourceList
+	limits   corev1.ResourceList
+}
+
+// CheckFitsQuota checks whether a pod would be admitted to a namespace
+// given its ResourceQuotas and LimitRanges, so that a Job can fail fast
+// with a clear reason instead of an opaque admission error. LimitRange
+// defaults are applied to containers before checking, as the API server
+// would. ResourceQuotas restricted by scopes are skipped, since whether
+// they apply depends on more than the pod's resources.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to query the cluster.
+// namespace: The namespace the pod would be created in.
+// podSpec: The spec of the pod, such as a Job's pod template spec.
+//
+// **Returns:**
+//
+// *QuotaVerdict: Whether the pod fits and, if not, why.
+// error: An error if the quotas or limit ranges can't be listed.
+func CheckFitsQuota(ctx context.Context, kc *KubernetesClient, namespace string, podSpec corev1.PodSpec) (*QuotaVerdict, error) {
+	limitRanges, err := kc.Clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges in %s: %v", namespace, err)
+	}
+	quotas, err := kc.Clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas in %s: %v", namespace, err)
+	}
+
+	containers := effectiveContainerResources(podSpec, limitRanges.Items)
+	verdict := &QuotaVerdict{}
+	verdict.Requests, verdict.Limits = podResources(containers, podSpec.Overhead)
+
+	for _, lr := range limitRanges.Items {
+		verdict.Violations = append(verdict.Violations, checkLimitRange(lr, containers, verdict.Requests, verdict.Limits)...)
+	}
+	for _, quota := range quotas.Items {
+		verdict.Violations = append(verdict.Violations, checkResourceQuota(quota, verdict.Requests, verdict.Limits)...)
+	}
+	verdict.Fits = len(verdict.Violations) == 0
+
+	return verdict, nil
+}
+
+// effectiveContainerResources returns the resources of each container in
+// spec after applying the container defaults of the limit ranges.
+// Resources with a limit but no request default the request to the
+// limit.
+func effectiveContainerResources(spec corev1.PodSpec, limitRanges []corev1.LimitRange) []containerResources {
+	var containers []containerResources
+	add := func(c corev1.Container, init bool) {
+		cr := containerResources{
+			name:     c.Name,
+			init:     init,
+			requests: c.Resources.Requests.DeepCopy(),
+			limits:   c.Resources.Limits.DeepCopy(),
+		}
+		if cr.requests == nil {
+			cr.requests = corev1.ResourceList{}
+		}
+		if cr.limits == nil {
+			cr.limits = corev1.ResourceList{}
+		}
+
+		for _, lr := range limitRanges {
+			for _, item := range lr.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for name, qty := range item.Default {
+					if _, ok := cr.limits[name]; !ok {
+						cr.limits[name] = qty.DeepCopy()
+					}
+				}
+				for name, qty := range item.DefaultRequest {
+					if _, ok := cr.requests[name]; !ok {
+						cr.requests[name] = qty.DeepCopy()
+					}
+				}
+			}
+		}
+		for name, qty := range cr.limits {
+			if _, ok := cr.requests[name]; !ok {
+				cr.requests[name] = qty.DeepCopy()
+			}
+		}
+
+		containers = append(containers, cr)
+	}
+
+	for _, c := range spec.InitContainers {
+		add(c, true)
+	}
+	for _, c := range spec.Containers {
+		add(c, false)
+	}
+
+	return containers
+}
+
+// podResources returns the total requests and limits of a pod: the sum
+// of its containers, or the largest init container if that's greater,
+// plus the pod overhead.
+func podResources(containers []containerResources, overhead corev1.ResourceList) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, c := range containers {
+		if !c.init {
+			addResources(requests, c.requests)
+			addResources(limits, c.limits)
+		}
+	}
+	for _, c := range containers {
+		if c.init {
+			maxResources(requests, c.requests)
+			maxResources(limits, c.limits)
+		}
+	}
+	addResources(requests, overhead)
+	addResources(limits, overhead)
+
+	return requests, limits
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, qty := range add {
+		sum := total[name]
+		sum.Add(qty)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, other corev1.ResourceList) {
+	for name, qty := range other {
+		if current, ok := total[name]; !ok || qty.Cmp(current) > 0 {
+			total[name] = qty.DeepCopy()
+		}
+	}
+}
+
+// checkLimitRange returns the constraints of lr that the containers or
+// the pod as a whole violate.
+func checkLimitRange(lr corev1.LimitRange, containers []containerResources, podRequests, podLimits corev1.ResourceList) []QuotaViolation {
+	var violations []QuotaViolation
+	for _, item := range lr.Spec.Limits {
+		switch item.Type {
+		case corev1.LimitTypeContainer:
+			for _, c := range containers {
+				violations = append(violations, checkLimitRangeItem(lr.Name, c.name, item, c.requests, c.limits)...)
+			}
+		case corev1.LimitTypePod:
+			violations = append(violations, checkLimitRangeItem(lr.Name, "", item, podRequests, podLimits)...)
+		}
+	}
+	return violations
+}
+
+// checkLimitRangeItem checks the requests and limits of a container, or
+// of a whole pod if container is empty, against a single LimitRange item.
+func checkLimitRangeItem(lrName, container string, item corev1.LimitRangeItem, requests, limits corev1.ResourceList) []QuotaViolation {
+	subject := "pod"
+	if container != "" {
+		subject = fmt.Sprintf("container %s", container)
+	}
+
+	var violations []QuotaViolation
+	add := func(name corev1.ResourceName, requested, allowed resource.Quantity, format string, args ...interface{}) {
+		violations = append(violations, QuotaViolation{
+			Source:    SourceLimitRange,
+			Name:      lrName,
+			Container: container,
+			Resource:  name,
+			Requested: requested,
+			Allowed:   allowed,
+			Reason:    fmt.Sprintf("%s: %s", subject, fmt.Sprintf(format, args...)),
+		})
+	}
+
+	for _, name := range sortedResourceNames(item.Min) {
+		minQty := item.Min[name]
+		request, ok := requests[name]
+		switch {
+		case !ok:
+			add(name, resource.Quantity{}, minQty, "%s request must be specified to satisfy minimum %s", name, minQty.String())
+		case request.Cmp(minQty) < 0:
+			add(name, request, minQty, "%s request %s is below minimum %s", name, request.String(), minQty.String())
+		}
+	}
+
+	for _, name := range sortedResourceNames(item.Max) {
+		maxQty := item.Max[name]
+		limit, ok := limits[name]
+		switch {
+		case !ok:
+			add(name, resource.Quantity{}, maxQty, "%s limit must be specified to satisfy maximum %s", name, maxQty.String())
+		case limit.Cmp(maxQty) > 0:
+			add(name, limit, maxQty, "%s limit %s exceeds maximum %s", name, limit.String(), maxQty.String())
+		}
+	}
+
+	for _, name := range sortedResourceNames(item.MaxLimitRequestRatio) {
+		ratio := item.MaxLimitRequestRatio[name]
+		request, hasRequest := requests[name]
+		limit, hasLimit := limits[name]
+		if !hasRequest || !hasLimit || request.IsZero() {
+			continue
+		}
+		actual := float64(limit.MilliValue()) / float64(request.MilliValue())
+		if actual > float64(ratio.MilliValue())/1000 {
+			add(name, limit, ratio, "%s limit to request ratio %.2f exceeds maximum %s", name, actual, ratio.String())
+		}
+	}
+
+	return violations
+}
+
+// checkResourceQuota returns the resources tracked by quota that a pod
+// with the given requests and limits would exceed.
+func checkResourceQuota(quota corev1.ResourceQuota, requests, limits corev1.ResourceList) []QuotaViolation {
+	if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+		return nil
+	}
+
+	hard := quota.Status.Hard
+	if len(hard) == 0 {
+		hard = quota.Spec.Hard
+	}
+
+	var violations []QuotaViolation
+	for _, name := range sortedResourceNames(hard) {
+		var requested resource.Quantity
+		var ok bool
+		switch name {
+		case corev1.ResourceRequestsCPU, corev1.ResourceCPU:
+			requested, ok = requests[corev1.ResourceCPU]
+		case corev1.ResourceRequestsMemory, corev1.ResourceMemory:
+			requested, ok = requests[corev1.ResourceMemory]
+		case corev1.ResourceLimitsCPU:
+			requested, ok = limits[corev1.ResourceCPU]
+		case corev1.ResourceLimitsMemory:
+			requested, ok = limits[corev1.ResourceMemory]
+		case corev1.ResourcePods:
+			requested, ok = *resource.NewQuantity(1, resource.DecimalSI), true
+		default:
+			continue
+		}
+
+		remaining := hard[name].DeepCopy()
+		remaining.Sub(quota.Status.Used[name])
+
+		violation := QuotaViolation{
+			Source:    SourceResourceQuota,
+			Name:      quota.Name,
+			Resource:  name,
+			Requested: requested,
+			Allowed:   remaining,
+		}
+		switch {
+		case !ok:
+			violation.Reason = fmt.Sprintf("quota %s tracks %s, so it must be specified", quota.Name, name)
+		case requested.Cmp(remaining) > 0:
+			violation.Reason = fmt.Sprintf("quota %s: requested %s %s exceeds remaining %s",
+				quota.Name, name, requested.String(), remaining.String())
+		default:
+			continue
+		}
+		violations = append(violations, violation)
+	}
+
+	return violations
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,175 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func resourceList(pairs ...string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	for i := 0; i < len(pairs); i += 2 {
+		list[corev1.ResourceName(pairs[i])] = resource.MustParse(pairs[i+1])
+	}
+	return list
+}
+
+func podSpecWithResources(requests, limits corev1.ResourceList) corev1.PodSpec {
+	return corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:      "main",
+			Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits},
+		}},
+	}
+}
+
+func TestCheckFitsQuota(t *testing.T) {
+	const ns = "jobs"
+
+	quota := func(hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: ns},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+	limitRange := func(items ...corev1.LimitRangeItem) *corev1.LimitRange {
+		return &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: ns},
+			Spec:       corev1.LimitRangeSpec{Limits: items},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		objects        []runtime.Object
+		spec           corev1.PodSpec
+		wantFits       bool
+		wantViolations []corev1.ResourceName
+		wantRequests   corev1.ResourceList
+	}{
+		{
+			name:     "No constraints",
+			spec:     podSpecWithResources(resourceList("cpu", "4"), nil),
+			wantFits: true,
+		},
+		{
+			name: "Fits remaining quota",
+			objects: []runtime.Object{
+				quota(resourceList("requests.cpu", "4", "pods", "10"), resourceList("requests.cpu", "3", "pods", "9")),
+			},
+			spec:     podSpecWithResources(resourceList("cpu", "1"), nil),
+			wantFits: true,
+		},
+		{
+			name: "Exceeds remaining quota",
+			objects: []runtime.Object{
+				quota(resourceList("requests.cpu", "4", "pods", "10"), resourceList("requests.cpu", "3500m", "pods", "10")),
+			},
+			spec:           podSpecWithResources(resourceList("cpu", "1"), nil),
+			wantViolations: []corev1.ResourceName{"pods", "requests.cpu"},
+		},
+		{
+			name:           "Quota requires unspecified resource",
+			objects:        []runtime.Object{quota(resourceList("limits.memory", "1Gi"), nil)},
+			spec:           podSpecWithResources(resourceList("memory", "128Mi"), nil),
+			wantViolations: []corev1.ResourceName{"limits.memory"},
+		},
+		{
+			name: "LimitRange defaults satisfy quota",
+			objects: []runtime.Object{
+				quota(resourceList("limits.memory", "1Gi", "requests.memory", "1Gi"), nil),
+				limitRange(corev1.LimitRangeItem{
+					Type:           corev1.LimitTypeContainer,
+					Default:        resourceList("memory", "512Mi"),
+					DefaultRequest: resourceList("memory", "256Mi"),
+				}),
+			},
+			spec:         podSpecWithResources(nil, nil),
+			wantFits:     true,
+			wantRequests: resourceList("memory", "256Mi"),
+		},
+		{
+			name: "Container outside LimitRange min and max",
+			objects: []runtime.Object{
+				limitRange(corev1.LimitRangeItem{
+					Type: corev1.LimitTypeContainer,
+					Min:  resourceList("cpu", "100m"),
+					Max:  resourceList("memory", "1Gi"),
+				}),
+			},
+			spec:           podSpecWithResources(resourceList("cpu", "50m"), resourceList("memory", "2Gi")),
+			wantViolations: []corev1.ResourceName{"cpu", "memory"},
+		},
+		{
+			name: "Limit to request ratio exceeded",
+			objects: []runtime.Object{
+				limitRange(corev1.LimitRangeItem{
+					Type:                 corev1.LimitTypeContainer,
+					MaxLimitRequestRatio: resourceList("cpu", "2"),
+				}),
+			},
+			spec:           podSpecWithResources(resourceList("cpu", "100m"), resourceList("cpu", "500m")),
+			wantViolations: []corev1.ResourceName{"cpu"},
+		},
+		{
+			name: "Pod max includes init containers",
+			objects: []runtime.Object{
+				limitRange(corev1.LimitRangeItem{Type: corev1.LimitTypePod, Max: resourceList("cpu", "2")}),
+			},
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{
+					Name:      "setup",
+					Resources: corev1.ResourceRequirements{Limits: resourceList("cpu", "3")},
+				}},
+				Containers: []corev1.Container{{
+					Name:      "main",
+					Resources: corev1.ResourceRequirements{Limits: resourceList("cpu", "1")},
+				}},
+			},
+			wantViolations: []corev1.ResourceName{"cpu"},
+			wantRequests:   resourceList("cpu", "3"),
+		},
+		{
+			name: "Scoped quotas are skipped",
+			objects: []runtime.Object{&corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "best-effort", Namespace: ns},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard:   resourceList("pods", "0"),
+					Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort},
+				},
+			}},
+			spec:     podSpecWithResources(resourceList("cpu", "1"), nil),
+			wantFits: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset(tc.objects...)}
+
+			verdict, err := client.CheckFitsQuota(context.Background(), kc, ns, tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantFits, verdict.Fits)
+
+			var resources []corev1.ResourceName
+			for _, v := range verdict.Violations {
+				resources = append(resources, v.Resource)
+				require.NotEmpty(t, v.Reason)
+			}
+			require.Equal(t, tc.wantViolations, resources)
+
+			for name, want := range tc.wantRequests {
+				got := verdict.Requests[name]
+				require.Zero(t, want.Cmp(got), "request for %s = %s, want %s", name, got.String(), want.String())
+			}
+		})
+	}
+}
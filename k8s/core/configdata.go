@@ -0,0 +1,330 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DataSpec describes a ConfigMap or Secret created from files and
+// literals, like `kubectl create configmap|secret generic --from-file
+// --from-literal`.
+//
+// **Attributes:**
+//
+// Name: The name of the ConfigMap or Secret.
+// Namespace: The namespace of the ConfigMap or Secret.
+// Files: Files whose contents become entries. Each is a path, keyed by
+// the file's base name, or "key=path". A directory adds each regular
+// file directly inside it.
+// Literals: Entries given directly, keyed by name.
+// Labels: Optional labels added to the object.
+// Type: The type of a Secret. Defaults to corev1.SecretTypeOpaque.
+// Ignored for ConfigMaps.
+type DataSpec struct {
+	Name      string
+	Namespace string
+	Files     []string
+	Literals  map[string]string
+	Labels    map[string]string
+	Type      corev1.SecretType
+}
+
+// LoadData reads the entries of a ConfigMap or Secret from files and
+// literals.
+//
+// **Parameters:**
+//
+// files: Files in the same format as DataSpec.Files.
+// literals: Entries given directly, keyed by name.
+//
+// **Returns:**
+//
+// map[string][]byte: The entries, keyed by name.
+// error: An error if a file can't be read, a key is invalid, or a key is
+// given more than once.
+func LoadData(files []string, literals map[string]string) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	add := func(key string, value []byte) error {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if _, ok := data[key]; ok {
+			return fmt.Errorf("key %q is given more than once", key)
+		}
+		data[key] = value
+		return nil
+	}
+
+	for _, source := range files {
+		key, path, hasKey := strings.Cut(source, "=")
+		if !hasKey {
+			path = source
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			if hasKey {
+				return nil, fmt.Errorf("a key can't be given for directory %s", path)
+			}
+			if err := addDir(path, add); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !hasKey {
+			key = filepath.Base(path)
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(key, contents); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range literals {
+		if err := add(key, []byte(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// addDir adds each regular file directly inside dir, keyed by its name.
+func addDir(dir string, add func(key string, value []byte) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := add(entry.Name(), contents); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateOrUpdateConfigMap creates a ConfigMap from files and literals,
+// or replaces the data of an existing one. Entries that aren't valid
+// UTF-8 are stored in BinaryData.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to manage the ConfigMap.
+// spec: The ConfigMap to create or update.
+//
+// **Returns:**
+//
+// *corev1.ConfigMap: The created or updated ConfigMap.
+// error: An error if the data can't be loaded or the ConfigMap can't be
+// created or updated.
+func CreateOrUpdateConfigMap(ctx context.Context, kc *client.KubernetesClient, spec DataSpec) (*corev1.ConfigMap, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+	if spec.Name == "" || spec.Namespace == "" {
+		return nil, errors.New("configmap name and namespace are required")
+	}
+
+	data, err := LoadData(spec.Files, spec.Literals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data of configmap '%s': %v", spec.Name, err)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: spec.Name, Namespace: spec.Namespace, Labels: spec.Labels}}
+	for key, value := range data {
+		if utf8.Valid(value) {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[key] = string(value)
+			continue
+		}
+		if cm.BinaryData == nil {
+			cm.BinaryData = make(map[string][]byte)
+		}
+		cm.BinaryData[key] = value
+	}
+
+	configMaps := kc.Clientset.CoreV1().ConfigMaps(spec.Namespace)
+	created, err := configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	if err == nil {
+		return created, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create configmap '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	existing, err := configMaps.Get(ctx, spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+	existing.Data = cm.Data
+	existing.BinaryData = cm.BinaryData
+	existing.Labels = client.MergeMetadata(existing.Labels, spec.Labels)
+
+	updated, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update configmap '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	return updated, nil
+}
+
+// CreateOrUpdateSecret creates a Secret from files and literals, or
+// replaces the data of an existing one. The type of an existing Secret
+// can't be changed.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to manage the Secret.
+// spec: The Secret to create or update.
+//
+// **Returns:**
+//
+// *corev1.Secret: The created or updated Secret.
+// error: An error if the data can't be loaded or the Secret can't be
+// created or updated.
+func CreateOrUpdateSecret(ctx context.Context, kc *client.KubernetesClient, spec DataSpec) (*corev1.Secret, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+	if spec.Name == "" || spec.Namespace == "" {
+		return nil, errors.New("secret name and namespace are required")
+	}
+
+	data, err := LoadData(spec.Files, spec.Literals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data of secret '%s': %v", spec.Name, err)
+	}
+
+	secretType := spec.Type
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+
+	secrets := kc.Clientset.CoreV1().Secrets(spec.Namespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: spec.Name, Namespace: spec.Namespace, Labels: spec.Labels},
+		Type:       secretType,
+		Data:       data,
+	}
+	created, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if err == nil {
+		return created, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create secret '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	existing, err := secrets.Get(ctx, spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+	if existing.Type != secretType {
+		return nil, fmt.Errorf("secret '%s' in namespace '%s' has type %s, not %s", spec.Name, spec.Namespace, existing.Type, secretType)
+	}
+	existing.Data = data
+	existing.StringData = nil
+	existing.Labels = client.MergeMetadata(existing.Labels, spec.Labels)
+
+	updated, err := secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	return updated, nil
+}
+
+// GetConfigMapData returns the entries of a ConfigMap, including its
+// binary entries.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to read the ConfigMap.
+// name: The name of the ConfigMap.
+// namespace: The namespace of the ConfigMap.
+//
+// **Returns:**
+//
+// map[string][]byte: The entries, keyed by name.
+// error: An error if the ConfigMap can't be read. Errors wrap the error
+// from client-go, so a missing ConfigMap can be detected with
+// apierrors.IsNotFound.
+func GetConfigMapData(ctx context.Context, kc *client.KubernetesClient, name, namespace string) (map[string][]byte, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+
+	cm, err := kc.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for key, value := range cm.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range cm.BinaryData {
+		data[key] = value
+	}
+
+	return data, nil
+}
+
+// GetSecretData returns the decoded entries of a Secret.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to read the Secret.
+// name: The name of the Secret.
+// namespace: The namespace of the Secret.
+//
+// **Returns:**
+//
+// map[string][]byte: The entries, keyed by name.
+// error: An error if the Secret can't be read. Errors wrap the error
+// from client-go, so a missing Secret can be detected with
+// apierrors.IsNotFound.
+func GetSecretData(ctx context.Context, kc *client.KubernetesClient, name, namespace string) (map[string][]byte, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+
+	secret, err := kc.Clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	return secret.Data, nil
+}
@@ -0,0 +1,132 @@
+package k8s_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	core "github.com/l50/goutils/v2/k8s/core"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLoadData(t *testing.T) {
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "app.conf")
+	require.NoError(t, os.WriteFile(conf, []byte("debug=true"), 0644))
+	certs := filepath.Join(dir, "certs")
+	require.NoError(t, os.MkdirAll(filepath.Join(certs, "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(certs, "tls.crt"), []byte("cert"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(certs, "tls.key"), []byte("key"), 0644))
+
+	tests := []struct {
+		name     string
+		files    []string
+		literals map[string]string
+		expected map[string][]byte
+		wantErr  bool
+	}{
+		{
+			name:     "File keyed by base name and literal",
+			files:    []string{conf},
+			literals: map[string]string{"mode": "fast"},
+			expected: map[string][]byte{"app.conf": []byte("debug=true"), "mode": []byte("fast")},
+		},
+		{
+			name:     "File with explicit key",
+			files:    []string{"settings=" + conf},
+			expected: map[string][]byte{"settings": []byte("debug=true")},
+		},
+		{
+			name:     "Directory adds its files",
+			files:    []string{certs},
+			expected: map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		},
+		{
+			name:     "Duplicate key",
+			files:    []string{conf},
+			literals: map[string]string{"app.conf": "x"},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid key",
+			literals: map[string]string{"bad/key": "x"},
+			wantErr:  true,
+		},
+		{
+			name:    "Missing file",
+			files:   []string{filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := core.LoadData(tc.files, tc.literals)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, data)
+		})
+	}
+}
+
+func TestConfigMapsAndSecrets(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "run.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho hi\n"), 0755))
+	blob := filepath.Join(dir, "blob.bin")
+	require.NoError(t, os.WriteFile(blob, []byte{0xff, 0xfe, 0x00}, 0644))
+
+	kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	cm, err := core.CreateOrUpdateConfigMap(ctx, kc, core.DataSpec{
+		Name: "scripts", Namespace: "default", Files: []string{script, blob}, Labels: map[string]string{"app": "demo"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"run.sh": "#!/bin/sh\necho hi\n"}, cm.Data)
+	require.Equal(t, map[string][]byte{"blob.bin": {0xff, 0xfe, 0x00}}, cm.BinaryData)
+
+	cm, err = core.CreateOrUpdateConfigMap(ctx, kc, core.DataSpec{
+		Name: "scripts", Namespace: "default", Literals: map[string]string{"entrypoint": "run.sh"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"entrypoint": "run.sh"}, cm.Data)
+	require.Empty(t, cm.BinaryData)
+	require.Equal(t, map[string]string{"app": "demo"}, cm.Labels)
+
+	data, err := core.GetConfigMapData(ctx, kc, "scripts", "default")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"entrypoint": []byte("run.sh")}, data)
+
+	secret, err := core.CreateOrUpdateSecret(ctx, kc, core.DataSpec{
+		Name: "creds", Namespace: "default", Literals: map[string]string{"password": "s3cret"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, corev1.SecretTypeOpaque, secret.Type)
+
+	_, err = core.CreateOrUpdateSecret(ctx, kc, core.DataSpec{
+		Name: "creds", Namespace: "default", Literals: map[string]string{"password": "rotated"},
+	})
+	require.NoError(t, err)
+	data, err = core.GetSecretData(ctx, kc, "creds", "default")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"password": []byte("rotated")}, data)
+
+	_, err = core.CreateOrUpdateSecret(ctx, kc, core.DataSpec{
+		Name: "creds", Namespace: "default", Type: corev1.SecretTypeBasicAuth, Literals: map[string]string{"username": "admin"},
+	})
+	require.Error(t, err, "changing the type of a secret must fail")
+
+	_, err = core.GetSecretData(ctx, kc, "missing", "default")
+	require.True(t, apierrors.IsNotFound(err))
+	_, err = core.CreateOrUpdateConfigMap(ctx, kc, core.DataSpec{Name: "scripts"})
+	require.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	k8swait "github.com/l50/goutils/v2/k8s/wait"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CreateNamespace creates a namespace, or adds the labels to it if it
+// already exists, so it can be called repeatedly, e.g., at the start of
+// every test run.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to create the namespace.
+// name: The name of the namespace.
+// labels: Optional labels added to the namespace. Existing labels with
+// other keys are kept.
+//
+// **Returns:**
+//
+// *corev1.Namespace: The created or updated namespace.
+// error: An error if the namespace can't be created or updated.
+func CreateNamespace(ctx context.Context, kc *client.KubernetesClient, name string, labels map[string]string) (*corev1.Namespace, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+	if name == "" {
+		return nil, errors.New("namespace name is required")
+	}
+
+	namespaces := kc.Clientset.CoreV1().Namespaces()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	created, err := namespaces.Create(ctx, ns, metav1.CreateOptions{})
+	if err == nil {
+		return created, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create namespace '%s': %w", name, err)
+	}
+
+	existing, err := namespaces.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace '%s': %w", name, err)
+	}
+
+	changed := false
+	for k, v := range labels {
+		if existing.Labels[k] != v {
+			if existing.Labels == nil {
+				existing.Labels = make(map[string]string)
+			}
+			existing.Labels[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return existing, nil
+	}
+
+	updated, err := namespaces.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update labels of namespace '%s': %w", name, err)
+	}
+
+	return updated, nil
+}
+
+// NamespaceExists reports whether a namespace exists. Namespaces that
+// are being deleted still exist until their contents are removed.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to read the namespace.
+// name: The name of the namespace.
+//
+// **Returns:**
+//
+// bool: Whether the namespace exists.
+// error: An error if the namespace can't be read.
+func NamespaceExists(ctx context.Context, kc *client.KubernetesClient, name string) (bool, error) {
+	if kc == nil || kc.Clientset == nil {
+		return false, errors.New("kubernetes client is not initialized")
+	}
+
+	_, err := kc.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace '%s': %w", name, err)
+	}
+
+	return true, nil
+}
+
+// DeleteNamespace deletes a namespace and, in the background, everything
+// in it. A missing namespace isn't an error. Use WaitForNamespaceDeleted
+// to wait until the deletion has finished.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to delete the namespace.
+// name: The name of the namespace.
+//
+// **Returns:**
+//
+// error: An error if the namespace can't be deleted.
+func DeleteNamespace(ctx context.Context, kc *client.KubernetesClient, name string) error {
+	if kc == nil || kc.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+
+	err := kc.Clientset.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete namespace '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// WaitForNamespaceDeleted waits until a namespace no longer exists.
+// Deleting a namespace can take a while, since all of its resources
+// are deleted first.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// kc: The KubernetesClient used to read the namespace.
+// name: The name of the namespace.
+// timeout: The maximum time to wait for the namespace to be deleted.
+// interval: How often the namespace is checked.
+//
+// **Returns:**
+//
+// error: An error if the namespace can't be read or still exists after
+// the timeout.
+func WaitForNamespaceDeleted(ctx context.Context, kc *client.KubernetesClient, name string, timeout, interval time.Duration) error {
+	if kc == nil || kc.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+
+	var phase corev1.NamespacePhase
+	get := func(ctx context.Context) (*corev1.Namespace, error) {
+		ns, err := kc.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			phase = ns.Status.Phase
+		}
+		return ns, err
+	}
+	namespacesGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	err := k8swait.WaitFor(ctx, kc, namespacesGVR, name, "", k8swait.Deleted(),
+		k8swait.WithTypedGetter(get), k8swait.WithTimeout(timeout), k8swait.WithPollInterval(interval))
+	if err != nil {
+		if phase != "" {
+			return fmt.Errorf("namespace '%s' was not deleted (phase %s): %w", name, phase, err)
+		}
+		return fmt.Errorf("namespace '%s' was not deleted: %w", name, err)
+	}
+
+	return nil
+}
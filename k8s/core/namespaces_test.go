@@ -0,0 +1,72 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	core "github.com/l50/goutils/v2/k8s/core"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceLifecycle(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       []runtime.Object
+		labels         map[string]string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "Creates namespace",
+			labels:         map[string]string{"team": "qa"},
+			expectedLabels: map[string]string{"team": "qa"},
+		},
+		{
+			name: "Adds labels to existing namespace",
+			existing: []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name: "e2e", Labels: map[string]string{"owner": "ops", "team": "dev"},
+			}}},
+			labels:         map[string]string{"team": "qa"},
+			expectedLabels: map[string]string{"owner": "ops", "team": "qa"},
+		},
+		{
+			name:     "Existing namespace without labels",
+			existing: []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "e2e"}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset(tc.existing...)}
+			ctx := context.Background()
+
+			ns, err := core.CreateNamespace(ctx, kc, "e2e", tc.labels)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLabels, ns.Labels)
+
+			exists, err := core.NamespaceExists(ctx, kc, "e2e")
+			require.NoError(t, err)
+			require.True(t, exists)
+			require.EqualError(t, core.WaitForNamespaceDeleted(ctx, kc, "e2e", 20*time.Millisecond, 5*time.Millisecond),
+				"namespace 'e2e' was not deleted: namespaces e2e didn't become deleted: context deadline exceeded")
+
+			require.NoError(t, core.DeleteNamespace(ctx, kc, "e2e"))
+			require.NoError(t, core.DeleteNamespace(ctx, kc, "e2e"), "deleting a missing namespace must succeed")
+			require.NoError(t, core.WaitForNamespaceDeleted(ctx, kc, "e2e", time.Second, 5*time.Millisecond))
+
+			exists, err = core.NamespaceExists(ctx, kc, "e2e")
+			require.NoError(t, err)
+			require.False(t, exists)
+		})
+	}
+
+	_, err := core.CreateNamespace(context.Background(), nil, "e2e", nil)
+	require.Error(t, err)
+	_, err = core.CreateNamespace(context.Background(), &client.KubernetesClient{Clientset: fake.NewSimpleClientset()}, "", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,276 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// restartedAtAnnotation is the pod template annotation that
+// `kubectl rollout restart` sets to trigger a new rollout.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RolloutOptions configures RolloutRestart.
+//
+// **Attributes:**
+//
+// Wait: Whether to wait for the rollout to complete before returning.
+// Timeout: The maximum time to wait for the rollout to complete.
+// PollInterval: How often the rollout status is checked while waiting.
+type RolloutOptions struct {
+	Wait         bool
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// RolloutOption is a function that modifies RolloutOptions.
+type RolloutOption func(*RolloutOptions)
+
+// WithRolloutWait makes RolloutRestart wait for the rollout to complete.
+//
+// **Parameters:**
+//
+// timeout: The maximum time to wait for the rollout to complete.
+//
+// **Returns:**
+//
+// RolloutOption: A RolloutOption that enables waiting.
+func WithRolloutWait(timeout time.Duration) RolloutOption {
+	return func(opts *RolloutOptions) {
+		opts.Wait = true
+		opts.Timeout = timeout
+	}
+}
+
+// WithRolloutPollInterval sets how often the rollout status is checked
+// while waiting.
+//
+// **Parameters:**
+//
+// interval: The time between rollout status checks.
+//
+// **Returns:**
+//
+// RolloutOption: A RolloutOption that sets the poll interval.
+func WithRolloutPollInterval(interval time.Duration) RolloutOption {
+	return func(opts *RolloutOptions) {
+		opts.PollInterval = interval
+	}
+}
+
+// RolloutRestart triggers a rolling restart of a deployment, daemonset, or
+// statefulset by patching the restartedAt annotation of its pod template,
+// in the same way as `kubectl rollout restart`. Pods are replaced
+// according to the workload's update strategy, so availability guarantees
+// such as maxUnavailable are respected. This is useful to pick up
+// configuration changes, such as an updated ConfigMap, that don't change
+// the pod template themselves.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to patch the workload.
+// kind: The kind of workload: "deployment", "daemonset", or "statefulset".
+// name: The name of the workload.
+// namespace: The namespace of the workload.
+// opts: Optional RolloutOption values, e.g. WithRolloutWait.
+//
+// **Returns:**
+//
+// error: An error if the workload can't be patched, or if waiting was
+// requested and the rollout doesn't complete in time.
+func RolloutRestart(ctx context.Context, kc *client.KubernetesClient, kind, name, namespace string, opts ...RolloutOption) error {
+	options := RolloutOptions{
+		Timeout:      5 * time.Minute,
+		PollInterval: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if kc == nil || kc.Clientset == nil {
+		return fmt.Errorf("kubernetes client is not initialized")
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339)))
+
+	apps := kc.Clientset.AppsV1()
+	var err error
+	switch normalizeWorkloadKind(kind) {
+	case "deployment":
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "daemonset":
+		_, err = apps.DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "statefulset":
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("unsupported workload kind %q: must be deployment, daemonset, or statefulset", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restart %s '%s' in namespace '%s': %v", kind, name, namespace, err)
+	}
+
+	if !options.Wait {
+		return nil
+	}
+
+	return WaitForRollout(ctx, kc, kind, name, namespace, options.Timeout, options.PollInterval)
+}
+
+// WaitForRollout waits until the latest rollout of a deployment,
+// daemonset, or statefulset has completed, mirroring
+// `kubectl rollout status`.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// kc: The KubernetesClient used to read the workload.
+// kind: The kind of workload: "deployment", "daemonset", or "statefulset".
+// name: The name of the workload.
+// namespace: The namespace of the workload.
+// timeout: The maximum time to wait for the rollout to complete.
+// interval: How often the rollout status is checked.
+//
+// **Returns:**
+//
+// error: An error if the rollout fails, the workload can't be read, or
+// the rollout doesn't complete before the timeout.
+func WaitForRollout(ctx context.Context, kc *client.KubernetesClient, kind, name, namespace string, timeout, interval time.Duration) error {
+	if kc == nil || kc.Clientset == nil {
+		return fmt.Errorf("kubernetes client is not initialized")
+	}
+
+	var status string
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		var done bool
+		var err error
+		done, status, err = rolloutStatus(ctx, kc, kind, name, namespace)
+		return done, err
+	})
+	if err != nil {
+		if status != "" {
+			return fmt.Errorf("rollout of %s '%s' in namespace '%s' did not complete (%s): %v", kind, name, namespace, status, err)
+		}
+		return fmt.Errorf("rollout of %s '%s' in namespace '%s' did not complete: %v", kind, name, namespace, err)
+	}
+
+	return nil
+}
+
+// rolloutStatus reports whether the latest rollout of a workload has
+// completed, along with a description of its progress.
+func rolloutStatus(ctx context.Context, kc *client.KubernetesClient, kind, name, namespace string) (bool, string, error) {
+	apps := kc.Clientset.AppsV1()
+
+	switch normalizeWorkloadKind(kind) {
+	case "deployment":
+		d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", err
+		}
+		return deploymentRolloutStatus(d)
+	case "daemonset":
+		ds, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", err
+		}
+		return daemonSetRolloutStatus(ds)
+	case "statefulset":
+		sts, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", err
+		}
+		return statefulSetRolloutStatus(sts)
+	default:
+		return false, "", fmt.Errorf("unsupported workload kind %q: must be deployment, daemonset, or statefulset", kind)
+	}
+}
+
+func deploymentRolloutStatus(d *appsv1.Deployment) (bool, string, error) {
+	if d.Generation > d.Status.ObservedGeneration {
+		return false, "waiting for deployment spec update to be observed", nil
+	}
+
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			return false, "", fmt.Errorf("deployment %q exceeded its progress deadline", d.Name)
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d updated replicas are available", d.Status.UpdatedReplicas, replicas), nil
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return false, fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas), nil
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return false, fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+func daemonSetRolloutStatus(ds *appsv1.DaemonSet) (bool, string, error) {
+	if ds.Generation > ds.Status.ObservedGeneration {
+		return false, "waiting for daemon set spec update to be observed", nil
+	}
+
+	desired := ds.Status.DesiredNumberScheduled
+	switch {
+	case ds.Status.UpdatedNumberScheduled < desired:
+		return false, fmt.Sprintf("%d of %d updated pods are scheduled", ds.Status.UpdatedNumberScheduled, desired), nil
+	case ds.Status.NumberAvailable < desired:
+		return false, fmt.Sprintf("%d of %d updated pods are available", ds.Status.NumberAvailable, desired), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+func statefulSetRolloutStatus(sts *appsv1.StatefulSet) (bool, string, error) {
+	if sts.Generation > sts.Status.ObservedGeneration {
+		return false, "waiting for statefulset spec update to be observed", nil
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	switch {
+	case sts.Status.ReadyReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d pods are ready", sts.Status.ReadyReplicas, replicas), nil
+	case sts.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d pods are updated", sts.Status.UpdatedReplicas, replicas), nil
+	case sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision:
+		return false, fmt.Sprintf("waiting for pods to move to revision %s", sts.Status.UpdateRevision), nil
+	}
+
+	return true, "successfully rolled out", nil
+}
+
+// normalizeWorkloadKind maps the accepted spellings of a workload kind,
+// such as "Deployment", "deployments", or "deploy", to a canonical name.
+func normalizeWorkloadKind(kind string) string {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		return "deployment"
+	case "daemonset", "daemonsets", "ds":
+		return "daemonset"
+	case "statefulset", "statefulsets", "sts":
+		return "statefulset"
+	default:
+		return ""
+	}
+}
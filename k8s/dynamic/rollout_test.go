@@ -0,0 +1,139 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	dynK8s "github.com/l50/goutils/v2/k8s/dynamic"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newRolloutWorkloads(complete bool) []runtime.Object {
+	replicas := int32(2)
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1}
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta("web"),
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: meta("agent"),
+		Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: meta("db"),
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 2, UpdatedReplicas: 2, CurrentRevision: "db-1", UpdateRevision: "db-1"},
+	}
+
+	if !complete {
+		deployment.Status.AvailableReplicas = 1
+		daemonSet.Status.UpdatedNumberScheduled = 1
+		statefulSet.Status.UpdateRevision = "db-2"
+	}
+
+	return []runtime.Object{deployment, daemonSet, statefulSet}
+}
+
+func restartedAt(t *testing.T, fakeClient *fake.Clientset, kind, name string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	apps := fakeClient.AppsV1()
+	var template corev1.PodTemplateSpec
+	switch kind {
+	case "deployment":
+		d, err := apps.Deployments("default").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		template = d.Spec.Template
+	case "daemonset":
+		ds, err := apps.DaemonSets("default").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		template = ds.Spec.Template
+	case "statefulset":
+		sts, err := apps.StatefulSets("default").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		template = sts.Spec.Template
+	}
+
+	return template.Annotations["kubectl.kubernetes.io/restartedAt"]
+}
+
+func TestRolloutRestart(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		resourceName string
+		complete     bool
+		wait         bool
+		expectError  bool
+	}{
+		{name: "restart deployment", kind: "deployment", resourceName: "web"},
+		{name: "restart daemonset and wait", kind: "DaemonSet", resourceName: "agent", complete: true, wait: true},
+		{name: "restart statefulset and wait", kind: "sts", resourceName: "db", complete: true, wait: true},
+		{name: "wait times out on incomplete rollout", kind: "deployment", resourceName: "web", wait: true, expectError: true},
+		{name: "missing workload", kind: "deployment", resourceName: "missing", expectError: true},
+		{name: "unsupported kind", kind: "pod", resourceName: "web", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(newRolloutWorkloads(tc.complete)...)
+			kc := &client.KubernetesClient{Clientset: fakeClient}
+
+			opts := []dynK8s.RolloutOption{dynK8s.WithRolloutPollInterval(10 * time.Millisecond)}
+			if tc.wait {
+				opts = append(opts, dynK8s.WithRolloutWait(100*time.Millisecond))
+			}
+
+			err := dynK8s.RolloutRestart(context.Background(), kc, tc.kind, tc.resourceName, "default", opts...)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			kind := map[string]string{"deployment": "deployment", "DaemonSet": "daemonset", "sts": "statefulset"}[tc.kind]
+			stamp := restartedAt(t, fakeClient, kind, tc.resourceName)
+			_, err = time.Parse(time.RFC3339, stamp)
+			require.NoError(t, err, "expected restartedAt annotation to be an RFC3339 timestamp, got %q", stamp)
+		})
+	}
+}
+
+func TestWaitForRollout(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		resourceName string
+		complete     bool
+		expectError  bool
+	}{
+		{name: "complete deployment", kind: "deployment", resourceName: "web", complete: true},
+		{name: "incomplete daemonset", kind: "daemonset", resourceName: "agent", expectError: true},
+		{name: "incomplete statefulset", kind: "statefulset", resourceName: "db", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset(newRolloutWorkloads(tc.complete)...)}
+
+			err := dynK8s.WaitForRollout(context.Background(), kc, tc.kind, tc.resourceName, "default", 50*time.Millisecond, 10*time.Millisecond)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
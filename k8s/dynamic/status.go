@@ -8,6 +8,7 @@ import (
 	"time"
 
 	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/l50/goutils/v2/logging"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,7 +31,7 @@ import (
 func GetResourceStatus(ctx context.Context, kc *client.KubernetesClient, resourceName, namespace string, gvr schema.GroupVersionResource) (bool, error) {
 	resource, err := kc.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	if err != nil {
-		return false, fmt.Errorf("failed to get %s (%s) in %s namespace: %v", resourceName, gvr.Resource, namespace, err)
+		return false, fmt.Errorf("failed to get %s (%s) in %s namespace: %w", resourceName, gvr.Resource, namespace, err)
 	}
 
 	status, found, err := unstructured.NestedFieldCopy(resource.UnstructuredContent(), "status")
@@ -164,6 +165,9 @@ func formatResourceDescription(resource *unstructured.Unstructured) string {
 //
 // error: An error if the waiting is cancelled by context, times out, or
 // fails to determine the state.
+//
+// Deprecated: Use WaitFor of the k8s/wait package, which supports
+// custom conditions, timeouts, and backoff.
 func WaitForResourceState(ctx context.Context, resourceName, namespace, resourceType, desiredState string, checkStatusFunc func(name, namespace string) (bool, error)) error {
 	// Set a timeout for reaching the desired state
 	timeout := time.After(5 * time.Minute)
@@ -185,7 +189,7 @@ func WaitForResourceState(ctx context.Context, resourceName, namespace, resource
 			inDesiredState, err := checkStatusFunc(resourceName, namespace)
 			if err != nil {
 				// Log failure in checking status
-				fmt.Printf("failed to get status for %s (%s) in %s namespace: %v\n", resourceName, resourceType, namespace, err)
+				logging.FromContext(ctx).Warnf("failed to get status for %s (%s) in %s namespace: %v", resourceName, resourceType, namespace, err)
 				continue // Continue checking at next tick
 			}
 			if inDesiredState {
@@ -0,0 +1,252 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	manifests "github.com/l50/goutils/v2/k8s/manifests"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CronJobsClient represents a client for managing Kubernetes cron jobs
+// through the Kubernetes API.
+//
+// **Attributes:**
+//
+// Client: A pointer to KubernetesClient for accessing Kubernetes API.
+// Jobs: An optional JobsClient used by TriggerNow to stream the logs of
+// the job it spawns.
+// ResourceMetadata: Optional labels and annotations, such as a run ID,
+// added to every cron job and job created by the client.
+type CronJobsClient struct {
+	Client           *client.KubernetesClient
+	Jobs             *JobsClient
+	ResourceMetadata *manifests.ResourceMetadata
+}
+
+// CreateCronJob creates the input cron job in the specified namespace.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// cronJob: The cron job to create.
+// namespace: Namespace where the cron job should be created.
+//
+// **Returns:**
+//
+// *batchv1.CronJob: The cron job as stored by the API server.
+// error: An error if the cron job could not be created.
+func (cc *CronJobsClient) CreateCronJob(ctx context.Context, cronJob *batchv1.CronJob, namespace string) (*batchv1.CronJob, error) {
+	if cc.Client == nil {
+		return nil, fmt.Errorf("cron jobs client is not initialized")
+	}
+
+	if cc.ResourceMetadata != nil {
+		cronJob = cronJob.DeepCopy()
+		cc.ResourceMetadata.ApplyTo(cronJob)
+		cc.ResourceMetadata.ApplyTo(&cronJob.Spec.JobTemplate)
+		cc.ResourceMetadata.ApplyTo(&cronJob.Spec.JobTemplate.Spec.Template)
+	}
+
+	created, err := cc.Client.Clientset.BatchV1().CronJobs(namespace).Create(ctx, cronJob, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cron job '%s' in namespace '%s': %v", cronJob.Name, namespace, err)
+	}
+
+	return created, nil
+}
+
+// ListCronJobs lists Kubernetes cron jobs from a specified namespace, or
+// all namespaces if no namespace is specified.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// namespace: Optional; specifies the namespace from which to list cron jobs.
+// If empty, cron jobs will be listed from all namespaces.
+//
+// **Returns:**
+//
+// []batchv1.CronJob: A slice of batchv1.CronJob objects containing the cron jobs found.
+// error: An error if the API call to fetch the cron jobs fails.
+func (cc *CronJobsClient) ListCronJobs(ctx context.Context, namespace string) ([]batchv1.CronJob, error) {
+	if cc.Client == nil {
+		return nil, fmt.Errorf("cron jobs client is not initialized")
+	}
+
+	cronJobs, err := cc.Client.Clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cron jobs: %v", err)
+	}
+
+	return cronJobs.Items, nil
+}
+
+// DeleteCronJob deletes a specified Kubernetes cron job within a given
+// namespace. Jobs spawned by the cron job are deleted in the foreground.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// name: Name of the cron job to delete.
+// namespace: Namespace where the cron job is located.
+//
+// **Returns:**
+//
+// error: An error if the cron job could not be deleted.
+func (cc *CronJobsClient) DeleteCronJob(ctx context.Context, name, namespace string) error {
+	if cc.Client == nil {
+		return fmt.Errorf("cron jobs client is not initialized")
+	}
+
+	deletePolicy := metav1.DeletePropagationForeground
+	deleteOptions := metav1.DeleteOptions{
+		PropagationPolicy: &deletePolicy,
+	}
+	if err := cc.Client.Clientset.BatchV1().CronJobs(namespace).Delete(ctx, name, deleteOptions); err != nil {
+		return fmt.Errorf("failed to delete cron job '%s' in namespace '%s': %v", name, namespace, err)
+	}
+
+	return nil
+}
+
+// SuspendCronJob stops a cron job from scheduling new jobs. Jobs that
+// are already running are not affected.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// name: Name of the cron job to suspend.
+// namespace: Namespace where the cron job is located.
+//
+// **Returns:**
+//
+// error: An error if the cron job could not be suspended.
+func (cc *CronJobsClient) SuspendCronJob(ctx context.Context, name, namespace string) error {
+	return cc.setSuspend(ctx, name, namespace, true)
+}
+
+// ResumeCronJob allows a suspended cron job to schedule new jobs again.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// name: Name of the cron job to resume.
+// namespace: Namespace where the cron job is located.
+//
+// **Returns:**
+//
+// error: An error if the cron job could not be resumed.
+func (cc *CronJobsClient) ResumeCronJob(ctx context.Context, name, namespace string) error {
+	return cc.setSuspend(ctx, name, namespace, false)
+}
+
+// TriggerNow runs a cron job immediately by creating a Job from its job
+// template, in the same way as `kubectl create job --from=cronjob/<name>`.
+// If the client has a Jobs client configured, the job is monitored and its
+// logs are streamed with StreamJobLogsContext, for up to 10 minutes,
+// before TriggerNow returns.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request.
+// name: Name of the cron job to trigger.
+// namespace: Namespace where the cron job is located.
+//
+// **Returns:**
+//
+// string: The name of the job that was created.
+// error: An error if the job could not be created or its logs streamed.
+func (cc *CronJobsClient) TriggerNow(ctx context.Context, name, namespace string) (string, error) {
+	if cc.Client == nil {
+		return "", fmt.Errorf("cron jobs client is not initialized")
+	}
+
+	cronJob, err := cc.Client.Clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get cron job '%s' in namespace '%s': %v", name, namespace, err)
+	}
+
+	job := JobFromCronJob(cronJob, time.Now())
+	if cc.ResourceMetadata != nil {
+		cc.ResourceMetadata.ApplyTo(job)
+		cc.ResourceMetadata.ApplyTo(&job.Spec.Template)
+	}
+	created, err := cc.Client.Clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create job from cron job '%s' in namespace '%s': %v", name, namespace, err)
+	}
+
+	if cc.Jobs != nil {
+		streamCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		defer cancel()
+		if err := cc.Jobs.StreamJobLogsContext(streamCtx, created.Name, namespace); err != nil {
+			return created.Name, err
+		}
+	}
+
+	return created.Name, nil
+}
+
+// JobFromCronJob builds a Job from the job template of a cron job. The job
+// is owned by the cron job and annotated as a manual instantiation.
+//
+// **Parameters:**
+//
+// cronJob: The cron job whose template is used.
+// now: The time used to derive a unique job name.
+//
+// **Returns:**
+//
+// *batchv1.Job: The job, ready to be created.
+func JobFromCronJob(cronJob *batchv1.CronJob, now time.Time) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	labels := make(map[string]string, len(cronJob.Spec.JobTemplate.Labels))
+	for k, v := range cronJob.Spec.JobTemplate.Labels {
+		labels[k] = v
+	}
+
+	// Job names are limited to 63 characters so that the job-name label
+	// placed on its pods stays valid.
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	prefix := cronJob.Name
+	if len(prefix)+len(suffix) > 63 {
+		prefix = prefix[:63-len(suffix)]
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        prefix + suffix,
+			Namespace:   cronJob.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// setSuspend patches the suspend field of a cron job.
+func (cc *CronJobsClient) setSuspend(ctx context.Context, name, namespace string, suspend bool) error {
+	if cc.Client == nil {
+		return fmt.Errorf("cron jobs client is not initialized")
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+	_, err := cc.Client.Clientset.BatchV1().CronJobs(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set suspend=%t on cron job '%s' in namespace '%s': %v", suspend, name, namespace, err)
+	}
+
+	return nil
+}
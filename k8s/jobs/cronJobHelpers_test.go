@@ -0,0 +1,213 @@
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	k8s "github.com/l50/goutils/v2/k8s/client"
+	jobs "github.com/l50/goutils/v2/k8s/jobs"
+	manifests "github.com/l50/goutils/v2/k8s/manifests"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestCronJob(name, namespace string) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "cronjob-uid",
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "*/5 * * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": name},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "task", Image: "busybox"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCronJobsClientLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+	cc := &jobs.CronJobsClient{Client: &k8s.KubernetesClient{Clientset: fakeClient}}
+
+	_, err := cc.CreateCronJob(ctx, newTestCronJob("backup", "default"), "default")
+	require.NoError(t, err)
+	_, err = cc.CreateCronJob(ctx, newTestCronJob("report", "ops"), "ops")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		namespace string
+		expected  int
+	}{
+		{name: "list cron jobs from a specific namespace", namespace: "default", expected: 1},
+		{name: "list cron jobs from all namespaces", namespace: "", expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cronJobs, err := cc.ListCronJobs(ctx, tc.namespace)
+			require.NoError(t, err)
+			require.Len(t, cronJobs, tc.expected)
+		})
+	}
+
+	t.Run("suspend and resume", func(t *testing.T) {
+		require.NoError(t, cc.SuspendCronJob(ctx, "backup", "default"))
+		cronJob, err := fakeClient.BatchV1().CronJobs("default").Get(ctx, "backup", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, cronJob.Spec.Suspend)
+		require.True(t, *cronJob.Spec.Suspend)
+
+		require.NoError(t, cc.ResumeCronJob(ctx, "backup", "default"))
+		cronJob, err = fakeClient.BatchV1().CronJobs("default").Get(ctx, "backup", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.False(t, *cronJob.Spec.Suspend)
+	})
+
+	t.Run("suspend missing cron job", func(t *testing.T) {
+		require.Error(t, cc.SuspendCronJob(ctx, "missing", "default"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, cc.DeleteCronJob(ctx, "backup", "default"))
+		require.Error(t, cc.DeleteCronJob(ctx, "backup", "default"))
+	})
+}
+
+func TestListCronJobsError(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "cronjobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("failed to list cron jobs")
+	})
+	cc := &jobs.CronJobsClient{Client: &k8s.KubernetesClient{Clientset: fakeClient}}
+
+	_, err := cc.ListCronJobs(context.Background(), "default")
+	require.Error(t, err)
+
+	uninitialized := &jobs.CronJobsClient{}
+	_, err = uninitialized.ListCronJobs(context.Background(), "default")
+	require.Error(t, err)
+}
+
+func TestTriggerNow(t *testing.T) {
+	tests := []struct {
+		name        string
+		cronJobName string
+		streamLogs  bool
+		expectError bool
+	}{
+		{
+			name:        "trigger existing cron job",
+			cronJobName: "backup",
+		},
+		{
+			name:        "trigger existing cron job and stream logs",
+			cronJobName: "backup",
+			streamLogs:  true,
+		},
+		{
+			name:        "trigger missing cron job",
+			cronJobName: "missing",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeClient := fake.NewSimpleClientset(newTestCronJob("backup", "default"))
+			kubeClient := &k8s.KubernetesClient{Clientset: fakeClient}
+			cc := &jobs.CronJobsClient{Client: kubeClient}
+
+			mockK8sLogger := new(MockK8sLogger)
+			if tc.streamLogs {
+				kubeClient.DynamicClient = jobsDynamicClient("Complete")
+				mockK8sLogger.On("StreamLogsForSelector", mock.Anything, "default", mock.MatchedBy(func(selector string) bool {
+					return strings.HasPrefix(selector, "job-name=backup-manual-")
+				})).Return(nil)
+				cc.Jobs = &jobs.JobsClient{
+					Client:    kubeClient,
+					K8sLogger: mockK8sLogger,
+				}
+			}
+
+			jobName, err := cc.TriggerNow(ctx, tc.cronJobName, "default")
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(jobName, "backup-manual-"), "unexpected job name %s", jobName)
+
+			job, err := fakeClient.BatchV1().Jobs("default").Get(ctx, jobName, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, "manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+			require.Equal(t, "backup", job.Labels["app"])
+			require.Len(t, job.OwnerReferences, 1)
+			require.Equal(t, "CronJob", job.OwnerReferences[0].Kind)
+
+			mockK8sLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCronJobsClientResourceMetadata(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+	cc := &jobs.CronJobsClient{
+		Client:           &k8s.KubernetesClient{Clientset: fakeClient},
+		ResourceMetadata: &manifests.ResourceMetadata{RunID: "run-7", Owner: "ci"},
+	}
+
+	input := newTestCronJob("backup", "default")
+	cronJob, err := cc.CreateCronJob(ctx, input, "default")
+	require.NoError(t, err)
+	require.Nil(t, input.Labels, "the input cron job should not be modified")
+	require.Equal(t, "run-7", cronJob.Labels[manifests.LabelRunID])
+	require.Equal(t, "run-7", cronJob.Spec.JobTemplate.Labels[manifests.LabelRunID])
+	require.Equal(t, "backup", cronJob.Spec.JobTemplate.Labels["app"])
+	require.Equal(t, "run-7", cronJob.Spec.JobTemplate.Spec.Template.Labels[manifests.LabelRunID])
+
+	jobName, err := cc.TriggerNow(ctx, "backup", "default")
+	require.NoError(t, err)
+	job, err := fakeClient.BatchV1().Jobs("default").Get(ctx, jobName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"app":                    "backup",
+		manifests.LabelManagedBy: "goutils",
+		manifests.LabelRunID:     "run-7",
+		manifests.LabelOwner:     "ci",
+	}, job.Labels)
+	require.Equal(t, "run-7", job.Spec.Template.Labels[manifests.LabelRunID])
+}
+
+func TestJobFromCronJobNameLength(t *testing.T) {
+	cronJob := newTestCronJob(strings.Repeat("a", 70), "default")
+	job := jobs.JobFromCronJob(cronJob, time.Unix(1700000000, 0))
+
+	require.LessOrEqual(t, len(job.Name), 63)
+	require.True(t, strings.HasSuffix(job.Name, "-manual-1700000000"))
+	require.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+}
@@ -7,7 +7,10 @@ import (
 
 	client "github.com/l50/goutils/v2/k8s/client"
 	dynK8s "github.com/l50/goutils/v2/k8s/dynamic"
+	k8sLoggers "github.com/l50/goutils/v2/k8s/loggers"
 	manifests "github.com/l50/goutils/v2/k8s/manifests"
+	k8swait "github.com/l50/goutils/v2/k8s/wait"
+	"github.com/l50/goutils/v2/logging"
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,66 +18,16 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-// DynK8sInterface defines the methods used from dynK8s
-// to manage Kubernetes resources.
-//
-// **Methods:**
-//
-// WaitForResourceState: Waits for a Kubernetes resource to reach a desired state.
-// GetResourceStatus: Retrieves the status of a Kubernetes resource.
-type DynK8sInterface interface {
-	WaitForResourceState(ctx context.Context, resourceName, namespace, resourceType, desiredState string, checkStatusFunc func(name, namespace string) (bool, error)) error
-	GetResourceStatus(ctx context.Context, kc *client.KubernetesClient, resourceName, namespace string, gvr schema.GroupVersionResource) (bool, error)
-}
-
 // K8sLoggerInterface defines the methods used from k8sLogger
 // to stream logs from Kubernetes pods.
 //
 // **Methods:**
 //
-// StreamLogs: Streams logs from a Kubernetes pod.
+// StreamLogsForSelector: Streams the logs of every pod matching a label
+// selector, configured by optional LogOption values such as
+// WithTailLines.
 type K8sLoggerInterface interface {
-	StreamLogs(clientset kubernetes.Interface, namespace, resourceType, podName string) error
-}
-
-// JobPodNameGetter defines the method to get job pod name
-// by job name and namespace.
-//
-// **Methods:**
-//
-// GetJobPodName: Retrieves the name of the first pod associated with a specific
-type JobPodNameGetter interface {
-	GetJobPodName(ctx context.Context, jobName, namespace string) (string, error)
-}
-
-// DefaultJobPodNameGetter implements the default behavior for getting job pod
-// names by using the JobsClient to fetch the pod name.
-//
-// **Attributes:**
-//
-// JC: A JobsClient for managing Kubernetes jobs.
-type DefaultJobPodNameGetter struct {
-	JC *JobsClient
-}
-
-// GetJobPodName retrieves the name of the first pod associated with a specific
-// Kubernetes job within a given namespace. It uses a label selector to find
-// pods that are labeled with the job's name. This method is typically used in
-// scenarios where jobs create a single pod or when only the first pod
-// is of interest.
-//
-// **Parameters:**
-//
-// ctx: Context for managing control flow of the request.
-// jobName: Name of the Kubernetes job to find pods for.
-// namespace: Namespace where the job and its pods are located.
-//
-// **Returns:**
-//
-// string: The name of the first pod found that is associated with the job
-// error: An error if no pods are found or if an error occurs during the pod retrieval
-func (d *DefaultJobPodNameGetter) GetJobPodName(ctx context.Context, jobName, namespace string) (string, error) {
-	return d.JC.GetJobPodName(ctx, jobName, namespace)
+	StreamLogsForSelector(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, opts ...k8sLoggers.LogOption) error
 }
 
 // JobsClient represents a client for managing Kubernetes jobs
@@ -83,16 +36,15 @@ func (d *DefaultJobPodNameGetter) GetJobPodName(ctx context.Context, jobName, na
 // **Attributes:**
 //
 // Client: A pointer to KubernetesClient for accessing Kubernetes API.
-// DynK8s: A DynK8sInterface for managing Kubernetes resources.
 // K8sLogger: A K8sLoggerInterface for streaming logs from Kubernetes pods.
 // StreamLogsFn: A function for streaming logs from a Kubernetes pod.
-// PodNameGetter: A JobPodNameGetter for getting job pod names.
+// ResourceMetadata: Optional labels and annotations, such as a run ID,
+// added to every resource created by the client.
 type JobsClient struct {
-	Client        *client.KubernetesClient
-	DynK8s        DynK8sInterface
-	K8sLogger     K8sLoggerInterface
-	StreamLogsFn  func(clientset *kubernetes.Clientset, namespace, resourceType, resourceName string) error
-	PodNameGetter JobPodNameGetter
+	Client           *client.KubernetesClient
+	K8sLogger        K8sLoggerInterface
+	StreamLogsFn     func(clientset *kubernetes.Clientset, namespace, resourceType, resourceName string) error
+	ResourceMetadata *manifests.ResourceMetadata
 }
 
 // ApplyKubernetesJob applies a Kubernetes job manifest to a Kubernetes cluster
@@ -122,6 +74,7 @@ func (jc *JobsClient) ApplyKubernetesJob(jobFilePath, namespace string, readFile
 	manifestConfig.Operation = manifests.OperationApply
 	manifestConfig.Client = jc.Client.DynamicClient
 	manifestConfig.ReadFile = readFile
+	manifestConfig.ResourceMetadata = jc.ResourceMetadata
 
 	if err := manifestConfig.ApplyOrDeleteManifest(context.Background()); err != nil {
 		return fmt.Errorf("failed to apply job: %v", err)
@@ -179,9 +132,8 @@ func (jc *JobsClient) GetJobPodName(ctx context.Context, jobName, namespace stri
 	if jc.Client == nil {
 		return "", fmt.Errorf("jobs client is not initialized")
 	}
-	labelSelector := "job-name=" + jobName
 	pods, err := jc.Client.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
+		LabelSelector: jobPodSelector(jobName),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get pods for job '%s' in namespace '%s': %v", jobName, namespace, err)
@@ -254,11 +206,12 @@ func (jc *JobsClient) JobExists(ctx context.Context, jobName, namespace string)
 }
 
 // StreamJobLogs monitors a Kubernetes job by waiting for it to reach
-// the 'Ready' state and then streams logs from the associated pod.
+// the 'Ready' state and then streams the logs of all of its pods, such
+// as those of retried or parallel runs. It gives up after 10 minutes;
+// use StreamJobLogsContext to control the deadline and the logger.
 //
 // **Parameters:**
 //
-// jobsClient: A JobsClient for managing Kubernetes jobs.
 // workloadName: Name of the Kubernetes job to monitor.
 // namespace: Namespace where the job is located.
 //
@@ -269,39 +222,65 @@ func (jc *JobsClient) StreamJobLogs(workloadName, namespace string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	fmt.Printf("Monitoring %s job in %s namespace\n", workloadName, namespace)
-
-	// Wait for the job to reach completion
-	err := jc.DynK8s.WaitForResourceState(ctx, workloadName, namespace, "job", "Complete", func(name, ns string) (bool, error) {
-		jobComplete, err := jc.DynK8s.GetResourceStatus(ctx, jc.Client, name, ns, schema.GroupVersionResource{
-			Group:    "batch",
-			Version:  "v1",
-			Resource: "jobs",
-		})
-		if err != nil {
-			return false, fmt.Errorf("error checking status for %s job in %s namespace: %v", name, ns, err)
-		}
-		return jobComplete, nil
-	})
+	return jc.StreamJobLogsContext(ctx, workloadName, namespace)
+}
 
-	if err != nil {
-		if diagErr := logJobDiagnosticInfo(jc.Client, workloadName, namespace); diagErr != nil {
-			fmt.Printf("failed to log diagnostic info for %s job: %v", workloadName, diagErr)
-		}
-		return fmt.Errorf("error waiting for %s job to complete in %s namespace: %v", workloadName, namespace, err)
+// StreamJobLogsContext is like StreamJobLogs, but waits for the job and
+// streams its logs with the input context, so that a logger attached
+// with logging.IntoContext receives the progress and diagnostic messages.
+//
+// **Parameters:**
+//
+// ctx: Context for managing control flow of the request. Canceling it
+// stops waiting and streaming.
+// workloadName: Name of the Kubernetes job to monitor.
+// namespace: Namespace where the job is located.
+//
+// **Returns:**
+//
+// error: An error if the job monitoring fails.
+func (jc *JobsClient) StreamJobLogsContext(ctx context.Context, workloadName, namespace string) error {
+	if err := jc.waitForJob(ctx, workloadName, namespace); err != nil {
+		return err
 	}
 
-	// Attempt to fetch the pod name after confirming the job has completed
-	podName, err := jc.PodNameGetter.GetJobPodName(ctx, workloadName, namespace)
+	// Stream the logs of every pod of the job, prefixed with the pod
+	// names. Credentials that expired while waiting for the job are
+	// refreshed once.
+	err := jc.Client.RetryOnUnauthorized(ctx, func(ctx context.Context) error {
+		return jc.K8sLogger.StreamLogsForSelector(ctx, jc.Client.Clientset, namespace, jobPodSelector(workloadName))
+	})
 	if err != nil {
-		return fmt.Errorf("failed to find pod associated with %s workload: %v", workloadName, err)
+		return fmt.Errorf("failed to stream logs for %s job: %v", workloadName, err)
 	}
 
-	fmt.Printf("%s pod for %s job in %s namespace is ready and being monitored\n", podName, workloadName, namespace)
+	return nil
+}
+
+// jobPodSelector returns the label selector of the pods of a job, which
+// the job controller labels with the name of the job.
+func jobPodSelector(jobName string) string {
+	return "job-name=" + jobName
+}
+
+// waitForJob waits for a Kubernetes job to complete. Diagnostic
+// information is logged if the job doesn't complete.
+func (jc *JobsClient) waitForJob(ctx context.Context, workloadName, namespace string) error {
+	logger := logging.FromContext(ctx)
+	logger.Printf("Monitoring %s job in %s namespace", workloadName, namespace)
+
+	// Wait for the job to reach completion. Credentials that expire
+	// while waiting are refreshed once.
+	jobsGVR := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	err := jc.Client.RetryOnUnauthorized(ctx, func(ctx context.Context) error {
+		return k8swait.WaitFor(ctx, jc.Client, jobsGVR, workloadName, namespace, k8swait.JobComplete())
+	})
 
-	// Stream logs from the pod, ensuring it exists
-	if err := jc.K8sLogger.StreamLogs(jc.Client.Clientset, namespace, "pod", podName); err != nil {
-		return fmt.Errorf("failed to stream logs for pod '%s': %v", podName, err)
+	if err != nil {
+		if diagErr := logJobDiagnosticInfo(ctx, logger, jc.Client, workloadName, namespace); diagErr != nil {
+			logger.Warnf("failed to log diagnostic info for %s job: %v", workloadName, diagErr)
+		}
+		return fmt.Errorf("error waiting for %s job to complete in %s namespace: %v", workloadName, namespace, err)
 	}
 
 	return nil
@@ -311,6 +290,9 @@ func (jc *JobsClient) StreamJobLogs(workloadName, namespace string) error {
 //
 // **Parameters:**
 //
+// ctx: The context of the failed wait. Its values are kept, but the
+// diagnostics are collected even if it's done.
+// logger: The Logger the diagnostic information is written to.
 // k8sClient: A KubernetesClient for accessing the Kubernetes API.
 // jobName: Name of the Kubernetes job to log diagnostic information for.
 // namespace: Namespace where the job is located.
@@ -318,8 +300,8 @@ func (jc *JobsClient) StreamJobLogs(workloadName, namespace string) error {
 // **Returns:**
 //
 // error: An error if the diagnostic information could not be logged.
-func logJobDiagnosticInfo(k8sClient *client.KubernetesClient, jobName, namespace string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+func logJobDiagnosticInfo(ctx context.Context, logger logging.Logger, k8sClient *client.KubernetesClient, jobName, namespace string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Minute)
 	defer cancel()
 
 	jobGVR := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
@@ -328,7 +310,7 @@ func logJobDiagnosticInfo(k8sClient *client.KubernetesClient, jobName, namespace
 		return fmt.Errorf("error describing job: %v", err)
 	}
 
-	fmt.Printf("Describe job output for '%s':\n%s", jobName, jobDescription)
+	logger.Printf("Describe job output for '%s':\n%s", jobName, jobDescription)
 
 	podsGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
 	podsDescription, err := dynK8s.DescribeKubernetesResource(ctx, k8sClient, jobName, namespace, podsGVR)
@@ -336,6 +318,6 @@ func logJobDiagnosticInfo(k8sClient *client.KubernetesClient, jobName, namespace
 		return fmt.Errorf("error describing pods for job: %v", err)
 	}
 
-	fmt.Printf("Describe pods output for job '%s':\n%s", jobName, podsDescription)
+	logger.Printf("Describe pods output for job '%s':\n%s", jobName, podsDescription)
 	return nil
 }
@@ -0,0 +1,294 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxLogLineSize is the longest log line ParseLogs can read. JSON-lines
+// output from scanners often exceeds bufio's 64 KiB default.
+const maxLogLineSize = 1024 * 1024
+
+// LineParser parses a single log line into a typed result.
+//
+// **Parameters:**
+//
+// line: The log line, without the trailing newline.
+//
+// **Returns:**
+//
+// T: The parsed result.
+// bool: False if the line holds no result and should be skipped.
+// error: An error if the line looks like a result but can't be parsed.
+type LineParser[T any] func(line string) (T, bool, error)
+
+// LineParseError records a log line that a LineParser failed to parse.
+//
+// **Attributes:**
+//
+// PodName: The pod whose logs held the line. Empty for ParseLogs.
+// Line: The 1-based number of the line in the log of its pod.
+// Text: The content of the line.
+// Err: The error returned by the LineParser.
+type LineParseError struct {
+	PodName string
+	Line    int
+	Text    string
+	Err     error
+}
+
+// Error returns the pod, the line number, and the parser error.
+//
+// **Returns:**
+//
+// string: The error message.
+func (e LineParseError) Error() string {
+	if e.PodName != "" {
+		return fmt.Sprintf("pod %s line %d: %v", e.PodName, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the error returned by the LineParser.
+//
+// **Returns:**
+//
+// error: The parser error.
+func (e LineParseError) Unwrap() error {
+	return e.Err
+}
+
+// JobLogSummary summarizes the log lines processed by ParseLogs, or by
+// ParseJobLogs across all pods of a job.
+//
+// **Attributes:**
+//
+// TotalLines: The number of lines read.
+// ParsedLines: The number of lines that produced a result.
+// SkippedLines: The number of lines the parser skipped.
+// Errors: The lines the parser failed to parse.
+type JobLogSummary struct {
+	TotalLines   int
+	ParsedLines  int
+	SkippedLines int
+	Errors       []LineParseError
+}
+
+// JobLogEntry is a result parsed from a log line.
+//
+// **Attributes:**
+//
+// PodName: The pod whose logs held the line. Empty for ParseLogs.
+// Value: The parsed result.
+type JobLogEntry[T any] struct {
+	PodName string
+	Value   T
+}
+
+// JobLogResults holds the typed results extracted from a job's logs.
+//
+// **Attributes:**
+//
+// Pods: The pods the logs were read from, oldest first. Empty for
+// ParseLogs.
+// Results: The parsed results, in log order per pod.
+// Summary: Counts of the processed lines and the parse errors.
+type JobLogResults[T any] struct {
+	Pods    []string
+	Results []JobLogEntry[T]
+	Summary JobLogSummary
+}
+
+// ParseJobLogs waits for a Kubernetes job to complete, like
+// StreamJobLogs, and then parses the logs of every pod of the job line by
+// line instead of printing them, so the output of retried or parallel
+// pods isn't lost. Lines that fail to parse are recorded in the summary
+// rather than aborting, so a single malformed line doesn't discard the
+// other results.
+//
+// **Parameters:**
+//
+// jc: A JobsClient for managing Kubernetes jobs.
+// workloadName: Name of the Kubernetes job to monitor.
+// namespace: Namespace where the job is located.
+// parse: The LineParser applied to each log line, such as
+// JSONLinesParser or RegexLineParser.
+//
+// **Returns:**
+//
+// *JobLogResults[T]: The parsed results of all pods and a summary of
+// their logs.
+// error: An error if the job doesn't complete, has no pods, or the logs
+// of a pod can't be read.
+func ParseJobLogs[T any](jc *JobsClient, workloadName, namespace string, parse LineParser[T]) (*JobLogResults[T], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if err := jc.waitForJob(ctx, workloadName, namespace); err != nil {
+		return nil, err
+	}
+
+	// Credentials that expired while waiting for the job are refreshed
+	// once.
+	var results *JobLogResults[T]
+	err := jc.Client.RetryOnUnauthorized(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = parseJobPodLogs(ctx, jc, workloadName, namespace, parse)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// parseJobPodLogs parses the logs of the pods of a job, oldest first.
+func parseJobPodLogs[T any](ctx context.Context, jc *JobsClient, workloadName, namespace string, parse LineParser[T]) (*JobLogResults[T], error) {
+	pods, err := jc.Client.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: jobPodSelector(workloadName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods for job '%s' in namespace '%s': %w", workloadName, namespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no pod found for job '%s'", workloadName)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		a, b := pods.Items[i], pods.Items[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+
+	results := &JobLogResults[T]{Results: []JobLogEntry[T]{}}
+	for _, pod := range pods.Items {
+		logStream, err := jc.Client.Clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open logs for pod '%s': %w", pod.Name, err)
+		}
+		err = parseLogsInto(results, logStream, pod.Name, parse)
+		logStream.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read logs for pod '%s': %v", pod.Name, err)
+		}
+		results.Pods = append(results.Pods, pod.Name)
+	}
+
+	return results, nil
+}
+
+// ParseLogs applies a LineParser to each line read from r, for example
+// logs saved from an earlier job run.
+//
+// **Parameters:**
+//
+// r: The reader to read the log lines from.
+// parse: The LineParser applied to each line.
+//
+// **Returns:**
+//
+// *JobLogResults[T]: The parsed results and a summary of the log.
+// error: An error if r can't be read.
+func ParseLogs[T any](r io.Reader, parse LineParser[T]) (*JobLogResults[T], error) {
+	results := &JobLogResults[T]{Results: []JobLogEntry[T]{}}
+	if err := parseLogsInto(results, r, "", parse); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// parseLogsInto applies a LineParser to each line of the log of a pod
+// read from r, adding the results and counts to results.
+func parseLogsInto[T any](results *JobLogResults[T], r io.Reader, podName string, parse LineParser[T]) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		results.Summary.TotalLines++
+
+		result, ok, err := parse(line)
+		switch {
+		case err != nil:
+			results.Summary.Errors = append(results.Summary.Errors, LineParseError{
+				PodName: podName,
+				Line:    lineNumber,
+				Text:    line,
+				Err:     err,
+			})
+		case !ok:
+			results.Summary.SkippedLines++
+		default:
+			results.Summary.ParsedLines++
+			results.Results = append(results.Results, JobLogEntry[T]{PodName: podName, Value: result})
+		}
+	}
+
+	return scanner.Err()
+}
+
+// JSONLinesParser returns a LineParser that decodes JSON-lines output into
+// values of type T. Lines that don't start with "{" are skipped, so plain
+// log messages mixed into the output are ignored.
+//
+// **Returns:**
+//
+// LineParser[T]: A parser that unmarshals each JSON object line into T.
+func JSONLinesParser[T any]() LineParser[T] {
+	return func(line string) (T, bool, error) {
+		var result T
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "{") {
+			return result, false, nil
+		}
+		if err := json.Unmarshal([]byte(trimmed), &result); err != nil {
+			return result, false, fmt.Errorf("failed to decode JSON: %v", err)
+		}
+		return result, true, nil
+	}
+}
+
+// RegexLineParser returns a LineParser that extracts the capture groups of
+// re from each matching line. Lines that don't match are skipped.
+//
+// **Parameters:**
+//
+// re: The regular expression to match each line against.
+//
+// **Returns:**
+//
+// LineParser[map[string]string]: A parser that maps each named capture
+// group to its match. Unnamed groups are keyed by their index, such as
+// "1".
+func RegexLineParser(re *regexp.Regexp) LineParser[map[string]string] {
+	names := re.SubexpNames()
+	return func(line string) (map[string]string, bool, error) {
+		match := re.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false, nil
+		}
+
+		result := make(map[string]string, len(match)-1)
+		for i := 1; i < len(match); i++ {
+			key := names[i]
+			if key == "" {
+				key = strconv.Itoa(i)
+			}
+			result[key] = match[i]
+		}
+		return result, true, nil
+	}
+}
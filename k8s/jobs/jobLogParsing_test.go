@@ -0,0 +1,145 @@
+package k8s_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	k8s "github.com/l50/goutils/v2/k8s/client"
+	jobs "github.com/l50/goutils/v2/k8s/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type finding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+}
+
+func TestParseLogs(t *testing.T) {
+	logs := strings.Join([]string{
+		"starting scan",
+		`{"id": "CVE-1", "severity": "high"}`,
+		`{"id": "CVE-2", "severity": "low"}`,
+		`{"id": broken}`,
+		"scan complete",
+	}, "\n")
+
+	t.Run("JSON lines", func(t *testing.T) {
+		results, err := jobs.ParseLogs(strings.NewReader(logs), jobs.JSONLinesParser[finding]())
+		require.NoError(t, err)
+
+		assert.Equal(t, []jobs.JobLogEntry[finding]{
+			{Value: finding{ID: "CVE-1", Severity: "high"}},
+			{Value: finding{ID: "CVE-2", Severity: "low"}},
+		}, results.Results)
+		assert.Equal(t, 5, results.Summary.TotalLines)
+		assert.Equal(t, 2, results.Summary.ParsedLines)
+		assert.Equal(t, 2, results.Summary.SkippedLines)
+		require.Len(t, results.Summary.Errors, 1)
+		assert.Equal(t, 4, results.Summary.Errors[0].Line)
+		assert.Equal(t, `{"id": broken}`, results.Summary.Errors[0].Text)
+	})
+
+	t.Run("Regex", func(t *testing.T) {
+		re := regexp.MustCompile(`"id": "(?P<id>[^"]+)", "severity": "(high|low)"`)
+		results, err := jobs.ParseLogs(strings.NewReader(logs), jobs.RegexLineParser(re))
+		require.NoError(t, err)
+
+		assert.Equal(t, []jobs.JobLogEntry[map[string]string]{
+			{Value: map[string]string{"id": "CVE-1", "2": "high"}},
+			{Value: map[string]string{"id": "CVE-2", "2": "low"}},
+		}, results.Results)
+		assert.Equal(t, 3, results.Summary.SkippedLines)
+		assert.Empty(t, results.Summary.Errors)
+	})
+
+	t.Run("Long line", func(t *testing.T) {
+		line := `{"id": "` + strings.Repeat("a", 100*1024) + `"}`
+		results, err := jobs.ParseLogs(strings.NewReader(line), jobs.JSONLinesParser[finding]())
+		require.NoError(t, err)
+		require.Len(t, results.Results, 1)
+		assert.Len(t, results.Results[0].Value.ID, 100*1024)
+	})
+}
+
+func jobPod(name, jobName string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         "default",
+		Labels:            map[string]string{"job-name": jobName},
+		CreationTimestamp: metav1.NewTime(created),
+	}}
+}
+
+func TestParseJobLogs(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		pods          []runtime.Object
+		expectedPods  []string
+		expectedError string
+	}{
+		{
+			name:         "Single pod",
+			pods:         []runtime.Object{jobPod("scan-job-a", "scan-job", created)},
+			expectedPods: []string{"scan-job-a"},
+		},
+		{
+			name: "Retried pods oldest first",
+			pods: []runtime.Object{
+				jobPod("scan-job-a", "scan-job", created.Add(time.Minute)),
+				jobPod("scan-job-b", "scan-job", created),
+				jobPod("other-job-a", "other-job", created),
+			},
+			expectedPods: []string{"scan-job-b", "scan-job-a"},
+		},
+		{
+			name:          "No pods",
+			pods:          []runtime.Object{jobPod("other-job-a", "other-job", created)},
+			expectedError: "no pod found for job 'scan-job'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jc := &jobs.JobsClient{
+				Client: &k8s.KubernetesClient{
+					Clientset:     fake.NewSimpleClientset(tc.pods...),
+					DynamicClient: jobsDynamicClient("Complete"),
+				},
+			}
+
+			// The fake clientset returns "fake logs" for every pod.
+			results, err := jobs.ParseJobLogs(jc, "scan-job", "default", jobs.RegexLineParser(regexp.MustCompile(`^(?P<word>\w+) logs$`)))
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			var expected []jobs.JobLogEntry[map[string]string]
+			for _, pod := range tc.expectedPods {
+				expected = append(expected, jobs.JobLogEntry[map[string]string]{PodName: pod, Value: map[string]string{"word": "fake"}})
+			}
+			assert.Equal(t, tc.expectedPods, results.Pods)
+			assert.Equal(t, expected, results.Results)
+			assert.Equal(t, len(tc.expectedPods), results.Summary.ParsedLines)
+		})
+	}
+}
+
+func TestLineParseError(t *testing.T) {
+	err := jobs.LineParseError{PodName: "scan-job-a", Line: 3, Err: errors.New("bad JSON")}
+	assert.EqualError(t, err, "pod scan-job-a line 3: bad JSON")
+
+	err.PodName = ""
+	assert.EqualError(t, err, "line 3: bad JSON")
+}
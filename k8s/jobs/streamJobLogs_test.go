@@ -7,16 +7,18 @@ import (
 
 	k8s "github.com/l50/goutils/v2/k8s/client"
 	jobs "github.com/l50/goutils/v2/k8s/jobs"
+	k8sLoggers "github.com/l50/goutils/v2/k8s/loggers"
+	"github.com/l50/goutils/v2/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 )
 
-type MockDynK8s struct {
-	mock.Mock
-}
-
 type MockK8sLogger struct {
 	mock.Mock
 }
@@ -26,98 +28,144 @@ type MockKubernetesClient struct {
 	kubernetes.Interface
 }
 
-type MockJobPodNameGetter struct {
-	mock.Mock
-}
-
-func (m *MockDynK8s) WaitForResourceState(ctx context.Context, workloadName, namespace, resourceType, state string, checkFunc func(string, string) (bool, error)) error {
-	args := m.Called(ctx, workloadName, namespace, resourceType, state, checkFunc)
-	if checkFunc != nil {
-		fmt.Printf("Calling checkFunc for workloadName: %s, namespace: %s\n", workloadName, namespace)
-		_, err := checkFunc(workloadName, namespace)
-		if err != nil {
-			return err
-		}
-	}
-	return args.Error(0)
-}
-
-func (m *MockDynK8s) GetResourceStatus(ctx context.Context, client *k8s.KubernetesClient, name, ns string, gvr schema.GroupVersionResource) (bool, error) {
-	args := m.Called(ctx, client, name, ns, gvr)
-	fmt.Printf("Mock GetResourceStatus called for name: %s, namespace: %s\n", name, ns)
-	return args.Bool(0), args.Error(1)
-}
-
-func (m *MockK8sLogger) StreamLogs(clientset kubernetes.Interface, namespace, resourceType, podName string) error {
-	args := m.Called(clientset, namespace, resourceType, podName)
-	fmt.Printf("Mock StreamLogs called for podName: %s, namespace: %s\n", podName, namespace)
+func (m *MockK8sLogger) StreamLogsForSelector(_ context.Context, clientset kubernetes.Interface, namespace, labelSelector string, _ ...k8sLoggers.LogOption) error {
+	args := m.Called(clientset, namespace, labelSelector)
+	fmt.Printf("Mock StreamLogsForSelector called for selector: %s, namespace: %s\n", labelSelector, namespace)
 	return args.Error(0)
 }
 
-func (m *MockJobPodNameGetter) GetJobPodName(ctx context.Context, workloadName, namespace string) (string, error) {
-	args := m.Called(ctx, workloadName, namespace)
-	fmt.Printf("Mock GetJobPodName called for workloadName: %s, namespace: %s\n", workloadName, namespace)
-	return args.String(0), args.Error(1)
+// jobsDynamicClient returns a dynamic client reporting every job with
+// a True condition of the input type, e.g., "Complete".
+func jobsDynamicClient(condition string) *dynamicfake.FakeDynamicClient {
+	dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dc.PrependReactor("get", "jobs", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata": map[string]interface{}{
+				"name":      action.(clienttesting.GetAction).GetName(),
+				"namespace": action.GetNamespace(),
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": condition, "status": "True", "reason": "BackoffLimitExceeded"},
+				},
+			},
+		}}, nil
+	})
+	return dc
 }
 
 func TestStreamJobLogs(t *testing.T) {
-	mockDynK8s := new(MockDynK8s)
-	mockK8sLogger := new(MockK8sLogger)
-	mockClientset := new(MockKubernetesClient)
-	mockJobPodNameGetter := new(MockJobPodNameGetter)
-
 	tests := []struct {
-		name                   string
-		workloadName           string
-		namespace              string
-		waitForResourceError   error
-		getResourceStatus      bool
-		getResourceStatusError error
-		streamLogsError        error
-		getJobPodName          string
-		getJobPodNameError     error
-		expectedError          bool
+		name          string
+		workloadName  string
+		namespace     string
+		jobCondition  string
+		expectStream  bool
+		expectedError string
 	}{
 		{
-			name:                   "successful log streaming",
-			workloadName:           "test-job",
-			namespace:              "test-namespace",
-			waitForResourceError:   nil,
-			getResourceStatus:      true,
-			getResourceStatusError: nil,
-			streamLogsError:        nil,
-			getJobPodName:          "test-pod",
-			getJobPodNameError:     nil,
-			expectedError:          false,
+			name:         "successful log streaming",
+			workloadName: "test-job",
+			namespace:    "test-namespace",
+			jobCondition: "Complete",
+			expectStream: true,
+		},
+		{
+			name:          "failed job",
+			workloadName:  "test-job",
+			namespace:     "test-namespace",
+			jobCondition:  "Failed",
+			expectedError: "error waiting for test-job job to complete in test-namespace namespace: jobs test-namespace/test-job can't become complete: job failed: BackoffLimitExceeded",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Setup mock expectations
-			mockDynK8s.On("WaitForResourceState", mock.Anything, tc.workloadName, tc.namespace, "job", "Complete", mock.Anything).Return(tc.waitForResourceError)
-			mockDynK8s.On("GetResourceStatus", mock.Anything, mock.Anything, tc.workloadName, tc.namespace, mock.Anything).Return(tc.getResourceStatus, tc.getResourceStatusError)
-			mockK8sLogger.On("StreamLogs", mock.Anything, tc.namespace, "pod", tc.getJobPodName).Return(tc.streamLogsError)
-			mockJobPodNameGetter.On("GetJobPodName", mock.Anything, tc.workloadName, tc.namespace).Return(tc.getJobPodName, tc.getJobPodNameError)
+			mockK8sLogger := new(MockK8sLogger)
+			if tc.expectStream {
+				mockK8sLogger.On("StreamLogsForSelector", mock.Anything, tc.namespace, "job-name="+tc.workloadName).Return(nil)
+			}
 
 			jc := &jobs.JobsClient{
-				Client:        &k8s.KubernetesClient{Clientset: mockClientset},
-				DynK8s:        mockDynK8s,
-				K8sLogger:     mockK8sLogger,
-				PodNameGetter: mockJobPodNameGetter,
+				Client: &k8s.KubernetesClient{
+					Clientset:     fake.NewSimpleClientset(),
+					DynamicClient: jobsDynamicClient(tc.jobCondition),
+				},
+				K8sLogger: mockK8sLogger,
 			}
 
 			err := jc.StreamJobLogs(tc.workloadName, tc.namespace)
-			if tc.expectedError {
-				assert.Error(t, err, "Expected error while streaming job logs")
-				assert.EqualError(t, err, tc.streamLogsError.Error(), "Error message does not match")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
 			} else {
 				assert.NoError(t, err, "Expected no error while streaming job logs")
 			}
 
-			mockDynK8s.AssertExpectations(t)
 			mockK8sLogger.AssertExpectations(t)
-			mockJobPodNameGetter.AssertExpectations(t)
 		})
 	}
 }
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) record(v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(v...))
+}
+
+func (l *recordingLogger) Println(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Error(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Errorf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Debug(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Debugf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Warn(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Warnf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func TestStreamJobLogsContext(t *testing.T) {
+	mockK8sLogger := new(MockK8sLogger)
+	mockK8sLogger.On("StreamLogsForSelector", mock.Anything, "default", "job-name=test-job").Return(nil)
+
+	jc := &jobs.JobsClient{
+		Client: &k8s.KubernetesClient{
+			Clientset:     new(MockKubernetesClient),
+			DynamicClient: jobsDynamicClient("Complete"),
+		},
+		K8sLogger: mockK8sLogger,
+	}
+
+	logger := &recordingLogger{}
+	ctx := logging.IntoContext(context.Background(), logger)
+	if err := jc.StreamJobLogsContext(ctx, "test-job", "default"); err != nil {
+		t.Fatalf("StreamJobLogsContext() error = %v", err)
+	}
+
+	assert.Contains(t, logger.messages, "Monitoring test-job job in default namespace")
+	mockK8sLogger.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/l50/goutils/v2/logging"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -22,7 +23,8 @@ import (
 //
 // error: An error if any occurs during fetching and logging of pods.
 func FetchAndLogPods(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string) error {
-	fmt.Printf("Attempting to list pods with label selector: '%s' in namespace '%s'\n", labelSelector, namespace)
+	logger := logging.FromContext(ctx)
+	logger.Printf("Attempting to list pods with label selector: '%s' in namespace '%s'", labelSelector, namespace)
 	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: labelSelector,
 	})
@@ -30,22 +32,22 @@ func FetchAndLogPods(ctx context.Context, clientset kubernetes.Interface, namesp
 		return fmt.Errorf("error listing pods: %v", err)
 	}
 	if len(pods.Items) == 0 {
-		fmt.Println("no pods found.")
+		logger.Println("no pods found.")
 		return nil
 	}
 	for _, pod := range pods.Items {
-		fmt.Printf("Fetching logs for pod: %s\n", pod.Name)
+		logger.Printf("Fetching logs for pod: %s", pod.Name)
 		req := clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &v1.PodLogOptions{})
 		logs, err := req.DoRaw(ctx)
 		if err != nil {
-			fmt.Printf("error getting logs for pod %s: %v\n", pod.Name, err)
+			logger.Warnf("error getting logs for pod %s: %v", pod.Name, err)
 			continue
 		}
 		if len(logs) == 0 {
-			fmt.Printf("No logs for pod %s\n", pod.Name)
+			logger.Printf("No logs for pod %s", pod.Name)
 			continue
 		}
-		fmt.Printf("Logs for pod %s:\n%s\n", pod.Name, string(logs))
+		logger.Printf("Logs for pod %s:\n%s", pod.Name, string(logs))
 	}
 	return nil
 }
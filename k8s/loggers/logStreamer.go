@@ -7,12 +7,167 @@ import (
 	"os"
 	"time"
 
+	"github.com/l50/goutils/v2/logging"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// StreamLogs streams logs for a specific resource within a namespace.
+// LogOptions configures StreamLogs and StreamLogsForSelector.
+//
+// **Attributes:**
+//
+// Follow: Whether to keep streaming new log lines until the container
+// exits. Defaults to true.
+// TailLines: Optional number of lines from the end of the logs to start
+// from. All lines are streamed if nil.
+// SinceTime: Optional time before which log lines are skipped.
+// Container: The container to stream logs from. May be empty for pods
+// with a single container.
+// Output: The writer the logs are written to. Defaults to os.Stdout.
+// Context: The context of the API requests, whose logger (see
+// logging.IntoContext) receives the retry warnings of StreamLogs.
+// Defaults to context.Background(). StreamLogsForSelector uses its ctx
+// parameter instead.
+type LogOptions struct {
+	Follow    bool
+	TailLines *int64
+	SinceTime *time.Time
+	Container string
+	Output    io.Writer
+	Context   context.Context
+}
+
+// LogOption is a function that modifies LogOptions.
+type LogOption func(*LogOptions)
+
+// WithFollow sets whether new log lines are streamed until the container
+// exits.
+//
+// **Parameters:**
+//
+// follow: Whether to follow the logs.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets Follow.
+func WithFollow(follow bool) LogOption {
+	return func(opts *LogOptions) {
+		opts.Follow = follow
+	}
+}
+
+// WithTailLines streams only the last lines of the logs.
+//
+// **Parameters:**
+//
+// lines: The number of lines from the end of the logs to start from.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets TailLines.
+func WithTailLines(lines int64) LogOption {
+	return func(opts *LogOptions) {
+		opts.TailLines = &lines
+	}
+}
+
+// WithSinceTime skips log lines written before the input time.
+//
+// **Parameters:**
+//
+// since: The time from which log lines are streamed.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets SinceTime.
+func WithSinceTime(since time.Time) LogOption {
+	return func(opts *LogOptions) {
+		opts.SinceTime = &since
+	}
+}
+
+// WithContainer streams the logs of a specific container.
+//
+// **Parameters:**
+//
+// container: The name of the container.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets Container.
+func WithContainer(container string) LogOption {
+	return func(opts *LogOptions) {
+		opts.Container = container
+	}
+}
+
+// WithOutput writes the logs to the input writer instead of os.Stdout.
+//
+// **Parameters:**
+//
+// w: The writer the logs are written to.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets Output.
+func WithOutput(w io.Writer) LogOption {
+	return func(opts *LogOptions) {
+		opts.Output = w
+	}
+}
+
+// WithContext runs the API requests of StreamLogs with the input context,
+// so that canceling it stops streaming and the logger it carries receives
+// the retry warnings.
+//
+// **Parameters:**
+//
+// ctx: The context of the API requests.
+//
+// **Returns:**
+//
+// LogOption: A LogOption that sets Context.
+func WithContext(ctx context.Context) LogOption {
+	return func(opts *LogOptions) {
+		opts.Context = ctx
+	}
+}
+
+// newLogOptions applies opts to the default LogOptions.
+func newLogOptions(opts []LogOption) LogOptions {
+	options := LogOptions{Follow: true, Output: os.Stdout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Output == nil {
+		options.Output = os.Stdout
+	}
+	if options.Context == nil {
+		options.Context = context.Background()
+	}
+	return options
+}
+
+// podLogOptions converts the options to the PodLogOptions of a log
+// request.
+func (o LogOptions) podLogOptions() *corev1.PodLogOptions {
+	podLogOpts := &corev1.PodLogOptions{
+		Follow:    o.Follow,
+		TailLines: o.TailLines,
+		Container: o.Container,
+	}
+	if o.SinceTime != nil {
+		since := metav1.NewTime(*o.SinceTime)
+		podLogOpts.SinceTime = &since
+	}
+	return podLogOpts
+}
+
+// StreamLogs streams logs for a specific resource within a namespace. For
+// jobs and deployments, the logs of their first pod are streamed; use
+// StreamLogsForSelector to stream the logs of every pod.
 //
 // **Parameters:**
 //
@@ -20,17 +175,22 @@ import (
 // namespace: Namespace where the resource is located.
 // resourceType: Type of resource ('pod', 'job', or 'deployment').
 // resourceName: Name of the resource to stream logs from.
+// opts: Optional LogOption values, e.g. WithTailLines or WithContext. By
+// default, the logs are followed and written to os.Stdout.
 //
 // **Returns:**
 //
-// error: An error if any occurs during the log streaming process.
-func StreamLogs(clientset kubernetes.Interface, namespace, resourceType, resourceName string) error {
+// error: An error if any occurs during the log streaming process. Errors
+// opening the stream wrap the error from client-go.
+func StreamLogs(clientset kubernetes.Interface, namespace, resourceType, resourceName string, opts ...LogOption) error {
+	options := newLogOptions(opts)
+	ctx := options.Context
 	podName := ""
 	switch resourceType {
 	case "pod":
 		podName = resourceName
 	case "job", "deployment":
-		pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("job-name=%s", resourceName),
 		})
 		if err != nil {
@@ -44,29 +204,33 @@ func StreamLogs(clientset kubernetes.Interface, namespace, resourceType, resourc
 		return fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
 
-	podLogOpts := &corev1.PodLogOptions{
-		Follow: true,
-	}
+	podLogOpts := options.podLogOptions()
 
 	maxRetries := 10
 	retryInterval := 10 * time.Second
 
 	for i := 0; i < maxRetries; i++ {
 		req := clientset.CoreV1().Pods(namespace).GetLogs(podName, podLogOpts)
-		logStream, err := req.Stream(context.TODO())
+		logStream, err := req.Stream(ctx)
 		if err != nil {
-			if i == maxRetries-1 {
-				return fmt.Errorf("error in opening stream: %v", err)
+			// Retrying with rejected credentials can't succeed; return so
+			// the caller can refresh them (see RetryOnUnauthorized).
+			if i == maxRetries-1 || apierrors.IsUnauthorized(err) {
+				return fmt.Errorf("error in opening stream: %w", err)
+			}
+			logging.FromContext(ctx).Warnf("Error in opening stream, retrying in %s: %v", retryInterval, err)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("error in opening stream: %w", ctx.Err())
+			case <-time.After(retryInterval):
 			}
-			fmt.Printf("Error in opening stream, retrying in %s: %v\n", retryInterval, err)
-			time.Sleep(retryInterval)
 			continue
 		}
 		defer logStream.Close()
 
-		_, err = io.Copy(os.Stdout, logStream)
+		_, err = io.Copy(options.Output, logStream)
 		if err != nil && err != io.EOF {
-			return fmt.Errorf("error in copying information from log to stdout: %v", err)
+			return fmt.Errorf("error in copying information from log to output: %v", err)
 		}
 		return nil
 	}
@@ -1,10 +1,14 @@
 package k8s_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	k8s "github.com/l50/goutils/v2/k8s/loggers"
 	"github.com/stretchr/testify/assert"
@@ -102,3 +106,133 @@ func TestStreamLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamLogsOptions(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tail := int64(50)
+
+	tests := []struct {
+		name     string
+		opts     []k8s.LogOption
+		expected *corev1.PodLogOptions
+	}{
+		{
+			name:     "defaults follow",
+			expected: &corev1.PodLogOptions{Follow: true},
+		},
+		{
+			name: "all options",
+			opts: []k8s.LogOption{
+				k8s.WithFollow(false),
+				k8s.WithTailLines(50),
+				k8s.WithSinceTime(since),
+				k8s.WithContainer("sidecar"),
+			},
+			expected: &corev1.PodLogOptions{
+				TailLines: &tail,
+				SinceTime: &metav1.Time{Time: since},
+				Container: "sidecar",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+			})
+			var out bytes.Buffer
+
+			err := k8s.StreamLogs(clientset, "default", "pod", "test-pod", append(tc.opts, k8s.WithOutput(&out))...)
+			assert.NoError(t, err)
+			assert.Equal(t, "fake logs", out.String())
+			assert.Equal(t, []*corev1.PodLogOptions{tc.expected}, podLogOptions(t, clientset))
+		})
+	}
+}
+
+func TestStreamLogsForSelector(t *testing.T) {
+	pod := func(name, app string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", Labels: map[string]string{"app": app},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		selector      string
+		setupClient   func(cs *fake.Clientset)
+		expectedLines []string
+		expectedError string
+	}{
+		{
+			name:          "all matching pods",
+			selector:      "app=web",
+			expectedLines: []string{"[web-1] fake logs", "[web-2] fake logs"},
+		},
+		{
+			name:          "no matching pods",
+			selector:      "app=cache",
+			expectedError: "no pods found matching selector 'app=cache'",
+		},
+		{
+			name:     "list failure",
+			selector: "app=web",
+			setupClient: func(cs *fake.Clientset) {
+				cs.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("forbidden")
+				})
+			},
+			expectedError: "failed to list pods: forbidden",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(pod("web-1", "web"), pod("web-2", "web"), pod("db-1", "db"))
+			if tc.setupClient != nil {
+				tc.setupClient(clientset)
+			}
+			var out bytes.Buffer
+
+			err := k8s.StreamLogsForSelector(context.Background(), clientset, "default", tc.selector,
+				k8s.WithTailLines(10), k8s.WithOutput(&out))
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			assert.ElementsMatch(t, tc.expectedLines, lines)
+
+			tail := int64(10)
+			opts := podLogOptions(t, clientset)
+			assert.Len(t, opts, len(tc.expectedLines))
+			for _, podOpts := range opts {
+				assert.Equal(t, &corev1.PodLogOptions{Follow: true, TailLines: &tail}, podOpts)
+			}
+		})
+	}
+}
+
+// podLogOptions returns the PodLogOptions of the log requests recorded by
+// clientset.
+func podLogOptions(t *testing.T, clientset *fake.Clientset) []*corev1.PodLogOptions {
+	t.Helper()
+
+	var opts []*corev1.PodLogOptions
+	for _, action := range clientset.Actions() {
+		generic, ok := action.(k8stesting.GenericActionImpl)
+		if !ok || generic.GetSubresource() != "log" {
+			continue
+		}
+		podOpts, ok := generic.Value.(*corev1.PodLogOptions)
+		if !ok {
+			t.Fatalf("unexpected log action value %T", generic.Value)
+		}
+		opts = append(opts, podOpts)
+	}
+	return opts
+}
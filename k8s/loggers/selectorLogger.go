@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StreamLogsForSelector streams the logs of every pod matching a label
+// selector concurrently, such as all pods of a job or a deployment. Lines
+// from the different pods are interleaved as they arrive, each prefixed
+// with the name of the pod it came from, e.g., "[web-7d4f9-abcde] ...".
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation. Canceling it stops
+// streaming without an error.
+// clientset: Kubernetes clientset to interact with Kubernetes API.
+// namespace: Namespace where the pods are located.
+// labelSelector: The label selector of the pods, e.g., "job-name=backup".
+// opts: Optional LogOption values, e.g. WithContainer. By default, the
+// logs are followed and written to os.Stdout.
+//
+// **Returns:**
+//
+// error: An error if no pods match the selector, or joining the errors of
+// the pods whose logs can't be streamed. The logs of the other pods are
+// still streamed.
+func StreamLogsForSelector(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, opts ...LogOption) error {
+	options := newLogOptions(opts)
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("no pods found matching selector '%s' in namespace '%s'", labelSelector, namespace)
+	}
+
+	out := &syncWriter{w: options.Output}
+	errs := make([]error, len(pods.Items))
+	var wg sync.WaitGroup
+	for i, pod := range pods.Items {
+		wg.Add(1)
+		go func(i int, podName string) {
+			defer wg.Done()
+			errs[i] = streamPrefixedLogs(ctx, clientset, namespace, podName, options, out)
+		}(i, pod.Name)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// streamPrefixedLogs streams the logs of a pod to out, prefixing each
+// line with the pod name.
+func streamPrefixedLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, options LogOptions, out *syncWriter) error {
+	logStream, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, options.podLogOptions()).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("error in opening stream for pod %s: %w", podName, err)
+	}
+	defer logStream.Close()
+
+	prefix := fmt.Sprintf("[%s] ", podName)
+	reader := bufio.NewReader(logStream)
+	for {
+		// ReadString is used rather than a bufio.Scanner so that long
+		// lines aren't rejected.
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if line[len(line)-1] != '\n' {
+				line += "\n"
+			}
+			if _, writeErr := out.Write([]byte(prefix + line)); writeErr != nil {
+				return fmt.Errorf("error in writing logs of pod %s: %v", podName, writeErr)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// Canceling ctx is how following streams are stopped.
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error in reading logs of pod %s: %v", podName, err)
+		}
+	}
+}
+
+// syncWriter serializes writes so lines from concurrent streams aren't
+// interleaved.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
@@ -0,0 +1,383 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// HelmClient manages Helm chart repositories and releases using the
+// Helm SDK, without requiring the helm CLI.
+//
+// **Attributes:**
+//
+// Settings: The Helm environment settings, including the kubeconfig and
+// the repository configuration and cache paths.
+// Namespace: The namespace releases are managed in.
+// ActionConfig: The Helm action configuration. It's initialized from
+// Settings on first use if nil.
+type HelmClient struct {
+	Settings     *cli.EnvSettings
+	Namespace    string
+	ActionConfig *action.Configuration
+}
+
+// HelmUpgradeOptions configures HelmClient.Upgrade.
+//
+// **Attributes:**
+//
+// Version: The chart version constraint. The latest version is used if
+// empty.
+// ValuesFiles: Values files merged in order, like `helm -f`.
+// Values: Values merged over the values files, like `helm --set`.
+// Wait: Whether to wait for the release's resources to become ready.
+// Timeout: How long to wait for Kubernetes operations. Defaults to 5
+// minutes.
+// CreateNamespace: Whether to create the namespace on install.
+type HelmUpgradeOptions struct {
+	Version         string
+	ValuesFiles     []string
+	Values          map[string]interface{}
+	Wait            bool
+	Timeout         time.Duration
+	CreateNamespace bool
+}
+
+// NewHelmClient creates a HelmClient for a namespace.
+//
+// **Parameters:**
+//
+// kubeConfigPath: Path to the kubeconfig file. Helm's default lookup is
+// used if empty.
+// namespace: The namespace releases are managed in.
+//
+// **Returns:**
+//
+// *HelmClient: The new HelmClient.
+func NewHelmClient(kubeConfigPath, namespace string) *HelmClient {
+	settings := cli.New()
+	if kubeConfigPath != "" {
+		settings.KubeConfig = kubeConfigPath
+	}
+	settings.SetNamespace(namespace)
+
+	return &HelmClient{Settings: settings, Namespace: namespace}
+}
+
+// AddRepo adds or updates a chart repository and downloads its index,
+// like `helm repo add --force-update`.
+//
+// **Parameters:**
+//
+// name: The name of the repository, e.g., "bitnami".
+// url: The URL of the repository.
+//
+// **Returns:**
+//
+// error: Error if the index can't be downloaded or the repository
+// configuration can't be written.
+func (hc *HelmClient) AddRepo(name, url string) error {
+	repoFile, err := hc.loadRepoFile()
+	if err != nil {
+		return err
+	}
+
+	entry := &repo.Entry{Name: name, URL: url}
+	if err := hc.downloadIndex(entry); err != nil {
+		return err
+	}
+
+	repoFile.Update(entry)
+	if err := os.MkdirAll(filepath.Dir(hc.Settings.RepositoryConfig), 0755); err != nil {
+		return fmt.Errorf("failed to create repository config directory: %v", err)
+	}
+	if err := repoFile.WriteFile(hc.Settings.RepositoryConfig, 0644); err != nil {
+		return fmt.Errorf("failed to write repository config: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateRepos downloads the latest index of every configured chart
+// repository, like `helm repo update`.
+//
+// **Returns:**
+//
+// error: Error if the repository configuration can't be read or an index
+// can't be downloaded.
+func (hc *HelmClient) UpdateRepos() error {
+	repoFile, err := hc.loadRepoFile()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range repoFile.Repositories {
+		if err := hc.downloadIndex(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Upgrade installs a chart as a release, or upgrades the release if it
+// already exists, like `helm upgrade --install`.
+//
+// **Parameters:**
+//
+// ctx: Context for the operation.
+// releaseName: The name of the release.
+// chartRef: The chart, as a local path or a "repo/chart" reference.
+// opts: HelmUpgradeOptions for the release.
+//
+// **Returns:**
+//
+// *release.Release: The installed or upgraded release.
+// error: Error if the chart can't be located or loaded, the values can't
+// be read, or the release fails.
+func (hc *HelmClient) Upgrade(ctx context.Context, releaseName, chartRef string, opts HelmUpgradeOptions) (*release.Release, error) {
+	cfg, err := hc.actionConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	vals, err := LoadHelmValues(opts.ValuesFiles, opts.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	_, err = history.Run(releaseName)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, fmt.Errorf("failed to get history of release %s: %v", releaseName, err)
+	}
+
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		install := action.NewInstall(cfg)
+		install.ReleaseName = releaseName
+		install.Namespace = hc.Namespace
+		install.Version = opts.Version
+		install.Wait = opts.Wait
+		install.Timeout = timeout
+		install.CreateNamespace = opts.CreateNamespace
+
+		chrt, err := hc.loadChart(&install.ChartPathOptions, chartRef)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := install.RunWithContext(ctx, chrt, vals)
+		if err != nil {
+			return nil, fmt.Errorf("helm install failed: %v", err)
+		}
+		return rel, nil
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = hc.Namespace
+	upgrade.Version = opts.Version
+	upgrade.Wait = opts.Wait
+	upgrade.Timeout = timeout
+
+	chrt, err := hc.loadChart(&upgrade.ChartPathOptions, chartRef)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := upgrade.RunWithContext(ctx, releaseName, chrt, vals)
+	if err != nil {
+		return nil, fmt.Errorf("helm upgrade failed: %v", err)
+	}
+
+	return rel, nil
+}
+
+// Uninstall uninstalls a release.
+//
+// **Parameters:**
+//
+// releaseName: The name of the release.
+//
+// **Returns:**
+//
+// error: Error if the release can't be uninstalled.
+func (hc *HelmClient) Uninstall(releaseName string) error {
+	cfg, err := hc.actionConfig()
+	if err != nil {
+		return err
+	}
+
+	if releaseName == "" {
+		return fmt.Errorf("invalid release name for deletion")
+	}
+	if _, err := action.NewUninstall(cfg).Run(releaseName); err != nil {
+		return fmt.Errorf("helm uninstall failed: %v", err)
+	}
+
+	return nil
+}
+
+// Status returns the latest revision of a release, like `helm status`.
+//
+// **Parameters:**
+//
+// releaseName: The name of the release.
+//
+// **Returns:**
+//
+// *release.Release: The release. Its Info.Status holds the release status.
+// error: Error if the release doesn't exist or can't be read.
+func (hc *HelmClient) Status(releaseName string) (*release.Release, error) {
+	cfg, err := hc.actionConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := action.NewStatus(cfg).Run(releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of release %s: %w", releaseName, err)
+	}
+
+	return rel, nil
+}
+
+// List returns the deployed releases of the namespace, like `helm list`.
+//
+// **Returns:**
+//
+// []*release.Release: The releases, sorted by name.
+// error: Error if the releases can't be listed.
+func (hc *HelmClient) List() ([]*release.Release, error) {
+	cfg, err := hc.actionConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := action.NewList(cfg).Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %v", err)
+	}
+
+	return releases, nil
+}
+
+// LoadHelmValues merges values files and values in the same way as
+// `helm -f file --set key=value`: files are merged in order, then values
+// are merged over them. Nested maps are merged key by key.
+//
+// **Parameters:**
+//
+// files: Values files, as paths or URLs.
+// overrides: Values taking precedence over the files.
+//
+// **Returns:**
+//
+// map[string]interface{}: The merged values.
+// error: Error if a values file can't be read or parsed.
+func LoadHelmValues(files []string, overrides map[string]interface{}) (map[string]interface{}, error) {
+	opts := values.Options{ValueFiles: files}
+	vals, err := opts.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read helm values: %v", err)
+	}
+
+	return mergeValues(vals, overrides), nil
+}
+
+// mergeValues returns base with the values of override merged over it.
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := out[k].(map[string]interface{}); ok {
+				out[k] = mergeValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// actionConfig returns the Helm action configuration, initializing it
+// on first use.
+func (hc *HelmClient) actionConfig() (*action.Configuration, error) {
+	if hc.ActionConfig != nil {
+		return hc.ActionConfig, nil
+	}
+
+	cfg := new(action.Configuration)
+	if err := cfg.Init(hc.Settings.RESTClientGetter(), hc.Namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+		return nil, fmt.Errorf("failed to initialize Helm: %v", err)
+	}
+	hc.ActionConfig = cfg
+
+	return cfg, nil
+}
+
+// loadChart locates and loads a chart, downloading it from a repository
+// if needed.
+func (hc *HelmClient) loadChart(opts *action.ChartPathOptions, chartRef string) (*chart.Chart, error) {
+	path, err := opts.LocateChart(chartRef, hc.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate helm chart %s: %v", chartRef, err)
+	}
+
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm chart: %v", err)
+	}
+
+	return chrt, nil
+}
+
+// loadRepoFile reads the repository configuration, returning an empty
+// one if it doesn't exist yet.
+func (hc *HelmClient) loadRepoFile() (*repo.File, error) {
+	repoFile, err := repo.LoadFile(hc.Settings.RepositoryConfig)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo.NewFile(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository config: %v", err)
+	}
+
+	return repoFile, nil
+}
+
+// downloadIndex downloads the index of a chart repository into the
+// repository cache.
+func (hc *HelmClient) downloadIndex(entry *repo.Entry) error {
+	chartRepo, err := repo.NewChartRepository(entry, getter.All(hc.Settings))
+	if err != nil {
+		return fmt.Errorf("invalid chart repository %s: %v", entry.Name, err)
+	}
+	chartRepo.CachePath = hc.Settings.RepositoryCache
+
+	if _, err := chartRepo.DownloadIndexFile(); err != nil {
+		return fmt.Errorf("failed to download index of chart repository %s: %v", entry.Name, err)
+	}
+
+	return nil
+}
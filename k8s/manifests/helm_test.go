@@ -0,0 +1,167 @@
+package k8s_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	k8s "github.com/l50/goutils/v2/k8s/manifests"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func newTestHelmClient(t *testing.T) *k8s.HelmClient {
+	t.Helper()
+
+	hc := k8s.NewHelmClient("", "default")
+	dir := t.TempDir()
+	hc.Settings.RepositoryConfig = filepath.Join(dir, "config", "repositories.yaml")
+	hc.Settings.RepositoryCache = filepath.Join(dir, "cache")
+	hc.ActionConfig = &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+	return hc
+}
+
+func writeChart(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "demo")
+	files := map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: demo\nversion: 0.1.0\n",
+		"values.yaml":              "image:\n  repository: nginx\n  tag: latest\nreplicas: 1\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\ndata:\n  image: {{ .Values.image.repository }}:{{ .Values.image.tag }}\n  replicas: \"{{ .Values.replicas }}\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoadHelmValues(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("image:\n  repository: nginx\n  tag: \"1.0\"\nreplicas: 1\n"), 0644))
+	require.NoError(t, os.WriteFile(prod, []byte("replicas: 3\n"), 0644))
+
+	tests := []struct {
+		name      string
+		files     []string
+		overrides map[string]interface{}
+		expected  map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			name:     "Files merged in order",
+			files:    []string{base, prod},
+			expected: map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "1.0"}, "replicas": float64(3)},
+		},
+		{
+			name:      "Overrides merge nested maps",
+			files:     []string{base},
+			overrides: map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}},
+			expected:  map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "2.0"}, "replicas": float64(1)},
+		},
+		{
+			name:    "Missing file",
+			files:   []string{filepath.Join(dir, "missing.yaml")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vals, err := k8s.LoadHelmValues(tc.files, tc.overrides)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, vals)
+		})
+	}
+}
+
+func TestHelmClientReleases(t *testing.T) {
+	hc := newTestHelmClient(t)
+	chartPath := writeChart(t)
+	ctx := context.Background()
+
+	rel, err := hc.Upgrade(ctx, "demo", chartPath, k8s.HelmUpgradeOptions{
+		Values: map[string]interface{}{"image": map[string]interface{}{"tag": "1.25"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, rel.Version)
+	require.Contains(t, rel.Manifest, "image: nginx:1.25")
+
+	rel, err = hc.Upgrade(ctx, "demo", chartPath, k8s.HelmUpgradeOptions{
+		Values: map[string]interface{}{"replicas": 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, rel.Version)
+	require.Contains(t, rel.Manifest, `replicas: "2"`)
+	require.Contains(t, rel.Manifest, "image: nginx:latest")
+
+	status, err := hc.Status("demo")
+	require.NoError(t, err)
+	require.Equal(t, release.StatusDeployed, status.Info.Status)
+	require.Equal(t, 2, status.Version)
+
+	releases, err := hc.List()
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	require.Equal(t, "demo", releases[0].Name)
+
+	require.NoError(t, hc.Uninstall("demo"))
+	_, err = hc.Status("demo")
+	require.Error(t, err)
+	require.Error(t, hc.Uninstall(""))
+
+	_, err = hc.Upgrade(ctx, "missing", filepath.Join(t.TempDir(), "missing"), k8s.HelmUpgradeOptions{})
+	require.Error(t, err)
+}
+
+func TestHelmClientRepos(t *testing.T) {
+	index := "apiVersion: v1\nentries:\n  demo:\n  - name: demo\n    version: 0.1.0\n    urls:\n    - demo-0.1.0.tgz\n"
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+		_, _ = io.WriteString(w, index)
+	}))
+	t.Cleanup(server.Close)
+
+	hc := newTestHelmClient(t)
+	require.NoError(t, hc.UpdateRepos())
+	require.NoError(t, hc.AddRepo("example", server.URL))
+	require.FileExists(t, filepath.Join(hc.Settings.RepositoryCache, "example-index.yaml"))
+
+	repoFile, err := repo.LoadFile(hc.Settings.RepositoryConfig)
+	require.NoError(t, err)
+	require.True(t, repoFile.Has("example"))
+	require.Equal(t, server.URL, repoFile.Get("example").URL)
+
+	require.NoError(t, hc.UpdateRepos())
+	require.Equal(t, 2, requests)
+
+	require.Error(t, hc.AddRepo("broken", server.URL+"/missing"))
+}
@@ -0,0 +1,202 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// inventoryKey is the ConfigMap data key holding the inventory.
+const inventoryKey = "objects"
+
+// inventoryGVR is the resource inventories are stored as.
+var inventoryGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// InventoryEntry identifies an object recorded in an inventory.
+//
+// **Attributes:**
+//
+// Group: The API group of the object. Empty for the core group.
+// Version: The API version the object was last handled with. It isn't
+// part of the object's identity, so an object whose manifest moves to a
+// new version is still tracked as the same object.
+// Resource: The resource of the object, e.g., "deployments".
+// Namespace: The namespace of the object.
+// Name: The name of the object.
+type InventoryEntry struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// String returns the entry as "resource.group/namespace/name".
+//
+// **Returns:**
+//
+// string: The string representation of the entry.
+func (e InventoryEntry) String() string {
+	resource := e.Resource
+	if e.Group != "" {
+		resource += "." + e.Group
+	}
+	return fmt.Sprintf("%s/%s/%s", resource, e.Namespace, e.Name)
+}
+
+// key identifies the object of the entry regardless of its API version,
+// so that moving a resource to a new version doesn't prune it.
+func (e InventoryEntry) key() InventoryEntry {
+	e.Version = ""
+	return e
+}
+
+func (e InventoryEntry) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: e.Group, Version: e.Version, Resource: e.Resource}
+}
+
+// ReadInventory returns the objects recorded in the Inventory ConfigMap.
+//
+// **Parameters:**
+//
+// ctx: The context for the operation.
+// dynClient: The dynamic client to read the inventory with.
+//
+// **Returns:**
+//
+// []InventoryEntry: The recorded objects. Empty if the inventory doesn't
+// exist yet.
+// error: Error if Inventory is empty or the inventory can't be read.
+func (mc *ManifestConfig) ReadInventory(ctx context.Context, dynClient dynamic.Interface) ([]InventoryEntry, error) {
+	if mc.Inventory == "" {
+		return nil, fmt.Errorf("no inventory is configured")
+	}
+
+	cm, err := dynClient.Resource(inventoryGVR).Namespace(mc.Namespace).Get(ctx, mc.Inventory, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory %s: %v", mc.Inventory, err)
+	}
+
+	raw, _, _ := unstructured.NestedString(cm.Object, "data", inventoryKey)
+	if raw == "" {
+		return nil, nil
+	}
+	var entries []InventoryEntry
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory %s: %v", mc.Inventory, err)
+	}
+
+	return entries, nil
+}
+
+// updateInventory records the objects handled by an apply or delete in
+// the Inventory ConfigMap. After an apply with Prune set, recorded
+// objects that weren't applied are deleted first.
+//
+// **Parameters:**
+//
+// ctx: The context for the operation.
+// dynClient: The dynamic client to perform Kubernetes operations.
+// handled: The objects that were applied or deleted.
+//
+// **Returns:**
+//
+// error: Error if a pruned object can't be deleted or the inventory
+// can't be read or written.
+func (mc *ManifestConfig) updateInventory(ctx context.Context, dynClient dynamic.Interface, handled []InventoryEntry) error {
+	previous, err := mc.ReadInventory(ctx, dynClient)
+	if err != nil {
+		return err
+	}
+
+	inHandled := make(map[InventoryEntry]bool, len(handled))
+	for _, entry := range handled {
+		inHandled[entry.key()] = true
+	}
+
+	var remaining []InventoryEntry
+	switch mc.Operation {
+	case OperationApply, OperationUpdate:
+		remaining = handled
+		for _, entry := range previous {
+			if inHandled[entry.key()] {
+				continue
+			}
+			if !mc.Prune {
+				remaining = append(remaining, entry)
+				continue
+			}
+			if err := pruneObject(ctx, dynClient, entry); err != nil {
+				return err
+			}
+		}
+	case OperationDelete:
+		for _, entry := range previous {
+			if !inHandled[entry.key()] {
+				remaining = append(remaining, entry)
+			}
+		}
+	}
+
+	return mc.writeInventory(ctx, dynClient, remaining)
+}
+
+// pruneObject deletes an object that was removed from the manifest.
+func pruneObject(ctx context.Context, dynClient dynamic.Interface, entry InventoryEntry) error {
+	propagation := metav1.DeletePropagationBackground
+	err := dynClient.Resource(entry.gvr()).Namespace(entry.Namespace).Delete(ctx, entry.Name,
+		metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to prune %s: %v", entry, err)
+	}
+	return nil
+}
+
+// writeInventory stores entries in the Inventory ConfigMap, deleting it
+// once it's empty.
+func (mc *ManifestConfig) writeInventory(ctx context.Context, dynClient dynamic.Interface, entries []InventoryEntry) error {
+	client := dynClient.Resource(inventoryGVR).Namespace(mc.Namespace)
+	if len(entries) == 0 {
+		if err := client.Delete(ctx, mc.Inventory, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete inventory %s: %v", mc.Inventory, err)
+		}
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].String() < entries[j].String() })
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory %s: %v", mc.Inventory, err)
+	}
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": mc.Inventory, "namespace": mc.Namespace},
+		"data":       map[string]interface{}{inventoryKey: string(raw)},
+	}}
+	mc.ResourceMetadata.ApplyTo(cm)
+
+	existing, err := client.Get(ctx, mc.Inventory, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = client.Create(ctx, cm, metav1.CreateOptions{})
+	} else if err == nil {
+		cm.SetResourceVersion(existing.GetResourceVersion())
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write inventory %s: %v", mc.Inventory, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	k8s "github.com/l50/goutils/v2/k8s/manifests"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func configMapManifest(names ...string) string {
+	var manifest string
+	for _, name := range names {
+		manifest += fmt.Sprintf("---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n  key: value\n", name)
+	}
+	return manifest
+}
+
+func TestPrune(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	tests := []struct {
+		name          string
+		prune         bool
+		operation     k8s.ManifestOperation
+		second        []string
+		expectedNames []string
+		expectedInv   []string
+	}{
+		{
+			name:          "Prune removed objects",
+			prune:         true,
+			operation:     k8s.OperationApply,
+			second:        []string{"a", "c"},
+			expectedNames: []string{"a", "c"},
+			expectedInv:   []string{"a", "c"},
+		},
+		{
+			name:          "Without prune removed objects stay tracked",
+			operation:     k8s.OperationApply,
+			second:        []string{"a"},
+			expectedNames: []string{"a", "b"},
+			expectedInv:   []string{"a", "b"},
+		},
+		{
+			name:        "Delete removes objects from inventory",
+			operation:   k8s.OperationDelete,
+			second:      []string{"a", "b"},
+			expectedInv: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fdc := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{configMaps: "ConfigMapList"})
+			manifest := configMapManifest("a", "b")
+
+			mc := k8s.NewManifestConfig()
+			mc.Namespace = "default"
+			mc.Inventory = "app-inventory"
+			mc.Prune = tc.prune
+			mc.Operation = k8s.OperationApply
+			mc.ReadFile = func(string) ([]byte, error) { return []byte(manifest), nil }
+			if err := mc.HandleRawManifest(ctx, fdc); err != nil {
+				t.Fatalf("first HandleRawManifest() error = %v", err)
+			}
+
+			manifest = configMapManifest(tc.second...)
+			mc.Operation = tc.operation
+			if err := mc.HandleRawManifest(ctx, fdc); err != nil {
+				t.Fatalf("second HandleRawManifest() error = %v", err)
+			}
+
+			list, err := fdc.Resource(configMaps).Namespace("default").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			var names []string
+			for _, item := range list.Items {
+				if item.GetName() != mc.Inventory {
+					names = append(names, item.GetName())
+				}
+			}
+			if !reflect.DeepEqual(names, tc.expectedNames) {
+				t.Errorf("remaining objects = %v, want %v", names, tc.expectedNames)
+			}
+
+			entries, err := mc.ReadInventory(ctx, fdc)
+			if err != nil {
+				t.Fatalf("ReadInventory() error = %v", err)
+			}
+			var inventory []string
+			for _, entry := range entries {
+				inventory = append(inventory, entry.Name)
+			}
+			if !reflect.DeepEqual(inventory, tc.expectedInv) {
+				t.Errorf("inventory = %v, want %v", inventory, tc.expectedInv)
+			}
+
+			_, err = fdc.Resource(configMaps).Namespace("default").Get(ctx, mc.Inventory, metav1.GetOptions{})
+			if tc.expectedInv == nil && !apierrors.IsNotFound(err) {
+				t.Errorf("expected empty inventory to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPruneRequiresInventory(t *testing.T) {
+	mc := k8s.NewManifestConfig()
+	mc.Prune = true
+	mc.ReadFile = func(string) ([]byte, error) { return []byte(configMapManifest("a")), nil }
+
+	if err := mc.HandleRawManifest(context.Background(), fake.NewSimpleDynamicClient(runtime.NewScheme())); err == nil {
+		t.Error("expected an error when pruning without an inventory")
+	}
+	if _, err := mc.ReadInventory(context.Background(), fake.NewSimpleDynamicClient(runtime.NewScheme())); err == nil {
+		t.Error("expected an error when reading an unset inventory")
+	}
+}
+
+func TestPruneKeepsObjectsMovedToNewVersion(t *testing.T) {
+	ctx := context.Background()
+	pdbs := schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}
+	pdbsBeta := schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets"}
+	fdc := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		pdbs:                                    "PodDisruptionBudgetList",
+		pdbsBeta:                                "PodDisruptionBudgetList",
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+	})
+
+	version := "v1beta1"
+	mc := k8s.NewManifestConfig()
+	mc.Namespace = "default"
+	mc.Inventory = "app-inventory"
+	mc.Prune = true
+	mc.Operation = k8s.OperationApply
+	mc.ReadFile = func(string) ([]byte, error) {
+		return []byte(fmt.Sprintf("apiVersion: policy/%s\nkind: PodDisruptionBudget\nmetadata:\n  name: web\n", version)), nil
+	}
+	if err := mc.HandleRawManifest(ctx, fdc); err != nil {
+		t.Fatalf("first HandleRawManifest() error = %v", err)
+	}
+
+	version = "v1"
+	fdc.ClearActions()
+	if err := mc.HandleRawManifest(ctx, fdc); err != nil {
+		t.Fatalf("second HandleRawManifest() error = %v", err)
+	}
+
+	for _, action := range fdc.Actions() {
+		if action.GetVerb() == "delete" && action.GetResource().Resource == "poddisruptionbudgets" {
+			t.Errorf("expected the moved object not to be pruned, got %v", action)
+		}
+	}
+	entries, err := mc.ReadInventory(ctx, fdc)
+	if err != nil {
+		t.Fatalf("ReadInventory() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Version != "v1" {
+		t.Errorf("inventory = %+v, want the object at its new version only", entries)
+	}
+}
@@ -1,14 +1,15 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"text/template"
 
-	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/chart/loader"
-	"helm.sh/helm/v3/pkg/cli"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +19,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
 // MetadataConfig holds metadata configuration for Kubernetes resources.
@@ -34,24 +37,76 @@ type MetadataConfig struct {
 // **Attributes:**
 //
 // KubeConfigPath: Path to the kubeconfig file.
-// ManifestPath: Path to the Kubernetes manifest file.
+// ManifestPath: Path to the Kubernetes manifest file, Helm chart, or
+// kustomization directory.
 // Namespace: Kubernetes namespace in which the operations will be performed.
 // Type: The type of manifest (raw, Helm, or Kustomize).
 // Operation: The operation to perform (apply or delete).
 // Metadata: Metadata related to the manifest.
 // Client: The dynamic Kubernetes client interface.
 // ReadFile: Function to read the manifest file from the filesystem.
+// ResourceMetadata: Optional labels and annotations added to every
+// resource created from a raw manifest or script.
+// ApplyStrategy: How OperationApply handles objects that already exist.
+// FieldManager: The field manager used by ApplyServerSide. Defaults to
+// DefaultFieldManager.
+// ForceConflicts: Whether ApplyServerSide takes ownership of fields
+// managed by other field managers.
+// Values: Optional values the manifest is rendered with as a
+// text/template before it's decoded. Templating is disabled if nil.
+// ExpandEnv: Whether ${VAR} references in the manifest are replaced with
+// the values of environment variables before it's decoded.
+// HelmValuesFiles: Values files used when installing or upgrading a Helm
+// chart, merged in order.
+// HelmValues: Values merged over HelmValuesFiles.
+// Inventory: Optional name of a ConfigMap in Namespace that records the
+// objects applied from raw and Kustomize manifests, so that objects
+// removed from the manifest can be pruned later.
+// Prune: Whether applying deletes the objects recorded in Inventory that
+// are no longer in the manifest, like `kubectl apply --prune`.
 type ManifestConfig struct {
-	KubeConfigPath string
-	ManifestPath   string
-	Namespace      string
-	Type           ManifestType
-	Operation      ManifestOperation
-	Metadata       *MetadataConfig
-	Client         dynamic.Interface
-	ReadFile       func(string) ([]byte, error)
+	KubeConfigPath   string
+	ManifestPath     string
+	Namespace        string
+	Type             ManifestType
+	Operation        ManifestOperation
+	Metadata         *MetadataConfig
+	Client           dynamic.Interface
+	ReadFile         func(string) ([]byte, error)
+	ResourceMetadata *ResourceMetadata
+	ApplyStrategy    ApplyStrategy
+	FieldManager     string
+	ForceConflicts   bool
+	Values           map[string]interface{}
+	ExpandEnv        bool
+	HelmValuesFiles  []string
+	HelmValues       map[string]interface{}
+	Inventory        string
+	Prune            bool
 }
 
+// DefaultFieldManager is the field manager used for server-side apply
+// when ManifestConfig.FieldManager is empty.
+const DefaultFieldManager = "goutils"
+
+// ApplyStrategy defines how OperationApply handles raw and Kustomize
+// manifests. Job manifests are always recreated.
+//
+// **Values:**
+//
+// ApplyUpdate: Create missing objects and update existing ones in place,
+// like `kubectl apply`. This is the default.
+// ApplyCreate: Create objects, deleting and recreating existing ones.
+// ApplyServerSide: Use server-side apply with the configured field
+// manager, like `kubectl apply --server-side`.
+type ApplyStrategy int
+
+const (
+	ApplyUpdate ApplyStrategy = iota
+	ApplyCreate
+	ApplyServerSide
+)
+
 // ManifestType defines the type of Kubernetes manifest.
 //
 // **Values:**
@@ -144,14 +199,18 @@ func (mc *ManifestConfig) ApplyOrDeleteManifest(ctx context.Context) error {
 	case ManifestRaw, ManifestJob:
 		return mc.HandleRawManifest(ctx, mc.Client)
 	case ManifestHelm:
-		return mc.handleHelmManifest()
+		return mc.handleHelmManifest(ctx)
+	case ManifestKustomize:
+		return mc.handleKustomizeManifest(ctx, mc.Client)
 	default:
 		return fmt.Errorf("unsupported manifest type")
 	}
 }
 
 // CreateConfigMapFromScript creates a ConfigMap from a script
-// file and applies it to the Kubernetes cluster.
+// file and applies it to the Kubernetes cluster. The script is stored
+// under the "script" key. CreateOrUpdateConfigMap of the k8s/core
+// package supports other keys and updates existing ConfigMaps.
 //
 // **Parameters:**
 //
@@ -192,6 +251,7 @@ func (mc *ManifestConfig) CreateConfigMapFromScript(ctx context.Context, scriptP
 
 	// Apply the ConfigMap
 	unstructuredObj := &unstructured.Unstructured{Object: rawObj}
+	mc.ResourceMetadata.ApplyToUnstructured(unstructuredObj)
 	gvk := unstructuredObj.GroupVersionKind()
 	gvr, err := mc.groupVersionResource(gvk)
 	if err != nil {
@@ -207,7 +267,8 @@ func (mc *ManifestConfig) CreateConfigMapFromScript(ctx context.Context, scriptP
 }
 
 // HandleRawManifest applies or deletes raw Kubernetes manifests based on the
-// operation specified in ManifestConfig.
+// operation specified in ManifestConfig. Existing objects are handled
+// according to the ApplyStrategy, so applying a manifest again succeeds.
 //
 // **Parameters:**
 //
@@ -222,6 +283,32 @@ func (mc *ManifestConfig) HandleRawManifest(ctx context.Context, dynClient dynam
 	if err != nil {
 		return fmt.Errorf("error reading manifest file: %v", err)
 	}
+	return mc.applyOrDeleteObjects(ctx, dynClient, data)
+}
+
+// applyOrDeleteObjects applies or deletes each object of a multi-document
+// YAML or JSON stream based on the operation specified in ManifestConfig.
+//
+// **Parameters:**
+//
+// ctx: The context for the operation.
+// dynClient: The dynamic client to perform Kubernetes operations.
+// data: The manifest objects to apply or delete.
+//
+// **Returns:**
+//
+// error: Error if any issue occurs while handling the objects.
+func (mc *ManifestConfig) applyOrDeleteObjects(ctx context.Context, dynClient dynamic.Interface, data []byte) error {
+	data, err := mc.Render(data)
+	if err != nil {
+		return err
+	}
+
+	if mc.Prune && mc.Inventory == "" {
+		return fmt.Errorf("pruning requires an inventory")
+	}
+
+	var handled []InventoryEntry
 	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(data)), 2048)
 	for {
 		rawObj := &unstructured.Unstructured{}
@@ -246,28 +333,8 @@ func (mc *ManifestConfig) HandleRawManifest(ctx context.Context, dynClient dynam
 		var operationErr error
 		switch mc.Operation {
 		case OperationApply:
-			_, operationErr = resourceClient.Create(ctx, rawObj, metav1.CreateOptions{})
-			if errors.IsAlreadyExists(operationErr) {
-				// Fetch the existing job
-				existingObj, getErr := resourceClient.Get(ctx, rawObj.GetName(), metav1.GetOptions{})
-				if getErr != nil {
-					return fmt.Errorf("failed to get existing job: %v", getErr)
-				}
-
-				// Check if the object is being deleted
-				if existingObj.GetDeletionTimestamp() != nil {
-					return fmt.Errorf("object is being deleted: %v", rawObj.GetName())
-				}
-
-				// Delete the existing job
-				delErr := resourceClient.Delete(ctx, existingObj.GetName(), metav1.DeleteOptions{})
-				if delErr != nil {
-					return fmt.Errorf("failed to delete existing job: %v", delErr)
-				}
-
-				// Create the job again after deleting the existing one
-				_, operationErr = resourceClient.Create(ctx, rawObj, metav1.CreateOptions{})
-			}
+			mc.ResourceMetadata.ApplyToUnstructured(rawObj)
+			operationErr = mc.applyObject(ctx, resourceClient, rawObj)
 		case OperationDelete:
 			operationErr = resourceClient.Delete(ctx, rawObj.GetName(), metav1.DeleteOptions{})
 		}
@@ -275,109 +342,218 @@ func (mc *ManifestConfig) HandleRawManifest(ctx context.Context, dynClient dynam
 		if operationErr != nil {
 			return fmt.Errorf("failed to %s manifest: %v", strings.ToLower(mc.Operation.String()), operationErr)
 		}
+		handled = append(handled, InventoryEntry{
+			Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource,
+			Namespace: mc.Namespace, Name: rawObj.GetName(),
+		})
 	}
-	return nil
+
+	if mc.Inventory == "" {
+		return nil
+	}
+	return mc.updateInventory(ctx, dynClient, handled)
 }
 
-// groupVersionResource constructs a GroupVersionResource from a GroupVersionKind.
+// envReference matches the ${VAR} references replaced when ExpandEnv is
+// set.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Render prepares manifest data for decoding: it expands ${VAR}
+// references to environment variables if ExpandEnv is set, then executes
+// the result as a text/template with Values if Values isn't nil.
+// References to unset environment variables are left as they are, so
+// shell snippets embedded in manifests survive. Documents that render to
+// nothing are skipped when the manifest is decoded.
 //
 // **Parameters:**
 //
-// gvk: The GroupVersionKind to convert.
+// data: The manifest to render.
 //
 // **Returns:**
 //
-// GroupVersionResource: The constructed GroupVersionResource.
-// error: Error if the kind is empty.
-func (mc *ManifestConfig) groupVersionResource(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
-	if gvk.Kind == "" {
-		return schema.GroupVersionResource{}, fmt.Errorf("kind must not be empty")
+// []byte: The rendered manifest.
+// error: Error if the template is invalid or references a missing value.
+func (mc *ManifestConfig) Render(data []byte) ([]byte, error) {
+	if mc.ExpandEnv {
+		data = envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+			if value, ok := os.LookupEnv(string(ref[2 : len(ref)-1])); ok {
+				return []byte(value)
+			}
+			return ref
+		})
 	}
 
-	resource := strings.ToLower(gvk.Kind) + "s"
-	return schema.GroupVersionResource{
-		Group:    gvk.Group,
-		Version:  gvk.Version,
-		Resource: resource,
-	}, nil
+	if mc.Values == nil {
+		return data, nil
+	}
+
+	tmpl, err := template.New(filepath.Base(mc.ManifestPath)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest template: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, mc.Values); err != nil {
+		return nil, fmt.Errorf("error rendering manifest template: %v", err)
+	}
+
+	return buf.Bytes(), nil
 }
 
-// handleHelmManifest manages Helm chart installations or deletions based on
-// the operation specified in ManifestConfig.
+// RenderKustomization builds a kustomization directory, in the same way
+// as `kustomize build` or `kubectl kustomize`, and returns the rendered
+// resources.
 //
 // **Parameters:**
 //
+// path: The path to the directory containing the kustomization file.
+//
 // **Returns:**
 //
-// error: Error if any issue occurs while handling the Helm manifest.
-func (mc *ManifestConfig) handleHelmManifest() error {
-	settings := cli.New() // Initialize Helm settings
-	actionConfig := new(action.Configuration)
+// []byte: The rendered resources as a multi-document YAML stream.
+// error: Error if the kustomization can't be built.
+func RenderKustomization(path string) ([]byte, error) {
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %s: %v", path, err)
+	}
 
-	if err := actionConfig.Init(settings.RESTClientGetter(), mc.Namespace, os.Getenv("HELM_DRIVER"), nil); err != nil {
-		return fmt.Errorf("failed to initialize Helm: %v", err)
+	data, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kustomization %s: %v", path, err)
 	}
 
-	switch mc.Operation {
-	case OperationApply:
-		return mc.installHelmChart(actionConfig)
-	case OperationDelete:
-		return mc.deleteHelmRelease(actionConfig)
-	default:
-		return fmt.Errorf("unsupported Helm operation")
+	return data, nil
+}
+
+// handleKustomizeManifest builds the kustomization at ManifestPath and
+// applies or deletes the rendered resources in the same way as a raw
+// manifest.
+//
+// **Parameters:**
+//
+// ctx: The context for the operation.
+// dynClient: The dynamic client to perform Kubernetes operations.
+//
+// **Returns:**
+//
+// error: Error if any issue occurs while handling the kustomization.
+func (mc *ManifestConfig) handleKustomizeManifest(ctx context.Context, dynClient dynamic.Interface) error {
+	data, err := RenderKustomization(mc.ManifestPath)
+	if err != nil {
+		return err
 	}
+	return mc.applyOrDeleteObjects(ctx, dynClient, data)
 }
 
-// installHelmChart installs a Helm chart using the specified action configuration.
+// applyObject creates or updates an object using the configured
+// ApplyStrategy. Jobs are always recreated, since their pod template is
+// immutable and re-running a job needs a new object.
 //
 // **Parameters:**
 //
-// actionConfig: Configuration for the Helm install action.
+// ctx: The context for the operation.
+// resourceClient: The client for the object's resource.
+// obj: The object to apply.
 //
 // **Returns:**
 //
-// error: Error if the installation fails.
-func (mc *ManifestConfig) installHelmChart(actionConfig *action.Configuration) error {
-	// Create an instance of the Install action
-	install := action.NewInstall(actionConfig)
-	install.ReleaseName = mc.Metadata.Name // The release name must be set if not automatically generated
-	install.Namespace = mc.Namespace       // Namespace where the chart will be installed
+// error: Error if the object can't be applied.
+func (mc *ManifestConfig) applyObject(ctx context.Context, resourceClient dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	strategy := mc.ApplyStrategy
+	if mc.Type == ManifestJob {
+		strategy = ApplyCreate
+	}
 
-	// Load the chart from the given path
-	chart, err := loader.Load(mc.ManifestPath)
-	if err != nil {
-		return fmt.Errorf("failed to load helm chart: %v", err)
+	if strategy == ApplyServerSide {
+		fieldManager := mc.FieldManager
+		if fieldManager == "" {
+			fieldManager = DefaultFieldManager
+		}
+		_, err := resourceClient.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        mc.ForceConflicts,
+		})
+		return err
+	}
+
+	_, err := resourceClient.Create(ctx, obj, metav1.CreateOptions{})
+	if !errors.IsAlreadyExists(err) {
+		return err
 	}
 
-	// Run the installation
-	_, err = install.Run(chart, nil) // Pass nil if no custom values are needed
+	existingObj, err := resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("helm install failed: %v", err)
+		return fmt.Errorf("failed to get existing object: %v", err)
 	}
 
-	return nil
+	// Check if the object is being deleted
+	if existingObj.GetDeletionTimestamp() != nil {
+		return fmt.Errorf("object is being deleted: %v", obj.GetName())
+	}
+
+	if strategy == ApplyUpdate {
+		obj.SetResourceVersion(existingObj.GetResourceVersion())
+		_, err = resourceClient.Update(ctx, obj, metav1.UpdateOptions{})
+		return err
+	}
+
+	// Delete the existing object and create it again
+	if err := resourceClient.Delete(ctx, existingObj.GetName(), metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete existing object: %v", err)
+	}
+	_, err = resourceClient.Create(ctx, obj, metav1.CreateOptions{})
+	return err
 }
 
-// deleteHelmRelease uninstalls a Helm release using the specified action configuration.
+// groupVersionResource constructs a GroupVersionResource from a GroupVersionKind.
 //
 // **Parameters:**
 //
-// actionConfig: Configuration for the Helm uninstall action.
+// gvk: The GroupVersionKind to convert.
 //
 // **Returns:**
 //
-// error: Error if the uninstallation fails.
-func (mc *ManifestConfig) deleteHelmRelease(actionConfig *action.Configuration) error {
-	client := action.NewUninstall(actionConfig)
+// GroupVersionResource: The constructed GroupVersionResource.
+// error: Error if the kind is empty.
+func (mc *ManifestConfig) groupVersionResource(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	if gvk.Kind == "" {
+		return schema.GroupVersionResource{}, fmt.Errorf("kind must not be empty")
+	}
+
+	resource := strings.ToLower(gvk.Kind) + "s"
+	return schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: resource,
+	}, nil
+}
 
-	// Ensure ReleaseName is derived safely.
+// handleHelmManifest installs or upgrades, or uninstalls, the Helm chart
+// at ManifestPath as a release named after Metadata.Name.
+//
+// **Parameters:**
+//
+// ctx: The context for the operation.
+//
+// **Returns:**
+//
+// error: Error if any issue occurs while handling the Helm manifest.
+func (mc *ManifestConfig) handleHelmManifest(ctx context.Context) error {
 	if mc.Metadata == nil || mc.Metadata.Name == "" {
-		return fmt.Errorf("invalid release name for deletion")
-	}
-	_, err := client.Run(mc.Metadata.Name)
-	if err != nil {
-		return fmt.Errorf("helm uninstall failed: %v", err)
+		return fmt.Errorf("invalid helm release name")
 	}
+	hc := NewHelmClient(mc.KubeConfigPath, mc.Namespace)
 
-	return nil
+	switch mc.Operation {
+	case OperationApply, OperationUpdate:
+		_, err := hc.Upgrade(ctx, mc.Metadata.Name, mc.ManifestPath, HelmUpgradeOptions{
+			ValuesFiles: mc.HelmValuesFiles,
+			Values:      mc.HelmValues,
+		})
+		return err
+	case OperationDelete:
+		return hc.Uninstall(mc.Metadata.Name)
+	default:
+		return fmt.Errorf("unsupported Helm operation")
+	}
 }
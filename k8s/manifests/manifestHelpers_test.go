@@ -3,11 +3,17 @@ package k8s_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	k8s "github.com/l50/goutils/v2/k8s/manifests"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic/fake"
 	k8stesting "k8s.io/client-go/testing"
 )
@@ -184,3 +190,255 @@ func TestHandleRawManifest(t *testing.T) {
 		})
 	}
 }
+
+func writeKustomization(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"kustomization.yaml": "resources:\n- configmap.yaml\nnamePrefix: dev-\ncommonLabels:\n  app: demo\n",
+		"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  mode: test\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestKustomizeManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation k8s.ManifestOperation
+		path      func(t *testing.T) string
+		verb      string
+		wantErr   bool
+	}{
+		{name: "apply kustomization", operation: k8s.OperationApply, path: writeKustomization, verb: "create"},
+		{name: "delete kustomization", operation: k8s.OperationDelete, path: writeKustomization, verb: "delete"},
+		{
+			name:      "missing kustomization",
+			operation: k8s.OperationApply,
+			path:      func(t *testing.T) string { return t.TempDir() },
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fdc := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			var names []string
+			fdc.PrependReactor("*", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetVerb() != tc.verb {
+					return false, nil, nil
+				}
+				switch a := action.(type) {
+				case k8stesting.CreateAction:
+					obj := a.GetObject().(*unstructured.Unstructured)
+					if obj.GetLabels()["app"] != "demo" {
+						t.Errorf("expected the common label to be set, got %v", obj.GetLabels())
+					}
+					names = append(names, obj.GetName())
+				case k8stesting.DeleteAction:
+					names = append(names, a.GetName())
+				}
+				return true, nil, nil
+			})
+
+			mc := k8s.NewManifestConfig()
+			mc.Type = k8s.ManifestKustomize
+			mc.Operation = tc.operation
+			mc.Namespace = "default"
+			mc.ManifestPath = tc.path(t)
+			mc.Client = fdc
+
+			err := mc.ApplyOrDeleteManifest(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ApplyOrDeleteManifest() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if len(names) != 1 || names[0] != "dev-settings" {
+				t.Errorf("expected dev-settings to be %sd, got %v", tc.verb, names)
+			}
+		})
+	}
+}
+
+func TestApplyStrategies(t *testing.T) {
+	existing := func() runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "settings", "namespace": "default", "resourceVersion": "7"},
+			"data":       map[string]interface{}{"mode": "old"},
+		}}
+	}
+
+	tests := []struct {
+		name         string
+		manifestType k8s.ManifestType
+		strategy     k8s.ApplyStrategy
+		objects      []runtime.Object
+		wantVerbs    []string
+	}{
+		{name: "update creates missing object", strategy: k8s.ApplyUpdate, wantVerbs: []string{"create"}},
+		{name: "update existing object", strategy: k8s.ApplyUpdate, objects: []runtime.Object{existing()}, wantVerbs: []string{"create", "get", "update"}},
+		{name: "recreate existing object", strategy: k8s.ApplyCreate, objects: []runtime.Object{existing()}, wantVerbs: []string{"create", "get", "delete", "create"}},
+		{name: "server-side apply", strategy: k8s.ApplyServerSide, objects: []runtime.Object{existing()}, wantVerbs: []string{"patch"}},
+		{name: "jobs are always recreated", manifestType: k8s.ManifestJob, strategy: k8s.ApplyUpdate, objects: []runtime.Object{existing()}, wantVerbs: []string{"create", "get", "delete", "create"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fdc := fake.NewSimpleDynamicClient(runtime.NewScheme(), tc.objects...)
+			gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+			// The fake object tracker can't merge apply patches into
+			// unstructured objects, so store the applied object instead.
+			fdc.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				obj := &unstructured.Unstructured{}
+				if err := obj.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()); err != nil {
+					return true, nil, err
+				}
+				obj.SetNamespace("default")
+				return true, obj, fdc.Tracker().Update(gvr, obj, "default")
+			})
+			mc := k8s.NewManifestConfig()
+			mc.Type = tc.manifestType
+			mc.Operation = k8s.OperationApply
+			mc.ApplyStrategy = tc.strategy
+			mc.Namespace = "default"
+			mc.ReadFile = func(string) ([]byte, error) {
+				return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  mode: new\n"), nil
+			}
+
+			if err := mc.HandleRawManifest(context.Background(), fdc); err != nil {
+				t.Fatalf("HandleRawManifest() error = %v", err)
+			}
+
+			var verbs []string
+			for _, action := range fdc.Actions() {
+				verbs = append(verbs, action.GetVerb())
+				if patch, ok := action.(k8stesting.PatchAction); ok && patch.GetPatchType() != types.ApplyPatchType {
+					t.Errorf("expected an apply patch, got %s", patch.GetPatchType())
+				}
+			}
+			if !reflect.DeepEqual(verbs, tc.wantVerbs) {
+				t.Errorf("HandleRawManifest() actions = %v, want %v", verbs, tc.wantVerbs)
+			}
+
+			obj, err := fdc.Resource(gvr).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get applied object: %v", err)
+			}
+			if mode, _, _ := unstructured.NestedString(obj.Object, "data", "mode"); mode != "new" {
+				t.Errorf("expected the applied data, got mode %q", mode)
+			}
+
+			// Applying the same manifest again must succeed.
+			if err := mc.HandleRawManifest(context.Background(), fdc); err != nil {
+				t.Errorf("re-applying the manifest failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("GOUTILS_TEST_TAG", "1.2.3")
+
+	tests := []struct {
+		name      string
+		values    map[string]interface{}
+		expandEnv bool
+		manifest  string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:     "no rendering",
+			manifest: "image: app:{{ .Tag }} ${GOUTILS_TEST_TAG}",
+			want:     "image: app:{{ .Tag }} ${GOUTILS_TEST_TAG}",
+		},
+		{
+			name:     "template values",
+			values:   map[string]interface{}{"Namespace": "qa", "Tag": "v2"},
+			manifest: "namespace: {{ .Namespace }}\nimage: app:{{ .Tag }}",
+			want:     "namespace: qa\nimage: app:v2",
+		},
+		{
+			name:      "environment variables",
+			expandEnv: true,
+			manifest:  "image: app:${GOUTILS_TEST_TAG}\nscript: echo $HOME ${GOUTILS_TEST_UNSET}",
+			want:      "image: app:1.2.3\nscript: echo $HOME ${GOUTILS_TEST_UNSET}",
+		},
+		{
+			name:     "missing value",
+			values:   map[string]interface{}{},
+			manifest: "image: app:{{ .Tag }}",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid template",
+			values:   map[string]interface{}{},
+			manifest: "image: app:{{ .Tag",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := k8s.NewManifestConfig()
+			mc.Values = tc.values
+			mc.ExpandEnv = tc.expandEnv
+
+			got, err := mc.Render([]byte(tc.manifest))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && string(got) != tc.want {
+				t.Errorf("Render() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandleTemplatedManifest(t *testing.T) {
+	manifest := `{{ range .Names }}---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ . }}
+data:
+  tag: "{{ $.Tag }}"
+{{ end }}{{ if .WithSecret }}---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+{{ end }}`
+
+	fdc := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	mc := k8s.NewManifestConfig()
+	mc.Operation = k8s.OperationApply
+	mc.Namespace = "default"
+	mc.Values = map[string]interface{}{"Names": []string{"first", "second"}, "Tag": "v1", "WithSecret": false}
+	mc.ReadFile = func(string) ([]byte, error) { return []byte(manifest), nil }
+
+	if err := mc.HandleRawManifest(context.Background(), fdc); err != nil {
+		t.Fatalf("HandleRawManifest() error = %v", err)
+	}
+
+	var created []string
+	for _, action := range fdc.Actions() {
+		if create, ok := action.(k8stesting.CreateAction); ok {
+			obj := create.GetObject().(*unstructured.Unstructured)
+			tag, _, _ := unstructured.NestedString(obj.Object, "data", "tag")
+			created = append(created, obj.GetKind()+"/"+obj.GetName()+"="+tag)
+		}
+	}
+	want := []string{"ConfigMap/first=v1", "ConfigMap/second=v1"}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("HandleRawManifest() created %v, want %v", created, want)
+	}
+}
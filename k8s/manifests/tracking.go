@@ -0,0 +1,249 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// LabelManagedBy is the standard label naming the tool that manages a
+	// resource.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// LabelRunID identifies the automation run that created a resource.
+	LabelRunID = "goutils/run-id"
+	// LabelOwner identifies the user or system on whose behalf a resource
+	// was created.
+	LabelOwner = "goutils/owner"
+
+	// defaultManagedBy is the LabelManagedBy value used when
+	// ResourceMetadata.ManagedBy is empty.
+	defaultManagedBy = "goutils"
+)
+
+// DefaultTrackedResources lists the resource types that ListByRunID and
+// DeleteByRunID search when no resource types are given.
+var DefaultTrackedResources = []schema.GroupVersionResource{
+	{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Version: "v1", Resource: "services"},
+	{Version: "v1", Resource: "configmaps"},
+	{Version: "v1", Resource: "secrets"},
+	{Version: "v1", Resource: "pods"},
+}
+
+// ResourceMetadata holds the labels and annotations added to every
+// resource created through ManifestConfig or the k8s/jobs clients, so
+// that the resources created by an automation run can be found and
+// cleaned up with ListByRunID and DeleteByRunID.
+//
+// **Attributes:**
+//
+// ManagedBy: The value of the app.kubernetes.io/managed-by label. Defaults
+// to "goutils".
+// RunID: The value of the goutils/run-id label. Omitted if empty.
+// Owner: The value of the goutils/owner label. Omitted if empty.
+// Labels: Additional labels. Labels already set on a resource are kept.
+// Annotations: Additional annotations. Annotations already set on a
+// resource are kept.
+type ResourceMetadata struct {
+	ManagedBy   string
+	RunID       string
+	Owner       string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// TrackingLabels returns the managed-by, run-id, and owner labels.
+//
+// **Returns:**
+//
+// map[string]string: The tracking labels.
+func (m *ResourceMetadata) TrackingLabels() map[string]string {
+	managedBy := m.ManagedBy
+	if managedBy == "" {
+		managedBy = defaultManagedBy
+	}
+
+	tracking := map[string]string{LabelManagedBy: managedBy}
+	if m.RunID != "" {
+		tracking[LabelRunID] = m.RunID
+	}
+	if m.Owner != "" {
+		tracking[LabelOwner] = m.Owner
+	}
+
+	return tracking
+}
+
+// ApplyTo adds the labels and annotations to the metadata of obj. The
+// tracking labels overwrite existing values; the additional labels and
+// annotations don't.
+//
+// **Parameters:**
+//
+// obj: The object to label, such as a *batchv1.Job.
+func (m *ResourceMetadata) ApplyTo(obj metav1.Object) {
+	if m == nil {
+		return
+	}
+	obj.SetLabels(m.mergeLabels(obj.GetLabels()))
+	obj.SetAnnotations(mergeMissing(obj.GetAnnotations(), m.Annotations))
+}
+
+// ApplyToUnstructured adds the labels and annotations to obj, like
+// ApplyTo, and also adds the labels to its pod template
+// (spec.template) or job template (spec.jobTemplate and its pod
+// template), so that pods created by workloads can be tracked too.
+//
+// **Parameters:**
+//
+// obj: The object to label.
+func (m *ResourceMetadata) ApplyToUnstructured(obj *unstructured.Unstructured) {
+	if m == nil {
+		return
+	}
+	m.ApplyTo(obj)
+
+	templates := [][]string{
+		{"spec", "template", "metadata", "labels"},
+		{"spec", "jobTemplate", "metadata", "labels"},
+		{"spec", "jobTemplate", "spec", "template", "metadata", "labels"},
+	}
+	for _, path := range templates {
+		parent := path[:len(path)-2]
+		if _, found, _ := unstructured.NestedMap(obj.Object, parent...); !found {
+			continue
+		}
+		existing, _, _ := unstructured.NestedStringMap(obj.Object, path...)
+		_ = unstructured.SetNestedStringMap(obj.Object, m.mergeLabels(existing), path...)
+	}
+}
+
+func (m *ResourceMetadata) mergeLabels(existing map[string]string) map[string]string {
+	merged := mergeMissing(existing, m.Labels)
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	for k, v := range m.TrackingLabels() {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// mergeMissing returns a copy of dst with the entries of src whose keys
+// aren't in dst added.
+func mergeMissing(dst, src map[string]string) map[string]string {
+	if len(dst) == 0 && len(src) == 0 {
+		return dst
+	}
+
+	merged := make(map[string]string, len(dst)+len(src))
+	for k, v := range src {
+		merged[k] = v
+	}
+	for k, v := range dst {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// ListByRunID lists the resources labeled with the input run ID.
+//
+// **Parameters:**
+//
+// ctx: The context for the API requests.
+// dynClient: The dynamic client to list resources with.
+// runID: The run ID to search for.
+// namespace: The namespace to search. An empty string searches all
+// namespaces.
+// gvrs: The resource types to search. If empty, DefaultTrackedResources is
+// used. Resource types that the cluster doesn't serve are skipped.
+//
+// **Returns:**
+//
+// []unstructured.Unstructured: The resources labeled with the run ID.
+// error: An error if runID is empty or a resource type can't be listed.
+func ListByRunID(ctx context.Context, dynClient dynamic.Interface, runID, namespace string, gvrs []schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	if runID == "" {
+		return nil, fmt.Errorf("run ID must not be empty")
+	}
+	if len(gvrs) == 0 {
+		gvrs = DefaultTrackedResources
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{LabelRunID: runID}).String()
+	var found []unstructured.Unstructured
+	for _, gvr := range gvrs {
+		list, err := dynClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return found, fmt.Errorf("failed to list %s with run ID %s: %v", gvr.Resource, runID, err)
+		}
+		found = append(found, list.Items...)
+	}
+
+	return found, nil
+}
+
+// DeleteByRunID deletes the resources labeled with the input run ID.
+// Dependents, such as the pods of a job, are deleted in the background.
+//
+// **Parameters:**
+//
+// ctx: The context for the API requests.
+// dynClient: The dynamic client to delete resources with.
+// runID: The run ID to search for.
+// namespace: The namespace to search. An empty string searches all
+// namespaces.
+// gvrs: The resource types to search. If empty, DefaultTrackedResources is
+// used.
+//
+// **Returns:**
+//
+// int: The number of deleted resources.
+// error: An error if a resource type can't be listed or a resource can't
+// be deleted.
+func DeleteByRunID(ctx context.Context, dynClient dynamic.Interface, runID, namespace string, gvrs []schema.GroupVersionResource) (int, error) {
+	if runID == "" {
+		return 0, fmt.Errorf("run ID must not be empty")
+	}
+	if len(gvrs) == 0 {
+		gvrs = DefaultTrackedResources
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	deleted := 0
+	for _, gvr := range gvrs {
+		items, err := ListByRunID(ctx, dynClient, runID, namespace, []schema.GroupVersionResource{gvr})
+		if err != nil {
+			return deleted, err
+		}
+		for _, item := range items {
+			err := dynClient.Resource(gvr).Namespace(item.GetNamespace()).Delete(ctx, item.GetName(),
+				metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete %s %s: %v", gvr.Resource, item.GetName(), err)
+			}
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,177 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	k8s "github.com/l50/goutils/v2/k8s/manifests"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	jobsGVR       = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+func newTrackingClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range k8s.DefaultTrackedResources {
+		listKinds[gvr] = "List"
+	}
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func TestResourceMetadataApplyToUnstructured(t *testing.T) {
+	md := &k8s.ResourceMetadata{
+		RunID:       "run-42",
+		Owner:       "ci",
+		Labels:      map[string]string{"team": "platform", "app": "ignored"},
+		Annotations: map[string]string{"note": "created by test"},
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		templatePaths [][]string
+	}{
+		{
+			name:     "Config map",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n  labels:\n    app: cfg\n",
+		},
+		{
+			name: "Job",
+			manifest: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: job\n  labels:\n    app: cfg\n" +
+				"spec:\n  template:\n    spec:\n      containers: []\n",
+			templatePaths: [][]string{{"spec", "template", "metadata", "labels"}},
+		},
+		{
+			name: "Cron job",
+			manifest: "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: cron\n  labels:\n    app: cfg\n" +
+				"spec:\n  jobTemplate:\n    spec:\n      template:\n        spec:\n          containers: []\n",
+			templatePaths: [][]string{
+				{"spec", "jobTemplate", "metadata", "labels"},
+				{"spec", "jobTemplate", "spec", "template", "metadata", "labels"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := &k8s.ManifestConfig{
+				Operation:        k8s.OperationApply,
+				Namespace:        "default",
+				ResourceMetadata: md,
+				ReadFile:         func(string) ([]byte, error) { return []byte(tc.manifest), nil },
+			}
+			client := newTrackingClient()
+			require.NoError(t, mc.HandleRawManifest(context.Background(), client))
+
+			items, err := k8s.ListByRunID(context.Background(), client, "run-42", "default", nil)
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			obj := items[0]
+
+			require.Equal(t, map[string]string{
+				"app":              "cfg",
+				"team":             "platform",
+				k8s.LabelManagedBy: "goutils",
+				k8s.LabelRunID:     "run-42",
+				k8s.LabelOwner:     "ci",
+			}, obj.GetLabels())
+			require.Equal(t, map[string]string{"note": "created by test"}, obj.GetAnnotations())
+
+			for _, path := range tc.templatePaths {
+				labels, found, err := unstructured.NestedStringMap(obj.Object, path...)
+				require.NoError(t, err)
+				require.True(t, found, "labels at %v", path)
+				require.Equal(t, "run-42", labels[k8s.LabelRunID])
+			}
+		})
+	}
+}
+
+func TestResourceMetadataNil(t *testing.T) {
+	var md *k8s.ResourceMetadata
+	obj := &unstructured.Unstructured{}
+	obj.SetLabels(map[string]string{"app": "web"})
+	md.ApplyToUnstructured(obj)
+	require.Equal(t, map[string]string{"app": "web"}, obj.GetLabels())
+}
+
+func TestDeleteByRunID(t *testing.T) {
+	newObject := func(gvr schema.GroupVersionResource, kind, namespace, name, runID string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(gvr.GroupVersion().String())
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		if runID != "" {
+			obj.SetLabels(map[string]string{k8s.LabelRunID: runID})
+		}
+		return obj
+	}
+
+	testCases := []struct {
+		name        string
+		namespace   string
+		gvrs        []schema.GroupVersionResource
+		wantDeleted int
+		wantLeft    []string
+	}{
+		{
+			name:        "All namespaces",
+			wantDeleted: 3,
+			wantLeft:    []string{"other-run", "unlabeled"},
+		},
+		{
+			name:        "Single namespace",
+			namespace:   "default",
+			wantDeleted: 2,
+			wantLeft:    []string{"other-ns", "other-run", "unlabeled"},
+		},
+		{
+			name:        "Single resource type",
+			gvrs:        []schema.GroupVersionResource{configMapsGVR},
+			wantDeleted: 2,
+			wantLeft:    []string{"job", "other-run", "unlabeled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTrackingClient(
+				newObject(jobsGVR, "Job", "default", "job", "run-1"),
+				newObject(configMapsGVR, "ConfigMap", "default", "cfg", "run-1"),
+				newObject(configMapsGVR, "ConfigMap", "ops", "other-ns", "run-1"),
+				newObject(configMapsGVR, "ConfigMap", "default", "other-run", "run-2"),
+				newObject(configMapsGVR, "ConfigMap", "default", "unlabeled", ""),
+			)
+			gvrs := tc.gvrs
+			if gvrs == nil {
+				gvrs = []schema.GroupVersionResource{jobsGVR, configMapsGVR}
+			}
+
+			deleted, err := k8s.DeleteByRunID(context.Background(), client, "run-1", tc.namespace, gvrs)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantDeleted, deleted)
+
+			var left []string
+			for _, gvr := range []schema.GroupVersionResource{jobsGVR, configMapsGVR} {
+				list, err := client.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+				require.NoError(t, err)
+				for _, item := range list.Items {
+					left = append(left, item.GetName())
+				}
+			}
+			require.ElementsMatch(t, tc.wantLeft, left)
+		})
+	}
+
+	_, err := k8s.DeleteByRunID(context.Background(), newTrackingClient(), "", "", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,313 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// routeGroupVersion is the API group version of OpenShift Routes.
+const routeGroupVersion = "route.openshift.io/v1"
+
+// RouteGVR identifies OpenShift Routes for the dynamic client.
+var RouteGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// ExposeSpec describes how a service is exposed outside of the cluster
+// with an Ingress or an OpenShift Route.
+//
+// **Attributes:**
+//
+// Name: The name of the Ingress or Route.
+// Namespace: The namespace of the service.
+// Host: The external host name. Required for Ingresses. OpenShift
+// generates a host for Routes if it's empty.
+// Path: The path routed to the service. Defaults to "/".
+// ServiceName: The name of the service to expose.
+// ServicePort: The port of the service to expose.
+// TLS: Whether the host is served over HTTPS. Routes use edge
+// termination with the router's default certificate.
+// TLSSecret: Optional secret holding the Ingress certificate. The
+// ingress controller's default certificate is used if empty.
+// IngressClass: Optional Ingress class. The cluster's default class is
+// used if empty.
+// Annotations: Optional annotations added to the Ingress or Route.
+type ExposeSpec struct {
+	Name         string
+	Namespace    string
+	Host         string
+	Path         string
+	ServiceName  string
+	ServicePort  int32
+	TLS          bool
+	TLSSecret    string
+	IngressClass string
+	Annotations  map[string]string
+}
+
+// CreateOrUpdateIngress creates an Ingress routing a host and path to a
+// service, or updates it if it already exists.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to manage the Ingress.
+// spec: The Ingress to create or update.
+//
+// **Returns:**
+//
+// string: The external URL of the service, e.g., "https://app.example.com/".
+// error: An error if the spec is invalid or the Ingress can't be
+// created or updated.
+func CreateOrUpdateIngress(ctx context.Context, kc *client.KubernetesClient, spec ExposeSpec) (string, error) {
+	if kc == nil || kc.Clientset == nil {
+		return "", errors.New("kubernetes client is not initialized")
+	}
+	if err := spec.validate(); err != nil {
+		return "", err
+	}
+	if spec.Host == "" {
+		return "", fmt.Errorf("host is required for ingress '%s'", spec.Name)
+	}
+
+	ingress := spec.ingress()
+	ingresses := kc.Clientset.NetworkingV1().Ingresses(spec.Namespace)
+	_, err := ingresses.Create(ctx, ingress, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := ingresses.Get(ctx, spec.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", fmt.Errorf("failed to get ingress '%s' in namespace '%s': %w", spec.Name, spec.Namespace, getErr)
+		}
+		existing.Spec = ingress.Spec
+		existing.Annotations = client.MergeMetadata(existing.Annotations, spec.Annotations)
+		_, err = ingresses.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to apply ingress '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	return externalURL(spec.TLS, spec.Host, spec.path()), nil
+}
+
+// IsOpenShift reports whether the cluster is OpenShift, detected by the
+// presence of the Route API.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to query the API server.
+//
+// **Returns:**
+//
+// bool: True if the cluster serves OpenShift Routes.
+// error: An error if the API server can't be queried.
+func IsOpenShift(ctx context.Context, kc *client.KubernetesClient) (bool, error) {
+	if kc == nil || kc.Clientset == nil {
+		return false, errors.New("kubernetes client is not initialized")
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	resources, err := kc.Clientset.Discovery().ServerResourcesForGroupVersion(routeGroupVersion)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to discover %s: %w", routeGroupVersion, err)
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == RouteGVR.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateOrUpdateRoute creates an OpenShift Route exposing a service, or
+// updates it if it already exists. The host generated by OpenShift is
+// kept when an existing Route is updated without a host.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient whose DynamicClient manages the Route.
+// spec: The Route to create or update.
+//
+// **Returns:**
+//
+// string: The external URL of the service.
+// error: An error if the spec is invalid, the Route can't be created or
+// updated, or it has no host.
+func CreateOrUpdateRoute(ctx context.Context, kc *client.KubernetesClient, spec ExposeSpec) (string, error) {
+	if kc == nil || kc.DynamicClient == nil {
+		return "", errors.New("kubernetes client is not initialized")
+	}
+	if err := spec.validate(); err != nil {
+		return "", err
+	}
+
+	route := spec.route()
+	routes := kc.DynamicClient.Resource(RouteGVR).Namespace(spec.Namespace)
+	applied, err := routes.Create(ctx, route, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := routes.Get(ctx, spec.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", fmt.Errorf("failed to get route '%s' in namespace '%s': %w", spec.Name, spec.Namespace, getErr)
+		}
+		if spec.Host == "" {
+			if host, _, _ := unstructured.NestedString(existing.Object, "spec", "host"); host != "" {
+				_ = unstructured.SetNestedField(route.Object, host, "spec", "host")
+			}
+		}
+		existing.Object["spec"] = route.Object["spec"]
+		existing.SetAnnotations(client.MergeMetadata(existing.GetAnnotations(), spec.Annotations))
+		applied, err = routes.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to apply route '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	host, _, _ := unstructured.NestedString(applied.Object, "spec", "host")
+	if host == "" {
+		return "", fmt.Errorf("route '%s' in namespace '%s' has no host", spec.Name, spec.Namespace)
+	}
+
+	return externalURL(spec.TLS, host, spec.path()), nil
+}
+
+// Expose exposes a service outside of the cluster, using an OpenShift
+// Route when running on OpenShift and an Ingress otherwise.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to detect the platform and manage the
+// resources.
+// spec: The service to expose.
+//
+// **Returns:**
+//
+// string: The external URL of the service.
+// error: An error if the platform can't be detected or the Route or
+// Ingress can't be created or updated.
+func Expose(ctx context.Context, kc *client.KubernetesClient, spec ExposeSpec) (string, error) {
+	openShift, err := IsOpenShift(ctx, kc)
+	if err != nil {
+		return "", err
+	}
+	if openShift {
+		return CreateOrUpdateRoute(ctx, kc, spec)
+	}
+	return CreateOrUpdateIngress(ctx, kc, spec)
+}
+
+// validate checks the fields common to Ingresses and Routes.
+func (s ExposeSpec) validate() error {
+	if s.Name == "" || s.Namespace == "" || s.ServiceName == "" {
+		return errors.New("name, namespace, and service name are required")
+	}
+	if s.ServicePort <= 0 || s.ServicePort > 65535 {
+		return fmt.Errorf("invalid service port %d", s.ServicePort)
+	}
+	if s.Path != "" && !strings.HasPrefix(s.Path, "/") {
+		return fmt.Errorf("path %q must start with /", s.Path)
+	}
+	return nil
+}
+
+// path returns the routed path, defaulting to "/".
+func (s ExposeSpec) path() string {
+	if s.Path == "" {
+		return "/"
+	}
+	return s.Path
+}
+
+// ingress builds the Ingress described by the spec.
+func (s ExposeSpec) ingress() *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace, Annotations: s.Annotations},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: s.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     s.path(),
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: s.ServiceName,
+									Port: networkingv1.ServiceBackendPort{Number: s.ServicePort},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if s.IngressClass != "" {
+		ingress.Spec.IngressClassName = &s.IngressClass
+	}
+	if s.TLS {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{s.Host}, SecretName: s.TLSSecret}}
+	}
+
+	return ingress
+}
+
+// route builds the OpenShift Route described by the spec.
+func (s ExposeSpec) route() *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"path": s.path(),
+		"to": map[string]interface{}{
+			"kind": "Service",
+			"name": s.ServiceName,
+		},
+		"port": map[string]interface{}{
+			"targetPort": int64(s.ServicePort),
+		},
+	}
+	if s.Host != "" {
+		spec["host"] = s.Host
+	}
+	if s.TLS {
+		spec["tls"] = map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": routeGroupVersion,
+		"kind":       "Route",
+		"spec":       spec,
+	}}
+	route.SetName(s.Name)
+	route.SetNamespace(s.Namespace)
+	if len(s.Annotations) > 0 {
+		route.SetAnnotations(s.Annotations)
+	}
+
+	return route
+}
+
+// externalURL returns the URL of a host and path.
+func externalURL(tls bool, host, path string) string {
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+	return scheme + "://" + host + path
+}
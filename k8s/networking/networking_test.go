@@ -0,0 +1,188 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	networking "github.com/l50/goutils/v2/k8s/networking"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCreateOrUpdateIngress(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        networking.ExposeSpec
+		existing    []runtime.Object
+		expectedURL string
+		expectedErr string
+		check       func(t *testing.T, ingress *networkingv1.Ingress)
+	}{
+		{
+			name:        "Plain HTTP",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", Host: "web.example.com", ServiceName: "web", ServicePort: 80},
+			expectedURL: "http://web.example.com/",
+			check: func(t *testing.T, ingress *networkingv1.Ingress) {
+				require.Nil(t, ingress.Spec.TLS)
+				backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+				require.Equal(t, "web", backend.Name)
+				require.Equal(t, int32(80), backend.Port.Number)
+			},
+		},
+		{
+			name: "TLS with secret updates existing ingress",
+			spec: networking.ExposeSpec{
+				Name: "web", Namespace: "default", Host: "web.example.com", Path: "/api", ServiceName: "api", ServicePort: 8080,
+				TLS: true, TLSSecret: "web-tls", IngressClass: "nginx", Annotations: map[string]string{"team": "qa"},
+			},
+			existing: []runtime.Object{&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Name: "web", Namespace: "default", Annotations: map[string]string{"owner": "ops"},
+			}}},
+			expectedURL: "https://web.example.com/api",
+			check: func(t *testing.T, ingress *networkingv1.Ingress) {
+				require.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"web.example.com"}, SecretName: "web-tls"}}, ingress.Spec.TLS)
+				require.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+				require.Equal(t, "/api", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+				require.Equal(t, map[string]string{"owner": "ops", "team": "qa"}, ingress.Annotations)
+			},
+		},
+		{
+			name:        "Missing host",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", ServiceName: "web", ServicePort: 80},
+			expectedErr: "host is required",
+		},
+		{
+			name:        "Invalid port",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", Host: "web.example.com", ServiceName: "web"},
+			expectedErr: "invalid service port 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tc.existing...)
+			kc := &client.KubernetesClient{Clientset: clientset}
+
+			url, err := networking.CreateOrUpdateIngress(context.Background(), kc, tc.spec)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedURL, url)
+
+			ingress, err := clientset.NetworkingV1().Ingresses("default").Get(context.Background(), tc.spec.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			tc.check(t, ingress)
+		})
+	}
+}
+
+func newRouteClient(t *testing.T, openShift bool, objects ...runtime.Object) *client.KubernetesClient {
+	t.Helper()
+
+	clientset := fake.NewSimpleClientset()
+	if openShift {
+		clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "route.openshift.io/v1",
+			APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route", Namespaced: true}},
+		}}
+	}
+
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{networking.RouteGVR: "RouteList"}, objects...)
+	// Simulate OpenShift generating a host for routes created without one.
+	dynClient.PrependReactor("create", "routes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		route := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host == "" {
+			require.NoError(t, unstructured.SetNestedField(route.Object, route.GetName()+"-"+route.GetNamespace()+".apps.example.com", "spec", "host"))
+		}
+		return false, nil, nil
+	})
+
+	return &client.KubernetesClient{Clientset: clientset, DynamicClient: dynClient}
+}
+
+func TestIsOpenShift(t *testing.T) {
+	for _, openShift := range []bool{true, false} {
+		ok, err := networking.IsOpenShift(context.Background(), newRouteClient(t, openShift))
+		require.NoError(t, err)
+		require.Equal(t, openShift, ok)
+	}
+}
+
+func TestCreateOrUpdateRoute(t *testing.T) {
+	existingRoute := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       map[string]interface{}{"host": "generated.apps.example.com"},
+	}}
+
+	tests := []struct {
+		name        string
+		spec        networking.ExposeSpec
+		existing    []runtime.Object
+		expectedURL string
+	}{
+		{
+			name:        "Generated host",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", ServiceName: "web", ServicePort: 8080},
+			expectedURL: "http://web-default.apps.example.com/",
+		},
+		{
+			name:        "Explicit host with TLS",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", Host: "web.example.com", Path: "/ui", ServiceName: "web", ServicePort: 8080, TLS: true},
+			expectedURL: "https://web.example.com/ui",
+		},
+		{
+			name:        "Update keeps generated host",
+			spec:        networking.ExposeSpec{Name: "web", Namespace: "default", ServiceName: "web", ServicePort: 9090},
+			existing:    []runtime.Object{existingRoute},
+			expectedURL: "http://generated.apps.example.com/",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := newRouteClient(t, true, tc.existing...)
+
+			url, err := networking.Expose(context.Background(), kc, tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedURL, url)
+
+			route, err := kc.DynamicClient.Resource(networking.RouteGVR).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+			require.NoError(t, err)
+			port, _, _ := unstructured.NestedInt64(route.Object, "spec", "port", "targetPort")
+			require.Equal(t, int64(tc.spec.ServicePort), port)
+			termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+			if tc.spec.TLS {
+				require.Equal(t, "edge", termination)
+			} else {
+				require.Empty(t, termination)
+			}
+		})
+	}
+}
+
+func TestExposeWithoutOpenShift(t *testing.T) {
+	kc := newRouteClient(t, false)
+	url, err := networking.Expose(context.Background(), kc, networking.ExposeSpec{
+		Name: "web", Namespace: "default", Host: "web.example.com", ServiceName: "web", ServicePort: 80,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "http://web.example.com/", url)
+
+	_, err = kc.Clientset.NetworkingV1().Ingresses("default").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+}
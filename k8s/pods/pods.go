@@ -0,0 +1,299 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	dynK8s "github.com/l50/goutils/v2/k8s/dynamic"
+	k8swait "github.com/l50/goutils/v2/k8s/wait"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// PodsClient represents a client for ad hoc management of Kubernetes pods
+// through the Kubernetes API.
+//
+// **Attributes:**
+//
+// Client: A pointer to KubernetesClient for accessing the Kubernetes API.
+// ExecutorCreator: Creates the SPDY executor used by ExecIntoPod. The
+// client-go executor is used if nil.
+type PodsClient struct {
+	Client          *client.KubernetesClient
+	ExecutorCreator dynK8s.ExecutorCreator
+}
+
+// ExecOptions configures ExecIntoPod.
+//
+// **Attributes:**
+//
+// Container: The container to run the command in. May be empty for pods
+// with a single container.
+// Stdin: Optional reader streamed to the command's standard input.
+// Stdout: Optional writer receiving the command's standard output. If
+// nil, the output is returned in ExecResult.Stdout.
+// Stderr: Optional writer receiving the command's standard error. If nil,
+// the output is returned in ExecResult.Stderr.
+// TTY: Whether to allocate a TTY for the command. With a TTY, standard
+// error is merged into standard output.
+type ExecOptions struct {
+	Container string
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	TTY       bool
+}
+
+// ExecResult is the result of a command run by ExecIntoPod.
+//
+// **Attributes:**
+//
+// Stdout: The command's standard output, unless ExecOptions.Stdout was
+// set.
+// Stderr: The command's standard error, unless ExecOptions.Stderr was set.
+// ExitCode: The command's exit code.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// NewPodsClient creates a PodsClient using the provided KubernetesClient.
+//
+// **Parameters:**
+//
+// kc: The KubernetesClient used to access the Kubernetes API.
+//
+// **Returns:**
+//
+// *PodsClient: A PodsClient using the client-go SPDY executor.
+func NewPodsClient(kc *client.KubernetesClient) *PodsClient {
+	return &PodsClient{
+		Client:          kc,
+		ExecutorCreator: &dynK8s.DefaultExecutorCreator{},
+	}
+}
+
+// ListPods lists the pods in a namespace, optionally filtered by a label
+// selector.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// namespace: The namespace to list pods in. Pods in all namespaces are
+// listed if empty.
+// labelSelector: An optional label selector, e.g., "app=web".
+//
+// **Returns:**
+//
+// []corev1.Pod: The pods found.
+// error: An error if the pods can't be listed.
+func (pc *PodsClient) ListPods(ctx context.Context, namespace, labelSelector string) ([]corev1.Pod, error) {
+	if err := pc.validate(); err != nil {
+		return nil, err
+	}
+
+	pods, err := pc.Client.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace '%s': %w", namespace, err)
+	}
+
+	return pods.Items, nil
+}
+
+// GetPod retrieves a pod by name.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the pod.
+// namespace: The namespace of the pod.
+//
+// **Returns:**
+//
+// *corev1.Pod: The pod.
+// error: An error if the pod can't be retrieved.
+func (pc *PodsClient) GetPod(ctx context.Context, name, namespace string) (*corev1.Pod, error) {
+	if err := pc.validate(); err != nil {
+		return nil, err
+	}
+
+	pod, err := pc.Client.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	return pod, nil
+}
+
+// DeletePod deletes a pod by name.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the pod.
+// namespace: The namespace of the pod.
+// gracePeriod: Optional duration the pod is given to terminate. The pod's
+// terminationGracePeriodSeconds is used if nil, and zero deletes the pod
+// immediately.
+//
+// **Returns:**
+//
+// error: An error if the pod can't be deleted.
+func (pc *PodsClient) DeletePod(ctx context.Context, name, namespace string, gracePeriod *time.Duration) error {
+	if err := pc.validate(); err != nil {
+		return err
+	}
+
+	opts := metav1.DeleteOptions{}
+	if gracePeriod != nil {
+		seconds := int64(gracePeriod.Seconds())
+		opts.GracePeriodSeconds = &seconds
+	}
+
+	if err := pc.Client.Clientset.CoreV1().Pods(namespace).Delete(ctx, name, opts); err != nil {
+		return fmt.Errorf("failed to delete pod '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	return nil
+}
+
+// ExecIntoPod runs a command in a pod's container over SPDY, in the same
+// way as `kubectl exec`, and waits for it to exit.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation. Canceling it
+// terminates the stream.
+// name: The name of the pod.
+// namespace: The namespace of the pod.
+// command: The command to run and its arguments.
+// opts: The container and streams to use.
+//
+// **Returns:**
+//
+// *ExecResult: The command's output and exit code. It's returned along
+// with the error when the command exits with a non-zero code.
+// error: An error if the command can't be run or exits with a non-zero
+// code.
+func (pc *PodsClient) ExecIntoPod(ctx context.Context, name, namespace string, command []string, opts ExecOptions) (*ExecResult, error) {
+	if err := pc.validate(); err != nil {
+		return nil, err
+	}
+	if len(command) == 0 {
+		return nil, errors.New("command cannot be empty")
+	}
+	if pc.Client.Config == nil {
+		return nil, errors.New("kubernetes REST config is not initialized")
+	}
+
+	var stdout, stderr bytes.Buffer
+	streams := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Stderr: opts.Stderr,
+		Tty:    opts.TTY,
+	}
+	if streams.Stdout == nil {
+		streams.Stdout = &stdout
+	}
+	// Standard error can't be requested along with a TTY.
+	if streams.Stderr == nil && !opts.TTY {
+		streams.Stderr = &stderr
+	}
+
+	req := pc.Client.Clientset.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Name(name).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   command,
+			Stdin:     streams.Stdin != nil,
+			Stdout:    true,
+			Stderr:    streams.Stderr != nil,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	creator := pc.ExecutorCreator
+	if creator == nil {
+		creator = &dynK8s.DefaultExecutorCreator{}
+	}
+	executor, err := creator.NewSPDYExecutor(pc.Client.Config, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize command executor: %w", err)
+	}
+
+	err = executor.StreamWithContext(ctx, streams)
+	result := &ExecResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		var exitErr exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.Exited() {
+			result.ExitCode = exitErr.ExitStatus()
+			return result, fmt.Errorf("command in pod '%s' exited with code %d: %w", name, result.ExitCode, err)
+		}
+		return nil, fmt.Errorf("failed to execute command in pod '%s': %w", name, err)
+	}
+
+	return result, nil
+}
+
+// WaitForPodReady waits until a pod's Ready condition is True. A pod that
+// doesn't exist yet is waited for.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// name: The name of the pod.
+// namespace: The namespace of the pod.
+// timeout: The maximum time to wait for the pod to become ready.
+// interval: How often the pod is checked.
+//
+// **Returns:**
+//
+// error: An error if the pod can't be read, terminates, or doesn't become
+// ready before the timeout.
+func (pc *PodsClient) WaitForPodReady(ctx context.Context, name, namespace string, timeout, interval time.Duration) error {
+	if err := pc.validate(); err != nil {
+		return err
+	}
+
+	var phase corev1.PodPhase
+	get := func(ctx context.Context) (*corev1.Pod, error) {
+		pod, err := pc.Client.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			phase = pod.Status.Phase
+		}
+		return pod, err
+	}
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	err := k8swait.WaitFor(ctx, pc.Client, podsGVR, name, namespace, k8swait.PodReady(),
+		k8swait.WithTypedGetter(get), k8swait.WithTimeout(timeout), k8swait.WithPollInterval(interval))
+	if err != nil {
+		if phase != "" {
+			return fmt.Errorf("pod '%s' in namespace '%s' did not become ready (phase %s): %w", name, namespace, phase, err)
+		}
+		return fmt.Errorf("pod '%s' in namespace '%s' did not become ready: %w", name, namespace, err)
+	}
+
+	return nil
+}
+
+// validate checks that the client can reach the Kubernetes API.
+func (pc *PodsClient) validate() error {
+	if pc.Client == nil || pc.Client.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	pods "github.com/l50/goutils/v2/k8s/pods"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+func newPod(name string, labels map[string]string, phase corev1.PodPhase, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestListGetDeletePods(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		newPod("web-1", map[string]string{"app": "web"}, corev1.PodRunning, true),
+		newPod("web-2", map[string]string{"app": "web"}, corev1.PodRunning, true),
+		newPod("db-1", map[string]string{"app": "db"}, corev1.PodRunning, true),
+	)
+	pc := pods.NewPodsClient(&client.KubernetesClient{Clientset: clientset})
+	ctx := context.Background()
+
+	listed, err := pc.ListPods(ctx, "default", "app=web")
+	require.NoError(t, err)
+	var names []string
+	for _, pod := range listed {
+		names = append(names, pod.Name)
+	}
+	require.ElementsMatch(t, []string{"web-1", "web-2"}, names)
+
+	pod, err := pc.GetPod(ctx, "db-1", "default")
+	require.NoError(t, err)
+	require.Equal(t, "db", pod.Labels["app"])
+
+	grace := 5 * time.Second
+	require.NoError(t, pc.DeletePod(ctx, "db-1", "default", &grace))
+	var deleteOpts metav1.DeleteOptions
+	for _, action := range clientset.Actions() {
+		if del, ok := action.(k8stesting.DeleteActionImpl); ok {
+			deleteOpts = del.DeleteOptions
+		}
+	}
+	require.NotNil(t, deleteOpts.GracePeriodSeconds)
+	require.Equal(t, int64(5), *deleteOpts.GracePeriodSeconds)
+
+	_, err = pc.GetPod(ctx, "db-1", "default")
+	require.Error(t, err)
+	require.Error(t, pc.DeletePod(ctx, "db-1", "default", nil))
+
+	_, err = (&pods.PodsClient{}).ListPods(ctx, "default", "")
+	require.EqualError(t, err, "kubernetes client is not initialized")
+}
+
+func TestWaitForPodReady(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		becomeReady bool
+		expectedErr string
+	}{
+		{
+			name: "Already ready",
+			pod:  newPod("app", nil, corev1.PodRunning, true),
+		},
+		{
+			name:        "Becomes ready",
+			pod:         newPod("app", nil, corev1.PodPending, false),
+			becomeReady: true,
+		},
+		{
+			name:        "Pod failed",
+			pod:         newPod("app", nil, corev1.PodFailed, false),
+			expectedErr: "pod 'app' in namespace 'default' did not become ready (phase Failed): pods default/app can't become ready: pod terminated with phase Failed",
+		},
+		{
+			name:        "Timeout",
+			pod:         newPod("app", nil, corev1.PodRunning, false),
+			expectedErr: "pod 'app' in namespace 'default' did not become ready (phase Running): pods default/app didn't become ready: context deadline exceeded",
+		},
+		{
+			name:        "Missing pod",
+			expectedErr: "pod 'app' in namespace 'default' did not become ready: pods default/app didn't become ready: context deadline exceeded",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if tc.pod != nil {
+				clientset = fake.NewSimpleClientset(tc.pod)
+			}
+			if tc.becomeReady {
+				gets := 0
+				clientset.PrependReactor("get", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+					gets++
+					return true, newPod("app", nil, corev1.PodRunning, gets > 2), nil
+				})
+			}
+			pc := pods.NewPodsClient(&client.KubernetesClient{Clientset: clientset})
+
+			err := pc.WaitForPodReady(context.Background(), "app", "default", 200*time.Millisecond, 10*time.Millisecond)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+type fakeExecutor struct {
+	stdout, stderr string
+	err            error
+}
+
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+func (e *fakeExecutor) StreamWithContext(_ context.Context, options remotecommand.StreamOptions) error {
+	if options.Stdout != nil {
+		fmt.Fprint(options.Stdout, e.stdout)
+	}
+	if options.Stderr != nil {
+		fmt.Fprint(options.Stderr, e.stderr)
+	}
+	return e.err
+}
+
+type fakeExecutorCreator struct {
+	executor *fakeExecutor
+	url      *url.URL
+}
+
+func (c *fakeExecutorCreator) NewSPDYExecutor(_ *rest.Config, _ string, u *url.URL) (remotecommand.Executor, error) {
+	c.url = u
+	return c.executor, nil
+}
+
+func TestExecIntoPod(t *testing.T) {
+	tests := []struct {
+		name          string
+		command       []string
+		opts          pods.ExecOptions
+		executor      *fakeExecutor
+		expected      *pods.ExecResult
+		expectedQuery []string
+		expectedErr   string
+	}{
+		{
+			name:          "Captures output",
+			command:       []string{"cat", "/etc/hostname"},
+			opts:          pods.ExecOptions{Container: "app"},
+			executor:      &fakeExecutor{stdout: "web-1\n", stderr: "warning\n"},
+			expected:      &pods.ExecResult{Stdout: "web-1\n", Stderr: "warning\n"},
+			expectedQuery: []string{"command=cat", "command=%2Fetc%2Fhostname", "container=app", "stdout=true", "stderr=true"},
+		},
+		{
+			name:          "TTY merges stderr",
+			command:       []string{"sh"},
+			opts:          pods.ExecOptions{TTY: true, Stdin: strings.NewReader("exit\n")},
+			executor:      &fakeExecutor{stdout: "$ "},
+			expected:      &pods.ExecResult{Stdout: "$ "},
+			expectedQuery: []string{"stdin=true", "tty=true"},
+		},
+		{
+			name:        "Non-zero exit code",
+			command:     []string{"false"},
+			executor:    &fakeExecutor{stderr: "failed", err: exec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3}},
+			expected:    &pods.ExecResult{Stderr: "failed", ExitCode: 3},
+			expectedErr: "exited with code 3",
+		},
+		{
+			name:        "Stream failure",
+			command:     []string{"true"},
+			executor:    &fakeExecutor{err: errors.New("connection refused")},
+			expectedErr: "failed to execute command in pod 'web-1': connection refused",
+		},
+		{
+			name:        "Empty command",
+			executor:    &fakeExecutor{},
+			expectedErr: "command cannot be empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &rest.Config{Host: "https://127.0.0.1:6443"}
+			clientset, err := kubernetes.NewForConfig(config)
+			require.NoError(t, err)
+			creator := &fakeExecutorCreator{executor: tc.executor}
+			pc := &pods.PodsClient{
+				Client:          &client.KubernetesClient{Clientset: clientset, Config: config},
+				ExecutorCreator: creator,
+			}
+
+			result, err := pc.ExecIntoPod(context.Background(), "web-1", "default", tc.command, tc.opts)
+			require.Equal(t, tc.expected, result)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, "/api/v1/namespaces/default/pods/web-1/exec", creator.url.Path)
+			for _, param := range tc.expectedQuery {
+				require.Contains(t, creator.url.RawQuery, param)
+			}
+		})
+	}
+}
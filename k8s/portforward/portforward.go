@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/l50/goutils/v2/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// ForwardPort forwards a local port to a port of a pod, or of a pod
+// backing a service, using client-go's SPDY transport in the same way as
+// `kubectl port-forward`. This allows integration tests to reach
+// workloads that aren't exposed outside of the cluster. For services,
+// remotePort is a service port, which is mapped to the target port of
+// the first running and ready pod selected by the service.
+//
+// ForwardPort returns once the local port is listening, so the ready
+// channel it returns is already closed; it's returned for callers that
+// hand it to code expecting a readiness signal. Forwarding stops when
+// the stop channel is closed or ctx is canceled. Errors occurring after
+// that point, such as a lost connection to the pod, are written to the
+// logger returned by logging.FromContext(ctx).
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the forward. Canceling it stops
+// forwarding.
+// kc: The KubernetesClient used to find the pod and open the forward.
+// namespace: The namespace of the pod or service.
+// podOrService: The target: "pod/<name>", "svc/<name>",
+// "service/<name>", or a bare pod name.
+// localPort: The local port to listen on, on localhost.
+// remotePort: The port of the pod or service to forward to.
+//
+// **Returns:**
+//
+// chan struct{}: The stop channel. Close it to stop forwarding.
+// chan struct{}: The ready channel, closed once the local port is
+// listening.
+// error: An error if the target can't be resolved or the forward can't
+// be established.
+func ForwardPort(ctx context.Context, kc *client.KubernetesClient, namespace, podOrService string, localPort, remotePort int) (chan struct{}, chan struct{}, error) {
+	if kc == nil || kc.Clientset == nil || kc.Config == nil {
+		return nil, nil, errors.New("kubernetes client is not initialized")
+	}
+	if localPort <= 0 || localPort > 65535 || remotePort <= 0 || remotePort > 65535 {
+		return nil, nil, fmt.Errorf("invalid ports %d:%d", localPort, remotePort)
+	}
+
+	podName, podPort, err := resolveTarget(ctx, kc, namespace, podOrService, remotePort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(kc.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create SPDY transport: %v", err)
+	}
+	forwardURL, err := url.Parse(kc.Config.Host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid API server host %q: %v", kc.Config.Host, err)
+	}
+	forwardURL.Path = path.Join(forwardURL.Path, "api", "v1", "namespaces", namespace, "pods", podName, "portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, forwardURL)
+
+	// The forwarder stops on its own channel so that the caller closing
+	// stop and ctx being canceled can't close the same channel twice.
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	forwarderStop := make(chan struct{})
+	var errOut strings.Builder
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"},
+		[]string{fmt.Sprintf("%d:%d", localPort, podPort)}, forwarderStop, ready, io.Discard, &errOut)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create port forward: %v", err)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		close(forwarderStop)
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+
+	target := fmt.Sprintf("%s/%s port %d", namespace, podName, podPort)
+	select {
+	case <-ready:
+	case err := <-done:
+		close(stop)
+		if err == nil {
+			err = errors.New("stopped before it was ready")
+		}
+		if msg := strings.TrimSpace(errOut.String()); msg != "" {
+			return nil, nil, fmt.Errorf("failed to forward localhost:%d to %s: %v: %s", localPort, target, err, msg)
+		}
+		return nil, nil, fmt.Errorf("failed to forward localhost:%d to %s: %v", localPort, target, err)
+	}
+
+	go func() {
+		if err := <-done; err != nil {
+			logging.FromContext(ctx).Warnf("port forward from localhost:%d to %s stopped: %v", localPort, target, err)
+		}
+	}()
+
+	return stop, ready, nil
+}
+
+// resolveTarget returns the pod to forward to and the port of the pod
+// corresponding to remotePort.
+func resolveTarget(ctx context.Context, kc *client.KubernetesClient, namespace, podOrService string, remotePort int) (string, int, error) {
+	kind, name, found := strings.Cut(podOrService, "/")
+	if !found {
+		kind, name = "pod", podOrService
+	}
+	if name == "" {
+		return "", 0, fmt.Errorf("invalid target %q", podOrService)
+	}
+
+	pods := kc.Clientset.CoreV1().Pods(namespace)
+	switch strings.ToLower(kind) {
+	case "pod", "pods", "po":
+		pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get pod '%s' in namespace '%s': %w", name, namespace, err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return "", 0, fmt.Errorf("pod '%s' is not running, current phase: %s", name, pod.Status.Phase)
+		}
+		return name, remotePort, nil
+	case "svc", "service", "services":
+		svc, err := kc.Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get service '%s' in namespace '%s': %w", name, namespace, err)
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return "", 0, fmt.Errorf("service '%s' has no pod selector", name)
+		}
+
+		list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to list pods of service '%s': %w", name, err)
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if pod.Status.Phase == corev1.PodRunning && client.IsPodReady(pod) {
+				port, err := servicePodPort(svc, pod, remotePort)
+				if err != nil {
+					return "", 0, err
+				}
+				return pod.Name, port, nil
+			}
+		}
+		return "", 0, fmt.Errorf("service '%s' has no running and ready pods", name)
+	default:
+		return "", 0, fmt.Errorf("unsupported target kind %q: must be pod or service", kind)
+	}
+}
+
+// servicePodPort maps a service port to the port of the pod it targets.
+func servicePodPort(svc *corev1.Service, pod *corev1.Pod, remotePort int) (int, error) {
+	for _, port := range svc.Spec.Ports {
+		if int(port.Port) != remotePort {
+			continue
+		}
+
+		target := port.TargetPort
+		switch {
+		case target.StrVal != "":
+			for _, container := range pod.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == target.StrVal {
+						return int(containerPort.ContainerPort), nil
+					}
+				}
+			}
+			return 0, fmt.Errorf("pod '%s' has no port named %q", pod.Name, target.StrVal)
+		case target.IntVal != 0:
+			return int(target.IntVal), nil
+		default:
+			// An unset target port defaults to the service port.
+			return remotePort, nil
+		}
+	}
+
+	return 0, fmt.Errorf("service '%s' has no port %d", svc.Name, remotePort)
+}
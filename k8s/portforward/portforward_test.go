@@ -0,0 +1,266 @@
+package k8s_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	portforward "github.com/l50/goutils/v2/k8s/portforward"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// echoPortForwardServer serves the pod portforward subresource over SPDY
+// and echoes the data sent to any port, recording the forwarded ports.
+type echoPortForwardServer struct {
+	mu    sync.Mutex
+	paths []string
+	ports []string
+}
+
+func (s *echoPortForwardServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if _, err := httpstream.Handshake(req, w, []string{"portforward.k8s.io"}); err != nil {
+		return
+	}
+
+	streams := make(chan httpstream.Stream, 8)
+	conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(stream httpstream.Stream, _ <-chan struct{}) error {
+		streams <- stream
+		return nil
+	})
+	if conn == nil {
+		return
+	}
+	defer conn.Close()
+
+	s.mu.Lock()
+	s.paths = append(s.paths, req.URL.Path)
+	s.mu.Unlock()
+
+	errorStreams := make(map[string]httpstream.Stream)
+	for {
+		select {
+		case <-conn.CloseChan():
+			return
+		case stream := <-streams:
+			headers := stream.Headers()
+			requestID := headers.Get(corev1.PortForwardRequestIDHeader)
+			if headers.Get(corev1.StreamType) == corev1.StreamTypeError {
+				errorStreams[requestID] = stream
+				continue
+			}
+
+			s.mu.Lock()
+			s.ports = append(s.ports, headers.Get(corev1.PortHeader))
+			s.mu.Unlock()
+			errorStream := errorStreams[requestID]
+			go func() {
+				_, _ = io.Copy(stream, stream)
+				stream.Close()
+				if errorStream != nil {
+					errorStream.Close()
+				}
+			}()
+		}
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func runningPod(name string, ready bool, podLabels map[string]string) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "app",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestForwardPort(t *testing.T) {
+	objects := []runtime.Object{
+		runningPod("web-0", false, map[string]string{"app": "web"}),
+		runningPod("web-1", true, map[string]string{"app": "web"}),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "web"},
+				Ports: []corev1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromString("http")},
+					{Port: 9090, TargetPort: intstr.FromInt32(9091)},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "db"}},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		target       string
+		remotePort   int
+		expectedPath string
+		expectedPort string
+		expectedErr  string
+	}{
+		{
+			name:         "Pod",
+			target:       "pod/web-1",
+			remotePort:   8080,
+			expectedPath: "/api/v1/namespaces/default/pods/web-1/portforward",
+			expectedPort: "8080",
+		},
+		{
+			name:         "Bare pod name",
+			target:       "web-0",
+			remotePort:   5000,
+			expectedPath: "/api/v1/namespaces/default/pods/web-0/portforward",
+			expectedPort: "5000",
+		},
+		{
+			name:         "Service with named target port",
+			target:       "svc/web",
+			remotePort:   80,
+			expectedPath: "/api/v1/namespaces/default/pods/web-1/portforward",
+			expectedPort: "8080",
+		},
+		{
+			name:         "Service with numeric target port",
+			target:       "service/web",
+			remotePort:   9090,
+			expectedPath: "/api/v1/namespaces/default/pods/web-1/portforward",
+			expectedPort: "9091",
+		},
+		{
+			name:        "Pod not running",
+			target:      "pod/pending",
+			remotePort:  80,
+			expectedErr: "pod 'pending' is not running",
+		},
+		{
+			name:        "Missing pod",
+			target:      "pod/missing",
+			remotePort:  80,
+			expectedErr: "failed to get pod 'missing'",
+		},
+		{
+			name:        "Unknown service port",
+			target:      "svc/web",
+			remotePort:  443,
+			expectedErr: "service 'web' has no port 443",
+		},
+		{
+			name:        "Service without ready pods",
+			target:      "svc/db",
+			remotePort:  5432,
+			expectedErr: "service 'db' has no running and ready pods",
+		},
+		{
+			name:        "Unsupported kind",
+			target:      "deployment/web",
+			remotePort:  80,
+			expectedErr: "unsupported target kind",
+		},
+		{
+			name:        "Invalid port",
+			target:      "pod/web-1",
+			expectedErr: "invalid ports",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &echoPortForwardServer{}
+			apiServer := httptest.NewServer(server)
+			defer apiServer.Close()
+
+			kc := &client.KubernetesClient{
+				Clientset: fake.NewSimpleClientset(objects...),
+				Config:    &rest.Config{Host: apiServer.URL},
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			localPort := freePort(t)
+			stop, ready, err := portforward.ForwardPort(ctx, kc, "default", tc.target, localPort, tc.remotePort)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer close(stop)
+
+			select {
+			case <-ready:
+			default:
+				t.Fatal("ready channel should be closed once ForwardPort returns")
+			}
+
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(localPort)), 5*time.Second)
+			require.NoError(t, err)
+			defer conn.Close()
+			require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+			_, err = conn.Write([]byte("ping"))
+			require.NoError(t, err)
+			reply := make([]byte, 4)
+			_, err = io.ReadFull(conn, reply)
+			require.NoError(t, err)
+			require.Equal(t, "ping", string(reply))
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			require.Equal(t, []string{tc.expectedPath}, server.paths)
+			require.Equal(t, []string{tc.expectedPort}, server.ports)
+		})
+	}
+}
+
+func TestForwardPortUnreachableAPIServer(t *testing.T) {
+	apiServer := httptest.NewServer(http.NotFoundHandler())
+	apiServer.Close()
+
+	kc := &client.KubernetesClient{
+		Clientset: fake.NewSimpleClientset(runningPod("web-1", true, nil)),
+		Config:    &rest.Config{Host: apiServer.URL},
+	}
+
+	_, _, err := portforward.ForwardPort(context.Background(), kc, "default", "pod/web-1", freePort(t), 8080)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to forward")
+	require.Contains(t, err.Error(), "default/web-1 port 8080")
+}
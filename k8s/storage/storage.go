@@ -0,0 +1,329 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	k8swait "github.com/l50/goutils/v2/k8s/wait"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultClassAnnotation marks the default storage class of a cluster.
+const defaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// PVCSpec describes a PersistentVolumeClaim created by CreatePVC.
+//
+// **Attributes:**
+//
+// Name: The name of the claim.
+// Namespace: The namespace of the claim.
+// Size: The requested storage, e.g., "1Gi".
+// StorageClass: Optional storage class. The cluster's default storage
+// class is used if empty.
+// AccessModes: The access modes of the claim. Defaults to ReadWriteOnce.
+// VolumeMode: Optional volume mode, e.g., corev1.PersistentVolumeBlock.
+// Labels: Optional labels added to the claim.
+type PVCSpec struct {
+	Name         string
+	Namespace    string
+	Size         string
+	StorageClass string
+	AccessModes  []corev1.PersistentVolumeAccessMode
+	VolumeMode   *corev1.PersistentVolumeMode
+	Labels       map[string]string
+}
+
+// OrphanedPV describes a PersistentVolume whose claim no longer exists.
+// Such volumes keep consuming storage until they're deleted.
+//
+// **Attributes:**
+//
+// Name: The name of the volume.
+// Phase: The phase of the volume, e.g., "Released".
+// Claim: The namespace and name of the claim the volume was bound to,
+// e.g., "default/data". Empty if it was never bound.
+// StorageClass: The storage class of the volume.
+// Capacity: The capacity of the volume, e.g., "10Gi".
+// ReclaimPolicy: What happens to the volume once it's released.
+// Reason: Why the volume is considered orphaned.
+type OrphanedPV struct {
+	Name          string
+	Phase         corev1.PersistentVolumePhase
+	Claim         string
+	StorageClass  string
+	Capacity      string
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy
+	Reason        string
+}
+
+// ListStorageClasses lists the storage classes of a cluster, sorted by
+// name.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to list the storage classes.
+//
+// **Returns:**
+//
+// []storagev1.StorageClass: The storage classes.
+// error: An error if the storage classes can't be listed.
+func ListStorageClasses(ctx context.Context, kc *client.KubernetesClient) ([]storagev1.StorageClass, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+
+	classes, err := kc.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+	sort.Slice(classes.Items, func(i, j int) bool { return classes.Items[i].Name < classes.Items[j].Name })
+
+	return classes.Items, nil
+}
+
+// DefaultStorageClass returns the name of the cluster's default storage
+// class, which is used by claims that don't request one.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to list the storage classes.
+//
+// **Returns:**
+//
+// string: The name of the default storage class.
+// error: An error if the storage classes can't be listed or none is
+// marked as the default.
+func DefaultStorageClass(ctx context.Context, kc *client.KubernetesClient) (string, error) {
+	classes, err := ListStorageClasses(ctx, kc)
+	if err != nil {
+		return "", err
+	}
+
+	for _, class := range classes {
+		if class.Annotations[defaultClassAnnotation] == "true" {
+			return class.Name, nil
+		}
+	}
+
+	return "", errors.New("no default storage class found")
+}
+
+// CreatePVC creates a PersistentVolumeClaim from a typed spec.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to create the claim.
+// spec: The claim to create.
+//
+// **Returns:**
+//
+// *corev1.PersistentVolumeClaim: The created claim.
+// error: An error if the spec is invalid or the claim can't be created.
+func CreatePVC(ctx context.Context, kc *client.KubernetesClient, spec PVCSpec) (*corev1.PersistentVolumeClaim, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+	if spec.Name == "" || spec.Namespace == "" {
+		return nil, errors.New("claim name and namespace are required")
+	}
+	size, err := resource.ParseQuantity(spec.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size %q for claim '%s': %v", spec.Size, spec.Name, err)
+	}
+
+	accessModes := spec.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			Labels:    spec.Labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			VolumeMode:  spec.VolumeMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if spec.StorageClass != "" {
+		pvc.Spec.StorageClassName = &spec.StorageClass
+	}
+
+	created, err := kc.Clientset.CoreV1().PersistentVolumeClaims(spec.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create claim '%s' in namespace '%s': %w", spec.Name, spec.Namespace, err)
+	}
+
+	return created, nil
+}
+
+// DeletePVC deletes a PersistentVolumeClaim. Whether its volume is
+// deleted too depends on the volume's reclaim policy.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to delete the claim.
+// name: The name of the claim.
+// namespace: The namespace of the claim.
+//
+// **Returns:**
+//
+// error: An error if the claim can't be deleted. Errors wrap the error
+// from client-go, so a missing claim can be detected with
+// apierrors.IsNotFound.
+func DeletePVC(ctx context.Context, kc *client.KubernetesClient, name, namespace string) error {
+	if kc == nil || kc.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+
+	if err := kc.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete claim '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	return nil
+}
+
+// WaitForPVCBound waits until a PersistentVolumeClaim is bound to a
+// volume. Claims using a storage class with the WaitForFirstConsumer
+// binding mode aren't bound until a pod using them is scheduled. A claim
+// that doesn't exist yet is waited for.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// kc: The KubernetesClient used to read the claim.
+// name: The name of the claim.
+// namespace: The namespace of the claim.
+// timeout: The maximum time to wait for the claim to be bound.
+// interval: How often the claim is checked.
+//
+// **Returns:**
+//
+// error: An error if the claim can't be read, is lost, or isn't bound
+// before the timeout.
+func WaitForPVCBound(ctx context.Context, kc *client.KubernetesClient, name, namespace string, timeout, interval time.Duration) error {
+	if kc == nil || kc.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+
+	var phase corev1.PersistentVolumeClaimPhase
+	get := func(ctx context.Context) (*corev1.PersistentVolumeClaim, error) {
+		pvc, err := kc.Clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			phase = pvc.Status.Phase
+		}
+		return pvc, err
+	}
+	pvcsGVR := schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+	err := k8swait.WaitFor(ctx, kc, pvcsGVR, name, namespace, k8swait.PVCBound(),
+		k8swait.WithTypedGetter(get), k8swait.WithTimeout(timeout), k8swait.WithPollInterval(interval))
+	if err != nil {
+		if phase != "" {
+			return fmt.Errorf("claim '%s' in namespace '%s' was not bound (phase %s): %w", name, namespace, phase, err)
+		}
+		return fmt.Errorf("claim '%s' in namespace '%s' was not bound: %w", name, namespace, err)
+	}
+
+	return nil
+}
+
+// FindOrphanedPVs reports the PersistentVolumes whose claim no longer
+// exists: released volumes retained after their claim was deleted, and
+// bound volumes whose claim is missing. This helps clean up the storage
+// left behind by test workloads.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// kc: The KubernetesClient used to list the volumes and claims.
+//
+// **Returns:**
+//
+// []OrphanedPV: The orphaned volumes, sorted by name.
+// error: An error if the volumes or claims can't be listed.
+func FindOrphanedPVs(ctx context.Context, kc *client.KubernetesClient) ([]OrphanedPV, error) {
+	if kc == nil || kc.Clientset == nil {
+		return nil, errors.New("kubernetes client is not initialized")
+	}
+
+	pvs, err := kc.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+
+	var orphaned []OrphanedPV
+	for _, pv := range pvs.Items {
+		reason, err := orphanReason(ctx, kc, pv)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			continue
+		}
+
+		info := OrphanedPV{
+			Name:          pv.Name,
+			Phase:         pv.Status.Phase,
+			StorageClass:  pv.Spec.StorageClassName,
+			ReclaimPolicy: pv.Spec.PersistentVolumeReclaimPolicy,
+			Reason:        reason,
+		}
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			info.Claim = ref.Namespace + "/" + ref.Name
+		}
+		if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+			info.Capacity = capacity.String()
+		}
+		orphaned = append(orphaned, info)
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].Name < orphaned[j].Name })
+
+	return orphaned, nil
+}
+
+// orphanReason returns why a volume is orphaned, or an empty string if it
+// isn't.
+func orphanReason(ctx context.Context, kc *client.KubernetesClient, pv corev1.PersistentVolume) (string, error) {
+	switch pv.Status.Phase {
+	case corev1.VolumeReleased:
+		return "claim was deleted", nil
+	case corev1.VolumeFailed:
+		return "reclamation failed", nil
+	case corev1.VolumeBound:
+		ref := pv.Spec.ClaimRef
+		if ref == nil {
+			return "", nil
+		}
+		pvc, err := kc.Clientset.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return "bound claim doesn't exist", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get claim '%s' in namespace '%s': %w", ref.Name, ref.Namespace, err)
+		}
+		if ref.UID != "" && pvc.UID != ref.UID {
+			return "bound claim was recreated", nil
+		}
+		return "", nil
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,238 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	storage "github.com/l50/goutils/v2/k8s/storage"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func storageClass(name string, isDefault bool) *storagev1.StorageClass {
+	class := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: "example.com/csi"}
+	if isDefault {
+		class.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+	}
+	return class
+}
+
+func TestStorageClasses(t *testing.T) {
+	tests := []struct {
+		name            string
+		classes         []runtime.Object
+		expectedNames   []string
+		expectedDefault string
+		expectedErr     string
+	}{
+		{
+			name:            "Default class",
+			classes:         []runtime.Object{storageClass("standard", true), storageClass("fast", false)},
+			expectedNames:   []string{"fast", "standard"},
+			expectedDefault: "standard",
+		},
+		{
+			name:          "No default class",
+			classes:       []runtime.Object{storageClass("fast", false)},
+			expectedNames: []string{"fast"},
+			expectedErr:   "no default storage class found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset(tc.classes...)}
+			ctx := context.Background()
+
+			classes, err := storage.ListStorageClasses(ctx, kc)
+			require.NoError(t, err)
+			var names []string
+			for _, class := range classes {
+				names = append(names, class.Name)
+			}
+			require.Equal(t, tc.expectedNames, names)
+
+			def, err := storage.DefaultStorageClass(ctx, kc)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDefault, def)
+		})
+	}
+}
+
+func TestCreateAndDeletePVC(t *testing.T) {
+	block := corev1.PersistentVolumeBlock
+	tests := []struct {
+		name        string
+		spec        storage.PVCSpec
+		check       func(t *testing.T, pvc *corev1.PersistentVolumeClaim)
+		expectedErr string
+	}{
+		{
+			name: "Defaults",
+			spec: storage.PVCSpec{Name: "data", Namespace: "default", Size: "1Gi"},
+			check: func(t *testing.T, pvc *corev1.PersistentVolumeClaim) {
+				require.Nil(t, pvc.Spec.StorageClassName)
+				require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, pvc.Spec.AccessModes)
+				require.True(t, resource.MustParse("1Gi").Equal(pvc.Spec.Resources.Requests[corev1.ResourceStorage]))
+			},
+		},
+		{
+			name: "Full spec",
+			spec: storage.PVCSpec{
+				Name:         "shared",
+				Namespace:    "tests",
+				Size:         "500Mi",
+				StorageClass: "fast",
+				AccessModes:  []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				VolumeMode:   &block,
+				Labels:       map[string]string{"run": "42"},
+			},
+			check: func(t *testing.T, pvc *corev1.PersistentVolumeClaim) {
+				require.Equal(t, "fast", *pvc.Spec.StorageClassName)
+				require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pvc.Spec.AccessModes)
+				require.Equal(t, block, *pvc.Spec.VolumeMode)
+				require.Equal(t, "42", pvc.Labels["run"])
+			},
+		},
+		{
+			name:        "Invalid size",
+			spec:        storage.PVCSpec{Name: "data", Namespace: "default", Size: "lots"},
+			expectedErr: `invalid size "lots"`,
+		},
+		{
+			name:        "Missing name",
+			spec:        storage.PVCSpec{Namespace: "default", Size: "1Gi"},
+			expectedErr: "claim name and namespace are required",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := &client.KubernetesClient{Clientset: fake.NewSimpleClientset()}
+			ctx := context.Background()
+
+			pvc, err := storage.CreatePVC(ctx, kc, tc.spec)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, pvc)
+
+			require.NoError(t, storage.DeletePVC(ctx, kc, tc.spec.Name, tc.spec.Namespace))
+			err = storage.DeletePVC(ctx, kc, tc.spec.Name, tc.spec.Namespace)
+			require.True(t, apierrors.IsNotFound(err), "expected a wrapped NotFound error, got %v", err)
+		})
+	}
+}
+
+func TestWaitForPVCBound(t *testing.T) {
+	claim := func(phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		pvc         *corev1.PersistentVolumeClaim
+		bindAfter   int
+		expectedErr string
+	}{
+		{name: "Already bound", pvc: claim(corev1.ClaimBound)},
+		{name: "Becomes bound", pvc: claim(corev1.ClaimPending), bindAfter: 2},
+		{name: "Lost", pvc: claim(corev1.ClaimLost), expectedErr: "claim lost its volume"},
+		{name: "Timeout", pvc: claim(corev1.ClaimPending), expectedErr: "was not bound (phase Pending)"},
+		{name: "Missing claim", expectedErr: "was not bound: "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if tc.pvc != nil {
+				clientset = fake.NewSimpleClientset(tc.pvc)
+			}
+			if tc.bindAfter > 0 {
+				gets := 0
+				clientset.PrependReactor("get", "persistentvolumeclaims", func(k8stesting.Action) (bool, runtime.Object, error) {
+					gets++
+					if gets > tc.bindAfter {
+						return true, claim(corev1.ClaimBound), nil
+					}
+					return true, claim(corev1.ClaimPending), nil
+				})
+			}
+			kc := &client.KubernetesClient{Clientset: clientset}
+
+			err := storage.WaitForPVCBound(context.Background(), kc, "data", "default", 200*time.Millisecond, 10*time.Millisecond)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFindOrphanedPVs(t *testing.T) {
+	pv := func(name string, phase corev1.PersistentVolumePhase, claim string, uid types.UID) *corev1.PersistentVolume {
+		volume := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				StorageClassName:              "standard",
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+		if claim != "" {
+			volume.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "default", Name: claim, UID: uid}
+		}
+		return volume
+	}
+
+	clientset := fake.NewSimpleClientset(
+		pv("pv-bound", corev1.VolumeBound, "data", "uid-1"),
+		pv("pv-released", corev1.VolumeReleased, "old", "uid-2"),
+		pv("pv-missing-claim", corev1.VolumeBound, "gone", "uid-3"),
+		pv("pv-recreated-claim", corev1.VolumeBound, "cache", "uid-4"),
+		pv("pv-available", corev1.VolumeAvailable, "", ""),
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", UID: "uid-1"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default", UID: "uid-5"}},
+	)
+	kc := &client.KubernetesClient{Clientset: clientset}
+
+	orphaned, err := storage.FindOrphanedPVs(context.Background(), kc)
+	require.NoError(t, err)
+	require.Equal(t, []storage.OrphanedPV{
+		{
+			Name: "pv-missing-claim", Phase: corev1.VolumeBound, Claim: "default/gone", StorageClass: "standard",
+			Capacity: "10Gi", ReclaimPolicy: corev1.PersistentVolumeReclaimRetain, Reason: "bound claim doesn't exist",
+		},
+		{
+			Name: "pv-recreated-claim", Phase: corev1.VolumeBound, Claim: "default/cache", StorageClass: "standard",
+			Capacity: "10Gi", ReclaimPolicy: corev1.PersistentVolumeReclaimRetain, Reason: "bound claim was recreated",
+		},
+		{
+			Name: "pv-released", Phase: corev1.VolumeReleased, Claim: "default/old", StorageClass: "standard",
+			Capacity: "10Gi", ReclaimPolicy: corev1.PersistentVolumeReclaimRetain, Reason: "claim was deleted",
+		},
+	}, orphaned)
+}
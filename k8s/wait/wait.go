@@ -0,0 +1,408 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	"github.com/l50/goutils/v2/logging"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Condition describes a state a resource is waited for.
+//
+// **Attributes:**
+//
+// Name: A description of the state used in errors, e.g., "complete".
+// Check: Reports whether the resource is in the state. obj is nil if
+// the resource doesn't exist. Returning an error stops waiting, so it's
+// reserved for states the resource can't recover from, such as a failed
+// job.
+type Condition struct {
+	Name  string
+	Check func(obj *unstructured.Unstructured) (bool, error)
+}
+
+// WaitOptions configures WaitFor.
+//
+// **Attributes:**
+//
+// Timeout: The maximum time to wait. Defaults to 5 minutes.
+// Interval: The initial time between checks. Defaults to 1 second.
+// BackoffFactor: The factor the interval is multiplied by after each
+// check. Values of 1 or less keep the interval constant.
+// MaxInterval: The upper bound of the interval when backing off.
+// Defaults to 30 seconds.
+// Getter: Optional function reading the resource instead of the
+// DynamicClient of the KubernetesClient.
+type WaitOptions struct {
+	Timeout       time.Duration
+	Interval      time.Duration
+	BackoffFactor float64
+	MaxInterval   time.Duration
+	Getter        func(ctx context.Context) (*unstructured.Unstructured, error)
+}
+
+// WaitOption is a function that modifies WaitOptions.
+type WaitOption func(*WaitOptions)
+
+// WithTimeout sets the maximum time to wait.
+//
+// **Parameters:**
+//
+// timeout: The maximum time to wait.
+//
+// **Returns:**
+//
+// WaitOption: The option setting the timeout.
+func WithTimeout(timeout time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithPollInterval sets the time between checks.
+//
+// **Parameters:**
+//
+// interval: The time between checks.
+//
+// **Returns:**
+//
+// WaitOption: The option setting the interval.
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.Interval = interval
+	}
+}
+
+// WithBackoff makes the time between checks grow exponentially, which
+// reduces the load on the API server for long waits.
+//
+// **Parameters:**
+//
+// factor: The factor the interval is multiplied by after each check.
+// maxInterval: The upper bound of the interval.
+//
+// **Returns:**
+//
+// WaitOption: The option enabling backoff.
+func WithBackoff(factor float64, maxInterval time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.BackoffFactor = factor
+		o.MaxInterval = maxInterval
+	}
+}
+
+// WithGetter reads the resource with the input function instead of the
+// DynamicClient, e.g., to wait with a typed Clientset. Typed objects can
+// be converted with runtime.DefaultUnstructuredConverter.
+//
+// **Parameters:**
+//
+// get: Reads the resource. It returns a NotFound API error if the
+// resource doesn't exist.
+//
+// **Returns:**
+//
+// WaitOption: The option setting the getter.
+func WithGetter(get func(ctx context.Context) (*unstructured.Unstructured, error)) WaitOption {
+	return func(o *WaitOptions) {
+		o.Getter = get
+	}
+}
+
+// WithTypedGetter is like WithGetter, but reads the resource as a typed
+// object, such as a *corev1.Pod from a Clientset, which is converted for
+// the condition.
+//
+// **Parameters:**
+//
+// get: Reads the resource. It returns a NotFound API error if the
+// resource doesn't exist.
+//
+// **Returns:**
+//
+// WaitOption: The option setting the getter.
+func WithTypedGetter[T runtime.Object](get func(ctx context.Context) (T, error)) WaitOption {
+	return WithGetter(func(ctx context.Context) (*unstructured.Unstructured, error) {
+		obj, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T: %v", obj, err)
+		}
+		return &unstructured.Unstructured{Object: content}, nil
+	})
+}
+
+// WaitFor waits until a resource meets a condition. Errors reading the
+// resource, other than it not existing, are logged to the logger from
+// logging.FromContext(ctx) and retried at the next check, except for
+// rejected credentials, which are returned right away so that the
+// caller can refresh them (see KubernetesClient.RetryOnUnauthorized).
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// kc: The KubernetesClient whose DynamicClient reads the resource. May
+// be nil if WithGetter is used.
+// gvr: The resource type, e.g., {Group: "batch", Version: "v1",
+// Resource: "jobs"}.
+// name: The name of the resource.
+// namespace: The namespace of the resource. Empty for cluster-scoped
+// resources.
+// condition: The condition to wait for.
+// opts: Optional WaitOption values.
+//
+// **Returns:**
+//
+// error: An error if the condition fails, the credentials are rejected,
+// or the condition isn't met before the timeout or ctx is canceled.
+// Timeouts and cancellations wrap the context's error.
+func WaitFor(ctx context.Context, kc *client.KubernetesClient, gvr schema.GroupVersionResource, name, namespace string, condition Condition, opts ...WaitOption) error {
+	if condition.Check == nil {
+		return errors.New("condition has no check")
+	}
+
+	options := WaitOptions{Timeout: 5 * time.Minute, Interval: time.Second, MaxInterval: 30 * time.Second}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	get := options.Getter
+	if get == nil {
+		if kc == nil || kc.DynamicClient == nil {
+			return errors.New("kubernetes client is not initialized")
+		}
+		get = func(ctx context.Context) (*unstructured.Unstructured, error) {
+			return kc.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	resource := gvr.Resource + " " + name
+	if namespace != "" {
+		resource = fmt.Sprintf("%s %s/%s", gvr.Resource, namespace, name)
+	}
+
+	logger := logging.FromContext(ctx)
+	var lastErr error
+	interval := options.Interval
+	for {
+		obj, err := get(ctx)
+		switch {
+		case apierrors.IsNotFound(err):
+			obj, err = nil, nil
+		case apierrors.IsUnauthorized(err):
+			return fmt.Errorf("failed to get %s: %w", resource, err)
+		case err != nil && ctx.Err() == nil:
+			logger.Warnf("failed to get %s: %v", resource, err)
+			lastErr = err
+		}
+
+		if err == nil {
+			met, checkErr := condition.Check(obj)
+			if checkErr != nil {
+				return fmt.Errorf("%s can't become %s: %w", resource, condition.Name, checkErr)
+			}
+			if met {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%s didn't become %s (last error: %v): %w", resource, condition.Name, lastErr, ctx.Err())
+			}
+			return fmt.Errorf("%s didn't become %s: %w", resource, condition.Name, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		if options.BackoffFactor > 1 {
+			interval = min(time.Duration(float64(interval)*options.BackoffFactor), options.MaxInterval)
+		}
+	}
+}
+
+// Exists returns a Condition met once the resource exists.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func Exists() Condition {
+	return Condition{Name: "present", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		return obj != nil, nil
+	}}
+}
+
+// Deleted returns a Condition met once the resource no longer exists.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func Deleted() Condition {
+	return Condition{Name: "deleted", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		return obj == nil, nil
+	}}
+}
+
+// JobComplete returns a Condition met once a job has completed. It
+// fails if the job fails.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func JobComplete() Condition {
+	return Condition{Name: "complete", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		if obj == nil {
+			return false, nil
+		}
+		if failed, reason := statusCondition(obj, "Failed"); failed {
+			if reason == "" {
+				return false, errors.New("job failed")
+			}
+			return false, fmt.Errorf("job failed: %s", reason)
+		}
+		complete, _ := statusCondition(obj, "Complete")
+		return complete, nil
+	}}
+}
+
+// PodReady returns a Condition met once a pod is ready. It fails if the
+// pod terminates.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func PodReady() Condition {
+	return Condition{Name: "ready", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		if obj == nil {
+			return false, nil
+		}
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "Succeeded" || phase == "Failed" {
+			return false, fmt.Errorf("pod terminated with phase %s", phase)
+		}
+		ready, _ := statusCondition(obj, "Ready")
+		return ready, nil
+	}}
+}
+
+// PVCBound returns a Condition met once a PersistentVolumeClaim is bound
+// to a volume. It fails if the claim loses its volume.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func PVCBound() Condition {
+	return Condition{Name: "bound", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		if obj == nil {
+			return false, nil
+		}
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "Lost" {
+			return false, errors.New("claim lost its volume")
+		}
+		return phase == "Bound", nil
+	}}
+}
+
+// DeploymentAvailable returns a Condition met once a deployment's
+// latest generation is observed, it reports the Available condition,
+// and all of its desired replicas are available.
+//
+// **Returns:**
+//
+// Condition: The condition.
+func DeploymentAvailable() Condition {
+	return Condition{Name: "available", Check: func(obj *unstructured.Unstructured) (bool, error) {
+		if obj == nil {
+			return false, nil
+		}
+		observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if observed < obj.GetGeneration() {
+			return false, nil
+		}
+
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+		ok, _ := statusCondition(obj, "Available")
+		return ok && available >= replicas, nil
+	}}
+}
+
+// JSONPathEquals returns a Condition met once a JSONPath expression
+// evaluates to a value, like `kubectl wait --for=jsonpath=...`. Both the
+// kubectl form, "{.status.phase}", and a bare path, ".status.phase", are
+// accepted. Missing fields evaluate to an empty string.
+//
+// **Parameters:**
+//
+// path: The JSONPath expression.
+// value: The expected value.
+//
+// **Returns:**
+//
+// Condition: The condition. Its check fails if the expression is
+// invalid.
+func JSONPathEquals(path, value string) Condition {
+	expr := path
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	parser := jsonpath.New("condition").AllowMissingKeys(true)
+	parseErr := parser.Parse(expr)
+
+	return Condition{Name: fmt.Sprintf("%s=%s", path, value), Check: func(obj *unstructured.Unstructured) (bool, error) {
+		if parseErr != nil {
+			return false, fmt.Errorf("invalid JSONPath %q: %v", path, parseErr)
+		}
+		if obj == nil {
+			return false, nil
+		}
+
+		var buf bytes.Buffer
+		if err := parser.Execute(&buf, obj.Object); err != nil {
+			return false, nil
+		}
+		return buf.String() == value, nil
+	}}
+}
+
+// statusCondition reports whether a status condition of the given type
+// is true, along with its reason and message joined by ": ", omitting
+// those that are empty.
+func statusCondition(obj *unstructured.Unstructured, conditionType string) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		var details []string
+		for _, key := range []string{"reason", "message"} {
+			if value, _ := condition[key].(string); value != "" {
+				details = append(details, value)
+			}
+		}
+		return condition["status"] == "True", strings.Join(details, ": ")
+	}
+	return false, ""
+}
@@ -0,0 +1,284 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	wait "github.com/l50/goutils/v2/k8s/wait"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var jobsGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func newObject(kind, name string, content map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion("batch/v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	return obj
+}
+
+func withConditions(conditions ...map[string]interface{}) map[string]interface{} {
+	list := make([]interface{}, len(conditions))
+	for i, c := range conditions {
+		list[i] = c
+	}
+	return map[string]interface{}{"status": map[string]interface{}{"conditions": list}}
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition wait.Condition
+		obj       *unstructured.Unstructured
+		expected  bool
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "Exists with object",
+			condition: wait.Exists(),
+			obj:       newObject("Job", "a", map[string]interface{}{}),
+			expected:  true,
+		},
+		{
+			name:      "Deleted without object",
+			condition: wait.Deleted(),
+			expected:  true,
+		},
+		{
+			name:      "Job complete",
+			condition: wait.JobComplete(),
+			obj:       newObject("Job", "a", withConditions(map[string]interface{}{"type": "Complete", "status": "True"})),
+			expected:  true,
+		},
+		{
+			name:      "Job failed",
+			condition: wait.JobComplete(),
+			obj: newObject("Job", "a", withConditions(map[string]interface{}{
+				"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded", "message": "Job has reached the specified backoff limit",
+			})),
+			errMsg: "job failed: BackoffLimitExceeded: Job has reached the specified backoff limit",
+		},
+		{
+			name:      "Job failed without message",
+			condition: wait.JobComplete(),
+			obj:       newObject("Job", "a", withConditions(map[string]interface{}{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"})),
+			errMsg:    "job failed: BackoffLimitExceeded",
+		},
+		{
+			name:      "Job failed without reason or message",
+			condition: wait.JobComplete(),
+			obj:       newObject("Job", "a", withConditions(map[string]interface{}{"type": "Failed", "status": "True"})),
+			errMsg:    "job failed",
+		},
+		{
+			name:      "Pod not ready",
+			condition: wait.PodReady(),
+			obj: newObject("Pod", "a", map[string]interface{}{"status": map[string]interface{}{
+				"phase":      "Running",
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}},
+			}}),
+		},
+		{
+			name:      "Pod terminated",
+			condition: wait.PodReady(),
+			obj:       newObject("Pod", "a", map[string]interface{}{"status": map[string]interface{}{"phase": "Failed"}}),
+			errMsg:    "pod terminated with phase Failed",
+		},
+		{
+			name:      "PVC bound",
+			condition: wait.PVCBound(),
+			obj:       newObject("PersistentVolumeClaim", "a", map[string]interface{}{"status": map[string]interface{}{"phase": "Bound"}}),
+			expected:  true,
+		},
+		{
+			name:      "PVC lost",
+			condition: wait.PVCBound(),
+			obj:       newObject("PersistentVolumeClaim", "a", map[string]interface{}{"status": map[string]interface{}{"phase": "Lost"}}),
+			errMsg:    "claim lost its volume",
+		},
+		{
+			name:      "Deployment available",
+			condition: wait.DeploymentAvailable(),
+			obj: newObject("Deployment", "a", map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(0),
+					"availableReplicas":  int64(2),
+					"conditions":         []interface{}{map[string]interface{}{"type": "Available", "status": "True"}},
+				},
+			}),
+			expected: true,
+		},
+		{
+			name:      "Deployment missing replicas",
+			condition: wait.DeploymentAvailable(),
+			obj: newObject("Deployment", "a", map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"availableReplicas": int64(2),
+					"conditions":        []interface{}{map[string]interface{}{"type": "Available", "status": "True"}},
+				},
+			}),
+		},
+		{
+			name:      "JSONPath equals",
+			condition: wait.JSONPathEquals(".status.phase", "Bound"),
+			obj:       newObject("PersistentVolumeClaim", "a", map[string]interface{}{"status": map[string]interface{}{"phase": "Bound"}}),
+			expected:  true,
+		},
+		{
+			name:      "JSONPath missing field",
+			condition: wait.JSONPathEquals("{.status.phase}", "Bound"),
+			obj:       newObject("PersistentVolumeClaim", "a", map[string]interface{}{}),
+		},
+		{
+			name:      "JSONPath invalid",
+			condition: wait.JSONPathEquals("{.status[", "Bound"),
+			obj:       newObject("PersistentVolumeClaim", "a", map[string]interface{}{}),
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			met, err := tc.condition.Check(tc.obj)
+			if tc.errMsg != "" {
+				require.EqualError(t, err, tc.errMsg)
+				return
+			}
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, met)
+		})
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	complete := withConditions(map[string]interface{}{"type": "Complete", "status": "True"})
+	failed := withConditions(map[string]interface{}{"type": "Failed", "status": "True"})
+
+	tests := []struct {
+		name      string
+		existing  []runtime.Object
+		condition wait.Condition
+		update    map[string]interface{}
+		wantErr   bool
+		timeout   bool
+	}{
+		{
+			name:      "Job completes",
+			existing:  []runtime.Object{newObject("Job", "migrate", map[string]interface{}{})},
+			condition: wait.JobComplete(),
+			update:    complete,
+		},
+		{
+			name:      "Job fails",
+			existing:  []runtime.Object{newObject("Job", "migrate", map[string]interface{}{})},
+			condition: wait.JobComplete(),
+			update:    failed,
+			wantErr:   true,
+		},
+		{
+			name:      "Already deleted",
+			condition: wait.Deleted(),
+		},
+		{
+			name:      "Timeout",
+			condition: wait.Exists(),
+			wantErr:   true,
+			timeout:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{jobsGVR: "JobList"}, tc.existing...)
+			kc := &client.KubernetesClient{DynamicClient: dynClient}
+
+			gets := 0
+			dynClient.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				gets++
+				if gets == 2 && tc.update != nil {
+					obj := newObject("Job", "migrate", tc.update)
+					require.NoError(t, dynClient.Tracker().Update(jobsGVR, obj, "default"))
+				}
+				return false, nil, nil
+			})
+
+			err := wait.WaitFor(context.Background(), kc, jobsGVR, "migrate", "default", tc.condition,
+				wait.WithPollInterval(10*time.Millisecond), wait.WithBackoff(2, 40*time.Millisecond), wait.WithTimeout(300*time.Millisecond))
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.timeout, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+		})
+	}
+
+	require.Error(t, wait.WaitFor(context.Background(), nil, jobsGVR, "migrate", "default", wait.Exists()))
+}
+
+func TestWaitForWithGetter(t *testing.T) {
+	tests := []struct {
+		name    string
+		get     func(gets int) (*unstructured.Unstructured, error)
+		wantErr bool
+		retries bool
+	}{
+		{
+			name: "Appears after a missing read",
+			get: func(gets int) (*unstructured.Unstructured, error) {
+				if gets < 2 {
+					return nil, apierrors.NewNotFound(jobsGVR.GroupResource(), "migrate")
+				}
+				return newObject("Job", "migrate", map[string]interface{}{}), nil
+			},
+			retries: true,
+		},
+		{
+			name: "Rejected credentials",
+			get: func(int) (*unstructured.Unstructured, error) {
+				return nil, apierrors.NewUnauthorized("token expired")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gets := 0
+			getter := func(context.Context) (*unstructured.Unstructured, error) {
+				gets++
+				return tc.get(gets)
+			}
+
+			err := wait.WaitFor(context.Background(), nil, jobsGVR, "migrate", "default", wait.Exists(),
+				wait.WithGetter(getter), wait.WithPollInterval(10*time.Millisecond), wait.WithTimeout(300*time.Millisecond))
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsUnauthorized(err), "expected an unauthorized error, got %v", err)
+				require.Equal(t, 1, gets, "rejected credentials shouldn't be retried")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.retries, gets > 1)
+		})
+	}
+}
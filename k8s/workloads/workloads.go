@@ -0,0 +1,222 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	dynK8s "github.com/l50/goutils/v2/k8s/dynamic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WorkloadsClient represents a client for day-two operations on
+// Kubernetes deployments and statefulsets through the Kubernetes API.
+//
+// **Attributes:**
+//
+// Client: A pointer to KubernetesClient for accessing the Kubernetes API.
+// PollInterval: How often the rollout status is checked while waiting.
+// Defaults to 2 seconds.
+type WorkloadsClient struct {
+	Client       *client.KubernetesClient
+	PollInterval time.Duration
+}
+
+// NewWorkloadsClient creates a WorkloadsClient using the provided
+// KubernetesClient.
+//
+// **Parameters:**
+//
+// kc: The KubernetesClient used to access the Kubernetes API.
+//
+// **Returns:**
+//
+// *WorkloadsClient: A WorkloadsClient with the default poll interval.
+func NewWorkloadsClient(kc *client.KubernetesClient) *WorkloadsClient {
+	return &WorkloadsClient{Client: kc, PollInterval: 2 * time.Second}
+}
+
+// ScaleDeployment sets the number of replicas of a deployment. Use
+// WaitForDeploymentRollout to wait for the replicas to become available.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the deployment.
+// namespace: The namespace of the deployment.
+// replicas: The desired number of replicas.
+//
+// **Returns:**
+//
+// error: An error if the deployment can't be scaled.
+func (wc *WorkloadsClient) ScaleDeployment(ctx context.Context, name, namespace string, replicas int32) error {
+	return wc.scale(ctx, "deployment", name, namespace, replicas)
+}
+
+// RestartDeployment triggers a rolling restart of a deployment, in the
+// same way as `kubectl rollout restart`.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the deployment.
+// namespace: The namespace of the deployment.
+// opts: Optional RolloutOption values, e.g. WithRolloutWait.
+//
+// **Returns:**
+//
+// error: An error if the deployment can't be restarted, or if waiting
+// was requested and the rollout doesn't complete in time.
+func (wc *WorkloadsClient) RestartDeployment(ctx context.Context, name, namespace string, opts ...dynK8s.RolloutOption) error {
+	return wc.restart(ctx, "deployment", name, namespace, opts)
+}
+
+// WaitForDeploymentRollout waits until the latest rollout of a deployment
+// has completed, mirroring `kubectl rollout status`.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// name: The name of the deployment.
+// namespace: The namespace of the deployment.
+// timeout: The maximum time to wait for the rollout to complete.
+//
+// **Returns:**
+//
+// error: An error if the rollout fails or doesn't complete before the
+// timeout.
+func (wc *WorkloadsClient) WaitForDeploymentRollout(ctx context.Context, name, namespace string, timeout time.Duration) error {
+	return wc.WaitForRollout(ctx, "deployment", name, namespace, timeout)
+}
+
+// ScaleStatefulSet sets the number of replicas of a statefulset. Use
+// WaitForStatefulSetRollout to wait for the replicas to become ready.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the statefulset.
+// namespace: The namespace of the statefulset.
+// replicas: The desired number of replicas.
+//
+// **Returns:**
+//
+// error: An error if the statefulset can't be scaled.
+func (wc *WorkloadsClient) ScaleStatefulSet(ctx context.Context, name, namespace string, replicas int32) error {
+	return wc.scale(ctx, "statefulset", name, namespace, replicas)
+}
+
+// RestartStatefulSet triggers a rolling restart of a statefulset, in the
+// same way as `kubectl rollout restart`.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to control the operation.
+// name: The name of the statefulset.
+// namespace: The namespace of the statefulset.
+// opts: Optional RolloutOption values, e.g. WithRolloutWait.
+//
+// **Returns:**
+//
+// error: An error if the statefulset can't be restarted, or if waiting
+// was requested and the rollout doesn't complete in time.
+func (wc *WorkloadsClient) RestartStatefulSet(ctx context.Context, name, namespace string, opts ...dynK8s.RolloutOption) error {
+	return wc.restart(ctx, "statefulset", name, namespace, opts)
+}
+
+// WaitForStatefulSetRollout waits until the latest rollout of a
+// statefulset has completed, mirroring `kubectl rollout status`.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// name: The name of the statefulset.
+// namespace: The namespace of the statefulset.
+// timeout: The maximum time to wait for the rollout to complete.
+//
+// **Returns:**
+//
+// error: An error if the rollout fails or doesn't complete before the
+// timeout.
+func (wc *WorkloadsClient) WaitForStatefulSetRollout(ctx context.Context, name, namespace string, timeout time.Duration) error {
+	return wc.WaitForRollout(ctx, "statefulset", name, namespace, timeout)
+}
+
+// WaitForRollout waits until the latest rollout of a deployment,
+// daemonset, or statefulset has completed, checking its status every
+// PollInterval.
+//
+// **Parameters:**
+//
+// ctx: A context.Context to allow for cancellation.
+// kind: The kind of workload: "deployment", "daemonset", or "statefulset".
+// name: The name of the workload.
+// namespace: The namespace of the workload.
+// timeout: The maximum time to wait for the rollout to complete.
+//
+// **Returns:**
+//
+// error: An error if the rollout fails or doesn't complete before the
+// timeout.
+func (wc *WorkloadsClient) WaitForRollout(ctx context.Context, kind, name, namespace string, timeout time.Duration) error {
+	if err := wc.validate(); err != nil {
+		return err
+	}
+
+	return dynK8s.WaitForRollout(ctx, wc.Client, kind, name, namespace, timeout, wc.pollInterval())
+}
+
+// scale patches the replicas of a deployment or statefulset.
+func (wc *WorkloadsClient) scale(ctx context.Context, kind, name, namespace string, replicas int32) error {
+	if err := wc.validate(); err != nil {
+		return err
+	}
+	if replicas < 0 {
+		return fmt.Errorf("invalid replica count %d for %s '%s'", replicas, kind, name)
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	apps := wc.Client.Clientset.AppsV1()
+	var err error
+	switch kind {
+	case "deployment":
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case "statefulset":
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scale %s '%s' in namespace '%s' to %d replicas: %v", kind, name, namespace, replicas, err)
+	}
+
+	return nil
+}
+
+// restart triggers a rollout restart, waiting with the client's poll
+// interval unless the options override it.
+func (wc *WorkloadsClient) restart(ctx context.Context, kind, name, namespace string, opts []dynK8s.RolloutOption) error {
+	if err := wc.validate(); err != nil {
+		return err
+	}
+
+	opts = append([]dynK8s.RolloutOption{dynK8s.WithRolloutPollInterval(wc.pollInterval())}, opts...)
+	return dynK8s.RolloutRestart(ctx, wc.Client, kind, name, namespace, opts...)
+}
+
+// pollInterval returns the rollout poll interval, applying the default.
+func (wc *WorkloadsClient) pollInterval() time.Duration {
+	if wc.PollInterval <= 0 {
+		return 2 * time.Second
+	}
+	return wc.PollInterval
+}
+
+// validate checks that the client can reach the Kubernetes API.
+func (wc *WorkloadsClient) validate() error {
+	if wc.Client == nil || wc.Client.Clientset == nil {
+		return errors.New("kubernetes client is not initialized")
+	}
+	return nil
+}
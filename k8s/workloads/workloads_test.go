@@ -0,0 +1,191 @@
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	client "github.com/l50/goutils/v2/k8s/client"
+	dynK8s "github.com/l50/goutils/v2/k8s/dynamic"
+	workloads "github.com/l50/goutils/v2/k8s/workloads"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newWorkloadsClient returns a client backed by a fake clientset holding a
+// "web" deployment and a "db" statefulset whose rollouts never complete.
+func newWorkloadsClient() (*workloads.WorkloadsClient, *fake.Clientset) {
+	meta := metav1.ObjectMeta{Namespace: "default", Generation: 1}
+	deployment := &appsv1.Deployment{ObjectMeta: meta}
+	deployment.Name = "web"
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: meta}
+	statefulSet.Name = "db"
+
+	clientset := fake.NewSimpleClientset(deployment, statefulSet)
+	wc := workloads.NewWorkloadsClient(&client.KubernetesClient{Clientset: clientset})
+	wc.PollInterval = 10 * time.Millisecond
+	return wc, clientset
+}
+
+// countActions returns the number of verb actions on resource.
+func countActions(clientset *fake.Clientset, verb, resource string) int {
+	count := 0
+	for _, action := range clientset.Actions() {
+		if action.Matches(verb, resource) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		resourceName string
+		replicas     int32
+		resource     string
+		expectedErr  string
+	}{
+		{name: "Scale deployment", kind: "deployment", resourceName: "web", replicas: 5, resource: "deployments"},
+		{name: "Scale deployment to zero", kind: "deployment", resourceName: "web", replicas: 0, resource: "deployments"},
+		{name: "Scale statefulset", kind: "statefulset", resourceName: "db", replicas: 3, resource: "statefulsets"},
+		{name: "Negative replicas", kind: "deployment", resourceName: "web", replicas: -1, expectedErr: "invalid replica count -1 for deployment 'web'"},
+		{name: "Missing statefulset", kind: "statefulset", resourceName: "missing", replicas: 1, expectedErr: "failed to scale statefulset 'missing' in namespace 'default' to 1 replicas: statefulsets.apps \"missing\" not found"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wc, clientset := newWorkloadsClient()
+			ctx := context.Background()
+
+			var err error
+			if tc.kind == "deployment" {
+				err = wc.ScaleDeployment(ctx, tc.resourceName, "default", tc.replicas)
+			} else {
+				err = wc.ScaleStatefulSet(ctx, tc.resourceName, "default", tc.replicas)
+			}
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, clientset.Actions(), 1)
+			patch, ok := clientset.Actions()[0].(k8stesting.PatchAction)
+			require.True(t, ok)
+			require.Equal(t, tc.resource, patch.GetResource().Resource)
+			require.Equal(t, tc.resourceName, patch.GetName())
+			require.JSONEq(t, fmt.Sprintf(`{"spec":{"replicas":%d}}`, tc.replicas), string(patch.GetPatch()))
+		})
+	}
+}
+
+func TestRestart(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		opts      []dynK8s.RolloutOption
+		resource  string
+		minPolls  int
+		maxPolls  int
+		expectErr bool
+	}{
+		{name: "Restart deployment", kind: "deployment", resource: "deployments"},
+		{name: "Restart statefulset", kind: "statefulset", resource: "statefulsets"},
+		{
+			name:      "Wait uses the client poll interval",
+			kind:      "statefulset",
+			opts:      []dynK8s.RolloutOption{dynK8s.WithRolloutWait(100 * time.Millisecond)},
+			resource:  "statefulsets",
+			minPolls:  3,
+			maxPolls:  20,
+			expectErr: true,
+		},
+		{
+			name: "Options override the client poll interval",
+			kind: "deployment",
+			opts: []dynK8s.RolloutOption{
+				dynK8s.WithRolloutWait(100 * time.Millisecond),
+				dynK8s.WithRolloutPollInterval(time.Hour),
+			},
+			resource:  "deployments",
+			minPolls:  1,
+			maxPolls:  1,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wc, clientset := newWorkloadsClient()
+			ctx := context.Background()
+
+			var err error
+			if tc.kind == "deployment" {
+				err = wc.RestartDeployment(ctx, "web", "default", tc.opts...)
+			} else {
+				err = wc.RestartStatefulSet(ctx, "db", "default", tc.opts...)
+			}
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, 1, countActions(clientset, "patch", tc.resource))
+			polls := countActions(clientset, "get", tc.resource)
+			require.GreaterOrEqual(t, polls, tc.minPolls)
+			require.LessOrEqual(t, polls, tc.maxPolls)
+		})
+	}
+}
+
+func TestWaitForWorkloadRollout(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		pollInterval time.Duration
+		resource     string
+		minPolls     int
+		maxPolls     int
+	}{
+		{name: "Deployment", kind: "deployment", pollInterval: 10 * time.Millisecond, resource: "deployments", minPolls: 3, maxPolls: 20},
+		{name: "StatefulSet", kind: "statefulset", pollInterval: 10 * time.Millisecond, resource: "statefulsets", minPolls: 3, maxPolls: 20},
+		{name: "Default poll interval", kind: "deployment", resource: "deployments", minPolls: 1, maxPolls: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wc, clientset := newWorkloadsClient()
+			wc.PollInterval = tc.pollInterval
+			ctx := context.Background()
+
+			var err error
+			if tc.kind == "deployment" {
+				err = wc.WaitForDeploymentRollout(ctx, "web", "default", 100*time.Millisecond)
+			} else {
+				err = wc.WaitForStatefulSetRollout(ctx, "db", "default", 100*time.Millisecond)
+			}
+			require.Error(t, err)
+
+			require.Len(t, clientset.Actions(), countActions(clientset, "get", tc.resource))
+			polls := countActions(clientset, "get", tc.resource)
+			require.GreaterOrEqual(t, polls, tc.minPolls)
+			require.LessOrEqual(t, polls, tc.maxPolls)
+		})
+	}
+}
+
+func TestUninitializedWorkloadsClient(t *testing.T) {
+	wc := workloads.NewWorkloadsClient(nil)
+	ctx := context.Background()
+
+	require.EqualError(t, wc.ScaleDeployment(ctx, "web", "default", 1), "kubernetes client is not initialized")
+	require.EqualError(t, wc.RestartStatefulSet(ctx, "db", "default"), "kubernetes client is not initialized")
+	require.EqualError(t, wc.WaitForDeploymentRollout(ctx, "web", "default", time.Second), "kubernetes client is not initialized")
+}
@@ -21,6 +21,8 @@ type ColorLogger struct {
 	Cfg            LogConfig
 	ColorAttribute color.Attribute
 	Logger         *slog.Logger
+
+	ctx context.Context
 }
 
 // NewColorLogger creates a new ColorLogger instance with the specified
@@ -56,53 +58,79 @@ func (l *ColorLogger) Println(v ...interface{}) {
 		Message: msg,
 	}
 
-	l.Logger.Log(context.Background(), record.Level, record.Message)
+	l.Logger.Log(l.logContext(), record.Level, record.Message)
 }
 
 // Printf for ColorLogger logs the provided formatted string in
 // the specified color. The format and arguments are handled in the
 // manner of fmt.Printf.
 func (l *ColorLogger) Printf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Error for ColorLogger logs the provided arguments as an error line
 // in the specified color. The arguments are handled in the manner
 // of fmt.Println.
 func (l *ColorLogger) Error(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelError, fmt.Sprint(v...))
+	l.Logger.Log(l.logContext(), slog.LevelError, fmt.Sprint(v...))
 }
 
 // Errorf for ColorLogger logs the provided formatted string as an
 // error line in the specified color. The format and arguments are handled
 // in the manner of fmt.Printf.
 func (l *ColorLogger) Errorf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelError, fmt.Sprintf(format, v...))
 }
 
 // Debug for ColorLogger logs the provided arguments as a debug line
 // in the specified color. The arguments are handled in the manner
 // of fmt.Println.
 func (l *ColorLogger) Debug(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelDebug, fmt.Sprint(v...))
+	l.Logger.Log(l.logContext(), slog.LevelDebug, fmt.Sprint(v...))
 }
 
 // Debugf for ColorLogger logs the provided formatted string as a debug
 // line in the specified color. The format and arguments are handled
 // in the manner of fmt.Printf.
 func (l *ColorLogger) Debugf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelDebug, fmt.Sprintf(format, v...))
 }
 
 // Warn for ColorLogger logs the provided arguments as a warning line
 // in the specified color. The arguments are handled in the manner of fmt.Println.
 func (l *ColorLogger) Warn(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelWarn, fmt.Sprint(v...))
+	l.Logger.Log(l.logContext(), slog.LevelWarn, fmt.Sprint(v...))
 }
 
 // Warnf for ColorLogger logs the provided formatted string as a warning
 // line in the specified color. The format and arguments are handled in the
 // manner of fmt.Printf.
 func (l *ColorLogger) Warnf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// WithContext returns a copy of the ColorLogger that passes ctx to its
+// handlers, allowing them to extract request-scoped values such as the
+// active trace span.
+//
+// **Parameters:**
+//
+// ctx: Context passed to the handlers with each log record.
+//
+// **Returns:**
+//
+// Logger: A copy of the ColorLogger bound to ctx.
+func (l *ColorLogger) WithContext(ctx context.Context) Logger {
+	clone := *l
+	clone.ctx = ctx
+	return &clone
+}
+
+// logContext returns the context bound with WithContext, or
+// context.Background if none has been set.
+func (l *ColorLogger) logContext() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
 }
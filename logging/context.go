@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loggerContextKey is the context key holding the Logger stored by
+// IntoContext.
+type loggerContextKey struct{}
+
+// IntoContext returns a copy of ctx carrying logger, so that functions
+// receiving the context, such as the k8s, web, and git helpers in this
+// module, write their messages to it instead of printing them.
+//
+// **Parameters:**
+//
+// ctx: The parent context.
+// logger: The Logger to carry. A nil logger removes a logger stored by a
+// parent context.
+//
+// **Returns:**
+//
+// context.Context: A context carrying logger.
+func IntoContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the Logger carried by ctx, bound to ctx (see
+// WithContext) so its records are correlated with the active span. If
+// ctx carries no logger, GlobalLogger is used, and if that isn't set
+// either, a logger printing every message as a line on stdout is
+// returned, matching how the helpers in this module printed before they
+// took loggers from the context.
+//
+// **Parameters:**
+//
+// ctx: The context to take the logger from. May be nil.
+//
+// **Returns:**
+//
+// Logger: The Logger to use. Never nil.
+func FromContext(ctx context.Context) Logger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	logger, _ := ctx.Value(loggerContextKey{}).(Logger)
+	if logger == nil {
+		logger = GlobalLogger
+	}
+	if logger == nil {
+		return stdoutLogger{}
+	}
+
+	return WithContext(ctx, logger)
+}
+
+// stdoutLogger is the Logger returned by FromContext when no logger is
+// configured. It prints each message as a line on stdout regardless of
+// its level.
+type stdoutLogger struct{}
+
+func (stdoutLogger) Println(v ...interface{}) { fmt.Println(v...) }
+
+func (l stdoutLogger) Printf(format string, v ...interface{}) { l.line(fmt.Sprintf(format, v...)) }
+
+func (l stdoutLogger) Error(v ...interface{}) { l.Println(v...) }
+
+func (l stdoutLogger) Errorf(format string, v ...interface{}) { l.Printf(format, v...) }
+
+func (l stdoutLogger) Debug(v ...interface{}) { l.Println(v...) }
+
+func (l stdoutLogger) Debugf(format string, v ...interface{}) { l.Printf(format, v...) }
+
+func (l stdoutLogger) Warn(v ...interface{}) { l.Println(v...) }
+
+func (l stdoutLogger) Warnf(format string, v ...interface{}) { l.Printf(format, v...) }
+
+// line prints msg followed by a newline unless it already ends with one.
+func (stdoutLogger) line(msg string) {
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	fmt.Fprint(os.Stdout, msg)
+}
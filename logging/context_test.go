@@ -0,0 +1,135 @@
+package logging_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/l50/goutils/v2/logging"
+)
+
+type recordingLogger struct {
+	name     string
+	messages []string
+}
+
+func (l *recordingLogger) record(v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(v...))
+}
+
+func (l *recordingLogger) Println(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Error(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Errorf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Debug(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Debugf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Warn(v ...interface{}) {
+	l.record(v...)
+}
+
+func (l *recordingLogger) Warnf(format string, v ...interface{}) {
+	l.record(fmt.Sprintf(format, v...))
+}
+
+func TestFromContext(t *testing.T) {
+	ctxLogger := &recordingLogger{name: "ctx"}
+	globalLogger := &recordingLogger{name: "global"}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		global   logging.Logger
+		expected *recordingLogger
+	}{
+		{
+			name:     "Logger from context",
+			ctx:      logging.IntoContext(context.Background(), ctxLogger),
+			global:   globalLogger,
+			expected: ctxLogger,
+		},
+		{
+			name:     "Logger from child context",
+			ctx:      context.WithValue(logging.IntoContext(context.Background(), ctxLogger), struct{}{}, "value"),
+			expected: ctxLogger,
+		},
+		{
+			name:     "Falls back to GlobalLogger",
+			ctx:      context.Background(),
+			global:   globalLogger,
+			expected: globalLogger,
+		},
+		{
+			name:     "Nil logger in context falls back to GlobalLogger",
+			ctx:      logging.IntoContext(logging.IntoContext(context.Background(), ctxLogger), nil),
+			global:   globalLogger,
+			expected: globalLogger,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			previous := logging.GlobalLogger
+			logging.GlobalLogger = tc.global
+			defer func() { logging.GlobalLogger = previous }()
+
+			ctxLogger.messages = nil
+			globalLogger.messages = nil
+
+			logging.FromContext(tc.ctx).Warnf("message %d", 1)
+
+			if len(tc.expected.messages) != 1 || tc.expected.messages[0] != "message 1" {
+				t.Errorf("expected %s logger to record %q, got %q", tc.expected.name, "message 1", tc.expected.messages)
+			}
+		})
+	}
+}
+
+func TestFromContextStdoutFallback(t *testing.T) {
+	previous := logging.GlobalLogger
+	logging.GlobalLogger = nil
+	defer func() { logging.GlobalLogger = previous }()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+
+	logger := logging.FromContext(context.Background())
+	logger.Printf("first %s", "line")
+	logger.Warnf("second line\n")
+	logger.Println("third", "line")
+
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read stdout: %v", err)
+	}
+
+	expected := "first line\nsecond line\nthird line\n"
+	if string(out) != expected {
+		t.Errorf("expected stdout %q, got %q", expected, string(out))
+	}
+}
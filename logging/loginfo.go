@@ -3,6 +3,7 @@ package logging
 import (
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -16,12 +17,41 @@ import (
 // Path: A string representing the full path to the log file.
 // Level: A slog.Level object representing the logging level.
 // LogToDisk: A boolean representing whether or not to log to disk.
+// NetSink: Optional NetSinkConfig used to forward logs to a remote collector.
+// TraceCorrelation: Whether to add the trace_id and span_id of the active
+// OpenTelemetry span to records logged through a context-bound Logger
+// (see WithContext).
+// CleanupOlderThan: When logging to disk, InitLogging deletes log files
+// in the log file's directory that are older than this, as CleanupLogs
+// does. The current log file is never deleted.
+// CleanupKeepLast: When logging to disk, the number of most recent log
+// files, other than the current one, that InitLogging keeps regardless
+// of their age. Cleanup only runs if CleanupOlderThan or CleanupKeepLast
+// is set.
+// Theme: Optional Theme for ColorOutput controlling the level colors,
+// timestamp format, and component prefix. DefaultTheme is used if nil.
+// HandlerFactory: Optional HandlerFactory used to create the stdout and
+// log file handlers instead of the ones selected by OutputType.
+// Sinks: Optional destinations, each with its own format and level, that
+// records are also written to. If any are set, records are no longer
+// written to stdout unless one of the sinks does so; the log file
+// selected by LogToDisk is still written.
 type LogConfig struct {
-	Fs         afero.Fs
-	LogPath    string
-	Level      slog.Level
-	OutputType OutputType
-	LogToDisk  bool
+	Fs               afero.Fs
+	LogPath          string
+	Level            slog.Level
+	OutputType       OutputType
+	LogToDisk        bool
+	NetSink          *NetSinkConfig
+	TraceCorrelation bool
+	CleanupOlderThan time.Duration
+	CleanupKeepLast  int
+	Theme            *Theme
+	HandlerFactory   HandlerFactory
+	Sinks            []Sink
+
+	sink    *NetSink
+	sinkCfg NetSinkConfig
 }
 
 // DetermineLogLevel determines the log level from a given string.
@@ -2,6 +2,7 @@ package logging
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,7 +14,8 @@ import (
 )
 
 // OutputType is an enumeration type that specifies the output format
-// of the logger. It can be either plain text or colorized text.
+// of the logger. It can be plain text, colorized text, JSON, or
+// key=value text.
 type OutputType int
 
 const (
@@ -24,10 +26,35 @@ const (
 
 	// ColorOutput indicates that the logger will produce colorized
 	// text output. This is useful for console output where color
-	// coding can enhance readability.
+	// coding can enhance readability. Colors are dropped when NO_COLOR
+	// is set or stdout isn't a terminal (see ColorEnabled).
 	ColorOutput
+
+	// JSONOutput indicates that the logger will write one JSON object
+	// per line to stdout, for log collectors that expect structured
+	// input such as Loki or CloudWatch.
+	JSONOutput
+
+	// TextOutput indicates that the logger will write key=value pairs
+	// with slog.TextHandler, which are human-readable without color.
+	TextOutput
 )
 
+// HandlerFactory creates a slog.Handler writing to w. It allows LogConfig
+// to use handlers other than the built-in ones, e.g., to rename keys for
+// a log collector or to add attributes to every record.
+//
+// **Parameters:**
+//
+// w: The writer the handler should write to.
+// opts: The handler options derived from the LogConfig, including the
+// level.
+//
+// **Returns:**
+//
+// slog.Handler: The handler writing to w.
+type HandlerFactory func(w io.Writer, opts *slog.HandlerOptions) slog.Handler
+
 // CreateLogFile creates a log file in a 'logs' subdirectory of the
 // specified directory. The log file's name is the provided log name
 // with the extension '.log'.
@@ -71,15 +98,20 @@ func (cfg *LogConfig) CreateLogFile() error {
 }
 
 // ConfigureLogger sets up a logger based on the provided logging level,
-// file path, and output type. It supports both colorized and plain text
-// logging output, selectable via the OutputType parameter. The logger
-// writes log entries to both a file and standard output.
+// file path, and output type. It supports colorized, plain text, and
+// JSON logging output, selectable via the OutputType parameter, or
+// custom handlers created by HandlerFactory. The logger writes log
+// entries to both a file and standard output, or to the configured
+// Sinks, each with its own format and level. A NetSink created by an
+// earlier call is reused if LogConfig.NetSink is unchanged, and closed
+// otherwise.
 //
 // **Parameters:**
 //
 // level: Logging level as a slog.Level.
 // path: Path to the log file.
-// outputType: Type of log output, either ColorOutput or PlainOutput.
+// outputType: Type of log output: ColorOutput, PlainOutput, JSONOutput,
+// or TextOutput.
 //
 // **Returns:**
 //
@@ -105,14 +137,15 @@ func (cfg *LogConfig) ConfigureLogger() (Logger, error) {
 		if err != nil {
 			return nil, err
 		}
-		fileHandler = slog.NewJSONHandler(logFile, opts)
+		if cfg.HandlerFactory != nil {
+			fileHandler = cfg.HandlerFactory(logFile, opts)
+		} else {
+			fileHandler = slog.NewJSONHandler(logFile, opts)
+		}
 	}
 
-	if cfg.OutputType == ColorOutput {
-		prettyOpts := PrettyHandlerOptions{SlogOpts: *opts}
-		stdoutHandler = NewPrettyHandler(os.Stdout, prettyOpts)
-	} else {
-		stdoutHandler = slog.NewJSONHandler(os.Stdout, opts)
+	if len(cfg.Sinks) == 0 {
+		stdoutHandler = newOutputHandler(os.Stdout, cfg.OutputType, opts, cfg.Theme, cfg.HandlerFactory)
 	}
 
 	var handlers []slog.Handler
@@ -122,15 +155,44 @@ func (cfg *LogConfig) ConfigureLogger() (Logger, error) {
 	if stdoutHandler != nil {
 		handlers = append(handlers, stdoutHandler)
 	}
+	for i, sink := range cfg.Sinks {
+		handler, err := sink.handler(cfg.Fs, cfg.Level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure sink %d: %v", i, err)
+		}
+		handlers = append(handlers, handler)
+	}
+
+	if cfg.sink != nil && (cfg.NetSink == nil || *cfg.NetSink != cfg.sinkCfg) {
+		if err := cfg.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.NetSink != nil {
+		if cfg.sink == nil {
+			sink, err := NewNetSink(*cfg.NetSink)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create net sink: %v", err)
+			}
+			cfg.sink = sink
+			cfg.sinkCfg = *cfg.NetSink
+		}
+		handlers = append(handlers, slog.NewJSONHandler(cfg.sink, opts))
+	}
 
 	if len(handlers) == 0 {
 		return nil, fmt.Errorf("no valid handlers available for logger")
 	}
 
-	multiHandler := slog.New(slogmulti.Fanout(handlers...))
+	var handler slog.Handler = slogmulti.Fanout(handlers...)
+	if cfg.TraceCorrelation {
+		handler = NewTraceHandler(handler)
+	}
+
+	multiHandler := slog.New(handler)
 	var logger Logger
 	if cfg.OutputType == ColorOutput {
-		colorAttribute := determineColorAttribute(cfg.Level)
+		colorAttribute := cfg.Theme.LevelColor(cfg.Level)
 		logger = &ColorLogger{
 			Cfg:            *cfg,
 			ColorAttribute: colorAttribute,
@@ -146,16 +208,49 @@ func (cfg *LogConfig) ConfigureLogger() (Logger, error) {
 	return logger, nil
 }
 
+// Sink returns the NetSink created by ConfigureLogger when
+// LogConfig.NetSink is set.
+//
+// **Returns:**
+//
+// *NetSink: The active NetSink, or nil if no network sink is configured.
+func (cfg *LogConfig) Sink() *NetSink {
+	return cfg.sink
+}
+
+// Close flushes and closes the NetSink created by ConfigureLogger,
+// if any. It should be called before the program exits to avoid
+// losing buffered log entries.
+//
+// **Returns:**
+//
+// error: An error if the NetSink fails to close.
+func (cfg *LogConfig) Close() error {
+	if cfg.sink == nil {
+		return nil
+	}
+
+	if err := cfg.sink.Close(); err != nil {
+		return fmt.Errorf("failed to close net sink: %v", err)
+	}
+	cfg.sink = nil
+
+	return nil
+}
+
 // InitLogging is a convenience function that combines
 // the CreateLogFile and ConfigureLogger functions into one call.
-// It is useful for quickly setting up logging to disk.
+// It is useful for quickly setting up logging to disk. If
+// CleanupOlderThan or CleanupKeepLast is set, old log files in the log
+// file's directory are deleted once the logger is configured.
 //
 // **Parameters:**
 //
 // fs: An afero.Fs instance for filesystem operations, allows mocking in tests.
 // logPath: The path to the log file.
 // level: The logging level.
-// outputType: The output type of the logger (PlainOutput or ColorOutput).
+// outputType: The output type of the logger (PlainOutput, ColorOutput,
+// JSONOutput, or TextOutput).
 // logToDisk: A boolean indicating whether to log to disk or not.
 //
 // **Returns:**
@@ -178,6 +273,15 @@ func InitLogging(cfg *LogConfig) (Logger, error) {
 		return nil, fmt.Errorf("failed to configure logger: %v", err)
 	}
 
+	// A failed cleanup shouldn't keep the program from logging, so it's
+	// only reported.
+	if cfg.LogToDisk && (cfg.CleanupOlderThan > 0 || cfg.CleanupKeepLast > 0) {
+		if _, err := cleanupLogs(cfg.Fs, filepath.Dir(cfg.LogPath), cfg.CleanupOlderThan,
+			cfg.CleanupKeepLast, cfg.LogPath); err != nil {
+			logger.Warnf("failed to clean up old log files: %v", err)
+		}
+	}
+
 	return logger, nil
 }
 
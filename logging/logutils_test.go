@@ -358,6 +358,98 @@ func TestLoggerOutput(t *testing.T) {
 	}
 }
 
+func TestConfigureLoggerOutputFormats(t *testing.T) {
+	renameMsg := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		opts.ReplaceAttr = func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.MessageKey {
+				a.Key = "message"
+			}
+			return a
+		}
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	testCases := []struct {
+		name           string
+		outputType     logging.OutputType
+		handlerFactory logging.HandlerFactory
+		expectedStdout string
+		expectedFile   string
+	}{
+		{
+			name:           "Plain output",
+			outputType:     logging.PlainOutput,
+			expectedStdout: `"level":"INFO","msg":"formats test"`,
+			expectedFile:   `"msg":"formats test"`,
+		},
+		{
+			name:           "Text output",
+			outputType:     logging.TextOutput,
+			expectedStdout: `level=INFO msg="formats test"`,
+			expectedFile:   `"msg":"formats test"`,
+		},
+		{
+			name:           "JSON output",
+			outputType:     logging.JSONOutput,
+			expectedStdout: `"level":"INFO","msg":"formats test"`,
+			expectedFile:   `"msg":"formats test"`,
+		},
+		{
+			name:           "Custom handler factory",
+			outputType:     logging.ColorOutput,
+			handlerFactory: renameMsg,
+			expectedStdout: `"message":"formats test"`,
+			expectedFile:   `"message":"formats test"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			cfg := logging.LogConfig{
+				Fs:             fs,
+				LogPath:        "/logs/formats.log",
+				LogToDisk:      true,
+				Level:          slog.LevelInfo,
+				OutputType:     tc.outputType,
+				HandlerFactory: tc.handlerFactory,
+			}
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create pipe: %v", err)
+			}
+			stdout := os.Stdout
+			os.Stdout = w
+			logger, err := logging.InitLogging(&cfg)
+			if err == nil {
+				logger.Printf("formats test")
+			}
+			os.Stdout = stdout
+			w.Close()
+			if err != nil {
+				t.Fatalf("InitLogging() error = %v", err)
+			}
+
+			out, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to read stdout: %v", err)
+			}
+			if !strings.Contains(string(out), tc.expectedStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tc.expectedStdout, out)
+			}
+
+			content, err := afero.ReadFile(fs, cfg.LogPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if !strings.Contains(string(content), tc.expectedFile) {
+				t.Errorf("expected log file to contain %q, got %q", tc.expectedFile, content)
+			}
+		})
+	}
+}
+
 type mockLogger struct {
 	lastLoggedError string
 }
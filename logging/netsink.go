@@ -0,0 +1,412 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SinkProtocol is an enumeration type that specifies the transport
+// used by a NetSink to forward log entries.
+type SinkProtocol int
+
+const (
+	// TCPSink forwards log entries as JSON lines over a persistent
+	// TCP connection.
+	TCPSink SinkProtocol = iota
+
+	// HTTPSink forwards log entries as newline-delimited JSON batches
+	// sent in the body of an HTTP POST request.
+	HTTPSink
+)
+
+// DropPolicy is an enumeration type that specifies which entries a
+// NetSink discards when its buffer is full.
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered entry to make room for
+	// the newest one.
+	DropOldest DropPolicy = iota
+
+	// DropNewest discards the incoming entry and keeps the buffered
+	// entries untouched.
+	DropNewest
+)
+
+// NetSinkConfig represents the parameters used to configure a NetSink.
+// Zero values are replaced with sensible defaults by NewNetSink.
+//
+// **Attributes:**
+//
+// Protocol: The transport used to forward entries (TCPSink or HTTPSink).
+// Address: host:port for TCPSink or the endpoint URL for HTTPSink.
+// BufferSize: Maximum number of entries held in memory (default 1024).
+// BatchSize: Maximum number of entries sent in a single write (default 100).
+// FlushInterval: How often buffered entries are flushed (default 1s).
+// MaxRetries: Number of retries for a failed batch (default 3, negative
+// disables retries).
+// Backoff: Initial delay between retries, doubled on each attempt (default 100ms).
+// MaxBackoff: Upper bound for the delay between retries (default 5s).
+// Timeout: Dial, write, and request timeout (default 5s).
+// DropPolicy: Which entries to discard when the buffer is full.
+// HTTPClient: Optional client used by HTTPSink.
+type NetSinkConfig struct {
+	Protocol      SinkProtocol
+	Address       string
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxRetries    int
+	Backoff       time.Duration
+	MaxBackoff    time.Duration
+	Timeout       time.Duration
+	DropPolicy    DropPolicy
+	HTTPClient    *http.Client
+}
+
+// NetSinkMetrics is a point-in-time snapshot of NetSink counters.
+//
+// **Attributes:**
+//
+// Sent: Number of entries successfully delivered.
+// Dropped: Number of entries discarded because the buffer was full.
+// Failed: Number of entries discarded after exhausting all retries.
+// Retries: Number of retried batch deliveries.
+type NetSinkMetrics struct {
+	Sent    uint64
+	Dropped uint64
+	Failed  uint64
+	Retries uint64
+}
+
+// NetSink is an io.Writer that buffers log entries in an in-memory
+// ring buffer and forwards them to a remote collector over TCP or
+// HTTP, retrying failed deliveries with exponential backoff.
+//
+// **Attributes:**
+//
+// Cfg: The NetSinkConfig used to create the sink.
+type NetSink struct {
+	Cfg NetSinkConfig
+
+	mu      sync.Mutex
+	ring    [][]byte
+	head    int
+	count   int
+	closed  bool
+	conn    net.Conn
+	notify  chan struct{}
+	flushCh chan chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+	retries atomic.Uint64
+}
+
+// NewNetSink creates a NetSink from the input configuration and starts
+// the background goroutine that forwards buffered entries.
+//
+// **Parameters:**
+//
+// cfg: NetSinkConfig describing the remote collector and buffering.
+//
+// **Returns:**
+//
+// *NetSink: A running NetSink.
+// error: An error if the configuration is invalid.
+func NewNetSink(cfg NetSinkConfig) (*NetSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("net sink address cannot be empty")
+	}
+	if cfg.Protocol != TCPSink && cfg.Protocol != HTTPSink {
+		return nil, fmt.Errorf("unsupported net sink protocol: %d", cfg.Protocol)
+	}
+
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 100 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Protocol == HTTPSink && cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	s := &NetSink{
+		Cfg:     cfg,
+		ring:    make([][]byte, cfg.BufferSize),
+		notify:  make(chan struct{}, 1),
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s, nil
+}
+
+// Write buffers a single log entry for delivery. It never blocks on
+// the network; when the buffer is full the configured DropPolicy
+// decides which entry is discarded.
+//
+// **Parameters:**
+//
+// p: The encoded log entry.
+//
+// **Returns:**
+//
+// int: The number of bytes accepted, always len(p).
+// error: An error if the sink has been closed.
+func (s *NetSink) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p), len(p)+1)
+	copy(entry, p)
+	if len(entry) == 0 || entry[len(entry)-1] != '\n' {
+		entry = append(entry, '\n')
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return 0, fmt.Errorf("net sink is closed")
+	}
+
+	if s.count == len(s.ring) {
+		if s.Cfg.DropPolicy == DropNewest {
+			s.mu.Unlock()
+			s.dropped.Add(1)
+			return len(p), nil
+		}
+		s.ring[s.head] = nil
+		s.head = (s.head + 1) % len(s.ring)
+		s.count--
+		s.dropped.Add(1)
+	}
+
+	s.ring[(s.head+s.count)%len(s.ring)] = entry
+	s.count++
+	full := s.count >= s.Cfg.BatchSize || s.count == len(s.ring)
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush blocks until every entry buffered before the call has been
+// delivered or discarded.
+//
+// **Returns:**
+//
+// error: An error if the sink has been closed.
+func (s *NetSink) Flush() error {
+	ack := make(chan struct{})
+	select {
+	case s.flushCh <- ack:
+		<-ack
+		return nil
+	case <-s.done:
+		return fmt.Errorf("net sink is closed")
+	}
+}
+
+// Close stops accepting entries, makes a final delivery attempt for
+// anything still buffered, and releases the underlying connection.
+//
+// **Returns:**
+//
+// error: An error if the sink was already closed.
+func (s *NetSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return fmt.Errorf("net sink is already closed")
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.done)
+	s.wg.Wait()
+
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+
+	return nil
+}
+
+// Metrics returns a snapshot of the sink's delivery counters.
+//
+// **Returns:**
+//
+// NetSinkMetrics: The current counter values.
+func (s *NetSink) Metrics() NetSinkMetrics {
+	return NetSinkMetrics{
+		Sent:    s.sent.Load(),
+		Dropped: s.dropped.Load(),
+		Failed:  s.failed.Load(),
+		Retries: s.retries.Load(),
+	}
+}
+
+// run is the background loop that drains the ring buffer on every
+// flush trigger until the sink is closed.
+func (s *NetSink) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.Cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.drain(true)
+		case <-s.notify:
+			s.drain(true)
+		case ack := <-s.flushCh:
+			s.drain(true)
+			close(ack)
+		case <-s.done:
+			s.drain(false)
+			return
+		}
+	}
+}
+
+// drain delivers buffered entries in batches. When retry is false each
+// batch gets a single delivery attempt.
+func (s *NetSink) drain(retry bool) {
+	for {
+		batch := s.pop(s.Cfg.BatchSize)
+		if len(batch) == 0 {
+			return
+		}
+		s.deliver(batch, retry)
+	}
+}
+
+// pop removes up to n entries from the head of the ring buffer.
+func (s *NetSink) pop(n int) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n > s.count {
+		n = s.count
+	}
+
+	batch := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		batch = append(batch, s.ring[s.head])
+		s.ring[s.head] = nil
+		s.head = (s.head + 1) % len(s.ring)
+	}
+	s.count -= n
+
+	return batch
+}
+
+// deliver sends a batch, retrying with exponential backoff on failure.
+func (s *NetSink) deliver(batch [][]byte, retry bool) {
+	payload := bytes.Join(batch, nil)
+	delay := s.Cfg.Backoff
+
+	for attempt := 0; ; attempt++ {
+		if err := s.send(payload); err == nil {
+			s.sent.Add(uint64(len(batch)))
+			return
+		}
+
+		if !retry || attempt >= s.Cfg.MaxRetries {
+			s.failed.Add(uint64(len(batch)))
+			return
+		}
+
+		s.retries.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-s.done:
+			// Stop backing off once Close is called.
+			s.failed.Add(uint64(len(batch)))
+			return
+		}
+
+		delay *= 2
+		if delay > s.Cfg.MaxBackoff {
+			delay = s.Cfg.MaxBackoff
+		}
+	}
+}
+
+// send writes a payload using the configured protocol.
+func (s *NetSink) send(payload []byte) error {
+	if s.Cfg.Protocol == HTTPSink {
+		return s.sendHTTP(payload)
+	}
+	return s.sendTCP(payload)
+}
+
+func (s *NetSink) sendTCP(payload []byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.Cfg.Address, s.Cfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to dial %s: %v", s.Cfg.Address, err)
+		}
+		s.conn = conn
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.Cfg.Timeout)); err != nil {
+		return fmt.Errorf("failed to set write deadline: %v", err)
+	}
+
+	if _, err := s.conn.Write(payload); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("failed to write to %s: %v", s.Cfg.Address, err)
+	}
+
+	return nil
+}
+
+func (s *NetSink) sendHTTP(payload []byte) error {
+	resp, err := s.Cfg.HTTPClient.Post(s.Cfg.Address, "application/x-ndjson", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %v", s.Cfg.Address, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status from %s: %s", s.Cfg.Address, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package logging_test
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/logging"
+	"github.com/spf13/afero"
+)
+
+func TestNetSinkTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink, err := logging.NewNetSink(logging.NetSinkConfig{
+		Protocol: logging.TCPSink,
+		Address:  ln.Addr().String(),
+	})
+	if err != nil {
+		t.Fatalf("NewNetSink() error = %v", err)
+	}
+
+	for _, msg := range []string{`{"msg":"one"}`, `{"msg":"two"}`} {
+		if _, err := sink.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	for _, want := range []string{`{"msg":"one"}`, `{"msg":"two"}`} {
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Errorf("expected line %q, got %q", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if m := sink.Metrics(); m.Sent != 2 {
+		t.Errorf("expected 2 sent entries, got %d", m.Sent)
+	}
+}
+
+func TestNetSinkHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		// Fail the first request to exercise the retry path.
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer srv.Close()
+
+	sink, err := logging.NewNetSink(logging.NetSinkConfig{
+		Protocol: logging.HTTPSink,
+		Address:  srv.URL,
+		Backoff:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewNetSink() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(sink, nil))
+	logger.Info("forwarded message")
+
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "forwarded message") {
+		t.Fatalf("unexpected bodies received: %v", bodies)
+	}
+
+	m := sink.Metrics()
+	if m.Sent != 1 || m.Retries != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestNetSinkDropPolicy(t *testing.T) {
+	// Reserve a port and release it so dials fail fast.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	testCases := []struct {
+		name   string
+		policy logging.DropPolicy
+	}{
+		{name: "Drop oldest", policy: logging.DropOldest},
+		{name: "Drop newest", policy: logging.DropNewest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink, err := logging.NewNetSink(logging.NetSinkConfig{
+				Protocol:      logging.TCPSink,
+				Address:       addr,
+				BufferSize:    2,
+				BatchSize:     1,
+				MaxRetries:    5,
+				Backoff:       time.Hour,
+				FlushInterval: time.Hour,
+				DropPolicy:    tc.policy,
+			})
+			if err != nil {
+				t.Fatalf("NewNetSink() error = %v", err)
+			}
+
+			for i := 0; i < 10; i++ {
+				if _, err := sink.Write([]byte("entry")); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if err := sink.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			m := sink.Metrics()
+			if m.Dropped < 7 {
+				t.Errorf("expected at least 7 dropped entries, got %d", m.Dropped)
+			}
+			if m.Sent != 0 || m.Dropped+m.Failed != 10 {
+				t.Errorf("unexpected metrics: %+v", m)
+			}
+
+			if _, err := sink.Write([]byte("late")); err == nil {
+				t.Error("expected error writing to closed sink")
+			}
+		})
+	}
+}
+
+func TestNetSinkInvalidConfig(t *testing.T) {
+	if _, err := logging.NewNetSink(logging.NetSinkConfig{}); err == nil {
+		t.Error("expected error for empty address")
+	}
+
+	fs := afero.NewMemMapFs()
+	if err := fs.MkdirAll("/tmp", 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	cfg := logging.LogConfig{
+		Fs:      fs,
+		LogPath: "/tmp/netsink.log",
+		NetSink: &logging.NetSinkConfig{Protocol: 42, Address: "localhost:0"},
+	}
+	if _, err := cfg.ConfigureLogger(); err == nil {
+		t.Error("expected ConfigureLogger() to fail with invalid net sink")
+	}
+}
+
+func TestConfigureLoggerNetSinkReuse(t *testing.T) {
+	listen := func() string {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		t.Cleanup(func() { ln.Close() })
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					_, _ = io.Copy(io.Discard, conn)
+				}()
+			}
+		}()
+		return ln.Addr().String()
+	}
+
+	fs := afero.NewMemMapFs()
+	if err := fs.MkdirAll("/tmp", 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	cfg := logging.LogConfig{
+		Fs:      fs,
+		LogPath: "/tmp/netsink.log",
+		NetSink: &logging.NetSinkConfig{Protocol: logging.TCPSink, Address: listen()},
+	}
+
+	if _, err := cfg.ConfigureLogger(); err != nil {
+		t.Fatalf("ConfigureLogger() error = %v", err)
+	}
+	first := cfg.Sink()
+
+	if _, err := cfg.ConfigureLogger(); err != nil {
+		t.Fatalf("ConfigureLogger() error = %v", err)
+	}
+	if cfg.Sink() != first {
+		t.Error("expected the net sink to be reused when its config is unchanged")
+	}
+
+	cfg.NetSink = &logging.NetSinkConfig{Protocol: logging.TCPSink, Address: listen()}
+	if _, err := cfg.ConfigureLogger(); err != nil {
+		t.Fatalf("ConfigureLogger() error = %v", err)
+	}
+	if cfg.Sink() == first {
+		t.Error("expected a new net sink when its config changes")
+	}
+	if _, err := first.Write([]byte(`{"msg":"late"}`)); err == nil {
+		t.Error("expected the replaced net sink to be closed")
+	}
+
+	if err := cfg.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if cfg.Sink() != nil {
+		t.Error("expected no net sink after Close()")
+	}
+}
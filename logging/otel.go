@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the log attribute key holding the trace ID of the
+	// active span.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the log attribute key holding the span ID of the
+	// active span.
+	SpanIDKey = "span_id"
+)
+
+// TraceHandler is a slog.Handler that correlates log records with
+// OpenTelemetry traces. When the context passed with a record carries a
+// valid span context, the trace_id and span_id attributes are added to
+// the record before it is forwarded to the wrapped handler.
+//
+// **Attributes:**
+//
+// next: The handler that receives the enriched records.
+type TraceHandler struct {
+	next slog.Handler
+}
+
+// NewTraceHandler wraps a slog.Handler so that its records include the
+// trace and span IDs of the active OpenTelemetry span.
+//
+// **Parameters:**
+//
+// next: The handler to forward records to.
+//
+// **Returns:**
+//
+// *TraceHandler: A handler that adds trace correlation attributes.
+func NewTraceHandler(next slog.Handler) *TraceHandler {
+	return &TraceHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at the
+// given level.
+//
+// **Parameters:**
+//
+// ctx: Context for the log record.
+// level: The level of the record.
+//
+// **Returns:**
+//
+// bool: True if the wrapped handler is enabled for level.
+func (h *TraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the trace_id and span_id attributes to r when ctx carries
+// a valid span context, then forwards it to the wrapped handler.
+//
+// **Parameters:**
+//
+// ctx: Context for the log record, possibly carrying an active span.
+// r: The log record to handle.
+//
+// **Returns:**
+//
+// error: An error returned by the wrapped handler.
+func (h *TraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String(TraceIDKey, sc.TraceID().String()),
+			slog.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a TraceHandler whose wrapped handler includes attrs.
+//
+// **Parameters:**
+//
+// attrs: Attributes to add to the wrapped handler.
+//
+// **Returns:**
+//
+// slog.Handler: A new TraceHandler.
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewTraceHandler(h.next.WithAttrs(attrs))
+}
+
+// WithGroup returns a TraceHandler whose wrapped handler uses the named
+// group.
+//
+// **Parameters:**
+//
+// name: Name of the group.
+//
+// **Returns:**
+//
+// slog.Handler: A new TraceHandler.
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return NewTraceHandler(h.next.WithGroup(name))
+}
+
+// WithContext returns a Logger that passes ctx to its handlers with each
+// record, so that a TraceHandler can correlate the output with the span
+// active in ctx. Loggers that cannot carry a context are returned
+// unchanged.
+//
+// **Parameters:**
+//
+// ctx: Context carrying the active span.
+// logger: The Logger to bind to ctx.
+//
+// **Returns:**
+//
+// Logger: A Logger bound to ctx.
+func WithContext(ctx context.Context, logger Logger) Logger {
+	if cl, ok := logger.(interface {
+		WithContext(context.Context) Logger
+	}); ok {
+		return cl.WithContext(ctx)
+	}
+	return logger
+}
+
+// SpanErrorLogger returns a Logger bound to ctx that, in addition to
+// logging, records each Error and Errorf message as an exception event
+// on the span active in ctx. If ctx carries no recording span, the span
+// events are dropped and only the log records are written.
+//
+// **Parameters:**
+//
+// ctx: Context carrying the active span.
+// logger: The Logger to forward all messages to.
+//
+// **Returns:**
+//
+// Logger: A Logger that mirrors errors as span events.
+func SpanErrorLogger(ctx context.Context, logger Logger) Logger {
+	return &spanErrorLogger{
+		Logger: WithContext(ctx, logger),
+		span:   trace.SpanFromContext(ctx),
+	}
+}
+
+// spanErrorLogger forwards all messages to the embedded Logger and
+// records errors on span.
+type spanErrorLogger struct {
+	Logger
+	span trace.Span
+}
+
+// Error logs the arguments as an error and records them on the span.
+func (l *spanErrorLogger) Error(v ...interface{}) {
+	l.Logger.Error(v...)
+	l.record(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// Errorf logs the formatted message as an error and records it on the
+// span.
+func (l *spanErrorLogger) Errorf(format string, v ...interface{}) {
+	l.Logger.Errorf(format, v...)
+	l.record(fmt.Sprintf(format, v...))
+}
+
+// WithContext rebinds the logger to ctx, recording errors on the span
+// active in ctx.
+func (l *spanErrorLogger) WithContext(ctx context.Context) Logger {
+	return SpanErrorLogger(ctx, l.Logger)
+}
+
+// record adds msg to the span as an exception event.
+func (l *spanErrorLogger) record(msg string) {
+	l.span.RecordError(errors.New(msg),
+		trace.WithAttributes(attribute.String("log.severity", slog.LevelError.String())))
+}
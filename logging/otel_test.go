@@ -0,0 +1,134 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	log "github.com/l50/goutils/v2/logging"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan captures the errors recorded on it.
+type recordingSpan struct {
+	noop.Span
+	sc     trace.SpanContext
+	errors []string
+}
+
+func (s *recordingSpan) SpanContext() trace.SpanContext { return s.sc }
+func (s *recordingSpan) IsRecording() bool              { return true }
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.errors = append(s.errors, err.Error())
+}
+
+func testSpanContext(t *testing.T) trace.SpanContext {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func TestTraceHandler(t *testing.T) {
+	sc := testSpanContext(t)
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		wantTrace bool
+	}{
+		{
+			name:      "active span adds trace attributes",
+			ctx:       trace.ContextWithSpanContext(context.Background(), sc),
+			wantTrace: true,
+		},
+		{
+			name: "no span leaves record unchanged",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := log.NewTraceHandler(slog.NewJSONHandler(&buf, nil))
+			logger, err := log.NewPlainLogger(log.LogConfig{}, slog.New(handler))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			log.WithContext(tc.ctx, logger).Printf("hello")
+
+			var rec map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+				t.Fatalf("failed to decode record %q: %v", buf.String(), err)
+			}
+
+			gotTrace, hasTrace := rec[log.TraceIDKey]
+			gotSpan, hasSpan := rec[log.SpanIDKey]
+			if hasTrace != tc.wantTrace || hasSpan != tc.wantTrace {
+				t.Fatalf("trace attributes present = %v/%v, want %v", hasTrace, hasSpan, tc.wantTrace)
+			}
+			if tc.wantTrace {
+				if gotTrace != sc.TraceID().String() {
+					t.Errorf("%s = %v, want %s", log.TraceIDKey, gotTrace, sc.TraceID())
+				}
+				if gotSpan != sc.SpanID().String() {
+					t.Errorf("%s = %v, want %s", log.SpanIDKey, gotSpan, sc.SpanID())
+				}
+			}
+		})
+	}
+}
+
+func TestSpanErrorLogger(t *testing.T) {
+	span := &recordingSpan{sc: testSpanContext(t)}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	var buf bytes.Buffer
+	handler := log.NewTraceHandler(slog.NewJSONHandler(&buf, nil))
+	base, err := log.NewPlainLogger(log.LogConfig{}, slog.New(handler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := log.SpanErrorLogger(ctx, base)
+	logger.Println("not an error")
+	logger.Error("first", "failure")
+	logger.Errorf("second failure: %v", errors.New("boom"))
+
+	want := []string{"first failure", "second failure: boom"}
+	if len(span.errors) != len(want) {
+		t.Fatalf("recorded %d span errors %q, want %d", len(span.errors), span.errors, len(want))
+	}
+	for i := range want {
+		if span.errors[i] != want[i] {
+			t.Errorf("span error %d = %q, want %q", i, span.errors[i], want[i])
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d log lines, want 3", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, span.sc.TraceID().String()) {
+			t.Errorf("log line %q is missing the trace ID", line)
+		}
+	}
+}
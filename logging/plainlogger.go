@@ -16,6 +16,8 @@ import (
 type PlainLogger struct {
 	Info   LogConfig
 	Logger *slog.Logger
+
+	ctx context.Context
 }
 
 // NewPlainLogger creates a new PlainLogger instance with the specified
@@ -42,53 +44,79 @@ func NewPlainLogger(cfg LogConfig, logger *slog.Logger) (*PlainLogger, error) {
 // The arguments are converted to a string using fmt.Sprint.
 // PlainLogger.go
 func (l *PlainLogger) Println(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelInfo, fmt.Sprintln(v...))
+	l.Logger.Log(l.logContext(), slog.LevelInfo, fmt.Sprintln(v...))
 }
 
 // Printf for PlainLogger logs the provided formatted string using slog library.
 // The format and arguments are handled in the manner of fmt.Printf.
 func (l *PlainLogger) Printf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Error for PlainLogger logs the provided arguments as an error line
 // using slog library.
 // The arguments are converted to a string using fmt.Sprint.
 func (l *PlainLogger) Error(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelError, fmt.Sprintln(v...))
+	l.Logger.Log(l.logContext(), slog.LevelError, fmt.Sprintln(v...))
 }
 
 // Errorf for PlainLogger logs the provided formatted string as an error
 // line using slog library.
 // The format and arguments are handled in the manner of fmt.Printf.
 func (l *PlainLogger) Errorf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelError, fmt.Sprintf(format, v...))
 }
 
 // Debug for PlainLogger logs the provided arguments as a debug line
 // using slog library.
 // The arguments are converted to a string using fmt.Sprint.
 func (l *PlainLogger) Debug(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintln(v...))
+	l.Logger.Log(l.logContext(), slog.LevelDebug, fmt.Sprintln(v...))
 }
 
 // Debugf for PlainLogger logs the provided formatted string as a debug
 // line using slog library.
 // The format and arguments are handled in the manner of fmt.Printf.
 func (l *PlainLogger) Debugf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelDebug, fmt.Sprintf(format, v...))
 }
 
 // Warn for PlainLogger logs the provided arguments as a warning line
 // using slog library.
 // The arguments are converted to a string using fmt.Sprint.
 func (l *PlainLogger) Warn(v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelWarn, fmt.Sprintln(v...))
+	l.Logger.Log(l.logContext(), slog.LevelWarn, fmt.Sprintln(v...))
 }
 
 // Warnf for PlainLogger logs the provided formatted string as a warning
 // line using slog library.
 // The format and arguments are handled in the manner of fmt.Printf.
 func (l *PlainLogger) Warnf(format string, v ...interface{}) {
-	l.Logger.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
+	l.Logger.Log(l.logContext(), slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// WithContext returns a copy of the PlainLogger that passes ctx to its
+// handlers, allowing them to extract request-scoped values such as the
+// active trace span.
+//
+// **Parameters:**
+//
+// ctx: Context passed to the handlers with each log record.
+//
+// **Returns:**
+//
+// Logger: A copy of the PlainLogger bound to ctx.
+func (l *PlainLogger) WithContext(ctx context.Context) Logger {
+	clone := *l
+	clone.ctx = ctx
+	return &clone
+}
+
+// logContext returns the context bound with WithContext, or
+// context.Background if none has been set.
+func (l *PlainLogger) logContext() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
 }
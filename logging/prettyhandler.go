@@ -8,10 +8,8 @@ import (
 	"log"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/fatih/color"
-	"github.com/mattn/go-isatty"
 )
 
 // PrettyHandlerOptions represents options used for configuring
@@ -20,8 +18,11 @@ import (
 // **Attributes:**
 //
 // SlogOpts: Options for the underlying slog.Handler.
+// Theme: Optional Theme controlling colors, the timestamp format, and the
+// component prefix. DefaultTheme is used if nil.
 type PrettyHandlerOptions struct {
 	SlogOpts slog.HandlerOptions
+	Theme    *Theme
 }
 
 // PrettyHandler is a custom log handler that provides colorized
@@ -32,14 +33,20 @@ type PrettyHandlerOptions struct {
 //
 // Handler: The underlying slog.Handler used for logging.
 // l: Standard logger used for outputting log messages.
+// theme: Theme used to render log messages.
+// color: Whether log messages are colorized.
 type PrettyHandler struct {
 	slog.Handler
-	l *log.Logger
+	l     *log.Logger
+	theme *Theme
+	color bool
 }
 
 // NewPrettyHandler creates a new PrettyHandler with specified output
 // writer and options. It configures the PrettyHandler for handling
 // log messages with optional colorization and structured formatting.
+// Colors are only used if ColorEnabled reports true for out, so
+// NO_COLOR, CLICOLOR_FORCE, and redirected output are honored.
 //
 // **Parameters:**
 //
@@ -50,9 +57,15 @@ type PrettyHandler struct {
 //
 // *PrettyHandler: A new instance of PrettyHandler.
 func NewPrettyHandler(out io.Writer, opts PrettyHandlerOptions) *PrettyHandler {
+	theme := opts.Theme
+	if theme == nil {
+		theme = DefaultTheme()
+	}
 	h := &PrettyHandler{
 		Handler: slog.NewJSONHandler(out, &opts.SlogOpts),
 		l:       log.New(out, "", 0),
+		theme:   theme,
+		color:   ColorEnabled(out),
 	}
 	return h
 }
@@ -79,19 +92,42 @@ func (h *PrettyHandler) Handle(ctx context.Context, r slog.Record) error {
 		return h.outputJSON(fields)
 	}
 
-	return h.outputFormatted(fields, r.Level)
+	return h.outputFormatted(fields, r.Level, h.component(r))
 }
 
 // outputToFile determines if the output is being written to a file
-// rather than a terminal, in which case it returns true.
+// rather than a terminal, in which case it returns true. Output forced
+// to color with CLICOLOR_FORCE is treated as a terminal.
 //
 // **Returns:**
 //
 // bool: True if output is to a file, false otherwise.
 func (h *PrettyHandler) outputToFile() bool {
-	_, isFile := h.l.Writer().(*os.File)
-	isTerminal := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
-	return isFile && !isTerminal
+	w := h.l.Writer()
+	_, isFile := w.(*os.File)
+	return isFile && !isTerminal(w) && !h.color
+}
+
+// component returns the component prefix for a record: the value of its
+// "component" attribute if present, otherwise the theme's Component.
+//
+// **Parameters:**
+//
+// r: The log record.
+//
+// **Returns:**
+//
+// string: The component prefix, or an empty string if there is none.
+func (h *PrettyHandler) component(r slog.Record) string {
+	component := h.theme.Component
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "component" {
+			component = a.Value.String()
+			return false
+		}
+		return true
+	})
+	return component
 }
 
 // outputJSON marshals the log fields into JSON format and outputs
@@ -122,12 +158,17 @@ func (h *PrettyHandler) outputJSON(fields map[string]interface{}) error {
 //
 // fields: Log fields to be formatted and outputted.
 // level: Log level used for determining the color.
+// component: Optional component prefix printed before the message.
 //
 // **Returns:**
 //
 // error: An error if formatting or output fails.
-func (h *PrettyHandler) outputFormatted(fields map[string]interface{}, level slog.Level) error {
-	finalLogMsg := fmt.Sprintf("[%s] [%s] %s", fields["time"], h.colorizeBasedOnLevel(level), fields["msg"])
+func (h *PrettyHandler) outputFormatted(fields map[string]interface{}, level slog.Level, component string) error {
+	finalLogMsg := fmt.Sprintf("[%s] [%s] ", fields["time"], h.colorizeBasedOnLevel(level))
+	if component != "" {
+		finalLogMsg += fmt.Sprintf("[%s] ", h.colorize(h.theme.ComponentColor, component))
+	}
+	finalLogMsg += fmt.Sprint(fields["msg"])
 	h.l.Println(finalLogMsg)
 	return nil
 }
@@ -153,7 +194,7 @@ func (h *PrettyHandler) parseLogRecord(r slog.Record) (map[string]interface{}, e
 	} else {
 		// Consider non-JSON messages as valid and create a field map
 		fields = map[string]interface{}{
-			"time":  r.Time.Format(time.DateTime),
+			"time":  r.Time.Format(h.theme.timestampFormat()),
 			"level": r.Level.String(),
 			"msg":   r.Message,
 		}
@@ -162,8 +203,8 @@ func (h *PrettyHandler) parseLogRecord(r slog.Record) (map[string]interface{}, e
 	return fields, nil
 }
 
-// colorizeBasedOnLevel applies color to the given log level string
-// based on its severity.
+// colorizeBasedOnLevel applies the theme's color for the given log
+// level to the level string.
 //
 // **Parameters:**
 //
@@ -173,14 +214,29 @@ func (h *PrettyHandler) parseLogRecord(r slog.Record) (map[string]interface{}, e
 //
 // string: The colorized log level string.
 func (h *PrettyHandler) colorizeBasedOnLevel(level slog.Level) string {
-	// Create a new color object based on the log level
-	colorAttr := determineColorAttribute(level)
-	c := color.New(colorAttr)
-
-	// Apply color only to the level part
-	coloredLevel := c.Sprint(level.String())
+	return h.colorize(h.theme.LevelColor(level), level.String())
+}
 
-	return coloredLevel
+// colorize applies a color to s if the handler's output is colorized.
+//
+// **Parameters:**
+//
+// attr: The color attribute to apply.
+// s: The string to colorize.
+//
+// **Returns:**
+//
+// string: The colorized string, or s unchanged if color is disabled.
+func (h *PrettyHandler) colorize(attr color.Attribute, s string) string {
+	c := color.New(attr)
+	// Decide per handler rather than relying on fatih/color's global
+	// setting, which only looks at os.Stdout.
+	if h.color {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return c.Sprint(s)
 }
 
 // determineColorAttribute returns the color attribute corresponding
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// CleanupLogs deletes old log files from a directory, such as the logs
+// left behind by earlier runs of a tool on a long-running host. Files
+// whose name ends in ".log" or contains ".log." (e.g., rotated logs like
+// "app.log.1" or "app.log.gz") are considered; subdirectories aren't
+// searched. The newest keepLast log files are always kept, and of the
+// rest, those last modified more than olderThan ago are deleted.
+//
+// **Parameters:**
+//
+// dir: The directory containing the log files.
+// olderThan: The age after which log files are deleted. 0 deletes every
+// log file other than the newest keepLast.
+// keepLast: The number of most recently modified log files to keep
+// regardless of their age. 0 keeps none.
+//
+// **Returns:**
+//
+// []string: The paths of the deleted log files.
+// error: An error if the directory can't be read or a file can't be
+// deleted.
+func CleanupLogs(dir string, olderThan time.Duration, keepLast int) ([]string, error) {
+	return cleanupLogs(afero.NewOsFs(), dir, olderThan, keepLast, "")
+}
+
+// cleanupLogs implements CleanupLogs on fs, never deleting the file at
+// keep.
+func cleanupLogs(fs afero.Fs, dir string, olderThan time.Duration, keepLast int, keep string) ([]string, error) {
+	if olderThan < 0 || keepLast < 0 {
+		return nil, fmt.Errorf("olderThan and keepLast cannot be negative")
+	}
+
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+
+	var logs []string
+	modTimes := make(map[string]time.Time)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Mode().IsRegular() || !isLogFileName(name) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if keep != "" && filepath.Clean(path) == filepath.Clean(keep) {
+			continue
+		}
+		logs = append(logs, path)
+		modTimes[path] = entry.ModTime()
+	}
+
+	// Newest first, so the first keepLast files are the ones to keep.
+	sort.Slice(logs, func(i, j int) bool {
+		return modTimes[logs[i]].After(modTimes[logs[j]])
+	})
+
+	cutoff := time.Now().Add(-olderThan)
+	var removed []string
+	for i, path := range logs {
+		if i < keepLast || (olderThan > 0 && modTimes[path].After(cutoff)) {
+			continue
+		}
+		if err := fs.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to delete %s: %v", path, err)
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
+
+// isLogFileName reports whether name looks like a log file or a rotated
+// log file.
+func isLogFileName(name string) bool {
+	return strings.HasSuffix(name, ".log") || strings.Contains(name, ".log.")
+}
@@ -0,0 +1,151 @@
+package logging_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/logging"
+	"github.com/spf13/afero"
+)
+
+func TestCleanupLogs(t *testing.T) {
+	// Log files and their ages; other.txt and sub.log/ aren't log files.
+	ages := map[string]time.Duration{
+		"today.log":    time.Hour,
+		"app.log.1":    2 * 24 * time.Hour,
+		"app.log.2.gz": 5 * 24 * time.Hour,
+		"old.log":      10 * 24 * time.Hour,
+		"other.txt":    30 * 24 * time.Hour,
+	}
+
+	newDir := func(t *testing.T) string {
+		dir := t.TempDir()
+		for name, age := range ages {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+				t.Fatal(err)
+			}
+			modTime := time.Now().Add(-age)
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Mkdir(filepath.Join(dir, "sub.log"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	testCases := []struct {
+		name        string
+		olderThan   time.Duration
+		keepLast    int
+		wantRemoved []string
+		wantErr     bool
+	}{
+		{
+			name:        "Older than three days",
+			olderThan:   3 * 24 * time.Hour,
+			wantRemoved: []string{"app.log.2.gz", "old.log"},
+		},
+		{
+			name:        "Keep last overrides age",
+			olderThan:   time.Minute,
+			keepLast:    2,
+			wantRemoved: []string{"app.log.2.gz", "old.log"},
+		},
+		{
+			name:        "Keep last only",
+			keepLast:    3,
+			wantRemoved: []string{"old.log"},
+		},
+		{
+			name:      "Nothing old enough",
+			olderThan: 30 * 24 * time.Hour,
+		},
+		{
+			name:     "Negative keep last",
+			keepLast: -1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := newDir(t)
+
+			removed, err := logging.CleanupLogs(dir, tc.olderThan, tc.keepLast)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CleanupLogs() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			var names []string
+			for _, path := range removed {
+				names = append(names, filepath.Base(path))
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("%s was reported removed but still exists", path)
+				}
+			}
+			sort.Strings(names)
+			if len(names) != len(tc.wantRemoved) {
+				t.Fatalf("CleanupLogs() removed %v, want %v", names, tc.wantRemoved)
+			}
+			for i := range names {
+				if names[i] != tc.wantRemoved[i] {
+					t.Errorf("CleanupLogs() removed %v, want %v", names, tc.wantRemoved)
+				}
+			}
+
+			for _, kept := range []string{"other.txt", "sub.log"} {
+				if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+					t.Errorf("%s should not be removed: %v", kept, err)
+				}
+			}
+		})
+	}
+
+	if _, err := logging.CleanupLogs(filepath.Join(t.TempDir(), "missing"), time.Hour, 0); err == nil {
+		t.Error("CleanupLogs() expected an error for a missing directory")
+	}
+}
+
+func TestInitLoggingCleanup(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"logs/run1.log", "logs/run2.log", "logs/current.log"} {
+		if err := afero.WriteFile(fs, name, []byte("old run"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Chtimes(name, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &logging.LogConfig{
+		Fs:               fs,
+		LogPath:          "logs/current.log",
+		OutputType:       logging.PlainOutput,
+		LogToDisk:        true,
+		CleanupOlderThan: 24 * time.Hour,
+	}
+	if _, err := logging.InitLogging(cfg); err != nil {
+		t.Fatalf("InitLogging() failed: %v", err)
+	}
+
+	for name, wantExists := range map[string]bool{
+		"logs/run1.log":    false,
+		"logs/run2.log":    false,
+		"logs/current.log": true,
+	} {
+		exists, err := afero.Exists(fs, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != wantExists {
+			t.Errorf("%s exists = %v, want %v", name, exists, wantExists)
+		}
+	}
+}
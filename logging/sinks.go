@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Sink is an additional destination for the records of a logger, with
+// its own format and level. Sinks let one logger write, e.g., colored
+// text to the console and JSON to a file.
+//
+// **Attributes:**
+//
+// Writer: The writer the records are written to, e.g., os.Stderr. Either
+// Writer or Path must be set.
+// Path: The path of a file the records are appended to, opened with the
+// LogConfig's Fs. Its directory is created if it doesn't exist.
+// Format: The output format: PlainOutput, ColorOutput, JSONOutput, or
+// TextOutput.
+// Level: Optional minimum level of the records written to the sink. The
+// LogConfig's Level is used if nil.
+// Theme: Optional Theme used by ColorOutput sinks.
+// HandlerFactory: Optional HandlerFactory used to create the sink's
+// handler instead of the one selected by Format.
+type Sink struct {
+	Writer         io.Writer
+	Path           string
+	Format         OutputType
+	Level          slog.Leveler
+	Theme          *Theme
+	HandlerFactory HandlerFactory
+}
+
+// handler creates the slog.Handler writing to the sink.
+//
+// **Parameters:**
+//
+// fs: The file system used to open the sink's Path.
+// level: The level used if the sink doesn't set one.
+//
+// **Returns:**
+//
+// slog.Handler: The handler writing to the sink.
+// error: An error if the sink has no destination or its file can't be
+// opened.
+func (s Sink) handler(fs afero.Fs, level slog.Level) (slog.Handler, error) {
+	w := s.Writer
+	if w == nil {
+		if s.Path == "" {
+			return nil, errors.New("sink requires a Writer or a Path")
+		}
+		if fs == nil {
+			fs = afero.NewOsFs()
+		}
+		if err := fs.MkdirAll(filepath.Dir(s.Path), os.ModePerm); err != nil {
+			return nil, err
+		}
+		f, err := fs.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	if s.Level != nil {
+		opts.Level = s.Level
+	}
+
+	return newOutputHandler(w, s.Format, opts, s.Theme, s.HandlerFactory), nil
+}
+
+// newOutputHandler creates the handler for an output type, or uses
+// factory if it's set.
+//
+// **Parameters:**
+//
+// w: The writer the handler writes to.
+// outputType: The output format.
+// opts: The handler options.
+// theme: The Theme used for ColorOutput.
+// factory: Optional HandlerFactory overriding the output type.
+//
+// **Returns:**
+//
+// slog.Handler: The handler writing to w.
+func newOutputHandler(w io.Writer, outputType OutputType, opts *slog.HandlerOptions, theme *Theme, factory HandlerFactory) slog.Handler {
+	switch {
+	case factory != nil:
+		return factory(w, opts)
+	case outputType == ColorOutput:
+		return NewPrettyHandler(w, PrettyHandlerOptions{SlogOpts: *opts, Theme: theme})
+	case outputType == TextOutput:
+		return slog.NewTextHandler(w, opts)
+	default:
+		return slog.NewJSONHandler(w, opts)
+	}
+}
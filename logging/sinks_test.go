@@ -0,0 +1,98 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/logging"
+	"github.com/spf13/afero"
+)
+
+func TestLogConfigSinks(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	fs := afero.NewMemMapFs()
+	var console, debug bytes.Buffer
+	cfg := logging.LogConfig{
+		Fs:         fs,
+		Level:      slog.LevelInfo,
+		OutputType: logging.ColorOutput,
+		Sinks: []logging.Sink{
+			{Writer: &console, Format: logging.ColorOutput, Theme: &logging.Theme{Component: "app"}},
+			{Path: "/var/log/app/app.json", Format: logging.JSONOutput, Level: slog.LevelWarn},
+			{Writer: &debug, Format: logging.TextOutput, Level: slog.LevelDebug},
+		},
+	}
+
+	logger, err := cfg.ConfigureLogger()
+	if err != nil {
+		t.Fatalf("ConfigureLogger() error = %v", err)
+	}
+	logger.Debug("debug details")
+	logger.Printf("starting up")
+	logger.Warnf("disk almost full")
+
+	testCases := []struct {
+		name      string
+		output    string
+		expected  []string
+		forbidden []string
+	}{
+		{
+			name:      "Color console at the config level",
+			output:    console.String(),
+			expected:  []string{"[INFO] [app] starting up", "[WARN] [app] disk almost full"},
+			forbidden: []string{"debug details", `"msg"`},
+		},
+		{
+			name:      "Text with its own level",
+			output:    debug.String(),
+			expected:  []string{`level=DEBUG msg="debug details`, `msg="starting up"`, `msg="disk almost full"`},
+			forbidden: []string{"\x1b["},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, want := range tc.expected {
+				if !strings.Contains(tc.output, want) {
+					t.Errorf("Expected to find %q in the output, got %q", want, tc.output)
+				}
+			}
+			for _, unwanted := range tc.forbidden {
+				if strings.Contains(tc.output, unwanted) {
+					t.Errorf("Expected not to find %q in the output, got %q", unwanted, tc.output)
+				}
+			}
+		})
+	}
+
+	t.Run("JSON file with a higher level", func(t *testing.T) {
+		content, err := afero.ReadFile(fs, "/var/log/app/app.json")
+		if err != nil {
+			t.Fatalf("failed to read sink file: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("expected only the warning in the file, got %q", content)
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+			t.Fatalf("file record isn't JSON: %v", err)
+		}
+		if record["level"] != "WARN" || record["msg"] != "disk almost full" {
+			t.Errorf("unexpected file record %v", record)
+		}
+	})
+
+	t.Run("Sink without destination", func(t *testing.T) {
+		cfg := logging.LogConfig{Fs: fs, Sinks: []logging.Sink{{Format: logging.JSONOutput}}}
+		if _, err := cfg.ConfigureLogger(); err == nil {
+			t.Error("ConfigureLogger() expected an error for a sink without Writer or Path")
+		}
+	})
+}
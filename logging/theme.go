@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Theme configures how the PrettyHandler renders console output.
+//
+// **Attributes:**
+//
+// LevelColors: The color used for each log level. Levels without an
+// entry are printed without color.
+// TimestampFormat: The time.Format layout used for timestamps. An empty
+// string uses time.DateTime.
+// Component: Optional prefix printed before every message, e.g., the
+// name of the tool or subsystem producing the logs. A "component"
+// attribute on a record takes precedence.
+// ComponentColor: The color used for the component prefix.
+type Theme struct {
+	LevelColors     map[slog.Level]color.Attribute
+	TimestampFormat string
+	Component       string
+	ComponentColor  color.Attribute
+}
+
+// DefaultTheme returns the theme used when none is configured: magenta
+// debug, blue info, yellow warning, and red error levels with
+// time.DateTime timestamps and no component prefix.
+//
+// **Returns:**
+//
+// *Theme: A new instance of the default theme.
+func DefaultTheme() *Theme {
+	return &Theme{
+		LevelColors: map[slog.Level]color.Attribute{
+			slog.LevelDebug: color.FgMagenta,
+			slog.LevelInfo:  color.FgBlue,
+			slog.LevelWarn:  color.FgYellow,
+			slog.LevelError: color.FgRed,
+		},
+		TimestampFormat: time.DateTime,
+		ComponentColor:  color.FgCyan,
+	}
+}
+
+// LevelColor returns the color attribute the theme uses for a log level.
+//
+// **Parameters:**
+//
+// level: Log level for which to determine the color.
+//
+// **Returns:**
+//
+// color.Attribute: The color attribute for the level, or color.Reset if
+// the theme doesn't define one.
+func (t *Theme) LevelColor(level slog.Level) color.Attribute {
+	if t == nil {
+		return determineColorAttribute(level)
+	}
+	if attr, ok := t.LevelColors[level]; ok {
+		return attr
+	}
+	return color.Reset
+}
+
+// timestampFormat returns the theme's timestamp layout, falling back to
+// time.DateTime.
+func (t *Theme) timestampFormat() string {
+	if t == nil || t.TimestampFormat == "" {
+		return time.DateTime
+	}
+	return t.TimestampFormat
+}
+
+// ColorEnabled reports whether colored output should be written to w.
+// Following the conventions at https://no-color.org and
+// https://bixense.com/clicolors, a non-empty NO_COLOR environment
+// variable disables color, a CLICOLOR_FORCE value other than "0" forces
+// it, and otherwise color is only used if w is a terminal.
+//
+// **Parameters:**
+//
+// w: The writer the output is written to.
+//
+// **Returns:**
+//
+// bool: True if the output should be colorized.
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force, ok := os.LookupEnv("CLICOLOR_FORCE"); ok && force != "" && force != "0" {
+		return true
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,134 @@
+package logging_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/l50/goutils/v2/logging"
+)
+
+func TestColorEnabled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		noColor  string
+		force    string
+		expected bool
+	}{
+		{
+			name:     "Non-terminal writer",
+			expected: false,
+		},
+		{
+			name:     "CLICOLOR_FORCE enables color",
+			force:    "1",
+			expected: true,
+		},
+		{
+			name:     "CLICOLOR_FORCE=0 is ignored",
+			force:    "0",
+			expected: false,
+		},
+		{
+			name:     "NO_COLOR wins over CLICOLOR_FORCE",
+			noColor:  "1",
+			force:    "1",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("CLICOLOR_FORCE", tc.force)
+
+			f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+			if err != nil {
+				t.Fatalf("failed to create output file: %v", err)
+			}
+			defer f.Close()
+
+			if got := logging.ColorEnabled(f); got != tc.expected {
+				t.Errorf("ColorEnabled() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPrettyHandlerTheme(t *testing.T) {
+	recordTime := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		force     string
+		theme     *logging.Theme
+		attrs     []slog.Attr
+		level     slog.Level
+		expected  []string
+		forbidden []string
+	}{
+		{
+			name:  "Custom level color and timestamp",
+			force: "1",
+			theme: &logging.Theme{
+				LevelColors:     map[slog.Level]color.Attribute{slog.LevelInfo: color.FgGreen},
+				TimestampFormat: time.Kitchen,
+			},
+			level:    slog.LevelInfo,
+			expected: []string{"[3:04PM]", "\x1b[32mINFO\x1b[0m", "test message"},
+		},
+		{
+			name:      "Component prefix from theme",
+			theme:     &logging.Theme{Component: "deploy"},
+			level:     slog.LevelWarn,
+			expected:  []string{"[WARN] [deploy] test message"},
+			forbidden: []string{"\x1b["},
+		},
+		{
+			name:      "Component attribute overrides theme",
+			theme:     &logging.Theme{Component: "deploy"},
+			attrs:     []slog.Attr{slog.String("component", "docs")},
+			level:     slog.LevelError,
+			expected:  []string{"[ERROR] [docs] test message"},
+			forbidden: []string{"deploy"},
+		},
+		{
+			name:      "Default theme without color",
+			level:     slog.LevelDebug,
+			expected:  []string{"[2024-01-02 15:04:05] [DEBUG] test message"},
+			forbidden: []string{"\x1b["},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("CLICOLOR_FORCE", tc.force)
+
+			var buf strings.Builder
+			prettyHandler := logging.NewPrettyHandler(&buf, logging.PrettyHandlerOptions{Theme: tc.theme})
+
+			record := slog.NewRecord(recordTime, tc.level, "test message", 0)
+			record.AddAttrs(tc.attrs...)
+			if err := prettyHandler.Handle(context.Background(), record); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			output := buf.String()
+			for _, want := range tc.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected to find %q in the output, got %q", want, output)
+				}
+			}
+			for _, unwanted := range tc.forbidden {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected not to find %q in the output, got %q", unwanted, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package str
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// base58Alphabet is the Bitcoin alphabet, which leaves out 0, O, I,
+	// and l so that encoded values can't be misread.
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// base62Alphabet contains the digits and letters, so that encoded
+	// values are safe in URLs, file names, and identifiers.
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// ErrInvalidEncoding is wrapped by the errors of the Decode functions
+// when their input isn't validly encoded.
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+// EncodeBase32 encodes data with the standard base32 alphabet of RFC
+// 4648.
+//
+// **Parameters:**
+//
+// data: The bytes to encode.
+// padding: Whether to pad the output with "=" to a multiple of eight
+// characters.
+//
+// **Returns:**
+//
+// string: The encoded data.
+func EncodeBase32(data []byte, padding bool) string {
+	if padding {
+		return base32.StdEncoding.EncodeToString(data)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(data)
+}
+
+// DecodeBase32 decodes base32 encoded with the standard alphabet of RFC
+// 4648. The input may be padded or not and is case-insensitive.
+//
+// **Parameters:**
+//
+// s: The string to decode.
+//
+// **Returns:**
+//
+// []byte: The decoded data.
+// error: An error wrapping ErrInvalidEncoding if s isn't valid base32.
+func DecodeBase32(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.TrimRight(s, "="))
+	data, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: base32: %v", ErrInvalidEncoding, err)
+	}
+
+	return data, nil
+}
+
+// EncodeBase58 encodes data with the Bitcoin base58 alphabet. Leading
+// zero bytes are encoded as leading "1" characters, so they survive a
+// round trip.
+//
+// **Parameters:**
+//
+// data: The bytes to encode.
+//
+// **Returns:**
+//
+// string: The encoded data.
+func EncodeBase58(data []byte) string {
+	return encodeBaseN(data, base58Alphabet)
+}
+
+// DecodeBase58 decodes base58 encoded with the Bitcoin alphabet.
+//
+// **Parameters:**
+//
+// s: The string to decode.
+//
+// **Returns:**
+//
+// []byte: The decoded data.
+// error: An error wrapping ErrInvalidEncoding if s contains a character
+// outside of the alphabet.
+func DecodeBase58(s string) ([]byte, error) {
+	return decodeBaseN(s, base58Alphabet, "base58")
+}
+
+// EncodeBase62 encodes data with the alphabet 0-9, A-Z, a-z. Leading zero
+// bytes are encoded as leading "0" characters, so they survive a round
+// trip.
+//
+// **Parameters:**
+//
+// data: The bytes to encode.
+//
+// **Returns:**
+//
+// string: The encoded data.
+func EncodeBase62(data []byte) string {
+	return encodeBaseN(data, base62Alphabet)
+}
+
+// DecodeBase62 decodes base62 encoded with the alphabet 0-9, A-Z, a-z.
+//
+// **Parameters:**
+//
+// s: The string to decode.
+//
+// **Returns:**
+//
+// []byte: The decoded data.
+// error: An error wrapping ErrInvalidEncoding if s contains a character
+// outside of the alphabet.
+func DecodeBase62(s string) ([]byte, error) {
+	return decodeBaseN(s, base62Alphabet, "base62")
+}
+
+// EncodeHex encodes data as lowercase hexadecimal.
+//
+// **Parameters:**
+//
+// data: The bytes to encode.
+//
+// **Returns:**
+//
+// string: The encoded data.
+func EncodeHex(data []byte) string {
+	return hex.EncodeToString(data)
+}
+
+// DecodeHex decodes hexadecimal in either case.
+//
+// **Parameters:**
+//
+// s: The string to decode.
+//
+// **Returns:**
+//
+// []byte: The decoded data.
+// error: An error wrapping ErrInvalidEncoding if s isn't valid
+// hexadecimal.
+func DecodeHex(s string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: hex: %v", ErrInvalidEncoding, err)
+	}
+
+	return data, nil
+}
+
+// encodeBaseN encodes data as a big-endian number in the base of the
+// alphabet, keeping one zero digit for each leading zero byte.
+func encodeBaseN(data []byte, alphabet string) string {
+	base := len(alphabet)
+
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// digits holds the number in little-endian order.
+	digits := make([]byte, 0, len(data)*2)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % base)
+			carry /= base
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%base))
+			carry /= base
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(zeros + len(digits))
+	for i := 0; i < zeros; i++ {
+		sb.WriteByte(alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteByte(alphabet[digits[i]])
+	}
+
+	return sb.String()
+}
+
+// decodeBaseN reverses encodeBaseN.
+func decodeBaseN(s, alphabet, name string) ([]byte, error) {
+	base := len(alphabet)
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// value holds the number in little-endian order.
+	var value []byte
+	for i, r := range s[zeros:] {
+		carry := strings.IndexRune(alphabet, r)
+		if carry < 0 {
+			return nil, fmt.Errorf("%w: invalid %s character %q at position %d", ErrInvalidEncoding, name, r, zeros+i)
+		}
+		for j := range value {
+			carry += int(value[j]) * base
+			value[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			value = append(value, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	data := make([]byte, zeros+len(value))
+	for i, b := range value {
+		data[len(data)-1-i] = b
+	}
+
+	return data, nil
+}
@@ -0,0 +1,154 @@
+package str_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	str "github.com/l50/goutils/v2/str"
+)
+
+func TestBaseNEncoding(t *testing.T) {
+	testCases := []struct {
+		name   string
+		encode func([]byte) string
+		decode func(string) ([]byte, error)
+		data   []byte
+		want   string
+	}{
+		{
+			name:   "Base58",
+			encode: str.EncodeBase58,
+			decode: str.DecodeBase58,
+			data:   []byte("Hello World!"),
+			want:   "2NEpo7TZRRrLZSi2U",
+		},
+		{
+			name:   "Base58 leading zeros",
+			encode: str.EncodeBase58,
+			decode: str.DecodeBase58,
+			data:   []byte{0, 0, 1},
+			want:   "112",
+		},
+		{
+			name:   "Base62",
+			encode: str.EncodeBase62,
+			decode: str.DecodeBase62,
+			data:   []byte("Hello World!"),
+			want:   "T8dgcjRGkZ3aysdN",
+		},
+		{
+			name:   "Base62 leading zeros",
+			encode: str.EncodeBase62,
+			decode: str.DecodeBase62,
+			data:   []byte{0, 61},
+			want:   "0z",
+		},
+		{
+			name:   "Base32 padded",
+			encode: func(b []byte) string { return str.EncodeBase32(b, true) },
+			decode: str.DecodeBase32,
+			data:   []byte("hi"),
+			want:   "NBUQ====",
+		},
+		{
+			name:   "Base32 unpadded",
+			encode: func(b []byte) string { return str.EncodeBase32(b, false) },
+			decode: str.DecodeBase32,
+			data:   []byte("hi"),
+			want:   "NBUQ",
+		},
+		{
+			name:   "Hex",
+			encode: str.EncodeHex,
+			decode: str.DecodeHex,
+			data:   []byte{0xde, 0xad, 0xbe, 0xef},
+			want:   "deadbeef",
+		},
+		{
+			name:   "Empty",
+			encode: str.EncodeBase58,
+			decode: str.DecodeBase58,
+			data:   []byte{},
+			want:   "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.encode(tc.data); got != tc.want {
+				t.Errorf("encode() = %q, want %q", got, tc.want)
+			}
+
+			got, err := tc.decode(tc.want)
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !bytes.Equal(got, tc.data) {
+				t.Errorf("decode() = %v, want %v", got, tc.data)
+			}
+		})
+	}
+}
+
+func TestBaseNRoundTrip(t *testing.T) {
+	codecs := map[string]struct {
+		encode func([]byte) string
+		decode func(string) ([]byte, error)
+	}{
+		"Base58": {str.EncodeBase58, str.DecodeBase58},
+		"Base62": {str.EncodeBase62, str.DecodeBase62},
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			for size := 1; size <= 64; size++ {
+				data := make([]byte, size)
+				if _, err := rand.Read(data); err != nil {
+					t.Fatal(err)
+				}
+				data[0] = 0
+
+				got, err := codec.decode(codec.encode(data))
+				if err != nil {
+					t.Fatalf("decode() error = %v", err)
+				}
+				if !bytes.Equal(got, data) {
+					t.Fatalf("round trip of %x = %x", data, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		decode func(string) ([]byte, error)
+		input  string
+	}{
+		{name: "Base58 excluded letter", decode: str.DecodeBase58, input: "2NEpo0"},
+		{name: "Base62 symbol", decode: str.DecodeBase62, input: "abc-def"},
+		{name: "Base62 non-ASCII", decode: str.DecodeBase62, input: "abcé"},
+		{name: "Base32 invalid character", decode: str.DecodeBase32, input: "NBUQ1"},
+		{name: "Hex odd length", decode: str.DecodeHex, input: "abc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.decode(tc.input); !errors.Is(err, str.ErrInvalidEncoding) {
+				t.Errorf("decode(%q) error = %v, want ErrInvalidEncoding", tc.input, err)
+			}
+		})
+	}
+}
+
+func TestDecodeBase32Lenient(t *testing.T) {
+	for _, input := range []string{"NBUQ====", "NBUQ", "nbuq"} {
+		got, err := str.DecodeBase32(input)
+		if err != nil || string(got) != "hi" {
+			t.Errorf("DecodeBase32(%q) = %q, %v, want \"hi\"", input, got, err)
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // GenRandom generates a random string of a specified length.
@@ -135,3 +138,291 @@ func StripANSI(str string) string {
 	re := regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
 	return re.ReplaceAllString(str, "")
 }
+
+// SplitRespectingQuotes splits a string on a separator, ignoring any
+// separators that appear inside single or double quotes. Quoted sections
+// are preserved as-is, including their quote characters. If sep is
+// empty, the string is split on runs of whitespace instead, and empty
+// fields are omitted.
+//
+// **Parameters:**
+//
+// s: String to split.
+// sep: Separator to split on, or "" to split on whitespace.
+//
+// **Returns:**
+//
+// []string: The fields of s. An unterminated quote extends to the end
+// of the string.
+func SplitRespectingQuotes(s, sep string) []string {
+	var fields []string
+	var current strings.Builder
+	var quote rune
+	inField := false
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case sep == "" && unicode.IsSpace(r):
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+			i += size
+			continue
+		case sep != "" && strings.HasPrefix(s[i:], sep):
+			fields = append(fields, current.String())
+			current.Reset()
+			i += len(sep)
+			continue
+		}
+
+		current.WriteRune(r)
+		inField = true
+		i += size
+	}
+
+	if sep != "" || inField {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
+
+// FieldsN splits a string on runs of whitespace into at most n fields,
+// like awk does for columns. The last field holds the remainder of the
+// string with its internal spacing intact, which makes FieldsN suited to
+// command output whose final column may contain spaces (e.g., the
+// command column of `ps`).
+//
+// **Parameters:**
+//
+// s: String to split.
+// n: Maximum number of fields to return. If n <= 0, every field is
+// returned, as with strings.Fields.
+//
+// **Returns:**
+//
+// []string: The fields of s.
+func FieldsN(s string, n int) []string {
+	if n <= 0 {
+		return strings.Fields(s)
+	}
+
+	var fields []string
+	rest := strings.TrimSpace(s)
+	for rest != "" && len(fields) < n-1 {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			break
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+
+	if rest != "" {
+		fields = append(fields, rest)
+	}
+
+	return fields
+}
+
+// HumanBytes formats a byte count using binary (IEC) units, such as
+// "512 B", "1.5 KiB", or "3.2 GiB".
+//
+// **Parameters:**
+//
+// n: The number of bytes.
+//
+// **Returns:**
+//
+// string: The byte count with one decimal place in the largest unit that
+// keeps the value at or above 1.
+func HumanBytes(n int64) string {
+	const unit = 1024
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = uint64(-(n + 1)) + 1 // avoid overflow for math.MinInt64
+	}
+
+	if u < unit {
+		return fmt.Sprintf("%s%d B", sign, u)
+	}
+
+	value := float64(u)
+	i := -1
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+
+	// Rounding to one decimal may carry the value into the next unit.
+	if value >= unit-0.05 && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+
+	return fmt.Sprintf("%s%.1f %s", sign, value, units[i])
+}
+
+// HumanDuration formats a duration using its two most significant units,
+// such as "1h 2m", "3d 4h", or "45s". Durations of a second or more are
+// truncated to whole seconds, and shorter durations are formatted as by
+// time.Duration.String (e.g., "250ms").
+//
+// **Parameters:**
+//
+// d: The duration to format.
+//
+// **Returns:**
+//
+// string: The human-readable duration. The second unit is omitted when
+// it's zero.
+func HumanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	if d < time.Second {
+		return sign + d.String()
+	}
+
+	units := []struct {
+		size  time.Duration
+		label string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	for i, u := range units {
+		if d < u.size {
+			continue
+		}
+		out := fmt.Sprintf("%s%d%s", sign, d/u.size, u.label)
+		if i+1 < len(units) {
+			next := units[i+1]
+			if rest := (d % u.size) / next.size; rest > 0 {
+				out += fmt.Sprintf(" %d%s", rest, next.label)
+			}
+		}
+		return out
+	}
+
+	return sign + d.String()
+}
+
+// Pluralize formats a count followed by the singular or plural form of a
+// noun, such as "1 file" or "3 files".
+//
+// **Parameters:**
+//
+// n: The count.
+// singular: The noun to use when n is 1 or -1.
+// plural: The noun to use otherwise. If empty, singular + "s" is used.
+//
+// **Returns:**
+//
+// string: The count and the matching form of the noun.
+func Pluralize(n int, singular, plural string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+
+	if plural == "" {
+		plural = singular + "s"
+	}
+
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// Distance returns the Levenshtein distance between two strings: the
+// minimum number of single-character insertions, deletions, and
+// substitutions needed to turn a into b. Characters are compared as
+// runes, so multi-byte characters count as one.
+//
+// **Parameters:**
+//
+// a: The first string.
+// b: The second string.
+//
+// **Returns:**
+//
+// int: The edit distance between a and b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// Only the previous row of the distance matrix is needed.
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// ClosestMatch returns the candidate closest to input by Levenshtein
+// distance, ignoring case, for "did you mean" suggestions and loose
+// matching of names such as tags or branches. Candidates further than a
+// third of the input's length (at least 1) are ignored so unrelated
+// strings aren't suggested. Ties go to the earliest candidate.
+//
+// **Parameters:**
+//
+// input: The string to match, e.g., a mistyped command.
+// candidates: The valid strings.
+//
+// **Returns:**
+//
+// string: The closest candidate, or an empty string if none is close
+// enough.
+func ClosestMatch(input string, candidates []string) string {
+	lowered := strings.ToLower(input)
+	maxDistance := max(1, (utf8.RuneCountInString(input)+2)/3)
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		d := Distance(lowered, strings.ToLower(candidate))
+		if d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+		if d == 0 {
+			break
+		}
+	}
+
+	return best
+}
@@ -3,6 +3,7 @@ package str_test
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/l50/goutils/v2/str"
 )
@@ -50,3 +51,54 @@ func ExampleSlicesEqual() {
 	fmt.Println(isEqual)
 	// Output: true
 }
+
+func ExampleSplitRespectingQuotes() {
+	fields := str.SplitRespectingQuotes(`name,"Doe, Jane",42`, ",")
+	fmt.Println(len(fields), fields[1])
+	// Output: 3 "Doe, Jane"
+}
+
+func ExampleFieldsN() {
+	fields := str.FieldsN("1234 S /usr/bin/python3 -m http.server", 3)
+	fmt.Println(fields[2])
+	// Output: /usr/bin/python3 -m http.server
+}
+
+func ExampleHumanBytes() {
+	fmt.Println(str.HumanBytes(1536))
+	// Output: 1.5 KiB
+}
+
+func ExampleHumanDuration() {
+	fmt.Println(str.HumanDuration(time.Hour + 2*time.Minute + 3*time.Second))
+	// Output: 1h 2m
+}
+
+func ExamplePluralize() {
+	fmt.Println(str.Pluralize(3, "file", ""))
+	// Output: 3 files
+}
+
+func ExampleDistance() {
+	fmt.Println(str.Distance("kitten", "sitting"))
+	// Output: 3
+}
+
+func ExampleClosestMatch() {
+	fmt.Println(str.ClosestMatch("stauts", []string{"commit", "push", "status"}))
+	// Output: status
+}
+
+func ExampleEncodeBase58() {
+	encoded := str.EncodeBase58([]byte("Hello World!"))
+	fmt.Println(encoded)
+
+	decoded, err := str.DecodeBase58(encoded)
+	if err != nil {
+		log.Fatalf("failed to decode: %v", err)
+	}
+	fmt.Println(string(decoded))
+	// Output:
+	// 2NEpo7TZRRrLZSi2U
+	// Hello World!
+}
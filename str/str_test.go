@@ -1,8 +1,10 @@
 package str_test
 
 import (
+	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	str "github.com/l50/goutils/v2/str"
 )
@@ -267,3 +269,243 @@ func TestStripANSI(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitRespectingQuotes(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		sep  string
+		want []string
+	}{
+		{
+			name: "Comma separated with quoted comma",
+			s:    `a,"b,c",d`,
+			sep:  ",",
+			want: []string{"a", `"b,c"`, "d"},
+		},
+		{
+			name: "Single quotes and multi-character separator",
+			s:    `key=>'x=>y'=>z`,
+			sep:  "=>",
+			want: []string{"key", "'x=>y'", "z"},
+		},
+		{
+			name: "Empty fields are kept with a separator",
+			s:    "a,,b,",
+			sep:  ",",
+			want: []string{"a", "", "b", ""},
+		},
+		{
+			name: "Whitespace split",
+			s:    `  git commit -m "initial commit"  --author 'A B' `,
+			sep:  "",
+			want: []string{"git", "commit", "-m", `"initial commit"`, "--author", "'A B'"},
+		},
+		{
+			name: "Other quote kind inside quotes",
+			s:    `say "it's fine" now`,
+			sep:  " ",
+			want: []string{"say", `"it's fine"`, "now"},
+		},
+		{
+			name: "Unterminated quote",
+			s:    `a "b c`,
+			sep:  "",
+			want: []string{"a", `"b c`},
+		},
+		{
+			name: "Empty string on whitespace",
+			s:    "",
+			sep:  "",
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := str.SplitRespectingQuotes(tc.s, tc.sep)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitRespectingQuotes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFieldsN(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		n    int
+		want []string
+	}{
+		{
+			name: "Remainder keeps internal spacing",
+			s:    "  1234  1  S   /usr/bin/python3  -m   http.server  ",
+			n:    4,
+			want: []string{"1234", "1", "S", "/usr/bin/python3  -m   http.server"},
+		},
+		{
+			name: "Fewer fields than n",
+			s:    "a b",
+			n:    5,
+			want: []string{"a", "b"},
+		},
+		{
+			name: "n of one returns trimmed string",
+			s:    "\ta  b\n",
+			n:    1,
+			want: []string{"a  b"},
+		},
+		{
+			name: "Non-positive n returns all fields",
+			s:    "a  b\tc",
+			n:    0,
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "Blank string",
+			s:    "   ",
+			n:    3,
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := str.FieldsN(tc.s, tc.n)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FieldsN() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	testCases := []struct {
+		name string
+		n    int64
+		want string
+	}{
+		{name: "Zero", n: 0, want: "0 B"},
+		{name: "Bytes", n: 512, want: "512 B"},
+		{name: "Just under a KiB", n: 1023, want: "1023 B"},
+		{name: "KiB", n: 1536, want: "1.5 KiB"},
+		{name: "Exact MiB", n: 1 << 20, want: "1.0 MiB"},
+		{name: "Rounds into next unit", n: 1<<20 - 1, want: "1.0 MiB"},
+		{name: "GiB", n: 3435973837, want: "3.2 GiB"},
+		{name: "Negative", n: -2048, want: "-2.0 KiB"},
+		{name: "Max", n: math.MaxInt64, want: "8.0 EiB"},
+		{name: "Min", n: math.MinInt64, want: "-8.0 EiB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := str.HumanBytes(tc.n); got != tc.want {
+				t.Errorf("HumanBytes(%d) = %q, want %q", tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	testCases := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{name: "Zero", d: 0, want: "0s"},
+		{name: "Sub-second", d: 250 * time.Millisecond, want: "250ms"},
+		{name: "Seconds", d: 45*time.Second + 900*time.Millisecond, want: "45s"},
+		{name: "Minutes and seconds", d: 90 * time.Second, want: "1m 30s"},
+		{name: "Hours and minutes", d: time.Hour + 2*time.Minute + 3*time.Second, want: "1h 2m"},
+		{name: "Zero second unit omitted", d: time.Hour + 5*time.Second, want: "1h"},
+		{name: "Days", d: 26 * time.Hour, want: "1d 2h"},
+		{name: "Negative", d: -90 * time.Second, want: "-1m 30s"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := str.HumanDuration(tc.d); got != tc.want {
+				t.Errorf("HumanDuration(%v) = %q, want %q", tc.d, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		n        int
+		singular string
+		plural   string
+		want     string
+	}{
+		{name: "Singular", n: 1, singular: "file", want: "1 file"},
+		{name: "Default plural", n: 3, singular: "file", want: "3 files"},
+		{name: "Zero is plural", n: 0, singular: "job", want: "0 jobs"},
+		{name: "Irregular plural", n: 2, singular: "child", plural: "children", want: "2 children"},
+		{name: "Negative one", n: -1, singular: "byte", want: "-1 byte"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := str.Pluralize(tc.n, tc.singular, tc.plural); got != tc.want {
+				t.Errorf("Pluralize() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "Identical", a: "main", b: "main", want: 0},
+		{name: "Empty strings", a: "", b: "", want: 0},
+		{name: "One empty", a: "", b: "tag", want: 3},
+		{name: "Substitution", a: "v1.2.0", b: "v1.3.0", want: 1},
+		{name: "Insertion and deletion", a: "kitten", b: "sitting", want: 3},
+		{name: "Transposition", a: "stauts", b: "status", want: 2},
+		{name: "Case sensitive", a: "Main", b: "main", want: 1},
+		{name: "Multi-byte runes", a: "café", b: "cafe", want: 1},
+		{name: "Symmetric", a: "sitting", b: "kitten", want: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := str.Distance(tc.a, tc.b); got != tc.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	commands := []string{"commit", "checkout", "push", "pull", "status"}
+
+	testCases := []struct {
+		name       string
+		input      string
+		candidates []string
+		want       string
+	}{
+		{name: "Typo", input: "stauts", candidates: commands, want: "status"},
+		{name: "Exact match", input: "push", candidates: commands, want: "push"},
+		{name: "Ignores case", input: "CHECKOUT", candidates: commands, want: "checkout"},
+		{name: "Tie goes to first candidate", input: "pusl", candidates: commands, want: "push"},
+		{name: "Branch name", input: "feature/login-from", candidates: []string{"main", "feature/login-form"}, want: "feature/login-form"},
+		{name: "Too far", input: "deploy", candidates: commands, want: ""},
+		{name: "No candidates", input: "push", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := str.ClosestMatch(tc.input, tc.candidates); got != tc.want {
+				t.Errorf("ClosestMatch(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
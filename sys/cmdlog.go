@@ -0,0 +1,86 @@
+package sys
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/l50/goutils/v2/logging"
+)
+
+// commandLog emits the debug records of a command run with a
+// logging.Logger. Its methods do nothing on a nil *commandLog, so callers
+// don't need to check whether a logger was configured.
+type commandLog struct {
+	logger logging.Logger
+	name   string
+	start  time.Time
+}
+
+// startCommandLog logs that a command is starting and returns the
+// commandLog for its output and result, or nil if logger is nil.
+//
+// **Parameters:**
+//
+// logger: The logger the records are written to. May be nil.
+// cmd: The command being run.
+// args: The arguments of the command.
+// dir: The working directory of the command. Empty for the current one.
+//
+// **Returns:**
+//
+// *commandLog: The log of the command, or nil if logger is nil.
+func startCommandLog(logger logging.Logger, cmd string, args []string, dir string) *commandLog {
+	if logger == nil {
+		return nil
+	}
+
+	line := strings.TrimSpace(cmd + " " + strings.Join(args, " "))
+	if dir != "" {
+		logger.Debugf("running command: %s (dir: %s)", line, dir)
+	} else {
+		logger.Debugf("running command: %s", line)
+	}
+
+	return &commandLog{logger: logger, name: cmd, start: time.Now()}
+}
+
+// output logs a line of output from one of the command's streams.
+func (l *commandLog) output(stream, line string) {
+	if l == nil {
+		return
+	}
+	l.logger.Debugf("%s [%s]: %s", l.name, stream, line)
+}
+
+// finish logs the duration and exit code of the command. err is the
+// error from starting or waiting for it.
+func (l *commandLog) finish(err error) {
+	if l == nil {
+		return
+	}
+
+	duration := time.Since(l.start).Round(time.Millisecond)
+	if err != nil {
+		l.logger.Debugf("command %s failed after %s with exit code %d: %v", l.name, duration, exitCode(err), err)
+		return
+	}
+	l.logger.Debugf("command %s finished in %s with exit code 0", l.name, duration)
+}
+
+// exitCode returns the exit code of a command from the error returned by
+// running it: 0 for nil, the process's exit code for an *exec.ExitError,
+// and -1 otherwise, e.g., if it couldn't be started or was killed.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
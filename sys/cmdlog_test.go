@@ -0,0 +1,106 @@
+package sys_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger is a logging.Logger that records its debug messages.
+type recordingLogger struct {
+	mu    sync.Mutex
+	debug []string
+}
+
+func (l *recordingLogger) Debugf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug = append(l.debug, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Debug(v ...interface{})                 { l.Debugf("%s", fmt.Sprint(v...)) }
+func (l *recordingLogger) Println(v ...interface{})               {}
+func (l *recordingLogger) Printf(format string, v ...interface{}) {}
+func (l *recordingLogger) Error(v ...interface{})                 {}
+func (l *recordingLogger) Errorf(format string, v ...interface{}) {}
+func (l *recordingLogger) Warn(v ...interface{})                  {}
+func (l *recordingLogger) Warnf(format string, v ...interface{})  {}
+
+func TestCommandLogging(t *testing.T) {
+	testCases := []struct {
+		name     string
+		run      func(logger *recordingLogger) (string, error)
+		output   string
+		wantErr  bool
+		expected []string
+	}{
+		{
+			name: "RunCommandWithLogger success",
+			run: func(logger *recordingLogger) (string, error) {
+				return sys.RunCommandWithLogger(logger, "sh", "-c", "echo out; echo err >&2")
+			},
+			output: "out\n",
+			expected: []string{
+				"running command: sh -c echo out; echo err >&2",
+				"sh [stdout]: out",
+				"sh [stderr]: err",
+				"with exit code 0",
+			},
+		},
+		{
+			name: "RunCommandWithLogger failure",
+			run: func(logger *recordingLogger) (string, error) {
+				return sys.RunCommandWithLogger(logger, "sh", "-c", "printf partial; exit 3")
+			},
+			wantErr:  true,
+			expected: []string{"sh [stdout]: partial", "with exit code 3"},
+		},
+		{
+			name: "Cmd with Logger",
+			run: func(logger *recordingLogger) (string, error) {
+				cmd := sys.Cmd{
+					CmdString: "sh",
+					Args:      []string{"-c", "echo one; echo two"},
+					Dir:       t.TempDir(),
+					Logger:    logger,
+				}
+				return cmd.RunCmd()
+			},
+			output:   "one\ntwo\n",
+			expected: []string{"(dir: ", "sh [stdout]: one", "sh [stdout]: two", "with exit code 0"},
+		},
+		{
+			name: "Cmd with missing command",
+			run: func(logger *recordingLogger) (string, error) {
+				cmd := sys.Cmd{CmdString: "goutils-missing-command", Logger: logger}
+				return cmd.RunCmd()
+			},
+			wantErr:  true,
+			expected: []string{"running command: goutils-missing-command", "with exit code -1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			output, err := tc.run(logger)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.output, output)
+			}
+
+			logged := strings.Join(logger.debug, "\n")
+			for _, want := range tc.expected {
+				assert.Contains(t, logged, want)
+			}
+			assert.True(t, strings.HasPrefix(logger.debug[0], "running command: "), "first record is the start: %q", logger.debug)
+			assert.Contains(t, logger.debug[len(logger.debug)-1], "exit code", "last record is the result")
+		})
+	}
+}
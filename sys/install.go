@@ -0,0 +1,159 @@
+package sys
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	fileutils "github.com/l50/goutils/v2/file/fileutils"
+)
+
+// InstallOptions configures how InstallBinary installs a file.
+//
+// **Attributes:**
+//
+// Checksum: Optional expected hex-encoded digest of the file. The file is
+// only installed if it matches.
+// ChecksumAlgorithm: Algorithm used to verify Checksum (default
+// fileutils.SHA256).
+// MakeExecutable: Whether to make the installed file executable (0755)
+// instead of 0644.
+// RenameTo: Optional name of the installed file. Defaults to the base
+// name of the source path or URL.
+type InstallOptions struct {
+	Checksum          string
+	ChecksumAlgorithm fileutils.HashAlgorithm
+	MakeExecutable    bool
+	RenameTo          string
+}
+
+// InstallBinary downloads or copies a file, such as a tool used by a mage
+// bootstrap target, into destDir. The file is written to a temporary file
+// in destDir, verified, given its final mode, and then moved into place,
+// so the destination never holds a partial or unverified file.
+//
+// **Parameters:**
+//
+// src: An http(s) URL to download, or the path of a local file to copy.
+// destDir: The directory to install the file into. It is created if it
+// doesn't exist.
+// opts: InstallOptions controlling verification, mode, and name.
+//
+// **Returns:**
+//
+// string: The path of the installed file.
+// error: An error if the file can't be fetched, fails checksum
+// verification, or can't be moved into place.
+func InstallBinary(src, destDir string, opts InstallOptions) (string, error) {
+	name := opts.RenameTo
+	if name == "" {
+		name = installName(src)
+	}
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("failed to determine file name for %s", src)
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", destDir, err)
+	}
+
+	// The temporary file lives in destDir so that the final rename
+	// doesn't cross file systems.
+	tmp, err := os.CreateTemp(destDir, "."+name+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file in %s: %v", destDir, err)
+	}
+	tmpPath := tmp.Name()
+	installed := false
+	defer func() {
+		if !installed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err := fetchInstallSource(src, tmp); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", tmpPath, err)
+	}
+
+	if opts.Checksum != "" {
+		algorithm := opts.ChecksumAlgorithm
+		if algorithm == "" {
+			algorithm = fileutils.SHA256
+		}
+		if err := fileutils.VerifyChecksum(tmpPath, opts.Checksum, algorithm); err != nil {
+			return "", fmt.Errorf("failed to verify %s: %v", src, err)
+		}
+	}
+
+	mode := os.FileMode(0644)
+	if opts.MakeExecutable {
+		mode = 0755
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return "", fmt.Errorf("failed to set mode of %s: %v", tmpPath, err)
+	}
+
+	dest := filepath.Join(destDir, name)
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return "", fmt.Errorf("failed to move %s into place: %v", dest, err)
+	}
+	installed = true
+
+	return dest, nil
+}
+
+// installName returns the base name of a source URL or path.
+func installName(src string) string {
+	if isInstallURL(src) {
+		u, err := url.Parse(src)
+		if err != nil {
+			return ""
+		}
+		return path.Base(u.Path)
+	}
+	return filepath.Base(src)
+}
+
+func isInstallURL(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
+
+// fetchInstallSource writes the content of src to w.
+func fetchInstallSource(src string, w io.Writer) error {
+	if !isInstallURL(src) {
+		f, err := os.Open(src)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %v", src, err)
+		}
+		defer f.Close()
+
+		if _, err := io.Copy(w, f); err != nil {
+			return fmt.Errorf("failed to copy %s: %v", src, err)
+		}
+		return nil
+	}
+
+	resp, err := http.Get(src)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %v", src, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", src, resp.Status)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %v", src, err)
+	}
+
+	return nil
+}
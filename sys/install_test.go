@@ -0,0 +1,116 @@
+package sys_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+func TestInstallBinary(t *testing.T) {
+	content := []byte("#!/bin/sh\necho hello\n")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases/tool" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	localSrc := filepath.Join(t.TempDir(), "local-tool")
+	if err := os.WriteFile(localSrc, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		src      string
+		opts     sys.InstallOptions
+		wantName string
+		wantMode os.FileMode
+		wantErr  bool
+	}{
+		{
+			name:     "Download with checksum",
+			src:      server.URL + "/releases/tool",
+			opts:     sys.InstallOptions{Checksum: checksum, MakeExecutable: true},
+			wantName: "tool",
+			wantMode: 0755,
+		},
+		{
+			name:     "Copy local file and rename",
+			src:      localSrc,
+			opts:     sys.InstallOptions{RenameTo: "renamed"},
+			wantName: "renamed",
+			wantMode: 0644,
+		},
+		{
+			name:    "Checksum mismatch",
+			src:     server.URL + "/releases/tool",
+			opts:    sys.InstallOptions{Checksum: "deadbeef"},
+			wantErr: true,
+		},
+		{
+			name:    "Download failure",
+			src:     server.URL + "/missing",
+			wantErr: true,
+		},
+		{
+			name:    "Missing local file",
+			src:     filepath.Join(t.TempDir(), "missing"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			destDir := filepath.Join(t.TempDir(), "bin")
+
+			got, err := sys.InstallBinary(tc.src, destDir, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("InstallBinary() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			entries, _ := os.ReadDir(destDir)
+			if tc.wantErr {
+				if len(entries) != 0 {
+					t.Errorf("InstallBinary() left %d files in %s after failing", len(entries), destDir)
+				}
+				return
+			}
+
+			if want := filepath.Join(destDir, tc.wantName); got != want {
+				t.Errorf("InstallBinary() = %s, want %s", got, want)
+			}
+			if len(entries) != 1 {
+				t.Errorf("InstallBinary() left %d files in %s, want 1", len(entries), destDir)
+			}
+
+			data, err := os.ReadFile(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != string(content) {
+				t.Errorf("installed content = %q, want %q", data, content)
+			}
+
+			info, err := os.Stat(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm() != tc.wantMode {
+				t.Errorf("installed mode = %v, want %v", info.Mode().Perm(), tc.wantMode)
+			}
+		})
+	}
+}
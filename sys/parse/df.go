@@ -0,0 +1,136 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/l50/goutils/v2/str"
+)
+
+// DFEntry represents a filesystem reported by `df`.
+//
+// **Attributes:**
+//
+// Filesystem: The device or remote filesystem name.
+// Size: The total size of the filesystem in bytes.
+// Used: The used space in bytes.
+// Available: The available space in bytes.
+// UsePercent: The percentage of space in use.
+// MountPoint: Where the filesystem is mounted.
+type DFEntry struct {
+	Filesystem string
+	Size       int64
+	Used       int64
+	Available  int64
+	UsePercent int
+	MountPoint string
+}
+
+// DF parses the output of `df` into a slice of DFEntry. The block size
+// is taken from the header, so `df -k`, `df -P`, and `df -h` output are
+// supported on Linux, as well as the macOS format with inode columns.
+// Lines wrapped because of a long filesystem name are joined.
+//
+// **Parameters:**
+//
+// output: The output of the df command, including the header row.
+//
+// **Returns:**
+//
+// []DFEntry: The parsed filesystems.
+// error: An error if the header is unrecognized or a row can't be parsed.
+func DF(output string) ([]DFEntry, error) {
+	lines := nonEmptyLines(output)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	header := strings.Fields(lines[0])
+	if len(header) < 6 || !strings.EqualFold(header[0], "Filesystem") {
+		return nil, fmt.Errorf("unrecognized df header %q", lines[0])
+	}
+	// "Mounted on" is the last column and spans two header fields.
+	columns := len(header) - 1
+
+	blockSize, err := dfBlockSize(header[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DFEntry
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		// Linux df wraps the row when the filesystem name is too long.
+		if len(strings.Fields(line)) == 1 && i+1 < len(lines) {
+			i++
+			line += " " + lines[i]
+		}
+
+		fields := str.FieldsN(line, columns)
+		if len(fields) < columns {
+			return nil, fmt.Errorf("unexpected df line %q", line)
+		}
+
+		entry := DFEntry{Filesystem: fields[0], MountPoint: fields[columns-1]}
+		sizes := []*int64{&entry.Size, &entry.Used, &entry.Available}
+		for j, size := range sizes {
+			if *size, err = parseDFSize(fields[j+1], blockSize); err != nil {
+				return nil, fmt.Errorf("invalid size %q in df line %q: %v", fields[j+1], line, err)
+			}
+		}
+		entry.UsePercent, _ = strconv.Atoi(strings.TrimSuffix(fields[4], "%"))
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// dfBlockSize returns the number of bytes per block for the size column
+// header, or 0 if sizes are human readable.
+func dfBlockSize(column string) (int64, error) {
+	column = strings.ToLower(column)
+	switch {
+	case column == "size":
+		return 0, nil
+	case strings.HasSuffix(column, "-blocks"):
+		return parseHumanSize(strings.TrimSuffix(column, "-blocks"))
+	default:
+		return 0, fmt.Errorf("unrecognized df size column %q", column)
+	}
+}
+
+func parseDFSize(value string, blockSize int64) (int64, error) {
+	if value == "-" {
+		return 0, nil
+	}
+	if blockSize == 0 {
+		return parseHumanSize(value)
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * blockSize, nil
+}
+
+// parseHumanSize parses sizes such as "512", "1K", "1.5Gi", or "20G",
+// using powers of 1024 for every suffix.
+func parseHumanSize(value string) (int64, error) {
+	value = strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(value), "B"), "I")
+	multiplier := int64(1)
+	if value != "" {
+		if i := strings.IndexByte("KMGTPE", value[len(value)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			value = value[:len(value)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n * float64(multiplier)), nil
+}
@@ -0,0 +1,206 @@
+package parse
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// NetInterface represents a network interface reported by `ip addr` or
+// `ifconfig`.
+//
+// **Attributes:**
+//
+// Name: The interface name (e.g., "eth0", "en0").
+// Flags: The interface flags (e.g., "UP", "LOOPBACK").
+// MTU: The maximum transmission unit.
+// MAC: The hardware address, if any.
+// Addresses: The IPv4 and IPv6 addresses assigned to the interface.
+type NetInterface struct {
+	Name      string
+	Flags     []string
+	MTU       int
+	MAC       string
+	Addresses []netip.Prefix
+}
+
+// Interfaces parses the output of `ip addr` on Linux or `ifconfig` on
+// Linux, macOS, and BSD into a slice of NetInterface. The format is
+// detected from the output.
+//
+// **Parameters:**
+//
+// output: The output of `ip addr` or `ifconfig`.
+//
+// **Returns:**
+//
+// []NetInterface: The parsed interfaces, in the order they were listed.
+// error: An error if an address can't be parsed.
+func Interfaces(output string) ([]NetInterface, error) {
+	var ifaces []NetInterface
+	var current *NetInterface
+
+	for _, line := range nonEmptyLines(output) {
+		indented := line[0] == ' ' || line[0] == '\t'
+		fields := strings.Fields(line)
+
+		if !indented {
+			iface, err := parseInterfaceHeader(fields)
+			if err != nil {
+				return nil, err
+			}
+			ifaces = append(ifaces, iface)
+			current = &ifaces[len(ifaces)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(fields[0], "link/") || fields[0] == "ether":
+			// ip: "link/ether 02:42:ac:11:00:02 brd ...", ifconfig: "ether 02:42:ac:11:00:02 ..."
+			if len(fields) > 1 && fields[0] != "link/loopback" {
+				current.MAC = fields[1]
+			}
+		case fields[0] == "inet" || fields[0] == "inet6":
+			prefix, err := parseInterfaceAddress(fields)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address on %s in line %q: %v", current.Name, strings.TrimSpace(line), err)
+			}
+			current.Addresses = append(current.Addresses, prefix)
+		}
+	}
+
+	return ifaces, nil
+}
+
+// parseInterfaceHeader parses the first line of an interface block, in
+// either the `ip addr` ("2: eth0@if5: <UP,...> mtu 1500 ...") or the
+// `ifconfig` ("en0: flags=8863<UP,...> mtu 1500") format.
+func parseInterfaceHeader(fields []string) (NetInterface, error) {
+	if len(fields) > 1 && strings.HasSuffix(fields[0], ":") {
+		if _, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":")); err == nil {
+			// Drop the `ip addr` interface index.
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return NetInterface{}, fmt.Errorf("empty interface header")
+	}
+
+	name := strings.TrimSuffix(fields[0], ":")
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name = name[:i]
+	}
+	iface := NetInterface{Name: name}
+
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		if start := strings.IndexByte(field, '<'); start >= 0 && strings.HasSuffix(field, ">") {
+			if flags := field[start+1 : len(field)-1]; flags != "" {
+				iface.Flags = strings.Split(flags, ",")
+			}
+		}
+		if field == "mtu" && i+1 < len(fields) {
+			iface.MTU, _ = strconv.Atoi(fields[i+1])
+		}
+	}
+
+	return iface, nil
+}
+
+// parseInterfaceAddress parses an inet or inet6 line. `ip addr` uses CIDR
+// notation, while ifconfig uses a separate netmask or prefixlen field.
+func parseInterfaceAddress(fields []string) (netip.Prefix, error) {
+	if len(fields) < 2 {
+		return netip.Prefix{}, fmt.Errorf("missing address")
+	}
+
+	addrField := fields[1]
+	if strings.HasPrefix(addrField, "addr:") {
+		// Older net-tools: "inet addr:10.0.0.1  Bcast:...  Mask:255.255.255.0"
+		addrField = strings.TrimPrefix(addrField, "addr:")
+	}
+
+	if strings.Contains(addrField, "/") {
+		prefix, err := netip.ParsePrefix(stripZone(addrField))
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix, nil
+	}
+
+	addr, err := netip.ParseAddr(stripZone(addrField))
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	bits := addr.BitLen()
+	for i := 2; i < len(fields); i++ {
+		key, value := fields[i], ""
+		if i+1 < len(fields) {
+			value = fields[i+1]
+		}
+		if k, v, ok := strings.Cut(key, ":"); ok && v != "" {
+			key, value = k, v
+		}
+
+		switch strings.ToLower(key) {
+		case "netmask", "mask":
+			if bits, err = maskBits(value); err != nil {
+				return netip.Prefix{}, err
+			}
+		case "prefixlen":
+			if bits, err = strconv.Atoi(value); err != nil {
+				return netip.Prefix{}, err
+			}
+		}
+	}
+
+	if bits < 0 || bits > addr.BitLen() {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix length %d", bits)
+	}
+	return netip.PrefixFrom(addr, bits), nil
+}
+
+// maskBits returns the prefix length of a dotted ("255.255.255.0") or
+// hexadecimal ("0xffffff00") netmask.
+func maskBits(mask string) (int, error) {
+	var value uint64
+	if strings.HasPrefix(mask, "0x") {
+		var err error
+		if value, err = strconv.ParseUint(mask[2:], 16, 32); err != nil {
+			return 0, err
+		}
+	} else {
+		addr, err := netip.ParseAddr(mask)
+		if err != nil || !addr.Is4() {
+			return 0, fmt.Errorf("invalid netmask %q", mask)
+		}
+		b := addr.As4()
+		value = uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3])
+	}
+
+	bits := 0
+	for value&(1<<31) != 0 {
+		bits++
+		value <<= 1
+		value &= 0xffffffff
+	}
+	return bits, nil
+}
+
+// stripZone removes an IPv6 zone (e.g., "%en0") from an address, keeping
+// any prefix length.
+func stripZone(addr string) string {
+	i := strings.IndexByte(addr, '%')
+	if i < 0 {
+		return addr
+	}
+	if j := strings.IndexByte(addr[i:], '/'); j >= 0 {
+		return addr[:i] + addr[i+j:]
+	}
+	return addr[:i]
+}
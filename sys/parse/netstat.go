@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NetstatEntry represents an internet socket reported by `netstat`.
+//
+// **Attributes:**
+//
+// Proto: The protocol (e.g., "tcp", "tcp6", "udp4").
+// RecvQ: The number of bytes in the receive queue.
+// SendQ: The number of bytes in the send queue.
+// LocalAddress: The local address as printed (e.g., "0.0.0.0", "::", "*").
+// LocalPort: The local port, or "*" for any port.
+// ForeignAddress: The remote address as printed.
+// ForeignPort: The remote port, or "*" for any port.
+// State: The TCP state (e.g., "LISTEN"); empty for UDP.
+// PID: The owning process ID when netstat was run with -p, otherwise 0.
+// Program: The owning program name when netstat was run with -p.
+type NetstatEntry struct {
+	Proto          string
+	RecvQ          int
+	SendQ          int
+	LocalAddress   string
+	LocalPort      string
+	ForeignAddress string
+	ForeignPort    string
+	State          string
+	PID            int
+	Program        string
+}
+
+// Netstat parses the internet sockets in the output of `netstat -an`
+// (optionally with -p on Linux) into a slice of NetstatEntry. Both the
+// Linux ("addr:port") and macOS/BSD ("addr.port") address formats are
+// supported. UNIX domain sockets and header lines are skipped.
+//
+// **Parameters:**
+//
+// output: The output of the netstat command.
+//
+// **Returns:**
+//
+// []NetstatEntry: The parsed sockets.
+// error: An error if a socket line is malformed.
+func Netstat(output string) ([]NetstatEntry, error) {
+	var entries []NetstatEntry
+
+	for _, line := range nonEmptyLines(output) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !(strings.HasPrefix(fields[0], "tcp") || strings.HasPrefix(fields[0], "udp")) {
+			continue
+		}
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("unexpected netstat line %q", line)
+		}
+
+		entry := NetstatEntry{Proto: fields[0]}
+		var err error
+		if entry.RecvQ, err = strconv.Atoi(fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid Recv-Q in netstat line %q: %v", line, err)
+		}
+		if entry.SendQ, err = strconv.Atoi(fields[2]); err != nil {
+			return nil, fmt.Errorf("invalid Send-Q in netstat line %q: %v", line, err)
+		}
+		entry.LocalAddress, entry.LocalPort = splitSocketAddress(fields[3])
+		entry.ForeignAddress, entry.ForeignPort = splitSocketAddress(fields[4])
+
+		rest := fields[5:]
+		if len(rest) > 0 && isSocketState(rest[0]) {
+			entry.State = rest[0]
+			rest = rest[1:]
+		}
+		if len(rest) > 0 {
+			// Linux -p: "1234/sshd: /usr/sbin" or "-" when unknown.
+			pid, program, _ := strings.Cut(strings.Join(rest, " "), "/")
+			entry.PID, _ = strconv.Atoi(pid)
+			entry.Program = program
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// splitSocketAddress splits a netstat address into host and port. Linux
+// separates the port with a colon and macOS/BSD with a dot, so the port
+// follows whichever separator appears last.
+func splitSocketAddress(addr string) (string, string) {
+	i := strings.LastIndexAny(addr, ":.")
+	if i < 0 {
+		return addr, ""
+	}
+	return addr[:i], addr[i+1:]
+}
+
+// isSocketState reports whether field looks like a TCP state such as
+// "LISTEN" or "TIME_WAIT".
+func isSocketState(field string) bool {
+	for _, r := range field {
+		if (r < 'A' || r > 'Z') && r != '_' && r != '0' && r != '1' && r != '2' {
+			return false
+		}
+	}
+	return field != ""
+}
@@ -0,0 +1,251 @@
+package parse_test
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys/parse"
+)
+
+func TestPS(t *testing.T) {
+	testCases := []struct {
+		name    string
+		output  string
+		want    []parse.PSEntry
+		wantErr bool
+	}{
+		{
+			name: "ps aux",
+			output: `USER         PID %CPU %MEM    VSZ   RSS TTY      STAT START   TIME COMMAND
+root           1  0.0  0.1 167744 11876 ?        Ss   Jan01   0:03 /sbin/init splash
+www-data    4242  1.5  2.0 256000 40960 ?        S    10:00   1:02 python3 -m http.server 8080
+`,
+			want: []parse.PSEntry{
+				{PID: 1, User: "root", CPU: 0.0, Mem: 0.1, State: "Ss", Command: "/sbin/init splash"},
+				{PID: 4242, User: "www-data", CPU: 1.5, Mem: 2.0, State: "S", Command: "python3 -m http.server 8080"},
+			},
+		},
+		{
+			name: "ps -eo on macOS",
+			output: `  PID  PPID USER  COMM
+    1     0 root  /sbin/launchd
+  512     1 alice /Applications/Visual Studio Code.app/Contents/MacOS/Electron
+`,
+			want: []parse.PSEntry{
+				{PID: 1, User: "root", Command: "/sbin/launchd"},
+				{PID: 512, PPID: 1, User: "alice", Command: "/Applications/Visual Studio Code.app/Contents/MacOS/Electron"},
+			},
+		},
+		{
+			name:    "missing PID column",
+			output:  "USER COMMAND\nroot init\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parse.PS(tc.output)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PS() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("PS() returned %d entries, want %d", len(got), len(tc.want))
+			}
+			for i := range got {
+				got[i].Fields = nil
+				if !reflect.DeepEqual(got[i], tc.want[i]) {
+					t.Errorf("PS()[%d] = %+v, want %+v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDF(t *testing.T) {
+	testCases := []struct {
+		name    string
+		output  string
+		want    []parse.DFEntry
+		wantErr bool
+	}{
+		{
+			name: "Linux df -P with wrapped filesystem",
+			output: `Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/sda1         10000000  4500000   5500000      45% /
+/dev/mapper/very-long-volume-group-name
+                   2000000   100000   1900000       5% /mnt/My Data
+`,
+			want: []parse.DFEntry{
+				{Filesystem: "/dev/sda1", Size: 10000000 * 1024, Used: 4500000 * 1024, Available: 5500000 * 1024, UsePercent: 45, MountPoint: "/"},
+				{Filesystem: "/dev/mapper/very-long-volume-group-name", Size: 2000000 * 1024, Used: 100000 * 1024, Available: 1900000 * 1024, UsePercent: 5, MountPoint: "/mnt/My Data"},
+			},
+		},
+		{
+			name: "macOS df -k with inode columns",
+			output: `Filesystem   1024-blocks     Used Available Capacity iused      ifree %iused  Mounted on
+/dev/disk3s1   239362496 10000000 150000000     7%  400000 1500000000    0%   /
+`,
+			want: []parse.DFEntry{
+				{Filesystem: "/dev/disk3s1", Size: 239362496 * 1024, Used: 10000000 * 1024, Available: 150000000 * 1024, UsePercent: 7, MountPoint: "/"},
+			},
+		},
+		{
+			name: "Human readable df -h",
+			output: `Filesystem      Size  Used Avail Use% Mounted on
+tmpfs           1.5G  512M  1.0G  34% /run
+`,
+			want: []parse.DFEntry{
+				{Filesystem: "tmpfs", Size: 1536 << 20, Used: 512 << 20, Available: 1 << 30, UsePercent: 34, MountPoint: "/run"},
+			},
+		},
+		{
+			name:    "Unrecognized header",
+			output:  "NAME SIZE\nsda 10G\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parse.DF(tc.output)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DF() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DF() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		want   []parse.NetInterface
+	}{
+		{
+			name: "Linux ip addr",
+			output: `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
+    inet 127.0.0.1/8 scope host lo
+       valid_lft forever preferred_lft forever
+    inet6 ::1/128 scope host
+       valid_lft forever preferred_lft forever
+2: eth0@if5: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc noqueue state UP group default
+    link/ether 02:42:ac:11:00:02 brd ff:ff:ff:ff:ff:ff link-netnsid 0
+    inet 172.17.0.2/16 brd 172.17.255.255 scope global eth0
+`,
+			want: []parse.NetInterface{
+				{
+					Name: "lo", Flags: []string{"LOOPBACK", "UP", "LOWER_UP"}, MTU: 65536,
+					Addresses: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/8"), netip.MustParsePrefix("::1/128")},
+				},
+				{
+					Name: "eth0", Flags: []string{"BROADCAST", "MULTICAST", "UP", "LOWER_UP"}, MTU: 1500, MAC: "02:42:ac:11:00:02",
+					Addresses: []netip.Prefix{netip.MustParsePrefix("172.17.0.2/16")},
+				},
+			},
+		},
+		{
+			name: "macOS ifconfig",
+			output: `lo0: flags=8049<UP,LOOPBACK,RUNNING,MULTICAST> mtu 16384
+	inet 127.0.0.1 netmask 0xff000000
+	inet6 fe80::1%lo0 prefixlen 64 scopeid 0x1
+en0: flags=8863<UP,BROADCAST,SMART,RUNNING,SIMPLEX,MULTICAST> mtu 1500
+	ether a4:83:e7:01:02:03
+	inet 192.168.1.10 netmask 0xffffff00 broadcast 192.168.1.255
+	status: active
+`,
+			want: []parse.NetInterface{
+				{
+					Name: "lo0", Flags: []string{"UP", "LOOPBACK", "RUNNING", "MULTICAST"}, MTU: 16384,
+					Addresses: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/8"), netip.MustParsePrefix("fe80::1/64")},
+				},
+				{
+					Name: "en0", Flags: []string{"UP", "BROADCAST", "SMART", "RUNNING", "SIMPLEX", "MULTICAST"}, MTU: 1500, MAC: "a4:83:e7:01:02:03",
+					Addresses: []netip.Prefix{netip.MustParsePrefix("192.168.1.10/24")},
+				},
+			},
+		},
+		{
+			name: "Linux net-tools ifconfig",
+			output: `eth0: flags=4163<UP,BROADCAST,RUNNING,MULTICAST>  mtu 1500
+        inet 172.17.0.2  netmask 255.255.0.0  broadcast 172.17.255.255
+        ether 02:42:ac:11:00:02  txqueuelen 0  (Ethernet)
+`,
+			want: []parse.NetInterface{
+				{
+					Name: "eth0", Flags: []string{"UP", "BROADCAST", "RUNNING", "MULTICAST"}, MTU: 1500, MAC: "02:42:ac:11:00:02",
+					Addresses: []netip.Prefix{netip.MustParsePrefix("172.17.0.2/16")},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parse.Interfaces(tc.output)
+			if err != nil {
+				t.Fatalf("Interfaces() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Interfaces() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNetstat(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		want   []parse.NetstatEntry
+	}{
+		{
+			name: "Linux netstat -anp",
+			output: `Active Internet connections (servers and established)
+Proto Recv-Q Send-Q Local Address           Foreign Address         State       PID/Program name
+tcp        0      0 0.0.0.0:22              0.0.0.0:*               LISTEN      812/sshd: /usr/sbin
+tcp6       0     36 ::ffff:10.0.0.5:22      ::ffff:10.0.0.9:51000   ESTABLISHED -
+udp        0      0 0.0.0.0:68              0.0.0.0:*                           530/dhclient
+Active UNIX domain sockets (servers and established)
+Proto RefCnt Flags       Type       State         I-Node   PID/Program name     Path
+unix  2      [ ACC ]     STREAM     LISTENING     20315    1/init               /run/systemd/private
+`,
+			want: []parse.NetstatEntry{
+				{Proto: "tcp", LocalAddress: "0.0.0.0", LocalPort: "22", ForeignAddress: "0.0.0.0", ForeignPort: "*", State: "LISTEN", PID: 812, Program: "sshd: /usr/sbin"},
+				{Proto: "tcp6", SendQ: 36, LocalAddress: "::ffff:10.0.0.5", LocalPort: "22", ForeignAddress: "::ffff:10.0.0.9", ForeignPort: "51000", State: "ESTABLISHED"},
+				{Proto: "udp", LocalAddress: "0.0.0.0", LocalPort: "68", ForeignAddress: "0.0.0.0", ForeignPort: "*", PID: 530, Program: "dhclient"},
+			},
+		},
+		{
+			name: "macOS netstat -an",
+			output: `Active Internet connections (including servers)
+Proto Recv-Q Send-Q  Local Address          Foreign Address        (state)
+tcp4       0      0  192.168.1.10.52144     17.57.146.20.5223      ESTABLISHED
+tcp46      0      0  *.80                   *.*                    LISTEN
+udp6       0      0  fe80::1%lo0.123        *.*
+`,
+			want: []parse.NetstatEntry{
+				{Proto: "tcp4", LocalAddress: "192.168.1.10", LocalPort: "52144", ForeignAddress: "17.57.146.20", ForeignPort: "5223", State: "ESTABLISHED"},
+				{Proto: "tcp46", LocalAddress: "*", LocalPort: "80", ForeignAddress: "*", ForeignPort: "*", State: "LISTEN"},
+				{Proto: "udp6", LocalAddress: "fe80::1%lo0", LocalPort: "123", ForeignAddress: "*", ForeignPort: "*"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parse.Netstat(tc.output)
+			if err != nil {
+				t.Fatalf("Netstat() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Netstat() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/l50/goutils/v2/str"
+)
+
+// PSEntry represents a row of `ps` output.
+//
+// **Attributes:**
+//
+// PID: The process ID.
+// PPID: The parent process ID, or 0 if the column wasn't present.
+// User: The user running the process.
+// CPU: The CPU usage percentage.
+// Mem: The memory usage percentage.
+// State: The process state (e.g., "S", "R", "Z").
+// Command: The command column, including its arguments.
+// Fields: Every column of the row, keyed by the upper-cased header.
+type PSEntry struct {
+	PID     int
+	PPID    int
+	User    string
+	CPU     float64
+	Mem     float64
+	State   string
+	Command string
+	Fields  map[string]string
+}
+
+// PS parses the output of `ps` into a slice of PSEntry. Columns are
+// identified by the header row, so the output of `ps aux`, `ps -ef`, and
+// custom `-o` formats are all supported as long as a header is printed.
+// The last column may contain spaces, which makes it suited to the
+// command column.
+//
+// **Parameters:**
+//
+// output: The output of the ps command, including the header row.
+//
+// **Returns:**
+//
+// []PSEntry: The parsed processes.
+// error: An error if the output has no PID column or a PID can't be parsed.
+func PS(output string) ([]PSEntry, error) {
+	lines := nonEmptyLines(output)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	headers := strings.Fields(strings.ToUpper(lines[0]))
+	pidIndex := indexOf(headers, "PID")
+	if pidIndex < 0 {
+		return nil, fmt.Errorf("no PID column found in ps header %q", lines[0])
+	}
+
+	var entries []PSEntry
+	for _, line := range lines[1:] {
+		fields := str.FieldsN(line, len(headers))
+		if len(fields) <= pidIndex {
+			continue
+		}
+
+		entry := PSEntry{Fields: make(map[string]string, len(fields))}
+		for i, field := range fields {
+			entry.Fields[headers[i]] = field
+		}
+
+		pid, err := strconv.Atoi(fields[pidIndex])
+		if err != nil {
+			return nil, fmt.Errorf("invalid PID %q in ps line %q", fields[pidIndex], line)
+		}
+		entry.PID = pid
+		entry.PPID, _ = strconv.Atoi(entry.Fields["PPID"])
+		entry.User = firstField(entry.Fields, "USER", "UID", "UNAME")
+		entry.CPU, _ = strconv.ParseFloat(firstField(entry.Fields, "%CPU", "PCPU"), 64)
+		entry.Mem, _ = strconv.ParseFloat(firstField(entry.Fields, "%MEM", "PMEM"), 64)
+		entry.State = firstField(entry.Fields, "STAT", "S", "STATE")
+		entry.Command = firstField(entry.Fields, "COMMAND", "CMD", "ARGS", "COMM", "UCOMM")
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// firstField returns the value of the first of keys present in fields.
+func firstField(fields map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func indexOf(values []string, target string) int {
+	for i, value := range values {
+		if value == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// nonEmptyLines splits output into lines, dropping blank lines.
+func nonEmptyLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package sys
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPowerStatusUnsupported is returned by PowerStatus when the power
+	// source can't be determined on the current platform.
+	ErrPowerStatusUnsupported = errors.New("power status is not supported on this platform")
+	// ErrIdleTimeUnsupported is returned by IdleTime when user activity
+	// can't be tracked on the current platform or session, e.g., on a
+	// headless Linux system.
+	ErrIdleTimeUnsupported = errors.New("idle time is not supported on this platform")
+)
+
+// PowerInfo describes the power source of the system.
+//
+// **Attributes:**
+//
+// OnAC: Whether the system is powered by an external power source.
+// Systems without a battery are always on AC.
+// HasBattery: Whether the system has a battery.
+// Percentage: The remaining battery charge, from 0 to 100. It's -1 if
+// the system has no battery or the charge is unknown.
+// Charging: Whether the battery is charging.
+type PowerInfo struct {
+	OnAC       bool
+	HasBattery bool
+	Percentage int
+	Charging   bool
+}
+
+// OnBattery reports whether the system is running on battery power,
+// e.g., to defer heavy work until a laptop is plugged in.
+//
+// **Returns:**
+//
+// bool: True if the system has a battery and isn't on AC.
+func (p PowerInfo) OnBattery() bool {
+	return p.HasBattery && !p.OnAC
+}
+
+// PowerStatus returns the power source and battery charge of the
+// system. It's supported on Linux, macOS, and Windows.
+//
+// **Returns:**
+//
+// PowerInfo: The power source and battery charge.
+// error: ErrPowerStatusUnsupported on other platforms, or an error if
+// the power status can't be read.
+func PowerStatus() (PowerInfo, error) {
+	return powerStatus()
+}
+
+// IdleTime returns how long it has been since the user last used the
+// keyboard or mouse. It's supported on macOS, Windows, and Linux
+// desktop sessions, where it uses xprintidle or the idle hint of the
+// systemd-logind session.
+//
+// **Returns:**
+//
+// time.Duration: The time since the last user input.
+// error: ErrIdleTimeUnsupported if user activity can't be tracked, or
+// an error if the idle time can't be read.
+func IdleTime() (time.Duration, error) {
+	return idleTime()
+}
@@ -0,0 +1,60 @@
+//go:build darwin
+
+package sys
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// pmsetBatteryRe matches a battery line of `pmset -g batt`, e.g.,
+	// "-InternalBattery-0 (id=4653155)	85%; charging; 0:42 remaining".
+	pmsetBatteryRe = regexp.MustCompile(`(\d+)%;\s*([^;]+);`)
+	// hidIdleTimeRe matches the idle time, in nanoseconds, reported by
+	// the IOHIDSystem registry entry.
+	hidIdleTimeRe = regexp.MustCompile(`"HIDIdleTime"\s*=\s*(\d+)`)
+)
+
+// powerStatus parses the output of `pmset -g batt`.
+func powerStatus() (PowerInfo, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return PowerInfo{}, fmt.Errorf("failed to run pmset: %v", err)
+	}
+
+	info := PowerInfo{
+		OnAC:       !strings.Contains(string(out), "'Battery Power'"),
+		Percentage: -1,
+	}
+	if match := pmsetBatteryRe.FindStringSubmatch(string(out)); match != nil {
+		info.HasBattery = true
+		info.Percentage, _ = strconv.Atoi(match[1])
+		info.Charging = strings.TrimSpace(match[2]) == "charging"
+	}
+
+	return info, nil
+}
+
+// idleTime reads the HIDIdleTime of the IOHIDSystem registry entry.
+func idleTime() (time.Duration, error) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to run ioreg: %v", err)
+	}
+
+	match := hidIdleTimeRe.FindSubmatch(out)
+	if match == nil {
+		return 0, ErrIdleTimeUnsupported
+	}
+	ns, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse HIDIdleTime %q: %v", match[1], err)
+	}
+
+	return time.Duration(ns), nil
+}
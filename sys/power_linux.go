@@ -0,0 +1,143 @@
+//go:build linux
+
+package sys
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// powerSupplyDir is where the kernel exposes power supplies.
+const powerSupplyDir = "/sys/class/power_supply"
+
+// powerStatus reads the power supplies exposed in sysfs. The charge of
+// several batteries is combined by energy, or averaged if the energy
+// isn't reported.
+func powerStatus() (PowerInfo, error) {
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil && !os.IsNotExist(err) {
+		return PowerInfo{}, fmt.Errorf("failed to read %s: %v", powerSupplyDir, err)
+	}
+
+	info := PowerInfo{Percentage: -1}
+	var hasMains, mainsOnline, discharging bool
+	var energyNow, energyFull, capacitySum, capacityCount int
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		switch readSysfs(dir, "type") {
+		case "Mains", "USB":
+			hasMains = true
+			if readSysfs(dir, "online") == "1" {
+				mainsOnline = true
+			}
+		case "Battery":
+			// Peripherals, such as wireless mice, report their batteries
+			// with scope "Device".
+			if readSysfs(dir, "scope") == "Device" || readSysfs(dir, "present") == "0" {
+				continue
+			}
+			info.HasBattery = true
+			switch readSysfs(dir, "status") {
+			case "Charging":
+				info.Charging = true
+			case "Discharging":
+				discharging = true
+			}
+
+			now, full := readSysfsInt(dir, "energy_now"), readSysfsInt(dir, "energy_full")
+			if full <= 0 {
+				now, full = readSysfsInt(dir, "charge_now"), readSysfsInt(dir, "charge_full")
+			}
+			if full > 0 && now >= 0 {
+				energyNow += now
+				energyFull += full
+			}
+			if capacity := readSysfsInt(dir, "capacity"); capacity >= 0 {
+				capacitySum += capacity
+				capacityCount++
+			}
+		}
+	}
+
+	if hasMains {
+		info.OnAC = mainsOnline
+	} else {
+		info.OnAC = !discharging
+	}
+
+	switch {
+	case energyFull > 0:
+		info.Percentage = min(100, energyNow*100/energyFull)
+	case capacityCount > 0:
+		info.Percentage = capacitySum / capacityCount
+	}
+
+	return info, nil
+}
+
+// readSysfs returns the trimmed content of a sysfs attribute, or an
+// empty string if it can't be read.
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysfsInt returns the value of a numeric sysfs attribute, or -1 if
+// it can't be read.
+func readSysfsInt(dir, name string) int {
+	value, err := strconv.Atoi(readSysfs(dir, name))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// idleTime asks the X server through xprintidle, falling back to the
+// idle hint that desktop environments set on their systemd-logind
+// session.
+func idleTime() (time.Duration, error) {
+	if os.Getenv("DISPLAY") != "" && CmdExists("xprintidle") {
+		out, err := exec.Command("xprintidle").Output()
+		if err != nil {
+			return 0, fmt.Errorf("failed to run xprintidle: %v", err)
+		}
+		ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse xprintidle output %q: %v", out, err)
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+
+	session := os.Getenv("XDG_SESSION_ID")
+	if session == "" || !CmdExists("loginctl") {
+		return 0, ErrIdleTimeUnsupported
+	}
+	out, err := exec.Command("loginctl", "show-session", session, "--property=IdleHint", "--property=IdleSinceHint").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the idle hint of session %s: %v", session, err)
+	}
+
+	properties := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			properties[key] = value
+		}
+	}
+	if properties["IdleHint"] != "yes" {
+		return 0, nil
+	}
+	since, err := strconv.ParseInt(properties["IdleSinceHint"], 10, 64)
+	if err != nil || since == 0 {
+		return 0, ErrIdleTimeUnsupported
+	}
+
+	return time.Since(time.UnixMicro(since)), nil
+}
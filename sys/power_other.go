@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package sys
+
+import "time"
+
+// powerStatus isn't implemented for this platform.
+func powerStatus() (PowerInfo, error) {
+	return PowerInfo{}, ErrPowerStatusUnsupported
+}
+
+// idleTime isn't implemented for this platform.
+func idleTime() (time.Duration, error) {
+	return 0, ErrIdleTimeUnsupported
+}
@@ -0,0 +1,60 @@
+package sys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+func TestPowerStatus(t *testing.T) {
+	info, err := sys.PowerStatus()
+	if errors.Is(err, sys.ErrPowerStatusUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("PowerStatus() error = %v", err)
+	}
+
+	if info.Percentage < -1 || info.Percentage > 100 {
+		t.Errorf("Percentage = %d, want -1 to 100", info.Percentage)
+	}
+	if !info.HasBattery {
+		if info.Percentage != -1 || info.Charging || !info.OnAC {
+			t.Errorf("PowerStatus() = %+v, want on AC with no charge for a system without a battery", info)
+		}
+	}
+}
+
+func TestPowerInfoOnBattery(t *testing.T) {
+	testCases := []struct {
+		name string
+		info sys.PowerInfo
+		want bool
+	}{
+		{name: "Discharging", info: sys.PowerInfo{HasBattery: true, Percentage: 50}, want: true},
+		{name: "Plugged in", info: sys.PowerInfo{OnAC: true, HasBattery: true, Percentage: 50}},
+		{name: "No battery", info: sys.PowerInfo{OnAC: true, Percentage: -1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.info.OnBattery(); got != tc.want {
+				t.Errorf("OnBattery() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIdleTime(t *testing.T) {
+	idle, err := sys.IdleTime()
+	if errors.Is(err, sys.ErrIdleTimeUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("IdleTime() error = %v", err)
+	}
+	if idle < 0 {
+		t.Errorf("IdleTime() = %v, want a non-negative duration", idle)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package sys
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	user32                   = windows.NewLazySystemDLL("user32.dll")
+	procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+	procGetTickCount         = kernel32.NewProc("GetTickCount")
+	procGetLastInputInfo     = user32.NewProc("GetLastInputInfo")
+)
+
+// systemPowerStatus mirrors the SYSTEM_POWER_STATUS structure.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// lastInputInfo mirrors the LASTINPUTINFO structure.
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+const (
+	batteryFlagCharging   = 8
+	batteryFlagNoBattery  = 128
+	batteryFlagUnknown    = 255
+	batteryPercentUnknown = 255
+)
+
+// powerStatus calls GetSystemPowerStatus.
+func powerStatus() (PowerInfo, error) {
+	var status systemPowerStatus
+	if ret, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); ret == 0 {
+		return PowerInfo{}, fmt.Errorf("failed to get system power status: %v", err)
+	}
+
+	info := PowerInfo{
+		HasBattery: status.BatteryFlag != batteryFlagNoBattery && status.BatteryFlag != batteryFlagUnknown,
+		Percentage: -1,
+	}
+	info.OnAC = status.ACLineStatus == 1 || !info.HasBattery
+	if info.HasBattery {
+		info.Charging = status.BatteryFlag&batteryFlagCharging != 0
+		if status.BatteryLifePercent != batteryPercentUnknown {
+			info.Percentage = int(status.BatteryLifePercent)
+		}
+	}
+
+	return info, nil
+}
+
+// idleTime compares the tick count of the last input event with the
+// current one. Both are 32-bit millisecond counters, so the unsigned
+// subtraction is correct across their wrap around.
+func idleTime() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ret, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return 0, fmt.Errorf("failed to get last input info: %v", err)
+	}
+	now, _, _ := procGetTickCount.Call()
+
+	return time.Duration(uint32(now)-info.dwTime) * time.Millisecond, nil
+}
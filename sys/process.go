@@ -0,0 +1,299 @@
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Process represents a running process on the system.
+//
+// **Attributes:**
+//
+// PID: The process ID.
+// PPID: The ID of the parent process.
+// Name: The executable name of the process.
+// State: The single-letter process state (e.g., "R", "S", "Z").
+// Cmdline: The full command line, if available.
+type Process struct {
+	PID     int
+	PPID    int
+	Name    string
+	State   string
+	Cmdline string
+}
+
+// ProcessTree represents a process together with all of its
+// descendants.
+//
+// **Attributes:**
+//
+// Process: The process at the root of this tree.
+// Children: Subtrees for each direct child of the process.
+type ProcessTree struct {
+	Process
+	Children []*ProcessTree
+}
+
+// ListProcesses returns every process currently running on the system.
+// On Linux the information is read from /proc; on other Unix-like
+// systems it is gathered with ps.
+//
+// **Returns:**
+//
+// []Process: The running processes, sorted by PID.
+// error: An error if the process table cannot be read.
+func ListProcesses() ([]Process, error) {
+	var procs []Process
+	var err error
+
+	if runtime.GOOS == "linux" {
+		procs, err = listProcFS("/proc")
+	} else {
+		procs, err = listPS()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+
+	return procs, nil
+}
+
+// FindProcessByName returns the running processes whose executable
+// name matches the input name exactly.
+//
+// **Parameters:**
+//
+// name: The executable name to search for (e.g., "sleep").
+//
+// **Returns:**
+//
+// []Process: The matching processes.
+// error: An error if the process table cannot be read or no process matches.
+func FindProcessByName(name string) ([]Process, error) {
+	procs, err := ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Process
+	for _, p := range procs {
+		if p.Name == name {
+			matches = append(matches, p)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no process named %s found", name)
+	}
+
+	return matches, nil
+}
+
+// GetProcessTree builds the tree of processes rooted at the input PID.
+//
+// **Parameters:**
+//
+// pid: The process ID at the root of the tree.
+//
+// **Returns:**
+//
+// *ProcessTree: The process and all of its descendants.
+// error: An error if the process table cannot be read or pid is not running.
+func GetProcessTree(pid int) (*ProcessTree, error) {
+	procs, err := ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int]*ProcessTree, len(procs))
+	for _, p := range procs {
+		nodes[p.PID] = &ProcessTree{Process: p}
+	}
+
+	root, ok := nodes[pid]
+	if !ok {
+		return nil, fmt.Errorf("process %d not found", pid)
+	}
+
+	for _, p := range procs {
+		if p.PID == p.PPID {
+			continue
+		}
+		if parent, ok := nodes[p.PPID]; ok {
+			parent.Children = append(parent.Children, nodes[p.PID])
+		}
+	}
+
+	return root, nil
+}
+
+// Descendants returns every process below the root of the tree in
+// depth-first post-order, so children always precede their parents.
+//
+// **Returns:**
+//
+// []Process: The descendant processes, excluding the root.
+func (t *ProcessTree) Descendants() []Process {
+	var out []Process
+	for _, child := range t.Children {
+		out = append(out, child.Descendants()...)
+		out = append(out, child.Process)
+	}
+	return out
+}
+
+// KillProcessTree sends a signal to a process and all of its
+// descendants. Children are signaled before their parents so that they
+// are not re-parented and left behind as orphans.
+//
+// **Parameters:**
+//
+// pid: The process ID at the root of the tree.
+// signal: The signal to send to each process.
+//
+// **Returns:**
+//
+// error: An error if the tree cannot be built or any process fails to
+// receive the signal.
+func KillProcessTree(pid int, signal Signal) error {
+	tree, err := GetProcessTree(pid)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range append(tree.Descendants(), tree.Process) {
+		if err := KillProcess(p.PID, signal); err != nil && !isProcessGone(err) {
+			errs = append(errs, fmt.Errorf("pid %d: %v", p.PID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to kill process tree %d: %v", pid, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// isProcessGone reports whether err indicates that the process exited
+// before it could be signaled.
+func isProcessGone(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "process already finished") ||
+		strings.Contains(msg, "no such process")
+}
+
+// listProcFS reads the process table from a procfs mount.
+func listProcFS(root string) ([]Process, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", root, err)
+	}
+
+	var procs []Process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		stat, err := os.ReadFile(filepath.Join(root, entry.Name(), "stat"))
+		if err != nil {
+			// The process exited while the table was being read.
+			continue
+		}
+
+		p, err := parseProcStat(pid, string(stat))
+		if err != nil {
+			continue
+		}
+
+		if cmdline, err := os.ReadFile(filepath.Join(root, entry.Name(), "cmdline")); err == nil {
+			args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+			p.Cmdline = strings.Join(args, " ")
+
+			// The kernel truncates comm to 15 characters; recover the
+			// full name from argv[0] when it was cut short.
+			if argv0 := filepath.Base(args[0]); len(p.Name) == 15 && strings.HasPrefix(argv0, p.Name) {
+				p.Name = argv0
+			}
+		}
+
+		procs = append(procs, p)
+	}
+
+	return procs, nil
+}
+
+// parseProcStat parses the contents of /proc/<pid>/stat. The command
+// name is wrapped in parentheses and may itself contain spaces or
+// parentheses, so the fields are located relative to the last ')'.
+func parseProcStat(pid int, stat string) (Process, error) {
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return Process{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return Process{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Process{}, fmt.Errorf("malformed ppid for pid %d: %v", pid, err)
+	}
+
+	return Process{
+		PID:   pid,
+		PPID:  ppid,
+		Name:  stat[open+1 : end],
+		State: fields[0],
+	}, nil
+}
+
+// listPS reads the process table using ps.
+func listPS() ([]Process, error) {
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,state=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %v", err)
+	}
+
+	var procs []Process
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		cmd := strings.Join(fields[3:], " ")
+		procs = append(procs, Process{
+			PID:     pid,
+			PPID:    ppid,
+			Name:    filepath.Base(cmd),
+			State:   fields[2][:1],
+			Cmdline: cmd,
+		})
+	}
+
+	return procs, nil
+}
@@ -0,0 +1,153 @@
+package sys_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+// startProcessTree starts a shell that spawns two sleeping children and
+// waits until both children are visible in the process table.
+func startProcessTree(t *testing.T) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30 & wait")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process tree: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		tree, err := sys.GetProcessTree(cmd.Process.Pid)
+		if err == nil && len(tree.Children) == 2 {
+			return cmd
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	_ = cmd.Process.Kill()
+	t.Fatalf("children of %d never appeared", cmd.Process.Pid)
+	return nil
+}
+
+func TestListProcesses(t *testing.T) {
+	procs, err := sys.ListProcesses()
+	if err != nil {
+		t.Fatalf("ListProcesses() error = %v", err)
+	}
+
+	found := false
+	for _, p := range procs {
+		if p.PID == os.Getpid() {
+			found = true
+			if p.PPID != os.Getppid() {
+				t.Errorf("expected ppid %d, got %d", os.Getppid(), p.PPID)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("current process %d not found in process list", os.Getpid())
+	}
+}
+
+func TestFindProcessByName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process trees are not supported on Windows")
+	}
+
+	cmd := startProcessTree(t)
+	defer func() {
+		_ = sys.KillProcessTree(cmd.Process.Pid, sys.SignalKill)
+		_ = cmd.Wait()
+	}()
+
+	testCases := []struct {
+		name    string
+		process string
+		wantErr bool
+	}{
+		{
+			name:    "Finds running process",
+			process: "sleep",
+			wantErr: false,
+		},
+		{
+			name:    "Missing process",
+			process: "definitely-not-running-goutils",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			procs, err := sys.FindProcessByName(tc.process)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("FindProcessByName() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if !tc.wantErr && len(procs) < 2 {
+				t.Errorf("expected at least 2 %s processes, got %d", tc.process, len(procs))
+			}
+		})
+	}
+}
+
+func TestKillProcessTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process trees are not supported on Windows")
+	}
+
+	cmd := startProcessTree(t)
+
+	tree, err := sys.GetProcessTree(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("GetProcessTree() error = %v", err)
+	}
+
+	descendants := tree.Descendants()
+	if len(descendants) != 2 {
+		t.Fatalf("expected 2 descendants, got %d", len(descendants))
+	}
+
+	if err := sys.KillProcessTree(cmd.Process.Pid, sys.SignalKill); err != nil {
+		t.Fatalf("KillProcessTree() error = %v", err)
+	}
+	_ = cmd.Wait()
+
+	// Orphaned children would be re-parented rather than disappear, so
+	// check that none of the original descendants are still running.
+	deadline := time.Now().Add(5 * time.Second)
+	for _, d := range descendants {
+		for {
+			procs, err := sys.ListProcesses()
+			if err != nil {
+				t.Fatalf("ListProcesses() error = %v", err)
+			}
+
+			alive := false
+			for _, p := range procs {
+				// Zombies are dead and only awaiting reaping by init.
+				if p.PID == d.PID && p.Name == d.Name && p.State != "Z" {
+					alive = true
+				}
+			}
+
+			if !alive {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("descendant %d (%s) is still running", d.PID, d.Name)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	if _, err := sys.GetProcessTree(cmd.Process.Pid); err == nil {
+		t.Error("expected error for process tree of a killed process")
+	}
+}
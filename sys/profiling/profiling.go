@@ -0,0 +1,235 @@
+// Package profiling collects CPU and heap profiles and serves the
+// net/http/pprof handlers on a dedicated address. It's a separate package
+// so that the handlers net/http/pprof registers on http.DefaultServeMux
+// are only linked into programs that opt in to profiling.
+package profiling
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	httppprof "net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvCPUProfile is the environment variable read by
+	// OptionsFromEnv for Options.CPUProfilePath.
+	EnvCPUProfile = "GOUTILS_CPU_PROFILE"
+	// EnvMemProfile is the environment variable read by
+	// OptionsFromEnv for Options.MemProfilePath.
+	EnvMemProfile = "GOUTILS_MEM_PROFILE"
+	// EnvPprofAddr is the environment variable read by
+	// OptionsFromEnv for Options.HTTPPprofAddr.
+	EnvPprofAddr = "GOUTILS_PPROF_ADDR"
+)
+
+// Options configures the profiles collected by Start.
+// Empty fields disable the corresponding profile.
+//
+// **Attributes:**
+//
+// CPUProfilePath: File the CPU profile is written to while profiling is
+// active.
+// MemProfilePath: File a heap profile is written to by Stop.
+// HTTPPprofAddr: Address, such as "localhost:6060", on which the
+// net/http/pprof handlers are served under /debug/pprof/.
+type Options struct {
+	CPUProfilePath string
+	MemProfilePath string
+	HTTPPprofAddr  string
+}
+
+// profiler holds the state of the active profiling session.
+type profiler struct {
+	cpuFile *os.File
+	memPath string
+	server  *http.Server
+	addr    string
+}
+
+var (
+	profilingMu     sync.Mutex
+	activeProfiling *profiler
+)
+
+// OptionsFromEnv returns the profiling options set by the
+// GOUTILS_CPU_PROFILE, GOUTILS_MEM_PROFILE, and GOUTILS_PPROF_ADDR
+// environment variables.
+//
+// **Returns:**
+//
+// Options: The options read from the environment.
+func OptionsFromEnv() Options {
+	return Options{
+		CPUProfilePath: os.Getenv(EnvCPUProfile),
+		MemProfilePath: os.Getenv(EnvMemProfile),
+		HTTPPprofAddr:  os.Getenv(EnvPprofAddr),
+	}
+}
+
+// RegisterFlags registers the -cpuprofile, -memprofile, and -pprof-addr
+// flags on the input flag set. The current option values are used as the
+// flag defaults, so flags can override options read with
+// OptionsFromEnv.
+//
+// **Parameters:**
+//
+// fs: The flag set to register the flags on, such as flag.CommandLine.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.CPUProfilePath, "cpuprofile", o.CPUProfilePath, "write a CPU profile to `file`")
+	fs.StringVar(&o.MemProfilePath, "memprofile", o.MemProfilePath, "write a heap profile to `file` on exit")
+	fs.StringVar(&o.HTTPPprofAddr, "pprof-addr", o.HTTPPprofAddr, "serve net/http/pprof on `address`")
+}
+
+// Start starts collecting the profiles enabled in opts. Call
+// Stop before the program exits to flush them. Only one
+// profiling session can be active at a time.
+//
+// **Parameters:**
+//
+// opts: Options selecting the profiles to collect.
+//
+// **Returns:**
+//
+// error: An error if profiling is already active, the CPU profile can't
+// be started, or the pprof address can't be listened on.
+func Start(opts Options) error {
+	profilingMu.Lock()
+	defer profilingMu.Unlock()
+
+	if activeProfiling != nil {
+		return errors.New("profiling is already active")
+	}
+
+	p := &profiler{memPath: opts.MemProfilePath}
+
+	if opts.CPUProfilePath != "" {
+		f, err := os.Create(opts.CPUProfilePath)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %v", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start CPU profile: %v", err)
+		}
+		p.cpuFile = f
+	}
+
+	if opts.HTTPPprofAddr != "" {
+		ln, err := net.Listen("tcp", opts.HTTPPprofAddr)
+		if err != nil {
+			_ = p.stopCPU()
+			return fmt.Errorf("failed to listen on %s: %v", opts.HTTPPprofAddr, err)
+		}
+
+		// A dedicated mux keeps other handlers registered on
+		// http.DefaultServeMux off the pprof address.
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", httppprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+
+		p.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		p.addr = ln.Addr().String()
+		go func() { _ = p.server.Serve(ln) }()
+	}
+
+	activeProfiling = p
+	return nil
+}
+
+// Stop stops the profiling session started by Start.
+// It flushes the CPU profile, writes the heap profile, and shuts down the
+// pprof HTTP server. Calling Stop without an active session does
+// nothing.
+//
+// **Returns:**
+//
+// error: An error if a profile can't be written or the HTTP server can't
+// be shut down.
+func Stop() error {
+	profilingMu.Lock()
+	p := activeProfiling
+	activeProfiling = nil
+	profilingMu.Unlock()
+
+	if p == nil {
+		return nil
+	}
+
+	var errs []error
+	if err := p.stopCPU(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if p.memPath != "" {
+		if err := writeHeapProfile(p.memPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := p.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down pprof server: %v", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Addr returns the address the pprof HTTP server listens on, or
+// an empty string if it isn't running. This is useful when
+// HTTPPprofAddr uses port 0.
+//
+// **Returns:**
+//
+// string: The listen address, such as "127.0.0.1:6060".
+func Addr() string {
+	profilingMu.Lock()
+	defer profilingMu.Unlock()
+
+	if activeProfiling == nil {
+		return ""
+	}
+	return activeProfiling.addr
+}
+
+func (p *profiler) stopCPU() error {
+	if p.cpuFile == nil {
+		return nil
+	}
+	pprof.StopCPUProfile()
+	err := p.cpuFile.Close()
+	p.cpuFile = nil
+	if err != nil {
+		return fmt.Errorf("failed to close CPU profile: %v", err)
+	}
+	return nil
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %v", err)
+	}
+	defer f.Close()
+
+	// Collect garbage first so the profile reflects live memory.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write heap profile: %v", err)
+	}
+	return nil
+}
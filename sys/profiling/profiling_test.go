@@ -0,0 +1,119 @@
+package profiling_test
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys/profiling"
+)
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name      string
+		opts      profiling.Options
+		wantFiles []string
+		wantHTTP  bool
+		wantErr   bool
+	}{
+		{
+			name: "CPU and heap profiles",
+			opts: profiling.Options{
+				CPUProfilePath: filepath.Join(dir, "cpu.pprof"),
+				MemProfilePath: filepath.Join(dir, "mem.pprof"),
+			},
+			wantFiles: []string{filepath.Join(dir, "cpu.pprof"), filepath.Join(dir, "mem.pprof")},
+		},
+		{
+			name:     "HTTP pprof server",
+			opts:     profiling.Options{HTTPPprofAddr: "127.0.0.1:0"},
+			wantHTTP: true,
+		},
+		{
+			name:    "unwritable CPU profile",
+			opts:    profiling.Options{CPUProfilePath: filepath.Join(dir, "missing", "cpu.pprof")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid HTTP address",
+			opts:    profiling.Options{HTTPPprofAddr: "256.0.0.1:0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := profiling.Start(tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Start() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if addr := profiling.Addr(); addr != "" {
+					t.Errorf("Addr() = %q after a failed start", addr)
+				}
+				return
+			}
+
+			if err := profiling.Start(tc.opts); err == nil {
+				t.Error("expected an error when profiling is already active")
+			}
+
+			if tc.wantHTTP {
+				addr := profiling.Addr()
+				resp, err := http.Get("http://" + addr + "/debug/pprof/")
+				if err != nil {
+					t.Fatalf("failed to query pprof server: %v", err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("pprof index returned %d: %s", resp.StatusCode, body)
+				}
+			}
+
+			if err := profiling.Stop(); err != nil {
+				t.Fatalf("Stop() error = %v", err)
+			}
+			if addr := profiling.Addr(); addr != "" {
+				t.Errorf("Addr() = %q after Stop", addr)
+			}
+			for _, path := range tc.wantFiles {
+				info, err := os.Stat(path)
+				if err != nil || info.Size() == 0 {
+					t.Errorf("expected non-empty profile at %s: %v", path, err)
+				}
+			}
+		})
+	}
+
+	if err := profiling.Stop(); err != nil {
+		t.Errorf("Stop() without an active session error = %v", err)
+	}
+}
+
+func TestOptionsFromEnvAndFlags(t *testing.T) {
+	t.Setenv(profiling.EnvCPUProfile, "env-cpu.pprof")
+	t.Setenv(profiling.EnvMemProfile, "env-mem.pprof")
+	t.Setenv(profiling.EnvPprofAddr, "")
+
+	opts := profiling.OptionsFromEnv()
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	opts.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-memprofile", "flag-mem.pprof", "-pprof-addr", "localhost:6060"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := profiling.Options{
+		CPUProfilePath: "env-cpu.pprof",
+		MemProfilePath: "flag-mem.pprof",
+		HTTPPprofAddr:  "localhost:6060",
+	}
+	if opts != want {
+		t.Errorf("options = %+v, want %+v", opts, want)
+	}
+}
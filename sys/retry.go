@@ -0,0 +1,84 @@
+package sys
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryOptions configures how RunCommandWithRetry retries a failing
+// command.
+//
+// **Attributes:**
+//
+// Attempts: Total number of times the command is run (default 3).
+// Backoff: Delay before the first retry, doubled after every failed
+// attempt (default 1s).
+// MaxBackoff: Optional upper bound for the delay between retries.
+// Jitter: Maximum random duration added to every delay so that
+// concurrent callers don't retry in lockstep.
+// RetryIf: Optional predicate called with the error and the combined
+// stdout and stderr of a failed attempt. Returning false stops retrying.
+// When nil, every failure is retried.
+type RetryOptions struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Jitter     time.Duration
+	RetryIf    func(err error, output string) bool
+}
+
+// RunCommandWithRetry executes a system command, retrying it with
+// exponential backoff when it fails. This is useful for flaky,
+// network-dependent commands such as `git fetch` or package installs.
+//
+// **Parameters:**
+//
+// cmd: A string representing the command to run.
+// args: Command line arguments for the command.
+// opts: RetryOptions controlling the number of attempts and delays.
+//
+// **Returns:**
+//
+// string: The output from the first successful run of the command.
+// error: An error if every attempt failed or RetryIf stopped the retries.
+func RunCommandWithRetry(cmd string, args []string, opts RetryOptions) (string, error) {
+	if opts.Attempts <= 0 {
+		opts.Attempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+
+	delay := opts.Backoff
+	var lastErr error
+
+	for attempt := 1; attempt <= opts.Attempts; attempt++ {
+		stdout, stderr, err := runCommand(cmd, args...)
+		if err == nil {
+			return stdout, nil
+		}
+		lastErr = err
+
+		if opts.RetryIf != nil && !opts.RetryIf(err, stdout+stderr) {
+			return "", fmt.Errorf("not retrying %s after attempt %d: %w", cmd, attempt, err)
+		}
+
+		if attempt == opts.Attempts {
+			break
+		}
+
+		wait := delay
+		if opts.Jitter > 0 {
+			wait += rand.N(opts.Jitter)
+		}
+		time.Sleep(wait)
+
+		delay *= 2
+		if opts.MaxBackoff > 0 && delay > opts.MaxBackoff {
+			delay = opts.MaxBackoff
+		}
+	}
+
+	return "", fmt.Errorf("%s failed after %d attempts: %w", cmd, opts.Attempts, lastErr)
+}
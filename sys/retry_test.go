@@ -0,0 +1,95 @@
+package sys_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+func TestRunCommandWithRetry(t *testing.T) {
+	// The script fails until it has been run `succeedOn` times, tracking
+	// the number of runs in a counter file.
+	script := `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"; ` +
+		`if [ $n -lt $2 ]; then echo "transient failure" >&2; exit 1; fi; echo ok`
+
+	testCases := []struct {
+		name      string
+		succeedOn int
+		opts      sys.RetryOptions
+		wantRuns  int
+		wantErr   bool
+	}{
+		{
+			name:      "succeeds on first attempt",
+			succeedOn: 1,
+			opts:      sys.RetryOptions{Attempts: 3, Backoff: time.Millisecond},
+			wantRuns:  1,
+		},
+		{
+			name:      "succeeds after retries",
+			succeedOn: 3,
+			opts:      sys.RetryOptions{Attempts: 3, Backoff: time.Millisecond, Jitter: time.Millisecond},
+			wantRuns:  3,
+		},
+		{
+			name:      "fails after all attempts",
+			succeedOn: 10,
+			opts:      sys.RetryOptions{Attempts: 2, Backoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			wantRuns:  2,
+			wantErr:   true,
+		},
+		{
+			name:      "RetryIf stops retries",
+			succeedOn: 3,
+			opts: sys.RetryOptions{
+				Attempts: 5,
+				Backoff:  time.Millisecond,
+				RetryIf: func(err error, output string) bool {
+					return !strings.Contains(output, "transient failure")
+				},
+			},
+			wantRuns: 1,
+			wantErr:  true,
+		},
+		{
+			name:      "RetryIf allows retries",
+			succeedOn: 2,
+			opts: sys.RetryOptions{
+				Attempts: 5,
+				Backoff:  time.Millisecond,
+				RetryIf: func(err error, output string) bool {
+					return strings.Contains(output, "transient failure")
+				},
+			},
+			wantRuns: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := filepath.Join(t.TempDir(), "counter")
+			out, err := sys.RunCommandWithRetry("sh",
+				[]string{"-c", script, "sh", counter, strconv.Itoa(tc.succeedOn)}, tc.opts)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("RunCommandWithRetry() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && strings.TrimSpace(out) != "ok" {
+				t.Errorf("RunCommandWithRetry() output = %q, want %q", out, "ok")
+			}
+
+			data, err := os.ReadFile(counter)
+			if err != nil {
+				t.Fatalf("failed to read counter: %v", err)
+			}
+			if runs := strings.TrimSpace(string(data)); runs != strconv.Itoa(tc.wantRuns) {
+				t.Errorf("command ran %s times, want %d", runs, tc.wantRuns)
+			}
+		})
+	}
+}
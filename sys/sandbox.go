@@ -0,0 +1,87 @@
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSandboxUnsupported is returned by Cmd.RunCmd when the requested
+// chroot or namespace isolation isn't available on the current platform.
+var ErrSandboxUnsupported = errors.New("sandboxed execution is not supported on this platform")
+
+// Namespace is a Linux namespace that a command can be isolated in.
+type Namespace int
+
+const (
+	// NamespaceMount gives the command its own mount table. Mounts made
+	// by the command aren't visible to the rest of the system.
+	NamespaceMount Namespace = iota + 1
+	// NamespaceNet gives the command its own network stack, containing
+	// only a loopback interface that is down.
+	NamespaceNet
+	// NamespacePID gives the command its own process ID space, in which
+	// it runs as PID 1.
+	NamespacePID
+	// NamespaceUTS gives the command its own hostname and domain name.
+	NamespaceUTS
+	// NamespaceIPC gives the command its own System V IPC objects and
+	// POSIX message queues.
+	NamespaceIPC
+	// NamespaceUser gives the command its own user and group IDs, mapping
+	// the current user to root inside the namespace. Combined with other
+	// namespaces, this allows isolation without privileges.
+	NamespaceUser
+)
+
+// String returns the name of the namespace, as used by unshare(1).
+//
+// **Returns:**
+//
+// string: The namespace name (e.g., "net").
+func (n Namespace) String() string {
+	switch n {
+	case NamespaceMount:
+		return "mount"
+	case NamespaceNet:
+		return "net"
+	case NamespacePID:
+		return "pid"
+	case NamespaceUTS:
+		return "uts"
+	case NamespaceIPC:
+		return "ipc"
+	case NamespaceUser:
+		return "user"
+	default:
+		return fmt.Sprintf("Namespace(%d)", int(n))
+	}
+}
+
+// sandboxed reports whether c requests chroot or namespace isolation.
+func (c *Cmd) sandboxed() bool {
+	return c.Chroot != "" || len(c.Namespaces) > 0
+}
+
+// lookPathInRoot resolves file to the path of an executable inside root,
+// searching the directories of $PATH if file contains no separator. The
+// returned path is relative to root.
+func lookPathInRoot(root, file string) (string, error) {
+	if strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		path := filepath.Join(dir, file)
+		if info, err := os.Stat(filepath.Join(root, path)); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("executable %s not found in $PATH inside chroot %s", file, root)
+}
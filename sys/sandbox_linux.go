@@ -0,0 +1,42 @@
+//go:build linux
+
+package sys
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// applySandbox configures attr to run the command in the chroot and
+// namespaces requested by c.
+func (c *Cmd) applySandbox(attr *syscall.SysProcAttr) error {
+	attr.Chroot = c.Chroot
+
+	for _, ns := range c.Namespaces {
+		switch ns {
+		case NamespaceMount:
+			// Unsharing the mount namespace, rather than cloning into it,
+			// makes the runtime mark the mounts private so that they
+			// don't propagate back to the host.
+			attr.Unshareflags |= syscall.CLONE_NEWNS
+		case NamespaceNet:
+			attr.Cloneflags |= syscall.CLONE_NEWNET
+		case NamespacePID:
+			attr.Cloneflags |= syscall.CLONE_NEWPID
+		case NamespaceUTS:
+			attr.Cloneflags |= syscall.CLONE_NEWUTS
+		case NamespaceIPC:
+			attr.Cloneflags |= syscall.CLONE_NEWIPC
+		case NamespaceUser:
+			attr.Cloneflags |= syscall.CLONE_NEWUSER
+			attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+			attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+			attr.GidMappingsEnableSetgroups = false
+		default:
+			return fmt.Errorf("%w: unknown namespace %s", ErrSandboxUnsupported, ns)
+		}
+	}
+
+	return nil
+}
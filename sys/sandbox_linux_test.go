@@ -0,0 +1,131 @@
+//go:build linux
+
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+// runSandboxed runs cmd, skipping the test if the environment doesn't
+// permit the requested isolation.
+func runSandboxed(t *testing.T, cmd sys.Cmd) string {
+	t.Helper()
+	cmd.OutputHandler = func(string) {}
+
+	out, err := cmd.RunCmd()
+	if err != nil && strings.Contains(err.Error(), "in sandbox") {
+		t.Skipf("sandboxing not permitted here: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("RunCmd() failed: %v (output: %q)", err, out)
+	}
+	return strings.TrimSpace(out)
+}
+
+func TestCmdNamespaces(t *testing.T) {
+	testCases := []struct {
+		name       string
+		namespaces []sys.Namespace
+		script     string
+		want       string
+	}{
+		{
+			name:       "PID namespace runs command as PID 1",
+			namespaces: []sys.Namespace{sys.NamespaceUser, sys.NamespacePID},
+			script:     "echo $$",
+			want:       "1",
+		},
+		{
+			name:       "Net namespace only has loopback",
+			namespaces: []sys.Namespace{sys.NamespaceUser, sys.NamespaceNet},
+			script:     "tail -n +3 /proc/net/dev | cut -d: -f1 | tr -d ' '",
+			want:       "lo",
+		},
+		{
+			name:       "User namespace maps caller to root",
+			namespaces: []sys.Namespace{sys.NamespaceUser},
+			script:     "id -u",
+			want:       "0",
+		},
+		{
+			name:       "UTS namespace isolates hostname",
+			namespaces: []sys.Namespace{sys.NamespaceUser, sys.NamespaceUTS},
+			script:     "hostname sandboxed && hostname",
+			want:       "sandboxed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := runSandboxed(t, sys.Cmd{
+				CmdString:  "sh",
+				Args:       []string{"-c", tc.script},
+				Namespaces: tc.namespaces,
+			})
+			if got != tc.want {
+				t.Errorf("output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	hostname, err := os.Hostname()
+	if err == nil && hostname == "sandboxed" {
+		t.Error("UTS namespace leaked the hostname change to the host")
+	}
+}
+
+func TestCmdChroot(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available to build a static helper")
+	}
+
+	// Build a static binary, as the chroot has no shared libraries.
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "main.go")
+	prog := "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\n" +
+		"func main() {\n\tentries, _ := os.ReadDir(\"/\")\n\tfor _, e := range entries {\n\t\tfmt.Println(e.Name())\n\t}\n}\n"
+	if err := os.WriteFile(src, []byte(prog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	build := exec.Command(goBin, "build", "-o", filepath.Join(root, "bin", "lsroot"), src)
+	build.Env = append(os.Environ(), "CGO_ENABLED=0", "GO111MODULE=off")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build helper: %v\n%s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(root, "marker"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", "/bin")
+	got := runSandboxed(t, sys.Cmd{
+		CmdString:  "lsroot",
+		Chroot:     root,
+		Namespaces: []sys.Namespace{sys.NamespaceUser, sys.NamespaceMount},
+	})
+	if got != "bin\nmarker" {
+		t.Errorf("chroot contents = %q, want %q", got, "bin\nmarker")
+	}
+
+	_, err = (&sys.Cmd{CmdString: "missing", Chroot: root, OutputHandler: func(string) {}}).RunCmd()
+	if err == nil || !strings.Contains(err.Error(), "inside chroot") {
+		t.Errorf("RunCmd() error = %v, want executable not found inside chroot", err)
+	}
+}
+
+func TestCmdUnknownNamespace(t *testing.T) {
+	_, err := (&sys.Cmd{CmdString: "true", Namespaces: []sys.Namespace{99}}).RunCmd()
+	if !errors.Is(err, sys.ErrSandboxUnsupported) {
+		t.Errorf("RunCmd() error = %v, want ErrSandboxUnsupported", err)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !windows
+
+package sys
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// applySandbox configures attr to run the command in the chroot
+// requested by c. Namespaces only exist on Linux.
+func (c *Cmd) applySandbox(attr *syscall.SysProcAttr) error {
+	if len(c.Namespaces) > 0 {
+		return fmt.Errorf("%w: namespaces require Linux", ErrSandboxUnsupported)
+	}
+
+	attr.Chroot = c.Chroot
+
+	return nil
+}
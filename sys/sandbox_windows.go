@@ -0,0 +1,12 @@
+package sys
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// applySandbox always fails, since Windows has neither chroots nor
+// namespaces.
+func (c *Cmd) applySandbox(attr *syscall.SysProcAttr) error {
+	return fmt.Errorf("%w: chroots and namespaces require a Unix-like system", ErrSandboxUnsupported)
+}
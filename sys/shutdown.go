@@ -0,0 +1,221 @@
+package sys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/l50/goutils/v2/logging"
+)
+
+// DefaultHookTimeout is the time a shutdown hook may run before its
+// context is canceled, unless ShutdownManager.HookTimeout is set.
+const DefaultHookTimeout = 30 * time.Second
+
+// ShutdownManager runs cleanup hooks in order when the process receives
+// SIGINT or SIGTERM, or when Shutdown is called. Hooks run once, one at
+// a time, each with its own timeout.
+//
+// **Attributes:**
+//
+// HookTimeout: The time each hook may run before its context is
+// canceled. Defaults to DefaultHookTimeout.
+// Logger: The logger shutdown progress is reported to. Defaults to the
+// logger from logging.FromContext.
+type ShutdownManager struct {
+	HookTimeout time.Duration
+	Logger      logging.Logger
+
+	mu      sync.Mutex
+	hooks   []shutdownHook
+	once    sync.Once
+	done    chan struct{}
+	err     error
+	signals chan os.Signal
+	stop    chan struct{}
+}
+
+type shutdownHook struct {
+	name  string
+	order int
+	hook  func(ctx context.Context) error
+}
+
+// NewShutdownManager creates a ShutdownManager that listens for SIGINT
+// and SIGTERM. Once a signal is received, the registered hooks are run
+// and Done is closed. Signal handling is restored once shutdown starts,
+// so a second Ctrl+C terminates a stuck shutdown. Exiting is left to
+// the caller, typically after Wait returns.
+//
+// **Returns:**
+//
+// *ShutdownManager: The new ShutdownManager.
+func NewShutdownManager() *ShutdownManager {
+	sm := &ShutdownManager{
+		HookTimeout: DefaultHookTimeout,
+		done:        make(chan struct{}),
+		signals:     make(chan os.Signal, 1),
+		stop:        make(chan struct{}),
+	}
+
+	signal.Notify(sm.signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sm.signals:
+			sm.logger().Printf("received %v, shutting down", sig)
+			_ = sm.Shutdown(context.Background())
+		case <-sm.stop:
+		}
+	}()
+
+	return sm
+}
+
+// Register adds a hook to run on shutdown. Hooks run in ascending order;
+// hooks with the same order run in the order they were registered.
+// Hooks registered after shutdown has started are not run.
+//
+// **Parameters:**
+//
+// name: The name of the hook, used in logs and errors.
+// hook: The cleanup function. Its context is canceled once the hook
+// timeout expires.
+// order: The position of the hook, e.g., 0 to stop accepting work
+// before 10 to flush buffers.
+func (sm *ShutdownManager) Register(name string, hook func(ctx context.Context) error, order int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.hooks = append(sm.hooks, shutdownHook{name: name, order: order, hook: hook})
+}
+
+// Shutdown stops listening for signals and runs the registered hooks.
+// A failing or timed out hook doesn't prevent later hooks from running.
+// Only the first call runs the hooks; later calls wait for it to finish.
+//
+// **Parameters:**
+//
+// ctx: The parent context of the hooks. Canceling it cancels the
+// running hook and skips the remaining ones.
+//
+// **Returns:**
+//
+// error: The errors of the hooks that failed, joined, or nil if all
+// succeeded.
+func (sm *ShutdownManager) Shutdown(ctx context.Context) error {
+	sm.once.Do(func() {
+		sm.Stop()
+		sm.err = sm.runHooks(ctx)
+		close(sm.done)
+	})
+
+	<-sm.done
+	return sm.err
+}
+
+// Stop stops listening for signals without running the hooks. Signals
+// are handled by the Go runtime again afterwards.
+func (sm *ShutdownManager) Stop() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	select {
+	case <-sm.stop:
+	default:
+		signal.Stop(sm.signals)
+		close(sm.stop)
+	}
+}
+
+// Done returns a channel that's closed once the hooks have run.
+//
+// **Returns:**
+//
+// <-chan struct{}: The channel.
+func (sm *ShutdownManager) Done() <-chan struct{} {
+	return sm.done
+}
+
+// Wait blocks until the hooks have run, e.g., after a signal, and
+// returns their errors.
+//
+// **Returns:**
+//
+// error: The errors of the hooks that failed, joined, or nil if all
+// succeeded.
+func (sm *ShutdownManager) Wait() error {
+	<-sm.done
+	return sm.err
+}
+
+// runHooks runs the hooks registered so far in order.
+func (sm *ShutdownManager) runHooks(ctx context.Context) error {
+	sm.mu.Lock()
+	hooks := make([]shutdownHook, len(sm.hooks))
+	copy(hooks, sm.hooks)
+	sm.mu.Unlock()
+
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].order < hooks[j].order })
+
+	timeout := sm.HookTimeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	logger := sm.logger()
+	var errs []error
+	for i, h := range hooks {
+		if ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("shutdown canceled before %d hooks ran: %w", len(hooks)-i, ctx.Err()))
+			break
+		}
+
+		start := time.Now()
+		if err := runHook(ctx, h, timeout); err != nil {
+			logger.Errorf("shutdown hook %s failed after %v: %v", h.name, time.Since(start).Round(time.Millisecond), err)
+			errs = append(errs, fmt.Errorf("shutdown hook %s: %w", h.name, err))
+			continue
+		}
+		logger.Debugf("shutdown hook %s finished in %v", h.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	return errors.Join(errs...)
+}
+
+// runHook runs a hook with a timeout. A hook that ignores its context
+// is abandoned once the timeout expires.
+func runHook(ctx context.Context, h shutdownHook, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		result <- h.hook(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %v: %w", timeout, ctx.Err())
+	}
+}
+
+// logger returns the configured logger or the default one.
+func (sm *ShutdownManager) logger() logging.Logger {
+	if sm.Logger != nil {
+		return sm.Logger
+	}
+	return logging.FromContext(context.Background())
+}
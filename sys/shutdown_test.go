@@ -0,0 +1,139 @@
+package sys_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/sys"
+)
+
+func TestShutdownManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		hooks    []string
+		orders   []int
+		failing  string
+		stuck    string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Hooks run in order",
+			hooks:    []string{"flush", "stop-server", "close-db"},
+			orders:   []int{10, 0, 20},
+			expected: []string{"stop-server", "flush", "close-db"},
+		},
+		{
+			name:     "Equal orders keep registration order",
+			hooks:    []string{"a", "b", "c"},
+			orders:   []int{1, 1, 0},
+			expected: []string{"c", "a", "b"},
+		},
+		{
+			name:     "Failing hook doesn't stop later hooks",
+			hooks:    []string{"a", "b"},
+			orders:   []int{0, 1},
+			failing:  "a",
+			expected: []string{"a", "b"},
+			wantErr:  true,
+		},
+		{
+			name:     "Stuck hook times out",
+			hooks:    []string{"a", "b"},
+			orders:   []int{0, 1},
+			stuck:    "a",
+			expected: []string{"a", "b"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := sys.NewShutdownManager()
+			sm.HookTimeout = 50 * time.Millisecond
+
+			var mu sync.Mutex
+			var ran []string
+			for i, name := range tc.hooks {
+				name := name
+				sm.Register(name, func(ctx context.Context) error {
+					mu.Lock()
+					ran = append(ran, name)
+					mu.Unlock()
+					switch name {
+					case tc.failing:
+						return errors.New("failed")
+					case tc.stuck:
+						<-ctx.Done()
+						return ctx.Err()
+					}
+					return nil
+				}, tc.orders[i])
+			}
+
+			err := sm.Shutdown(context.Background())
+			mu.Lock()
+			defer mu.Unlock()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Shutdown() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(ran) != len(tc.expected) {
+				t.Fatalf("hooks ran = %v, want %v", ran, tc.expected)
+			}
+			for i := range ran {
+				if ran[i] != tc.expected[i] {
+					t.Fatalf("hooks ran = %v, want %v", ran, tc.expected)
+				}
+			}
+
+			// Hooks only run once.
+			if err2 := sm.Shutdown(context.Background()); (err2 != nil) != tc.wantErr {
+				t.Errorf("second Shutdown() error = %v, wantErr %v", err2, tc.wantErr)
+			}
+			if len(ran) != len(tc.expected) {
+				t.Errorf("hooks ran again: %v", ran)
+			}
+		})
+	}
+}
+
+func TestShutdownManagerSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending SIGTERM is not supported on windows")
+	}
+
+	sm := sys.NewShutdownManager()
+	called := make(chan struct{})
+	sm.Register("cleanup", func(ctx context.Context) error {
+		close(called)
+		return nil
+	}, 0)
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("failed to find own process: %v", err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case <-sm.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("hooks didn't run after SIGTERM")
+	}
+	select {
+	case <-called:
+	default:
+		t.Fatal("hook wasn't called")
+	}
+	if err := sm.Wait(); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+}
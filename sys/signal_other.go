@@ -0,0 +1,14 @@
+//go:build !unix
+
+package sys
+
+import (
+	"fmt"
+	"os"
+)
+
+// userSignal always fails, since user-defined signals only exist on
+// Unix-like systems.
+func userSignal(signal Signal) (os.Signal, error) {
+	return nil, fmt.Errorf("unsupported signal: %v", signal)
+}
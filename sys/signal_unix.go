@@ -0,0 +1,17 @@
+//go:build unix
+
+package sys
+
+import (
+	"os"
+	"syscall"
+)
+
+// userSignal maps SignalUsr1 and SignalUsr2 to their os.Signal
+// counterparts.
+func userSignal(signal Signal) (os.Signal, error) {
+	if signal == SignalUsr1 {
+		return syscall.SIGUSR1, nil
+	}
+	return syscall.SIGUSR2, nil
+}
@@ -0,0 +1,216 @@
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrJunctionUnsupported is returned by CreateJunction on platforms other
+// than Windows.
+var ErrJunctionUnsupported = errors.New("junctions are only supported on Windows")
+
+// LinkMethod describes how SymlinkWithFallback linked a path.
+type LinkMethod int
+
+const (
+	// LinkSymlink indicates that a symbolic link was created.
+	LinkSymlink LinkMethod = iota + 1
+	// LinkJunction indicates that a Windows directory junction was
+	// created because symbolic links weren't available.
+	LinkJunction
+	// LinkCopy indicates that the target was copied because no kind of
+	// link was available. Later changes to the target aren't reflected
+	// at the link path.
+	LinkCopy
+)
+
+// String returns the name of the link method.
+//
+// **Returns:**
+//
+// string: The name of the link method (e.g., "symlink").
+func (m LinkMethod) String() string {
+	switch m {
+	case LinkSymlink:
+		return "symlink"
+	case LinkJunction:
+		return "junction"
+	case LinkCopy:
+		return "copy"
+	default:
+		return fmt.Sprintf("LinkMethod(%d)", int(m))
+	}
+}
+
+// Symlink creates link as a symbolic link to target. On Windows, creating
+// symbolic links requires either administrator privileges or Developer
+// Mode; use SymlinkWithFallback where neither can be relied on.
+//
+// **Parameters:**
+//
+// target: The path the link points to. Relative targets are resolved
+// against the directory containing link.
+// link: The path of the symbolic link to create.
+//
+// **Returns:**
+//
+// error: An error if the symbolic link can't be created.
+func Symlink(target, link string) error {
+	if err := os.Symlink(target, link); err != nil {
+		return fmt.Errorf("failed to create symlink %s -> %s: %w", link, target, err)
+	}
+
+	return nil
+}
+
+// SymlinkWithFallback creates link as a symbolic link to target and, if
+// the current user isn't allowed to create symbolic links or the
+// filesystem doesn't support them, falls back to a directory junction on
+// Windows when target is a directory, and otherwise to copying target to
+// link.
+//
+// **Parameters:**
+//
+// target: The path the link points to. Relative targets are resolved
+// against the directory containing link.
+// link: The path of the link to create.
+//
+// **Returns:**
+//
+// LinkMethod: How link was created.
+// error: An error if link can't be created by any method.
+func SymlinkWithFallback(target, link string) (LinkMethod, error) {
+	err := os.Symlink(target, link)
+	if err == nil {
+		return LinkSymlink, nil
+	}
+	if !symlinkUnavailable(err) {
+		return 0, fmt.Errorf("failed to create symlink %s -> %s: %w", link, target, err)
+	}
+
+	resolved := target
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(link), target)
+	}
+	info, statErr := os.Stat(resolved)
+	if statErr != nil {
+		return 0, fmt.Errorf("failed to create symlink %s -> %s: %v, and target can't be "+
+			"copied: %v", link, target, err, statErr)
+	}
+
+	if info.IsDir() {
+		if err := CreateJunction(resolved, link); err == nil {
+			return LinkJunction, nil
+		} else if !errors.Is(err, ErrJunctionUnsupported) {
+			return 0, err
+		}
+	}
+
+	if err := Cp(resolved, link); err != nil {
+		return 0, err
+	}
+
+	return LinkCopy, nil
+}
+
+// ReadLink returns the target of a symbolic link or, on Windows, a
+// directory junction.
+//
+// **Parameters:**
+//
+// link: The path of the link.
+//
+// **Returns:**
+//
+// string: The target of the link, as it was stored.
+// error: An error if link isn't a link or can't be read.
+func ReadLink(link string) (string, error) {
+	target, err := os.Readlink(link)
+	if err != nil {
+		return "", fmt.Errorf("failed to read link %s: %w", link, err)
+	}
+
+	return target, nil
+}
+
+// IsSymlink reports whether path is a symbolic link or, on Windows, a
+// directory junction. The link itself is inspected, not its target.
+//
+// **Parameters:**
+//
+// path: The path to check.
+//
+// **Returns:**
+//
+// bool: True if path is a symbolic link or junction.
+// error: An error if path can't be inspected.
+func IsSymlink(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return true, nil
+	}
+
+	return isJunction(path, info)
+}
+
+// CreateJunction creates link as a Windows directory junction to the
+// target directory. Unlike symbolic links, junctions don't require any
+// privileges, but they can only point to directories on local volumes.
+//
+// **Parameters:**
+//
+// target: The directory the junction points to. Relative paths are
+// resolved against the current working directory.
+// link: The path of the junction to create. It must not exist.
+//
+// **Returns:**
+//
+// error: ErrJunctionUnsupported on platforms other than Windows, or an
+// error if the junction can't be created.
+func CreateJunction(target, link string) error {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return fmt.Errorf("failed to resolve junction target %s: %v", target, err)
+	}
+
+	if err := createJunction(abs, link); err != nil {
+		if errors.Is(err, ErrJunctionUnsupported) {
+			return err
+		}
+		return fmt.Errorf("failed to create junction %s -> %s: %w", link, abs, err)
+	}
+
+	return nil
+}
+
+// SymlinkSupported reports whether the current process can create
+// symbolic links in dir, such as the directory an installer is about to
+// populate. It's checked by creating and removing a temporary link.
+//
+// **Parameters:**
+//
+// dir: The directory to check. An empty string uses the system's
+// temporary directory.
+//
+// **Returns:**
+//
+// bool: True if symbolic links can be created in dir.
+func SymlinkSupported(dir string) bool {
+	probe, err := os.MkdirTemp(dir, "symlink-probe-*")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(probe)
+
+	target := filepath.Join(probe, "target")
+	if err := os.WriteFile(target, nil, 0600); err != nil {
+		return false
+	}
+
+	return os.Symlink(target, filepath.Join(probe, "link")) == nil
+}
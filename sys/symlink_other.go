@@ -0,0 +1,27 @@
+//go:build !windows
+
+package sys
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// createJunction always fails, since junctions only exist on Windows.
+func createJunction(target, link string) error {
+	return ErrJunctionUnsupported
+}
+
+// isJunction always reports false, since junctions only exist on Windows.
+func isJunction(path string, info os.FileInfo) (bool, error) {
+	return false, nil
+}
+
+// symlinkUnavailable reports whether err means that symbolic links can't
+// be created at all, such as on filesystems like FAT that don't support
+// them, rather than that this particular link can't be created.
+func symlinkUnavailable(err error) bool {
+	return errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTSUP) ||
+		errors.Is(err, syscall.EOPNOTSUPP)
+}
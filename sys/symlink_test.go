@@ -0,0 +1,125 @@
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/l50/goutils/v2/sys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymlink(t *testing.T) {
+	if !sys.SymlinkSupported("") {
+		t.Skip("symbolic links can't be created by the current user")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "target.txt"), []byte("data"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "targetdir"), 0755))
+
+	testCases := []struct {
+		name    string
+		target  string
+		link    string
+		wantErr bool
+	}{
+		{name: "Absolute file target", target: filepath.Join(dir, "target.txt"), link: "abs.txt"},
+		{name: "Relative file target", target: "target.txt", link: "rel.txt"},
+		{name: "Directory target", target: "targetdir", link: "reldir"},
+		{name: "Existing link path", target: "target.txt", link: "target.txt", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			link := filepath.Join(dir, tc.link)
+			err := sys.Symlink(tc.target, link)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			isLink, err := sys.IsSymlink(link)
+			require.NoError(t, err)
+			require.True(t, isLink)
+
+			target, err := sys.ReadLink(link)
+			require.NoError(t, err)
+			require.Equal(t, tc.target, target)
+
+			_, err = os.Stat(link)
+			require.NoError(t, err, "link should resolve")
+		})
+	}
+
+	isLink, err := sys.IsSymlink(filepath.Join(dir, "target.txt"))
+	require.NoError(t, err)
+	require.False(t, isLink)
+
+	_, err = sys.IsSymlink(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+	_, err = sys.ReadLink(filepath.Join(dir, "target.txt"))
+	require.Error(t, err)
+}
+
+func TestSymlinkWithFallback(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "targetdir"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "targetdir", "file.txt"), []byte("data"), 0644))
+
+	link := filepath.Join(dir, "link")
+	method, err := sys.SymlinkWithFallback("targetdir", link)
+	require.NoError(t, err)
+
+	wantMethod := sys.LinkSymlink
+	if !sys.SymlinkSupported(dir) {
+		wantMethod = sys.LinkCopy
+		if runtime.GOOS == "windows" {
+			wantMethod = sys.LinkJunction
+		}
+	}
+	require.Equal(t, wantMethod, method)
+
+	data, err := os.ReadFile(filepath.Join(link, "file.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+
+	_, err = sys.SymlinkWithFallback("targetdir", link)
+	require.Error(t, err, "existing link path")
+}
+
+func TestCreateJunction(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "file.txt"), []byte("data"), 0644))
+	link := filepath.Join(dir, "junction")
+
+	err := sys.CreateJunction(target, link)
+	if runtime.GOOS != "windows" {
+		require.True(t, errors.Is(err, sys.ErrJunctionUnsupported), "CreateJunction() error = %v", err)
+		return
+	}
+	require.NoError(t, err)
+
+	isLink, err := sys.IsSymlink(link)
+	require.NoError(t, err)
+	require.True(t, isLink)
+
+	data, err := os.ReadFile(filepath.Join(link, "file.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+
+	require.Error(t, sys.CreateJunction(filepath.Join(target, "file.txt"), filepath.Join(dir, "file-junction")),
+		"junction to a file")
+}
+
+func TestLinkMethodString(t *testing.T) {
+	require.Equal(t, "symlink", sys.LinkSymlink.String())
+	require.Equal(t, "junction", sys.LinkJunction.String())
+	require.Equal(t, "copy", sys.LinkCopy.String())
+	require.Equal(t, "LinkMethod(9)", sys.LinkMethod(9).String())
+}
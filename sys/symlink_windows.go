@@ -0,0 +1,115 @@
+//go:build windows
+
+package sys
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"unicode/utf16"
+
+	"golang.org/x/sys/windows"
+)
+
+// createJunction creates link as a mount point reparse point that
+// redirects to the absolute directory target.
+func createJunction(target, link string) error {
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New("junction target is not a directory")
+	}
+
+	if err := os.Mkdir(link, 0755); err != nil {
+		return err
+	}
+	if err := setMountPoint(target, link); err != nil {
+		_ = os.Remove(link)
+		return err
+	}
+
+	return nil
+}
+
+// setMountPoint writes a mount point reparse point for target to the
+// empty directory dir.
+func setMountPoint(target, dir string) error {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return err
+	}
+	handle, err := windows.CreateFile(dirPtr, windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+		windows.FILE_FLAG_OPEN_REPARSE_POINT|windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(handle)
+
+	buf := mountPointReparseBuffer(target)
+	var returned uint32
+	return windows.DeviceIoControl(handle, windows.FSCTL_SET_REPARSE_POINT,
+		&buf[0], uint32(len(buf)), nil, 0, &returned, nil)
+}
+
+// mountPointReparseBuffer returns a REPARSE_DATA_BUFFER for a mount point
+// to target. The substitute name is the NT path of target, and the print
+// name, shown by tools such as dir, is target itself.
+func mountPointReparseBuffer(target string) []byte {
+	substitute := utf16.Encode([]rune(`\??\` + target))
+	printName := utf16.Encode([]rune(target))
+
+	// The path buffer holds both names, each followed by a NUL.
+	pathBuf := make([]uint16, 0, len(substitute)+len(printName)+2)
+	pathBuf = append(pathBuf, substitute...)
+	pathBuf = append(pathBuf, 0)
+	pathBuf = append(pathBuf, printName...)
+	pathBuf = append(pathBuf, 0)
+
+	const headerLen = 8        // ReparseTag, ReparseDataLength, Reserved
+	const mountPointHeader = 8 // name offsets and lengths
+	dataLen := mountPointHeader + 2*len(pathBuf)
+
+	buf := make([]byte, headerLen+dataLen)
+	binary.LittleEndian.PutUint32(buf[0:], windows.IO_REPARSE_TAG_MOUNT_POINT)
+	binary.LittleEndian.PutUint16(buf[4:], uint16(dataLen))
+	binary.LittleEndian.PutUint16(buf[8:], 0)                              // SubstituteNameOffset
+	binary.LittleEndian.PutUint16(buf[10:], uint16(2*len(substitute)))     // SubstituteNameLength
+	binary.LittleEndian.PutUint16(buf[12:], uint16(2*(len(substitute)+1))) // PrintNameOffset
+	binary.LittleEndian.PutUint16(buf[14:], uint16(2*len(printName)))      // PrintNameLength
+	for i, c := range pathBuf {
+		binary.LittleEndian.PutUint16(buf[headerLen+mountPointHeader+2*i:], c)
+	}
+
+	return buf
+}
+
+// isJunction reports whether path is a mount point reparse point. Recent
+// Go versions no longer report junctions as os.ModeSymlink.
+func isJunction(path string, info os.FileInfo) (bool, error) {
+	if !info.IsDir() && info.Mode()&os.ModeIrregular == 0 {
+		return false, nil
+	}
+
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return false, err
+	}
+	var data windows.Win32finddata
+	handle, err := windows.FindFirstFile(pathPtr, &data)
+	if err != nil {
+		return false, err
+	}
+	windows.FindClose(handle)
+
+	return data.FileAttributes&windows.FILE_ATTRIBUTE_REPARSE_POINT != 0 &&
+		data.Reserved0 == windows.IO_REPARSE_TAG_MOUNT_POINT, nil
+}
+
+// symlinkUnavailable reports whether err means that the current user
+// isn't allowed to create symbolic links, which requires administrator
+// privileges or Developer Mode.
+func symlinkUnavailable(err error) bool {
+	return errors.Is(err, windows.ERROR_PRIVILEGE_NOT_HELD)
+}
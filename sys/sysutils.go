@@ -1,7 +1,6 @@
 package sys
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -19,6 +18,7 @@ import (
 
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	fileutils "github.com/l50/goutils/v2/file/fileutils"
+	"github.com/l50/goutils/v2/logging"
 	cp "github.com/otiai10/copy"
 )
 
@@ -26,6 +26,16 @@ import (
 const (
 	// SignalKill represents a signal that kills a process immediately
 	SignalKill Signal = iota
+	// SignalTerm represents a signal that asks a process to terminate
+	SignalTerm
+	// SignalInt represents an interrupt signal, as sent by Ctrl+C
+	SignalInt
+	// SignalHup represents a hangup signal, commonly used to reload config
+	SignalHup
+	// SignalUsr1 represents the first user-defined signal
+	SignalUsr1
+	// SignalUsr2 represents the second user-defined signal
+	SignalUsr2
 )
 
 // Cmd represents a command to be executed in a shell environment.
@@ -35,17 +45,38 @@ const (
 // CmdString:     The command string to be executed.
 // Args:          Arguments for the command.
 // Dir:           The working directory for the command.
+// Env:           Extra KEY=VALUE pairs appended to the inherited environment.
+// Stdin:         Optional reader fed to the command's standard input.
 // Timeout:       Maximum duration to wait for the command to execute.
 //
 //	A value of 0 indicates no timeout.
 //
 // OutputHandler: Function to handle the output of the command.
+// Chroot:        Optional directory to use as the command's root
+//
+//	directory. CmdString and Dir are resolved inside it. Requires root
+//	or CAP_SYS_CHROOT unless NamespaceUser is also requested.
+//
+// Namespaces:    Linux namespaces to isolate the command in. Creating
+//
+//	namespaces other than NamespaceUser requires root or CAP_SYS_ADMIN
+//	unless NamespaceUser is also requested.
+//
+// Logger:        Optional logger that the command's start, output lines,
+//
+//	duration, and exit code are written to at Debug level. If set and
+//	OutputHandler is nil, output is no longer printed to stdout.
 type Cmd struct {
 	CmdString     string
 	Args          []string
 	Dir           string
+	Env           []string
+	Stdin         io.Reader
 	Timeout       time.Duration
 	OutputHandler func(string)
+	Chroot        string
+	Namespaces    []Namespace
+	Logger        logging.Logger
 }
 
 // Signal represents a signal that can be sent to a process.
@@ -53,8 +84,37 @@ type Cmd struct {
 // **Attributes:**
 //
 // SignalKill: A signal that causes the process to be killed immediately.
+// SignalTerm: A signal that requests graceful termination.
+// SignalInt: An interrupt signal.
+// SignalHup: A hangup signal.
+// SignalUsr1: The first user-defined signal.
+// SignalUsr2: The second user-defined signal.
 type Signal int
 
+// String returns the conventional name of the signal.
+//
+// **Returns:**
+//
+// string: The signal name (e.g., "SIGTERM").
+func (s Signal) String() string {
+	switch s {
+	case SignalKill:
+		return "SIGKILL"
+	case SignalTerm:
+		return "SIGTERM"
+	case SignalInt:
+		return "SIGINT"
+	case SignalHup:
+		return "SIGHUP"
+	case SignalUsr1:
+		return "SIGUSR1"
+	case SignalUsr2:
+		return "SIGUSR2"
+	default:
+		return fmt.Sprintf("Signal(%d)", int(s))
+	}
+}
+
 // CheckRoot checks if the current process is being run with root permissions.
 //
 // **Returns:**
@@ -330,36 +390,43 @@ func IsDirEmpty(name string) (bool, error) {
 }
 
 // KillProcess sends a signal to the process with the specified PID. On Windows,
-// it uses the taskkill command to terminate the process. On Unix-like systems,
-// it sends the specified signal to the process using the syscall.Kill function.
+// it uses the taskkill command: SignalKill forcefully terminates the process
+// tree, while SignalTerm, SignalInt, and SignalHup request a graceful close.
+// On Unix-like systems, it sends the corresponding signal to the process.
 //
-// Note that SignalKill may not work on all platforms. For more information,
+// Note that signals may not work on all platforms. For more information,
 // see the documentation for the syscall package.
 //
 // **Parameters:**
 //
 // pid: The process ID to kill.
-// signal: The signal to send to the process. Currently, only SignalKill is
-// supported, which terminates the process.
+// signal: The signal to send to the process. SignalUsr1 and SignalUsr2
+// are not supported on Windows.
 //
 // **Returns:**
 //
-// error: An error if the process couldn't be killed.
+// error: An error if the signal couldn't be delivered.
 func KillProcess(pid int, signal Signal) error {
 	if runtime.GOOS == "windows" {
-		cmd := exec.Command("taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", pid))
+		args := []string{"/T", "/PID", fmt.Sprintf("%d", pid)}
+		switch signal {
+		case SignalKill:
+			args = append([]string{"/F"}, args...)
+		case SignalTerm, SignalInt, SignalHup:
+		default:
+			return fmt.Errorf("unsupported signal: %v", signal)
+		}
+
+		cmd := exec.Command("taskkill", args...)
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("failed to kill process: %v", err)
 		}
 		return nil
 	}
 
-	var sig os.Signal
-	switch signal {
-	case SignalKill:
-		sig = syscall.SIGKILL
-	default:
-		return fmt.Errorf("unsupported signal: %v", signal)
+	sig, err := toOSSignal(signal)
+	if err != nil {
+		return err
 	}
 
 	p, err := os.FindProcess(pid)
@@ -374,6 +441,96 @@ func KillProcess(pid int, signal Signal) error {
 	return nil
 }
 
+// TerminateWithTimeout gracefully stops the process with the specified
+// PID. It sends SignalTerm, waits up to grace for the process to exit,
+// and sends SignalKill if it is still running afterwards.
+//
+// **Parameters:**
+//
+// pid: The process ID to terminate.
+// grace: How long to wait for the process to exit after SignalTerm.
+//
+// **Returns:**
+//
+// bool: True if the process had to be forcefully killed.
+// error: An error if either signal couldn't be delivered.
+func TerminateWithTimeout(pid int, grace time.Duration) (bool, error) {
+	if err := KillProcess(pid, SignalTerm); err != nil {
+		if !processRunning(pid) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	deadline := time.Now().Add(grace)
+	for time.Now().Before(deadline) {
+		if !processRunning(pid) {
+			return false, nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if !processRunning(pid) {
+		return false, nil
+	}
+
+	if err := KillProcess(pid, SignalKill); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// toOSSignal maps a Signal to its os.Signal counterpart on Unix-like
+// systems.
+func toOSSignal(signal Signal) (os.Signal, error) {
+	switch signal {
+	case SignalKill:
+		return syscall.SIGKILL, nil
+	case SignalTerm:
+		return syscall.SIGTERM, nil
+	case SignalInt:
+		return syscall.SIGINT, nil
+	case SignalHup:
+		return syscall.SIGHUP, nil
+	case SignalUsr1, SignalUsr2:
+		return userSignal(signal)
+	default:
+		return nil, fmt.Errorf("unsupported signal: %v", signal)
+	}
+}
+
+// processRunning reports whether the process with the specified PID is
+// still running. Zombie processes that have exited but not yet been
+// reaped are reported as not running.
+func processRunning(pid int) bool {
+	if runtime.GOOS == "windows" {
+		out, err := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/NH").Output()
+		return err == nil && strings.Contains(string(out), fmt.Sprintf(" %d ", pid))
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		return false
+	}
+
+	if runtime.GOOS == "linux" {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			return false
+		}
+		if proc, err := parseProcStat(pid, string(stat)); err == nil && proc.State == "Z" {
+			return false
+		}
+	}
+
+	return true
+}
+
 // RmRf deletes an input path and everything in it.
 // If the input path doesn't exist, an error is returned.
 //
@@ -430,23 +587,76 @@ func GetTempPath() string {
 // string: The output from the command.
 // error: An error if there was any problem running the command.
 func RunCommand(cmd string, args ...string) (string, error) {
+	return RunCommandWithLogger(nil, cmd, args...)
+}
+
+// RunCommandWithLogger executes a specified system command like
+// RunCommand, but routes it through logger instead of the terminal: the
+// command's start, every line of its output, its duration, and its exit
+// code are logged at Debug level.
+//
+// **Parameters:**
+//
+// logger: The logging.Logger to write to. If nil, output is printed to
+// os.Stdout and os.Stderr like RunCommand.
+// cmd: A string representing the command to run.
+// args: A variadic parameter representing any command line arguments to the command.
+//
+// **Returns:**
+//
+// string: The output from the command.
+// error: An error if there was any problem running the command.
+func RunCommandWithLogger(logger logging.Logger, cmd string, args ...string) (string, error) {
+	stdout, _, err := runLoggedCommand(logger, cmd, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return stdout, nil
+}
+
+// runCommand executes a command in its own process group, echoing its
+// output to the terminal while capturing stdout and stderr separately.
+func runCommand(cmd string, args ...string) (string, string, error) {
+	return runLoggedCommand(nil, cmd, args...)
+}
+
+// runLoggedCommand executes a command like runCommand. If logger isn't
+// nil, the output is logged instead of echoed to the terminal.
+func runLoggedCommand(logger logging.Logger, cmd string, args ...string) (string, string, error) {
 	execCmd := exec.Command(cmd, args...)
 	execCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true} // create new process group
 
 	var stdoutBuf, stderrBuf bytes.Buffer
-	multiStdout := io.MultiWriter(os.Stdout, &stdoutBuf) // write to both os.Stdout and stdoutBuf
-	multiStderr := io.MultiWriter(os.Stderr, &stderrBuf) // write to both os.Stderr and stderrBuf
+	var stdoutEcho, stderrEcho io.Writer = os.Stdout, os.Stderr
+	var stdoutLines, stderrLines *lineWriter
+	cmdLog := startCommandLog(logger, cmd, args, "")
+	if cmdLog != nil {
+		var mu sync.Mutex
+		stdoutLines = &lineWriter{mu: &mu, handler: func(line string) { cmdLog.output("stdout", line) }}
+		stderrLines = &lineWriter{mu: &mu, handler: func(line string) { cmdLog.output("stderr", line) }}
+		stdoutEcho, stderrEcho = stdoutLines, stderrLines
+	}
+	multiStdout := io.MultiWriter(stdoutEcho, &stdoutBuf) // write to both the echo and stdoutBuf
+	multiStderr := io.MultiWriter(stderrEcho, &stderrBuf) // write to both the echo and stderrBuf
 
 	// Attach to standard output and standard error
 	execCmd.Stdout = multiStdout
 	execCmd.Stderr = multiStderr
 
-	if err := execCmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run %s with args %v: stdout: %s, stderr: %s, err: %v",
-			cmd, args, stdoutBuf.String(), stderrBuf.String(), err)
+	err := execCmd.Run()
+	if cmdLog != nil {
+		stdoutLines.flush()
+		stderrLines.flush()
+	}
+	cmdLog.finish(err)
+	if err != nil {
+		return stdoutBuf.String(), stderrBuf.String(),
+			fmt.Errorf("failed to run %s with args %v: stdout: %s, stderr: %s, err: %v",
+				cmd, args, stdoutBuf.String(), stderrBuf.String(), err)
 	}
 
-	return stdoutBuf.String(), nil
+	return stdoutBuf.String(), stderrBuf.String(), nil
 }
 
 // RunCommandWithTimeout executes a command for a specified number of
@@ -505,20 +715,26 @@ func RunCommandWithTimeout(to int, cmd string, args ...string) (string, error) {
 }
 
 // RunCmd executes a command with the settings specified in the Cmd struct.
-// It starts the command, optionally manages a timeout, and captures the
-// command's output. If the command does not complete within the specified
-// timeout, it is forcibly terminated.
+// It starts the command in the configured working directory and
+// environment, feeds it Stdin if set, optionally manages a timeout, and
+// captures the command's output. If the command does not complete within
+// the specified timeout, its process group is forcibly terminated. If
+// Logger is set, the command's start, output, duration, and exit code are
+// logged at Debug level.
 //
 // **Returns:**
 //
 // string: The combined output from both standard output and standard error
 // of the executed command. If an error occurs or the command times out,
-// an empty string is returned.
+// the output captured so far is returned.
 // error: An error if any issue occurs while executing the command, including
 // a timeout.
 func (c *Cmd) RunCmd() (string, error) {
 	if c.OutputHandler == nil {
 		c.OutputHandler = func(s string) { fmt.Println(s) }
+		if c.Logger != nil {
+			c.OutputHandler = func(string) {}
+		}
 	}
 
 	var ctx context.Context
@@ -532,38 +748,69 @@ func (c *Cmd) RunCmd() (string, error) {
 
 	execCmd := exec.CommandContext(ctx, c.CmdString, c.Args...)
 	execCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if c.sandboxed() {
+		if err := c.applySandbox(execCmd.SysProcAttr); err != nil {
+			return "", err
+		}
+		// exec.Command resolves CmdString against the host's filesystem,
+		// so resolve it inside the chroot instead.
+		if c.Chroot != "" {
+			path, err := lookPathInRoot(c.Chroot, c.CmdString)
+			if err != nil {
+				return "", err
+			}
+			execCmd.Path = path
+			execCmd.Err = nil
+		}
+	}
 	execCmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		execCmd.Env = append(os.Environ(), c.Env...)
+	}
+	execCmd.Stdin = c.Stdin
 
-	stdout, err := execCmd.StdoutPipe()
-	if err != nil {
-		return "", fmt.Errorf("failed to get stdout pipe: %v", err)
+	// Kill the whole process group on timeout so that children holding
+	// the output pipes open don't keep the command alive.
+	execCmd.Cancel = func() error {
+		return KillProcess(-execCmd.Process.Pid, SignalKill)
 	}
-	stderr, err := execCmd.StderrPipe()
-	if err != nil {
-		return "", fmt.Errorf("failed to get stderr pipe: %v", err)
+	execCmd.WaitDelay = time.Second
+
+	var outputBuf bytes.Buffer
+	var mu sync.Mutex
+
+	cmdLog := startCommandLog(c.Logger, c.CmdString, c.Args, c.Dir)
+	streamHandler := func(stream string) func(string) {
+		return func(line string) {
+			cmdLog.output(stream, line)
+			c.OutputHandler(line)
+		}
 	}
+	stdout := &lineWriter{mu: &mu, buf: &outputBuf, handler: streamHandler("stdout")}
+	stderr := &lineWriter{mu: &mu, buf: &outputBuf, handler: streamHandler("stderr")}
+	execCmd.Stdout = stdout
+	execCmd.Stderr = stderr
 
 	// Start the command
 	if err := execCmd.Start(); err != nil {
+		cmdLog.finish(err)
+		if c.sandboxed() && errors.Is(err, syscall.EPERM) {
+			return "", fmt.Errorf("failed to start command %s in sandbox (chroot and namespaces "+
+				"require root, or NamespaceUser where unprivileged user namespaces are enabled): %v",
+				c.CmdString, err)
+		}
 		return "", fmt.Errorf("failed to start command %s: %v", c.CmdString, err)
 	}
 
-	var outputBuf bytes.Buffer
-	var mu sync.Mutex
-
-	go c.handleOutput(stdout, &outputBuf, &mu)
-	go c.handleOutput(stderr, &outputBuf, &mu)
-
-	// Wait for the command to complete
-	err = execCmd.Wait()
+	// Wait for the command to complete and its output to be consumed
+	err := execCmd.Wait()
+	stdout.flush()
+	stderr.flush()
+	cmdLog.finish(err)
 
 	if err != nil {
 		// Handle error (including timeout)
 		if ctx.Err() == context.DeadlineExceeded {
-			// The command timed out, now force kill the process group
-			if err := KillProcess(-execCmd.Process.Pid, SignalKill); err != nil {
-				return outputBuf.String(), fmt.Errorf("failed to kill process group: %v", err)
-			}
 			return outputBuf.String(), fmt.Errorf("command timed out")
 		} else if exitErr, ok := err.(*exec.ExitError); ok {
 			// The command exited with a non-zero status
@@ -575,16 +822,49 @@ func (c *Cmd) RunCmd() (string, error) {
 	return outputBuf.String(), nil
 }
 
-// handleOutput reads from the provided reader (standard output
-// or standard error of the command) and sends each line of
-// output to the OutputHandler function of the Cmd struct, while also writing it to the output buffer.
-func (c *Cmd) handleOutput(reader io.Reader, outputBuf *bytes.Buffer, mu *sync.Mutex) {
-	scanner := bufio.NewScanner(reader)
-	for scanner.Scan() {
-		line := scanner.Text()
-		mu.Lock()
-		c.OutputHandler(line)
-		outputBuf.WriteString(line + "\n")
-		mu.Unlock()
+// lineWriter is an io.Writer that splits the output of a command
+// into lines, sending each line to the OutputHandler of the Cmd
+// struct while also writing it to a shared output buffer, if set.
+type lineWriter struct {
+	mu      *sync.Mutex
+	buf     *bytes.Buffer
+	handler func(string)
+	partial []byte
+}
+
+// Write buffers p and emits every complete line it contains.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// flush emits any trailing output that wasn't terminated by a newline.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.partial) > 0 {
+		w.emit(string(w.partial))
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	line = strings.TrimSuffix(line, "\r")
+	w.handler(line)
+	if w.buf != nil {
+		w.buf.WriteString(line + "\n")
 	}
 }
@@ -115,6 +115,16 @@ func ExampleKillProcess() {
 	}
 }
 
+func ExampleTerminateWithTimeout() {
+	killed, err := sys.TerminateWithTimeout(1234, 5*time.Second)
+	if err != nil {
+		log.L().Errorf("Failed to terminate process: %v", err)
+		return
+	}
+
+	log.L().Println("Process required SIGKILL:", killed)
+}
+
 func ExampleRunCommand() {
 	output, err := sys.RunCommand("ls", "-l")
 
@@ -694,6 +694,79 @@ func TestKillProcess(t *testing.T) {
 	}
 }
 
+func TestTerminateWithTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signal handling differs on Windows")
+	}
+
+	testCases := []struct {
+		name       string
+		args       []string
+		grace      time.Duration
+		wantKilled bool
+	}{
+		{
+			name:       "Process exits on SIGTERM",
+			args:       []string{"sleep", "30"},
+			grace:      2 * time.Second,
+			wantKilled: false,
+		},
+		{
+			name:       "Process ignoring SIGTERM is killed",
+			args:       []string{"sh", "-c", "trap '' TERM; while true; do sleep 0.1; done"},
+			grace:      300 * time.Millisecond,
+			wantKilled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(tc.args[0], tc.args[1:]...)
+			if err := cmd.Start(); err != nil {
+				t.Fatalf("failed to start process: %v", err)
+			}
+
+			// Give the shell time to install its trap
+			time.Sleep(200 * time.Millisecond)
+
+			killed, err := sys.TerminateWithTimeout(cmd.Process.Pid, tc.grace)
+			if err != nil {
+				t.Fatalf("TerminateWithTimeout() error = %v", err)
+			}
+			if killed != tc.wantKilled {
+				t.Errorf("expected killed = %v, got %v", tc.wantKilled, killed)
+			}
+
+			if err := cmd.Wait(); err == nil {
+				t.Errorf("expected process %d to be terminated by a signal", cmd.Process.Pid)
+			}
+		})
+	}
+}
+
+func TestSignalString(t *testing.T) {
+	testCases := []struct {
+		signal sys.Signal
+		want   string
+	}{
+		{signal: sys.SignalKill, want: "SIGKILL"},
+		{signal: sys.SignalTerm, want: "SIGTERM"},
+		{signal: sys.SignalInt, want: "SIGINT"},
+		{signal: sys.SignalHup, want: "SIGHUP"},
+		{signal: sys.SignalUsr1, want: "SIGUSR1"},
+		{signal: sys.SignalUsr2, want: "SIGUSR2"},
+		{signal: sys.Signal(999), want: "Signal(999)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := tc.signal.String(); got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
 type MockFile struct {
 	open      func() (io.ReadCloser, error)
 	write     func(contents []byte, perm os.FileMode) error
@@ -981,6 +1054,38 @@ func TestRunCmd(t *testing.T) {
 			expectedOutput: "",
 			expectError:    true,
 		},
+		{
+			name: "Command with Stdin",
+			cmd: &sys.Cmd{
+				CmdString:     "cat",
+				Stdin:         strings.NewReader("piped input"),
+				OutputHandler: logOutputHandler,
+			},
+			expectedOutput: "piped input\n",
+			expectError:    false,
+		},
+		{
+			name: "Command with Env",
+			cmd: &sys.Cmd{
+				CmdString:     "printenv",
+				Args:          []string{"GOUTILS_TEST_VAR"},
+				Env:           []string{"GOUTILS_TEST_VAR=injected"},
+				OutputHandler: logOutputHandler,
+			},
+			expectedOutput: "injected\n",
+			expectError:    false,
+		},
+		{
+			name: "Command with Dir",
+			cmd: &sys.Cmd{
+				CmdString:     "sh",
+				Args:          []string{"-c", "pwd"},
+				Dir:           "/",
+				OutputHandler: logOutputHandler,
+			},
+			expectedOutput: "/\n",
+			expectError:    false,
+		},
 		{
 			name: "Command with Timeout",
 			cmd: &sys.Cmd{
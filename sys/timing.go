@@ -0,0 +1,211 @@
+package sys
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/l50/goutils/v2/logging"
+)
+
+// DefaultTimer is the CumulativeTimer used by TimeIt. Call
+// DefaultTimer.LogSummary before the program exits, e.g. with defer in
+// main or at the end of a mage target, to log the timings.
+var DefaultTimer = NewCumulativeTimer()
+
+// TimingStat holds the timings recorded for an operation.
+//
+// **Attributes:**
+//
+// Name: The name of the operation.
+// Count: The number of times the operation was recorded.
+// Total: The total time spent in the operation.
+// Min: The shortest recorded duration.
+// Max: The longest recorded duration.
+type TimingStat struct {
+	Name  string
+	Count int
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+}
+
+// Mean returns the average duration of the operation.
+//
+// **Returns:**
+//
+// time.Duration: The average duration, or 0 if nothing was recorded.
+func (s TimingStat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// CumulativeTimer accumulates the durations of named operations so that
+// a summary can be reported once a sequence of steps has finished. It's
+// safe for concurrent use.
+type CumulativeTimer struct {
+	mu    sync.Mutex
+	stats map[string]*TimingStat
+	order []string
+}
+
+// NewCumulativeTimer creates an empty CumulativeTimer.
+//
+// **Returns:**
+//
+// *CumulativeTimer: The new timer.
+func NewCumulativeTimer() *CumulativeTimer {
+	return &CumulativeTimer{stats: make(map[string]*TimingStat)}
+}
+
+// TimeIt runs fn, records its duration under name in DefaultTimer, and
+// returns the duration along with the error from fn. The duration is
+// recorded whether or not fn fails.
+//
+// **Parameters:**
+//
+// name: The name of the operation.
+// fn: The operation to time.
+//
+// **Returns:**
+//
+// time.Duration: How long fn took to run.
+// error: The error returned by fn.
+func TimeIt(name string, fn func() error) (time.Duration, error) {
+	return DefaultTimer.TimeIt(name, fn)
+}
+
+// TimeIt runs fn, records its duration under name, and returns the
+// duration along with the error from fn. The duration is recorded
+// whether or not fn fails.
+//
+// **Parameters:**
+//
+// name: The name of the operation.
+// fn: The operation to time.
+//
+// **Returns:**
+//
+// time.Duration: How long fn took to run.
+// error: The error returned by fn.
+func (t *CumulativeTimer) TimeIt(name string, fn func() error) (time.Duration, error) {
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start)
+	t.Record(name, elapsed)
+
+	return elapsed, err
+}
+
+// Record adds a duration to the timings of the named operation.
+//
+// **Parameters:**
+//
+// name: The name of the operation.
+// d: The duration to record.
+func (t *CumulativeTimer) Record(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stat, ok := t.stats[name]
+	if !ok {
+		stat = &TimingStat{Name: name, Min: d, Max: d}
+		t.stats[name] = stat
+		t.order = append(t.order, name)
+	}
+	stat.Count++
+	stat.Total += d
+	stat.Min = min(stat.Min, d)
+	stat.Max = max(stat.Max, d)
+}
+
+// Stats returns the timings recorded so far, in the order the operations
+// were first recorded.
+//
+// **Returns:**
+//
+// []TimingStat: A copy of the recorded timings.
+func (t *CumulativeTimer) Stats() []TimingStat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]TimingStat, 0, len(t.order))
+	for _, name := range t.order {
+		stats = append(stats, *t.stats[name])
+	}
+	return stats
+}
+
+// Reset discards all recorded timings.
+func (t *CumulativeTimer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats = make(map[string]*TimingStat)
+	t.order = nil
+}
+
+// Summary formats the recorded timings as a table with a row per
+// operation and a final row with the overall total.
+//
+// **Returns:**
+//
+// string: The table, or an empty string if nothing was recorded.
+func (t *CumulativeTimer) Summary() string {
+	stats := t.Stats()
+	if len(stats) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tCOUNT\tTOTAL\tMEAN\tMIN\tMAX")
+	var total time.Duration
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", s.Name, s.Count, round(s.Total), round(s.Mean()), round(s.Min), round(s.Max))
+		total += s.Total
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%s\t\t\t\n", round(total))
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// LogSummary logs the summary table, one line per row. Nothing is logged
+// if no timings were recorded.
+//
+// **Parameters:**
+//
+// logger: The logger to write to. If nil, the logger from
+// logging.FromContext is used.
+func (t *CumulativeTimer) LogSummary(logger logging.Logger) {
+	if logger == nil {
+		logger = logging.FromContext(context.Background())
+	}
+
+	summary := t.Summary()
+	if summary == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(summary, "\n"), "\n") {
+		logger.Println(strings.TrimRight(line, " "))
+	}
+}
+
+// round shortens a duration for display while keeping sub-millisecond
+// timings readable.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d
+	}
+}
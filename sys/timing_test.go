@@ -0,0 +1,77 @@
+package sys_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// printLogger is a logging.Logger that records its Println messages.
+type printLogger struct {
+	recordingLogger
+	lines []string
+}
+
+func (l *printLogger) Println(v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(v...))
+}
+
+func TestTimeIt(t *testing.T) {
+	errStep := errors.New("step failed")
+	testCases := []struct {
+		name    string
+		fn      func() error
+		wantErr error
+	}{
+		{name: "Success", fn: func() error { time.Sleep(5 * time.Millisecond); return nil }},
+		{name: "Failure is still recorded", fn: func() error { return errStep }, wantErr: errStep},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sys.DefaultTimer.Reset()
+			t.Cleanup(sys.DefaultTimer.Reset)
+
+			elapsed, err := sys.TimeIt(tc.name, tc.fn)
+			assert.ErrorIs(t, err, tc.wantErr)
+			stats := sys.DefaultTimer.Stats()
+			require.Len(t, stats, 1)
+			assert.Equal(t, tc.name, stats[0].Name)
+			assert.Equal(t, 1, stats[0].Count)
+			assert.Equal(t, elapsed, stats[0].Total)
+		})
+	}
+}
+
+func TestCumulativeTimer(t *testing.T) {
+	timer := sys.NewCumulativeTimer()
+	assert.Empty(t, timer.Summary())
+
+	timer.Record("build", 3*time.Second)
+	timer.Record("test", 500*time.Millisecond)
+	timer.Record("build", time.Second)
+
+	assert.Equal(t, []sys.TimingStat{
+		{Name: "build", Count: 2, Total: 4 * time.Second, Min: time.Second, Max: 3 * time.Second},
+		{Name: "test", Count: 1, Total: 500 * time.Millisecond, Min: 500 * time.Millisecond, Max: 500 * time.Millisecond},
+	}, timer.Stats())
+	assert.Equal(t, 2*time.Second, timer.Stats()[0].Mean())
+	assert.Equal(t, time.Duration(0), sys.TimingStat{}.Mean())
+
+	logger := &printLogger{}
+	timer.LogSummary(logger)
+	require.Len(t, logger.lines, 4)
+	assert.Equal(t, []string{"OPERATION", "COUNT", "TOTAL", "MEAN", "MIN", "MAX"}, strings.Fields(logger.lines[0]))
+	assert.Equal(t, []string{"build", "2", "4s", "2s", "1s", "3s"}, strings.Fields(logger.lines[1]))
+	assert.Equal(t, []string{"test", "1", "500ms", "500ms", "500ms", "500ms"}, strings.Fields(logger.lines[2]))
+	assert.Equal(t, []string{"TOTAL", "4.5s"}, strings.Fields(logger.lines[3]))
+
+	timer.Reset()
+	assert.Empty(t, timer.Stats())
+}
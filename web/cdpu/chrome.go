@@ -10,15 +10,17 @@ import (
 	"time"
 
 	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/logging"
 	"github.com/l50/goutils/v2/web"
 )
 
 // CheckElement checks if a web page element, identified by the provided XPath,
-// exists within a specified timeout.
+// exists within a specified timeout. It reports a found element as a locked
+// out account; use ElementExists for a general check.
 //
 // **Note:** Ensure to handle the error sent to the "done" channel in a
 // separate goroutine or after calling this function to avoid deadlock.
@@ -87,6 +89,10 @@ func CheckElement(site web.Site, elementXPath string, done chan error) error {
 type Driver struct {
 	Context context.Context
 	Options *[]chromedp.ExecAllocatorOption
+
+	// targets holds the contexts attached to the tabs and frames the
+	// driver has switched to, keyed by target.
+	targets map[target.ID]context.Context
 }
 
 // GetContext retrieves the context associated with the Driver instance.
@@ -121,6 +127,12 @@ func (d *Driver) SetContext(ctx context.Context) {
 // web.Browser: An initialized Browser instance.
 // error: Any error encountered during initialization.
 func Init(headless bool, ignoreCertErrors bool) (web.Browser, error) {
+	return initBrowser(headless, ignoreCertErrors)
+}
+
+// initBrowser initializes a chrome browser instance like Init, adding
+// the extra allocator options.
+func initBrowser(headless bool, ignoreCertErrors bool, extra ...chromedp.ExecAllocatorOption) (web.Browser, error) {
 	var cancels []func()
 
 	browser := web.Browser{
@@ -129,6 +141,7 @@ func Init(headless bool, ignoreCertErrors bool) (web.Browser, error) {
 
 	options := []chromedp.ExecAllocatorOption{}
 	setChromeOptions(&browser, headless, ignoreCertErrors, &options)
+	options = append(options, extra...)
 
 	driver, ok := browser.Driver.(*Driver)
 	if !ok {
@@ -190,6 +203,38 @@ func GetPageSource(site web.Site) (string, error) {
 	return pageSource, err
 }
 
+// NavigateOptions holds optional behavior for Navigate.
+//
+// **Attributes:**
+//
+// console: Collector that receives console messages and JS exceptions.
+type NavigateOptions struct {
+	console *ConsoleCollector
+}
+
+// NavigateOption is a type for functions that modify the navigate options.
+// These functions take a pointer to a NavigateOptions struct and modify it
+// in place.
+type NavigateOption func(*NavigateOptions)
+
+// WithConsoleCapture returns a NavigateOption that records browser console
+// messages and uncaught JavaScript exceptions in the input collector while
+// the actions run.
+//
+// **Parameters:**
+//
+// collector: The ConsoleCollector that receives captured records.
+//
+// **Returns:**
+//
+// NavigateOption: A function that sets the console collector of a
+// NavigateOptions struct.
+func WithConsoleCapture(collector *ConsoleCollector) NavigateOption {
+	return func(opts *NavigateOptions) {
+		opts.console = collector
+	}
+}
+
 // Navigate performs the provided actions sequentially on the provided Site's
 // session. It enables network events and sets up request logging.
 //
@@ -199,29 +244,40 @@ func GetPageSource(site web.Site) (string, error) {
 // actions ([]InputAction): A slice of InputAction objects which define
 // the actions to be performed.
 // waitTime (time.Duration): The time to wait between actions.
+// options (...NavigateOption): Optional behavior such as console capture.
 //
 // **Returns:**
 //
 // error: An error if any occurred during navigation.
-func Navigate(site web.Site, actions []InputAction, waitTime time.Duration) error {
+func Navigate(site web.Site, actions []InputAction, waitTime time.Duration, options ...NavigateOption) error {
 	chromeDriver, ok := site.Session.Driver.(*Driver)
 	if !ok {
 		return errors.New("driver is not of type *Driver")
 	}
 
+	navOpts := &NavigateOptions{}
+	for _, option := range options {
+		option(navOpts)
+	}
+
 	if err := enableNetwork(chromeDriver); err != nil {
 		return err
 	}
 
 	setUpRequestLogging(chromeDriver, &site)
+	logger := logging.FromContext(chromeDriver.GetContext())
+
+	if navOpts.console != nil {
+		navOpts.console.attach(chromeDriver.GetContext())
+	}
 
 	for i, inputAction := range actions {
 		actionType := fmt.Sprintf("%T", inputAction.Action)
 		if inputAction.Description != "" && site.Debug {
-			fmt.Printf("Executing action #%d:\n Description: %s\nType: %s", i+1, inputAction.Description, actionType)
+			logger.Debugf("Executing action #%d:\n Description: %s\nType: %s", i+1, inputAction.Description, actionType)
 		}
 		if site.Debug {
-			fmt.Printf("Executing action #%d:\n Type: %s", i+1, actionType)
+			logger.Debugf("Executing action #%d:\n Type: %s", i+1, actionType)
 		}
 
 		ctx := inputAction.Context
@@ -241,7 +297,10 @@ func Navigate(site web.Site, actions []InputAction, waitTime time.Duration) erro
 }
 
 // ScreenShot captures a screenshot of the currently loaded page in the
-// provided Site's session and writes the image data to the provided file path.
+// provided Site's session at a 1280x800 viewport and writes the image
+// data to the provided file path. The image is a JPEG if imgPath ends
+// in ".jpg" or ".jpeg", and a PNG otherwise. Use ScreenShotWithOptions
+// for full-page or element captures and other viewports.
 //
 // **Parameters:**
 //
@@ -252,22 +311,7 @@ func Navigate(site web.Site, actions []InputAction, waitTime time.Duration) erro
 //
 // error: An error if any occurred during screenshot capturing or saving.
 func ScreenShot(site web.Site, imgPath string) error {
-	var screenshot []byte
-	// Convert the driver to a chrome-specific *Driver instance
-	chromeDriver, ok := site.Session.Driver.(*Driver)
-	if !ok {
-		return errors.New("driver is not of type *Driver")
-	}
-
-	if err := chromedp.Run(chromeDriver.Context, takeSS(100, &screenshot)); err != nil {
-		return fmt.Errorf("failed to take screenshot: %v", err)
-	}
-
-	if err := os.WriteFile(imgPath, screenshot, 0644); err != nil {
-		return fmt.Errorf("failed to write screenshot to disk: %v", err)
-	}
-
-	return nil
+	return ScreenShotWithOptions(site, imgPath, ScreenshotOptions{})
 }
 
 // enableNetwork enables network events
@@ -282,7 +326,8 @@ func setChromeOptions(browser *web.Browser, headless bool,
 	*options = append(*options,
 		chromedp.DisableGPU,
 		chromedp.Flag("ignoreCertErrors", ignoreCertErrors),
-		// Uncomment to prevent navigation to a new tab
+		// Uncomment to prevent navigation to a new tab. Pages that open
+		// new tabs can be driven with WaitForNewTab and SwitchToTab.
 		// chromedp.Flag("block-new-web-contents", true),
 		chromedp.NoDefaultBrowserCheck,
 		chromedp.NoFirstRun,
@@ -295,64 +340,33 @@ func setChromeOptions(browser *web.Browser, headless bool,
 
 // setUpRequestLogging sets up request logging
 func setUpRequestLogging(chromeDriver *Driver, site *web.Site) {
+	logger := logging.FromContext(chromeDriver.Context)
 	chromedp.ListenTarget(chromeDriver.Context, func(ev interface{}) {
 		switch msg := ev.(type) {
 		case *page.EventJavascriptDialogOpening:
 			go func() {
 				if err := chromedp.Run(chromeDriver.Context,
 					page.HandleJavaScriptDialog(true)); err != nil {
-					fmt.Printf("error handling JavaScript dialog: %v", err)
+					logger.Errorf("error handling JavaScript dialog: %v", err)
 				}
 			}()
 		case *network.EventRequestWillBeSent:
 			// Check if we have been redirected
 			// if so, change the URL that we are tracking.
 			if msg.RedirectResponse != nil && site.Debug {
-				fmt.Printf("Encountered redirect: %s\n", msg.RedirectResponse.URL)
+				logger.Debugf("Encountered redirect: %s", msg.RedirectResponse.URL)
 			}
 		case *network.EventResponseReceived:
 			if site.Debug {
-				fmt.Printf("Response URL: %s\n Response Headers: %s\n Response Status Code: %d\n",
+				logger.Debugf("Response URL: %s\n Response Headers: %s\n Response Status Code: %d",
 					msg.Response.URL, msg.Response.Headers, msg.Response.Status)
 			}
 		}
 	})
 }
 
-func takeSS(quality int, res *[]byte) chromedp.Tasks {
-	return chromedp.Tasks{
-		chromedp.ActionFunc(func(ctx context.Context) error {
-
-			// force viewport emulation
-			err := emulation.SetDeviceMetricsOverride(1280, 800, 1, false).
-				WithScreenOrientation(&emulation.ScreenOrientation{
-					Type:  emulation.OrientationTypePortraitPrimary,
-					Angle: 0,
-				}).
-				Do(ctx)
-			if err != nil {
-				return err
-			}
-
-			// capture screenshot
-			*res, err = page.CaptureScreenshot().
-				WithQuality(100).
-				WithClip(&page.Viewport{
-					X:      0,
-					Y:      0,
-					Width:  1280,
-					Height: 800,
-					Scale:  1,
-				}).Do(ctx)
-			if err != nil {
-				return err
-			}
-			return nil
-		}),
-	}
-}
-
 // SaveCookiesToDisk retrieves cookies from the current session and writes them to a file.
+// Use LoadCookiesFromDisk to restore them.
 //
 // **Parameters:**
 //
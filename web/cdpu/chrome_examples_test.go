@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/device"
 	"github.com/l50/goutils/v2/web"
 	"github.com/l50/goutils/v2/web/cdpu"
 )
@@ -119,6 +120,25 @@ func ExampleNavigate() {
 	}
 }
 
+func ExampleWithConsoleCapture() {
+	actions := []cdpu.InputAction{
+		// initialize actions
+	}
+
+	site := web.Site{
+		// initialize site
+	}
+
+	collector := cdpu.NewConsoleCollector(nil)
+	if err := cdpu.Navigate(site, actions, 1000, cdpu.WithConsoleCapture(collector)); err != nil {
+		log.Fatalf("failed to navigate site: %v", err)
+	}
+
+	for _, msg := range collector.Exceptions() {
+		log.Printf("uncaught exception at %s:%d: %s", msg.URL, msg.Line, msg.Text)
+	}
+}
+
 func ExampleScreenShot() {
 	site := web.Site{
 		// initialize site
@@ -128,3 +148,67 @@ func ExampleScreenShot() {
 		log.Fatalf("failed to capture screenshot: %v", err)
 	}
 }
+
+func ExampleCaptureDiagnostics() {
+	site := web.Site{
+		// initialize site
+	}
+
+	if err := cdpu.Login(site, "#username", "#password", "#submit"); err != nil {
+		manifest, diagErr := cdpu.CaptureDiagnostics(site, "diagnostics", cdpu.WithDiagnosticsLabel("login"))
+		if manifest != nil {
+			log.Printf("saved diagnostics to %s", manifest.Dir)
+		}
+		log.Fatalf("failed to log in: %v (diagnostics: %v)", err, diagErr)
+	}
+}
+
+func ExampleScreenShotWithOptions() {
+	site := web.Site{
+		// initialize site
+	}
+
+	opts := cdpu.ScreenshotOptions{Device: device.IPhone13, FullPage: true, Quality: 80}
+	if err := cdpu.ScreenShotWithOptions(site, "/path/to/save/image.jpg", opts); err != nil {
+		log.Fatalf("failed to capture screenshot: %v", err)
+	}
+}
+
+func ExampleWaitForNavigation() {
+	site := web.Site{
+		// initialize site and submit the login form
+	}
+
+	landingURL, err := cdpu.WaitForNavigation(site, `/dashboard`, 30*time.Second)
+	if err != nil {
+		log.Fatalf("login did not redirect to the dashboard: %v", err)
+	}
+
+	log.Printf("logged in, landed on %s", landingURL)
+}
+
+func ExampleWithinFrame() {
+	site := web.Site{
+		// initialize site and load a page embedding a payment form
+	}
+
+	actions, err := cdpu.WithinFrame(site, "iframe#payment", func(inFrame chromedp.QueryOption) []cdpu.InputAction {
+		return []cdpu.InputAction{
+			{
+				Description: "Enter the card number",
+				Action:      chromedp.SendKeys("#card-number", "4242424242424242", chromedp.ByQuery, inFrame),
+			},
+			{
+				Description: "Submit the payment",
+				Action:      chromedp.Click("#pay", chromedp.ByQuery, inFrame),
+			},
+		}
+	})
+	if err != nil {
+		log.Fatalf("failed to find the payment frame: %v", err)
+	}
+
+	if err := cdpu.Navigate(site, actions, time.Second); err != nil {
+		log.Fatalf("failed to submit the payment: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package cdpu
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/logging"
+)
+
+// ConsoleMessage is a typed record of a browser console message or an
+// uncaught JavaScript exception.
+//
+// **Attributes:**
+//
+// Level: The console API type (e.g., "log", "warning", "error") or
+// "exception" for uncaught exceptions.
+// Text: The message text, with console arguments joined by spaces.
+// URL: The script URL the message originated from, if known.
+// Line: The 1-based line number the message originated from, if known.
+// Column: The 1-based column number the message originated from, if known.
+// Exception: True if the record describes an uncaught exception.
+// Timestamp: When the browser emitted the message.
+type ConsoleMessage struct {
+	Level     string
+	Text      string
+	URL       string
+	Line      int64
+	Column    int64
+	Exception bool
+	Timestamp time.Time
+}
+
+// ConsoleCollector gathers console messages and uncaught exceptions
+// emitted by the browser while actions run. Records can be read back
+// with Messages and Exceptions, and are optionally forwarded to a
+// goutils Logger as they arrive.
+//
+// **Attributes:**
+//
+// Logger: Optional logger that receives every captured record.
+type ConsoleCollector struct {
+	Logger logging.Logger
+
+	mu       sync.Mutex
+	messages []ConsoleMessage
+	attached []context.Context
+}
+
+// NewConsoleCollector creates a ConsoleCollector that forwards records
+// to the input logger.
+//
+// **Parameters:**
+//
+// logger: Logger that receives captured records, or nil to only
+// collect them.
+//
+// **Returns:**
+//
+// *ConsoleCollector: A new, empty ConsoleCollector.
+func NewConsoleCollector(logger logging.Logger) *ConsoleCollector {
+	return &ConsoleCollector{Logger: logger}
+}
+
+// HandleEvent records ev if it is a console API call or an uncaught
+// exception and ignores every other event. It can be passed directly
+// to chromedp.ListenTarget.
+//
+// **Parameters:**
+//
+// ev: The CDP event to inspect.
+func (c *ConsoleCollector) HandleEvent(ev interface{}) {
+	var msg ConsoleMessage
+
+	switch e := ev.(type) {
+	case *runtime.EventConsoleAPICalled:
+		args := make([]string, 0, len(e.Args))
+		for _, arg := range e.Args {
+			args = append(args, remoteObjectString(arg))
+		}
+		msg = ConsoleMessage{
+			Level: string(e.Type),
+			Text:  strings.Join(args, " "),
+		}
+		if e.StackTrace != nil && len(e.StackTrace.CallFrames) > 0 {
+			frame := e.StackTrace.CallFrames[0]
+			msg.URL = frame.URL
+			msg.Line = frame.LineNumber + 1
+			msg.Column = frame.ColumnNumber + 1
+		}
+		if e.Timestamp != nil {
+			msg.Timestamp = e.Timestamp.Time()
+		}
+	case *runtime.EventExceptionThrown:
+		if e.ExceptionDetails == nil {
+			return
+		}
+		details := e.ExceptionDetails
+		text := details.Text
+		if details.Exception != nil && details.Exception.Description != "" {
+			text = strings.TrimSpace(text + " " + details.Exception.Description)
+		}
+		msg = ConsoleMessage{
+			Level:     "exception",
+			Text:      text,
+			URL:       details.URL,
+			Line:      details.LineNumber + 1,
+			Column:    details.ColumnNumber + 1,
+			Exception: true,
+		}
+		if e.Timestamp != nil {
+			msg.Timestamp = e.Timestamp.Time()
+		}
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	c.messages = append(c.messages, msg)
+	c.mu.Unlock()
+
+	c.forward(msg)
+}
+
+// Messages returns every record captured so far, in arrival order.
+//
+// **Returns:**
+//
+// []ConsoleMessage: The captured console messages and exceptions.
+func (c *ConsoleCollector) Messages() []ConsoleMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ConsoleMessage(nil), c.messages...)
+}
+
+// Exceptions returns only the uncaught exceptions captured so far.
+//
+// **Returns:**
+//
+// []ConsoleMessage: The captured exceptions.
+func (c *ConsoleCollector) Exceptions() []ConsoleMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []ConsoleMessage
+	for _, msg := range c.messages {
+		if msg.Exception {
+			out = append(out, msg)
+		}
+	}
+
+	return out
+}
+
+// Reset discards every record captured so far.
+func (c *ConsoleCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = nil
+}
+
+// attach starts listening for console events on the target behind ctx.
+// A collector is attached to each context at most once.
+func (c *ConsoleCollector) attach(ctx context.Context) {
+	c.mu.Lock()
+	for _, attached := range c.attached {
+		if attached == ctx {
+			c.mu.Unlock()
+			return
+		}
+	}
+	c.attached = append(c.attached, ctx)
+	c.mu.Unlock()
+
+	chromedp.ListenTarget(ctx, c.HandleEvent)
+}
+
+func (c *ConsoleCollector) forward(msg ConsoleMessage) {
+	if c.Logger == nil {
+		return
+	}
+
+	switch msg.Level {
+	case "exception", string(runtime.APITypeError), string(runtime.APITypeAssert):
+		c.Logger.Errorf("browser console %s: %s", msg.Level, msg.Text)
+	case string(runtime.APITypeWarning):
+		c.Logger.Warnf("browser console %s: %s", msg.Level, msg.Text)
+	case string(runtime.APITypeDebug), string(runtime.APITypeTrace):
+		c.Logger.Debugf("browser console %s: %s", msg.Level, msg.Text)
+	default:
+		c.Logger.Printf("browser console %s: %s", msg.Level, msg.Text)
+	}
+}
+
+// remoteObjectString renders a console argument the way the browser's
+// devtools would display it.
+func remoteObjectString(obj *runtime.RemoteObject) string {
+	if obj == nil {
+		return ""
+	}
+
+	if len(obj.Value) > 0 {
+		var s string
+		if err := json.Unmarshal(obj.Value, &s); err == nil {
+			return s
+		}
+		return string(obj.Value)
+	}
+
+	if obj.UnserializableValue != "" {
+		return string(obj.UnserializableValue)
+	}
+
+	return obj.Description
+}
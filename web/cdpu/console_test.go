@@ -0,0 +1,129 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) record(level, msg string) {
+	l.lines = append(l.lines, level+" "+msg)
+}
+
+func (l *recordingLogger) Println(v ...interface{})          { l.record("INFO", fmt.Sprint(v...)) }
+func (l *recordingLogger) Printf(f string, v ...interface{}) { l.record("INFO", fmt.Sprintf(f, v...)) }
+func (l *recordingLogger) Error(v ...interface{})            { l.record("ERROR", fmt.Sprint(v...)) }
+func (l *recordingLogger) Errorf(f string, v ...interface{}) { l.record("ERROR", fmt.Sprintf(f, v...)) }
+func (l *recordingLogger) Debug(v ...interface{})            { l.record("DEBUG", fmt.Sprint(v...)) }
+func (l *recordingLogger) Debugf(f string, v ...interface{}) { l.record("DEBUG", fmt.Sprintf(f, v...)) }
+func (l *recordingLogger) Warn(v ...interface{})             { l.record("WARN", fmt.Sprint(v...)) }
+func (l *recordingLogger) Warnf(f string, v ...interface{})  { l.record("WARN", fmt.Sprintf(f, v...)) }
+
+func TestConsoleCollectorHandleEvent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		event   interface{}
+		want    *cdpu.ConsoleMessage
+		wantLog string
+	}{
+		{
+			name: "Console log with mixed arguments",
+			event: &runtime.EventConsoleAPICalled{
+				Type: runtime.APITypeLog,
+				Args: []*runtime.RemoteObject{
+					{Type: runtime.TypeString, Value: []byte(`"loaded"`)},
+					{Type: runtime.TypeNumber, Value: []byte(`42`)},
+					{Type: runtime.TypeObject, Description: "Object"},
+				},
+				StackTrace: &runtime.StackTrace{
+					CallFrames: []*runtime.CallFrame{{URL: "https://example.com/app.js", LineNumber: 9, ColumnNumber: 4}},
+				},
+			},
+			want: &cdpu.ConsoleMessage{
+				Level:  "log",
+				Text:   "loaded 42 Object",
+				URL:    "https://example.com/app.js",
+				Line:   10,
+				Column: 5,
+			},
+			wantLog: "INFO browser console log: loaded 42 Object",
+		},
+		{
+			name: "Console warning",
+			event: &runtime.EventConsoleAPICalled{
+				Type: runtime.APITypeWarning,
+				Args: []*runtime.RemoteObject{{Type: runtime.TypeString, Value: []byte(`"deprecated"`)}},
+			},
+			want:    &cdpu.ConsoleMessage{Level: "warning", Text: "deprecated"},
+			wantLog: "WARN browser console warning: deprecated",
+		},
+		{
+			name: "Uncaught exception",
+			event: &runtime.EventExceptionThrown{
+				ExceptionDetails: &runtime.ExceptionDetails{
+					Text:         "Uncaught",
+					URL:          "https://example.com/app.js",
+					LineNumber:   0,
+					ColumnNumber: 0,
+					Exception:    &runtime.RemoteObject{Description: "TypeError: x is undefined"},
+				},
+			},
+			want: &cdpu.ConsoleMessage{
+				Level:     "exception",
+				Text:      "Uncaught TypeError: x is undefined",
+				URL:       "https://example.com/app.js",
+				Line:      1,
+				Column:    1,
+				Exception: true,
+			},
+			wantLog: "ERROR browser console exception: Uncaught TypeError: x is undefined",
+		},
+		{
+			name:  "Unrelated event is ignored",
+			event: &network.EventRequestWillBeSent{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			collector := cdpu.NewConsoleCollector(logger)
+			collector.HandleEvent(tc.event)
+
+			msgs := collector.Messages()
+			if tc.want == nil {
+				if len(msgs) != 0 || len(logger.lines) != 0 {
+					t.Fatalf("expected event to be ignored, got %v", msgs)
+				}
+				return
+			}
+
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(msgs))
+			}
+			if msgs[0] != *tc.want {
+				t.Errorf("unexpected message: got %+v, want %+v", msgs[0], *tc.want)
+			}
+
+			if len(logger.lines) != 1 || logger.lines[0] != tc.wantLog {
+				t.Errorf("unexpected forwarded log: %v", logger.lines)
+			}
+
+			if got := len(collector.Exceptions()) == 1; got != tc.want.Exception {
+				t.Errorf("expected exception recorded = %v, got %v", tc.want.Exception, got)
+			}
+
+			collector.Reset()
+			if len(collector.Messages()) != 0 {
+				t.Error("expected Reset() to discard messages")
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package cdpu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrSessionExpired is returned by LoadCookiesFromDisk when every
+// cookie in the file has expired, so the session must be established
+// again, e.g., by logging in.
+var ErrSessionExpired = errors.New("all saved cookies have expired")
+
+// HTTPCookie converts a cookie from a Chrome session into an
+// *http.Cookie.
+//
+// **Parameters:**
+//
+// cookie (*network.Cookie): The Chrome cookie to convert.
+//
+// **Returns:**
+//
+// *http.Cookie: The converted cookie. Host-only cookies have an empty
+// Domain, and session cookies have a zero Expires.
+func HTTPCookie(cookie *network.Cookie) *http.Cookie {
+	httpCookie := &http.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HTTPOnly,
+		SameSite: httpSameSite(cookie.SameSite),
+	}
+
+	// Chrome prefixes domain cookies with a dot; cookies without it only
+	// apply to the host that set them.
+	if strings.HasPrefix(cookie.Domain, ".") {
+		httpCookie.Domain = cookie.Domain
+	}
+	if !cookie.Session && cookie.Expires > 0 {
+		sec, frac := math.Modf(cookie.Expires)
+		httpCookie.Expires = time.Unix(int64(sec), int64(frac*1e9))
+	}
+
+	return httpCookie
+}
+
+// CookieParam converts an *http.Cookie into the parameters used to set
+// it in a Chrome session.
+//
+// **Parameters:**
+//
+// cookie (*http.Cookie): The cookie to convert.
+// rawURL (string): The URL the cookie belongs to. Chrome derives the
+// domain, path, and scheme of the cookie from it unless the cookie sets
+// them.
+//
+// **Returns:**
+//
+// *network.CookieParam: The parameters for network.SetCookies.
+func CookieParam(cookie *http.Cookie, rawURL string) *network.CookieParam {
+	param := &network.CookieParam{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		URL:      rawURL,
+		Domain:   cookie.Domain,
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HTTPOnly: cookie.HttpOnly,
+		SameSite: cdpSameSite(cookie.SameSite),
+	}
+	if !cookie.Expires.IsZero() {
+		expires := cdp.TimeSinceEpoch(cookie.Expires)
+		param.Expires = &expires
+	}
+
+	return param
+}
+
+// ExportCookies copies every cookie from the Chrome session of the
+// provided Site into an http.CookieJar, so that an HTTP client using the
+// jar shares the browser's session, for example after logging in with
+// Navigate.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose browser cookies should be exported.
+// jar (http.CookieJar): The jar to add the cookies to.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver or the cookies
+// can't be retrieved.
+func ExportCookies(site web.Site, jar http.CookieJar) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+
+	var cookies []*network.Cookie
+	err := chromedp.Run(chromeDriver.GetContext(), chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = storage.GetCookies().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to get browser cookies: %v", err)
+	}
+
+	for _, cookie := range cookies {
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(cookie.Domain, "."), Path: path}
+		jar.SetCookies(u, []*http.Cookie{HTTPCookie(cookie)})
+	}
+
+	return nil
+}
+
+// NewHTTPClient returns an HTTP client whose cookie jar holds the cookies
+// of the Chrome session of the provided Site. Cookies set by the browser
+// afterwards aren't shared; call ExportCookies with the client's jar to
+// refresh them.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose browser session should be shared.
+//
+// **Returns:**
+//
+// *http.Client: The HTTP client using the browser's cookies.
+// error: An error if the cookies can't be exported.
+func NewHTTPClient(site web.Site) (*http.Client, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %v", err)
+	}
+
+	if err := ExportCookies(site, jar); err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Jar: jar}, nil
+}
+
+// ImportCookies sets cookies, such as those returned by an HTTP
+// response, in the Chrome session of the provided Site.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose browser cookies should be set.
+// rawURL (string): The URL the cookies belong to.
+// cookies ([]*http.Cookie): The cookies to set.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver, the URL is
+// invalid, or the cookies can't be set.
+func ImportCookies(site web.Site, rawURL string, cookies []*http.Cookie) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+	if _, err := url.Parse(rawURL); err != nil {
+		return fmt.Errorf("invalid URL %s: %v", rawURL, err)
+	}
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		params = append(params, CookieParam(cookie, rawURL))
+	}
+
+	if err := chromedp.Run(chromeDriver.GetContext(), network.SetCookies(params)); err != nil {
+		return fmt.Errorf("failed to set browser cookies: %v", err)
+	}
+
+	return nil
+}
+
+// ImportCookiesFromJar sets the cookies that an http.CookieJar holds for
+// each of the input URLs in the Chrome session of the provided Site, so
+// that the browser continues a session established by an HTTP client.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose browser cookies should be set.
+// jar (http.CookieJar): The jar to read the cookies from.
+// rawURLs (...string): The URLs whose cookies should be copied.
+//
+// **Returns:**
+//
+// error: An error if a URL is invalid or the cookies can't be set.
+func ImportCookiesFromJar(site web.Site, jar http.CookieJar, rawURLs ...string) error {
+	for _, rawURL := range rawURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid URL %s: %v", rawURL, err)
+		}
+		if err := ImportCookies(site, rawURL, jar.Cookies(u)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadCookiesFromDisk restores cookies written by SaveCookiesToDisk in
+// the Chrome session of the provided Site, so that a scripted session
+// can resume without authenticating again. Expired cookies are skipped.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose browser cookies should be set.
+// filePath (string): The file the cookies were saved to.
+//
+// **Returns:**
+//
+// int: The number of cookies restored.
+// error: ErrSessionExpired if every cookie has expired, or an error if
+// the driver is not of type *Driver, the file can't be read, or the
+// cookies can't be set.
+func LoadCookiesFromDisk(site web.Site, filePath string) (int, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return 0, errors.New("driver is not of type *Driver")
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cookies from %s: %v", filePath, err)
+	}
+	var cookies []*network.Cookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return 0, fmt.Errorf("failed to parse cookies from %s: %v", filePath, err)
+	}
+	if len(cookies) == 0 {
+		return 0, nil
+	}
+
+	now := float64(time.Now().Unix())
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		if !cookie.Session && cookie.Expires > 0 && cookie.Expires <= now {
+			continue
+		}
+		params = append(params, savedCookieParam(cookie))
+	}
+	if len(params) == 0 {
+		return 0, ErrSessionExpired
+	}
+
+	if err := chromedp.Run(chromeDriver.GetContext(), network.SetCookies(params)); err != nil {
+		return 0, fmt.Errorf("failed to set browser cookies: %v", err)
+	}
+
+	return len(params), nil
+}
+
+// savedCookieParam converts a cookie read from the browser back into the
+// parameters to set it. Host-only cookies are set through a URL, since
+// setting their domain would make them apply to subdomains too.
+func savedCookieParam(cookie *network.Cookie) *network.CookieParam {
+	param := &network.CookieParam{
+		Name:         cookie.Name,
+		Value:        cookie.Value,
+		Path:         cookie.Path,
+		Secure:       cookie.Secure,
+		HTTPOnly:     cookie.HTTPOnly,
+		SameSite:     cookie.SameSite,
+		Priority:     cookie.Priority,
+		SourceScheme: cookie.SourceScheme,
+		SourcePort:   cookie.SourcePort,
+		PartitionKey: cookie.PartitionKey,
+	}
+	if strings.HasPrefix(cookie.Domain, ".") {
+		param.Domain = cookie.Domain
+	} else {
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		param.URL = (&url.URL{Scheme: scheme, Host: cookie.Domain, Path: cookie.Path}).String()
+	}
+	if !cookie.Session && cookie.Expires > 0 {
+		sec, frac := math.Modf(cookie.Expires)
+		expires := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*1e9)))
+		param.Expires = &expires
+	}
+
+	return param
+}
+
+func httpSameSite(sameSite network.CookieSameSite) http.SameSite {
+	switch sameSite {
+	case network.CookieSameSiteStrict:
+		return http.SameSiteStrictMode
+	case network.CookieSameSiteLax:
+		return http.SameSiteLaxMode
+	case network.CookieSameSiteNone:
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
+
+func cdpSameSite(sameSite http.SameSite) network.CookieSameSite {
+	switch sameSite {
+	case http.SameSiteStrictMode:
+		return network.CookieSameSiteStrict
+	case http.SameSiteLaxMode:
+		return network.CookieSameSiteLax
+	case http.SameSiteNoneMode:
+		return network.CookieSameSiteNone
+	default:
+		return ""
+	}
+}
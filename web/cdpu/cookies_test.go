@@ -0,0 +1,152 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestHTTPCookie(t *testing.T) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		cookie *network.Cookie
+		want   http.Cookie
+	}{
+		{
+			name: "Host-only session cookie",
+			cookie: &network.Cookie{
+				Name: "sid", Value: "abc", Domain: "example.com", Path: "/",
+				HTTPOnly: true, Secure: true, Session: true, SameSite: network.CookieSameSiteLax,
+			},
+			want: http.Cookie{Name: "sid", Value: "abc", Path: "/", HttpOnly: true, Secure: true, SameSite: http.SameSiteLaxMode},
+		},
+		{
+			name: "Domain cookie with expiry",
+			cookie: &network.Cookie{
+				Name: "pref", Value: "dark", Domain: ".example.com", Path: "/app",
+				Expires: float64(expires.Unix()), SameSite: network.CookieSameSiteStrict,
+			},
+			want: http.Cookie{Name: "pref", Value: "dark", Domain: ".example.com", Path: "/app", Expires: expires, SameSite: http.SameSiteStrictMode},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := cdpu.HTTPCookie(tc.cookie)
+			if got.String() != tc.want.String() || !got.Expires.Equal(tc.want.Expires) {
+				t.Errorf("HTTPCookie() = %v, want %v", got, &tc.want)
+			}
+
+			param := cdpu.CookieParam(got, "https://example.com/")
+			if param.Name != tc.cookie.Name || param.Value != tc.cookie.Value || param.Path != tc.cookie.Path ||
+				param.Secure != tc.cookie.Secure || param.HTTPOnly != tc.cookie.HTTPOnly || param.SameSite != tc.cookie.SameSite {
+				t.Errorf("CookieParam() = %+v does not round-trip %+v", param, tc.cookie)
+			}
+			if tc.cookie.Session != (param.Expires == nil) {
+				t.Errorf("CookieParam() expires = %v, session = %v", param.Expires, tc.cookie.Session)
+			}
+		})
+	}
+}
+
+func TestCookieSharing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "browser-login", Path: "/"})
+		fmt.Fprint(w, `<html><body>logged in</body></html>`)
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "api", Value: "from-client", Path: "/"})
+		fmt.Fprint(w, cookie.Value)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	browser, err := cdpu.Init(true, true)
+	if err != nil {
+		t.Fatalf("failed to initialize a chrome browser: %v", err)
+	}
+	defer web.CancelAll(browser.Cancels...)
+
+	site := web.Site{
+		LoginURL: server.URL + "/login",
+		Session:  web.Session{Driver: browser.Driver},
+	}
+	actions := []cdpu.InputAction{
+		{Description: "Log in", Action: chromedp.Navigate(site.LoginURL)},
+	}
+	if err := cdpu.Navigate(site, actions, 0); err != nil {
+		t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+	}
+
+	client, err := cdpu.NewHTTPClient(site)
+	if err != nil {
+		t.Fatalf("NewHTTPClient() failed: %v", err)
+	}
+	resp, err := client.Get(server.URL + "/api")
+	if err != nil {
+		t.Fatalf("failed to call API: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("API returned %d, want the browser session to be shared", resp.StatusCode)
+	}
+
+	if err := cdpu.ImportCookiesFromJar(site, client.Jar, server.URL+"/"); err != nil {
+		t.Fatalf("ImportCookiesFromJar() failed: %v", err)
+	}
+	var cookies string
+	if err := chromedp.Run(browser.Driver.(*cdpu.Driver).GetContext(), chromedp.Evaluate(`document.cookie`, &cookies)); err != nil {
+		t.Fatalf("failed to read browser cookies: %v", err)
+	}
+	if want := "api=from-client"; !containsCookie(cookies, want) {
+		t.Errorf("browser cookies = %q, want %q", cookies, want)
+	}
+}
+
+func TestCookiesInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.NewHTTPClient(site); err == nil {
+		t.Error("NewHTTPClient() expected an error for an invalid driver")
+	}
+	if err := cdpu.ImportCookies(site, "https://example.com", []*http.Cookie{{Name: "a", Value: "b"}}); err == nil {
+		t.Error("ImportCookies() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.LoadCookiesFromDisk(site, "cookies.json"); err == nil {
+		t.Error("LoadCookiesFromDisk() expected an error for an invalid driver")
+	}
+}
+
+func containsCookie(cookies, want string) bool {
+	for _, cookie := range splitCookies(cookies) {
+		if cookie == want {
+			return true
+		}
+	}
+	return false
+}
+
+func splitCookies(cookies string) []string {
+	header := http.Header{"Cookie": {cookies}}
+	req := http.Request{Header: header}
+	var pairs []string
+	for _, c := range req.Cookies() {
+		pairs = append(pairs, c.Name+"="+c.Value)
+	}
+	return pairs
+}
@@ -0,0 +1,304 @@
+package cdpu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// Names of the files written by CaptureDiagnostics.
+const (
+	DiagnosticsScreenshotFile = "screenshot.png"
+	DiagnosticsPageSourceFile = "page.html"
+	DiagnosticsCookiesFile    = "cookies.json"
+	DiagnosticsConsoleFile    = "console.json"
+	DiagnosticsURLFile        = "url.txt"
+	DiagnosticsManifestFile   = "manifest.json"
+)
+
+// DiagnosticsOptions holds optional behavior for CaptureDiagnostics.
+//
+// **Attributes:**
+//
+// console: Collector whose records are saved, if any.
+// label: Prefix of the name of the bundle's directory.
+// attempts: How many times each artifact is captured before giving up.
+// retryDelay: How long to wait between attempts.
+// timeout: How long each attempt may take.
+type DiagnosticsOptions struct {
+	console    *ConsoleCollector
+	label      string
+	attempts   int
+	retryDelay time.Duration
+	timeout    time.Duration
+}
+
+// DiagnosticsOption is a type for functions that modify the diagnostics
+// options. These functions take a pointer to a DiagnosticsOptions struct
+// and modify it in place.
+type DiagnosticsOption func(*DiagnosticsOptions)
+
+// WithDiagnosticsConsole returns a DiagnosticsOption that saves the
+// records of the input collector, e.g., the one passed to Navigate with
+// WithConsoleCapture.
+//
+// **Parameters:**
+//
+// collector: The ConsoleCollector whose records are saved.
+//
+// **Returns:**
+//
+// DiagnosticsOption: A function that sets the console collector of a
+// DiagnosticsOptions struct.
+func WithDiagnosticsConsole(collector *ConsoleCollector) DiagnosticsOption {
+	return func(opts *DiagnosticsOptions) {
+		opts.console = collector
+	}
+}
+
+// WithDiagnosticsLabel returns a DiagnosticsOption that prefixes the
+// name of the bundle's directory with label, such as the name of the
+// failed flow and its attempt number (e.g., "login-attempt-2"), so that
+// the bundles of retried flows are told apart.
+//
+// **Parameters:**
+//
+// label: The prefix of the directory name.
+//
+// **Returns:**
+//
+// DiagnosticsOption: A function that sets the label of a
+// DiagnosticsOptions struct.
+func WithDiagnosticsLabel(label string) DiagnosticsOption {
+	return func(opts *DiagnosticsOptions) {
+		opts.label = label
+	}
+}
+
+// WithDiagnosticsRetries returns a DiagnosticsOption that controls how
+// often each artifact is captured before giving up. Captures commonly
+// fail transiently while the page is navigating, which is often when a
+// flow fails. Values of zero or less keep the defaults of 3 attempts,
+// 500ms apart, of up to 10s each.
+//
+// **Parameters:**
+//
+// attempts: How many times each artifact is captured.
+// delay: How long to wait between attempts.
+// timeout: How long each attempt may take, so that a hung page doesn't
+// block the failure handler.
+//
+// **Returns:**
+//
+// DiagnosticsOption: A function that sets the retries of a
+// DiagnosticsOptions struct.
+func WithDiagnosticsRetries(attempts int, delay, timeout time.Duration) DiagnosticsOption {
+	return func(opts *DiagnosticsOptions) {
+		if attempts > 0 {
+			opts.attempts = attempts
+		}
+		if delay > 0 {
+			opts.retryDelay = delay
+		}
+		if timeout > 0 {
+			opts.timeout = timeout
+		}
+	}
+}
+
+// DiagnosticsManifest describes a bundle written by CaptureDiagnostics.
+// It's saved in the bundle as manifest.json.
+//
+// **Attributes:**
+//
+// Dir: The directory of the bundle.
+// CapturedAt: When the capture started.
+// URL: The URL of the page, if it could be retrieved.
+// Files: The names of the files in the bundle, keyed by artifact (e.g.,
+// "screenshot"), excluding the manifest.
+// Errors: Why artifacts couldn't be captured, keyed by artifact. The
+// console is listed as "no collector attached" if no collector was
+// passed with WithDiagnosticsConsole.
+type DiagnosticsManifest struct {
+	Dir        string            `json:"dir"`
+	CapturedAt time.Time         `json:"captured_at"`
+	URL        string            `json:"url,omitempty"`
+	Files      map[string]string `json:"files"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+// diagnosticsArtifact captures the content of a file of a bundle.
+type diagnosticsArtifact struct {
+	name    string
+	file    string
+	capture func(ctx context.Context) ([]byte, error)
+}
+
+// CaptureDiagnostics collects a screenshot of the whole page, the page
+// source, the cookies, the console records, and the current URL of the
+// provided Site's session into a new timestamped directory under dir,
+// for use in failure handlers. Each artifact is retried independently,
+// and artifacts that still can't be captured are recorded in the
+// manifest rather than aborting the capture. Without a console
+// collector, the missing console records are noted in the manifest, but
+// don't cause an error. The bundle is written to a temporary directory
+// and renamed into place once complete, so a directory named after a
+// capture is never partial. The cookies are saved unencrypted, but only
+// the current user can read the bundle.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to capture.
+// dir (string): The directory the bundle's directory is created in.
+// options (...DiagnosticsOption): Optional behavior such as console
+// capture and retries.
+//
+// **Returns:**
+//
+// *DiagnosticsManifest: The manifest of the bundle, or nil if no bundle
+// was written.
+// error: An error if the driver is not of type *Driver, the bundle can't
+// be written, or any artifact couldn't be captured.
+func CaptureDiagnostics(site web.Site, dir string, options ...DiagnosticsOption) (*DiagnosticsManifest, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return nil, errors.New("driver is not of type *Driver")
+	}
+
+	opts := &DiagnosticsOptions{attempts: 3, retryDelay: 500 * time.Millisecond, timeout: 10 * time.Second}
+	for _, option := range options {
+		option(opts)
+	}
+
+	manifest := &DiagnosticsManifest{
+		CapturedAt: time.Now().UTC(),
+		Files:      make(map[string]string),
+		Errors:     make(map[string]string),
+	}
+	name := manifest.CapturedAt.Format("20060102T150405.000000000Z")
+	if opts.label != "" {
+		name = opts.label + "-" + name
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+	tmpDir, err := os.MkdirTemp(dir, "."+name+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create diagnostics directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var failed []string
+	for _, artifact := range diagnosticsArtifacts(manifest, opts) {
+		data, err := captureWithRetry(chromeDriver.GetContext(), artifact.capture, opts)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(tmpDir, artifact.file), data, 0600)
+		}
+		if err != nil {
+			manifest.Errors[artifact.name] = err.Error()
+			failed = append(failed, fmt.Sprintf("%s: %v", artifact.name, err))
+			continue
+		}
+		manifest.Files[artifact.name] = artifact.file
+	}
+	if opts.console == nil {
+		manifest.Errors["console"] = "no collector attached"
+	}
+
+	manifest.Dir = filepath.Join(dir, name)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode diagnostics manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, DiagnosticsManifestFile), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write diagnostics manifest: %v", err)
+	}
+	if err := os.Rename(tmpDir, manifest.Dir); err != nil {
+		return nil, fmt.Errorf("failed to move diagnostics to %s: %v", manifest.Dir, err)
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return manifest, fmt.Errorf("failed to capture %d diagnostics: %v", len(failed), failed)
+	}
+
+	return manifest, nil
+}
+
+// diagnosticsArtifacts returns the artifacts of a bundle. The URL is
+// also recorded in the manifest.
+func diagnosticsArtifacts(manifest *DiagnosticsManifest, opts *DiagnosticsOptions) []diagnosticsArtifact {
+	artifacts := []diagnosticsArtifact{
+		{name: "url", file: DiagnosticsURLFile, capture: func(ctx context.Context) ([]byte, error) {
+			var currentURL string
+			if err := chromedp.Run(ctx, chromedp.Location(&currentURL)); err != nil {
+				return nil, err
+			}
+			manifest.URL = currentURL
+			return []byte(currentURL + "\n"), nil
+		}},
+		{name: "screenshot", file: DiagnosticsScreenshotFile, capture: func(ctx context.Context) ([]byte, error) {
+			var screenshot []byte
+			err := chromedp.Run(ctx, chromedp.FullScreenshot(&screenshot, 100))
+			return screenshot, err
+		}},
+		{name: "page_source", file: DiagnosticsPageSourceFile, capture: func(ctx context.Context) ([]byte, error) {
+			var pageSource string
+			err := chromedp.Run(ctx, chromedp.OuterHTML("html", &pageSource, chromedp.ByQuery))
+			return []byte(pageSource), err
+		}},
+		{name: "cookies", file: DiagnosticsCookiesFile, capture: func(ctx context.Context) ([]byte, error) {
+			var cookies []*network.Cookie
+			err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+				var err error
+				cookies, err = network.GetCookies().Do(ctx)
+				return err
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return json.MarshalIndent(cookies, "", "  ")
+		}},
+	}
+
+	if opts.console != nil {
+		artifacts = append(artifacts, diagnosticsArtifact{
+			name: "console", file: DiagnosticsConsoleFile, capture: func(context.Context) ([]byte, error) {
+				return json.MarshalIndent(opts.console.Messages(), "", "  ")
+			},
+		})
+	}
+
+	return artifacts
+}
+
+// captureWithRetry captures an artifact, retrying failed attempts. It
+// gives up early if the driver's context is done.
+func captureWithRetry(ctx context.Context, capture func(ctx context.Context) ([]byte, error), opts *DiagnosticsOptions) ([]byte, error) {
+	var err error
+	for attempt := 1; attempt <= opts.attempts; attempt++ {
+		var data []byte
+		attemptCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+		data, err = capture(attemptCtx)
+		cancel()
+		if err == nil {
+			return data, nil
+		}
+		if ctx.Err() != nil || attempt == opts.attempts {
+			break
+		}
+		time.Sleep(opts.retryDelay)
+	}
+
+	return nil, err
+}
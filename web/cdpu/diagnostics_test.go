@@ -0,0 +1,117 @@
+package cdpu_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestCaptureDiagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+		fmt.Fprint(w, `<html><body><h1>Checkout failed</h1>
+			<script>console.error("payment declined")</script></body></html>`)
+	}))
+	defer server.Close()
+
+	browser, err := cdpu.Init(true, true)
+	if err != nil {
+		t.Fatalf("failed to initialize a chrome browser: %v", err)
+	}
+	defer web.CancelAll(browser.Cancels...)
+
+	site := web.Site{
+		LoginURL: server.URL + "/checkout",
+		Session:  web.Session{Driver: browser.Driver},
+	}
+	collector := cdpu.NewConsoleCollector(nil)
+	load := []cdpu.InputAction{{
+		Description: "Load the checkout page",
+		Action:      chromedp.Navigate(site.LoginURL),
+	}}
+	if err := cdpu.Navigate(site, load, 0, cdpu.WithConsoleCapture(collector)); err != nil {
+		t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+	}
+
+	dir := t.TempDir()
+	manifest, err := cdpu.CaptureDiagnostics(site, dir,
+		cdpu.WithDiagnosticsConsole(collector), cdpu.WithDiagnosticsLabel("checkout-attempt-1"))
+	if err != nil {
+		t.Fatalf("CaptureDiagnostics() failed: %v", err)
+	}
+
+	if filepath.Dir(manifest.Dir) != dir || !strings.HasPrefix(filepath.Base(manifest.Dir), "checkout-attempt-1-") {
+		t.Errorf("manifest.Dir = %s, want a labeled directory in %s", manifest.Dir, dir)
+	}
+	if manifest.URL != site.LoginURL {
+		t.Errorf("manifest.URL = %s, want %s", manifest.URL, site.LoginURL)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected only the bundle in %s, got %v (%v)", dir, entries, err)
+	}
+
+	wantContents := map[string]string{
+		"url":         site.LoginURL,
+		"page_source": "Checkout failed",
+		"cookies":     "abc123",
+		"console":     "payment declined",
+		"screenshot":  "PNG",
+	}
+	for artifact, want := range wantContents {
+		file, ok := manifest.Files[artifact]
+		if !ok {
+			t.Errorf("manifest is missing %s", artifact)
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(manifest.Dir, file))
+		if err != nil {
+			t.Errorf("failed to read %s: %v", file, err)
+			continue
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s doesn't contain %q", file, want)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(manifest.Dir, cdpu.DiagnosticsManifestFile))
+	if err != nil {
+		t.Fatalf("failed to read the manifest: %v", err)
+	}
+	var saved cdpu.DiagnosticsManifest
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("failed to decode the manifest: %v", err)
+	}
+	if saved.Dir != manifest.Dir || len(saved.Files) != len(manifest.Files) || len(saved.Errors) != 0 {
+		t.Errorf("saved manifest = %+v, want %+v", saved, manifest)
+	}
+
+	manifest, err = cdpu.CaptureDiagnostics(site, dir)
+	if err != nil {
+		t.Fatalf("CaptureDiagnostics() without a collector failed: %v", err)
+	}
+	if _, ok := manifest.Files["console"]; ok || manifest.Errors["console"] != "no collector attached" {
+		t.Errorf("manifest = %+v, want the console noted as missing a collector", manifest)
+	}
+}
+
+func TestCaptureDiagnosticsInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+	dir := t.TempDir()
+
+	if _, err := cdpu.CaptureDiagnostics(site, dir); err == nil {
+		t.Error("CaptureDiagnostics() expected an error for an invalid driver")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no bundle for an invalid driver, got %v", entries)
+	}
+}
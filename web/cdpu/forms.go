@@ -0,0 +1,142 @@
+package cdpu
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// FillFormActions returns the actions that fill in form fields. Each
+// field is waited for, cleared, and typed into with key events, so
+// frameworks that listen for input events see the value. Fields are
+// filled in the order of their selectors.
+//
+// **Parameters:**
+//
+// values (map[string]string): The values to enter, keyed by the selector
+// of their field.
+//
+// **Returns:**
+//
+// []InputAction: The actions, for use with Navigate.
+func FillFormActions(values map[string]string) []InputAction {
+	selectors := make([]string, 0, len(values))
+	for selector := range values {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	actions := make([]InputAction, 0, len(selectors))
+	for _, selector := range selectors {
+		actions = append(actions, fillAction("Fill in "+selector, selector, values[selector]))
+	}
+
+	return actions
+}
+
+// FillForm fills in form fields on the page currently loaded in the
+// provided Site's session. See FillFormActions.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose form should be filled in.
+// values (map[string]string): The values to enter, keyed by the selector
+// of their field.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver or a field can't
+// be filled in.
+func FillForm(site web.Site, values map[string]string) error {
+	return Navigate(site, FillFormActions(values), 0)
+}
+
+// LoginActions returns the actions that log in with the Credential of
+// the provided Site's session: they load the site's LoginURL, if set,
+// enter the user and password, and click the submit button. Further
+// actions, such as entering TwoFacCode, can be appended before running
+// them with Navigate. The password isn't included in the action
+// descriptions, so it isn't logged in debug mode.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to log in to.
+// usernameSelector (string): The selector of the username field.
+// passwordSelector (string): The selector of the password field.
+// submitSelector (string): The selector of the submit button.
+//
+// **Returns:**
+//
+// []InputAction: The actions, for use with Navigate.
+// error: An error if a selector or the credential's user is empty.
+func LoginActions(site web.Site, usernameSelector, passwordSelector, submitSelector string) ([]InputAction, error) {
+	if usernameSelector == "" || passwordSelector == "" || submitSelector == "" {
+		return nil, errors.New("username, password, and submit selectors are required")
+	}
+	credential := site.Session.Credential
+	if credential.User == "" {
+		return nil, errors.New("site has no credential to log in with")
+	}
+
+	var actions []InputAction
+	if site.LoginURL != "" {
+		actions = append(actions, InputAction{
+			Description: "Load the login page",
+			Action:      chromedp.Navigate(site.LoginURL),
+		})
+	}
+	actions = append(actions,
+		fillAction("Enter the username", usernameSelector, credential.User),
+		fillAction("Enter the password", passwordSelector, credential.Password),
+		InputAction{
+			Description: "Submit the login form",
+			Selector:    submitSelector,
+			Action: chromedp.Tasks{
+				chromedp.WaitVisible(submitSelector),
+				chromedp.Click(submitSelector),
+			},
+		},
+	)
+
+	return actions, nil
+}
+
+// Login logs in to a site with the Credential of its session. See
+// LoginActions. Use WaitForNavigation afterwards to wait for the
+// redirect that follows a successful login.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to log in to.
+// usernameSelector (string): The selector of the username field.
+// passwordSelector (string): The selector of the password field.
+// submitSelector (string): The selector of the submit button.
+//
+// **Returns:**
+//
+// error: An error if the selectors or credential are missing, the driver
+// is not of type *Driver, or the login form can't be submitted.
+func Login(site web.Site, usernameSelector, passwordSelector, submitSelector string) error {
+	actions, err := LoginActions(site, usernameSelector, passwordSelector, submitSelector)
+	if err != nil {
+		return err
+	}
+
+	return Navigate(site, actions, 0)
+}
+
+// fillAction returns an action that clears a field and types value into
+// it.
+func fillAction(description, selector, value string) InputAction {
+	return InputAction{
+		Description: description,
+		Selector:    selector,
+		Action: chromedp.Tasks{
+			chromedp.WaitVisible(selector),
+			chromedp.Clear(selector),
+			chromedp.SendKeys(selector, value),
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestLoginActions(t *testing.T) {
+	site := web.Site{
+		LoginURL: "https://example.com/login",
+		Session:  web.Session{Credential: web.Credential{User: "alice", Password: "s3cret"}},
+	}
+
+	testCases := []struct {
+		name     string
+		site     web.Site
+		submit   string
+		wantDesc []string
+		wantErr  bool
+	}{
+		{
+			name:     "With login URL",
+			site:     site,
+			submit:   "#submit",
+			wantDesc: []string{"Load the login page", "Enter the username", "Enter the password", "Submit the login form"},
+		},
+		{
+			name:     "Current page",
+			site:     web.Site{Session: site.Session},
+			submit:   "#submit",
+			wantDesc: []string{"Enter the username", "Enter the password", "Submit the login form"},
+		},
+		{
+			name:    "Missing selector",
+			site:    site,
+			wantErr: true,
+		},
+		{
+			name:    "Missing credential",
+			site:    web.Site{LoginURL: site.LoginURL},
+			submit:  "#submit",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actions, err := cdpu.LoginActions(tc.site, "#user", "#pass", tc.submit)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LoginActions() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(actions) != len(tc.wantDesc) {
+				t.Fatalf("LoginActions() returned %d actions, want %d", len(actions), len(tc.wantDesc))
+			}
+			for i, action := range actions {
+				if action.Description != tc.wantDesc[i] {
+					t.Errorf("action %d description = %q, want %q", i, action.Description, tc.wantDesc[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoginAndFillForm(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><form method="post" action="/welcome">
+			<input id="user" name="user" type="text" value="prefilled">
+			<input id="pass" name="pass" type="password">
+			<button id="submit" type="submit">Log in</button></form></body></html>`)
+	})
+	mux.HandleFunc("/welcome", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("user") != "alice" || r.PostForm.Get("pass") != "s3cret" {
+			http.Redirect(w, r, "/login?failed=1", http.StatusSeeOther)
+			return
+		}
+		fmt.Fprint(w, `<html><body><form><input id="city" type="text"><input id="zip" type="text"></form></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	browser, err := cdpu.Init(true, true)
+	if err != nil {
+		t.Fatalf("failed to initialize a chrome browser: %v", err)
+	}
+	defer web.CancelAll(browser.Cancels...)
+
+	site := web.Site{
+		LoginURL: server.URL + "/login",
+		Session: web.Session{
+			Driver:     browser.Driver,
+			Credential: web.Credential{User: "alice", Password: "s3cret"},
+		},
+	}
+
+	if err := cdpu.Login(site, "#user", "#pass", "#submit"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, err := cdpu.WaitForNavigation(site, `/welcome$`, 10*time.Second); err != nil {
+		t.Fatalf("login didn't reach the welcome page: %v", err)
+	}
+
+	values := map[string]string{"#city": "Springfield", "#zip": "12345"}
+	if err := cdpu.FillForm(site, values); err != nil {
+		t.Fatalf("FillForm() error = %v", err)
+	}
+	source, err := cdpu.GetPageSource(site)
+	if err != nil {
+		t.Fatalf("GetPageSource() error = %v", err)
+	}
+	if source == "" {
+		t.Error("expected a page source")
+	}
+}
@@ -0,0 +1,169 @@
+package cdpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// Frame describes a frame of the page loaded in a Site's session.
+//
+// **Attributes:**
+//
+// ID: The unique identifier of the frame.
+// ParentID: The identifier of the parent frame. Empty for the main frame.
+// Name: The name of the frame as specified by its iframe's name attribute.
+// URL: The URL of the document loaded in the frame.
+// OutOfProcess: Whether the frame is rendered in a separate process
+// because it's cross-origin, as is common for payment and authentication
+// widgets.
+type Frame struct {
+	ID           string
+	ParentID     string
+	Name         string
+	URL          string
+	OutOfProcess bool
+}
+
+// FrameActions builds the actions to run inside a frame. Queries of the
+// returned actions must include the inFrame option (e.g.,
+// chromedp.Click("#pay", chromedp.ByQuery, inFrame)) so that they're
+// resolved against the frame's document rather than the top document.
+type FrameActions func(inFrame chromedp.QueryOption) []InputAction
+
+// ListFrames returns the frames of the page currently loaded in the
+// provided Site's session, starting with the main frame.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose frames should be listed.
+//
+// **Returns:**
+//
+// []Frame: The frames of the page in depth-first order.
+// error: An error if the driver is not of type *Driver or the frames
+// can't be retrieved.
+func ListFrames(site web.Site) ([]Frame, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return nil, errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	var tree *page.FrameTree
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		tree, err = page.GetFrameTree().Do(ctx)
+		return err
+	})); err != nil {
+		return nil, fmt.Errorf("failed to get frame tree: %v", err)
+	}
+
+	oopifs, err := outOfProcessFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []Frame
+	var walk func(t *page.FrameTree)
+	walk = func(t *page.FrameTree) {
+		if t == nil || t.Frame == nil {
+			return
+		}
+		frames = append(frames, Frame{
+			ID:           string(t.Frame.ID),
+			ParentID:     string(t.Frame.ParentID),
+			Name:         t.Frame.Name,
+			URL:          t.Frame.URL + t.Frame.URLFragment,
+			OutOfProcess: oopifs[target.ID(t.Frame.ID)],
+		})
+		for _, child := range t.ChildFrames {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	return frames, nil
+}
+
+// WithinFrame builds actions that target elements inside the iframe
+// matching frameSelector, for use with Navigate. Same-origin frames are
+// queried through the iframe's content document, and cross-origin frames
+// through a session attached to the frame's own renderer, which stays
+// attached until the driver's context is cancelled. Only iframes in the
+// top document are matched; call WithinFrame from inside the returned
+// actions' frame to reach nested frames.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page contains the iframe.
+// frameSelector (string): The CSS selector of the iframe element.
+// actions (FrameActions): Builds the actions to run inside the frame.
+//
+// **Returns:**
+//
+// []InputAction: The actions, scoped to the frame.
+// error: An error if the driver is not of type *Driver or
+// frameSelector doesn't match an iframe.
+func WithinFrame(site web.Site, frameSelector string, actions FrameActions) ([]InputAction, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return nil, errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	var nodes []*cdp.Node
+	if err := chromedp.Run(ctx, chromedp.Nodes(frameSelector, &nodes, chromedp.ByQuery)); err != nil {
+		return nil, fmt.Errorf("failed to find frame %q: %v", frameSelector, err)
+	}
+	frameNode := nodes[0]
+	if frameNode.FrameID == "" {
+		return nil, fmt.Errorf("element matching %q is not a frame", frameSelector)
+	}
+
+	oopifs, err := outOfProcessFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetID := target.ID(frameNode.FrameID)
+	if !oopifs[targetID] {
+		return actions(chromedp.FromNode(frameNode)), nil
+	}
+
+	// Cancelling a context attached to an existing target closes it, so
+	// the frame's context is left to be cancelled with its parent.
+	frameCtx, _ := chromedp.NewContext(ctx, chromedp.WithTargetID(targetID))
+	scoped := actions(func(*chromedp.Selector) {})
+	for i := range scoped {
+		if scoped[i].Context == nil {
+			scoped[i].Context = frameCtx
+		}
+	}
+
+	return scoped, nil
+}
+
+// outOfProcessFrames returns the IDs of the frames rendered by their own
+// iframe targets. The ID of such a target is the ID of its frame.
+func outOfProcessFrames(ctx context.Context) (map[target.ID]bool, error) {
+	targets, err := chromedp.Targets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list targets: %v", err)
+	}
+
+	oopifs := make(map[target.ID]bool)
+	for _, info := range targets {
+		if info.Type == "iframe" {
+			oopifs[info.TargetID] = true
+		}
+	}
+
+	return oopifs, nil
+}
@@ -0,0 +1,116 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestWithinFrame(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/frame", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><input id="card" type="text"></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Serving the frame from localhost while the page is loaded from
+	// 127.0.0.1 makes it cross-origin.
+	crossOriginURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/frame"
+	mux.HandleFunc("/checkout", func(w http.ResponseWriter, r *http.Request) {
+		src := "/frame"
+		if r.URL.Query().Get("cross") != "" {
+			src = crossOriginURL
+		}
+		fmt.Fprintf(w, `<html><body><input id="card" type="text" value="top">
+			<iframe id="payment" name="payment" src="%s"></iframe></body></html>`, src)
+	})
+
+	testCases := []struct {
+		name string
+		path string
+	}{
+		{name: "Same-origin frame", path: "/checkout"},
+		{name: "Cross-origin frame", path: "/checkout?cross=1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, err := cdpu.Init(true, true)
+			if err != nil {
+				t.Fatalf("failed to initialize a chrome browser: %v", err)
+			}
+			defer web.CancelAll(browser.Cancels...)
+
+			site := web.Site{
+				LoginURL: server.URL + tc.path,
+				Session:  web.Session{Driver: browser.Driver},
+			}
+
+			load := []cdpu.InputAction{{
+				Description: "Load the checkout page",
+				Action: chromedp.Tasks{
+					chromedp.Navigate(site.LoginURL),
+					chromedp.WaitReady("#payment", chromedp.ByQuery),
+				},
+			}}
+			if err := cdpu.Navigate(site, load, 0); err != nil {
+				t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+			}
+
+			frames, err := cdpu.ListFrames(site)
+			if err != nil {
+				t.Fatalf("ListFrames() failed: %v", err)
+			}
+			if len(frames) != 2 || frames[1].Name != "payment" || frames[1].ParentID != frames[0].ID {
+				t.Fatalf("ListFrames() = %+v, want the main frame and the payment frame", frames)
+			}
+
+			var frameValue, topValue string
+			actions, err := cdpu.WithinFrame(site, "#payment", func(inFrame chromedp.QueryOption) []cdpu.InputAction {
+				return []cdpu.InputAction{
+					{
+						Description: "Enter the card number",
+						Action:      chromedp.SendKeys("#card", "4242", chromedp.ByQuery, inFrame),
+					},
+					{
+						Description: "Read the card number back",
+						Action:      chromedp.Value("#card", &frameValue, chromedp.ByQuery, inFrame),
+					},
+				}
+			})
+			if err != nil {
+				t.Fatalf("WithinFrame() failed: %v", err)
+			}
+			if err := cdpu.Navigate(site, actions, 0); err != nil {
+				t.Fatalf("failed to run frame actions: %v", err)
+			}
+
+			readTop := []cdpu.InputAction{{Action: chromedp.Value("#card", &topValue, chromedp.ByQuery)}}
+			if err := cdpu.Navigate(site, readTop, 0); err != nil {
+				t.Fatalf("failed to read the top document: %v", err)
+			}
+
+			if frameValue != "4242" || topValue != "top" {
+				t.Errorf("frame value = %q, top value = %q; want %q and %q", frameValue, topValue, "4242", "top")
+			}
+		})
+	}
+}
+
+func TestFramesInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.ListFrames(site); err == nil {
+		t.Error("ListFrames() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.WithinFrame(site, "iframe", func(chromedp.QueryOption) []cdpu.InputAction { return nil }); err == nil {
+		t.Error("WithinFrame() expected an error for an invalid driver")
+	}
+}
@@ -0,0 +1,111 @@
+package cdpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// CurrentURL returns the URL of the page currently loaded in the
+// provided Site's session.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose current URL should be retrieved.
+//
+// **Returns:**
+//
+// string: The URL of the current page.
+// error: An error if the driver is not of type *Driver or the URL can't
+// be retrieved.
+func CurrentURL(site web.Site) (string, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return "", errors.New("driver is not of type *Driver")
+	}
+
+	var currentURL string
+	if err := chromedp.Run(chromeDriver.GetContext(), chromedp.Location(&currentURL)); err != nil {
+		return "", fmt.Errorf("failed to get current URL: %v", err)
+	}
+
+	return currentURL, nil
+}
+
+// WaitForNavigation waits until the main frame of the provided Site's
+// session navigates to a URL matching expectedURLPattern, such as the
+// redirect that follows a successful login. Both full page loads and
+// same-document navigations (e.g., history.pushState in single-page
+// applications) are considered. If the current URL already matches,
+// WaitForNavigation returns immediately.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose navigation should be awaited.
+// expectedURLPattern (string): A regular expression the destination URL
+// must match.
+// timeout (time.Duration): The maximum time to wait for the navigation.
+//
+// **Returns:**
+//
+// string: The URL that matched expectedURLPattern.
+// error: An error if the pattern is invalid, the driver is not of type
+// *Driver, or no matching navigation happens before the timeout.
+func WaitForNavigation(site web.Site, expectedURLPattern string, timeout time.Duration) (string, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return "", errors.New("driver is not of type *Driver")
+	}
+
+	pattern, err := regexp.Compile(expectedURLPattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL pattern %q: %v", expectedURLPattern, err)
+	}
+
+	ctx, cancel := context.WithTimeout(chromeDriver.GetContext(), timeout)
+	defer cancel()
+
+	// Start listening before checking the current URL so that a
+	// navigation completing in between is not missed.
+	matched := make(chan string, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		var navigatedURL string
+		switch msg := ev.(type) {
+		case *page.EventFrameNavigated:
+			if msg.Frame.ParentID != "" {
+				return
+			}
+			navigatedURL = msg.Frame.URL + msg.Frame.URLFragment
+		case *page.EventNavigatedWithinDocument:
+			navigatedURL = msg.URL
+		default:
+			return
+		}
+
+		if pattern.MatchString(navigatedURL) {
+			select {
+			case matched <- navigatedURL:
+			default:
+			}
+		}
+	})
+
+	if currentURL, err := CurrentURL(site); err == nil && pattern.MatchString(currentURL) {
+		return currentURL, nil
+	}
+
+	select {
+	case navigatedURL := <-matched:
+		return navigatedURL, nil
+	case <-ctx.Done():
+		lastURL, _ := CurrentURL(site)
+		return "", fmt.Errorf("timed out after %s waiting for navigation to %q (current URL: %q)",
+			timeout, expectedURLPattern, lastURL)
+	}
+}
@@ -0,0 +1,113 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestWaitForNavigation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a post-auth redirect that happens after the page loads.
+		fmt.Fprint(w, `<html><body><script>
+			setTimeout(function() { window.location = "/dashboard"; }, 500);
+		</script></body></html>`)
+	})
+	mux.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>dashboard</body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		pattern string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "Redirect matches pattern",
+			pattern: `/dashboard$`,
+			timeout: 10 * time.Second,
+		},
+		{
+			name:    "Already on matching page",
+			pattern: `/login$`,
+			timeout: 10 * time.Second,
+		},
+		{
+			name:    "Times out without matching navigation",
+			pattern: `/admin$`,
+			timeout: 2 * time.Second,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid pattern",
+			pattern: `(`,
+			timeout: time.Second,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, err := cdpu.Init(true, true)
+			if err != nil {
+				t.Fatalf("failed to initialize a chrome browser: %v", err)
+			}
+			defer web.CancelAll(browser.Cancels...)
+
+			site := web.Site{
+				LoginURL: server.URL + "/login",
+				Session: web.Session{
+					Driver: browser.Driver,
+				},
+			}
+
+			actions := []cdpu.InputAction{
+				{
+					Description: "Navigate to the login page",
+					Action:      chromedp.Navigate(site.LoginURL),
+				},
+			}
+			if err := cdpu.Navigate(site, actions, 0); err != nil {
+				t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+			}
+
+			got, err := cdpu.WaitForNavigation(site, tc.pattern, tc.timeout)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("WaitForNavigation() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			current, err := cdpu.CurrentURL(site)
+			if err != nil {
+				t.Fatalf("CurrentURL() failed: %v", err)
+			}
+			if got != current || !strings.HasPrefix(got, server.URL) {
+				t.Errorf("WaitForNavigation() = %q, CurrentURL() = %q", got, current)
+			}
+		})
+	}
+}
+
+func TestNavigationInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.CurrentURL(site); err == nil {
+		t.Error("CurrentURL() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.WaitForNavigation(site, `.*`, time.Second); err == nil {
+		t.Error("WaitForNavigation() expected an error for an invalid driver")
+	}
+}
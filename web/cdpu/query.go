@@ -0,0 +1,160 @@
+package cdpu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// DefaultQueryTimeout is how long GetText, GetAttribute, and
+// ExtractTable wait for their element to appear.
+const DefaultQueryTimeout = 10 * time.Second
+
+// extractTableJS returns the trimmed text of the cells of a table, with
+// the table's selector substituted as a JSON string.
+const extractTableJS = `Array.from(document.querySelector(%s).rows,
+	row => Array.from(row.cells, cell => cell.innerText.trim()))`
+
+// GetText returns the visible text of the first element matching a CSS
+// selector on the page loaded in the provided Site's session. It waits
+// up to DefaultQueryTimeout for the element to become visible.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to query.
+// selector (string): The CSS selector of the element.
+//
+// **Returns:**
+//
+// string: The text of the element, as rendered.
+// error: An error if the driver is not of type *Driver or no visible
+// element matches the selector in time.
+func GetText(site web.Site, selector string) (string, error) {
+	var text string
+	err := runQuery(site, selector, chromedp.Text(selector, &text, chromedp.ByQuery))
+	return text, err
+}
+
+// GetAttribute returns an attribute of the first element matching a CSS
+// selector on the page loaded in the provided Site's session, such as
+// the href of a link. It waits up to DefaultQueryTimeout for the
+// element to appear.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to query.
+// selector (string): The CSS selector of the element.
+// attr (string): The name of the attribute.
+//
+// **Returns:**
+//
+// string: The value of the attribute.
+// error: An error if the driver is not of type *Driver, no element
+// matches the selector in time, or the element doesn't have the
+// attribute.
+func GetAttribute(site web.Site, selector, attr string) (string, error) {
+	var value string
+	var ok bool
+	if err := runQuery(site, selector, chromedp.AttributeValue(selector, attr, &value, &ok, chromedp.ByQuery)); err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("element %s has no %s attribute", selector, attr)
+	}
+
+	return value, nil
+}
+
+// ElementExists reports whether an element matching a CSS selector
+// appears on the page loaded in the provided Site's session within a
+// timeout.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to query.
+// selector (string): The CSS selector of the element.
+// timeout (time.Duration): How long to wait for the element.
+//
+// **Returns:**
+//
+// bool: True if the element appeared before the timeout.
+// error: An error if the driver is not of type *Driver or the page
+// can't be queried.
+func ElementExists(site web.Site, selector string, timeout time.Duration) (bool, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return false, errors.New("driver is not of type *Driver")
+	}
+
+	ctx, cancel := context.WithTimeout(chromeDriver.GetContext(), timeout)
+	defer cancel()
+
+	err := chromedp.Run(ctx, chromedp.WaitReady(selector, chromedp.ByQuery))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, context.DeadlineExceeded) && chromeDriver.GetContext().Err() == nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to query %s: %v", selector, err)
+	}
+}
+
+// ExtractTable returns the text of the cells of a table matching a CSS
+// selector on the page loaded in the provided Site's session, one slice
+// per row, including header rows. Rows may have different lengths if
+// cells span several columns. It waits up to DefaultQueryTimeout for
+// the table to appear.
+//
+// **Parameters:**
+//
+// site (web.Site): The site to query.
+// selector (string): The CSS selector of the table element.
+//
+// **Returns:**
+//
+// [][]string: The trimmed text of the cells, by row.
+// error: An error if the driver is not of type *Driver, no table
+// matches the selector in time, or the cells can't be read.
+func ExtractTable(site web.Site, selector string) ([][]string, error) {
+	quoted, err := json.Marshal(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode selector %s: %v", selector, err)
+	}
+
+	var rows [][]string
+	err = runQuery(site, selector, chromedp.Tasks{
+		chromedp.WaitReady(selector, chromedp.ByQuery),
+		chromedp.Evaluate(fmt.Sprintf(extractTableJS, quoted), &rows),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// runQuery runs the action of a query, limited to DefaultQueryTimeout.
+func runQuery(site web.Site, selector string, action chromedp.Action) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+
+	ctx, cancel := context.WithTimeout(chromeDriver.GetContext(), DefaultQueryTimeout)
+	defer cancel()
+
+	if err := chromedp.Run(ctx, action); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("element %s not found within %s", selector, DefaultQueryTimeout)
+		}
+		return fmt.Errorf("failed to query %s: %v", selector, err)
+	}
+
+	return nil
+}
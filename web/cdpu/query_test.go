@@ -0,0 +1,102 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestElementQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>
+			<h1 id="title">  Scan results </h1>
+			<a id="report" href="/report.pdf">Report</a>
+			<table id="findings">
+				<thead><tr><th>Host</th><th>Port</th></tr></thead>
+				<tbody>
+					<tr><td>10.0.0.1</td><td> 22 </td></tr>
+					<tr><td>10.0.0.2</td><td>443</td></tr>
+				</tbody>
+			</table>
+		</body></html>`)
+	}))
+	defer server.Close()
+
+	browser, err := cdpu.Init(true, true)
+	if err != nil {
+		t.Fatalf("failed to initialize a chrome browser: %v", err)
+	}
+	defer web.CancelAll(browser.Cancels...)
+
+	site := web.Site{Session: web.Session{Driver: browser.Driver}}
+	actions := []cdpu.InputAction{{Description: "Open results", Action: chromedp.Navigate(server.URL)}}
+	if err := cdpu.Navigate(site, actions, 0); err != nil {
+		t.Fatalf("failed to navigate to %s: %v", server.URL, err)
+	}
+
+	text, err := cdpu.GetText(site, "#title")
+	if err != nil || text != "Scan results" {
+		t.Errorf("GetText() = %q, %v, want %q", text, err, "Scan results")
+	}
+
+	href, err := cdpu.GetAttribute(site, "#report", "href")
+	if err != nil || href != "/report.pdf" {
+		t.Errorf("GetAttribute() = %q, %v, want %q", href, err, "/report.pdf")
+	}
+	if _, err := cdpu.GetAttribute(site, "#report", "download"); err == nil {
+		t.Error("GetAttribute() expected an error for a missing attribute")
+	}
+
+	rows, err := cdpu.ExtractTable(site, "#findings")
+	if err != nil {
+		t.Fatalf("ExtractTable() failed: %v", err)
+	}
+	want := [][]string{{"Host", "Port"}, {"10.0.0.1", "22"}, {"10.0.0.2", "443"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ExtractTable() = %v, want %v", rows, want)
+	}
+
+	testCases := []struct {
+		name     string
+		selector string
+		want     bool
+	}{
+		{name: "Present", selector: "#findings td", want: true},
+		{name: "Absent", selector: "#lockout", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cdpu.ElementExists(site, tc.selector, 500*time.Millisecond)
+			if err != nil {
+				t.Fatalf("ElementExists() failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ElementExists(%q) = %v, want %v", tc.selector, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestElementQueriesInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.GetText(site, "h1"); err == nil {
+		t.Error("GetText() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.GetAttribute(site, "a", "href"); err == nil {
+		t.Error("GetAttribute() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.ElementExists(site, "h1", time.Second); err == nil {
+		t.Error("ElementExists() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.ExtractTable(site, "table"); err == nil {
+		t.Error("ExtractTable() expected an error for an invalid driver")
+	}
+}
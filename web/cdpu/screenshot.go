@@ -0,0 +1,212 @@
+package cdpu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// ScreenshotFormat is the image format of a screenshot.
+type ScreenshotFormat string
+
+const (
+	// ScreenshotPNG captures a lossless PNG image.
+	ScreenshotPNG ScreenshotFormat = "png"
+	// ScreenshotJPEG captures a JPEG image, compressed according to the
+	// screenshot's quality.
+	ScreenshotJPEG ScreenshotFormat = "jpeg"
+)
+
+const (
+	// DefaultScreenshotWidth is the width of the viewport screenshots
+	// are taken at if no viewport or device is given.
+	DefaultScreenshotWidth = 1280
+	// DefaultScreenshotHeight is the height of the viewport screenshots
+	// are taken at if no viewport or device is given.
+	DefaultScreenshotHeight = 800
+	// DefaultScreenshotQuality is the quality of JPEG screenshots if no
+	// quality is given.
+	DefaultScreenshotQuality = 90
+)
+
+// elementClipJS returns the position of an element relative to the top
+// of the page, with the element's selector substituted as a JSON string.
+const elementClipJS = `(() => {
+	const rect = document.querySelector(%s).getBoundingClientRect();
+	return {x: rect.left + window.scrollX, y: rect.top + window.scrollY,
+		width: rect.width, height: rect.height};
+})()`
+
+// ScreenshotOptions configures ScreenShotWithOptions.
+//
+// **Attributes:**
+//
+// Format: The image format. If empty, it's inferred from the extension
+// of the image path: JPEG for ".jpg" and ".jpeg", PNG otherwise.
+// Quality: The compression quality of JPEG images, from 1 to 100.
+// Defaults to DefaultScreenshotQuality. Ignored for PNG images.
+// FullPage: Capture the whole page rather than the viewport. Ignored if
+// Selector is set.
+// Selector: Optional CSS selector of an element to capture instead of
+// the page.
+// Width: The width of the emulated viewport in CSS pixels. Defaults to
+// DefaultScreenshotWidth.
+// Height: The height of the emulated viewport in CSS pixels. Defaults
+// to DefaultScreenshotHeight.
+// Scale: The device scale factor of the emulated viewport, e.g., 2 for
+// a high-density display. Defaults to 1.
+// Device: Optional device to emulate instead of the viewport, such as
+// device.IPhone13 from github.com/chromedp/chromedp/device. Its user
+// agent is emulated too.
+type ScreenshotOptions struct {
+	Format   ScreenshotFormat
+	Quality  int
+	FullPage bool
+	Selector string
+	Width    int64
+	Height   int64
+	Scale    float64
+	Device   chromedp.Device
+}
+
+// ScreenShotWithOptions captures a screenshot of the currently loaded
+// page in the provided Site's session, or of one of its elements, and
+// writes the image data to the provided file path. The viewport or
+// device emulation stays in effect for the session's later actions.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page a screenshot should be taken of.
+// imgPath (string): The path to which the screenshot should be saved.
+// opts (ScreenshotOptions): Options controlling what is captured, the
+// emulated viewport, and the image format.
+//
+// **Returns:**
+//
+// error: An error if the options are invalid, the driver is not of type
+// *Driver, or the screenshot can't be captured or saved.
+func ScreenShotWithOptions(site web.Site, imgPath string, opts ScreenshotOptions) error {
+	if opts.Format == "" {
+		opts.Format = ScreenshotPNG
+		switch strings.ToLower(filepath.Ext(imgPath)) {
+		case ".jpg", ".jpeg":
+			opts.Format = ScreenshotJPEG
+		}
+	}
+	if opts.Format != ScreenshotPNG && opts.Format != ScreenshotJPEG {
+		return fmt.Errorf("unsupported screenshot format %q", opts.Format)
+	}
+	if opts.Quality == 0 {
+		opts.Quality = DefaultScreenshotQuality
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return fmt.Errorf("screenshot quality %d is not between 1 and 100", opts.Quality)
+	}
+
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+
+	var screenshot []byte
+	if err := chromedp.Run(chromeDriver.GetContext(), takeSS(opts, &screenshot)); err != nil {
+		return fmt.Errorf("failed to take screenshot: %v", err)
+	}
+
+	if err := os.WriteFile(imgPath, screenshot, 0644); err != nil {
+		return fmt.Errorf("failed to write screenshot to disk: %v", err)
+	}
+
+	return nil
+}
+
+// takeSS emulates the viewport or device of opts and captures a
+// screenshot.
+func takeSS(opts ScreenshotOptions, res *[]byte) chromedp.Tasks {
+	tasks := chromedp.Tasks{emulateScreenshotViewport(opts)}
+	if opts.Selector != "" {
+		tasks = append(tasks, chromedp.WaitVisible(opts.Selector, chromedp.ByQuery))
+	}
+
+	return append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		capture := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormat(opts.Format)).
+			WithFromSurface(true)
+		if opts.Format == ScreenshotJPEG {
+			capture = capture.WithQuality(int64(opts.Quality))
+		}
+
+		switch {
+		case opts.Selector != "":
+			clip, err := elementClip(ctx, opts.Selector)
+			if err != nil {
+				return err
+			}
+			capture = capture.WithClip(clip).WithCaptureBeyondViewport(true)
+		case opts.FullPage:
+			capture = capture.WithCaptureBeyondViewport(true)
+		}
+
+		var err error
+		*res, err = capture.Do(ctx)
+		return err
+	}))
+}
+
+// emulateScreenshotViewport returns the action emulating the device or
+// viewport of opts.
+func emulateScreenshotViewport(opts ScreenshotOptions) chromedp.Action {
+	if opts.Device != nil {
+		return chromedp.Emulate(opts.Device)
+	}
+
+	width, height, scale := opts.Width, opts.Height, opts.Scale
+	if width <= 0 {
+		width = DefaultScreenshotWidth
+	}
+	if height <= 0 {
+		height = DefaultScreenshotHeight
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+
+	return emulation.SetDeviceMetricsOverride(width, height, scale, false).
+		WithScreenOrientation(&emulation.ScreenOrientation{
+			Type:  emulation.OrientationTypePortraitPrimary,
+			Angle: 0,
+		})
+}
+
+// elementClip returns the area of the page covered by the element
+// matching selector, rounded out to whole pixels.
+func elementClip(ctx context.Context, selector string) (*page.Viewport, error) {
+	quoted, err := json.Marshal(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode selector %s: %v", selector, err)
+	}
+
+	var clip page.Viewport
+	if err := chromedp.Evaluate(fmt.Sprintf(elementClipJS, quoted), &clip).Do(ctx); err != nil {
+		return nil, fmt.Errorf("failed to locate %s: %v", selector, err)
+	}
+	if clip.Width == 0 || clip.Height == 0 {
+		return nil, fmt.Errorf("element %s has no size", selector)
+	}
+
+	x, y := math.Floor(clip.X), math.Floor(clip.Y)
+	clip.Width, clip.Height = math.Ceil(clip.X+clip.Width)-x, math.Ceil(clip.Y+clip.Height)-y
+	clip.X, clip.Y, clip.Scale = x, y, 1
+
+	return &clip, nil
+}
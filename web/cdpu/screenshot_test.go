@@ -0,0 +1,130 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestScreenShotWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body style="margin:0">
+			<div id="badge" style="position:absolute;top:1500px;left:20px;width:200px;height:100px;background:red"></div>
+			<div style="height:3000px"></div></body></html>`)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		filename   string
+		opts       cdpu.ScreenshotOptions
+		wantFormat string
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:       "Default viewport",
+			filename:   "page.png",
+			wantFormat: "png",
+			wantWidth:  cdpu.DefaultScreenshotWidth,
+			wantHeight: cdpu.DefaultScreenshotHeight,
+		},
+		{
+			name:       "Custom viewport as JPEG",
+			filename:   "page.jpg",
+			opts:       cdpu.ScreenshotOptions{Width: 640, Height: 480, Quality: 50},
+			wantFormat: "jpeg",
+			wantWidth:  640,
+			wantHeight: 480,
+		},
+		{
+			name:       "Full page",
+			filename:   "full.png",
+			opts:       cdpu.ScreenshotOptions{FullPage: true},
+			wantFormat: "png",
+			wantWidth:  cdpu.DefaultScreenshotWidth,
+			wantHeight: 3000,
+		},
+		{
+			name:       "Element below the fold",
+			filename:   "badge.png",
+			opts:       cdpu.ScreenshotOptions{Selector: "#badge"},
+			wantFormat: "png",
+			wantWidth:  200,
+			wantHeight: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, err := cdpu.Init(true, true)
+			if err != nil {
+				t.Fatalf("failed to initialize a chrome browser: %v", err)
+			}
+			defer web.CancelAll(browser.Cancels...)
+
+			site := web.Site{
+				LoginURL: server.URL,
+				Session:  web.Session{Driver: browser.Driver},
+			}
+			load := []cdpu.InputAction{{
+				Description: "Load the page",
+				Action:      chromedp.Navigate(site.LoginURL),
+			}}
+			if err := cdpu.Navigate(site, load, 0); err != nil {
+				t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+			}
+
+			imgPath := filepath.Join(t.TempDir(), tc.filename)
+			if err := cdpu.ScreenShotWithOptions(site, imgPath, tc.opts); err != nil {
+				t.Fatalf("ScreenShotWithOptions() failed: %v", err)
+			}
+
+			f, err := os.Open(imgPath)
+			if err != nil {
+				t.Fatalf("failed to open screenshot: %v", err)
+			}
+			defer f.Close()
+			cfg, format, err := image.DecodeConfig(f)
+			if err != nil {
+				t.Fatalf("failed to decode screenshot: %v", err)
+			}
+			if format != tc.wantFormat || cfg.Width != tc.wantWidth || cfg.Height != tc.wantHeight {
+				t.Errorf("screenshot is a %dx%d %s, want a %dx%d %s",
+					cfg.Width, cfg.Height, format, tc.wantWidth, tc.wantHeight, tc.wantFormat)
+			}
+		})
+	}
+}
+
+func TestScreenShotWithOptionsInvalid(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+	imgPath := filepath.Join(t.TempDir(), "page.png")
+
+	testCases := []struct {
+		name string
+		opts cdpu.ScreenshotOptions
+	}{
+		{name: "Invalid driver"},
+		{name: "Unsupported format", opts: cdpu.ScreenshotOptions{Format: "webp"}},
+		{name: "Quality out of range", opts: cdpu.ScreenshotOptions{Quality: 101}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := cdpu.ScreenShotWithOptions(site, imgPath, tc.opts); err == nil {
+				t.Error("ScreenShotWithOptions() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cdpu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// ScrollOptions configures ScrollToBottom.
+//
+// **Attributes:**
+//
+// MaxScrolls: The maximum number of times to scroll. Defaults to 20.
+// WaitBetween: How long to wait after each scroll for lazy-loaded
+// content to appear. Defaults to 500ms.
+// StopWhenSelectorStable: Optional CSS selector of the items the page
+// loads, e.g., ".search-result". If set, scrolling stops once a scroll
+// doesn't change the number of matching elements, rather than once the
+// page height stops changing. This handles pages that keep a fixed
+// height or show a loading footer.
+type ScrollOptions struct {
+	MaxScrolls             int
+	WaitBetween            time.Duration
+	StopWhenSelectorStable string
+}
+
+// scrollState is the page state compared between scrolls.
+type scrollState struct {
+	Height int `json:"height"`
+	Count  int `json:"count"`
+}
+
+// ScrollToBottom repeatedly scrolls the page loaded in the provided
+// Site's session to the bottom so that infinite-scroll and lazy-loaded
+// content is rendered before extracting the page source or taking a
+// screenshot. It stops once scrolling no longer loads new content or
+// after MaxScrolls scrolls.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page should be scrolled.
+// opts (ScrollOptions): Options controlling the number of scrolls, the
+// wait between them, and when the content is considered loaded.
+//
+// **Returns:**
+//
+// int: The number of scrolls performed.
+// error: An error if the driver is not of type *Driver or the page can't
+// be scrolled.
+func ScrollToBottom(site web.Site, opts ScrollOptions) (int, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return 0, errors.New("driver is not of type *Driver")
+	}
+
+	if opts.MaxScrolls <= 0 {
+		opts.MaxScrolls = 20
+	}
+	if opts.WaitBetween <= 0 {
+		opts.WaitBetween = 500 * time.Millisecond
+	}
+
+	selector, err := json.Marshal(opts.StopWhenSelectorStable)
+	if err != nil {
+		return 0, err
+	}
+	stateJS := fmt.Sprintf(`(() => {
+		const el = document.scrollingElement || document.documentElement;
+		const sel = %s;
+		return {height: el.scrollHeight, count: sel ? document.querySelectorAll(sel).length : 0};
+	})()`, selector)
+	scrollJS := `(() => {
+		const el = document.scrollingElement || document.documentElement;
+		el.scrollTop = el.scrollHeight;
+		return true;
+	})()`
+
+	ctx := chromeDriver.GetContext()
+	var previous scrollState
+	if err := chromedp.Run(ctx, chromedp.Evaluate(stateJS, &previous)); err != nil {
+		return 0, fmt.Errorf("failed to read page state: %v", err)
+	}
+
+	for scrolls := 1; scrolls <= opts.MaxScrolls; scrolls++ {
+		var scrolled bool
+		var current scrollState
+		if err := chromedp.Run(ctx,
+			chromedp.Evaluate(scrollJS, &scrolled),
+			chromedp.Sleep(opts.WaitBetween),
+			chromedp.Evaluate(stateJS, &current),
+		); err != nil {
+			return scrolls, fmt.Errorf("failed to scroll page: %v", err)
+		}
+
+		stable := current.Height == previous.Height
+		if opts.StopWhenSelectorStable != "" {
+			stable = current.Count == previous.Count
+		}
+		if stable {
+			return scrolls, nil
+		}
+		previous = current
+	}
+
+	return opts.MaxScrolls, nil
+}
+
+// ScrollIntoView scrolls the page loaded in the provided Site's session
+// until the first element matching the input CSS selector is visible,
+// which triggers content that's lazily loaded when it enters the
+// viewport.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page should be scrolled.
+// selector (string): The CSS selector of the element to scroll to.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver, no element
+// matches the selector, or the page can't be scrolled.
+func ScrollIntoView(site web.Site, selector string) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+
+	quoted, err := json.Marshal(selector)
+	if err != nil {
+		return err
+	}
+
+	// chromedp waits for a matching element indefinitely, so check that
+	// one exists first.
+	var exists bool
+	ctx := chromeDriver.GetContext()
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null`, quoted), &exists)); err != nil {
+		return fmt.Errorf("failed to find %q: %v", selector, err)
+	}
+	if !exists {
+		return fmt.Errorf("no element matches %q", selector)
+	}
+
+	if err := chromedp.Run(ctx, chromedp.ScrollIntoView(selector, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to scroll %q into view: %v", selector, err)
+	}
+
+	return nil
+}
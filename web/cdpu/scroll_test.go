@@ -0,0 +1,126 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+// infiniteScrollPage appends a batch of items whenever the page is
+// scrolled to the bottom, until five batches have been loaded.
+const infiniteScrollPage = `<html><body>
+<div id="items"></div>
+<div id="footer" style="margin-top: 3000px">footer</div>
+<script>
+	let batches = 0;
+	function load() {
+		if (batches >= 5) { return; }
+		batches++;
+		for (let i = 0; i < 10; i++) {
+			const item = document.createElement("div");
+			item.className = "item";
+			item.style.height = "100px";
+			item.textContent = "item " + batches + "-" + i;
+			document.getElementById("items").appendChild(item);
+		}
+	}
+	load();
+	window.addEventListener("scroll", () => {
+		const el = document.scrollingElement;
+		if (el.scrollTop + window.innerHeight >= el.scrollHeight - 10) { setTimeout(load, 50); }
+	});
+</script>
+</body></html>`
+
+func TestScroll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, infiniteScrollPage)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		opts       cdpu.ScrollOptions
+		wantItems  int
+		maxScrolls int
+	}{
+		{
+			name:       "Until the page height is stable",
+			opts:       cdpu.ScrollOptions{WaitBetween: 300 * time.Millisecond},
+			wantItems:  50,
+			maxScrolls: 6,
+		},
+		{
+			name:       "Until the item count is stable",
+			opts:       cdpu.ScrollOptions{WaitBetween: 300 * time.Millisecond, StopWhenSelectorStable: ".item"},
+			wantItems:  50,
+			maxScrolls: 6,
+		},
+		{
+			name:       "Limited number of scrolls",
+			opts:       cdpu.ScrollOptions{MaxScrolls: 2, WaitBetween: 300 * time.Millisecond},
+			wantItems:  30,
+			maxScrolls: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, err := cdpu.Init(true, true)
+			if err != nil {
+				t.Fatalf("failed to initialize a chrome browser: %v", err)
+			}
+			defer web.CancelAll(browser.Cancels...)
+
+			site := web.Site{
+				LoginURL: server.URL,
+				Session:  web.Session{Driver: browser.Driver},
+			}
+			actions := []cdpu.InputAction{{Description: "Open the feed", Action: chromedp.Navigate(server.URL)}}
+			if err := cdpu.Navigate(site, actions, 0); err != nil {
+				t.Fatalf("failed to navigate to %s: %v", server.URL, err)
+			}
+
+			scrolls, err := cdpu.ScrollToBottom(site, tc.opts)
+			if err != nil {
+				t.Fatalf("ScrollToBottom() error = %v", err)
+			}
+			if scrolls > tc.maxScrolls {
+				t.Errorf("ScrollToBottom() scrolled %d times, want at most %d", scrolls, tc.maxScrolls)
+			}
+
+			var items int
+			driver := browser.Driver.(*cdpu.Driver)
+			if err := chromedp.Run(driver.GetContext(), chromedp.Evaluate(`document.querySelectorAll(".item").length`, &items)); err != nil {
+				t.Fatalf("failed to count items: %v", err)
+			}
+			if items != tc.wantItems {
+				t.Errorf("found %d items after scrolling, want %d", items, tc.wantItems)
+			}
+
+			if err := cdpu.ScrollIntoView(site, "#footer"); err != nil {
+				t.Errorf("ScrollIntoView() error = %v", err)
+			}
+			if err := cdpu.ScrollIntoView(site, "#missing"); err == nil {
+				t.Error("ScrollIntoView() expected an error for a missing element")
+			}
+		})
+	}
+}
+
+func TestScrollInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.ScrollToBottom(site, cdpu.ScrollOptions{}); err == nil {
+		t.Error("ScrollToBottom() expected an error for an invalid driver")
+	}
+	if err := cdpu.ScrollIntoView(site, "#footer"); err == nil {
+		t.Error("ScrollIntoView() expected an error for an invalid driver")
+	}
+}
@@ -0,0 +1,171 @@
+package cdpu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// exportStorageJS reads the web storage of the current page.
+const exportStorageJS = `({
+	origin: location.origin,
+	local: Object.fromEntries(Object.entries(localStorage)),
+	session: Object.fromEntries(Object.entries(sessionStorage)),
+})`
+
+// importStorageJS stores the items of a StorageSnapshot, which is
+// substituted as a JSON literal, in the web storage of the current page.
+const importStorageJS = `(() => {
+	const snapshot = %s;
+	for (const [key, value] of Object.entries(snapshot.local || {})) {
+		localStorage.setItem(key, value);
+	}
+	for (const [key, value] of Object.entries(snapshot.session || {})) {
+		sessionStorage.setItem(key, value);
+	}
+	return true;
+})()`
+
+// StorageSnapshot holds the web storage of an origin. Single-page
+// applications often keep their access tokens there instead of in
+// cookies.
+//
+// **Attributes:**
+//
+// Origin: The origin the storage belongs to (e.g.,
+// "https://example.com").
+// Local: The items of localStorage.
+// Session: The items of sessionStorage.
+type StorageSnapshot struct {
+	Origin  string            `json:"origin"`
+	Local   map[string]string `json:"local"`
+	Session map[string]string `json:"session"`
+}
+
+// ExportStorage returns the localStorage and sessionStorage of the page
+// currently loaded in the provided Site's session.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose storage should be exported.
+//
+// **Returns:**
+//
+// StorageSnapshot: The storage of the page's origin.
+// error: An error if the driver is not of type *Driver or the storage
+// can't be read.
+func ExportStorage(site web.Site) (StorageSnapshot, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return StorageSnapshot{}, errors.New("driver is not of type *Driver")
+	}
+
+	var snapshot StorageSnapshot
+	if err := chromedp.Run(chromeDriver.GetContext(), chromedp.Evaluate(exportStorageJS, &snapshot)); err != nil {
+		return StorageSnapshot{}, fmt.Errorf("failed to read web storage: %v", err)
+	}
+
+	return snapshot, nil
+}
+
+// ImportStorage adds the items of a snapshot to the localStorage and
+// sessionStorage of the page currently loaded in the provided Site's
+// session. Storage is scoped to an origin, so the page must be on the
+// snapshot's origin; reload it afterwards if the application only reads
+// its storage on startup.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose storage should be set.
+// snapshot (StorageSnapshot): The items to add.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver, the page is on
+// another origin, or the storage can't be written.
+func ImportStorage(site web.Site, snapshot StorageSnapshot) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	var origin string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`location.origin`, &origin)); err != nil {
+		return fmt.Errorf("failed to get page origin: %v", err)
+	}
+	if origin != snapshot.Origin {
+		return fmt.Errorf("page origin %s does not match storage origin %s", origin, snapshot.Origin)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode web storage: %v", err)
+	}
+	var done bool
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(importStorageJS, data), &done)); err != nil {
+		return fmt.Errorf("failed to write web storage: %v", err)
+	}
+
+	return nil
+}
+
+// SaveStorageToDisk writes the web storage of the page currently loaded
+// in the provided Site's session to a file. See ExportStorage.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose storage should be saved.
+// filePath (string): The file path where the storage should be saved.
+//
+// **Returns:**
+//
+// error: An error if the storage can't be read or the file can't be
+// written.
+func SaveStorageToDisk(site web.Site, filePath string) error {
+	snapshot, err := ExportStorage(site)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode web storage: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write web storage to %s: %v", filePath, err)
+	}
+
+	return nil
+}
+
+// LoadStorageFromDisk restores web storage written by SaveStorageToDisk
+// in the page currently loaded in the provided Site's session. See
+// ImportStorage.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose storage should be set.
+// filePath (string): The file the storage was saved to.
+//
+// **Returns:**
+//
+// error: An error if the file can't be read or the storage can't be
+// written.
+func LoadStorageFromDisk(site web.Site, filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read web storage from %s: %v", filePath, err)
+	}
+
+	var snapshot StorageSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse web storage from %s: %v", filePath, err)
+	}
+
+	return ImportStorage(site, snapshot)
+}
@@ -0,0 +1,128 @@
+package cdpu_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestSessionPersistence(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "persisted", Path: "/", Expires: time.Now().Add(time.Hour)})
+		fmt.Fprint(w, `<html><body><script>
+			localStorage.setItem("token", "abc");
+			sessionStorage.setItem("tab", "1");
+		</script></body></html>`)
+	})
+	mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>app</body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	cookiesPath := filepath.Join(dir, "cookies.json")
+	storagePath := filepath.Join(dir, "storage.json")
+
+	newSite := func(t *testing.T) web.Site {
+		t.Helper()
+		browser, err := cdpu.Init(true, true)
+		if err != nil {
+			t.Fatalf("failed to initialize a chrome browser: %v", err)
+		}
+		t.Cleanup(func() { web.CancelAll(browser.Cancels...) })
+		return web.Site{LoginURL: server.URL + "/login", Session: web.Session{Driver: browser.Driver}}
+	}
+	open := func(t *testing.T, site web.Site, url string) {
+		t.Helper()
+		actions := []cdpu.InputAction{{Description: "Open " + url, Action: chromedp.Navigate(url)}}
+		if err := cdpu.Navigate(site, actions, 0); err != nil {
+			t.Fatalf("failed to navigate to %s: %v", url, err)
+		}
+	}
+
+	site := newSite(t)
+	open(t, site, site.LoginURL)
+	if err := cdpu.SaveCookiesToDisk(site, cookiesPath); err != nil {
+		t.Fatalf("SaveCookiesToDisk() failed: %v", err)
+	}
+	if err := cdpu.SaveStorageToDisk(site, storagePath); err != nil {
+		t.Fatalf("SaveStorageToDisk() failed: %v", err)
+	}
+
+	resumed := newSite(t)
+	n, err := cdpu.LoadCookiesFromDisk(resumed, cookiesPath)
+	if err != nil || n != 1 {
+		t.Fatalf("LoadCookiesFromDisk() = %d, %v, want 1 cookie", n, err)
+	}
+	open(t, resumed, server.URL+"/app")
+	if err := cdpu.LoadStorageFromDisk(resumed, storagePath); err != nil {
+		t.Fatalf("LoadStorageFromDisk() failed: %v", err)
+	}
+
+	var cookies string
+	if err := chromedp.Run(resumed.Session.Driver.(*cdpu.Driver).GetContext(), chromedp.Evaluate(`document.cookie`, &cookies)); err != nil {
+		t.Fatalf("failed to read browser cookies: %v", err)
+	}
+	if want := "session=persisted"; !containsCookie(cookies, want) {
+		t.Errorf("browser cookies = %q, want %q", cookies, want)
+	}
+	snapshot, err := cdpu.ExportStorage(resumed)
+	if err != nil {
+		t.Fatalf("ExportStorage() failed: %v", err)
+	}
+	if snapshot.Local["token"] != "abc" || snapshot.Session["tab"] != "1" {
+		t.Errorf("ExportStorage() = %+v, want the saved items", snapshot)
+	}
+
+	t.Run("Other origin", func(t *testing.T) {
+		other := cdpu.StorageSnapshot{Origin: "https://example.com", Local: map[string]string{"a": "b"}}
+		if err := cdpu.ImportStorage(resumed, other); err == nil {
+			t.Error("ImportStorage() expected an error for another origin")
+		}
+	})
+
+	t.Run("Expired cookies", func(t *testing.T) {
+		expired := []*network.Cookie{{
+			Name: "session", Value: "old", Domain: "127.0.0.1", Path: "/",
+			Expires: float64(time.Now().Add(-time.Hour).Unix()),
+		}}
+		data, err := json.Marshal(expired)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expiredPath := filepath.Join(dir, "expired.json")
+		if err := os.WriteFile(expiredPath, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cdpu.LoadCookiesFromDisk(resumed, expiredPath); !errors.Is(err, cdpu.ErrSessionExpired) {
+			t.Errorf("LoadCookiesFromDisk() error = %v, want ErrSessionExpired", err)
+		}
+	})
+}
+
+func TestStorageInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.ExportStorage(site); err == nil {
+		t.Error("ExportStorage() expected an error for an invalid driver")
+	}
+	if err := cdpu.ImportStorage(site, cdpu.StorageSnapshot{}); err == nil {
+		t.Error("ImportStorage() expected an error for an invalid driver")
+	}
+	if err := cdpu.SaveStorageToDisk(site, filepath.Join(t.TempDir(), "storage.json")); err == nil {
+		t.Error("SaveStorageToDisk() expected an error for an invalid driver")
+	}
+}
@@ -0,0 +1,328 @@
+package cdpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+)
+
+// Tab describes a tab of the browser driven by a Site's session.
+//
+// **Attributes:**
+//
+// ID: The unique identifier of the tab's target.
+// OpenerID: The identifier of the tab that opened this one, e.g., with
+// window.open or a link with target="_blank". Empty if it wasn't opened
+// by another tab.
+// URL: The URL of the document loaded in the tab.
+// Title: The title of the document loaded in the tab.
+// Current: Whether the session's driver is attached to the tab.
+type Tab struct {
+	ID       string
+	OpenerID string
+	URL      string
+	Title    string
+	Current  bool
+}
+
+// ListTabs returns the tabs of the browser driven by the provided Site's
+// session, including tabs opened by the pages themselves.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose tabs should be listed.
+//
+// **Returns:**
+//
+// []Tab: The tabs of the browser.
+// error: An error if the driver is not of type *Driver or the tabs
+// can't be retrieved.
+func ListTabs(site web.Site) ([]Tab, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return nil, errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	current, err := currentTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := chromedp.Targets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list targets: %v", err)
+	}
+
+	var tabs []Tab
+	for _, info := range targets {
+		if info.Type != "page" {
+			continue
+		}
+		tabs = append(tabs, Tab{
+			ID:       string(info.TargetID),
+			OpenerID: string(info.OpenerID),
+			URL:      info.URL,
+			Title:    info.Title,
+			Current:  info.TargetID == current,
+		})
+	}
+
+	return tabs, nil
+}
+
+// WaitForNewTab runs actions that open a new tab, such as clicking a
+// link with target="_blank", and waits for the tab to be opened by the
+// current one. The driver stays attached to the current tab; pass the
+// ID of the returned tab to SwitchToTab to drive it. The new tab may
+// still be loading when it's returned, so wait for its content (e.g.,
+// with WaitForNavigation) after switching to it.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page opens the tab.
+// actions ([]InputAction): The actions that open the tab, run with
+// Navigate.
+// timeout (time.Duration): The maximum time to wait for the tab after
+// the actions have run.
+//
+// **Returns:**
+//
+// Tab: The new tab.
+// error: An error if the driver is not of type *Driver, the actions
+// fail, or no tab is opened before the timeout.
+func WaitForNewTab(site web.Site, actions []InputAction, timeout time.Duration) (Tab, error) {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return Tab{}, errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	opener, err := currentTarget(ctx)
+	if err != nil {
+		return Tab{}, err
+	}
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opened := chromedp.WaitNewTarget(listenCtx, func(info *target.Info) bool {
+		return info.Type == "page" && info.OpenerID == opener
+	})
+
+	if err := Navigate(site, actions, 0); err != nil {
+		return Tab{}, err
+	}
+
+	select {
+	case id := <-opened:
+		tabs, err := ListTabs(site)
+		if err != nil {
+			return Tab{}, err
+		}
+		for _, tab := range tabs {
+			if tab.ID == string(id) {
+				return tab, nil
+			}
+		}
+		return Tab{}, fmt.Errorf("tab %s was closed after being opened", id)
+	case <-time.After(timeout):
+		return Tab{}, fmt.Errorf("no tab opened within %s", timeout)
+	}
+}
+
+// SwitchToTab attaches the driver of the provided Site's session to a
+// tab and brings it to the front, so that subsequent actions run
+// against it. Switching back to a tab reuses its previous attachment,
+// so listeners set up on the tab's context keep working.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose driver should be switched.
+// tabID (string): The ID of the tab, as returned by ListTabs or
+// WaitForNewTab.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver or the tab
+// can't be attached.
+func SwitchToTab(site web.Site, tabID string) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+
+	ctx, err := chromeDriver.targetContext(target.ID(tabID))
+	if err != nil {
+		return err
+	}
+	if err := chromedp.Run(ctx, target.ActivateTarget(target.ID(tabID))); err != nil {
+		return fmt.Errorf("failed to activate tab %s: %v", tabID, err)
+	}
+
+	chromeDriver.SetContext(ctx)
+	return nil
+}
+
+// SwitchToFrame attaches the driver of the provided Site's session to
+// the cross-origin iframe matching frameSelector, so that subsequent
+// actions run against the frame's document without the inFrame option
+// of WithinFrame. Frames rendered in the page's own process can't be
+// attached to; use WithinFrame for them. Use SwitchToTab with the ID of
+// the frame's tab to switch back.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose page contains the iframe.
+// frameSelector (string): The CSS selector of the iframe element.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver, frameSelector
+// doesn't match an iframe, or the frame isn't rendered in its own
+// process.
+func SwitchToFrame(site web.Site, frameSelector string) error {
+	chromeDriver, ok := site.Session.Driver.(*Driver)
+	if !ok {
+		return errors.New("driver is not of type *Driver")
+	}
+	ctx := chromeDriver.GetContext()
+
+	var nodes []*cdp.Node
+	if err := chromedp.Run(ctx, chromedp.Nodes(frameSelector, &nodes, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to find frame %q: %v", frameSelector, err)
+	}
+	if nodes[0].FrameID == "" {
+		return fmt.Errorf("element matching %q is not a frame", frameSelector)
+	}
+
+	oopifs, err := outOfProcessFrames(ctx)
+	if err != nil {
+		return err
+	}
+	targetID := target.ID(nodes[0].FrameID)
+	if !oopifs[targetID] {
+		return fmt.Errorf("frame %q is rendered by its page; use WithinFrame instead", frameSelector)
+	}
+
+	frameCtx, err := chromeDriver.targetContext(targetID)
+	if err != nil {
+		return err
+	}
+
+	chromeDriver.SetContext(frameCtx)
+	return nil
+}
+
+// CloseTab closes a tab of the browser driven by the provided Site's
+// session. If the driver is attached to the tab, or to a frame (see
+// SwitchToFrame), it's switched to the tab's opener if it's still open, or to
+// another tab otherwise.
+//
+// **Parameters:**
+//
+// site (web.Site): The site whose tab should be closed.
+// tabID (string): The ID of the tab, as returned by ListTabs or
+// WaitForNewTab.
+//
+// **Returns:**
+//
+// error: An error if the driver is not of type *Driver, the tab can't
+// be closed, or it's the driver's tab and no other tab is left, in
+// which case it's left open.
+func CloseTab(site web.Site, tabID string) error {
+	tabs, err := ListTabs(site)
+	if err != nil {
+		return err
+	}
+	chromeDriver := site.Session.Driver.(*Driver)
+	ctx := chromeDriver.GetContext()
+
+	var closing *Tab
+	var remaining []Tab
+	for i := range tabs {
+		if tabs[i].ID == tabID {
+			closing = &tabs[i]
+		} else {
+			remaining = append(remaining, tabs[i])
+		}
+	}
+	if closing == nil {
+		return fmt.Errorf("tab %s not found", tabID)
+	}
+	// The driver may be attached to a frame rather than a tab, in which
+	// case no tab is current and the frame may belong to the tab.
+	attached := closing.Current || !anyCurrent(tabs)
+	if attached && len(remaining) == 0 {
+		return fmt.Errorf("tab %s is the driver's last tab", tabID)
+	}
+
+	browserCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+	if err := target.CloseTarget(target.ID(tabID)).Do(browserCtx); err != nil {
+		return fmt.Errorf("failed to close tab %s: %v", tabID, err)
+	}
+	delete(chromeDriver.targets, target.ID(tabID))
+
+	if !attached {
+		return nil
+	}
+	next := remaining[0]
+	for _, tab := range remaining {
+		if tab.ID == closing.OpenerID {
+			next = tab
+		}
+	}
+
+	return SwitchToTab(site, next.ID)
+}
+
+// targetContext returns the context attached to a target, creating it
+// on first use. The contexts aren't cancelled, since cancelling a
+// context attached to an existing target closes it; they're cancelled
+// with the driver's context instead.
+func (d *Driver) targetContext(id target.ID) (context.Context, error) {
+	if current, err := currentTarget(d.Context); err == nil && current == id {
+		return d.Context, nil
+	}
+	if ctx, ok := d.targets[id]; ok {
+		return ctx, nil
+	}
+
+	ctx, _ := chromedp.NewContext(d.Context, chromedp.WithTargetID(id))
+	if err := chromedp.Run(ctx); err != nil {
+		return nil, fmt.Errorf("failed to attach to target %s: %v", id, err)
+	}
+
+	if d.targets == nil {
+		d.targets = make(map[target.ID]context.Context)
+	}
+	if current, err := currentTarget(d.Context); err == nil {
+		d.targets[current] = d.Context
+	}
+	d.targets[id] = ctx
+
+	return ctx, nil
+}
+
+// currentTarget returns the ID of the target a chromedp context is
+// attached to, attaching it first if needed.
+func currentTarget(ctx context.Context) (target.ID, error) {
+	if err := chromedp.Run(ctx); err != nil {
+		return "", fmt.Errorf("failed to attach to the current tab: %v", err)
+	}
+	return chromedp.FromContext(ctx).Target.TargetID, nil
+}
+
+// anyCurrent reports whether the driver is attached to one of tabs.
+func anyCurrent(tabs []Tab) bool {
+	for _, tab := range tabs {
+		if tab.Current {
+			return true
+		}
+	}
+	return false
+}
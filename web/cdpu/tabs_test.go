@@ -0,0 +1,118 @@
+package cdpu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/l50/goutils/v2/web"
+	"github.com/l50/goutils/v2/web/cdpu"
+)
+
+func TestTabs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/report", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Report</title></head><body><p id="total">42</p></body></html>`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Home</title></head><body>
+			<a id="open" href="/report" target="_blank">Report</a></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	browser, err := cdpu.Init(true, true)
+	if err != nil {
+		t.Fatalf("failed to initialize a chrome browser: %v", err)
+	}
+	defer web.CancelAll(browser.Cancels...)
+
+	site := web.Site{
+		LoginURL: server.URL,
+		Session:  web.Session{Driver: browser.Driver},
+	}
+	load := []cdpu.InputAction{{
+		Description: "Load the home page",
+		Action:      chromedp.Navigate(site.LoginURL),
+	}}
+	if err := cdpu.Navigate(site, load, 0); err != nil {
+		t.Fatalf("failed to navigate to %s: %v", site.LoginURL, err)
+	}
+
+	tabs, err := cdpu.ListTabs(site)
+	if err != nil {
+		t.Fatalf("ListTabs() failed: %v", err)
+	}
+	if len(tabs) != 1 || !tabs[0].Current {
+		t.Fatalf("ListTabs() = %+v, want the current tab only", tabs)
+	}
+	home := tabs[0]
+
+	open := []cdpu.InputAction{{
+		Description: "Open the report",
+		Action:      chromedp.Click("#open", chromedp.ByQuery),
+	}}
+	report, err := cdpu.WaitForNewTab(site, open, 10*time.Second)
+	if err != nil {
+		t.Fatalf("WaitForNewTab() failed: %v", err)
+	}
+	if report.OpenerID != home.ID || report.Current {
+		t.Fatalf("WaitForNewTab() = %+v, want a tab opened by %s", report, home.ID)
+	}
+
+	if err := cdpu.SwitchToTab(site, report.ID); err != nil {
+		t.Fatalf("SwitchToTab() failed: %v", err)
+	}
+	if _, err := cdpu.WaitForNavigation(site, "/report$", 10*time.Second); err != nil {
+		t.Fatalf("WaitForNavigation() failed: %v", err)
+	}
+	total, err := cdpu.GetText(site, "#total")
+	if err != nil || total != "42" {
+		t.Fatalf("GetText() = %q, %v; want %q", total, err, "42")
+	}
+
+	if err := cdpu.CloseTab(site, report.ID); err != nil {
+		t.Fatalf("CloseTab() failed: %v", err)
+	}
+	currentURL, err := cdpu.CurrentURL(site)
+	if err != nil {
+		t.Fatalf("CurrentURL() failed: %v", err)
+	}
+	if currentURL != server.URL+"/" {
+		t.Errorf("CurrentURL() = %q after closing the report, want %q", currentURL, server.URL+"/")
+	}
+	tabs, err = cdpu.ListTabs(site)
+	if err != nil {
+		t.Fatalf("ListTabs() failed: %v", err)
+	}
+	if len(tabs) != 1 || tabs[0].ID != home.ID {
+		t.Errorf("ListTabs() = %+v after closing the report, want only %s", tabs, home.ID)
+	}
+
+	if err := cdpu.CloseTab(site, home.ID); err == nil {
+		t.Error("CloseTab() expected an error for the last tab")
+	}
+}
+
+func TestTabsInvalidDriver(t *testing.T) {
+	site := web.Site{Session: web.Session{Driver: "not a driver"}}
+
+	if _, err := cdpu.ListTabs(site); err == nil {
+		t.Error("ListTabs() expected an error for an invalid driver")
+	}
+	if _, err := cdpu.WaitForNewTab(site, nil, time.Second); err == nil {
+		t.Error("WaitForNewTab() expected an error for an invalid driver")
+	}
+	if err := cdpu.SwitchToTab(site, "tab"); err == nil {
+		t.Error("SwitchToTab() expected an error for an invalid driver")
+	}
+	if err := cdpu.SwitchToFrame(site, "iframe"); err == nil {
+		t.Error("SwitchToFrame() expected an error for an invalid driver")
+	}
+	if err := cdpu.CloseTab(site, "tab"); err == nil {
+		t.Error("CloseTab() expected an error for an invalid driver")
+	}
+}